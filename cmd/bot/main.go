@@ -4,22 +4,93 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"github.com/rovshanmuradov/solana-bot/internal/backtest"
 	"github.com/rovshanmuradov/solana-bot/internal/bot"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/export"
 	"github.com/rovshanmuradov/solana-bot/internal/logger"
+	"github.com/rovshanmuradov/solana-bot/internal/orders"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/rovshanmuradov/solana-bot/internal/tax"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func main() {
 	// Флаг конфигурации
 	configPath := flag.String("config", "configs/config.json", "Path to config file")
+	readOnly := flag.Bool("read-only", false, "Observe balances and executions without trading")
+	headless := flag.Bool("headless", false, "Run unattended: abort if startup checks fail")
+	showPortfolio := flag.Bool("portfolio", false, "Show wallet balances and token holdings without trading")
+	closeEmpty := flag.Bool("close-empty-accounts", false, "Close empty token accounts of all wallets to reclaim rent and exit")
+	dryRun := flag.Bool("dry-run", false, "With -close-empty-accounts, list the accounts and rent to reclaim without sending anything")
+	listOrders := flag.Bool("orders", false, "List limit orders and exit")
+	cancelOrder := flag.String("cancel-order", "", "Cancel a pending limit order by ID and exit")
+	importKeys := flag.String("keystore-import", "", "Encrypt a wallets CSV into "+bot.KeystorePath+" and exit")
+	exportKeys := flag.String("keystore-export", "", "Decrypt "+bot.KeystorePath+" into a wallets CSV and exit")
+	forceKeys := flag.Bool("keystore-force", false, "With -keystore-import, replace an existing keystore without asking")
+	exportTrades := flag.String("export-trades", "", "Export trades from "+execution.DefaultStorePath+" to a .csv or .json file and exit")
+	exportMint := flag.String("export-mint", "", "Export only trades of this token")
+	exportFrom := flag.String("export-from", "", "Export trades started on or after this date (YYYY-MM-DD)")
+	exportTo := flag.String("export-to", "", "Export trades started on or before this date (YYYY-MM-DD)")
+	taxReport := flag.String("tax-report", "", "Write a capital gains CSV for sales in the -export-from/-export-to period and exit")
+	taxMethod := flag.String("tax-method", "fifo", "Lot matching for -tax-report: fifo or lifo")
+	backtestPath := flag.String("backtest", "", "Replay recorded market snapshots (.jsonl or .jsonl.gz) through the exit rules of config.json and exit")
+	backtestAmount := flag.Float64("backtest-amount", 0.1, "SOL bought per token in -backtest")
+	backtestPlan := flag.String("backtest-plan", "", "Exit plan from exit_plans to use in -backtest")
+	verifyAudit := flag.Bool("audit-verify", false, "Check the hash chain of "+audit.DefaultPath+" and exit")
 	flag.Parse()
 
+	if *importKeys != "" || *exportKeys != "" {
+		if err := runKeystoreCommand(*importKeys, *exportKeys, *forceKeys); err != nil {
+			log.Fatalf("Keystore command failed: %v", err)
+		}
+		return
+	}
+
+	if *exportTrades != "" {
+		if err := runExportCommand(*exportTrades, *exportMint, *exportFrom, *exportTo); err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+		return
+	}
+
+	if *taxReport != "" {
+		if err := runTaxCommand(context.Background(), *taxReport, *taxMethod, *exportFrom, *exportTo); err != nil {
+			log.Fatalf("Tax report failed: %v", err)
+		}
+		return
+	}
+
+	// Команды журнала ордеров работают и при запущенном боте: он замечает отмену сам
+	if *listOrders || *cancelOrder != "" {
+		if err := runOrderCommand(orders.NewBook(orders.DefaultPath), *cancelOrder); err != nil {
+			log.Fatalf("Order command failed: %v", err)
+		}
+		return
+	}
+
+	if *verifyAudit {
+		n, err := audit.Verify(audit.DefaultPath)
+		if err != nil {
+			log.Fatalf("Audit log check failed after %d records: %v", n, err)
+		}
+		fmt.Printf("%s: %d records, chain intact\n", audit.DefaultPath, n)
+		return
+	}
+
 	// Контекст с обработкой SIGINT / SIGTERM
 	rootCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -29,6 +100,17 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if *backtestPath != "" {
+		if err := runBacktestCommand(cfg, *backtestPath, *backtestAmount, *backtestPlan); err != nil {
+			log.Fatalf("Backtest failed: %v", err)
+		}
+		return
+	}
+	cfg.ReadOnly = *readOnly
+	cfg.Headless = *headless
+	cfg.Portfolio = *showPortfolio
+	cfg.CloseEmpty = *closeEmpty
+	cfg.DryRun = *dryRun
 
 	// Логгер
 	appLogger, err := logger.CreatePrettyLogger(cfg.DebugLogging)
@@ -39,9 +121,250 @@ func main() {
 		_ = appLogger.Sync()
 	}()
 
+	// Структурированные логи по компонентам — в дополнение к консоли
+	if cfg.LogJSON {
+		level := zapcore.InfoLevel
+		if cfg.DebugLogging {
+			level = zapcore.DebugLevel
+		}
+		files, err := logger.NewComponentCore("logs", level, int64(cfg.LogMaxSizeMB)<<20, cfg.LogMaxFiles)
+		if err != nil {
+			log.Fatalf("Failed to open JSON logs: %v", err)
+		}
+		defer files.Close()
+		appLogger = appLogger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, files)
+		}))
+	}
+
 	// Runner
 	runner := bot.NewRunner(cfg, appLogger)
 	if err := runner.Run(rootCtx); err != nil && rootCtx.Err() == nil {
 		log.Fatalf("💥 Application failed to start: %v", err)
 	}
 }
+
+// runOrderCommand отменяет ордер cancelID (если задан) и выводит список ордеров.
+func runOrderCommand(book *orders.Book, cancelID string) error {
+	if cancelID != "" {
+		if err := book.Cancel(cancelID); err != nil {
+			return err
+		}
+		fmt.Println("🚫 Canceled limit order " + cancelID)
+	}
+
+	all, err := book.All()
+	if err != nil {
+		return err
+	}
+	if len(all) == 0 {
+		fmt.Println("No limit orders")
+		return nil
+	}
+	for _, o := range all {
+		line := o.CreatedAt.Format("2006-01-02 15:04:05") + "  " + o.String()
+		if o.Reason != "" {
+			line += ": " + o.Reason
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// runKeystoreCommand шифрует wallets CSV importPath в хранилище или расшифровывает
+// хранилище в exportPath. Пароль нового хранилища запрашивается дважды; существующее
+// хранилище заменяется только после подтверждения или с force.
+func runKeystoreCommand(importPath, exportPath string, force bool) error {
+	if importPath != "" {
+		if err := bot.ConfirmKeystoreOverwrite(force); err != nil {
+			return err
+		}
+		csvData, err := os.ReadFile(importPath)
+		if err != nil {
+			return err
+		}
+		defer task.Zero(csvData)
+
+		password, err := bot.ReadKeystorePassword("🔐 New keystore password: ")
+		if err != nil {
+			return err
+		}
+		defer task.Zero(password)
+		if os.Getenv(bot.KeystorePasswordEnv) == "" {
+			repeat, err := bot.ReadKeystorePassword("🔐 Repeat the password: ")
+			if err != nil {
+				return err
+			}
+			defer task.Zero(repeat)
+			if !bytes.Equal(password, repeat) {
+				return fmt.Errorf("passwords do not match")
+			}
+		}
+
+		data, err := task.EncryptKeystore(csvData, password)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(bot.KeystorePath, data, 0o600); err != nil {
+			return err
+		}
+		fmt.Printf("🔐 Wallets from %s encrypted into %s; delete the CSV once the bot starts with the keystore\n",
+			importPath, bot.KeystorePath)
+		return nil
+	}
+
+	data, err := os.ReadFile(bot.KeystorePath)
+	if err != nil {
+		return err
+	}
+	password, err := bot.ReadKeystorePassword("🔐 Keystore password: ")
+	if err != nil {
+		return err
+	}
+	defer task.Zero(password)
+	csvData, err := task.DecryptKeystore(data, password)
+	if err != nil {
+		return err
+	}
+	defer task.Zero(csvData)
+	if err := os.WriteFile(exportPath, csvData, 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("🔓 Keystore decrypted into %s; it holds plaintext keys, keep it safe\n", exportPath)
+	return nil
+}
+
+// runExportCommand выгружает сделки журнала исполнения в path: все или только токена
+// mint, за период from..to (даты YYYY-MM-DD включительно, пусто — без ограничения).
+func runExportCommand(path, mint, from, to string) error {
+	q := execution.TradeQuery{Mint: mint}
+	var err error
+	if q.From, q.To, err = parsePeriod(from, to); err != nil {
+		return err
+	}
+
+	store, err := openTradeStore()
+	if err != nil {
+		return err
+	}
+	n, err := export.NewTradeExporter(store).Export(q, path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📤 Exported %d trades to %s\n", n, path)
+	return nil
+}
+
+// openTradeStore открывает журнал сделок, спрашивая пароль хранилища, если он зашифрован.
+func openTradeStore() (*execution.Store, error) {
+	journals, err := bot.OpenJournals()
+	if err != nil {
+		return nil, err
+	}
+	store := execution.NewStore(execution.DefaultStorePath)
+	store.SetCipher(journals)
+	return store, nil
+}
+
+// runTaxCommand сопоставляет продажи с покупками методом method и записывает в path
+// отчет о прибыли и убытках по продажам за период from..to с курсом SOL/USD CoinGecko.
+func runTaxCommand(ctx context.Context, path, method, from, to string) error {
+	m, err := tax.ParseMethod(method)
+	if err != nil {
+		return err
+	}
+	fromTime, toTime, err := parsePeriod(from, to)
+	if err != nil {
+		return err
+	}
+
+	// Лоты строятся по всей истории: покупка могла быть раньше периода
+	store, err := openTradeStore()
+	if err != nil {
+		return err
+	}
+	records, err := store.Load(time.Time{})
+	if err != nil {
+		return err
+	}
+	res := tax.Match(records, m)
+	disposals := tax.InPeriod(res.Disposals, fromTime, toTime)
+	if err := tax.Value(ctx, disposals, tax.NewCoinGeckoPrices()); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := tax.WriteCSV(f, disposals); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	var gain float64
+	unknown := 0
+	for _, d := range disposals {
+		gain += d.GainUSD()
+		if d.BasisUnknown {
+			unknown++
+		}
+	}
+	fmt.Printf("🧾 %d sales (%s), net gain $%.2f written to %s\n", len(disposals), strings.ToUpper(string(m)), gain, path)
+	if unknown > 0 {
+		fmt.Printf("⚠️  %d sales have no matching buy in the log; their cost basis is 0\n", unknown)
+	}
+	if res.Skipped > 0 {
+		fmt.Printf("⚠️  %d trades were recorded without actual amounts and are left out\n", res.Skipped)
+	}
+	return nil
+}
+
+// parsePeriod разбирает границы периода YYYY-MM-DD; to включает весь день.
+func parsePeriod(from, to string) (fromTime, toTime time.Time, err error) {
+	if from != "" {
+		if fromTime, err = time.ParseInLocation(time.DateOnly, from, time.Local); err != nil {
+			return fromTime, toTime, fmt.Errorf("invalid -export-from: %w", err)
+		}
+	}
+	if to != "" {
+		if toTime, err = time.ParseInLocation(time.DateOnly, to, time.Local); err != nil {
+			return fromTime, toTime, fmt.Errorf("invalid -export-to: %w", err)
+		}
+		toTime = toTime.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return fromTime, toTime, nil
+}
+
+// runBacktestCommand прогоняет снимки рынка из path через stop-loss, take-profit и
+// trailing stop из cfg и план planName, покупая каждый токен на amount SOL.
+func runBacktestCommand(cfg *task.Config, path string, amount float64, planName string) error {
+	if amount <= 0 {
+		return fmt.Errorf("-backtest-amount must be positive")
+	}
+	strategy := backtest.Strategy{
+		AmountSol:  amount,
+		FeePercent: backtest.DefaultFeePercent,
+		StopLoss:   cfg.StopLossPercent,
+		TakeProfit: cfg.TakeProfitPercent,
+		Trailing:   cfg.TrailingStopPercent,
+		Warmup:     cfg.StopLossWarmup,
+	}
+	if planName != "" {
+		tiers, ok := cfg.ExitPlans[strings.ToLower(planName)]
+		if !ok {
+			return fmt.Errorf("exit plan %q not found in exit_plans", planName)
+		}
+		strategy.Plan = tiers
+	}
+
+	snapshots, err := backtest.ReadSnapshots(path)
+	if err != nil {
+		return err
+	}
+	fmt.Println("🧪 " + backtest.Run(snapshots, strategy).String())
+	return nil
+}
@@ -0,0 +1,204 @@
+// internal/audit/audit.go
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPath — журнал аудита команд оператора (JSON Lines).
+const DefaultPath = "logs/audit.jsonl"
+
+// Role — уровень доступа оператора. Каждая следующая роль включает права предыдущей.
+type Role int
+
+const (
+	RoleNone   Role = iota // Команды недоступны
+	RoleViewer             // Чтение: позиции, сделки, сессии, статус kill switch
+	RoleTrader             // Плюс ручные продажи и включение kill switch
+	RoleAdmin              // Плюс rearm: возобновление торговли после kill switch
+)
+
+// ParseRole разбирает роль из конфига: viewer, trader или admin.
+func ParseRole(s string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "viewer":
+		return RoleViewer, nil
+	case "trader":
+		return RoleTrader, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return RoleNone, fmt.Errorf("unknown role %q (want viewer, trader or admin)", s)
+	}
+}
+
+// String возвращает имя роли, как оно пишется в конфиге.
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleTrader:
+		return "trader"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// Allows сообщает, хватает ли роли r для действия, требующего need.
+func (r Role) Allows(need Role) bool {
+	return r != RoleNone && r >= need
+}
+
+// Principal — тот, кто отдал команду: имя клиента local RPC или пользователь Telegram.
+type Principal struct {
+	Name string
+	Role Role
+}
+
+// Entry — запись журнала аудита об одной изменяющей или отклоненной команде.
+type Entry struct {
+	At        time.Time `json:"at"`
+	Surface   string    `json:"surface"`   // "telegram" или "local_rpc"
+	Principal string    `json:"principal"` // Имя клиента или telegram:<user id>
+	Role      string    `json:"role"`
+	Command   string    `json:"command"`
+	Allowed   bool      `json:"allowed"`
+	Result    string    `json:"result,omitempty"` // Итог или причина отказа
+	Prev      string    `json:"prev"`             // SHA-256 предыдущей строки журнала
+}
+
+// Log дописывает записи в журнал аудита. Каждая запись хранит хеш предыдущей строки,
+// поэтому правку или удаление строки в середине журнала находит Verify. Nil-журнал
+// ничего не пишет.
+type Log struct {
+	path string
+	now  func() time.Time
+
+	mu     sync.Mutex
+	last   string // Хеш последней строки; читается из файла при первой записи
+	loaded bool
+}
+
+// NewLog создает журнал аудита в файле path.
+func NewLog(path string) *Log {
+	return &Log{path: path, now: time.Now}
+}
+
+// Path возвращает путь к файлу журнала.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record дописывает запись e, проставляя время и хеш предыдущей строки.
+func (l *Log) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.loaded {
+		last, err := lastLineHash(l.path)
+		if err != nil {
+			return err
+		}
+		l.last, l.loaded = last, true
+	}
+
+	if e.At.IsZero() {
+		e.At = l.now()
+	}
+	e.At = e.At.UTC()
+	e.Prev = l.last
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal audit entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("create audit log dir: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit entry: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("sync audit log: %w", err)
+	}
+	l.last = lineHash(line)
+	return nil
+}
+
+// Verify проверяет цепочку хешей журнала path и возвращает число записей. Удаление
+// последних строк цепочка не выдает: для этого сверяйте число записей с прежним.
+func Verify(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	prev, n := "", 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		n++
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return n - 1, fmt.Errorf("audit record %d: %w", n, err)
+		}
+		if e.Prev != prev {
+			return n - 1, fmt.Errorf("audit record %d: chain broken, the previous record was changed or removed", n)
+		}
+		prev = lineHash(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return n, fmt.Errorf("read audit log: %w", err)
+	}
+	return n, nil
+}
+
+// lastLineHash возвращает хеш последней непустой строки файла, "" — файла еще нет.
+func lastLineHash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read audit log: %w", err)
+	}
+	data = bytes.TrimRight(data, "\r\n\t ")
+	if len(data) == 0 {
+		return "", nil
+	}
+	if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+		data = data[i+1:]
+	}
+	return lineHash(data), nil
+}
+
+func lineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
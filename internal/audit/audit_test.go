@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRole_Allows(t *testing.T) {
+	for name, tc := range map[string]struct {
+		role, need Role
+		want       bool
+	}{
+		"viewer reads":        {RoleViewer, RoleViewer, true},
+		"viewer cannot sell":  {RoleViewer, RoleTrader, false},
+		"trader sells":        {RoleTrader, RoleTrader, true},
+		"trader cannot rearm": {RoleTrader, RoleAdmin, false},
+		"admin rearms":        {RoleAdmin, RoleAdmin, true},
+		"none reads nothing":  {RoleNone, RoleNone, false},
+	} {
+		assert.Equal(t, tc.want, tc.role.Allows(tc.need), name)
+	}
+
+	role, err := ParseRole(" Trader ")
+	require.NoError(t, err)
+	assert.Equal(t, RoleTrader, role)
+	_, err = ParseRole("root")
+	assert.EqualError(t, err, `unknown role "root" (want viewer, trader or admin)`)
+}
+
+func TestLog_Chain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	log := NewLog(path)
+	require.NoError(t, log.Record(Entry{Surface: "local_rpc", Principal: "ops", Role: "admin", Command: "panic", Allowed: true}))
+	require.NoError(t, log.Record(Entry{Surface: "telegram", Principal: "telegram:7", Role: "viewer", Command: "/rearm", Result: "needs admin"}))
+
+	// Новый экземпляр продолжает цепочку с последней строки файла
+	require.NoError(t, NewLog(path).Record(Entry{Surface: "local_rpc", Principal: "ops", Role: "admin", Command: "rearm", Allowed: true}))
+
+	n, err := Verify(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	tampered := strings.Replace(string(data), `"allowed":false`, `"allowed":true`, 1)
+	require.NoError(t, os.WriteFile(path, []byte(tampered), 0o600))
+	n, err = Verify(path)
+	assert.EqualError(t, err, "audit record 3: chain broken, the previous record was changed or removed")
+	assert.Equal(t, 2, n)
+
+	var nilLog *Log
+	assert.NoError(t, nilLog.Record(Entry{Command: "panic"}))
+}
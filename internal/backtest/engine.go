@@ -0,0 +1,191 @@
+// internal/backtest/engine.go
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// DefaultFeePercent — комиссия площадки с каждой сделки, как у Pump.fun.
+const DefaultFeePercent = 1.0
+
+// Strategy — правила входа и выхода, которые проверяет бэктест. Позиция открывается
+// по первому снимку токена и закрывается теми же правилами, что и мониторинг.
+type Strategy struct {
+	AmountSol  float64         // Покупка на каждый токен
+	FeePercent float64         // Комиссия с покупки и продажи, %
+	StopLoss   float64         // stop_loss_percent, 0 — выключен
+	TakeProfit float64         // take_profit_percent, 0 — выключен
+	Trailing   float64         // trailing_stop_percent, 0 — выключен
+	Warmup     time.Duration   // stop_loss_warmup: stop-loss не срабатывает столько после входа
+	Plan       []task.ExitTier // Ступени exit_plan, пусто — без плана
+	MaxHold    time.Duration   // Продать, если позиция держится дольше (0 — до конца данных)
+}
+
+// Trade — результат позиции по одному токену.
+type Trade struct {
+	Mint       string
+	Entry      time.Time
+	Exit       time.Time
+	EntryPrice float64
+	ExitPrice  float64
+	Reason     string // Правило, закрывшее позицию: stop_loss, exit_plan, end_of_data...
+	Steps      int    // Сработавших ступеней плана
+	PnL        model.PnLResult
+}
+
+// Summary — итоги бэктеста по всем токенам.
+type Summary struct {
+	Trades []Trade
+}
+
+// Run прогоняет снимки через стратегию: по позиции на каждый токен.
+func Run(snapshots []Snapshot, s Strategy) Summary {
+	byMint := make(map[string][]Snapshot)
+	var mints []string
+	for _, snap := range snapshots {
+		if _, ok := byMint[snap.Mint]; !ok {
+			mints = append(mints, snap.Mint)
+		}
+		byMint[snap.Mint] = append(byMint[snap.Mint], snap)
+	}
+
+	var sum Summary
+	for _, mint := range mints {
+		sum.Trades = append(sum.Trades, replay(byMint[mint], s))
+	}
+	return sum
+}
+
+// replay открывает позицию по первому снимку и ведет ее по остальным.
+func replay(snaps []Snapshot, s Strategy) Trade {
+	fee := s.FeePercent / 100
+	entry := snaps[0]
+	tr := Trade{Mint: entry.Mint, Entry: entry.Time, EntryPrice: entry.Price}
+
+	tokens := s.AmountSol * (1 - fee) / entry.Price
+	var realized float64 // SOL от частичных продаж
+	exits := monitor.NewExitRules(s.StopLoss, s.TakeProfit, s.Trailing)
+	now := entry.Time // Прогрев отсчитывается по времени снимков
+	exits.WarmUp(s.Warmup, func() time.Time { return now })
+	plan := monitor.NewExitPlan(s.Plan)
+
+	pnlAt := func(price float64) model.PnLResult {
+		estimate := realized + tokens*price*(1-fee)
+		net := estimate - s.AmountSol
+		return model.PnLResult{
+			InitialInvestment: s.AmountSol,
+			SellEstimate:      estimate,
+			NetPnL:            net,
+			PnLPercentage:     net / s.AmountSol * 100,
+		}
+	}
+	closeAt := func(snap Snapshot, reason string) Trade {
+		tr.Exit, tr.ExitPrice, tr.Reason = snap.Time, snap.Price, reason
+		tr.PnL = pnlAt(snap.Price)
+		return tr
+	}
+
+	for _, snap := range snaps[1:] {
+		now = snap.Time
+		pnl := pnlAt(snap.Price)
+		for {
+			_, percent, hit := plan.Check(pnl.PnLPercentage, snap.Price)
+			if !hit {
+				break
+			}
+			sold := tokens * percent / 100
+			realized += sold * snap.Price * (1 - fee)
+			tokens -= sold
+			tr.Steps++
+		}
+		if plan.Done() {
+			return closeAt(snap, "exit_plan")
+		}
+		if kind, hit := exits.Check(pnl.PnLPercentage, snap.Price); hit {
+			return closeAt(snap, string(kind))
+		}
+		if s.MaxHold > 0 && snap.Time.Sub(entry.Time) >= s.MaxHold {
+			return closeAt(snap, "max_hold")
+		}
+	}
+	return closeAt(snaps[len(snaps)-1], "end_of_data")
+}
+
+// Invested возвращает сумму вложений по всем токенам.
+func (s Summary) Invested() float64 {
+	var total float64
+	for _, t := range s.Trades {
+		total += t.PnL.InitialInvestment
+	}
+	return total
+}
+
+// NetPnL возвращает суммарный чистый PnL.
+func (s Summary) NetPnL() float64 {
+	var total float64
+	for _, t := range s.Trades {
+		total += t.PnL.NetPnL
+	}
+	return total
+}
+
+// Wins возвращает число прибыльных позиций.
+func (s Summary) Wins() int {
+	wins := 0
+	for _, t := range s.Trades {
+		if t.PnL.NetPnL > 0 {
+			wins++
+		}
+	}
+	return wins
+}
+
+// MaxDrawdown возвращает наибольшее падение накопленного PnL от его максимума в SOL,
+// если позиции закрывались в порядке выхода.
+func (s Summary) MaxDrawdown() float64 {
+	trades := append([]Trade(nil), s.Trades...)
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Exit.Before(trades[j].Exit) })
+
+	var equity, peak, drawdown float64
+	for _, t := range trades {
+		equity += t.PnL.NetPnL
+		peak = max(peak, equity)
+		drawdown = max(drawdown, peak-equity)
+	}
+	return drawdown
+}
+
+// String описывает итоги: сводку и строку по каждому токену.
+func (s Summary) String() string {
+	if len(s.Trades) == 0 {
+		return "No snapshots to backtest"
+	}
+	var b strings.Builder
+	percent := 0.0
+	if invested := s.Invested(); invested > 0 {
+		percent = s.NetPnL() / invested * 100
+	}
+	fmt.Fprintf(&b, "%d trades, %d wins (%.0f%%), net %+.4f SOL (%+.2f%%) on %.3f SOL, max drawdown %.4f SOL\n",
+		len(s.Trades), s.Wins(), float64(s.Wins())/float64(len(s.Trades))*100,
+		s.NetPnL(), percent, s.Invested(), s.MaxDrawdown())
+	for _, t := range s.Trades {
+		fmt.Fprintf(&b, "  %s: %+.2f%% (%+.4f SOL) by %s after %s\n",
+			shortMint(t.Mint), t.PnL.PnLPercentage, t.PnL.NetPnL, t.Reason, t.Exit.Sub(t.Entry).Round(time.Second))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// shortMint сокращает адрес токена для вывода, например "7xKX...sAsU".
+func shortMint(mint string) string {
+	if len(mint) <= 8 {
+		return mint
+	}
+	return mint[:4] + "..." + mint[len(mint)-4:]
+}
@@ -0,0 +1,85 @@
+package backtest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var t0 = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func series(mint string, prices ...float64) []Snapshot {
+	snaps := make([]Snapshot, len(prices))
+	for i, p := range prices {
+		snaps[i] = Snapshot{Time: t0.Add(time.Duration(i) * time.Second), Mint: mint, Price: p}
+	}
+	return snaps
+}
+
+func TestRun_ExitRules(t *testing.T) {
+	snaps := append(series("Pump", 1, 1.2, 1.6, 2.1, 2.5), series("Dump", 1, 0.9, 0.7, 0.5)...)
+	sum := Run(snaps, Strategy{AmountSol: 1, TakeProfit: 100, StopLoss: 25})
+	require.Len(t, sum.Trades, 2)
+
+	pump := sum.Trades[0]
+	assert.Equal(t, "take_profit", pump.Reason)
+	assert.Equal(t, 2.1, pump.ExitPrice)
+	assert.InDelta(t, 110, pump.PnL.PnLPercentage, 1e-9)
+
+	dump := sum.Trades[1]
+	assert.Equal(t, "stop_loss", dump.Reason)
+	assert.Equal(t, t0.Add(2*time.Second), dump.Exit)
+
+	assert.Equal(t, 1, sum.Wins())
+	assert.InDelta(t, 0.8, sum.NetPnL(), 1e-9)
+	assert.InDelta(t, 0.3, sum.MaxDrawdown(), 1e-9)
+
+	// Во время прогрева stop-loss пропускает просадку снимка 0.7, но не 0.5 после него
+	warm := Run(series("Dump", 1, 0.9, 0.7, 0.5), Strategy{AmountSol: 1, StopLoss: 25, Warmup: 3 * time.Second})
+	assert.Equal(t, "stop_loss", warm.Trades[0].Reason)
+	assert.Equal(t, t0.Add(3*time.Second), warm.Trades[0].Exit)
+}
+
+func TestRun_ExitPlanAndFees(t *testing.T) {
+	plan := []task.ExitTier{{SellPercent: 50, AtPnL: 50}, {Trailing: 20}}
+	sum := Run(series("Mint", 1, 1.5, 2, 1.6, 1.5), Strategy{AmountSol: 1, FeePercent: 1, Plan: plan})
+	require.Len(t, sum.Trades, 1)
+	tr := sum.Trades[0]
+	assert.Equal(t, "exit_plan", tr.Reason)
+	assert.Equal(t, 2, tr.Steps)
+	assert.Equal(t, 1.6, tr.ExitPrice)
+
+	// 0.99 токена; при 1.5 PnL с учетом комиссий только +47%, поэтому половина
+	// продана по 2, остаток — по 1.6 на откате 20% от максимума
+	want := 0.495*2*0.99 + 0.495*1.6*0.99 - 1
+	assert.InDelta(t, want, tr.PnL.NetPnL, 1e-9)
+
+	end := Run(series("Flat", 1, 1.01), Strategy{AmountSol: 1})
+	assert.Equal(t, "end_of_data", end.Trades[0].Reason)
+}
+
+func TestReadSnapshots_Gzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "market.jsonl.gz")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	enc := json.NewEncoder(gz)
+	require.NoError(t, enc.Encode(Snapshot{Time: t0.Add(time.Second), Mint: "Mint", Price: 2}))
+	_, _ = gz.Write([]byte("garbage\n"))
+	require.NoError(t, enc.Encode(Snapshot{Time: t0, Mint: "Mint", Price: 1, SolReserve: 30_000_000_000}))
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	snaps, err := ReadSnapshots(path)
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	assert.Equal(t, 1.0, snaps[0].Price)
+	assert.Equal(t, uint64(30_000_000_000), snaps[0].SolReserve)
+}
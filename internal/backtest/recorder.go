@@ -0,0 +1,124 @@
+// internal/backtest/recorder.go
+package backtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRecorderDir — каталог записанных снимков рынка.
+	DefaultRecorderDir = "logs"
+	// recorderFlushInterval — как часто накопленные снимки сбрасываются на диск.
+	recorderFlushInterval = 5 * time.Second
+)
+
+// Recorder записывает снимки рынка мониторящихся токенов в сжатые файлы JSON Lines,
+// по файлу на день: market-2025-01-01.jsonl.gz. Каждый сброс дописывает в файл
+// отдельный gzip-поток, поэтому сбой теряет только несброшенные снимки.
+// Методы безопасны для nil: без рекордера снимки не записываются.
+type Recorder struct {
+	dir string
+
+	mu      sync.Mutex
+	pending []Snapshot
+}
+
+// NewRecorder создает рекордер, пишущий в каталог dir.
+func NewRecorder(dir string) *Recorder {
+	return &Recorder{dir: dir}
+}
+
+// Record ставит снимок в очередь на запись.
+func (r *Recorder) Record(s Snapshot) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.pending = append(r.pending, s)
+	r.mu.Unlock()
+}
+
+// Run сбрасывает снимки на диск раз в recorderFlushInterval, пока не отменен ctx,
+// и последний раз при отмене.
+func (r *Recorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(recorderFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return r.Flush()
+		case <-ticker.C:
+			if err := r.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Flush дописывает накопленные снимки в файлы их дней.
+func (r *Recorder) Flush() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	byFile := make(map[string][]Snapshot)
+	var files []string
+	for _, s := range pending {
+		name := r.Path(s.Time)
+		if _, ok := byFile[name]; !ok {
+			files = append(files, name)
+		}
+		byFile[name] = append(byFile[name], s)
+	}
+	for _, name := range files {
+		if err := appendGzip(name, byFile[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Path возвращает файл снимков дня at.
+func (r *Recorder) Path(at time.Time) string {
+	return filepath.Join(r.dir, "market-"+at.UTC().Format(time.DateOnly)+".jsonl.gz")
+}
+
+// appendGzip дописывает снимки в файл path одним gzip-потоком.
+func appendGzip(path string, snapshots []Snapshot) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, s := range snapshots {
+		if err := enc.Encode(s); err != nil {
+			return fmt.Errorf("encode snapshot: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("compress snapshots: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create market data dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open market data: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("write market data: %w", err)
+	}
+	return f.Close()
+}
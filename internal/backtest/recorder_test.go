@@ -0,0 +1,36 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_AppendsDailyGzipFiles(t *testing.T) {
+	rec := NewRecorder(t.TempDir())
+	rec.Record(Snapshot{Time: t0, Mint: "Mint", Venue: "Pump.fun", Price: 1})
+	require.NoError(t, rec.Flush())
+	rec.Record(Snapshot{Time: t0.Add(time.Second), Mint: "Mint", Price: 2})
+	rec.Record(Snapshot{Time: t0.Add(24 * time.Hour), Mint: "Mint", Price: 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, rec.Run(ctx), "cancel flushes the rest")
+
+	snaps, err := ReadSnapshots(rec.Path(t0))
+	require.NoError(t, err)
+	require.Len(t, snaps, 2, "both gzip streams are read")
+	assert.Equal(t, "Pump.fun", snaps[0].Venue)
+	assert.Equal(t, 2.0, snaps[1].Price)
+
+	next, err := ReadSnapshots(rec.Path(t0.Add(24 * time.Hour)))
+	require.NoError(t, err)
+	assert.Len(t, next, 1)
+
+	var none *Recorder
+	none.Record(Snapshot{})
+	assert.NoError(t, none.Flush())
+}
@@ -0,0 +1,60 @@
+// internal/backtest/snapshot.go
+package backtest
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snapshot — записанное состояние рынка токена: цена и, если известны, резервы
+// bonding curve или пула. Одна запись — одна строка JSON Lines.
+type Snapshot struct {
+	Time         time.Time `json:"ts"`
+	Mint         string    `json:"mint"`
+	Venue        string    `json:"venue,omitempty"`
+	Price        float64   `json:"price"`                   // SOL за токен
+	SolReserve   uint64    `json:"sol_reserve,omitempty"`   // Lamports в резерве кривой или пула
+	TokenReserve uint64    `json:"token_reserve,omitempty"` // Raw токены в резерве
+}
+
+// ReadSnapshots читает снимки из файла JSON Lines; файл .gz распаковывается.
+// Поврежденные строки пропускаются. Снимки возвращаются упорядоченными по времени.
+func ReadSnapshots(path string) ([]Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open snapshots: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("open gzip snapshots: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var s Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil || s.Mint == "" || s.Price <= 0 {
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read snapshots: %w", err)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return snapshots, nil
+}
@@ -0,0 +1,116 @@
+// internal/blockchain/batch.go
+package blockchain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// maxBatchAccounts — лимит getMultipleAccounts на число аккаунтов в одном запросе
+	maxBatchAccounts = 100
+	// batchRequestTimeout ограничивает общий запрос: он не зависит от отмены отдельных вызовов
+	batchRequestTimeout = 10 * time.Second
+)
+
+// accountBatcher собирает GetAccountInfo, вызванные в пределах окна window, в один
+// getMultipleAccounts. Одинаковые адреса в окне запрашиваются один раз.
+type accountBatcher struct {
+	window time.Duration
+	fetch  func(ctx context.Context, keys []solana.PublicKey) (*rpc.GetMultipleAccountsResult, error)
+
+	mu      sync.Mutex
+	pending *accountBatch
+}
+
+// accountBatch — набор адресов одного окна и общий результат запроса.
+type accountBatch struct {
+	ctx   context.Context // Контекст первого вызова без отмены: значения сохраняются
+	keys  []solana.PublicKey
+	index map[solana.PublicKey]int
+	done  chan struct{}
+	res   *rpc.GetMultipleAccountsResult
+	err   error
+}
+
+func newAccountBatcher(window time.Duration, fetch func(context.Context, []solana.PublicKey) (*rpc.GetMultipleAccountsResult, error)) *accountBatcher {
+	return &accountBatcher{window: window, fetch: fetch}
+}
+
+// get добавляет адрес в текущее окно и ждет общего ответа. Отсутствующий аккаунт
+// возвращает rpc.ErrNotFound, как и одиночный GetAccountInfo.
+func (b *accountBatcher) get(ctx context.Context, key solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+	b.mu.Lock()
+	batch := b.pending
+	if batch == nil {
+		batch = &accountBatch{
+			ctx:   context.WithoutCancel(ctx),
+			index: make(map[solana.PublicKey]int),
+			done:  make(chan struct{}),
+		}
+		b.pending = batch
+		time.AfterFunc(b.window, func() { b.flush(batch) })
+	}
+	i, ok := batch.index[key]
+	if !ok {
+		i = len(batch.keys)
+		batch.index[key] = i
+		batch.keys = append(batch.keys, key)
+	}
+	if len(batch.keys) >= maxBatchAccounts {
+		// Полное окно уходит сразу, следующие вызовы начинают новое
+		b.pending = nil
+		go b.run(batch)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	if batch.res == nil || i >= len(batch.res.Value) || batch.res.Value[i] == nil {
+		return nil, rpc.ErrNotFound
+	}
+	return &rpc.GetAccountInfoResult{RPCContext: batch.res.RPCContext, Value: batch.res.Value[i]}, nil
+}
+
+// flush отправляет окно по таймеру, если оно еще не ушло заполненным.
+func (b *accountBatcher) flush(batch *accountBatch) {
+	b.mu.Lock()
+	if b.pending != batch {
+		b.mu.Unlock()
+		return
+	}
+	b.pending = nil
+	b.mu.Unlock()
+	b.run(batch)
+}
+
+func (b *accountBatcher) run(batch *accountBatch) {
+	ctx, cancel := context.WithTimeout(batch.ctx, batchRequestTimeout)
+	defer cancel()
+	batch.res, batch.err = b.fetch(ctx, batch.keys)
+	close(batch.done)
+}
+
+// SetAccountBatching включает объединение одновременных GetAccountInfo: вызовы в пределах
+// window уходят одним getMultipleAccounts с той же кодировкой и commitment. 0 — выключено.
+func (c *Client) SetAccountBatching(window time.Duration) {
+	if window <= 0 {
+		c.accounts = nil
+		return
+	}
+	c.accounts = newAccountBatcher(window, func(ctx context.Context, keys []solana.PublicKey) (*rpc.GetMultipleAccountsResult, error) {
+		return c.rpc.GetMultipleAccountsWithOpts(ctx, keys, &rpc.GetMultipleAccountsOpts{
+			Encoding: solana.EncodingBase64,
+		})
+	})
+}
@@ -0,0 +1,86 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountBatcher_CoalescesConcurrentReads(t *testing.T) {
+	missing := solana.NewWallet().PublicKey()
+	var calls atomic.Int32
+	var requested []solana.PublicKey
+	b := newAccountBatcher(20*time.Millisecond, func(_ context.Context, keys []solana.PublicKey) (*rpc.GetMultipleAccountsResult, error) {
+		calls.Add(1)
+		requested = keys
+		res := &rpc.GetMultipleAccountsResult{Value: make([]*rpc.Account, len(keys))}
+		for i, k := range keys {
+			if !k.Equals(missing) {
+				res.Value[i] = &rpc.Account{Owner: k, Lamports: uint64(i + 1)}
+			}
+		}
+		return res, nil
+	})
+
+	keys := []solana.PublicKey{solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), missing}
+	keys = append(keys, keys[0])
+	results := make([]*rpc.GetAccountInfoResult, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = b.get(context.Background(), k)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Len(t, requested, 3, "a repeated address is requested once")
+	for _, i := range []int{0, 1} {
+		require.NoError(t, errs[i])
+		assert.Equal(t, keys[i], results[i].Value.Owner)
+	}
+	require.NoError(t, errs[3])
+	assert.Equal(t, keys[0], results[3].Value.Owner)
+	assert.ErrorIs(t, errs[2], rpc.ErrNotFound)
+}
+
+func TestAccountBatcher_FullBatchAndErrors(t *testing.T) {
+	var calls atomic.Int32
+	failed := errors.New("rpc down")
+	b := newAccountBatcher(time.Hour, func(_ context.Context, keys []solana.PublicKey) (*rpc.GetMultipleAccountsResult, error) {
+		calls.Add(1)
+		return nil, failed
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, maxBatchAccounts)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = b.get(context.Background(), solana.NewWallet().PublicKey())
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "a full batch is sent without waiting for the window")
+	for _, err := range errs {
+		assert.ErrorIs(t, err, failed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := b.get(ctx, solana.NewWallet().PublicKey())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,86 @@
+// internal/blockchain/blockhash.go
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// DefaultBlockhashRefresh — период обновления кеша blockhash (примерно один слот).
+	DefaultBlockhashRefresh = 400 * time.Millisecond
+	// blockhashMaxAge — защита от устаревания: более старый кешированный blockhash не
+	// используется, а запрашивается синхронно. Blockhash действителен ~60 секунд, и почти
+	// все это время нужно оставить на подтверждение.
+	blockhashMaxAge = 5 * time.Second
+)
+
+// blockhashCache — последний полученный blockhash и момент его получения.
+type blockhashCache struct {
+	mu        sync.RWMutex
+	hash      solana.Hash
+	fetchedAt time.Time
+}
+
+// PrefetchBlockhash обновляет кеш blockhash каждые interval до отмены ctx, чтобы сборка
+// транзакции не ждала RPC. Ошибки обновления не прерывают цикл: кеш просто стареет,
+// и после blockhashMaxAge LatestBlockhash снова ходит в RPC сам.
+func (c *Client) PrefetchBlockhash(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultBlockhashRefresh
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		if _, _, err := c.fetchBlockhash(ctx); err != nil && ctx.Err() == nil {
+			failures++
+			if failures == 1 || failures%100 == 0 {
+				c.logger.Warn(fmt.Sprintf("⚠️  Blockhash prefetch failed (%d in a row): %v", failures, err))
+			}
+		} else {
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// LatestBlockhash возвращает blockhash и момент его получения: из кеша, если он свежее
+// blockhashMaxAge, иначе — синхронным запросом к RPC.
+func (c *Client) LatestBlockhash(ctx context.Context) (solana.Hash, time.Time, error) {
+	c.blockhash.mu.RLock()
+	hash, fetchedAt := c.blockhash.hash, c.blockhash.fetchedAt
+	c.blockhash.mu.RUnlock()
+
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) < blockhashMaxAge {
+		return hash, fetchedAt, nil
+	}
+	return c.fetchBlockhash(ctx)
+}
+
+// fetchBlockhash запрашивает blockhash уровня confirmed: он на ~30 слотов новее finalized
+// и оставляет больше времени до истечения транзакции.
+func (c *Client) fetchBlockhash(ctx context.Context) (solana.Hash, time.Time, error) {
+	result, err := c.rpc.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		return solana.Hash{}, time.Time{}, err
+	}
+	now := time.Now()
+
+	c.blockhash.mu.Lock()
+	c.blockhash.hash = result.Value.Blockhash
+	c.blockhash.fetchedAt = now
+	c.blockhash.mu.Unlock()
+	return result.Value.Blockhash, now, nil
+}
@@ -0,0 +1,92 @@
+// internal/blockchain/cache.go
+package blockchain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// MintCacheTTL — сколько хранится mint-аккаунт: программа и decimals неизменны,
+	// authority и комиссия Token-2022 меняются редко (mint watch сбрасывает кеш при изменении).
+	MintCacheTTL = 5 * time.Minute
+	// ConfigCacheTTL — глобальные конфигурации программ DEX: комиссии и получатели
+	// меняются редко, но изменение должно подхватываться без перезапуска.
+	ConfigCacheTTL = time.Minute
+)
+
+// accountCache — кеш ответов GetAccountInfo для редко меняющихся аккаунтов, общий
+// для всех DEX и PoolManager, созданных поверх одного клиента.
+type accountCache struct {
+	mu      sync.Mutex
+	entries map[solana.PublicKey]cachedAccount
+	now     func() time.Time
+}
+
+type cachedAccount struct {
+	result  *rpc.GetAccountInfoResult
+	expires time.Time
+}
+
+func newAccountCache() *accountCache {
+	return &accountCache{entries: make(map[solana.PublicKey]cachedAccount), now: time.Now}
+}
+
+func (c *accountCache) get(key solana.PublicKey) (*rpc.GetAccountInfoResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (c *accountCache) put(key solana.PublicKey, result *rpc.GetAccountInfoResult, ttl time.Duration) {
+	c.mu.Lock()
+	c.entries[key] = cachedAccount{result: result, expires: c.now().Add(ttl)}
+	c.mu.Unlock()
+}
+
+func (c *accountCache) invalidate(keys ...solana.PublicKey) {
+	c.mu.Lock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+}
+
+// GetCachedAccountInfo возвращает аккаунт из кеша, если он получен не раньше ttl назад,
+// иначе запрашивает его через GetAccountInfo. Отсутствующие аккаунты и ошибки не
+// кешируются: аккаунт может появиться, а сбой RPC — пройти.
+func (c *Client) GetCachedAccountInfo(ctx context.Context, pubkey solana.PublicKey, ttl time.Duration) (*rpc.GetAccountInfoResult, error) {
+	if c.cache == nil || ttl <= 0 {
+		return c.GetAccountInfo(ctx, pubkey)
+	}
+	if result, ok := c.cache.get(pubkey); ok {
+		return result, nil
+	}
+	result, err := c.GetAccountInfo(ctx, pubkey)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil && result.Value != nil {
+		c.cache.put(pubkey, result, ttl)
+	}
+	return result, nil
+}
+
+// InvalidateAccount удаляет аккаунты из кеша: следующее чтение пойдет в RPC.
+func (c *Client) InvalidateAccount(pubkeys ...solana.PublicKey) {
+	if c.cache != nil {
+		c.cache.invalidate(pubkeys...)
+	}
+}
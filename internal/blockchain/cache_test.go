@@ -0,0 +1,69 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// accountTransport отвечает на getAccountInfo заданным JSON и считает запросы.
+type accountTransport struct {
+	response string
+	calls    int
+}
+
+func (a *accountTransport) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	a.calls++
+	return json.Unmarshal([]byte(a.response), out)
+}
+
+func (a *accountTransport) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	return nil
+}
+
+func (a *accountTransport) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	return nil, nil
+}
+
+func TestGetCachedAccountInfo(t *testing.T) {
+	tr := &accountTransport{response: `{"context":{"slot":1},"value":{"lamports":7,"owner":"` +
+		solana.TokenProgramID.String() + `","data":["AAAA","base64"],"executable":false,"rentEpoch":0}}`}
+	c := &Client{rpc: rpc.NewWithCustomRPCClient(tr), logger: zap.NewNop(), cache: newAccountCache()}
+	now := time.Unix(1_700_000_000, 0)
+	c.cache.now = func() time.Time { return now }
+	key := solana.NewWallet().PublicKey()
+
+	for range 3 {
+		info, err := c.GetCachedAccountInfo(context.Background(), key, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), info.Value.Lamports)
+	}
+	assert.Equal(t, 1, tr.calls, "fresh entries are served from the cache")
+
+	now = now.Add(time.Minute)
+	_, err := c.GetCachedAccountInfo(context.Background(), key, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, tr.calls, "an expired entry is fetched again")
+
+	c.InvalidateAccount(key)
+	_, err = c.GetCachedAccountInfo(context.Background(), key, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 3, tr.calls, "an invalidated entry is fetched again")
+
+	tr.response = `{"context":{"slot":1},"value":null}`
+	other := solana.NewWallet().PublicKey()
+	for range 2 {
+		_, err = c.GetCachedAccountInfo(context.Background(), other, time.Minute)
+		assert.ErrorIs(t, err, rpc.ErrNotFound)
+	}
+	assert.Equal(t, 5, tr.calls, "missing accounts are not cached")
+}
@@ -0,0 +1,340 @@
+// internal/blockchain/confirm.go
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// Интервалы ожидания подтверждения при активной подписке signatureSubscribe.
+const (
+	trackedPollInterval = 2 * time.Second  // Страховочный опрос статусов, пока подписки живы
+	expiryCheckInterval = 2 * time.Second  // Как часто проверяется, не истек ли blockhash
+	confirmDialTimeout  = 2 * time.Second  // Подключение к WebSocket не задерживает опрос дольше
+	confirmRetryDelay   = 10 * time.Second // Пауза перед новым подключением после ошибки
+)
+
+// ErrBlockhashExpired возвращается, если blockhash транзакции истек, а она так и не
+// попала в блок: ждать дальше бессмысленно, транзакцию нужно собрать заново.
+var ErrBlockhashExpired = errors.New("transaction blockhash expired before confirmation")
+
+// sigResult — итог подписи из уведомления signatureNotification.
+type sigResult struct {
+	index int
+	err   error
+}
+
+// sigWatch — подписка signatureSubscribe одного ожидающего на одну подпись.
+type sigWatch struct {
+	index   int
+	reqID   uint64
+	subID   uint64 // 0, пока сервер не подтвердил подписку
+	results chan<- sigResult
+	acks    chan<- struct{}
+}
+
+// confirmTracker держит одно WebSocket-соединение, по которому подписывается на
+// подписи всех ожидаемых транзакций: о подтверждении сервер сообщает сам, и
+// опрашивать getSignatureStatuses каждые checkInterval не нужно. Соединение
+// открывается при первой подписке и после обрыва — при следующей.
+type confirmTracker struct {
+	wsURL  string
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	conn      *wsConn
+	lost      chan struct{} // Закрывается при обрыве текущего соединения
+	downUntil time.Time     // До этого момента подключение не повторяется
+	nextID    uint64
+	requests  map[uint64]*sigWatch // id запроса signatureSubscribe → подписка
+	subs      map[uint64]*sigWatch // id подписки → подписка
+}
+
+// SetConfirmationSocket включает ожидание подтверждений через signatureSubscribe на
+// WebSocket-адресе wsURL. Опрос статусов остается страховкой, а при недоступности
+// WebSocket снова становится частым. Копии WithEndpoint используют то же соединение.
+func (c *Client) SetConfirmationSocket(wsURL string) {
+	if wsURL == "" {
+		c.confirm = nil
+		return
+	}
+	c.confirm = &confirmTracker{wsURL: wsURL, logger: c.logger}
+}
+
+// watch подписывается на подписи sigs. Итоги приходят в results, подтверждения
+// подписок — в acks; lost закрывается при обрыве соединения.
+func (t *confirmTracker) watch(ctx context.Context, sigs []solana.Signature, commitment rpc.CommitmentType, results chan<- sigResult, acks chan<- struct{}) (watches []*sigWatch, lost <-chan struct{}, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		if time.Now().Before(t.downUntil) {
+			return nil, nil, errors.New("confirmation websocket is unavailable")
+		}
+		dialCtx, cancel := context.WithTimeout(ctx, confirmDialTimeout)
+		conn, err := dialWebSocket(dialCtx, t.wsURL)
+		cancel()
+		if err != nil {
+			t.downUntil = time.Now().Add(confirmRetryDelay)
+			return nil, nil, err
+		}
+		t.conn = conn
+		t.lost = make(chan struct{})
+		t.requests = make(map[uint64]*sigWatch)
+		t.subs = make(map[uint64]*sigWatch)
+		go t.read(conn, t.lost)
+	}
+
+	for i, sig := range sigs {
+		t.nextID++
+		w := &sigWatch{index: i, reqID: t.nextID, results: results, acks: acks}
+		request, err := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      w.reqID,
+			"method":  "signatureSubscribe",
+			"params": []interface{}{
+				sig.String(),
+				map[string]interface{}{"commitment": commitment},
+			},
+		})
+		if err != nil {
+			t.unwatchLocked(watches)
+			return nil, nil, fmt.Errorf("marshal signatureSubscribe: %w", err)
+		}
+		t.requests[w.reqID] = w
+		watches = append(watches, w)
+		if err := t.conn.WriteMessage(request); err != nil {
+			t.unwatchLocked(watches)
+			t.conn.Close() // Читающая горутина закроет lost
+			return nil, nil, err
+		}
+	}
+	return watches, t.lost, nil
+}
+
+// unwatch снимает подписки, по которым ожидающему больше ничего не нужно.
+func (t *confirmTracker) unwatch(watches []*sigWatch) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.unwatchLocked(watches)
+}
+
+func (t *confirmTracker) unwatchLocked(watches []*sigWatch) {
+	for _, w := range watches {
+		delete(t.requests, w.reqID)
+		if w.subID == 0 || t.subs[w.subID] != w {
+			continue // Сервер сам снимает подписку после уведомления
+		}
+		delete(t.subs, w.subID)
+		t.nextID++
+		request, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      t.nextID,
+			"method":  "signatureUnsubscribe",
+			"params":  []interface{}{w.subID},
+		})
+		if t.conn != nil {
+			_ = t.conn.WriteMessage(request)
+		}
+	}
+}
+
+// read разбирает ответы и уведомления соединения conn до его обрыва.
+func (t *confirmTracker) read(conn *wsConn, lost chan struct{}) {
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			t.mu.Lock()
+			if t.conn == conn {
+				t.conn = nil
+			}
+			t.mu.Unlock()
+			conn.Close()
+			close(lost)
+			t.logger.Debug("Confirmation websocket closed", zap.Error(err))
+			return
+		}
+
+		var msg struct {
+			ID     uint64          `json:"id"`
+			Method string          `json:"method"`
+			Result json.RawMessage `json:"result"`
+			Error  *rpcError       `json:"error"`
+			Params struct {
+				Subscription uint64 `json:"subscription"`
+				Result       struct {
+					Value struct {
+						Err interface{} `json:"err"`
+					} `json:"value"`
+				} `json:"result"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+
+		t.mu.Lock()
+		switch {
+		case msg.ID != 0:
+			w := t.requests[msg.ID]
+			delete(t.requests, msg.ID)
+			if w == nil {
+				break
+			}
+			if msg.Error != nil {
+				t.logger.Debug("signatureSubscribe rejected: " + msg.Error.Message)
+				break
+			}
+			if err := json.Unmarshal(msg.Result, &w.subID); err != nil {
+				break
+			}
+			t.subs[w.subID] = w
+			w.acks <- struct{}{}
+		case msg.Method == "signatureNotification":
+			w := t.subs[msg.Params.Subscription]
+			delete(t.subs, msg.Params.Subscription)
+			if w == nil {
+				break
+			}
+			var res error
+			if txErr := msg.Params.Result.Value.Err; txErr != nil {
+				res = fmt.Errorf("transaction failed: %v", txErr)
+			}
+			select {
+			case w.results <- sigResult{index: w.index, err: res}:
+			default:
+			}
+		}
+		t.mu.Unlock()
+	}
+}
+
+// awaitSignatures ждет, пока одна из подписей sigs достигнет commitment или упадет, но
+// не дольше wait (0 — пока не отменен ctx), и возвращает ее индекс; -1 без ошибки
+// значит, что wait истек. Если задан blockhash транзакций, его истечение до попадания
+// любой из них в блок возвращает ErrBlockhashExpired.
+func (c *Client) awaitSignatures(
+	ctx context.Context,
+	sigs []solana.Signature,
+	commitment rpc.CommitmentType,
+	wait time.Duration,
+	blockhash solana.Hash,
+) (int, error) {
+	if len(sigs) == 0 {
+		if wait <= 0 {
+			<-ctx.Done()
+			return -1, ctx.Err()
+		}
+		select {
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		case <-time.After(wait):
+			return -1, nil
+		}
+	}
+
+	results := make(chan sigResult, len(sigs))
+	acks := make(chan struct{}, len(sigs))
+	var lost <-chan struct{}
+	if c.confirm != nil {
+		watches, l, err := c.confirm.watch(ctx, sigs, commitment, results, acks)
+		if err != nil {
+			c.logger.Debug("Confirmation websocket unavailable, polling statuses", zap.Error(err))
+		} else {
+			defer c.confirm.unwatch(watches)
+			lost = l
+		}
+	}
+
+	var deadline <-chan time.Time
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	subscribed := 0
+	var expiryChecked time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		case <-deadline:
+			return -1, nil
+		case r := <-results:
+			return r.index, r.err
+		case <-acks:
+			if subscribed++; subscribed < len(sigs) {
+				continue
+			}
+			// Все подписки активны: проверяем статусы на случай, если транзакция
+			// подтвердилась до подписки, а дальше опрашиваем лишь для страховки
+			ticker.Reset(trackedPollInterval)
+		case <-lost:
+			lost = nil
+			ticker.Reset(checkInterval)
+		case <-ticker.C:
+		}
+
+		index, landed, err := c.pollSignatures(ctx, sigs, commitment)
+		if index >= 0 || err != nil {
+			return index, err
+		}
+		if landed || blockhash.IsZero() || time.Since(expiryChecked) < expiryCheckInterval {
+			continue
+		}
+		expiryChecked = time.Now()
+		if c.blockhashExpired(ctx, blockhash) {
+			// Транзакция могла попасть в блок перед самым истечением
+			index, landed, err := c.pollSignatures(ctx, sigs, commitment)
+			if index >= 0 || err != nil {
+				return index, err
+			}
+			if !landed {
+				return -1, ErrBlockhashExpired
+			}
+		}
+	}
+}
+
+// pollSignatures запрашивает статусы подписей. Возвращает индекс подписи, достигшей
+// commitment или упавшей (-1 — таких нет), и признак того, что какая-то из них уже в блоке.
+func (c *Client) pollSignatures(ctx context.Context, sigs []solana.Signature, commitment rpc.CommitmentType) (index int, landed bool, err error) {
+	resp, err := c.rpc.GetSignatureStatuses(ctx, true, sigs...)
+	if err != nil || resp == nil {
+		if err != nil && ctx.Err() == nil {
+			c.logger.Debug("Error getting signature statuses", zap.Error(err))
+		}
+		return -1, false, nil
+	}
+	for i, status := range resp.Value {
+		if status == nil || i >= len(sigs) {
+			continue
+		}
+		landed = true
+		if status.Err != nil {
+			return i, true, fmt.Errorf("transaction failed: %v", status.Err)
+		}
+		if contains(okStatuses[commitment], status.ConfirmationStatus) {
+			return i, true, nil
+		}
+	}
+	return -1, landed, nil
+}
+
+// blockhashExpired сообщает, что blockhash больше не принимается сетью. При ошибке
+// запроса считается, что он еще действителен.
+func (c *Client) blockhashExpired(ctx context.Context, blockhash solana.Hash) bool {
+	res, err := c.rpc.IsBlockhashValid(ctx, blockhash, rpc.CommitmentConfirmed)
+	return err == nil && res != nil && !res.Value
+}
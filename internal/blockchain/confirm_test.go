@@ -0,0 +1,138 @@
+package blockchain
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// methodTransport отвечает на каждый метод RPC заданным JSON и считает запросы.
+type methodTransport struct {
+	mu        sync.Mutex
+	responses map[string]string
+	calls     map[string]int
+}
+
+func (m *methodTransport) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.calls == nil {
+		m.calls = make(map[string]int)
+	}
+	m.calls[method]++
+	return json.Unmarshal([]byte(m.responses[method]), out)
+}
+
+func (m *methodTransport) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	return nil
+}
+
+func (m *methodTransport) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	return nil, nil
+}
+
+func (m *methodTransport) count(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+// signatureServer подтверждает подписку signatureSubscribe и отправляет уведомление с txErr.
+func signatureServer(t *testing.T, sig solana.Signature, txErr interface{}) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + wsAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+
+		server := &wsConn{conn: conn, br: bufio.NewReader(rw)}
+		data, err := server.ReadMessage()
+		require.NoError(t, err)
+		var req struct {
+			ID     uint64        `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(data, &req))
+		assert.Equal(t, "signatureSubscribe", req.Method)
+		assert.Equal(t, sig.String(), req.Params[0])
+		assert.Equal(t, map[string]interface{}{"commitment": "confirmed"}, req.Params[1])
+
+		ack, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "result": 11, "id": req.ID})
+		require.NoError(t, server.WriteMessage(ack))
+		note, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "signatureNotification",
+			"params": map[string]interface{}{
+				"subscription": 11,
+				"result":       map[string]interface{}{"context": map[string]interface{}{"slot": 5}, "value": map[string]interface{}{"err": txErr}},
+			},
+		})
+		require.NoError(t, server.WriteMessage(note))
+		_, _ = server.ReadMessage() // Держим соединение, пока клиент его не закроет
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestAwaitSignatures_Notification(t *testing.T) {
+	sig := solana.Signature{4, 5, 6}
+	for name, tc := range map[string]struct {
+		txErr   interface{}
+		wantErr string
+	}{
+		"confirmed": {},
+		"failed":    {txErr: map[string]interface{}{"InstructionError": []interface{}{2, map[string]interface{}{"Custom": 6005}}}, wantErr: "transaction failed"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tr := &methodTransport{responses: map[string]string{"getSignatureStatuses": `{"context":{"slot":1},"value":[null]}`}}
+			c := &Client{rpc: rpc.NewWithCustomRPCClient(tr), logger: zap.NewNop()}
+			c.SetConfirmationSocket(signatureServer(t, sig, tc.txErr))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			index, err := c.awaitSignatures(ctx, []solana.Signature{sig}, rpc.CommitmentConfirmed, 0, solana.Hash{})
+			assert.Equal(t, 0, index)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+			assert.LessOrEqual(t, tr.count("getSignatureStatuses"), 2, "the subscription replaces frequent polling")
+		})
+	}
+}
+
+func TestAwaitSignatures_BlockhashExpired(t *testing.T) {
+	sig := solana.Signature{7}
+	hash := solana.Hash{8}
+	tr := &methodTransport{responses: map[string]string{
+		"getSignatureStatuses": `{"context":{"slot":1},"value":[null]}`,
+		"isBlockhashValid":     `{"context":{"slot":1},"value":false}`,
+	}}
+	c := &Client{rpc: rpc.NewWithCustomRPCClient(tr), logger: zap.NewNop()}
+
+	_, err := c.awaitSignatures(context.Background(), []solana.Signature{sig}, rpc.CommitmentConfirmed, 5*time.Second, hash)
+	assert.ErrorIs(t, err, ErrBlockhashExpired)
+
+	// Транзакция уже в блоке, но еще не confirmed: истекший blockhash ей не помеха
+	tr.responses["getSignatureStatuses"] = `{"context":{"slot":1},"value":[{"slot":1,"confirmations":0,"err":null,"confirmationStatus":"processed"}]}`
+	index, err := c.awaitSignatures(context.Background(), []solana.Signature{sig}, rpc.CommitmentConfirmed, 500*time.Millisecond, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, index, "the wait runs out without an expiry error")
+}
@@ -0,0 +1,129 @@
+// internal/blockchain/fee_sponsor.go
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// HTTPFeeSponsor — сервис спонсирования комиссий с простым JSON API:
+//
+//	GET  <url> → {"fee_payer": "<pubkey>"}
+//	POST <url> {"transaction": "<base64>"} → {"transaction": "<base64 с подписью fee_payer>"}
+//
+// Сервис подписывает транзакцию как плательщик комиссий, но не отправляет ее.
+type HTTPFeeSponsor struct {
+	url    string
+	payer  solana.PublicKey
+	client *http.Client
+}
+
+// NewHTTPFeeSponsor запрашивает у сервиса адрес плательщика комиссий.
+func NewHTTPFeeSponsor(ctx context.Context, url string) (*HTTPFeeSponsor, error) {
+	s := &HTTPFeeSponsor{url: url, client: &http.Client{Timeout: 5 * time.Second}}
+
+	var info struct {
+		FeePayer string `json:"fee_payer"`
+	}
+	if err := s.call(ctx, http.MethodGet, nil, &info); err != nil {
+		return nil, err
+	}
+	payer, err := solana.PublicKeyFromBase58(info.FeePayer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee_payer %q: %w", info.FeePayer, err)
+	}
+	s.payer = payer
+	return s, nil
+}
+
+// PublicKey возвращает адрес плательщика комиссий сервиса.
+func (s *HTTPFeeSponsor) PublicKey() solana.PublicKey {
+	return s.payer
+}
+
+// SignAsPayer отправляет транзакцию сервису и переносит его подпись в tx.
+// Подпись принимается, только если сервис не изменил сообщение транзакции.
+func (s *HTTPFeeSponsor) SignAsPayer(ctx context.Context, tx *solana.Transaction) error {
+	if len(tx.Message.AccountKeys) == 0 || !tx.Message.AccountKeys[0].Equals(s.payer) {
+		return fmt.Errorf("transaction fee payer is not %s", s.payer)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("encode transaction: %w", err)
+	}
+
+	var resp struct {
+		Transaction string `json:"transaction"`
+	}
+	req := map[string]string{"transaction": base64.StdEncoding.EncodeToString(raw)}
+	if err := s.call(ctx, http.MethodPost, req, &resp); err != nil {
+		return err
+	}
+
+	signed, err := solana.TransactionFromBase64(resp.Transaction)
+	if err != nil {
+		return fmt.Errorf("decode sponsored transaction: %w", err)
+	}
+	return applySponsorSignature(tx, signed, s.payer)
+}
+
+// applySponsorSignature копирует подпись payer из signed в tx, проверив, что сообщение
+// не изменилось и подпись действительна.
+func applySponsorSignature(tx, signed *solana.Transaction, payer solana.PublicKey) error {
+	msg, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("encode message: %w", err)
+	}
+	signedMsg, err := signed.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("encode sponsored message: %w", err)
+	}
+	if !bytes.Equal(msg, signedMsg) {
+		return fmt.Errorf("sponsor modified the transaction")
+	}
+	if len(signed.Signatures) == 0 || !signed.Signatures[0].Verify(payer, msg) {
+		return fmt.Errorf("sponsor returned an invalid fee payer signature")
+	}
+	tx.Signatures[0] = signed.Signatures[0]
+	return nil
+}
+
+func (s *HTTPFeeSponsor) call(ctx context.Context, method string, in, out any) error {
+	var body *bytes.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshal sponsor request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.url, body)
+	if err != nil {
+		return fmt.Errorf("create sponsor request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call fee sponsor: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("fee sponsor returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode sponsor response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sponsorServer подписывает транзакции ключом payer; tamper меняет сообщение перед подписью.
+func sponsorServer(t *testing.T, payer solana.PrivateKey, tamper bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(map[string]string{"fee_payer": payer.PublicKey().String()})
+			return
+		}
+		var req struct {
+			Transaction string `json:"transaction"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		tx, err := solana.TransactionFromBase64(req.Transaction)
+		require.NoError(t, err)
+		if tamper {
+			tx.Message.RecentBlockhash = solana.Hash{9}
+		}
+		_, err = tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+			if key.Equals(payer.PublicKey()) {
+				return &payer
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]string{"transaction": tx.MustToBase64()})
+	}))
+}
+
+func sponsoredTransfer(t *testing.T, payer solana.PublicKey, owner solana.PrivateKey) *solana.Transaction {
+	ix := system.NewTransferInstruction(1_000, owner.PublicKey(), solana.NewWallet().PublicKey()).Build()
+	tx, err := solana.NewTransaction([]solana.Instruction{ix}, solana.Hash{1}, solana.TransactionPayer(payer))
+	require.NoError(t, err)
+	_, err = tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(owner.PublicKey()) {
+			return &owner
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestHTTPFeeSponsor(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	owner := solana.NewWallet().PrivateKey
+	srv := sponsorServer(t, payer, false)
+	defer srv.Close()
+
+	sponsor, err := NewHTTPFeeSponsor(context.Background(), srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, payer.PublicKey(), sponsor.PublicKey())
+
+	tx := sponsoredTransfer(t, sponsor.PublicKey(), owner)
+	require.NoError(t, sponsor.SignAsPayer(context.Background(), tx))
+	assert.NoError(t, tx.VerifySignatures())
+}
+
+func TestHTTPFeeSponsor_RejectsModifiedTransaction(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	srv := sponsorServer(t, payer, true)
+	defer srv.Close()
+
+	sponsor, err := NewHTTPFeeSponsor(context.Background(), srv.URL)
+	require.NoError(t, err)
+
+	tx := sponsoredTransfer(t, sponsor.PublicKey(), solana.NewWallet().PrivateKey)
+	assert.ErrorContains(t, sponsor.SignAsPayer(context.Background(), tx), "modified")
+	assert.True(t, tx.Signatures[0].IsZero())
+}
@@ -0,0 +1,381 @@
+// internal/blockchain/geyser.go
+package blockchain
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"golang.org/x/net/http2"
+)
+
+// geyserSubscribePath — метод Subscribe сервиса geyser.Geyser (Yellowstone gRPC).
+const geyserSubscribePath = "/geyser.Geyser/Subscribe"
+
+// geyserFilter — имя фильтра подписки; сервер повторяет его в каждом обновлении.
+const geyserFilter = "solana-bot"
+
+// geyserMaxMessage — предел размера одного сообщения потока, как и у WebSocket.
+const geyserMaxMessage = wsMaxMessage
+
+// Номера полей geyser.proto и solana-storage.proto, которые читает и пишет клиент.
+const (
+	// SubscribeRequest
+	reqAccounts     = 1
+	reqTransactions = 3
+	reqCommitment   = 6
+	reqPing         = 9
+
+	// SubscribeRequestFilterAccounts
+	accountFilterAccount = 2
+	// SubscribeRequestFilterTransactions
+	txFilterVote    = 1
+	txFilterFailed  = 2
+	txFilterInclude = 3
+
+	// SubscribeUpdate
+	updateAccount     = 2
+	updateTransaction = 4
+	updatePing        = 6
+
+	// SubscribeUpdateAccount и SubscribeUpdateAccountInfo
+	accountUpdateInfo     = 1
+	accountUpdateSlot     = 2
+	accountInfoPubkey     = 1
+	accountInfoLamports   = 2
+	accountInfoOwner      = 3
+	accountInfoData       = 6
+	transactionUpdateInfo = 1
+	transactionUpdateSlot = 2
+
+	// SubscribeUpdateTransactionInfo и TransactionStatusMeta
+	txInfoSignature = 1
+	txInfoMeta      = 4
+	txMetaErr       = 1
+	txMetaLogs      = 6
+)
+
+// errGeyserClosed возвращается, когда сервер штатно завершил поток подписки.
+var errGeyserClosed = errors.New("geyser stream closed by server")
+
+// GeyserClient — клиент Yellowstone gRPC: обновления аккаунтов и транзакций приходят
+// потоком из Geyser-плагина валидатора, заметно быстрее уведомлений WebSocket RPC.
+// gRPC поверх HTTP/2 реализован вручную, как WebSocket в ws.go: из proto нужны лишь
+// несколько сообщений подписки.
+type GeyserClient struct {
+	endpoint string // https://host:port; http:// — HTTP/2 без TLS
+	token    string // Заголовок x-token (пусто — без авторизации)
+	http     *http.Client
+}
+
+// NewGeyserClient создает клиента для endpoint с токеном доступа token. Соединение
+// открывается при подписке.
+func NewGeyserClient(endpoint, token string) (*GeyserClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid geyser endpoint: %w", err)
+	}
+	transport := &http2.Transport{
+		// Пинг HTTP/2 обнаруживает оборванное соединение, по которому сервер молчит
+		ReadIdleTimeout: 15 * time.Second,
+		PingTimeout:     10 * time.Second,
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	default:
+		return nil, fmt.Errorf("geyser endpoint must start with https:// or http://")
+	}
+	return &GeyserClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		token:    token,
+		http:     &http.Client{Transport: transport},
+	}, nil
+}
+
+// AccountUpdate — новое состояние аккаунта из подписки Geyser.
+type AccountUpdate struct {
+	Slot     uint64
+	Pubkey   solana.PublicKey
+	Owner    solana.PublicKey
+	Lamports uint64
+	Data     []byte
+}
+
+// SubscribeAccounts подписывается на изменения аккаунтов accounts и передает их
+// onAccount. Возвращается при отмене ctx (nil) или обрыве потока; переподключение —
+// забота вызывающего.
+func (g *GeyserClient) SubscribeAccounts(ctx context.Context, accounts []solana.PublicKey, commitment rpc.CommitmentType, onAccount func(AccountUpdate)) error {
+	if len(accounts) == 0 {
+		// Пустой фильтр Geyser понимает как «все аккаунты сети»
+		return errors.New("geyser: no accounts to subscribe to")
+	}
+	var filter []byte
+	for _, a := range accounts {
+		filter = protoAppendBytes(filter, accountFilterAccount, []byte(a.String()))
+	}
+	request := protoAppendBytes(nil, reqAccounts, geyserFilterEntry(filter))
+	request = protoAppendVarint(request, reqCommitment, geyserCommitment(commitment))
+
+	return g.subscribe(ctx, request, func(update []protoField) {
+		f, ok := protoFind(update, updateAccount)
+		if !ok {
+			return
+		}
+		if ev, ok := parseAccountUpdate(f.bytes); ok {
+			onAccount(ev)
+		}
+	})
+}
+
+// SubscribeTransactions подписывается на успешные транзакции, упоминающие mention, и
+// передает их логи onLogs — так же, как SubscribeLogs через WebSocket. Возвращается при
+// отмене ctx (nil) или обрыве потока; переподключение — забота вызывающего.
+func (g *GeyserClient) SubscribeTransactions(ctx context.Context, mention solana.PublicKey, commitment rpc.CommitmentType, onLogs func(LogsEvent)) error {
+	filter := protoAppendBool(nil, txFilterVote, false)
+	filter = protoAppendBool(filter, txFilterFailed, false)
+	filter = protoAppendBytes(filter, txFilterInclude, []byte(mention.String()))
+	request := protoAppendBytes(nil, reqTransactions, geyserFilterEntry(filter))
+	request = protoAppendVarint(request, reqCommitment, geyserCommitment(commitment))
+
+	return g.subscribe(ctx, request, func(update []protoField) {
+		f, ok := protoFind(update, updateTransaction)
+		if !ok {
+			return
+		}
+		if ev, ok := parseTransactionUpdate(f.bytes); ok {
+			onLogs(ev)
+		}
+	})
+}
+
+// subscribe открывает поток Subscribe, отправляет request и передает каждое
+// обновление onUpdate, отвечая на ping сервера.
+func (g *GeyserClient) subscribe(ctx context.Context, request []byte, onUpdate func([]protoField)) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	body, pipe := io.Pipe()
+	defer pipe.Close()
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodPost, g.endpoint+geyserSubscribePath, body)
+	if err != nil {
+		return fmt.Errorf("geyser request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	if g.token != "" {
+		req.Header.Set("x-token", g.token)
+	}
+
+	// Do ждет заголовков ответа, а сервер может отправить их только после запроса
+	// подписки, поэтому запрос пишется параллельно
+	var writeMu sync.Mutex
+	send := func(msg []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, err := pipe.Write(grpcFrame(msg))
+		return err
+	}
+	go func() { _ = send(request) }()
+
+	resp, err := g.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("connect geyser: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("geyser: server returned status %d", resp.StatusCode)
+	}
+	if err := grpcStatus(resp.Header); err != nil {
+		return err // Ответ из одних заголовков: подписка отклонена сразу
+	}
+
+	br := bufio.NewReader(resp.Body)
+	for {
+		msg, err := readGRPCMessage(br)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				if err := grpcStatus(resp.Trailer); err != nil {
+					return err
+				}
+				return errGeyserClosed
+			}
+			return fmt.Errorf("read geyser stream: %w", err)
+		}
+		update, err := protoFields(msg)
+		if err != nil {
+			continue
+		}
+		if _, ok := protoFind(update, updatePing); ok {
+			// Без ответа балансировщики провайдеров закрывают «молчащий» поток
+			if err := send(protoAppendBytes(nil, reqPing, protoAppendVarint(nil, 1, 1))); err != nil {
+				return fmt.Errorf("geyser ping: %w", err)
+			}
+			continue
+		}
+		onUpdate(update)
+	}
+}
+
+// geyserFilterEntry кодирует элемент map<string, filter> запроса с именем geyserFilter.
+func geyserFilterEntry(filter []byte) []byte {
+	entry := protoAppendBytes(nil, 1, []byte(geyserFilter))
+	return protoAppendBytes(entry, 2, filter)
+}
+
+// geyserCommitment переводит commitment RPC в CommitmentLevel Geyser.
+func geyserCommitment(commitment rpc.CommitmentType) uint64 {
+	switch commitment {
+	case rpc.CommitmentConfirmed:
+		return 1
+	case rpc.CommitmentFinalized:
+		return 2
+	default:
+		return 0 // processed
+	}
+}
+
+// parseAccountUpdate разбирает SubscribeUpdateAccount.
+func parseAccountUpdate(data []byte) (AccountUpdate, bool) {
+	fields, err := protoFields(data)
+	if err != nil {
+		return AccountUpdate{}, false
+	}
+	var ev AccountUpdate
+	if f, ok := protoFind(fields, accountUpdateSlot); ok {
+		ev.Slot = f.varint
+	}
+	f, ok := protoFind(fields, accountUpdateInfo)
+	if !ok {
+		return AccountUpdate{}, false
+	}
+	info, err := protoFields(f.bytes)
+	if err != nil {
+		return AccountUpdate{}, false
+	}
+	for _, f := range info {
+		switch f.num {
+		case accountInfoPubkey:
+			if len(f.bytes) != solana.PublicKeyLength {
+				return AccountUpdate{}, false
+			}
+			ev.Pubkey = solana.PublicKeyFromBytes(f.bytes)
+		case accountInfoOwner:
+			if len(f.bytes) == solana.PublicKeyLength {
+				ev.Owner = solana.PublicKeyFromBytes(f.bytes)
+			}
+		case accountInfoLamports:
+			ev.Lamports = f.varint
+		case accountInfoData:
+			ev.Data = f.bytes
+		}
+	}
+	return ev, !ev.Pubkey.IsZero()
+}
+
+// parseTransactionUpdate разбирает SubscribeUpdateTransaction в LogsEvent.
+func parseTransactionUpdate(data []byte) (LogsEvent, bool) {
+	fields, err := protoFields(data)
+	if err != nil {
+		return LogsEvent{}, false
+	}
+	var ev LogsEvent
+	if f, ok := protoFind(fields, transactionUpdateSlot); ok {
+		ev.Slot = f.varint
+	}
+	f, ok := protoFind(fields, transactionUpdateInfo)
+	if !ok {
+		return LogsEvent{}, false
+	}
+	info, err := protoFields(f.bytes)
+	if err != nil {
+		return LogsEvent{}, false
+	}
+	sig, ok := protoFind(info, txInfoSignature)
+	if !ok || len(sig.bytes) != len(ev.Signature) {
+		return LogsEvent{}, false
+	}
+	copy(ev.Signature[:], sig.bytes)
+
+	if f, ok := protoFind(info, txInfoMeta); ok {
+		meta, err := protoFields(f.bytes)
+		if err != nil {
+			return LogsEvent{}, false
+		}
+		for _, f := range meta {
+			switch f.num {
+			case txMetaErr:
+				ev.Err = f.bytes // Ошибка в bincode, как ее хранит узел
+			case txMetaLogs:
+				ev.Logs = append(ev.Logs, string(f.bytes))
+			}
+		}
+	}
+	return ev, true
+}
+
+// grpcFrame оборачивает сообщение в кадр gRPC: флаг сжатия и длина.
+func grpcFrame(msg []byte) []byte {
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	return append(frame, msg...)
+}
+
+// readGRPCMessage читает следующее сообщение потока.
+func readGRPCMessage(r io.Reader) ([]byte, error) {
+	var head [5]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	if head[0] != 0 {
+		return nil, errors.New("compressed grpc messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(head[1:])
+	if size > geyserMaxMessage {
+		return nil, fmt.Errorf("grpc message exceeds %d bytes", geyserMaxMessage)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
+// grpcStatus возвращает ошибку из grpc-status заголовков или трейлеров ответа.
+func grpcStatus(h http.Header) error {
+	status := h.Get("grpc-status")
+	if status == "" || status == "0" {
+		return nil
+	}
+	message, err := url.PathUnescape(h.Get("grpc-message"))
+	if err != nil {
+		message = h.Get("grpc-message")
+	}
+	return fmt.Errorf("geyser: %s (grpc status %s)", message, status)
+}
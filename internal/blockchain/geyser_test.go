@@ -0,0 +1,124 @@
+package blockchain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// geyserServer поднимает HTTP/2 сервер с обработчиком потока Subscribe.
+func geyserServer(t *testing.T, handler http.HandlerFunc) *GeyserClient {
+	srv := httptest.NewUnstartedServer(handler)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	g, err := NewGeyserClient(srv.URL, "secret")
+	require.NoError(t, err)
+	g.http = srv.Client()
+	return g
+}
+
+// grpcWrite отправляет клиенту одно сообщение потока.
+func grpcWrite(t *testing.T, w http.ResponseWriter, msg []byte) {
+	_, err := w.Write(grpcFrame(msg))
+	require.NoError(t, err)
+	w.(http.Flusher).Flush()
+}
+
+func TestGeyserSubscribeTransactions(t *testing.T) {
+	mention := solana.NewWallet().PublicKey()
+	sig := solana.Signature{1, 2, 3}
+
+	g := geyserServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, geyserSubscribePath, r.URL.Path)
+		assert.Equal(t, "application/grpc", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("x-token"))
+
+		msg, err := readGRPCMessage(r.Body)
+		require.NoError(t, err)
+		req, err := protoFields(msg)
+		require.NoError(t, err)
+		commitment, _ := protoFind(req, reqCommitment)
+		assert.Equal(t, uint64(1), commitment.varint)
+		entry, ok := protoFind(req, reqTransactions)
+		require.True(t, ok)
+		kv, err := protoFields(entry.bytes)
+		require.NoError(t, err)
+		filter, _ := protoFind(kv, 2)
+		fields, err := protoFields(filter.bytes)
+		require.NoError(t, err)
+		include, _ := protoFind(fields, txFilterInclude)
+		assert.Equal(t, mention.String(), string(include.bytes))
+		failed, ok := protoFind(fields, txFilterFailed)
+		assert.True(t, ok && failed.varint == 0, "failed transactions are filtered on the server")
+
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "grpc-status")
+		grpcWrite(t, w, protoAppendBytes(nil, updatePing, nil))
+		msg, err = readGRPCMessage(r.Body)
+		require.NoError(t, err)
+		pong, err := protoFields(msg)
+		require.NoError(t, err)
+		_, ok = protoFind(pong, reqPing)
+		assert.True(t, ok, "the client answers server pings")
+
+		meta := protoAppendBytes(nil, txMetaLogs, []byte("Program log: Instruction: Create"))
+		meta = protoAppendBytes(meta, txMetaLogs, []byte("Program log: done"))
+		info := protoAppendBytes(nil, txInfoSignature, sig[:])
+		info = protoAppendBytes(info, txInfoMeta, meta)
+		tx := protoAppendBytes(nil, transactionUpdateInfo, info)
+		tx = protoAppendVarint(tx, transactionUpdateSlot, 42)
+		update := protoAppendBytes(nil, 1, []byte(geyserFilter))
+		grpcWrite(t, w, protoAppendBytes(update, updateTransaction, tx))
+		w.Header().Set("grpc-status", "0")
+	})
+
+	var got []LogsEvent
+	err := g.SubscribeTransactions(context.Background(), mention, rpc.CommitmentConfirmed, func(ev LogsEvent) {
+		got = append(got, ev)
+	})
+	assert.ErrorIs(t, err, errGeyserClosed)
+	require.Len(t, got, 1)
+	assert.Equal(t, uint64(42), got[0].Slot)
+	assert.Equal(t, sig, got[0].Signature)
+	assert.Nil(t, got[0].Err)
+	assert.Equal(t, []string{"Program log: Instruction: Create", "Program log: done"}, got[0].Logs)
+}
+
+func TestGeyserSubscribeRejected(t *testing.T) {
+	g := geyserServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("grpc-status", "16")
+		w.Header().Set("grpc-message", "invalid%20x-token")
+	})
+
+	err := g.SubscribeAccounts(context.Background(), []solana.PublicKey{solana.NewWallet().PublicKey()}, rpc.CommitmentProcessed, func(AccountUpdate) {
+		t.Fatal("no updates expected")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid x-token")
+}
+
+func TestParseAccountUpdate(t *testing.T) {
+	key, owner := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	info := protoAppendBytes(nil, accountInfoPubkey, key[:])
+	info = protoAppendVarint(info, accountInfoLamports, 7)
+	info = protoAppendBytes(info, accountInfoOwner, owner[:])
+	info = protoAppendBytes(info, accountInfoData, []byte{9, 8})
+	update := protoAppendBytes(nil, accountUpdateInfo, info)
+	update = protoAppendVarint(update, accountUpdateSlot, 99)
+
+	ev, ok := parseAccountUpdate(update)
+	require.True(t, ok)
+	assert.Equal(t, AccountUpdate{Slot: 99, Pubkey: key, Owner: owner, Lamports: 7, Data: []byte{9, 8}}, ev)
+
+	_, ok = parseAccountUpdate(protoAppendVarint(nil, accountUpdateSlot, 1))
+	assert.False(t, ok, "an update without the account is skipped")
+}
@@ -0,0 +1,63 @@
+// internal/blockchain/holders.go
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// tokenAccountOwnerOffset — смещение владельца в данных токен-аккаунта.
+const tokenAccountOwnerOffset = 32
+
+// TokenHolder — один из крупнейших токен-аккаунтов mint.
+type TokenHolder struct {
+	Account solana.PublicKey
+	Owner   solana.PublicKey // Кошелек или программа (PDA), которой принадлежит аккаунт
+	Amount  uint64           // Баланс в raw единицах
+}
+
+// IsProgramOwned сообщает, принадлежит ли аккаунт программному адресу (PDA): пулу,
+// bonding curve или локеру, а не кошельку с приватным ключом.
+func (h TokenHolder) IsProgramOwned() bool {
+	return !solana.IsOnCurve(h.Owner[:])
+}
+
+// GetLargestHolders возвращает до 20 крупнейших токен-аккаунтов mint вместе с их владельцами.
+func (c *Client) GetLargestHolders(ctx context.Context, mint solana.PublicKey) ([]TokenHolder, error) {
+	result, err := c.rpc.GetTokenLargestAccounts(ctx, mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, fmt.Errorf("get largest token accounts: %w", err)
+	}
+	if result == nil || len(result.Value) == 0 {
+		return nil, nil
+	}
+
+	holders := make([]TokenHolder, 0, len(result.Value))
+	keys := make([]solana.PublicKey, 0, len(result.Value))
+	for _, v := range result.Value {
+		amount, err := strconv.ParseUint(v.Amount, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance %q of %s: %w", v.Amount, v.Address, err)
+		}
+		holders = append(holders, TokenHolder{Account: v.Address, Amount: amount})
+		keys = append(keys, v.Address)
+	}
+
+	accounts, err := c.GetMultipleAccounts(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("get token account owners: %w", err)
+	}
+	for i := range holders {
+		if i >= len(accounts.Value) || accounts.Value[i] == nil {
+			continue
+		}
+		if data := accounts.Value[i].Data.GetBinary(); len(data) >= tokenAccountOwnerOffset+32 {
+			copy(holders[i].Owner[:], data[tokenAccountOwnerOffset:tokenAccountOwnerOffset+32])
+		}
+	}
+	return holders, nil
+}
@@ -0,0 +1,115 @@
+// internal/blockchain/logs.go
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// LogsEvent — уведомление logsSubscribe: логи одной транзакции.
+type LogsEvent struct {
+	Slot      uint64
+	Signature solana.Signature
+	Err       interface{} // Ошибка транзакции (nil — успешна)
+	Logs      []string
+}
+
+// SubscribeLogs подписывается через WebSocket-адрес wsURL на логи транзакций,
+// упоминающих mention, и передает их onLogs. Возвращается при отмене ctx (nil)
+// или обрыве соединения; переподключение — забота вызывающего.
+func SubscribeLogs(ctx context.Context, wsURL string, mention solana.PublicKey, commitment rpc.CommitmentType, onLogs func(LogsEvent)) error {
+	conn, err := dialWebSocket(ctx, wsURL)
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	if commitment == "" {
+		commitment = rpc.CommitmentProcessed
+	}
+	request, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "logsSubscribe",
+		"params": []interface{}{
+			map[string]interface{}{"mentions": []string{mention.String()}},
+			map[string]interface{}{"commitment": commitment},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal logsSubscribe: %w", err)
+	}
+	if err := conn.WriteMessage(request); err != nil {
+		return err
+	}
+
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("read logs subscription: %w", err)
+		}
+
+		var msg struct {
+			ID     int             `json:"id"`
+			Method string          `json:"method"`
+			Error  *rpcError       `json:"error"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("logsSubscribe: %s", msg.Error.Message)
+		}
+		if msg.Method != "logsNotification" {
+			continue // Подтверждение подписки
+		}
+
+		var params struct {
+			Result struct {
+				Context struct {
+					Slot uint64 `json:"slot"`
+				} `json:"context"`
+				Value struct {
+					Signature string      `json:"signature"`
+					Err       interface{} `json:"err"`
+					Logs      []string    `json:"logs"`
+				} `json:"value"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			continue
+		}
+		sig, err := solana.SignatureFromBase58(params.Result.Value.Signature)
+		if err != nil {
+			continue
+		}
+		onLogs(LogsEvent{
+			Slot:      params.Result.Context.Slot,
+			Signature: sig,
+			Err:       params.Result.Value.Err,
+			Logs:      params.Result.Value.Logs,
+		})
+	}
+}
+
+// rpcError — ошибка JSON-RPC в ответе сервера.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,130 @@
+package blockchain
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeLogs(t *testing.T) {
+	mention := solana.NewWallet().PublicKey()
+	sig := solana.Signature{1, 2, 3}
+	manyLogs := make([]string, 200) // Уведомление длиннее 64 КБ — расширенная длина кадра
+	for i := range manyLogs {
+		manyLogs[i] = "Program log: " + strings.Repeat("x", 400)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "websocket", r.Header.Get("Upgrade"))
+		assert.Equal(t, "key", r.URL.Query().Get("api-key"))
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + wsAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+
+		server := &wsConn{conn: conn, br: bufio.NewReader(rw)}
+		data, err := server.ReadMessage()
+		require.NoError(t, err)
+		var req struct {
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(data, &req))
+		assert.Equal(t, "logsSubscribe", req.Method)
+		assert.Equal(t, map[string]interface{}{"mentions": []interface{}{mention.String()}}, req.Params[0])
+
+		require.NoError(t, server.WriteMessage([]byte(`{"jsonrpc":"2.0","result":7,"id":1}`)))
+		require.NoError(t, server.writeFrame(wsPing, []byte("hi")))
+		for _, logs := range [][]string{{"Program log: Instruction: Create"}, manyLogs} {
+			note, _ := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "logsNotification",
+				"params": map[string]interface{}{"result": map[string]interface{}{
+					"context": map[string]interface{}{"slot": 42},
+					"value":   map[string]interface{}{"signature": sig.String(), "err": nil, "logs": logs},
+				}},
+			})
+			require.NoError(t, server.WriteMessage(note))
+		}
+		_, err = server.ReadMessage() // Pong пропускается, затем клиент закрывает соединение
+		assert.ErrorIs(t, err, errWSClosed)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var events []LogsEvent
+	err := SubscribeLogs(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/?api-key=key", mention, "", func(ev LogsEvent) {
+		events = append(events, ev)
+		if len(events) == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, LogsEvent{Slot: 42, Signature: sig, Logs: []string{"Program log: Instruction: Create"}}, events[0])
+	assert.Equal(t, manyLogs, events[1].Logs)
+}
+
+func TestSubscribeLogs_HalfOpenConnection(t *testing.T) {
+	pingInterval, idleTimeout := wsPingInterval, wsIdleTimeout
+	wsPingInterval, wsIdleTimeout = 50*time.Millisecond, 300*time.Millisecond
+	t.Cleanup(func() { wsPingInterval, wsIdleTimeout = pingInterval, idleTimeout })
+
+	pinged := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + wsAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = rw.Flush()
+
+		server := &wsConn{conn: conn, br: bufio.NewReader(rw)}
+		_, err = server.ReadMessage()
+		require.NoError(t, err)
+		require.NoError(t, server.WriteMessage([]byte(`{"jsonrpc":"2.0","result":7,"id":1}`)))
+
+		// Сервер «пропал»: кадры клиента читаются, но на ping никто не отвечает
+		for {
+			_, opcode, _, err := server.readFrame()
+			if err != nil {
+				return
+			}
+			if opcode == wsPing {
+				select {
+				case <-pinged:
+				default:
+					close(pinged)
+				}
+			}
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	started := time.Now()
+	err := SubscribeLogs(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), solana.NewWallet().PublicKey(), "", func(LogsEvent) {
+		t.Fatal("no notifications expected")
+	})
+	require.Error(t, err, "a silent connection ends the subscription so the caller reconnects")
+	assert.Contains(t, err.Error(), "idle")
+	assert.Less(t, time.Since(started), 2*time.Second)
+	select {
+	case <-pinged:
+	default:
+		t.Fatal("the client pings an idle connection")
+	}
+}
@@ -0,0 +1,119 @@
+// internal/blockchain/lookup_table.go
+package blockchain
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/gagliardetto/solana-go"
+	addresslookuptable "github.com/gagliardetto/solana-go/programs/address-lookup-table"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// AddressLookupTableProgramID — программа таблиц адресов (ALT) для v0 транзакций.
+var AddressLookupTableProgramID = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+
+// Индексы инструкций программы ALT (bincode enum u32).
+const (
+	lookupTableInstructionCreate = 0
+	lookupTableInstructionExtend = 2
+)
+
+// LookupTable — активная таблица адресов.
+type LookupTable struct {
+	Address          solana.PublicKey
+	Authority        *solana.PublicKey
+	Addresses        solana.PublicKeySlice
+	LastExtendedSlot uint64
+}
+
+// Contains сообщает, есть ли адрес в таблице.
+func (t *LookupTable) Contains(key solana.PublicKey) bool {
+	return t != nil && t.Addresses.Contains(key)
+}
+
+// GetSlot возвращает текущий слот с указанным уровнем подтверждения.
+func (c *Client) GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	slot, err := c.rpc.GetSlot(ctx, commitment)
+	if err != nil {
+		return 0, fmt.Errorf("get slot: %w", err)
+	}
+	return slot, nil
+}
+
+// GetLookupTable загружает таблицу адресов. Деактивированные таблицы считаются недоступными.
+func (c *Client) GetLookupTable(ctx context.Context, address solana.PublicKey) (*LookupTable, error) {
+	info, err := c.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("get lookup table: %w", err)
+	}
+	if info == nil || info.Value == nil {
+		return nil, fmt.Errorf("lookup table %s not found", address)
+	}
+	if !info.Value.Owner.Equals(AddressLookupTableProgramID) {
+		return nil, fmt.Errorf("account %s is not an address lookup table", address)
+	}
+
+	state, err := addresslookuptable.DecodeAddressLookupTableState(info.Value.Data.GetBinary())
+	if err != nil {
+		return nil, fmt.Errorf("decode lookup table: %w", err)
+	}
+	if state.DeactivationSlot != math.MaxUint64 {
+		return nil, fmt.Errorf("lookup table %s is deactivated", address)
+	}
+
+	return &LookupTable{
+		Address:          address,
+		Authority:        state.Authority,
+		Addresses:        state.Addresses,
+		LastExtendedSlot: state.LastExtendedSlot,
+	}, nil
+}
+
+// NewCreateLookupTableInstruction создает инструкцию создания таблицы адресов.
+// recentSlot должен быть недавним подтвержденным слотом — от него зависит адрес таблицы.
+func NewCreateLookupTableInstruction(authority, payer solana.PublicKey, recentSlot uint64) (solana.Instruction, solana.PublicKey, error) {
+	slotSeed := make([]byte, 8)
+	binary.LittleEndian.PutUint64(slotSeed, recentSlot)
+
+	table, bump, err := solana.FindProgramAddress(
+		[][]byte{authority.Bytes(), slotSeed},
+		AddressLookupTableProgramID,
+	)
+	if err != nil {
+		return nil, solana.PublicKey{}, fmt.Errorf("derive lookup table address: %w", err)
+	}
+
+	data := make([]byte, 4+8+1)
+	binary.LittleEndian.PutUint32(data[0:4], lookupTableInstructionCreate)
+	binary.LittleEndian.PutUint64(data[4:12], recentSlot)
+	data[12] = bump
+
+	accounts := solana.AccountMetaSlice{
+		solana.NewAccountMeta(table, true, false),
+		solana.NewAccountMeta(authority, false, true),
+		solana.NewAccountMeta(payer, true, true),
+		solana.NewAccountMeta(solana.SystemProgramID, false, false),
+	}
+	return solana.NewInstruction(AddressLookupTableProgramID, accounts, data), table, nil
+}
+
+// NewExtendLookupTableInstruction создает инструкцию добавления адресов в таблицу.
+func NewExtendLookupTableInstruction(table, authority, payer solana.PublicKey, addresses []solana.PublicKey) solana.Instruction {
+	data := make([]byte, 4+8, 4+8+32*len(addresses))
+	binary.LittleEndian.PutUint32(data[0:4], lookupTableInstructionExtend)
+	binary.LittleEndian.PutUint64(data[4:12], uint64(len(addresses)))
+	for _, addr := range addresses {
+		data = append(data, addr.Bytes()...)
+	}
+
+	accounts := solana.AccountMetaSlice{
+		solana.NewAccountMeta(table, true, false),
+		solana.NewAccountMeta(authority, false, true),
+		solana.NewAccountMeta(payer, true, true),
+		solana.NewAccountMeta(solana.SystemProgramID, false, false),
+	}
+	return solana.NewInstruction(AddressLookupTableProgramID, accounts, data)
+}
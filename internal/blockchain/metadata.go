@@ -0,0 +1,69 @@
+// internal/blockchain/metadata.go
+package blockchain
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// MetaplexProgramID — программа Metaplex Token Metadata.
+var MetaplexProgramID = solana.MustPublicKeyFromBase58("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
+
+// Начало аккаунта метаданных: key (u8), update authority и mint, затем строки name, symbol, uri
+const metaplexStringsOffset = 1 + 32 + 32
+
+// MetaplexMetadata — название токена из аккаунта метаданных Metaplex.
+type MetaplexMetadata struct {
+	Name   string
+	Symbol string
+	URI    string // JSON с описанием и картинкой токена
+}
+
+// MetadataAddress возвращает PDA аккаунта метаданных Metaplex для mint.
+func MetadataAddress(mint solana.PublicKey) (solana.PublicKey, error) {
+	addr, _, err := solana.FindProgramAddress(
+		[][]byte{[]byte("metadata"), MetaplexProgramID[:], mint[:]},
+		MetaplexProgramID,
+	)
+	return addr, err
+}
+
+// ParseMetaplexMetadata разбирает аккаунт метаданных Metaplex. Строки в аккаунте
+// дополнены нулями до фиксированной длины, они обрезаются.
+func ParseMetaplexMetadata(data []byte) (*MetaplexMetadata, error) {
+	offset := metaplexStringsOffset
+	fields := make([]string, 3)
+	for i := range fields {
+		if len(data) < offset+4 {
+			return nil, fmt.Errorf("metadata account too short (%d bytes)", len(data))
+		}
+		n := int(binary.LittleEndian.Uint32(data[offset:]))
+		offset += 4
+		if n > len(data)-offset {
+			return nil, fmt.Errorf("metadata string length %d out of range", n)
+		}
+		fields[i] = strings.TrimSpace(strings.TrimRight(string(data[offset:offset+n]), "\x00"))
+		offset += n
+	}
+	return &MetaplexMetadata{Name: fields[0], Symbol: fields[1], URI: fields[2]}, nil
+}
+
+// GetMetaplexMetadata читает метаданные Metaplex токена.
+func (c *Client) GetMetaplexMetadata(ctx context.Context, mint solana.PublicKey) (*MetaplexMetadata, error) {
+	addr, err := MetadataAddress(mint)
+	if err != nil {
+		return nil, fmt.Errorf("derive metadata address: %w", err)
+	}
+	info, err := c.GetCachedAccountInfo(ctx, addr, MintCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("get metadata account: %w", err)
+	}
+	if info == nil || info.Value == nil {
+		return nil, fmt.Errorf("metadata account for %s not found", mint)
+	}
+	return ParseMetaplexMetadata(info.Value.Data.GetBinary())
+}
@@ -0,0 +1,30 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetaplexMetadata(t *testing.T) {
+	data := make([]byte, metaplexStringsOffset)
+	data[0] = 4 // Key::MetadataV1
+	for _, s := range []struct {
+		value string
+		size  int
+	}{{"Bonk", 32}, {"BONK", 10}, {"https://example.com/bonk.json", 200}} {
+		field := make([]byte, 4+s.size)
+		binary.LittleEndian.PutUint32(field, uint32(s.size))
+		copy(field[4:], s.value)
+		data = append(data, field...)
+	}
+
+	meta, err := ParseMetaplexMetadata(data)
+	require.NoError(t, err)
+	assert.Equal(t, &MetaplexMetadata{Name: "Bonk", Symbol: "BONK", URI: "https://example.com/bonk.json"}, meta)
+
+	_, err = ParseMetaplexMetadata(data[:metaplexStringsOffset+10])
+	assert.Error(t, err)
+}
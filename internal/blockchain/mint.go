@@ -0,0 +1,82 @@
+// internal/blockchain/mint.go
+package blockchain
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	// Базовая раскладка SPL mint (у Token-2022 расширения идут после нее)
+	mintLen                = 82
+	mintAuthorityOffset    = 0
+	mintSupplyOffset       = 36
+	mintDecimalsOffset     = 44
+	mintFreezeAuthorityOff = 46
+
+	// Поле state токен-аккаунта: 1 — initialized, 2 — frozen
+	tokenAccountStateOffset = 108
+	tokenAccountStateFrozen = 2
+)
+
+// MintState — изменяемые поля mint-аккаунта, по которым видны типичные rug-сценарии:
+// выпуск новых токенов и заморозка аккаунтов держателей.
+type MintState struct {
+	MintAuthority   *solana.PublicKey // nil — выпуск отключен навсегда
+	FreezeAuthority *solana.PublicKey // nil — аккаунты держателей нельзя заморозить
+	Supply          uint64            // Эмиссия в raw единицах
+	Decimals        uint8
+}
+
+// ParseMintState разбирает данные mint-аккаунта SPL Token или Token-2022.
+func ParseMintState(data []byte) (*MintState, error) {
+	if len(data) < mintLen {
+		return nil, fmt.Errorf("account is not a token mint (%d bytes)", len(data))
+	}
+	return &MintState{
+		MintAuthority:   parseCOptionKey(data[mintAuthorityOffset:]),
+		FreezeAuthority: parseCOptionKey(data[mintFreezeAuthorityOff:]),
+		Supply:          binary.LittleEndian.Uint64(data[mintSupplyOffset : mintSupplyOffset+8]),
+		Decimals:        data[mintDecimalsOffset],
+	}, nil
+}
+
+// parseCOptionKey читает COption<Pubkey>: тег u32 и ключ.
+func parseCOptionKey(data []byte) *solana.PublicKey {
+	if binary.LittleEndian.Uint32(data[:4]) == 0 {
+		return nil
+	}
+	key := solana.PublicKeyFromBytes(data[4:36])
+	return &key
+}
+
+// IsTokenAccountFrozen сообщает, заморожен ли токен-аккаунт по его данным.
+func IsTokenAccountFrozen(data []byte) bool {
+	return len(data) > tokenAccountStateOffset && data[tokenAccountStateOffset] == tokenAccountStateFrozen
+}
+
+// GetMintState читает mint токена и, если задан tokenAccount, проверяет его заморозку
+// тем же запросом.
+func (c *Client) GetMintState(ctx context.Context, mint, tokenAccount solana.PublicKey) (*MintState, bool, error) {
+	keys := []solana.PublicKey{mint}
+	if !tokenAccount.IsZero() {
+		keys = append(keys, tokenAccount)
+	}
+	res, err := c.GetMultipleAccounts(ctx, keys)
+	if err != nil {
+		return nil, false, fmt.Errorf("get mint account: %w", err)
+	}
+	if res == nil || len(res.Value) == 0 || res.Value[0] == nil {
+		return nil, false, fmt.Errorf("mint account %s not found", mint)
+	}
+
+	state, err := ParseMintState(res.Value[0].Data.GetBinary())
+	if err != nil {
+		return nil, false, err
+	}
+	frozen := len(res.Value) > 1 && res.Value[1] != nil && IsTokenAccountFrozen(res.Value[1].Data.GetBinary())
+	return state, frozen, nil
+}
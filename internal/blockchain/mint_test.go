@@ -0,0 +1,39 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMintState(t *testing.T) {
+	freezer := solana.NewWallet().PublicKey()
+	data := make([]byte, mintLen)
+	binary.LittleEndian.PutUint64(data[mintSupplyOffset:], 1_000_000_000)
+	data[mintDecimalsOffset] = 6
+	binary.LittleEndian.PutUint32(data[mintFreezeAuthorityOff:], 1)
+	copy(data[mintFreezeAuthorityOff+4:], freezer[:])
+
+	state, err := ParseMintState(data)
+	require.NoError(t, err)
+	assert.Nil(t, state.MintAuthority)
+	require.NotNil(t, state.FreezeAuthority)
+	assert.Equal(t, freezer, *state.FreezeAuthority)
+	assert.Equal(t, uint64(1_000_000_000), state.Supply)
+	assert.Equal(t, uint8(6), state.Decimals)
+
+	_, err = ParseMintState(data[:40])
+	assert.Error(t, err)
+}
+
+func TestIsTokenAccountFrozen(t *testing.T) {
+	account := make([]byte, 165)
+	account[tokenAccountStateOffset] = 1
+	assert.False(t, IsTokenAccountFrozen(account))
+	account[tokenAccountStateOffset] = tokenAccountStateFrozen
+	assert.True(t, IsTokenAccountFrozen(account))
+	assert.False(t, IsTokenAccountFrozen(nil))
+}
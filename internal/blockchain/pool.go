@@ -0,0 +1,476 @@
+// internal/blockchain/pool.go
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// Режимы выбора эндпоинта пулом.
+const (
+	RoutingFailover = "failover" // Первый здоровый эндпоинт в порядке rpc_list
+	RoutingLatency  = "latency"  // Здоровый эндпоинт с наименьшей задержкой
+)
+
+const (
+	// DefaultFailureCooldown — сколько эндпоинт после сбоя не получает запросов,
+	// если проверка здоровья не вернет его раньше.
+	DefaultFailureCooldown = 30 * time.Second
+	// healthCheckTimeout ограничивает один запрос проверки здоровья.
+	healthCheckTimeout = 5 * time.Second
+	// latencyWeight — вес нового замера в скользящей средней задержки.
+	latencyWeight = 0.2
+)
+
+// Коды ошибок JSON-RPC, которыми узел сообщает, что сам не может ответить.
+const (
+	codeRateLimited   = 429
+	codeNodeUnhealthy = -32005
+)
+
+// PoolOptions — настройки пула RPC-эндпоинтов.
+type PoolOptions struct {
+	Routing         string               // RoutingFailover (по умолчанию) или RoutingLatency
+	FailureCooldown time.Duration        // Пауза эндпоинта после сбоя (0 = DefaultFailureCooldown)
+	RateLimit       RateLimit            // Лимит запросов каждого эндпоинта (нулевой — без лимита)
+	EndpointLimits  map[string]RateLimit // Лимиты отдельных эндпоинтов по URL вместо RateLimit
+}
+
+// RateLimit — token bucket для запросов к эндпоинту.
+type RateLimit struct {
+	PerSecond float64 // Запросов в секунду (0 — без лимита)
+	Burst     int     // Сколько запросов можно отправить подряд (0 — PerSecond с округлением вверх)
+}
+
+// limiter создает token bucket или возвращает nil, если лимита нет.
+func (l RateLimit) limiter() *rate.Limiter {
+	if l.PerSecond <= 0 {
+		return nil
+	}
+	burst := l.Burst
+	if burst <= 0 {
+		burst = int(math.Ceil(l.PerSecond))
+	}
+	return rate.NewLimiter(rate.Limit(l.PerSecond), burst)
+}
+
+// EndpointStatus — состояние эндпоинта пула для логов и проверок.
+type EndpointStatus struct {
+	URL       string
+	Host      string // Хост эндпоинта без пути и ключа API — для логов
+	Healthy   bool
+	Latency   time.Duration // Скользящая средняя задержка ответа (0 — замеров еще нет)
+	Requests  uint64        // Запросов отправлено на эндпоинт
+	Throttled uint64        // Из них ждали свободного слота лимита
+	Waited    time.Duration // Общее время ожидания лимита
+	Queued    int           // Запросов ждет лимита прямо сейчас
+	TaskOnly  bool          // Эндпоинт колонки rpc задач вне rpc_list: в ротации пула не участвует
+}
+
+// poolEndpoint — эндпоинт пула, его здоровье и лимит запросов.
+type poolEndpoint struct {
+	url       string
+	transport rpc.JSONRPCClient
+	latency   time.Duration
+	downUntil time.Time
+
+	limiter   *rate.Limiter // nil — без лимита
+	requests  uint64
+	throttled uint64
+	waited    time.Duration
+	queued    int
+}
+
+// Pool — транспорт JSON-RPC поверх нескольких эндпоинтов: каждый запрос уходит на
+// лучший здоровый эндпоинт, а при сетевой ошибке, 429 или 5xx повторяется на
+// следующем. Ошибки, которые вернул сам узел (нет аккаунта, сбой симуляции),
+// переотправкой не лечатся и возвращаются как есть.
+// Pool реализует rpc.JSONRPCClient, поэтому все методы Client и адаптеры DEX
+// получают отказоустойчивость без изменений.
+type Pool struct {
+	mu        sync.Mutex
+	endpoints []*poolEndpoint
+	pinned    []*poolEndpoint // Эндпоинты задач вне rpc_list, выданные Endpoint
+	opts      PoolOptions
+	logger    *zap.Logger
+	now       func() time.Time
+	active    string // Эндпоинт, ответивший последним, — чтобы логировать переключения
+}
+
+// NewPool создает пул для urls; порядок задает приоритет в режиме failover.
+func NewPool(urls []string, opts PoolOptions, logger *zap.Logger) *Pool {
+	endpoints := make([]*poolEndpoint, 0, len(urls))
+	for _, url := range urls {
+		endpoints = append(endpoints, &poolEndpoint{url: url, transport: rpcTransport{rpc.New(url)}})
+	}
+	return newPool(endpoints, opts, logger)
+}
+
+func newPool(endpoints []*poolEndpoint, opts PoolOptions, logger *zap.Logger) *Pool {
+	if opts.Routing == "" {
+		opts.Routing = RoutingFailover
+	}
+	if opts.FailureCooldown <= 0 {
+		opts.FailureCooldown = DefaultFailureCooldown
+	}
+	for _, ep := range endpoints {
+		ep.limiter = opts.limitFor(ep.url).limiter()
+	}
+	return &Pool{
+		endpoints: endpoints,
+		opts:      opts,
+		logger:    logger.Named("rpc-pool"),
+		now:       time.Now,
+	}
+}
+
+// limitFor возвращает лимит эндпоинта: свой из EndpointLimits или общий RateLimit.
+func (o PoolOptions) limitFor(rpcURL string) RateLimit {
+	if limit, ok := o.EndpointLimits[rpcURL]; ok {
+		return limit
+	}
+	return o.RateLimit
+}
+
+// Endpoint возвращает транспорт одного эндпоинта без переключения на другие — для
+// задач с собственным rpc. Эндпоинт из rpc_list делит с пулом token bucket и счетчики,
+// остальные получают лимит по тем же правилам и попадают в Status.
+func (p *Pool) Endpoint(rpcURL string) rpc.JSONRPCClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, ep := range slices.Concat(p.endpoints, p.pinned) {
+		if ep.url == rpcURL {
+			return endpointTransport{pool: p, ep: ep}
+		}
+	}
+	ep := &poolEndpoint{
+		url:       rpcURL,
+		transport: rpcTransport{rpc.New(rpcURL)},
+		limiter:   p.opts.limitFor(rpcURL).limiter(),
+	}
+	p.pinned = append(p.pinned, ep)
+	return endpointTransport{pool: p, ep: ep}
+}
+
+type endpointObserverKey struct{}
+
+// WithEndpointObserver возвращает контекст, запросы которого через пул сообщают fn метод
+// и хост эндпоинта, который на него ответил, — например, чтобы записать в отчет, через
+// какой RPC ушла транзакция после переключения.
+func WithEndpointObserver(ctx context.Context, fn func(method, host string)) context.Context {
+	return context.WithValue(ctx, endpointObserverKey{}, fn)
+}
+
+// observeEndpoint сообщает наблюдателю из ctx, какой эндпоинт ответил на метод.
+func observeEndpoint(ctx context.Context, method, rpcURL string) {
+	if fn, ok := ctx.Value(endpointObserverKey{}).(func(method, host string)); ok {
+		fn(method, rpcHost(rpcURL))
+	}
+}
+
+// CallForInto реализует rpc.JSONRPCClient.
+func (p *Pool) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	return p.call(ctx, method, func(t rpc.JSONRPCClient) error {
+		return t.CallForInto(ctx, out, method, params)
+	})
+}
+
+// CallWithCallback реализует rpc.JSONRPCClient.
+func (p *Pool) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	return p.call(ctx, method, func(t rpc.JSONRPCClient) error {
+		return t.CallWithCallback(ctx, method, params, callback)
+	})
+}
+
+// CallBatch реализует rpc.JSONRPCClient.
+func (p *Pool) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	var responses jsonrpc.RPCResponses
+	err := p.call(ctx, "batch", func(t rpc.JSONRPCClient) error {
+		var err error
+		responses, err = t.CallBatch(ctx, requests)
+		return err
+	})
+	return responses, err
+}
+
+// call выполняет запрос на эндпоинтах пула по очереди, пока один из них не ответит.
+func (p *Pool) call(ctx context.Context, method string, do func(rpc.JSONRPCClient) error) error {
+	var lastErr error
+	for _, ep := range p.order() {
+		if err := p.throttle(ctx, ep); err != nil {
+			return err
+		}
+		started := p.now()
+		err := do(ep.transport)
+		if ctx.Err() != nil {
+			return err
+		}
+		if err == nil || !isEndpointFailure(ctx, err) {
+			p.markUp(ep, p.now().Sub(started))
+			p.markActive(ep)
+			observeEndpoint(ctx, method, ep.url)
+			return err
+		}
+		lastErr = err
+		p.markDown(ep)
+		p.logger.Warn(fmt.Sprintf("⚠️  RPC %s failed on %s, trying the next endpoint: %v", rpcHost(ep.url), method, err))
+	}
+	if lastErr == nil {
+		return errors.New("rpc pool has no endpoints")
+	}
+	return lastErr
+}
+
+// order возвращает эндпоинты в порядке попыток: здоровые по правилу маршрутизации,
+// затем отдыхающие после сбоя — на случай, если упали все.
+func (p *Pool) order() []*poolEndpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var healthy, down []*poolEndpoint
+	for _, ep := range p.endpoints {
+		if now.Before(ep.downUntil) {
+			down = append(down, ep)
+		} else {
+			healthy = append(healthy, ep)
+		}
+	}
+	if p.opts.Routing == RoutingLatency {
+		// Эндпоинты без замеров идут первыми, чтобы получить замер
+		sort.SliceStable(healthy, func(i, j int) bool { return healthy[i].latency < healthy[j].latency })
+	}
+	// Эндпоинт, исчерпавший лимит, уступает очередь тем, у кого есть свободный слот
+	free := make(map[*poolEndpoint]bool, len(healthy))
+	for _, ep := range healthy {
+		free[ep] = hasCapacity(ep)
+	}
+	sort.SliceStable(healthy, func(i, j int) bool { return free[healthy[i]] && !free[healthy[j]] })
+	sort.SliceStable(down, func(i, j int) bool { return down[i].downUntil.Before(down[j].downUntil) })
+	return append(healthy, down...)
+}
+
+// hasCapacity сообщает, можно ли отправить запрос на эндпоинт без ожидания лимита.
+func hasCapacity(ep *poolEndpoint) bool {
+	return ep.limiter == nil || ep.limiter.Tokens() >= 1
+}
+
+// throttle ждет свободного слота лимита эндпоинта, пока не истечет ctx. Если слот не
+// освободится до дедлайна запроса, ошибка возвращается сразу, без ожидания.
+func (p *Pool) throttle(ctx context.Context, ep *poolEndpoint) error {
+	p.mu.Lock()
+	ep.requests++
+	if ep.limiter == nil || ep.limiter.Allow() {
+		p.mu.Unlock()
+		return nil
+	}
+	ep.throttled++
+	ep.queued++
+	p.mu.Unlock()
+
+	started := time.Now()
+	err := ep.limiter.Wait(ctx)
+
+	p.mu.Lock()
+	ep.queued--
+	ep.waited += time.Since(started)
+	p.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("rpc %s rate limit: %w", rpcHost(ep.url), err)
+	}
+	return nil
+}
+
+// markUp учитывает успешный ответ эндпоинта и его задержку.
+func (p *Pool) markUp(ep *poolEndpoint, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wasDown := !ep.downUntil.IsZero()
+	ep.downUntil = time.Time{}
+	if ep.latency == 0 {
+		ep.latency = latency
+	} else {
+		ep.latency = time.Duration((1-latencyWeight)*float64(ep.latency) + latencyWeight*float64(latency))
+	}
+	if wasDown {
+		p.logger.Info("✅ RPC " + rpcHost(ep.url) + " is healthy again")
+	}
+}
+
+// markActive запоминает эндпоинт, ответивший на запрос, и логирует переключение трафика.
+func (p *Pool) markActive(ep *poolEndpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active == ep.url {
+		return
+	}
+	if p.active != "" {
+		p.logger.Info(fmt.Sprintf("🔀 RPC traffic moved from %s to %s", rpcHost(p.active), rpcHost(ep.url)))
+	}
+	p.active = ep.url
+}
+
+// markDown выводит эндпоинт из ротации на FailureCooldown.
+func (p *Pool) markDown(ep *poolEndpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.downUntil = p.now().Add(p.opts.FailureCooldown)
+}
+
+// Status возвращает состояние эндпоинтов в порядке rpc_list, затем эндпоинты задач.
+func (p *Pool) Status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	status := make([]EndpointStatus, 0, len(p.endpoints)+len(p.pinned))
+	for i, ep := range slices.Concat(p.endpoints, p.pinned) {
+		status = append(status, EndpointStatus{
+			URL:       ep.url,
+			Host:      rpcHost(ep.url),
+			Healthy:   !now.Before(ep.downUntil),
+			Latency:   ep.latency,
+			Requests:  ep.requests,
+			Throttled: ep.throttled,
+			Waited:    ep.waited,
+			Queued:    ep.queued,
+			TaskOnly:  i >= len(p.endpoints),
+		})
+	}
+	return status
+}
+
+// CheckHealth опрашивает все эндпоинты каждые interval до отмены ctx: так задержка
+// известна до первой сделки, а упавший эндпоинт возвращается в ротацию сразу после
+// восстановления, не дожидаясь конца паузы.
+func (p *Pool) CheckHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.checkOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkOnce проверяет каждый эндпоинт запросом getSlot.
+func (p *Pool) checkOnce(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func(ep *poolEndpoint) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			var slot uint64
+			started := p.now()
+			err := ep.transport.CallForInto(checkCtx, &slot, "getSlot", nil)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil && isEndpointFailure(ctx, err) {
+				p.markDown(ep)
+				p.logger.Debug("RPC health check failed for " + rpcHost(ep.url) + ": " + err.Error())
+				return
+			}
+			p.markUp(ep, p.now().Sub(started))
+		}(ep)
+	}
+	wg.Wait()
+}
+
+// isEndpointFailure отличает сбой эндпоинта, который стоит повторить на другом,
+// от ответа узла и от отмены самого запроса.
+func isEndpointFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == codeRateLimited || rpcErr.Code == codeNodeUnhealthy
+	}
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code == http.StatusTooManyRequests || httpErr.Code >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// rpcHost возвращает хост эндпоинта без пути и ключей API для логов.
+func rpcHost(rpcURL string) string {
+	u, err := url.Parse(rpcURL)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
+
+// rpcTransport отдает JSON-RPC транспорт клиента solana-go.
+type rpcTransport struct {
+	client *rpc.Client
+}
+
+func (t rpcTransport) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	return t.client.RPCCallForInto(ctx, out, method, params)
+}
+
+func (t rpcTransport) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	return t.client.RPCCallWithCallback(ctx, method, params, callback)
+}
+
+func (t rpcTransport) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	return t.client.RPCCallBatch(ctx, requests)
+}
+
+// endpointTransport — транспорт одного эндпоинта пула с его лимитом, без переключения.
+type endpointTransport struct {
+	pool *Pool
+	ep   *poolEndpoint
+}
+
+func (t endpointTransport) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	if err := t.pool.throttle(ctx, t.ep); err != nil {
+		return err
+	}
+	observeEndpoint(ctx, method, t.ep.url)
+	return t.ep.transport.CallForInto(ctx, out, method, params)
+}
+
+func (t endpointTransport) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	if err := t.pool.throttle(ctx, t.ep); err != nil {
+		return err
+	}
+	observeEndpoint(ctx, method, t.ep.url)
+	return t.ep.transport.CallWithCallback(ctx, method, params, callback)
+}
+
+func (t endpointTransport) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	if err := t.pool.throttle(ctx, t.ep); err != nil {
+		return nil, err
+	}
+	return t.ep.transport.CallBatch(ctx, requests)
+}
+
+// Гарантируем, что Pool подходит как транспорт rpc.Client.
+var _ rpc.JSONRPCClient = (*Pool)(nil)
@@ -0,0 +1,218 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeTransport отвечает заданной ошибкой и считает запросы.
+type fakeTransport struct {
+	err   error
+	calls int
+}
+
+func (f *fakeTransport) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	f.calls++
+	return f.err
+}
+
+func (f *fakeTransport) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	f.calls++
+	return f.err
+}
+
+func (f *fakeTransport) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	f.calls++
+	return nil, f.err
+}
+
+func testPool(routing string, transports ...*fakeTransport) (*Pool, *time.Time) {
+	endpoints := make([]*poolEndpoint, len(transports))
+	for i, tr := range transports {
+		endpoints[i] = &poolEndpoint{url: "https://rpc" + string(rune('a'+i)) + ".test", transport: tr}
+	}
+	p := newPool(endpoints, PoolOptions{Routing: routing, FailureCooldown: time.Minute}, zap.NewNop())
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+	return p, &now
+}
+
+func TestPool_Failover(t *testing.T) {
+	primary := &fakeTransport{err: errors.New("connection refused")}
+	fallback := &fakeTransport{}
+	p, now := testPool(RoutingFailover, primary, fallback)
+
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, fallback.calls)
+	assert.False(t, p.Status()[0].Healthy)
+
+	// Пока идет пауза, упавший эндпоинт не получает запросов
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.Equal(t, 1, primary.calls)
+
+	// После паузы основной эндпоинт снова первый
+	primary.err = nil
+	*now = now.Add(2 * time.Minute)
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.Equal(t, 2, primary.calls)
+	assert.Equal(t, 2, fallback.calls)
+}
+
+func TestPool_NodeErrorsAreNotRetried(t *testing.T) {
+	nodeErr := &jsonrpc.RPCError{Code: -32002, Message: "Transaction simulation failed"}
+	primary := &fakeTransport{err: nodeErr}
+	fallback := &fakeTransport{}
+	p, _ := testPool(RoutingFailover, primary, fallback)
+
+	assert.ErrorIs(t, p.CallForInto(context.Background(), nil, "sendTransaction", nil), nodeErr)
+	assert.Zero(t, fallback.calls)
+	assert.True(t, p.Status()[0].Healthy)
+
+	// Ограничение частоты — сбой эндпоинта
+	primary.err = &jsonrpc.RPCError{Code: codeRateLimited, Message: "Too many requests"}
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.Equal(t, 1, fallback.calls)
+}
+
+func TestPool_AllDown(t *testing.T) {
+	a := &fakeTransport{err: jsonrpc.NewHTTPError(http.StatusBadGateway, errors.New("bad gateway"))}
+	b := &fakeTransport{err: errors.New("timeout")}
+	p, _ := testPool(RoutingFailover, a, b)
+
+	assert.EqualError(t, p.CallForInto(context.Background(), nil, "getSlot", nil), "timeout")
+
+	// Когда упали все, пул все равно пробует их, начиная с раньше всех упавшего
+	b.err = nil
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.Equal(t, 2, a.calls)
+	assert.Equal(t, 2, b.calls)
+}
+
+func TestPool_LatencyRouting(t *testing.T) {
+	slow, fast := &fakeTransport{}, &fakeTransport{}
+	p, _ := testPool(RoutingLatency, slow, fast)
+	p.endpoints[0].latency = 300 * time.Millisecond
+	p.endpoints[1].latency = 40 * time.Millisecond
+
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.Zero(t, slow.calls)
+	assert.Equal(t, 1, fast.calls)
+}
+
+func TestPool_CanceledContext(t *testing.T) {
+	primary := &fakeTransport{err: context.Canceled}
+	fallback := &fakeTransport{}
+	p, _ := testPool(RoutingFailover, primary, fallback)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, p.CallForInto(ctx, nil, "getSlot", nil), context.Canceled)
+	assert.Zero(t, fallback.calls, "a canceled request is not retried elsewhere")
+	assert.True(t, p.Status()[0].Healthy)
+}
+
+func TestPool_RateLimit(t *testing.T) {
+	primary, fallback := &fakeTransport{}, &fakeTransport{}
+	endpoints := []*poolEndpoint{
+		{url: "https://rpca.test", transport: primary},
+		{url: "https://rpcb.test", transport: fallback},
+	}
+	p := newPool(endpoints, PoolOptions{
+		RateLimit:      RateLimit{PerSecond: 0.01, Burst: 1},
+		EndpointLimits: map[string]RateLimit{"https://rpcb.test": {PerSecond: 1000, Burst: 10}},
+	}, zap.NewNop())
+
+	// Исчерпавший лимит основной эндпоинт уступает запрос резервному
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, fallback.calls)
+
+	// Единственный эндпоинт без свободного слота: запрос ждет и отменяется по дедлайну
+	single := newPool([]*poolEndpoint{{url: "https://rpcc.test", transport: &fakeTransport{}}},
+		PoolOptions{RateLimit: RateLimit{PerSecond: 0.01, Burst: 1}}, zap.NewNop())
+	assert.NoError(t, single.CallForInto(context.Background(), nil, "getSlot", nil))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Error(t, single.CallForInto(ctx, nil, "getSlot", nil))
+
+	status := single.Status()[0]
+	assert.Equal(t, "rpcc.test", status.Host)
+	assert.Equal(t, uint64(2), status.Requests)
+	assert.Equal(t, uint64(1), status.Throttled)
+	assert.Zero(t, status.Queued)
+}
+
+func TestPool_EndpointSharesRateLimit(t *testing.T) {
+	primary, fallback := &fakeTransport{}, &fakeTransport{}
+	p := newPool([]*poolEndpoint{
+		{url: "https://rpca.test", transport: primary},
+		{url: "https://rpcb.test", transport: fallback},
+	}, PoolOptions{
+		RateLimit:      RateLimit{PerSecond: 0.01, Burst: 1},
+		EndpointLimits: map[string]RateLimit{"https://task.test": {PerSecond: 5, Burst: 2}},
+	}, zap.NewNop())
+
+	// Задача с rpc из rpc_list тратит тот же token bucket, что и пул
+	assert.NoError(t, p.Endpoint("https://rpca.test").CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.NoError(t, p.CallForInto(context.Background(), nil, "getSlot", nil))
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, fallback.calls, "the primary bucket is already spent")
+
+	// Без свободного слота запрос задачи ждет и не уходит на другой эндпоинт
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Error(t, p.Endpoint("https://rpca.test").CallForInto(ctx, nil, "getSlot", nil))
+	assert.Equal(t, 1, fallback.calls)
+
+	// Эндпоинт вне rpc_list получает свой лимит и попадает в отчет
+	task := p.Endpoint("https://task.test").(endpointTransport)
+	assert.Same(t, task.ep, p.Endpoint("https://task.test").(endpointTransport).ep)
+	assert.Equal(t, 2, task.ep.limiter.Burst())
+
+	status := p.Status()
+	require.Len(t, status, 3)
+	assert.Equal(t, uint64(2), status[0].Requests)
+	assert.Equal(t, uint64(1), status[0].Throttled)
+	assert.False(t, status[0].TaskOnly)
+	assert.Equal(t, "task.test", status[2].Host)
+	assert.True(t, status[2].TaskOnly)
+}
+
+func TestClient_WithEndpointUsesPool(t *testing.T) {
+	primary := &fakeTransport{}
+	p := newPool([]*poolEndpoint{{url: "https://rpca.test", transport: primary}}, PoolOptions{}, zap.NewNop())
+	c := NewPoolClient(p, zap.NewNop())
+
+	_, err := c.WithEndpoint("https://rpca.test").GetSlot(context.Background(), rpc.CommitmentProcessed)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, uint64(1), p.Status()[0].Requests)
+}
+
+func TestPool_EndpointObserver(t *testing.T) {
+	primary, fallback := &fakeTransport{err: errors.New("connection refused")}, &fakeTransport{}
+	p, _ := testPool(RoutingFailover, primary, fallback)
+
+	var methods, hosts []string
+	ctx := WithEndpointObserver(context.Background(), func(method, host string) {
+		methods = append(methods, method)
+		hosts = append(hosts, host)
+	})
+	assert.NoError(t, p.CallForInto(ctx, nil, "sendTransaction", nil))
+	assert.NoError(t, p.Endpoint("https://rpcb.test").CallForInto(ctx, nil, "getSlot", nil))
+
+	// После переключения сообщается эндпоинт, который ответил, а не первый в rpc_list
+	assert.Equal(t, []string{"sendTransaction", "getSlot"}, methods)
+	assert.Equal(t, []string{"rpcb.test", "rpcb.test"}, hosts)
+}
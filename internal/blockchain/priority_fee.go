@@ -0,0 +1,290 @@
+// internal/blockchain/priority_fee.go
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// Источники рекомендаций по priority fee для режима "auto".
+const (
+	FeeSourceRPC    = "rpc"    // getRecentPrioritizationFees обычного RPC
+	FeeSourceHelius = "helius" // Helius getPriorityFeeEstimate
+	FeeSourceTriton = "triton" // Triton getRecentPrioritizationFees с percentile
+)
+
+// defaultFeePercentile — перцентиль (в процентах) по умолчанию для on-chain оценки.
+const defaultFeePercentile = 75
+
+// feeCacheTTL — сколько живет оценка priority fee: задачи, торгующие одновременно,
+// не запрашивают у провайдера одно и то же.
+const feeCacheTTL = 2 * time.Second
+
+// PriorityFeeProvider возвращает рекомендуемую цену вычислительной единицы в micro-lamports
+// по перцентилю percentile (в процентах) недавних комиссий; 0 — уровень источника по умолчанию.
+type PriorityFeeProvider interface {
+	Name() string
+	EstimatePriorityFee(ctx context.Context, accounts []solana.PublicKey, percentile int) (uint64, error)
+}
+
+// IsAutoFee сообщает, что priority_fee задачи просит оценку: "auto" или "auto:p75".
+func IsAutoFee(setting string) bool {
+	return setting == "auto" || strings.HasPrefix(setting, "auto:")
+}
+
+// ParseAutoFee разбирает перцентиль из priority_fee вида "auto:p75"; для "auto" возвращает 0.
+func ParseAutoFee(setting string) (int, error) {
+	if setting == "auto" {
+		return 0, nil
+	}
+	s, ok := strings.CutPrefix(setting, "auto:p")
+	p, err := strconv.Atoi(s)
+	if !ok || err != nil || p < 1 || p > 100 {
+		return 0, fmt.Errorf("invalid priority fee %q: use auto or auto:pN with N from 1 to 100", setting)
+	}
+	return p, nil
+}
+
+// NewPriorityFeeProvider создает провайдера по имени источника и URL его RPC.
+func NewPriorityFeeProvider(source, rpcURL string) (PriorityFeeProvider, error) {
+	switch source {
+	case FeeSourceHelius:
+		return &heliusFeeProvider{rpc: rpc.New(rpcURL), level: "High"}, nil
+	case FeeSourceTriton:
+		return &tritonFeeProvider{rpc: rpc.New(rpcURL), percentile: defaultFeePercentile}, nil
+	case FeeSourceRPC, "":
+		return &onChainFeeProvider{rpc: rpc.New(rpcURL), percentile: defaultFeePercentile}, nil
+	default:
+		return nil, fmt.Errorf("unknown priority fee source: %s", source)
+	}
+}
+
+// SetPriorityFeeProvider задает внешний источник рекомендаций по priority fee.
+func (c *Client) SetPriorityFeeProvider(p PriorityFeeProvider) {
+	c.feeProvider = p
+}
+
+// PriorityFee оценивает цену CU в micro-lamports для priority_fee "auto" или "auto:pN"
+// по аккаунтам транзакции.
+func (c *Client) PriorityFee(ctx context.Context, setting string, accounts []solana.PublicKey) (uint64, error) {
+	percentile, err := ParseAutoFee(setting)
+	if err != nil {
+		return 0, err
+	}
+	return c.EstimatePriorityFee(ctx, accounts, percentile)
+}
+
+// EstimatePriorityFee возвращает рекомендуемую цену CU в micro-lamports для указанных аккаунтов
+// по перцентилю percentile (0 — уровень источника по умолчанию). Оценки кешируются на
+// feeCacheTTL. При недоступности Helius или Triton используется getRecentPrioritizationFees
+// основного RPC; ошибка on-chain провайдера возвращается сразу — повтор того же запроса
+// ничего бы не дал.
+func (c *Client) EstimatePriorityFee(ctx context.Context, accounts []solana.PublicKey, percentile int) (uint64, error) {
+	key := feeCacheKey(accounts, percentile)
+	if fee, ok := c.fees.get(key); ok {
+		return fee, nil
+	}
+
+	if c.feeProvider != nil {
+		fee, err := c.feeProvider.EstimatePriorityFee(ctx, accounts, percentile)
+		if err == nil {
+			c.logger.Debug("Priority fee estimate",
+				zap.String("source", c.feeProvider.Name()),
+				zap.Int("percentile", percentile),
+				zap.Uint64("micro_lamports", fee))
+			c.fees.put(key, fee)
+			return fee, nil
+		}
+		if _, onChain := c.feeProvider.(*onChainFeeProvider); onChain {
+			return 0, err
+		}
+		c.logger.Warn(fmt.Sprintf("⚠️  Priority fee provider %s unavailable, falling back to on-chain: %v",
+			c.feeProvider.Name(), err))
+	}
+
+	fallback := &onChainFeeProvider{rpc: c.rpc, percentile: defaultFeePercentile}
+	fee, err := fallback.EstimatePriorityFee(ctx, accounts, percentile)
+	if err != nil {
+		return 0, err
+	}
+	c.fees.put(key, fee)
+	return fee, nil
+}
+
+// feeCache хранит недавние оценки priority fee по аккаунтам и перцентилю.
+// Общий для клиента и его копий WithEndpoint; nil-кеш ничего не хранит.
+type feeCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]cachedFee
+}
+
+type cachedFee struct {
+	fee uint64
+	at  time.Time
+}
+
+func newFeeCache() *feeCache {
+	return &feeCache{now: time.Now, entries: make(map[string]cachedFee)}
+}
+
+func feeCacheKey(accounts []solana.PublicKey, percentile int) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(percentile))
+	for _, a := range accounts {
+		b.WriteByte('|')
+		b.WriteString(a.String())
+	}
+	return b.String()
+}
+
+func (fc *feeCache) get(key string) (uint64, bool) {
+	if fc == nil {
+		return 0, false
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	e, ok := fc.entries[key]
+	if !ok || fc.now().Sub(e.at) > feeCacheTTL {
+		return 0, false
+	}
+	return e.fee, true
+}
+
+func (fc *feeCache) put(key string, fee uint64) {
+	if fc == nil {
+		return
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	now := fc.now()
+	// Устаревшие оценки удаляются при записи, чтобы кеш не рос с числом токенов
+	for k, e := range fc.entries {
+		if now.Sub(e.at) > feeCacheTTL {
+			delete(fc.entries, k)
+		}
+	}
+	fc.entries[key] = cachedFee{fee: fee, at: now}
+}
+
+// heliusFeeProvider использует Helius getPriorityFeeEstimate.
+type heliusFeeProvider struct {
+	rpc   *rpc.Client
+	level string
+}
+
+func (h *heliusFeeProvider) Name() string { return FeeSourceHelius }
+
+func (h *heliusFeeProvider) EstimatePriorityFee(ctx context.Context, accounts []solana.PublicKey, percentile int) (uint64, error) {
+	keys := make([]string, len(accounts))
+	for i, a := range accounts {
+		keys[i] = a.String()
+	}
+
+	params := []interface{}{
+		map[string]interface{}{
+			"accountKeys": keys,
+			"options":     map[string]interface{}{"priorityLevel": h.levelFor(percentile)},
+		},
+	}
+
+	var out struct {
+		PriorityFeeEstimate float64 `json:"priorityFeeEstimate"`
+	}
+	if err := h.rpc.RPCCallForInto(ctx, &out, "getPriorityFeeEstimate", params); err != nil {
+		return 0, fmt.Errorf("helius getPriorityFeeEstimate: %w", err)
+	}
+	return uint64(out.PriorityFeeEstimate), nil
+}
+
+// levelFor переводит перцентиль в ближайший не меньший уровень Helius
+// (Low — 25, Medium — 50, High — 75, VeryHigh — 95, UnsafeMax — 100).
+func (h *heliusFeeProvider) levelFor(percentile int) string {
+	switch {
+	case percentile <= 0:
+		return h.level
+	case percentile <= 25:
+		return "Low"
+	case percentile <= 50:
+		return "Medium"
+	case percentile <= 75:
+		return "High"
+	case percentile <= 95:
+		return "VeryHigh"
+	default:
+		return "UnsafeMax"
+	}
+}
+
+// tritonFeeProvider использует расширенный getRecentPrioritizationFees Triton с параметром percentile.
+type tritonFeeProvider struct {
+	rpc        *rpc.Client
+	percentile int
+}
+
+func (t *tritonFeeProvider) Name() string { return FeeSourceTriton }
+
+func (t *tritonFeeProvider) EstimatePriorityFee(ctx context.Context, accounts []solana.PublicKey, percentile int) (uint64, error) {
+	if percentile <= 0 {
+		percentile = t.percentile
+	}
+	// Triton принимает перцентиль в базисных пунктах (0..10000)
+	params := []interface{}{
+		solana.PublicKeySlice(accounts),
+		map[string]interface{}{"percentile": percentile * 100},
+	}
+
+	var out []rpc.PriorizationFeeResult
+	if err := t.rpc.RPCCallForInto(ctx, &out, "getRecentPrioritizationFees", params); err != nil {
+		return 0, fmt.Errorf("triton getRecentPrioritizationFees: %w", err)
+	}
+	if len(out) == 0 {
+		return 0, fmt.Errorf("triton returned no fee samples")
+	}
+
+	// Значения уже посчитаны по перцентилю внутри слота — берем медиану по слотам
+	return feePercentile(out, 50), nil
+}
+
+// onChainFeeProvider считает перцентиль по стандартному getRecentPrioritizationFees.
+type onChainFeeProvider struct {
+	rpc        *rpc.Client
+	percentile int
+}
+
+func (o *onChainFeeProvider) Name() string { return FeeSourceRPC }
+
+func (o *onChainFeeProvider) EstimatePriorityFee(ctx context.Context, accounts []solana.PublicKey, percentile int) (uint64, error) {
+	if percentile <= 0 {
+		percentile = o.percentile
+	}
+	out, err := o.rpc.GetRecentPrioritizationFees(ctx, accounts)
+	if err != nil {
+		return 0, fmt.Errorf("getRecentPrioritizationFees: %w", err)
+	}
+	if len(out) == 0 {
+		return 0, fmt.Errorf("no recent prioritization fees")
+	}
+	return feePercentile(out, percentile), nil
+}
+
+// feePercentile возвращает p-й перцентиль (0..100) комиссий из выборки.
+func feePercentile(samples []rpc.PriorizationFeeResult, p int) uint64 {
+	fees := make([]uint64, len(samples))
+	for i, s := range samples {
+		fees[i] = s.PrioritizationFee
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i] < fees[j] })
+
+	idx := (len(fees) - 1) * p / 100
+	return fees[idx]
+}
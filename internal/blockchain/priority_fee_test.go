@@ -0,0 +1,125 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingFeeProvider отвечает перцентилем как ценой и считает запросы.
+type countingFeeProvider struct {
+	calls int
+}
+
+func (p *countingFeeProvider) Name() string { return "counting" }
+
+func (p *countingFeeProvider) EstimatePriorityFee(_ context.Context, _ []solana.PublicKey, percentile int) (uint64, error) {
+	p.calls++
+	return uint64(percentile) * 1_000, nil
+}
+
+func TestParseAutoFee(t *testing.T) {
+	p, err := ParseAutoFee("auto")
+	require.NoError(t, err)
+	assert.Zero(t, p)
+
+	p, err = ParseAutoFee("auto:p90")
+	require.NoError(t, err)
+	assert.Equal(t, 90, p)
+
+	for _, setting := range []string{"auto:p0", "auto:p101", "auto:75", "auto:pmax"} {
+		_, err := ParseAutoFee(setting)
+		assert.Error(t, err, setting)
+	}
+	assert.True(t, IsAutoFee("auto:p50"))
+	assert.False(t, IsAutoFee("0.00001"))
+}
+
+func TestClient_PriorityFeeCache(t *testing.T) {
+	provider := &countingFeeProvider{}
+	client := NewClient("http://127.0.0.1:0", zap.NewNop())
+	client.SetPriorityFeeProvider(provider)
+	now := time.Unix(1_700_000_000, 0)
+	client.fees.now = func() time.Time { return now }
+	accounts := []solana.PublicKey{solana.SystemProgramID}
+
+	fee, err := client.PriorityFee(context.Background(), "auto:p75", accounts)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(75_000), fee)
+
+	// Клиент другого RPC разделяет кеш: повторный запрос не доходит до провайдера
+	fee, err = client.WithEndpoint("http://127.0.0.1:1").PriorityFee(context.Background(), "auto:p75", accounts)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(75_000), fee)
+	assert.Equal(t, 1, provider.calls)
+
+	fee, err = client.PriorityFee(context.Background(), "auto:p90", accounts)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(90_000), fee, "every percentile is cached separately")
+	assert.Equal(t, 2, provider.calls)
+
+	now = now.Add(feeCacheTTL + time.Millisecond)
+	_, err = client.PriorityFee(context.Background(), "auto:p75", accounts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, provider.calls, "stale estimates are fetched again")
+
+	_, err = client.PriorityFee(context.Background(), "auto:p0", accounts)
+	assert.Error(t, err)
+}
+
+func TestClient_PriorityFeeFallback(t *testing.T) {
+	accounts := []solana.PublicKey{solana.SystemProgramID}
+	samples := `[{"slot":1,"prioritizationFee":1000},{"slot":2,"prioritizationFee":3000}]`
+
+	for name, tc := range map[string]struct {
+		provider      func(r *rpc.Client) PriorityFeeProvider
+		wantFallbacks int
+	}{
+		"helius falls back to on-chain": {
+			provider:      func(r *rpc.Client) PriorityFeeProvider { return &heliusFeeProvider{rpc: r, level: "High"} },
+			wantFallbacks: 1,
+		},
+		"triton falls back to on-chain": {
+			provider: func(r *rpc.Client) PriorityFeeProvider {
+				return &tritonFeeProvider{rpc: r, percentile: defaultFeePercentile}
+			},
+			wantFallbacks: 1,
+		},
+		"on-chain source is not retried": {
+			provider: func(r *rpc.Client) PriorityFeeProvider {
+				return &onChainFeeProvider{rpc: r, percentile: defaultFeePercentile}
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			down := &methodTransport{} // Провайдер отвечает ошибкой на любой запрос
+			primary := &methodTransport{responses: map[string]string{"getRecentPrioritizationFees": samples}}
+			client := &Client{rpc: rpc.NewWithCustomRPCClient(primary), logger: zap.NewNop(), fees: newFeeCache()}
+			client.SetPriorityFeeProvider(tc.provider(rpc.NewWithCustomRPCClient(down)))
+
+			fee, err := client.EstimatePriorityFee(context.Background(), accounts, 0)
+			assert.Equal(t, tc.wantFallbacks, primary.count("getRecentPrioritizationFees"))
+			if tc.wantFallbacks == 0 {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1000), fee)
+		})
+	}
+}
+
+func TestHeliusLevelFor(t *testing.T) {
+	h := &heliusFeeProvider{level: "High"}
+	assert.Equal(t, "High", h.levelFor(0))
+	assert.Equal(t, "Low", h.levelFor(10))
+	assert.Equal(t, "Medium", h.levelFor(50))
+	assert.Equal(t, "VeryHigh", h.levelFor(90))
+	assert.Equal(t, "UnsafeMax", h.levelFor(99))
+}
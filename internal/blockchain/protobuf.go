@@ -0,0 +1,103 @@
+// internal/blockchain/protobuf.go
+package blockchain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Типы полей protobuf, которые встречаются в сообщениях Yellowstone gRPC.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+var errProtoTruncated = errors.New("protobuf: truncated message")
+
+// protoAppendVarint дописывает поле field с целым значением v.
+func protoAppendVarint(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|protoVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+// protoAppendBool дописывает булево поле; false тоже пишется, чтобы optional-поле было задано.
+func protoAppendBool(b []byte, field int, v bool) []byte {
+	if v {
+		return protoAppendVarint(b, field, 1)
+	}
+	return protoAppendVarint(b, field, 0)
+}
+
+// protoAppendBytes дописывает поле field с байтами, строкой или вложенным сообщением.
+func protoAppendBytes(b []byte, field int, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|protoBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// protoField — поле разобранного сообщения: целое значение или содержимое bytes.
+type protoField struct {
+	num    int
+	wire   int
+	varint uint64
+	bytes  []byte
+}
+
+// protoFields разбирает сообщение на поля в порядке следования. Поля fixed32/fixed64
+// пропускаются: в сообщениях, которые читает бот, их нет.
+func protoFields(data []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errProtoTruncated
+		}
+		data = data[n:]
+		f := protoField{num: int(tag >> 3), wire: int(tag & 7)}
+
+		switch f.wire {
+		case protoVarint:
+			f.varint, n = binary.Uvarint(data)
+			if n <= 0 {
+				return nil, errProtoTruncated
+			}
+			data = data[n:]
+		case protoBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return nil, errProtoTruncated
+			}
+			f.bytes = data[n : n+int(size)]
+			data = data[n+int(size):]
+		case protoFixed64:
+			if len(data) < 8 {
+				return nil, errProtoTruncated
+			}
+			data = data[8:]
+			continue
+		case protoFixed32:
+			if len(data) < 4 {
+				return nil, errProtoTruncated
+			}
+			data = data[4:]
+			continue
+		default:
+			return nil, fmt.Errorf("protobuf: unsupported wire type %d", f.wire)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// protoFind возвращает первое поле с номером num.
+func protoFind(fields []protoField, num int) (protoField, bool) {
+	for _, f := range fields {
+		if f.num == num {
+			return f, true
+		}
+	}
+	return protoField{}, false
+}
@@ -0,0 +1,221 @@
+// internal/blockchain/rebroadcast.go
+package blockchain
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// setComputeUnitPriceID — индекс инструкции SetComputeUnitPrice программы Compute Budget.
+const setComputeUnitPriceID = 3
+
+// FeeEscalation — расписание повышения цены CU при повторной отправке неподтвержденной транзакции.
+type FeeEscalation struct {
+	Interval    time.Duration // Сколько ждать подтверждения перед повторной отправкой (0 = без повторов)
+	StepPercent float64       // Прирост цены CU за каждую повторную отправку, %
+	MaxPrice    uint64        // Потолок цены CU в micro-lamports (0 = без потолка)
+	MaxAttempts int           // Максимум повторных отправок
+}
+
+// Enabled сообщает, включены ли повторные отправки.
+func (e FeeEscalation) Enabled() bool {
+	return e.Interval > 0 && e.MaxAttempts > 0
+}
+
+// Price возвращает цену CU для попытки attempt (0 — исходная отправка).
+func (e FeeEscalation) Price(base uint64, attempt int) uint64 {
+	price := float64(base)
+	for i := 0; i < attempt; i++ {
+		price *= 1 + e.StepPercent/100
+	}
+	result := uint64(price)
+	if e.MaxPrice > 0 && result > e.MaxPrice {
+		result = max(e.MaxPrice, base)
+	}
+	return result
+}
+
+// SendAttempt описывает одну отправку транзакции.
+type SendAttempt struct {
+	Signature     solana.Signature
+	Attempt       int    // 0 — исходная отправка, далее — повторные
+	MicroLamports uint64 // Цена CU этой отправки
+	Resubmission  int    // Сколько раз транзакция подписана заново со свежим blockhash
+}
+
+// SetFeeEscalation задает расписание повторных отправок для SendAndConfirm.
+func (c *Client) SetFeeEscalation(e FeeEscalation) {
+	c.escalation = e
+}
+
+// SendAndConfirm отправляет транзакцию и ждет ее подтверждения. Если включено расписание
+// FeeEscalation и транзакция не подтвердилась за Interval, она пересобирается с более высокой
+// ценой CU — меняется только инструкция SetComputeUnitPrice — и отправляется снова.
+// Все версии подписываются с одним blockhash, чтобы истекать одновременно; ожидается
+// подтверждение любой из отправленных версий. Если включена симуляция, транзакция,
+// обреченная на ошибку, не отправляется: возвращается *SimulationError.
+// Если blockhash истек, а транзакция так и не попала в блок, возвращается ErrBlockhashExpired,
+// а при включенном SetResubmitDeadline транзакция подписывается со свежим blockhash и
+// отправляется заново, пока не выйдет срок.
+func (c *Client) SendAndConfirm(
+	ctx context.Context,
+	instructions []solana.Instruction,
+	blockhash solana.Hash,
+	build func(ixs []solana.Instruction, blockhash solana.Hash) (*solana.Transaction, error),
+	opts TransactionOptions,
+	commitment rpc.CommitmentType,
+	onSend func(SendAttempt),
+) (SendAttempt, error) {
+	if commitment == "" {
+		commitment = rpc.CommitmentConfirmed
+	}
+	timeout := confirmationTimeout
+	if c.resubmit > 0 {
+		timeout = c.resubmit
+	}
+	ctx, cancel := withDefaultTimeout(ctx, timeout)
+	defer cancel()
+
+	for resubmission := 0; ; resubmission++ {
+		landed, err := c.sendAndConfirm(ctx, instructions, blockhash, build, opts, commitment, resubmission, onSend)
+		if c.resubmit <= 0 || !IsBlockhashError(err) || ctx.Err() != nil {
+			return landed, err
+		}
+		fresh, _, fetchErr := c.fetchBlockhash(ctx)
+		if fetchErr != nil {
+			return landed, err
+		}
+		c.logger.Warn(fmt.Sprintf("♻️  Blockhash expired before confirmation, resubmitting with a fresh one (#%d)", resubmission+1))
+		blockhash = fresh
+	}
+}
+
+// sendAndConfirm выполняет одну отправку SendAndConfirm с blockhash: исходную версию и
+// повторные с повышенной ценой CU.
+func (c *Client) sendAndConfirm(
+	ctx context.Context,
+	instructions []solana.Instruction,
+	blockhash solana.Hash,
+	build func([]solana.Instruction, solana.Hash) (*solana.Transaction, error),
+	opts TransactionOptions,
+	commitment rpc.CommitmentType,
+	resubmission int,
+	onSend func(SendAttempt),
+) (SendAttempt, error) {
+	esc := c.escalation
+	basePrice, hasPrice := ComputeUnitPrice(instructions)
+	if !esc.Enabled() || !hasPrice {
+		tx, err := build(instructions, blockhash)
+		if err != nil {
+			return SendAttempt{}, err
+		}
+		if c.simulate {
+			if err := c.Preflight(ctx, tx); err != nil {
+				return SendAttempt{}, err
+			}
+		}
+		sig, err := c.SendTransactionWithOpts(ctx, tx, opts)
+		if err != nil {
+			return SendAttempt{}, fmt.Errorf("send transaction: %w", err)
+		}
+		sent := SendAttempt{Signature: sig, MicroLamports: basePrice, Resubmission: resubmission}
+		if onSend != nil {
+			onSend(sent)
+		}
+		return sent, c.waitForConfirmation(ctx, sig, commitment, blockhash)
+	}
+
+	var sent []SendAttempt
+	for attempt := 0; ; attempt++ {
+		price := esc.Price(basePrice, attempt)
+		tx, err := build(WithComputeUnitPrice(instructions, price), blockhash)
+		if err != nil {
+			return SendAttempt{}, err
+		}
+		if attempt == 0 && c.simulate {
+			// Повторные версии отличаются только ценой CU — достаточно проверить исходную
+			if err := c.Preflight(ctx, tx); err != nil {
+				return SendAttempt{}, err
+			}
+		}
+		sig, err := c.SendTransactionWithOpts(ctx, tx, opts)
+		if err != nil {
+			if len(sent) == 0 {
+				return SendAttempt{}, fmt.Errorf("send transaction: %w", err)
+			}
+			// Предыдущие версии еще могут подтвердиться — продолжаем ждать их
+			c.logger.Warn(fmt.Sprintf("⚠️  Rebroadcast #%d failed: %v", attempt, err))
+		} else {
+			current := SendAttempt{Signature: sig, Attempt: attempt, MicroLamports: price, Resubmission: resubmission}
+			sent = append(sent, current)
+			if onSend != nil {
+				onSend(current)
+			}
+		}
+
+		wait := esc.Interval
+		if attempt >= esc.MaxAttempts {
+			// Повторы исчерпаны — ждем до общего срока
+			deadline, _ := ctx.Deadline()
+			wait = time.Until(deadline)
+		}
+		landed, err := c.waitForAny(ctx, sent, commitment, wait, blockhash)
+		if err != nil || landed != nil {
+			if landed != nil {
+				return *landed, err
+			}
+			return sent[len(sent)-1], err
+		}
+	}
+}
+
+// waitForAny ждет подтверждения любой из отправленных версий не дольше wait.
+// Возвращает nil без ошибки, если за wait ничего не подтвердилось, и ErrBlockhashExpired,
+// если общий blockhash версий истек раньше.
+func (c *Client) waitForAny(ctx context.Context, sent []SendAttempt, commitment rpc.CommitmentType, wait time.Duration, blockhash solana.Hash) (*SendAttempt, error) {
+	sigs := make([]solana.Signature, len(sent))
+	for i, s := range sent {
+		sigs[i] = s.Signature
+	}
+	index, err := c.awaitSignatures(ctx, sigs, commitment, wait, blockhash)
+	if index < 0 {
+		return nil, err
+	}
+	return &sent[index], err
+}
+
+// ComputeUnitPrice возвращает цену CU из инструкции SetComputeUnitPrice, если она есть.
+func ComputeUnitPrice(instructions []solana.Instruction) (uint64, bool) {
+	for _, ix := range instructions {
+		if !ix.ProgramID().Equals(computebudget.ProgramID) {
+			continue
+		}
+		data, err := ix.Data()
+		if err != nil || len(data) < 9 || data[0] != setComputeUnitPriceID {
+			continue
+		}
+		return binary.LittleEndian.Uint64(data[1:9]), true
+	}
+	return 0, false
+}
+
+// WithComputeUnitPrice возвращает копию инструкций с новой ценой CU.
+func WithComputeUnitPrice(instructions []solana.Instruction, microLamports uint64) []solana.Instruction {
+	result := make([]solana.Instruction, len(instructions))
+	for i, ix := range instructions {
+		result[i] = ix
+		if !ix.ProgramID().Equals(computebudget.ProgramID) {
+			continue
+		}
+		if data, err := ix.Data(); err == nil && len(data) > 0 && data[0] == setComputeUnitPriceID {
+			result[i] = computebudget.NewSetComputeUnitPriceInstruction(microLamports).Build()
+		}
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeEscalation_Price(t *testing.T) {
+	e := FeeEscalation{StepPercent: 50, MaxPrice: 20_000}
+
+	assert.Equal(t, uint64(5_000), e.Price(5_000, 0))
+	assert.Equal(t, uint64(7_500), e.Price(5_000, 1))
+	assert.Equal(t, uint64(11_250), e.Price(5_000, 2))
+	assert.Equal(t, uint64(20_000), e.Price(5_000, 4))
+
+	// Потолок ниже исходной цены не снижает ее
+	assert.Equal(t, uint64(30_000), e.Price(30_000, 2))
+}
+
+func TestWithComputeUnitPrice(t *testing.T) {
+	transfer := system.NewTransferInstruction(1, solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()).Build()
+	ixs := []solana.Instruction{
+		computebudget.NewSetComputeUnitLimitInstruction(200_000).Build(),
+		computebudget.NewSetComputeUnitPriceInstruction(5_000).Build(),
+		transfer,
+	}
+
+	price, ok := ComputeUnitPrice(ixs)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5_000), price)
+
+	bumped := WithComputeUnitPrice(ixs, 7_500)
+	price, ok = ComputeUnitPrice(bumped)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7_500), price)
+	assert.Equal(t, ixs[0], bumped[0])
+	assert.Equal(t, ixs[2], bumped[2])
+
+	// Исходные инструкции не меняются
+	price, _ = ComputeUnitPrice(ixs)
+	assert.Equal(t, uint64(5_000), price)
+
+	_, ok = ComputeUnitPrice([]solana.Instruction{transfer})
+	assert.False(t, ok)
+}
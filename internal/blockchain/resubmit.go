@@ -0,0 +1,34 @@
+// internal/blockchain/resubmit.go
+package blockchain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// SetResubmitDeadline включает повторную подпись и отправку транзакции со свежим blockhash,
+// если прежний истек или узел его не знает. d ограничивает SendAndConfirm целиком, если у
+// ctx нет своего срока (0 — без повторной подписи).
+func (c *Client) SetResubmitDeadline(d time.Duration) {
+	c.resubmit = d
+}
+
+// IsBlockhashError сообщает, что транзакция не прошла из-за blockhash: он истек до
+// подтверждения или отклонен узлом при отправке. Такую транзакцию можно подписать
+// со свежим blockhash и отправить заново — прежняя версия в блок уже не попадет.
+func IsBlockhashError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrBlockhashExpired) {
+		return true
+	}
+	var sim *SimulationError
+	if errors.As(err, &sim) {
+		name, _ := sim.Err.(string)
+		return name == "BlockhashNotFound"
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "BlockhashNotFound") || strings.Contains(strings.ToLower(msg), "blockhash not found")
+}
@@ -0,0 +1,97 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// funcTransport отвечает на каждый запрос RPC JSON-ом, который функция вернула для метода.
+type funcTransport func(method string) string
+
+func (f funcTransport) CallForInto(ctx context.Context, out interface{}, method string, params []interface{}) error {
+	return json.Unmarshal([]byte(f(method)), out)
+}
+
+func (f funcTransport) CallWithCallback(ctx context.Context, method string, params []interface{}, callback func(*http.Request, *http.Response) error) error {
+	return nil
+}
+
+func (f funcTransport) CallBatch(ctx context.Context, requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	return nil, nil
+}
+
+func TestIsBlockhashError(t *testing.T) {
+	assert.True(t, IsBlockhashError(ErrBlockhashExpired))
+	assert.True(t, IsBlockhashError(fmt.Errorf("send transaction: %w", errors.New("Transaction simulation failed: Blockhash not found"))))
+	assert.True(t, IsBlockhashError(errors.New(`transaction failed: BlockhashNotFound`)))
+	assert.True(t, IsBlockhashError(&SimulationError{Reason: "blockhash expired", Err: "BlockhashNotFound"}))
+	assert.False(t, IsBlockhashError(&SimulationError{Reason: "insufficient SOL to pay the transaction fee", Err: "InsufficientFundsForFee"}))
+	assert.False(t, IsBlockhashError(errors.New("transaction failed: map[InstructionError:[2 map[Custom:6004]]]")))
+	assert.False(t, IsBlockhashError(nil))
+}
+
+func TestSendAndConfirm_ResubmitsExpiredBlockhash(t *testing.T) {
+	stale, fresh := solana.Hash{1}, solana.Hash{2}
+	refreshed := false
+	tr := funcTransport(func(method string) string {
+		switch method {
+		case "sendTransaction":
+			return `"` + solana.Signature{9}.String() + `"`
+		case "getLatestBlockhash":
+			refreshed = true
+			return `{"context":{"slot":1},"value":{"blockhash":"` + fresh.String() + `","lastValidBlockHeight":100}}`
+		case "isBlockhashValid":
+			return `{"context":{"slot":1},"value":false}`
+		case "getSignatureStatuses":
+			if refreshed {
+				return `{"context":{"slot":1},"value":[{"slot":1,"confirmations":null,"err":null,"confirmationStatus":"confirmed"}]}`
+			}
+			return `{"context":{"slot":1},"value":[null]}`
+		}
+		return `null`
+	})
+	c := &Client{rpc: rpc.NewWithCustomRPCClient(tr), logger: zap.NewNop()}
+	c.SetResubmitDeadline(10 * time.Second)
+
+	payer := solana.NewWallet()
+	var signedWith []solana.Hash
+	build := func(ixs []solana.Instruction, blockhash solana.Hash) (*solana.Transaction, error) {
+		signedWith = append(signedWith, blockhash)
+		tx, err := solana.NewTransaction(ixs, blockhash, solana.TransactionPayer(payer.PublicKey()))
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer.PrivateKey })
+		return tx, err
+	}
+	ixs := []solana.Instruction{system.NewTransferInstruction(1, payer.PublicKey(), solana.NewWallet().PublicKey()).Build()}
+
+	var sent []SendAttempt
+	landed, err := c.SendAndConfirm(context.Background(), ixs, stale, build, TransactionOptions{}, rpc.CommitmentConfirmed, func(a SendAttempt) {
+		sent = append(sent, a)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []solana.Hash{stale, fresh}, signedWith, "the same instructions are re-signed with a fresh blockhash")
+	require.Len(t, sent, 2)
+	assert.Equal(t, 1, sent[1].Resubmission)
+	assert.Equal(t, 1, landed.Resubmission)
+
+	// Без SetResubmitDeadline истекший blockhash возвращается вызывающему
+	refreshed = false
+	c.SetResubmitDeadline(0)
+	_, err = c.SendAndConfirm(context.Background(), ixs, stale, build, TransactionOptions{}, rpc.CommitmentConfirmed, nil)
+	assert.ErrorIs(t, err, ErrBlockhashExpired)
+}
@@ -4,6 +4,8 @@ package blockchain
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
@@ -11,10 +13,23 @@ import (
 	"go.uber.org/zap"
 )
 
+// MainnetGenesisHash — genesis hash сети mainnet-beta, для которой заданы адреса программ DEX.
+var MainnetGenesisHash = solana.MustHashFromBase58("5eykt4UsFv8P8NJdTREpY1vzqKqZKvdpKuc147dw2N9d")
+
 // Client – тонкий адаптер для взаимодействия с блокчейном Solana через solana-go.
 type Client struct {
-	rpc    *rpc.Client
-	logger *zap.Logger
+	rpc         *rpc.Client
+	logger      *zap.Logger
+	feeProvider PriorityFeeProvider
+	fees        *feeCache // Недавние оценки priority fee, общие с копиями WithEndpoint
+	escalation  FeeEscalation
+	resubmit    time.Duration // Срок повторной подписи со свежим blockhash (0 — выключена)
+	simulate    bool          // Симулировать транзакции перед отправкой в SendAndConfirm
+	blockhash   blockhashCache
+	pool        *Pool           // Пул эндпоинтов, если клиент создан через NewPoolClient
+	accounts    *accountBatcher // Объединение GetAccountInfo, если включено SetAccountBatching
+	cache       *accountCache   // Редко меняющиеся аккаунты для GetCachedAccountInfo
+	confirm     *confirmTracker // Подписки signatureSubscribe, если включено SetConfirmationSocket
 }
 
 // NewClient создаёт новый клиент, принимая RPC URL и логгер через dependency injection.
@@ -22,17 +37,67 @@ func NewClient(rpcURL string, logger *zap.Logger) *Client {
 	return &Client{
 		rpc:    rpc.New(rpcURL),
 		logger: logger.Named("solbc-client"),
+		fees:   newFeeCache(),
+		cache:  newAccountCache(),
+	}
+}
+
+// NewPoolClient создаёт клиент поверх пула RPC-эндпоинтов: запросы выбирают лучший
+// здоровый эндпоинт и переключаются на следующий при его сбое.
+func NewPoolClient(pool *Pool, logger *zap.Logger) *Client {
+	return &Client{
+		rpc:    rpc.NewWithCustomRPCClient(pool),
+		logger: logger.Named("solbc-client"),
+		fees:   newFeeCache(),
+		cache:  newAccountCache(),
+		pool:   pool,
+	}
+}
+
+// Pool возвращает пул эндпоинтов клиента или nil для клиента одного RPC.
+func (c *Client) Pool() *Pool {
+	return c.pool
+}
+
+// WithEndpoint возвращает клиент к другому RPC с теми же источником и кешем priority fee,
+// кешем аккаунтов, настройками переотправки и симуляции. Кеш blockhash у нового клиента свой.
+// У клиента пула запросы к эндпоинту проходят через его лимит rpc_rate_limits и учитываются
+// в отчете об использовании.
+func (c *Client) WithEndpoint(rpcURL string) *Client {
+	transport := rpc.New(rpcURL)
+	if c.pool != nil {
+		transport = rpc.NewWithCustomRPCClient(c.pool.Endpoint(rpcURL))
+	}
+	return &Client{
+		rpc:         transport,
+		logger:      c.logger,
+		feeProvider: c.feeProvider,
+		fees:        c.fees,
+		escalation:  c.escalation,
+		resubmit:    c.resubmit,
+		simulate:    c.simulate,
+		cache:       c.cache,
+		confirm:     c.confirm,
 	}
 }
 
-// GetRecentBlockhash получает последний blockhash с использованием стандартного метода solana-go.
+// GetRecentBlockhash получает последний blockhash, по возможности из кеша PrefetchBlockhash.
 func (c *Client) GetRecentBlockhash(ctx context.Context) (solana.Hash, error) {
-	result, err := c.rpc.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
+	hash, _, err := c.LatestBlockhash(ctx)
 	if err != nil {
 		c.logger.Error("❌ GetRecentBlockhash error: " + err.Error())
 		return solana.Hash{}, err
 	}
-	return result.Value.Blockhash, nil
+	return hash, nil
+}
+
+// GetGenesisHash возвращает genesis hash сети, к которой подключен RPC.
+func (c *Client) GetGenesisHash(ctx context.Context) (solana.Hash, error) {
+	hash, err := c.rpc.GetGenesisHash(ctx)
+	if err != nil {
+		return solana.Hash{}, fmt.Errorf("get genesis hash: %w", err)
+	}
+	return hash, nil
 }
 
 // SendTransaction отправляет транзакцию c параметрами по умолчанию.
@@ -61,9 +126,16 @@ func (c *Client) GetAccountDataInto(ctx context.Context, pubkey solana.PublicKey
 	return nil
 }
 
-// GetAccountInfo получает информацию об аккаунте.
+// GetAccountInfo получает информацию об аккаунте; при включенном SetAccountBatching
+// запрос объединяется с одновременными в один getMultipleAccounts.
 func (c *Client) GetAccountInfo(ctx context.Context, pubkey solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
-	result, err := c.rpc.GetAccountInfo(ctx, pubkey)
+	var result *rpc.GetAccountInfoResult
+	var err error
+	if c.accounts != nil {
+		result, err = c.accounts.get(ctx, pubkey)
+	} else {
+		result, err = c.rpc.GetAccountInfo(ctx, pubkey)
+	}
 	if err != nil {
 		c.logger.Debug("GetAccountInfo error for " + pubkey.String() + ": " + err.Error())
 		return nil, err
@@ -173,6 +245,19 @@ func (c *Client) GetSignatureStatuses(ctx context.Context, signatures ...solana.
 	return result, nil
 }
 
+// GetSignaturesForAddress возвращает последние подтвержденные подписи транзакций, затронувших адрес (новые первыми).
+func (c *Client) GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int) ([]*rpc.TransactionSignature, error) {
+	result, err := c.rpc.GetSignaturesForAddressWithOpts(ctx, address, &rpc.GetSignaturesForAddressOpts{
+		Limit:      &limit,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		c.logger.Debug("GetSignaturesForAddress error for " + address.String() + ": " + err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
 // SendTransactionWithOpts отправляет транзакцию с заданными опциями.
 func (c *Client) SendTransactionWithOpts(ctx context.Context, tx *solana.Transaction, opts TransactionOptions) (solana.Signature, error) {
 	sig, err := c.rpc.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{
@@ -221,6 +306,15 @@ const (
 	checkInterval       = 200 * time.Millisecond
 )
 
+// withDefaultTimeout ограничивает ctx таймаутом timeout, если у него еще нет срока:
+// срок, заданный бюджетом сделки, не сокращается.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // Для каждого уровня commitment — набор допустимых confirmation statuses
 var okStatuses = map[rpc.CommitmentType][]rpc.ConfirmationStatusType{
 	rpc.CommitmentProcessed: {rpc.ConfirmationStatusProcessed, rpc.ConfirmationStatusConfirmed, rpc.ConfirmationStatusFinalized},
@@ -243,47 +337,33 @@ func (c *Client) WaitForTransactionConfirmation(
 	ctx context.Context,
 	signature solana.Signature,
 	commitment rpc.CommitmentType,
+) error {
+	return c.waitForConfirmation(ctx, signature, commitment, solana.Hash{})
+}
+
+// waitForConfirmation ожидает подтверждения; если известен blockhash транзакции,
+// его истечение прекращает ожидание с ErrBlockhashExpired.
+func (c *Client) waitForConfirmation(
+	ctx context.Context,
+	signature solana.Signature,
+	commitment rpc.CommitmentType,
+	blockhash solana.Hash,
 ) error {
 	// По умолчанию — минимум Confirmed
 	if commitment == "" {
 		commitment = rpc.CommitmentConfirmed
 	}
 
-	// Обрезаем общий ctx таймаутом
-	ctx, cancel := context.WithTimeout(ctx, confirmationTimeout)
+	// Обрезаем общий ctx таймаутом, если у операции нет своего срока
+	ctx, cancel := withDefaultTimeout(ctx, confirmationTimeout)
 	defer cancel()
 
-	ticker := time.NewTicker(checkInterval)
-	defer ticker.Stop()
-
 	c.logger.Info("⏳ Waiting for confirmation: " + signature.String()[:8] + "...")
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-ticker.C:
-			resp, err := c.rpc.GetSignatureStatuses(ctx, true, signature)
-			if err != nil {
-				c.logger.Warn("⚠️  Error getting signature status for " + signature.String()[:8] + "...: " + err.Error())
-				continue
-			}
-			if resp == nil || len(resp.Value) == 0 || resp.Value[0] == nil {
-				continue
-			}
-
-			status := resp.Value[0]
-			// Если транзакция упала — сразу возвращаем ошибку
-			if status.Err != nil {
-				return fmt.Errorf("transaction failed: %v", status.Err)
-			}
-			// Если дошли до нужного статуса — выходим
-			if contains(okStatuses[commitment], status.ConfirmationStatus) {
-				c.logger.Info("✅ Transaction confirmed: " + signature.String()[:8] + "...")
-				return nil
-			}
-		}
+	if _, err := c.awaitSignatures(ctx, []solana.Signature{signature}, commitment, 0, blockhash); err != nil {
+		return err
 	}
+	c.logger.Info("✅ Transaction confirmed: " + signature.String()[:8] + "...")
+	return nil
 }
 
 // GetTokenAccountBalance получает баланс токенного аккаунта с указанным уровнем подтверждения.
@@ -301,5 +381,80 @@ func (c *Client) GetTokenAccountBalance(ctx context.Context, account solana.Publ
 	return result, nil
 }
 
+// GetMintDecimals возвращает decimals минта токена.
+func (c *Client) GetMintDecimals(ctx context.Context, mint solana.PublicKey) (uint8, error) {
+	info, err := c.GetCachedAccountInfo(ctx, mint, MintCacheTTL)
+	if err != nil {
+		return 0, err
+	}
+	if info == nil || info.Value == nil || info.Value.Data == nil {
+		return 0, fmt.Errorf("mint %s not found", mint)
+	}
+	// SPL mint: decimals на смещении 44 (у Token-2022 расширения идут после базовой части)
+	data := info.Value.Data.GetBinary()
+	if len(data) <= 44 {
+		return 0, fmt.Errorf("account %s is not a token mint", mint)
+	}
+	return data[44], nil
+}
+
+// GetTokenSupply возвращает эмиссию токена в UI-единицах (с учетом decimals).
+func (c *Client) GetTokenSupply(ctx context.Context, mint solana.PublicKey) (float64, error) {
+	result, err := c.rpc.GetTokenSupply(ctx, mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil || result.Value == nil {
+		return 0, fmt.Errorf("no supply for mint %s", mint)
+	}
+	raw, err := strconv.ParseUint(result.Value.Amount, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid supply %q for mint %s: %w", result.Value.Amount, mint, err)
+	}
+	return float64(raw) / math.Pow10(int(result.Value.Decimals)), nil
+}
+
+// GetTokenAccountsByOwner получает все токен-аккаунты владельца: по запросу на SPL Token и Token-2022.
+func (c *Client) GetTokenAccountsByOwner(ctx context.Context, owner solana.PublicKey) (*rpc.GetTokenAccountsResult, error) {
+	var result *rpc.GetTokenAccountsResult
+	for _, program := range []solana.PublicKey{solana.TokenProgramID, Token2022ProgramID} {
+		programID := program
+		res, err := c.rpc.GetTokenAccountsByOwner(
+			ctx,
+			owner,
+			&rpc.GetTokenAccountsConfig{ProgramId: &programID},
+			&rpc.GetTokenAccountsOpts{
+				Commitment: rpc.CommitmentConfirmed,
+				Encoding:   solana.EncodingBase64,
+			},
+		)
+		if err != nil {
+			c.logger.Debug("GetTokenAccountsByOwner error for " + owner.String() + ": " + err.Error())
+			return nil, err
+		}
+		if result == nil {
+			result = res
+			continue
+		}
+		result.Value = append(result.Value, res.Value...)
+	}
+	return result, nil
+}
+
+// GetTransaction получает подтвержденную транзакцию вместе с метаданными (комиссия, балансы).
+func (c *Client) GetTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error) {
+	maxVersion := uint64(0)
+	result, err := c.rpc.GetTransaction(ctx, signature, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		c.logger.Debug("GetTransaction error for " + signature.String() + ": " + err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
 // Гарантируем, что Client реализует интерфейс blockchain.Client.
 var _ Rpc = (*Client)(nil)
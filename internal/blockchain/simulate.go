@@ -0,0 +1,143 @@
+// internal/blockchain/simulate.go
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ErrSimulationFailed — сентинельная ошибка для проверки через errors.Is: транзакция
+// не прошла симуляцию и не была отправлена.
+var ErrSimulationFailed = errors.New("transaction simulation failed")
+
+// SimulationError описывает транзакцию, отклоненную симуляцией.
+type SimulationError struct {
+	Reason string      // Понятное описание причины
+	Err    interface{} // Исходная ошибка из ответа simulateTransaction
+	Logs   []string
+}
+
+func (e *SimulationError) Error() string {
+	return "simulation failed: " + e.Reason
+}
+
+// Is позволяет использовать errors.Is для проверки типа ошибки
+func (e *SimulationError) Is(target error) bool {
+	return target == ErrSimulationFailed
+}
+
+// slippageErrorCodes — коды ошибок программ DEX о превышении проскальзывания.
+var slippageErrorCodes = map[int]string{
+	6002: "slippage exceeded: more SOL required than the maximum allowed", // Pump.fun TooMuchSolRequired
+	6003: "slippage exceeded: less SOL received than the minimum allowed", // Pump.fun TooLittleSolReceived
+	6004: "slippage exceeded",                                             // Pump.swap ExceededSlippage
+}
+
+// SetSimulateBeforeSend включает симуляцию транзакций перед отправкой в SendAndConfirm.
+func (c *Client) SetSimulateBeforeSend(enabled bool) {
+	c.simulate = enabled
+}
+
+// Preflight симулирует транзакцию и возвращает *SimulationError, если она закончится
+// ошибкой. Ошибка самого запроса симуляции не мешает отправке: возвращается nil.
+func (c *Client) Preflight(ctx context.Context, tx *solana.Transaction) error {
+	result, err := c.SimulateTransaction(ctx, tx)
+	if err != nil {
+		c.logger.Warn("⚠️  Simulation unavailable, sending without it: " + err.Error())
+		return nil
+	}
+	return SimulationFailure(result)
+}
+
+// SimulationFailure переводит результат симуляции в *SimulationError с понятной причиной
+// (нехватка средств, проскальзывание, отсутствующий аккаунт); nil — симуляция успешна.
+func SimulationFailure(result *SimulationResult) error {
+	if result == nil || result.Err == nil {
+		return nil
+	}
+	return &SimulationError{Reason: simulationReason(result), Err: result.Err, Logs: result.Logs}
+}
+
+// simulationReason подбирает описание ошибки: сначала по логам программ, затем по коду ошибки.
+func simulationReason(result *SimulationResult) string {
+	for _, line := range result.Logs {
+		switch {
+		case strings.Contains(line, "insufficient lamports"):
+			return "insufficient SOL balance: " + strings.TrimPrefix(line, "Program log: ")
+		case strings.Contains(line, "Error: insufficient funds"):
+			return "insufficient token balance"
+		}
+	}
+
+	switch e := result.Err.(type) {
+	case string:
+		return describeTransactionError(e)
+	case map[string]interface{}:
+		if ix, ok := e["InstructionError"].([]interface{}); ok && len(ix) == 2 {
+			return describeInstructionError(ix[0], ix[1], result.Logs)
+		}
+	}
+	return fmt.Sprintf("%v", result.Err)
+}
+
+// describeTransactionError описывает ошибку уровня транзакции.
+func describeTransactionError(name string) string {
+	switch name {
+	case "AccountNotFound":
+		return "fee payer account not found (wallet has no SOL)"
+	case "InsufficientFundsForFee":
+		return "insufficient SOL to pay the transaction fee"
+	case "InsufficientFundsForRent":
+		return "insufficient SOL to cover account rent"
+	case "BlockhashNotFound":
+		return "blockhash expired"
+	default:
+		return name
+	}
+}
+
+// describeInstructionError описывает ошибку инструкции index.
+func describeInstructionError(index, detail interface{}, logs []string) string {
+	at := fmt.Sprintf("instruction %v: ", index)
+	switch d := detail.(type) {
+	case string:
+		switch d {
+		case "InsufficientFunds":
+			return at + "insufficient funds"
+		case "AccountNotInitialized", "InvalidAccountData", "UninitializedAccount":
+			return at + "account not found or not initialized"
+		default:
+			return at + d
+		}
+	case map[string]interface{}:
+		code, ok := d["Custom"].(float64)
+		if !ok {
+			break
+		}
+		if msg := anchorErrorMessage(logs); msg != "" {
+			return fmt.Sprintf("%sprogram error %d: %s", at, int(code), msg)
+		}
+		if reason, ok := slippageErrorCodes[int(code)]; ok {
+			return at + reason
+		}
+		if code == 1 {
+			return at + "insufficient funds"
+		}
+		return fmt.Sprintf("%sprogram error %d", at, int(code))
+	}
+	return fmt.Sprintf("%s%v", at, detail)
+}
+
+// anchorErrorMessage достает текст ошибки Anchor-программы из логов, если он есть.
+func anchorErrorMessage(logs []string) string {
+	for _, line := range logs {
+		if _, msg, ok := strings.Cut(line, "Error Message: "); ok {
+			return strings.TrimSuffix(strings.TrimSpace(msg), ".")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSimulationFailure(t *testing.T) {
+	assert.NoError(t, SimulationFailure(&SimulationResult{Logs: []string{"Program log: ok"}}))
+
+	cases := []struct {
+		name   string
+		result SimulationResult
+		want   string
+	}{
+		{
+			name:   "fee payer missing",
+			result: SimulationResult{Err: "AccountNotFound"},
+			want:   "simulation failed: fee payer account not found (wallet has no SOL)",
+		},
+		{
+			name: "anchor slippage",
+			result: SimulationResult{
+				Err: map[string]interface{}{"InstructionError": []interface{}{float64(3), map[string]interface{}{"Custom": float64(6002)}}},
+				Logs: []string{
+					"Program 6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P invoke [1]",
+					"Program log: AnchorError thrown in programs/pump/src/lib.rs:747. Error Code: TooMuchSolRequired. Error Number: 6002. Error Message: slippage: Too much SOL required to buy the given amount of tokens..",
+				},
+			},
+			want: "simulation failed: instruction 3: program error 6002: slippage: Too much SOL required to buy the given amount of tokens.",
+		},
+		{
+			name:   "slippage without logs",
+			result: SimulationResult{Err: map[string]interface{}{"InstructionError": []interface{}{float64(2), map[string]interface{}{"Custom": float64(6004)}}}},
+			want:   "simulation failed: instruction 2: slippage exceeded",
+		},
+		{
+			name: "not enough SOL",
+			result: SimulationResult{
+				Err:  map[string]interface{}{"InstructionError": []interface{}{float64(2), map[string]interface{}{"Custom": float64(1)}}},
+				Logs: []string{"Transfer: insufficient lamports 1000, need 5000"},
+			},
+			want: "simulation failed: insufficient SOL balance: Transfer: insufficient lamports 1000, need 5000",
+		},
+		{
+			name:   "uninitialized account",
+			result: SimulationResult{Err: map[string]interface{}{"InstructionError": []interface{}{float64(4), "UninitializedAccount"}}},
+			want:   "simulation failed: instruction 4: account not found or not initialized",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := SimulationFailure(&tc.result)
+			require.Error(t, err)
+			assert.EqualError(t, err, tc.want)
+			assert.True(t, errors.Is(err, ErrSimulationFailed))
+		})
+	}
+}
+
+func TestWithEndpoint_KeepsSendSettings(t *testing.T) {
+	c := NewClient("http://127.0.0.1:1", zap.NewNop())
+	c.SetSimulateBeforeSend(true)
+	c.SetResubmitDeadline(time.Minute)
+
+	// Задача с собственным rpc симулирует транзакции так же, как общий клиент
+	task := c.WithEndpoint("http://127.0.0.1:2")
+	assert.True(t, task.simulate)
+	assert.Equal(t, time.Minute, task.resubmit)
+}
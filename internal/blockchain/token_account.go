@@ -0,0 +1,127 @@
+// internal/blockchain/token_account.go
+package blockchain
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const (
+	// Раскладка токен-аккаунта SPL Token и Token-2022; смещение владельца — tokenAccountOwnerOffset
+	tokenAccountLen            = 165
+	tokenAccountMintOffset     = 0
+	tokenAccountAmountOffset   = 64
+	tokenAccountCloseAuthority = 129
+
+	token2022AccountTypeAccount = 2
+	extensionTransferFeeAmount  = 2 // Удержанные с переводов комиссии: withheld_amount u64
+
+	// Инструкция CloseAccount программ SPL Token и Token-2022
+	closeAccountInstruction = 9
+)
+
+// TokenAccount — поля токен-аккаунта, от которых зависит, можно ли его закрыть.
+type TokenAccount struct {
+	Address        solana.PublicKey
+	Program        solana.PublicKey // Программа токена: SPL Token или Token-2022
+	Mint           solana.PublicKey
+	Owner          solana.PublicKey
+	Amount         uint64
+	Lamports       uint64 // Депозит, который вернет закрытие
+	Frozen         bool
+	CloseAuthority *solana.PublicKey // nil — закрыть может владелец
+	WithheldFees   uint64            // Удержанные комиссии Token-2022, мешают закрытию
+}
+
+// ParseTokenAccount разбирает данные токен-аккаунта SPL Token или Token-2022.
+func ParseTokenAccount(address, program solana.PublicKey, lamports uint64, data []byte) (*TokenAccount, error) {
+	if len(data) < tokenAccountLen {
+		return nil, fmt.Errorf("account %s is not a token account (%d bytes)", address, len(data))
+	}
+	acc := &TokenAccount{
+		Address:        address,
+		Program:        program,
+		Mint:           solana.PublicKeyFromBytes(data[tokenAccountMintOffset : tokenAccountMintOffset+32]),
+		Owner:          solana.PublicKeyFromBytes(data[tokenAccountOwnerOffset : tokenAccountOwnerOffset+32]),
+		Amount:         binary.LittleEndian.Uint64(data[tokenAccountAmountOffset : tokenAccountAmountOffset+8]),
+		Lamports:       lamports,
+		Frozen:         IsTokenAccountFrozen(data),
+		CloseAuthority: parseCOptionKey(data[tokenAccountCloseAuthority:]),
+	}
+	if program.Equals(Token2022ProgramID) && len(data) > token2022AccountTypeOffset &&
+		data[token2022AccountTypeOffset] == token2022AccountTypeAccount {
+		// TLV: type u16, length u16, value
+		for offset := token2022AccountTypeOffset + 1; offset+4 <= len(data); {
+			extType := binary.LittleEndian.Uint16(data[offset : offset+2])
+			extLen := int(binary.LittleEndian.Uint16(data[offset+2 : offset+4]))
+			value := offset + 4
+			if value+extLen > len(data) {
+				break
+			}
+			if extType == extensionTransferFeeAmount && extLen >= 8 {
+				acc.WithheldFees = binary.LittleEndian.Uint64(data[value : value+8])
+			}
+			offset = value + extLen
+		}
+	}
+	return acc, nil
+}
+
+// Closable сообщает, может ли владелец закрыть пустой аккаунт, и почему нет.
+func (a *TokenAccount) Closable() (bool, string) {
+	switch {
+	case a.Amount > 0:
+		return false, "holds tokens"
+	case a.Frozen:
+		return false, "frozen"
+	case a.CloseAuthority != nil && !a.CloseAuthority.Equals(a.Owner):
+		return false, "close authority is " + a.CloseAuthority.String()
+	case a.WithheldFees > 0:
+		return false, "withheld transfer fees"
+	}
+	return true, ""
+}
+
+// NewCloseAccountInstruction закрывает токен-аккаунт и переводит его депозит destination.
+func NewCloseAccountInstruction(acc *TokenAccount, destination solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(acc.Program, solana.AccountMetaSlice{
+		solana.Meta(acc.Address).WRITE(),
+		solana.Meta(destination).WRITE(),
+		solana.Meta(acc.Owner).SIGNER(),
+	}, []byte{closeAccountInstruction})
+}
+
+// GetOwnedTokenAccounts возвращает токен-аккаунты владельца в обеих программах токенов.
+func (c *Client) GetOwnedTokenAccounts(ctx context.Context, owner solana.PublicKey) ([]*TokenAccount, error) {
+	var accounts []*TokenAccount
+	for _, program := range []solana.PublicKey{solana.TokenProgramID, Token2022ProgramID} {
+		programID := program
+		res, err := c.rpc.GetTokenAccountsByOwner(
+			ctx,
+			owner,
+			&rpc.GetTokenAccountsConfig{ProgramId: &programID},
+			&rpc.GetTokenAccountsOpts{
+				Commitment: rpc.CommitmentConfirmed,
+				Encoding:   solana.EncodingBase64,
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("token accounts of %s: %w", program, err)
+		}
+		for _, ta := range res.Value {
+			if ta == nil || ta.Account.Data == nil {
+				continue
+			}
+			acc, err := ParseTokenAccount(ta.Pubkey, program, ta.Account.Lamports, ta.Account.Data.GetBinary())
+			if err != nil {
+				continue
+			}
+			accounts = append(accounts, acc)
+		}
+	}
+	return accounts, nil
+}
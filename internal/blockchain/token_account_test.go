@@ -0,0 +1,71 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenAccountData(mint, owner solana.PublicKey, amount uint64) []byte {
+	data := make([]byte, tokenAccountLen)
+	copy(data[tokenAccountMintOffset:], mint[:])
+	copy(data[tokenAccountOwnerOffset:], owner[:])
+	binary.LittleEndian.PutUint64(data[tokenAccountAmountOffset:], amount)
+	data[tokenAccountStateOffset] = 1
+	return data
+}
+
+func TestParseTokenAccount_Closable(t *testing.T) {
+	mint, owner, address := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+
+	acc, err := ParseTokenAccount(address, solana.TokenProgramID, 2_039_280, tokenAccountData(mint, owner, 0))
+	require.NoError(t, err)
+	assert.Equal(t, mint, acc.Mint)
+	assert.Equal(t, owner, acc.Owner)
+	ok, _ := acc.Closable()
+	assert.True(t, ok)
+
+	ix := NewCloseAccountInstruction(acc, owner)
+	assert.Equal(t, solana.TokenProgramID, ix.ProgramID())
+	data, err := ix.Data()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{closeAccountInstruction}, data)
+
+	acc, err = ParseTokenAccount(address, solana.TokenProgramID, 2_039_280, tokenAccountData(mint, owner, 5))
+	require.NoError(t, err)
+	ok, reason := acc.Closable()
+	assert.False(t, ok)
+	assert.Equal(t, "holds tokens", reason)
+
+	frozen := tokenAccountData(mint, owner, 0)
+	frozen[tokenAccountStateOffset] = tokenAccountStateFrozen
+	acc, err = ParseTokenAccount(address, solana.TokenProgramID, 2_039_280, frozen)
+	require.NoError(t, err)
+	ok, reason = acc.Closable()
+	assert.False(t, ok)
+	assert.Equal(t, "frozen", reason)
+
+	_, err = ParseTokenAccount(address, solana.TokenProgramID, 0, frozen[:100])
+	assert.Error(t, err)
+}
+
+func TestParseTokenAccount_Token2022WithheldFees(t *testing.T) {
+	mint, owner := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	data := tokenAccountData(mint, owner, 0)
+	data = append(data, token2022AccountTypeAccount)
+	ext := make([]byte, 4+8)
+	binary.LittleEndian.PutUint16(ext[0:], extensionTransferFeeAmount)
+	binary.LittleEndian.PutUint16(ext[2:], 8)
+	binary.LittleEndian.PutUint64(ext[4:], 42)
+	data = append(data, ext...)
+
+	acc, err := ParseTokenAccount(solana.NewWallet().PublicKey(), Token2022ProgramID, 2_074_080, data)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), acc.WithheldFees)
+	ok, reason := acc.Closable()
+	assert.False(t, ok)
+	assert.Equal(t, "withheld transfer fees", reason)
+}
@@ -0,0 +1,66 @@
+// internal/blockchain/token_program.go
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// TokenMint — программа, которой принадлежит mint, и его комиссия за перевод Token-2022.
+type TokenMint struct {
+	Program     solana.PublicKey // SPL Token или Token-2022
+	TransferFee *TransferFee     // nil, если комиссии нет
+}
+
+// IsToken2022 сообщает, выпущен ли токен программой Token-2022.
+func (m *TokenMint) IsToken2022() bool {
+	return m.Program.Equals(Token2022ProgramID)
+}
+
+// TokenProgramOf проверяет, что владелец mint-аккаунта — одна из программ токенов, и возвращает ее.
+func TokenProgramOf(owner solana.PublicKey) (solana.PublicKey, error) {
+	if owner.Equals(solana.TokenProgramID) || owner.Equals(Token2022ProgramID) {
+		return owner, nil
+	}
+	return solana.PublicKey{}, fmt.Errorf("account is owned by %s, not a token program", owner)
+}
+
+// FindAssociatedTokenAddress вычисляет ATA кошелька для mint: адрес зависит от программы токена,
+// поэтому у Token-2022 он отличается от того, что возвращает solana.FindAssociatedTokenAddress.
+func FindAssociatedTokenAddress(wallet, mint, program solana.PublicKey) (solana.PublicKey, error) {
+	ata, _, err := solana.FindProgramAddress(
+		[][]byte{wallet[:], program[:], mint[:]},
+		solana.SPLAssociatedTokenAccountProgramID,
+	)
+	return ata, err
+}
+
+// GetTokenMint читает mint-аккаунт и определяет программу токена и комиссию за перевод.
+func (c *Client) GetTokenMint(ctx context.Context, mint solana.PublicKey) (*TokenMint, error) {
+	info, err := c.GetCachedAccountInfo(ctx, mint, MintCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("get mint account: %w", err)
+	}
+	if info == nil || info.Value == nil {
+		return nil, fmt.Errorf("mint account %s not found", mint)
+	}
+	program, err := TokenProgramOf(info.Value.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("mint %s: %w", mint, err)
+	}
+	return &TokenMint{
+		Program:     program,
+		TransferFee: ParseTransferFee(program, info.Value.Data.GetBinary()),
+	}, nil
+}
+
+// AssociatedTokenAccount возвращает ATA владельца для mint, определяя программу токена по mint-аккаунту.
+func (c *Client) AssociatedTokenAccount(ctx context.Context, owner, mint solana.PublicKey) (solana.PublicKey, error) {
+	info, err := c.GetTokenMint(ctx, mint)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	return FindAssociatedTokenAddress(owner, mint, info.Program)
+}
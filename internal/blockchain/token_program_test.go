@@ -0,0 +1,36 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAssociatedTokenAddress(t *testing.T) {
+	wallet, mint := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+
+	classic, err := FindAssociatedTokenAddress(wallet, mint, solana.TokenProgramID)
+	require.NoError(t, err)
+	expected, _, err := solana.FindAssociatedTokenAddress(wallet, mint)
+	require.NoError(t, err)
+	assert.Equal(t, expected, classic)
+
+	ext, err := FindAssociatedTokenAddress(wallet, mint, Token2022ProgramID)
+	require.NoError(t, err)
+	assert.NotEqual(t, classic, ext, "Token-2022 accounts live at a different address")
+}
+
+func TestTokenProgramOf(t *testing.T) {
+	program, err := TokenProgramOf(Token2022ProgramID)
+	require.NoError(t, err)
+	assert.True(t, (&TokenMint{Program: program}).IsToken2022())
+
+	program, err = TokenProgramOf(solana.TokenProgramID)
+	require.NoError(t, err)
+	assert.False(t, (&TokenMint{Program: program}).IsToken2022())
+
+	_, err = TokenProgramOf(solana.SystemProgramID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+// internal/blockchain/transfer_fee.go
+package blockchain
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Token2022ProgramID — программа SPL Token-2022 (Token Extensions).
+var Token2022ProgramID = solana.MustPublicKeyFromBase58("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+
+const (
+	// Размер базового аккаунта токена, до которого дополняется mint Token-2022 перед TLV-расширениями.
+	token2022AccountTypeOffset = 165
+	token2022AccountTypeMint   = 1
+	extensionTransferFeeConfig = 1
+	transferFeeConfigLen       = 108
+	// maxFeeBasisPoints — предел комиссии программы Token-2022 (MAX_FEE_BASIS_POINTS): 100%.
+	maxFeeBasisPoints = 10_000
+)
+
+// TransferFee — параметры комиссии за перевод Token-2022 (расширение TransferFeeConfig).
+type TransferFee struct {
+	BasisPoints uint16 // Комиссия в базисных пунктах
+	MaximumFee  uint64 // Максимальная комиссия в raw единицах токена
+}
+
+// Percent возвращает комиссию в процентах.
+func (f *TransferFee) Percent() float64 {
+	if f == nil {
+		return 0
+	}
+	return float64(f.BasisPoints) / 100
+}
+
+// Fee вычисляет комиссию за перевод amount так же, как программа Token-2022:
+// ceil(amount * bps / 10000), но не больше MaximumFee. Больше 10000 bps программа
+// не допускает; такое значение считается 100% и не переполняет деление.
+func (f *TransferFee) Fee(amount uint64) uint64 {
+	if f == nil || f.BasisPoints == 0 || amount == 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(amount, uint64(min(f.BasisPoints, maxFeeBasisPoints)))
+	lo, carry := bits.Add64(lo, 9_999, 0)
+	hi += carry
+	fee, _ := bits.Div64(hi, lo, 10_000)
+	if fee > f.MaximumFee {
+		return f.MaximumFee
+	}
+	return fee
+}
+
+// Net возвращает количество токенов, которое дойдет до получателя после комиссии.
+func (f *TransferFee) Net(amount uint64) uint64 {
+	return amount - f.Fee(amount)
+}
+
+// ParseTransferFee извлекает TransferFeeConfig из данных mint-аккаунта.
+// Возвращает nil для классических SPL-токенов и mint без этого расширения.
+// Из старой и новой конфигурации выбирается большая — до смены эпохи
+// действует старая, после — новая, и консервативная оценка безопаснее для min-out.
+func ParseTransferFee(owner solana.PublicKey, data []byte) *TransferFee {
+	if !owner.Equals(Token2022ProgramID) || len(data) <= token2022AccountTypeOffset {
+		return nil
+	}
+	if data[token2022AccountTypeOffset] != token2022AccountTypeMint {
+		return nil
+	}
+
+	// TLV: type u16, length u16, value
+	for offset := token2022AccountTypeOffset + 1; offset+4 <= len(data); {
+		extType := binary.LittleEndian.Uint16(data[offset : offset+2])
+		extLen := int(binary.LittleEndian.Uint16(data[offset+2 : offset+4]))
+		value := offset + 4
+		if value+extLen > len(data) {
+			return nil
+		}
+
+		if extType == extensionTransferFeeConfig && extLen >= transferFeeConfigLen {
+			v := data[value : value+extLen]
+			// authority(32) + withdraw authority(32) + withheld(8), затем older и newer:
+			// epoch u64, maximum_fee u64, basis_points u16
+			older := TransferFee{
+				MaximumFee:  binary.LittleEndian.Uint64(v[80:88]),
+				BasisPoints: binary.LittleEndian.Uint16(v[88:90]),
+			}
+			newer := TransferFee{
+				MaximumFee:  binary.LittleEndian.Uint64(v[98:106]),
+				BasisPoints: binary.LittleEndian.Uint16(v[106:108]),
+			}
+			fee := older
+			if newer.BasisPoints > fee.BasisPoints {
+				fee.BasisPoints = newer.BasisPoints
+			}
+			if newer.MaximumFee > fee.MaximumFee {
+				fee.MaximumFee = newer.MaximumFee
+			}
+			if fee.BasisPoints == 0 {
+				return nil
+			}
+			return &fee
+		}
+
+		offset = value + extLen
+	}
+	return nil
+}
+
+// GetTransferFee возвращает комиссию за перевод токена или nil, если ее нет.
+func (c *Client) GetTransferFee(ctx context.Context, mint solana.PublicKey) (*TransferFee, error) {
+	info, err := c.GetCachedAccountInfo(ctx, mint, MintCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("get mint account: %w", err)
+	}
+	if info == nil || info.Value == nil {
+		return nil, fmt.Errorf("mint account %s not found", mint)
+	}
+	return ParseTransferFee(info.Value.Owner, info.Value.Data.GetBinary()), nil
+}
@@ -0,0 +1,110 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// token2022Mint собирает данные mint Token-2022: базовый mint, дополнение до размера
+// аккаунта токена, тип аккаунта и TLV-расширения.
+func token2022Mint(extensions ...[]byte) []byte {
+	data := make([]byte, token2022AccountTypeOffset+1)
+	binary.LittleEndian.PutUint64(data[mintSupplyOffset:], 1_000_000_000_000)
+	data[mintDecimalsOffset] = 6
+	data[mintDecimalsOffset+1] = 1 // is_initialized
+	data[token2022AccountTypeOffset] = token2022AccountTypeMint
+	for _, ext := range extensions {
+		data = append(data, ext...)
+	}
+	return data
+}
+
+// tlv кодирует расширение: type u16, length u16, value.
+func tlv(extType uint16, value []byte) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, extType)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(value)))
+	return append(b, value...)
+}
+
+// transferFeeConfig кодирует TransferFeeConfig со старой и новой конфигурацией.
+func transferFeeConfig(older, newer TransferFee) []byte {
+	authority := solana.NewWallet().PublicKey()
+	v := append([]byte{}, authority[:]...)            // transfer_fee_config_authority
+	v = append(v, authority[:]...)                    // withdraw_withheld_authority
+	v = binary.LittleEndian.AppendUint64(v, 123)      // withheld_amount
+	for i, fee := range []TransferFee{older, newer} { // epoch, maximum_fee, transfer_fee_basis_points
+		v = binary.LittleEndian.AppendUint64(v, uint64(600+i))
+		v = binary.LittleEndian.AppendUint64(v, fee.MaximumFee)
+		v = binary.LittleEndian.AppendUint16(v, fee.BasisPoints)
+	}
+	return tlv(extensionTransferFeeConfig, v)
+}
+
+func TestParseTransferFee(t *testing.T) {
+	fee := TransferFee{BasisPoints: 250, MaximumFee: 5_000_000_000}
+	closeAuthority := solana.NewWallet().PublicKey()
+	withFee := token2022Mint(transferFeeConfig(fee, fee))
+
+	for name, tc := range map[string]struct {
+		owner solana.PublicKey
+		data  []byte
+		want  *TransferFee
+	}{
+		"transfer fee config": {owner: Token2022ProgramID, data: withFee, want: &fee},
+		"other extensions first": {
+			owner: Token2022ProgramID,
+			data: token2022Mint(
+				tlv(3, closeAuthority[:]),   // MintCloseAuthority
+				tlv(18, make([]byte, 64)),   // MetadataPointer
+				transferFeeConfig(fee, fee), // TransferFeeConfig
+			),
+			want: &fee,
+		},
+		"newer config is larger": {
+			owner: Token2022ProgramID,
+			data:  token2022Mint(transferFeeConfig(TransferFee{BasisPoints: 100, MaximumFee: 9}, TransferFee{BasisPoints: 300, MaximumFee: 7})),
+			want:  &TransferFee{BasisPoints: 300, MaximumFee: 9},
+		},
+		"truncated tlv":          {owner: Token2022ProgramID, data: withFee[:len(withFee)-10]},
+		"truncated tlv header":   {owner: Token2022ProgramID, data: token2022Mint([]byte{1, 0})},
+		"no extensions":          {owner: Token2022ProgramID, data: token2022Mint()},
+		"zero basis points":      {owner: Token2022ProgramID, data: token2022Mint(transferFeeConfig(TransferFee{MaximumFee: 1}, TransferFee{MaximumFee: 1}))},
+		"classic spl owner":      {owner: solana.TokenProgramID, data: withFee},
+		"token account, no mint": {owner: Token2022ProgramID, data: func() []byte { d := append([]byte{}, withFee...); d[token2022AccountTypeOffset] = 2; return d }()},
+		"base mint only":         {owner: Token2022ProgramID, data: withFee[:mintLen]},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ParseTransferFee(tc.owner, tc.data))
+		})
+	}
+}
+
+func TestTransferFee_Fee(t *testing.T) {
+	for name, tc := range map[string]struct {
+		fee    TransferFee
+		amount uint64
+		want   uint64
+	}{
+		"rounds up":             {fee: TransferFee{BasisPoints: 1, MaximumFee: math.MaxUint64}, amount: 10_001, want: 2},
+		"exact":                 {fee: TransferFee{BasisPoints: 1, MaximumFee: math.MaxUint64}, amount: 10_000, want: 1},
+		"smallest amount":       {fee: TransferFee{BasisPoints: 1, MaximumFee: math.MaxUint64}, amount: 1, want: 1},
+		"maximum fee cap":       {fee: TransferFee{BasisPoints: 500, MaximumFee: 1_000}, amount: 1_000_000, want: 1_000},
+		"zero amount":           {fee: TransferFee{BasisPoints: 500, MaximumFee: 1_000}, amount: 0, want: 0},
+		"full fee, huge amount": {fee: TransferFee{BasisPoints: 10_000, MaximumFee: math.MaxUint64}, amount: math.MaxUint64, want: math.MaxUint64},
+		"bps above the limit":   {fee: TransferFee{BasisPoints: math.MaxUint16, MaximumFee: math.MaxUint64}, amount: math.MaxUint64, want: math.MaxUint64},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NotPanics(t, func() { assert.Equal(t, tc.want, tc.fee.Fee(tc.amount)) })
+			assert.Equal(t, tc.amount-tc.want, tc.fee.Net(tc.amount))
+		})
+	}
+
+	var none *TransferFee
+	assert.Zero(t, none.Fee(1_000))
+	assert.Zero(t, none.Percent())
+}
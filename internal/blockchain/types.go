@@ -40,6 +40,9 @@ type Rpc interface {
 	// Получить статусы подписей транзакций.
 	GetSignatureStatuses(ctx context.Context, signatures ...solana.Signature) (*rpc.GetSignatureStatusesResult, error)
 
+	// Получить последние подписи транзакций, затронувших адрес.
+	GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int) ([]*rpc.TransactionSignature, error)
+
 	// Отправить транзакцию с опциями.
 	SendTransactionWithOpts(ctx context.Context, tx *solana.Transaction, opts TransactionOptions) (solana.Signature, error)
 
@@ -54,4 +57,19 @@ type Rpc interface {
 
 	// Получить баланс токенного аккаунта.
 	GetTokenAccountBalance(ctx context.Context, account solana.PublicKey, commitment rpc.CommitmentType) (*rpc.GetTokenAccountBalanceResult, error)
+
+	// Получить все токен-аккаунты владельца.
+	GetTokenAccountsByOwner(ctx context.Context, owner solana.PublicKey) (*rpc.GetTokenAccountsResult, error)
+
+	// Получить подтвержденную транзакцию с метаданными.
+	GetTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error)
+
+	// Получить genesis hash сети.
+	GetGenesisHash(ctx context.Context) (solana.Hash, error)
+
+	// Получить текущий слот.
+	GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error)
+
+	// Получить активную таблицу адресов (ALT).
+	GetLookupTable(ctx context.Context, address solana.PublicKey) (*LookupTable, error)
 }
@@ -0,0 +1,313 @@
+// internal/blockchain/ws.go
+package blockchain
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Коды кадров WebSocket (RFC 6455)
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// wsAcceptGUID — строка из RFC 6455 для проверки ответа на рукопожатие.
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsMaxMessage — предел размера одного сообщения; уведомления RPC намного меньше.
+const wsMaxMessage = 16 << 20
+
+// errWSClosed возвращается при чтении из соединения, закрытого сервером.
+var errWSClosed = errors.New("websocket closed by server")
+
+// Проверка живости клиентского соединения: полуоткрытое TCP-соединение (обрыв у
+// провайдера или на NAT) иначе блокирует чтение навсегда, и переподключение не наступает.
+var (
+	wsPingInterval = 20 * time.Second // Как часто клиент отправляет ping
+	wsIdleTimeout  = 60 * time.Second // Без входящих кадров дольше этого соединение считается оборванным
+	wsWriteTimeout = 10 * time.Second // Предел записи одного кадра
+)
+
+// wsConn — минимальное WebSocket-соединение для подписок RPC: текстовые сообщения,
+// ping/pong и закрытие. Клиент маскирует свои кадры, сервер — нет.
+type wsConn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+	idle   time.Duration // Предел ожидания следующего кадра (0 — без предела)
+	mu     sync.Mutex    // Запись кадров из разных горутин
+
+	done      chan struct{} // Закрывается в Close и останавливает keepalive
+	closeOnce sync.Once
+}
+
+// dialWebSocket открывает соединение с ws:// или wss:// адресом rawURL.
+func dialWebSocket(ctx context.Context, rawURL string) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket url: %w", err)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "wss":
+			host = net.JoinHostPort(u.Hostname(), "443")
+		case "ws":
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	var conn net.Conn
+	switch u.Scheme {
+	case "wss":
+		d := tls.Dialer{Config: &tls.Config{ServerName: u.Hostname()}}
+		conn, err = d.DialContext(ctx, "tcp", host)
+	case "ws":
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", host)
+	default:
+		return nil, fmt.Errorf("websocket url must start with ws:// or wss://")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connect websocket: %w", err)
+	}
+
+	ws, err := handshake(ctx, conn, u)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// handshake выполняет HTTP Upgrade и проверяет Sec-WebSocket-Accept.
+func handshake(ctx context.Context, conn net.Conn, u *url.URL) (*wsConn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("websocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{Method: http.MethodGet, URL: u, Host: u.Host, Header: http.Header{}}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if _, err := fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: %s\r\n", u.RequestURI(), u.Host); err != nil {
+		return nil, fmt.Errorf("websocket handshake: %w", err)
+	}
+	if err := req.Header.Write(conn); err != nil {
+		return nil, fmt.Errorf("websocket handshake: %w", err)
+	}
+	if _, err := io.WriteString(conn, "\r\n"); err != nil {
+		return nil, fmt.Errorf("websocket handshake: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, fmt.Errorf("websocket handshake: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("websocket handshake: server returned status %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsAccept(key) {
+		return nil, fmt.Errorf("websocket handshake: invalid Sec-WebSocket-Accept")
+	}
+	ws := &wsConn{conn: conn, br: br, client: true, idle: wsIdleTimeout, done: make(chan struct{})}
+	go ws.keepalive(wsPingInterval)
+	return ws, nil
+}
+
+// keepalive отправляет ping каждые interval до Close: сервер отвечает pong, поэтому
+// живое соединение не упирается в idle, даже когда уведомлений нет.
+func (c *wsConn) keepalive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.writeFrame(wsPing, nil); err != nil {
+				c.conn.Close() // Чтение вернет ошибку, и вызывающий переподключится
+				return
+			}
+		}
+	}
+}
+
+// wsAccept вычисляет ожидаемый Sec-WebSocket-Accept для ключа key.
+func wsAccept(key string) string {
+	h := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// ReadMessage возвращает следующее текстовое или бинарное сообщение,
+// отвечая на ping по ходу чтения. Если за idle не пришло ни одного кадра,
+// возвращается ошибка: соединение считается оборванным.
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	for {
+		if c.idle > 0 {
+			_ = c.conn.SetReadDeadline(time.Now().Add(c.idle))
+		}
+		fin, opcode, payload, err := c.readFrame()
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return nil, fmt.Errorf("websocket idle for %v: %w", c.idle, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			_ = c.writeFrame(wsClose, nil)
+			return nil, errWSClosed
+		case wsText, wsBinary, wsContinuation:
+			message = append(message, payload...)
+			if len(message) > wsMaxMessage {
+				return nil, fmt.Errorf("websocket message exceeds %d bytes", wsMaxMessage)
+			}
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, fmt.Errorf("unexpected websocket opcode %d", opcode)
+		}
+	}
+}
+
+// WriteMessage отправляет текстовое сообщение.
+func (c *wsConn) WriteMessage(data []byte) error {
+	return c.writeFrame(wsText, data)
+}
+
+// Close закрывает соединение.
+func (c *wsConn) Close() error {
+	c.closeOnce.Do(func() {
+		if c.done != nil {
+			close(c.done)
+		}
+	})
+	_ = c.writeFrame(wsClose, nil)
+	return c.conn.Close()
+}
+
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+
+	size := uint64(head[1] & 0x7F)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if size > wsMaxMessage {
+		return false, 0, nil, fmt.Errorf("websocket frame exceeds %d bytes", wsMaxMessage)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, size)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	data := payload
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return fmt.Errorf("websocket mask: %w", err)
+		}
+		frame = append(frame, mask[:]...)
+		data = make([]byte, len(payload))
+		for i := range payload {
+			data[i] = payload[i] ^ mask[i%4]
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	}
+	if _, err := c.conn.Write(append(frame, data...)); err != nil {
+		return fmt.Errorf("websocket write: %w", err)
+	}
+	return nil
+}
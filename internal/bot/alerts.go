@@ -0,0 +1,57 @@
+// internal/bot/alerts.go
+package bot
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// alertSinks собирает каналы уведомлений из конфигурации: webhook_url и telegram_*
+// получают все уведомления, каналы из alert_sinks — только прошедшие свой фильтр.
+// Второй результат — чат Telegram для команд оператора (первый настроенный, nil — нет).
+func alertSinks(cfg *task.Config, logger *zap.Logger) ([]notify.Sink, *notify.TelegramSink) {
+	var sinks []notify.Sink
+	var commands *notify.TelegramSink
+
+	if cfg.WebhookURL != "" {
+		sinks = append(sinks, notify.NewWebhookSink(cfg.WebhookURL))
+		logger.Info("🔔 Webhook alerts enabled")
+	}
+	if cfg.TelegramBotToken != "" {
+		commands = notify.NewTelegramSink(cfg.TelegramBotToken, cfg.TelegramChatID)
+		sinks = append(sinks, commands)
+		logger.Info("🔔 Telegram alerts and commands enabled for chat " + cfg.TelegramChatID)
+	}
+
+	for _, sc := range cfg.AlertSinks {
+		var sink notify.Sink
+		switch sc.Type {
+		case "webhook":
+			sink = notify.NewWebhookSink(sc.URL)
+		case "telegram":
+			telegram := notify.NewTelegramSink(sc.BotToken, sc.ChatID)
+			if commands == nil {
+				commands = telegram
+			}
+			sink = telegram
+		case "email":
+			sink = notify.NewEmailSink(sc.SMTPAddr, sc.Username, sc.Password, sc.From, sc.To)
+		default:
+			continue // Отсеяно при проверке конфигурации
+		}
+
+		// Уровень важности уже проверен в конфигурации
+		severity, _ := notify.ParseSeverity(sc.MinSeverity)
+		filter := notify.Filter{MinSeverity: severity}
+		for _, t := range sc.AlertTypes {
+			filter.Types = append(filter.Types, notify.AlertType(t))
+		}
+		sinks = append(sinks, notify.Filtered(sc.Name, sink, filter))
+		logger.Info(fmt.Sprintf("🔔 Alert channel %s (%s) enabled, min severity %s", sc.Name, sc.Type, severity))
+	}
+	return sinks, commands
+}
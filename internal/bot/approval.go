@@ -0,0 +1,91 @@
+// internal/bot/approval.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// errOrderRejected — крупная сделка не получила подтверждения оператора.
+var errOrderRejected = errors.New("large order rejected")
+
+// approveOrder спрашивает оператора, отправлять ли сделку на amountSol SOL, если сумма
+// не меньше approval_above_sol: защита от лишнего нуля в tasks.csv. Без ответа за
+// approval_timeout применяется approval_timeout_action.
+func (wp *WorkerPool) approveOrder(ctx context.Context, t *task.Task, side string, amountSol float64, logger *zap.Logger) error {
+	threshold := wp.config.ApprovalAboveSol
+	if threshold <= 0 || amountSol < threshold {
+		return nil
+	}
+
+	amount := fmt.Sprintf("%.4f SOL", amountSol)
+	if math.IsInf(amountSol, 1) {
+		amount = "unknown SOL value"
+	}
+	order := fmt.Sprintf("%s of %s: %s, %s from %s", side, t.TaskName, amount, t.TokenMint, t.WalletName)
+	timeout, action := wp.config.ApprovalTimeout, wp.config.ApprovalTimeoutAction
+
+	wp.notifier.Notify(notify.Alert{
+		Type:     notify.AlertApprovalRequired,
+		Key:      t.TaskName,
+		Severity: notify.SeverityWarning,
+		Message:  fmt.Sprintf("Approval needed for %s (limit %.4f SOL); %s in %s without an answer", order, threshold, action, timeout),
+	})
+
+	askCtx, cancel := context.WithTimeout(ctx, timeout)
+	answer, err := wp.renderer.Ask(askCtx, fmt.Sprintf("❓ Large order %s (limit %.4f SOL). Send it? [y/N] (%s in %s)",
+		order, threshold, action, timeout))
+	cancel()
+
+	switch {
+	case err == nil && isYes(answer):
+		logger.Info("✅ Operator approved " + order)
+		return nil
+	case err == nil:
+		logger.Warn("🛑 Operator rejected " + order)
+		return fmt.Errorf("%w by operator: %s", errOrderRejected, order)
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case action == "approve":
+		logger.Warn(fmt.Sprintf("⏰ No answer for %s (%v), sending it: approval_timeout_action = approve", order, err))
+		return nil
+	default:
+		logger.Warn(fmt.Sprintf("⏰ No answer for %s (%v), not sending it", order, err))
+		return fmt.Errorf("%w: no answer within %s: %s", errOrderRejected, timeout, order)
+	}
+}
+
+func isYes(answer string) bool {
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// sellValue оценивает выручку продажи units базовых единиц токена в SOL.
+func (wp *WorkerPool) sellValue(ctx context.Context, t *task.Task, dexAdapter dex.DEX, units uint64) (float64, error) {
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token mint: %w", err)
+	}
+	decimals, err := wp.solClient.GetMintDecimals(ctx, mint)
+	if err != nil {
+		return 0, fmt.Errorf("resolve token decimals: %w", err)
+	}
+	tokens := float64(units) / math.Pow10(int(decimals))
+	pnl, err := dexAdapter.CalculatePnL(ctx, tokens, 0)
+	if err != nil {
+		return 0, fmt.Errorf("estimate sell value: %w", err)
+	}
+	return pnl.SellEstimate, nil
+}
@@ -0,0 +1,136 @@
+// internal/bot/cleanup.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// closeBatch — сколько токен-аккаунтов закрывается одной транзакцией; столько
+// инструкций CloseAccount с запасом укладываются в лимит размера транзакции.
+const closeBatch = 10
+
+// runCleanup находит пустые токен-аккаунты всех кошельков и закрывает их, возвращая
+// депозит (rent) на кошелек-владелец. С -dry-run только выводит, что было бы закрыто.
+// Аккаунты с токенами, замороженные, с чужим close authority или удержанными
+// комиссиями Token-2022 не трогаются.
+func (r *Runner) runCleanup(ctx context.Context) error {
+	if r.config.DryRun {
+		r.logger.Info("🧹 Looking for empty token accounts (dry run: nothing is sent)")
+	} else {
+		r.logger.Info("🧹 Closing empty token accounts to reclaim rent")
+	}
+
+	names := make([]string, 0, len(r.wallets))
+	for name := range r.wallets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var found, closed, failed int
+	var reclaimed uint64
+	for _, name := range names {
+		w := r.wallets[name]
+		accounts, err := r.solClient.GetOwnedTokenAccounts(ctx, w.PublicKey)
+		if err != nil {
+			r.logger.Warn(fmt.Sprintf("⚠️  Could not list token accounts of %s: %v", name, err))
+			failed++
+			continue
+		}
+
+		var empty []*blockchain.TokenAccount
+		for _, acc := range accounts {
+			ok, reason := acc.Closable()
+			if !ok {
+				if acc.Amount == 0 {
+					r.logger.Info(fmt.Sprintf("   ⏭️  %s: keeping empty account %s of %s: %s", name, acc.Address, acc.Mint, reason))
+				}
+				continue
+			}
+			empty = append(empty, acc)
+		}
+		found += len(empty)
+		if len(empty) == 0 {
+			continue
+		}
+
+		if r.config.DryRun {
+			var rent uint64
+			for _, acc := range empty {
+				rent += acc.Lamports
+				r.logger.Info(fmt.Sprintf("   🧹 %s: would close %s (%s), %.6f SOL", name, acc.Address, acc.Mint, lamportsToSol(acc.Lamports)))
+			}
+			reclaimed += rent
+			r.logger.Info(fmt.Sprintf("🧹 %s: %d empty accounts, %.6f SOL to reclaim", name, len(empty), lamportsToSol(rent)))
+			continue
+		}
+
+		var rent uint64
+		for start := 0; start < len(empty); start += closeBatch {
+			batch := empty[start:min(start+closeBatch, len(empty))]
+			sig, err := r.closeAccounts(ctx, w, batch)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				r.logger.Error(fmt.Sprintf("❌ %s: failed to close %d accounts: %v", name, len(batch), err))
+				failed++
+				continue
+			}
+			for _, acc := range batch {
+				rent += acc.Lamports
+			}
+			closed += len(batch)
+			r.logger.Info(fmt.Sprintf("   🧹 %s: closed %d accounts (%s...)", name, len(batch), sig.String()[:8]))
+		}
+		reclaimed += rent
+		r.balances.Invalidate(w.PublicKey)
+		r.logger.Info(fmt.Sprintf("🧹 %s: reclaimed %.6f SOL", name, lamportsToSol(rent)))
+	}
+
+	switch {
+	case found == 0:
+		r.logger.Info(fmt.Sprintf("🧹 No empty token accounts in %d wallets", len(names)))
+	case r.config.DryRun:
+		r.logger.Info(fmt.Sprintf("🧹 Dry run: %d empty accounts in %d wallets, %.6f SOL to reclaim; run without -dry-run to close them",
+			found, len(names), lamportsToSol(reclaimed)))
+	default:
+		r.logger.Info(fmt.Sprintf("🧹 Closed %d of %d empty accounts, reclaimed %.6f SOL", closed, found, lamportsToSol(reclaimed)))
+	}
+	if failed > 0 {
+		return fmt.Errorf("cleanup finished with %d errors", failed)
+	}
+	return nil
+}
+
+// closeAccounts закрывает аккаунты кошелька w одной транзакцией; депозит уходит на кошелек.
+func (r *Runner) closeAccounts(ctx context.Context, w *task.Wallet, accounts []*blockchain.TokenAccount) (solana.Signature, error) {
+	instructions := make([]solana.Instruction, 0, len(accounts))
+	for _, acc := range accounts {
+		instructions = append(instructions, blockchain.NewCloseAccountInstruction(acc, w.PublicKey))
+	}
+	blockhash, _, err := r.solClient.LatestBlockhash(ctx)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("get blockhash: %w", err)
+	}
+
+	build := func(ixs []solana.Instruction, recent solana.Hash) (*solana.Transaction, error) {
+		tx, err := solana.NewTransaction(ixs, recent, solana.TransactionPayer(w.Payer()))
+		if err != nil {
+			return nil, fmt.Errorf("create transaction: %w", err)
+		}
+		if err := w.SignTransaction(ctx, tx); err != nil {
+			return nil, fmt.Errorf("sign transaction: %w", err)
+		}
+		return tx, nil
+	}
+	opts := blockchain.TransactionOptions{PreflightCommitment: rpc.CommitmentConfirmed}
+	landed, err := r.solClient.SendAndConfirm(ctx, instructions, blockhash, build, opts, rpc.CommitmentConfirmed, nil)
+	return landed.Signature, err
+}
@@ -0,0 +1,206 @@
+// internal/bot/commands.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/storage"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// remoteCommandUsage — подсказка по командам из чата.
+const remoteCommandUsage = "Commands: /positions, /sell MINT PERCENT [WALLET], /panic, /rearm"
+
+// trackMonitor регистрирует мониторинг позиции задачи для команд из чата и kill switch;
+// возвращаемая функция снимает регистрацию. Позиция, открытая уже после включения kill
+// switch (покупка была в пути), продается сразу.
+func (wp *WorkerPool) trackMonitor(t *task.Task, mw *MonitorWorker) func() {
+	key := storage.PositionKey(t.WalletName, t.TokenMint)
+	wp.monitorsMu.Lock()
+	wp.monitors[key] = mw
+	wp.monitorsMu.Unlock()
+
+	// Регистрация идет раньше проверки, а Engage включает switch раньше снимка flatten:
+	// позицию продаст хотя бы один из них
+	if wp.kill.Engaged() {
+		go wp.sellHalted(mw)
+	}
+
+	return func() {
+		wp.monitorsMu.Lock()
+		if wp.monitors[key] == mw {
+			delete(wp.monitors, key)
+		}
+		wp.monitorsMu.Unlock()
+	}
+}
+
+// activeMonitors возвращает мониторинги открытых позиций, упорядоченные по mint и кошельку.
+func (wp *WorkerPool) activeMonitors() []*MonitorWorker {
+	wp.monitorsMu.Lock()
+	defer wp.monitorsMu.Unlock()
+
+	active := make([]*MonitorWorker, 0, len(wp.monitors))
+	for _, mw := range wp.monitors {
+		active = append(active, mw)
+	}
+	sort.Slice(active, func(i, j int) bool {
+		a, b := active[i].task, active[j].task
+		if a.TokenMint != b.TokenMint {
+			return a.TokenMint < b.TokenMint
+		}
+		return a.WalletName < b.WalletName
+	})
+	return active
+}
+
+// remoteCommand выполняет команду оператора from из чата: /positions перечисляет открытые
+// позиции, /sell MINT PERCENT [WALLET] продает долю позиции, /panic включает kill
+// switch, /rearm снова разрешает задачи. MINT можно сократить до начала адреса,
+// если оно однозначно. Команда требует роли из commandRole; изменяющие команды и
+// отказы записываются в журнал аудита.
+func (wp *WorkerPool) remoteCommand(ctx context.Context, from notify.Sender, command string) string {
+	p := wp.telegramPrincipal(from)
+	fields := strings.Fields(command)
+	name, _, _ := strings.Cut(fields[0], "@") // В группах Telegram добавляет имя бота: /sell@my_bot
+
+	need := commandRole(name)
+	if !p.Role.Allows(need) {
+		reply := fmt.Sprintf("%s needs the %s role", name, need)
+		wp.logger.Warn(fmt.Sprintf("🚫 Remote command %q refused for %s (%s)", command, p.Name, p.Role))
+		wp.auditCommand(p, command, false, reply)
+		return reply
+	}
+	reply := wp.runCommand(ctx, p, name, fields)
+	if need != audit.RoleViewer {
+		wp.auditCommand(p, command, true, reply)
+	}
+	return reply
+}
+
+// commandRole возвращает роль, нужную для команды из чата.
+func commandRole(name string) audit.Role {
+	switch name {
+	case "/sell", "/panic":
+		return audit.RoleTrader
+	case "/rearm":
+		return audit.RoleAdmin
+	default:
+		return audit.RoleViewer
+	}
+}
+
+// telegramPrincipal возвращает оператора from с ролью из telegram_roles. Без telegram_roles
+// любой участник настроенного чата — admin; пользователь не из списка команд не отдает.
+func (wp *WorkerPool) telegramPrincipal(from notify.Sender) audit.Principal {
+	id := strconv.FormatInt(from.ID, 10)
+	name := "telegram:" + id
+	if from.Username != "" {
+		name += " (@" + from.Username + ")"
+	}
+	if len(wp.config.TelegramRoles) == 0 {
+		return audit.Principal{Name: name, Role: audit.RoleAdmin}
+	}
+	role, _ := audit.ParseRole(wp.config.TelegramRoles[id]) // Нет в списке — RoleNone
+	return audit.Principal{Name: name, Role: role}
+}
+
+// auditCommand записывает команду из чата в журнал аудита.
+func (wp *WorkerPool) auditCommand(p audit.Principal, command string, allowed bool, result string) {
+	err := wp.audit.Record(audit.Entry{
+		Surface:   "telegram",
+		Principal: p.Name,
+		Role:      p.Role.String(),
+		Command:   command,
+		Allowed:   allowed,
+		Result:    result,
+	})
+	if err != nil {
+		wp.logger.Error("❌ Audit log write failed: " + err.Error())
+	}
+}
+
+// runCommand выполняет разрешенную команду name с аргументами fields.
+func (wp *WorkerPool) runCommand(ctx context.Context, p audit.Principal, name string, fields []string) string {
+	switch name {
+	case "/positions":
+		return wp.describePositions()
+	case "/sell":
+		if len(fields) < 3 || len(fields) > 4 {
+			return "Usage: /sell MINT PERCENT [WALLET]"
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(fields[2], "%"), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return "PERCENT must be a number in (0, 100]"
+		}
+		wallet := ""
+		if len(fields) == 4 {
+			wallet = fields[3]
+		}
+		mw, err := wp.findMonitor(fields[1], wallet)
+		if err != nil {
+			return err.Error()
+		}
+		if err := mw.RequestSell(ctx, percent); err != nil {
+			return fmt.Sprintf("Sell of %s not started: %v", mw.task.TokenMint, err)
+		}
+		wp.logger.Info(fmt.Sprintf("📨 Remote command from %s: sell %.1f%% of %s (%s)", p.Name, percent, mw.task.TokenMint, mw.task.WalletName))
+		return fmt.Sprintf("Selling %g%% of %s (%s)", percent, mw.task.TokenMint, mw.task.WalletName)
+	case "/panic":
+		return describeKillReport(wp.Engage(ctx, "telegram"))
+	case "/rearm":
+		if _, ok := wp.Rearm("telegram"); !ok {
+			return "Kill switch is not engaged"
+		}
+		return "Kill switch re-armed, tasks run again"
+	default:
+		return remoteCommandUsage
+	}
+}
+
+// findMonitor ищет мониторинг по mint (или его началу) и, если задан, кошельку.
+func (wp *WorkerPool) findMonitor(mint, wallet string) (*MonitorWorker, error) {
+	var found []*MonitorWorker
+	for _, mw := range wp.activeMonitors() {
+		if !strings.HasPrefix(mw.task.TokenMint, mint) || (wallet != "" && mw.task.WalletName != wallet) {
+			continue
+		}
+		found = append(found, mw)
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no open position for %s", mint)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("%s matches %d positions; give the full mint and the wallet", mint, len(found))
+	}
+}
+
+// describePositions перечисляет открытые позиции для ответа в чат.
+func (wp *WorkerPool) describePositions() string {
+	active := wp.activeMonitors()
+	if len(active) == 0 {
+		return "No open positions"
+	}
+	lines := make([]string, 0, len(active))
+	for _, mw := range active {
+		lines = append(lines, mw.describe())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describe описывает позицию одной строкой: токен, кошелек, вложения и PnL.
+func (mw *MonitorWorker) describe() string {
+	mw.history.mu.Lock()
+	pnl := mw.history.pnl
+	mw.history.mu.Unlock()
+	return fmt.Sprintf("%s (%s): %.3f SOL invested, PnL %.2f%% (%.4f SOL)",
+		mw.task.TokenMint, mw.task.WalletName, mw.invested(), pnl.PnLPercentage, pnl.NetPnL)
+}
@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"github.com/rovshanmuradov/solana-bot/internal/killswitch"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+func TestRemoteCommand_Roles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	wp := &WorkerPool{
+		logger:   zap.NewNop(),
+		config:   &task.Config{TelegramRoles: map[string]string{"1": "viewer", "2": "admin"}},
+		kill:     killswitch.New(),
+		audit:    audit.NewLog(path),
+		monitors: make(map[string]*MonitorWorker),
+	}
+	viewer := notify.Sender{ID: 1, Username: "alice"}
+	admin := notify.Sender{ID: 2}
+	stranger := notify.Sender{ID: 3}
+
+	for name, tc := range map[string]struct {
+		from    notify.Sender
+		command string
+		want    string
+	}{
+		"viewer reads":           {viewer, "/positions", "No open positions"},
+		"viewer cannot panic":    {viewer, "/panic", "/panic needs the trader role"},
+		"stranger reads nothing": {stranger, "/positions", "/positions needs the viewer role"},
+		"admin rearms":           {admin, "/rearm@my_bot", "Kill switch is not engaged"},
+	} {
+		assert.Equal(t, tc.want, wp.remoteCommand(context.Background(), tc.from, tc.command), name)
+	}
+
+	// В журнал попадают отказы и изменяющие команды, но не чтение
+	n, err := audit.Verify(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	assert.Equal(t, audit.Principal{Name: "telegram:1 (@alice)", Role: audit.RoleViewer}, wp.telegramPrincipal(viewer))
+	wp.config.TelegramRoles = nil
+	assert.Equal(t, audit.RoleAdmin, wp.telegramPrincipal(stranger).Role, "without telegram_roles the configured chat keeps full access")
+}
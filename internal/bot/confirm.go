@@ -0,0 +1,45 @@
+// internal/bot/confirm.go
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// confirmCommitment возвращает уровень подтверждения, после которого сделка считается успешной.
+func (wp *WorkerPool) confirmCommitment() rpc.CommitmentType {
+	if wp.config.ConfirmCommitment == "" {
+		return rpc.CommitmentProcessed
+	}
+	return rpc.CommitmentType(wp.config.ConfirmCommitment)
+}
+
+// awaitCommitment ждет, пока попавшая в блок сделка достигнет confirm_commitment.
+// DEX-адаптеры возвращаются уже на processed, поэтому до нужного уровня сделка
+// считается ожидающей: об успехе не сообщается, а позиция помечается как pending.
+func (wp *WorkerPool) awaitCommitment(ctx context.Context, t *task.Task, tr *execution.Trace) error {
+	commitment := wp.confirmCommitment()
+	rec := tr.Record()
+	if commitment == rpc.CommitmentProcessed || rec.Signature == "" || rec.ConfirmedAt.IsZero() {
+		return nil // Сделка не уходила в сеть (Sim DEX) или processed достаточно
+	}
+	sig, err := solana.SignatureFromBase58(rec.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid trade signature %q: %w", rec.Signature, err)
+	}
+
+	wp.positions.SetPending(t.TokenMint, t.WalletName, true)
+	defer wp.positions.SetPending(t.TokenMint, t.WalletName, false)
+
+	wp.logger.Info(fmt.Sprintf("⏳ %s of %s pending: waiting for %s commitment of %s...",
+		rec.Side, t.TaskName, commitment, rec.Signature[:8]))
+	if err := wp.solClient.WaitForTransactionConfirmation(ctx, sig, commitment); err != nil {
+		return fmt.Errorf("transaction %s landed but did not reach %s commitment: %w", rec.Signature, commitment, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// internal/bot/cooldown.go
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/storage"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// errRecentBuy — токен уже покупался этим кошельком в пределах buy_cooldown.
+var errRecentBuy = errors.New("token was bought recently")
+
+// acquireBuyCooldown отмечает покупку токена задачи t кошельком задачи и отказывает,
+// если такая покупка уже была в пределах buy_cooldown (кроме задач с allow_rebuy).
+// forget снимает отметку, когда покупка не состоялась.
+func (wp *WorkerPool) acquireBuyCooldown(t *task.Task, logger *zap.Logger) (forget func(), err error) {
+	if t.AllowRebuy || wp.cooldown == nil {
+		return func() {}, nil
+	}
+	key := storage.PositionKey(t.WalletName, t.TokenMint)
+	at, ok := wp.cooldown.Acquire(key)
+	if !ok {
+		ago := wp.clock.Since(at).Round(100 * time.Millisecond)
+		logger.Warn(fmt.Sprintf("🔁 Skipping buy for task %s: %s was bought from %s %s ago (buy_cooldown %s, set allow_rebuy to override)",
+			t.TaskName, t.TokenMint, t.WalletName, ago, wp.config.BuyCooldown))
+		return func() {}, fmt.Errorf("%w: %s from %s %s ago", errRecentBuy, t.TokenMint, t.WalletName, ago)
+	}
+	return func() { wp.cooldown.Release(key, at) }, nil
+}
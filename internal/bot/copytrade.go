@@ -0,0 +1,159 @@
+// internal/bot/copytrade.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// minCopySol — меньшие покупки не повторяются: комиссии съедят всю сделку.
+const minCopySol = 0.001
+
+// copyTarget — отслеживаемый кошелек из copy_trading.
+type copyTarget struct {
+	wallet    solana.PublicKey
+	template  *task.Task
+	ratio     float64
+	maxSol    float64
+	blacklist map[solana.PublicKey]bool
+
+	mu    sync.Mutex
+	spent map[solana.PublicKey]float64 // SOL, уже вложенный в каждый повторенный токен
+}
+
+// copyTrader повторяет покупки на Pump.fun отслеживаемых кошельков задачами-шаблонами
+// (token_mint = "copy"), пропорционально сумме исходной покупки.
+type copyTrader struct {
+	logs    logSource
+	targets []*copyTarget
+	queue   *dynamicTasks
+	logger  *zap.Logger
+}
+
+// newCopyTrader собирает отслеживаемые кошельки из copy_trading; записи с неверным
+// адресом или без шаблона в tasks.csv пропускаются. Возвращает nil, если следить не за кем.
+func (r *Runner) newCopyTrader(templates []*task.Task, queue *dynamicTasks) *copyTrader {
+	logger := r.logger.Named("copytrade")
+	byName := make(map[string]*task.Task)
+	for _, t := range templates {
+		if t.TokenMint == task.CopyTradeMint {
+			byName[t.TaskName] = t
+		}
+	}
+
+	c := &copyTrader{logs: r.logSource(), queue: queue, logger: logger}
+	for _, cfg := range r.config.CopyTrading {
+		wallet, err := solana.PublicKeyFromBase58(cfg.Wallet)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("⚠️  Not copying %s: invalid wallet address", cfg.Wallet))
+			continue
+		}
+		tmpl := byName[cfg.Task]
+		if tmpl == nil {
+			logger.Warn(fmt.Sprintf("⚠️  Not copying %s: tasks.csv has no row '%s' with token_mint \"copy\"", cfg.Wallet, cfg.Task))
+			continue
+		}
+		target := &copyTarget{
+			wallet:    wallet,
+			template:  tmpl,
+			ratio:     cfg.Ratio,
+			maxSol:    cfg.MaxSol,
+			blacklist: make(map[solana.PublicKey]bool),
+			spent:     make(map[solana.PublicKey]float64),
+		}
+		for _, m := range cfg.Blacklist {
+			if mint, err := solana.PublicKeyFromBase58(m); err == nil {
+				target.blacklist[mint] = true
+			} else {
+				logger.Warn(fmt.Sprintf("⚠️  Ignoring invalid blacklisted mint %s for %s", m, cfg.Wallet))
+			}
+		}
+		c.targets = append(c.targets, target)
+	}
+	if len(c.targets) == 0 {
+		return nil
+	}
+	return c
+}
+
+// Run следит за всеми кошельками, пока не отменен ctx.
+func (c *copyTrader) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, target := range c.targets {
+		c.logger.Info(fmt.Sprintf("👥 Copying Pump.fun buys of %s with '%s' (ratio %g, max %g SOL per token)",
+			target.wallet, target.template.TaskName, target.ratio, target.maxSol))
+		wg.Add(1)
+		go func(target *copyTarget) {
+			defer wg.Done()
+			listenLogs(ctx, c.logs, target.wallet, c.logger, func(ev blockchain.LogsEvent) bool {
+				c.handle(target, ev)
+				return false
+			})
+		}(target)
+	}
+	wg.Wait()
+	c.logger.Info("👥 Copy trading stopped")
+}
+
+// handle повторяет покупки кошелька target из транзакции ev.
+func (c *copyTrader) handle(target *copyTarget, ev blockchain.LogsEvent) {
+	if ev.Err != nil {
+		return
+	}
+	for _, tr := range pumpfun.ParseTradeLogs(ev.Logs) {
+		if !tr.IsBuy || !tr.User.Equals(target.wallet) {
+			continue
+		}
+		if target.blacklist[tr.Mint] {
+			c.logger.Debug(fmt.Sprintf("Buy of blacklisted %s by %s not copied", tr.Mint, target.wallet))
+			continue
+		}
+		if t := target.mirror(tr); t != nil {
+			if !c.queue.enqueue(t) {
+				target.refund(tr.Mint, t.AmountSol)
+				c.logger.Warn(fmt.Sprintf("⚠️  Copy of %s skipped: too many tasks wait for a free worker", tr.Mint))
+				continue
+			}
+			c.logger.Info(fmt.Sprintf("👥 %s bought %s for %.3f SOL, copying with %.3f SOL",
+				target.wallet, tr.Mint, float64(tr.SolAmount)/float64(solana.LAMPORTS_PER_SOL), t.AmountSol))
+		}
+	}
+}
+
+// mirror создает задачу, повторяющую покупку tr, и учитывает ее сумму в лимите max_sol.
+// Возвращает nil, если лимит по токену исчерпан или сумма слишком мала.
+func (target *copyTarget) mirror(tr pumpfun.Trade) *task.Task {
+	target.mu.Lock()
+	defer target.mu.Unlock()
+
+	amount := float64(tr.SolAmount) / float64(solana.LAMPORTS_PER_SOL) * target.ratio
+	if target.maxSol > 0 {
+		amount = min(amount, target.maxSol-target.spent[tr.Mint])
+	}
+	if amount < minCopySol {
+		return nil
+	}
+	target.spent[tr.Mint] += amount
+
+	mint := tr.Mint.String()
+	t := *target.template
+	t.TaskName = fmt.Sprintf("%s-%s", target.template.TaskName, mint[:8])
+	t.TokenMint = mint
+	t.AmountSol = amount
+	return &t
+}
+
+// refund возвращает в лимит сумму повтора, который не попал в очередь.
+func (target *copyTarget) refund(mint solana.PublicKey, amount float64) {
+	target.mu.Lock()
+	target.spent[mint] -= amount
+	target.mu.Unlock()
+}
@@ -0,0 +1,228 @@
+// internal/bot/dca.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/risk"
+	"github.com/rovshanmuradov/solana-bot/internal/storage"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// errPositionClosed — позиция, которую набирает DCA, уже закрыта.
+var errPositionClosed = errors.New("position is closed")
+
+// dcaSchedule — прогресс покупок задачи DCA для экрана позиции.
+type dcaSchedule struct {
+	mu     sync.Mutex
+	clock  clock.Clock
+	total  int       // Покупок по расписанию
+	made   int       // Выполнено покупок, включая неудачные
+	failed int       // Неудачных покупок
+	next   time.Time // Время следующей покупки (ноль — расписание завершено)
+}
+
+// record учитывает покупку и время следующей.
+func (s *dcaSchedule) record(err error, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.made++
+	if err != nil {
+		s.failed++
+	}
+	if s.made >= s.total {
+		next = time.Time{}
+	}
+	s.next = next
+}
+
+// String описывает прогресс, например "3/12 · next in 7m0s" или "12/12 (1 failed)".
+func (s *dcaSchedule) String() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line := fmt.Sprintf("%d/%d", s.made, s.total)
+	if s.failed > 0 {
+		line += fmt.Sprintf(" (%d failed)", s.failed)
+	}
+	if !s.next.IsZero() {
+		line += fmt.Sprintf(" · next in %s", max(s.next.Sub(s.clock.Now()), 0).Truncate(time.Second))
+	}
+	return line
+}
+
+// trackSchedule регистрирует расписание DCA позиции для экрана мониторинга;
+// возвращаемая функция снимает регистрацию.
+func (wp *WorkerPool) trackSchedule(t *task.Task, s *dcaSchedule) func() {
+	key := storage.PositionKey(t.WalletName, t.TokenMint)
+	wp.schedulesMu.Lock()
+	wp.schedules[key] = s
+	wp.schedulesMu.Unlock()
+
+	return func() {
+		wp.schedulesMu.Lock()
+		if wp.schedules[key] == s {
+			delete(wp.schedules, key)
+		}
+		wp.schedulesMu.Unlock()
+	}
+}
+
+// dcaSchedule возвращает расписание DCA позиции задачи (nil — позиция набирается не по DCA).
+func (wp *WorkerPool) dcaSchedule(t *task.Task) *dcaSchedule {
+	wp.schedulesMu.Lock()
+	defer wp.schedulesMu.Unlock()
+	return wp.schedules[storage.PositionKey(t.WalletName, t.TokenMint)]
+}
+
+// handleDCATask покупает токен на amount_sol сразу и затем каждые dca_interval_minutes,
+// пока не пройдет dca_minutes. Позиция мониторится с первой покупки, остальные
+// сливаются с ней; когда мониторинг завершается, расписание прекращается.
+func (wp *WorkerPool) handleDCATask(ctx context.Context, t *task.Task, dexAdapter dex.DEX, logger *zap.Logger) error {
+	buy := *t
+	buy.Operation = buyOperationFor(t.Module)
+	if _, restored := wp.restoredPosition(&buy); restored || wp.recovered(&buy, execution.SideBuy) {
+		// Прогресс расписания не сохраняется: после перезапуска только мониторим набранную позицию
+		logger.Warn("♻️  DCA schedule is not resumed after a restart, monitoring the position only: " + t.TaskName)
+		return wp.handleMonitoredTask(ctx, &buy, dexAdapter, logger)
+	}
+
+	schedule := &dcaSchedule{clock: wp.clock, total: t.DCABuys()}
+	untrack := wp.trackSchedule(&buy, schedule)
+	defer untrack()
+
+	logger.Info(fmt.Sprintf("🗓️  DCA into %s...%s: %d buys of %.3f SOL every %s",
+		t.TokenMint[:4], t.TokenMint[len(t.TokenMint)-4:], schedule.total, t.AmountSol, t.DCAInterval))
+
+	scheduleCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	err := wp.runMonitoredTask(ctx, &buy, dexAdapter, logger, func() {
+		schedule.record(nil, wp.clock.Now().Add(t.DCAInterval))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wp.runDCASchedule(scheduleCtx, ctx, &buy, schedule, dexAdapter, logger)
+		}()
+	})
+	cancel()
+	wg.Wait()
+	return err
+}
+
+// runDCASchedule выполняет оставшиеся покупки расписания, пока не отменен scheduleCtx.
+// Сами сделки идут под ctx, чтобы завершение мониторинга не обрывало отправленную покупку.
+func (wp *WorkerPool) runDCASchedule(scheduleCtx, ctx context.Context, t *task.Task, schedule *dcaSchedule, dexAdapter dex.DEX, logger *zap.Logger) {
+	ticker := wp.clock.NewTicker(t.DCAInterval)
+	defer ticker.Stop()
+
+	for n := 2; n <= schedule.total; n++ {
+		select {
+		case <-scheduleCtx.Done():
+			return
+		case <-ticker.C():
+		}
+
+		err := wp.buyDCA(ctx, t, n, dexAdapter, logger)
+		if errors.Is(err, errHalted) || errors.Is(err, errLossLimit) || errors.Is(err, risk.ErrLimitExceeded) ||
+			errors.Is(err, errInsufficientFunds) {
+			logger.Warn(fmt.Sprintf("🗓️  DCA stopped after %d/%d buys: %v (%s)", n-1, schedule.total, err, t.TaskName))
+			return
+		}
+		if errors.Is(err, errPositionClosed) {
+			logger.Info(fmt.Sprintf("🗓️  DCA stopped after %d/%d buys: the position is closed (%s)", n-1, schedule.total, t.TaskName))
+			return
+		}
+		if err != nil {
+			logger.Error(fmt.Sprintf("❌ DCA buy %d/%d failed: %v", n, schedule.total, err))
+		}
+		schedule.record(err, wp.clock.Now().Add(t.DCAInterval))
+	}
+	logger.Info(fmt.Sprintf("✅ DCA schedule complete: %s", schedule))
+}
+
+// buyDCA выполняет n-ю покупку расписания и добавляет ее к открытой позиции.
+// У каждой покупки свое имя задачи, а значит и свой ключ намерения.
+func (wp *WorkerPool) buyDCA(ctx context.Context, t *task.Task, n int, dexAdapter dex.DEX, logger *zap.Logger) error {
+	buy := *t
+	buy.TaskName = fmt.Sprintf("%s #%d", t.TaskName, n)
+
+	pos, err := wp.buyIntoPosition(ctx, t, &buy, dexAdapter, logger)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("🗓️  DCA buy %s done: %.3f SOL invested, avg entry %.10f SOL",
+		buy.TaskName, pos.Invested(), pos.EntryPrice()))
+	return nil
+}
+
+// buyIntoPosition выполняет покупку buy и добавляет ее к открытой позиции задачи t.
+// errPositionClosed — позиция закрыта до покупки или во время нее.
+func (wp *WorkerPool) buyIntoPosition(ctx context.Context, t, buy *task.Task, dexAdapter dex.DEX, logger *zap.Logger) (*position, error) {
+	if !wp.book.isOpen(t) {
+		return nil, errPositionClosed
+	}
+	if err := wp.checkHalted(buy, logger); err != nil {
+		return nil, err
+	}
+	if err := wp.checkLossLimit(buy, logger); err != nil {
+		return nil, err
+	}
+	if err := wp.checkFunds(ctx, buy, logger); err != nil {
+		wp.alertTradeFailed(buy, err)
+		return nil, err
+	}
+	if err := wp.approveOrder(ctx, buy, execution.SideBuy, buy.AmountSol, logger); err != nil {
+		return nil, err
+	}
+	if err := wp.checkHalted(buy, logger); err != nil {
+		return nil, err
+	}
+	release, err := wp.reserveBuy(buy, buy.AmountSol, logger)
+	if err != nil {
+		wp.alertTradeFailed(buy, err)
+		return nil, err
+	}
+	defer release()
+	tradeCtx, cancel := execution.WithBudget(ctx, wp.config.TradeDeadline)
+	defer cancel()
+	traceCtx, tr, err := wp.beginTrade(tradeCtx, buy, dexAdapter, execution.SideBuy)
+	if err != nil {
+		wp.alertTradeFailed(buy, err)
+		return nil, err
+	}
+	err = wp.finishTrade(tradeCtx, buy, tr, dexAdapter.Execute(traceCtx, buy))
+	wp.checkLatencyBudget(buy, tr, logger)
+	if err != nil {
+		wp.alertTradeFailed(buy, err)
+		return nil, fmt.Errorf("execute task: %w", err)
+	}
+	wp.alertTradeExecuted(buy)
+
+	balance, err := dexAdapter.GetTokenBalance(tradeCtx, t.TokenMint)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("⚠️  GetTokenBalance failed after buy %s: %v", buy.TaskName, err))
+	}
+	pos, ok := wp.book.merge(t, positionBuy{
+		Task:      buy.TaskName,
+		AmountSol: buy.AmountSol,
+		Tokens:    boughtTokens(tr.Record(), balance),
+		At:        wp.clock.Now(),
+	})
+	if !ok {
+		logger.Warn(fmt.Sprintf("⚠️  Position closed during buy %s; its tokens stay in the wallet", buy.TaskName))
+		return nil, errPositionClosed
+	}
+	wp.savePosition(pos, balance, logger)
+	return pos, nil
+}
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/killswitch"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// dcaDEX исполняет покупки без сети и копит баланс токена.
+type dcaDEX struct {
+	dex.DEX
+	mu      sync.Mutex
+	buys    int
+	balance uint64
+	fail    error // Ошибка каждой покупки (nil — покупки проходят)
+}
+
+func (d *dcaDEX) GetName() string { return "Sim" }
+
+func (d *dcaDEX) Execute(context.Context, *task.Task) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.buys++
+	if d.fail != nil {
+		return d.fail
+	}
+	d.balance += 1_000_000
+	return nil
+}
+
+func (d *dcaDEX) GetTokenBalance(context.Context, string) (uint64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.balance, nil
+}
+
+func (d *dcaDEX) executed() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.buys
+}
+
+func (s *dcaSchedule) progress() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.made
+}
+
+func TestDCASchedule_String(t *testing.T) {
+	clk := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	s := &dcaSchedule{clock: clk, total: 3}
+
+	s.record(nil, clk.Now().Add(5*time.Minute))
+	assert.Equal(t, "1/3 · next in 5m0s", s.String())
+	clk.Advance(2*time.Minute + 500*time.Millisecond)
+	assert.Equal(t, "1/3 · next in 2m59s", s.String())
+
+	s.record(errors.New("slippage"), clk.Now().Add(5*time.Minute))
+	assert.Equal(t, "2/3 (1 failed) · next in 5m0s", s.String())
+	s.record(nil, clk.Now().Add(5*time.Minute))
+	assert.Equal(t, "3/3 (1 failed)", s.String(), "a finished schedule has no next buy")
+
+	var none *dcaSchedule
+	assert.Empty(t, none.String())
+}
+
+func TestRunDCASchedule(t *testing.T) {
+	const interval = 10 * time.Minute
+	for name, tc := range map[string]struct {
+		prepare  func(wp *WorkerPool, pos *position, d *dcaDEX)
+		ticks    int
+		buys     int    // Вызовов Execute
+		made     int    // Покупок по расписанию, включая первую
+		progress string // Пусто — не проверяется
+		merged   int    // Покупок в позиции, включая первую
+	}{
+		"all buys merge into the position": {
+			prepare:  func(*WorkerPool, *position, *dcaDEX) {},
+			ticks:    2,
+			buys:     2,
+			made:     3,
+			progress: "3/3",
+			merged:   3,
+		},
+		"failed buys are counted and the schedule goes on": {
+			prepare:  func(_ *WorkerPool, _ *position, d *dcaDEX) { d.fail = errors.New("slippage exceeded") },
+			ticks:    2,
+			buys:     2,
+			made:     3,
+			progress: "3/3 (2 failed)",
+			merged:   1,
+		},
+		"closed position stops the schedule": {
+			prepare: func(wp *WorkerPool, pos *position, _ *dcaDEX) { wp.book.close(pos) },
+			ticks:   1,
+			made:    1,
+		},
+		"kill switch stops the schedule": {
+			prepare: func(wp *WorkerPool, _ *position, _ *dcaDEX) {
+				wp.kill.Engage(context.Background(), "test", func(context.Context) (int, []killswitch.PositionResult, error) {
+					return 0, nil, nil
+				})
+			},
+			ticks:  1,
+			made:   1,
+			merged: 1,
+		},
+	} {
+		clk := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+		wp := &WorkerPool{
+			logger: zap.NewNop(),
+			config: &task.Config{RPCList: []string{"https://rpc.test"}},
+			clock:  clk,
+			book:   newPositionBook(),
+			kill:   killswitch.New(),
+		}
+		dca := &task.Task{TaskName: "dca", WalletName: "main", TokenMint: "MintA", AmountSol: 0.1, DCAInterval: interval}
+		pos, _ := wp.book.add(dca, 6, positionBuy{Task: dca.TaskName, AmountSol: 0.1, Tokens: 1_000_000, At: clk.Now()})
+		d := &dcaDEX{balance: 1_000_000}
+		tc.prepare(wp, pos, d)
+
+		schedule := &dcaSchedule{clock: clk, total: 3}
+		schedule.record(nil, clk.Now().Add(interval))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			wp.runDCASchedule(context.Background(), context.Background(), dca, schedule, d, zap.NewNop())
+		}()
+		for i := 0; i < tc.ticks; i++ {
+			clk.BlockUntil(1)
+			made := schedule.progress()
+			clk.Advance(interval)
+			if i < tc.ticks-1 {
+				require.Eventually(t, func() bool { return schedule.progress() > made }, time.Second, time.Millisecond, name)
+			}
+		}
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("%s: the schedule did not stop", name)
+		}
+
+		assert.Equal(t, tc.buys, d.executed(), name)
+		assert.Equal(t, tc.made, schedule.progress(), name)
+		if tc.progress != "" {
+			assert.Equal(t, tc.progress, schedule.String(), name)
+		}
+		if tc.merged == 0 {
+			continue
+		}
+		buys := pos.Buys()
+		require.Len(t, buys, tc.merged, name)
+		if tc.merged == 3 {
+			assert.Equal(t, "dca #2", buys[1].Task, name)
+			assert.Equal(t, "dca #3", buys[2].Task, name)
+			assert.Equal(t, clk.Now(), buys[2].At, "%s: merged buys are stamped by the pool clock", name)
+			assert.InDelta(t, 0.3, pos.Invested(), 1e-9, name)
+		}
+	}
+}
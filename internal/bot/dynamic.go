@@ -0,0 +1,154 @@
+// internal/bot/dynamic.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// dynamicQueue — сколько задач, созданных во время работы, может ждать свободного воркера.
+const dynamicQueue = 16
+
+// maxReconnectDelay — потолок паузы между переподключениями подписки на логи.
+const maxReconnectDelay = 30 * time.Second
+
+// geyserRetryDelay — сколько логи идут через WebSocket после сбоя потока Geyser,
+// прежде чем Geyser пробуется снова.
+const geyserRetryDelay = time.Minute
+
+// logSource — откуда приходят логи транзакций: поток Geyser, если он настроен,
+// и WebSocket RPC как запасной путь.
+type logSource struct {
+	wsURL  string
+	geyser *blockchain.GeyserClient // nil — только WebSocket
+}
+
+// logSource возвращает источник логов из конфигурации.
+func (r *Runner) logSource() logSource {
+	return logSource{wsURL: r.config.WebSocketURL, geyser: r.geyser}
+}
+
+// dynamicTasks ставит в очередь воркеров задачи, созданные во время работы
+// (новые токены, повторенные сделки), выдавая им ID после задач из tasks.csv.
+type dynamicTasks struct {
+	ch     chan<- *task.Task
+	mu     sync.Mutex
+	lastID int
+}
+
+// enqueue ставит задачу в очередь; false — очередь заполнена и задача пропущена:
+// сделка, дождавшаяся воркера слишком поздно, уже не повторяет исходную.
+func (d *dynamicTasks) enqueue(t *task.Task) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastID++
+	t.ID = d.lastID
+	t.CreatedAt = time.Now()
+	select {
+	case d.ch <- t:
+		return true
+	default:
+		return false
+	}
+}
+
+// listenLogs подписывается на логи транзакций, упоминающих mention, и переподключается
+// при обрывах, пока не отменен ctx или onLogs не вернет true. При сбое потока Geyser
+// логи на geyserRetryDelay переходят на WebSocket.
+func listenLogs(ctx context.Context, src logSource, mention solana.PublicKey, logger *zap.Logger, onLogs func(blockchain.LogsEvent) bool) {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	handle := func(ev blockchain.LogsEvent) {
+		if onLogs(ev) {
+			stop()
+		}
+	}
+
+	delay := time.Second
+	var geyserDownUntil time.Time
+	for ctx.Err() == nil {
+		if src.geyser != nil && !time.Now().Before(geyserDownUntil) {
+			err := src.geyser.SubscribeTransactions(ctx, mention, rpc.CommitmentProcessed, handle)
+			if ctx.Err() != nil {
+				return
+			}
+			geyserDownUntil = time.Now().Add(geyserRetryDelay)
+			logger.Warn(fmt.Sprintf("⚠️  Geyser stream for %s lost, using WebSocket for %v: %v", mention, geyserRetryDelay, err))
+			continue
+		}
+
+		wsCtx, cancel := ctx, context.CancelFunc(func() {})
+		if src.geyser != nil {
+			wsCtx, cancel = context.WithDeadline(ctx, geyserDownUntil)
+		}
+		started := time.Now()
+		err := blockchain.SubscribeLogs(wsCtx, src.wsURL, mention, rpc.CommitmentProcessed, handle)
+		retryGeyser := wsCtx.Err() != nil
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if retryGeyser {
+			continue
+		}
+		if time.Since(started) > time.Minute {
+			delay = time.Second
+		}
+		logger.Warn(fmt.Sprintf("⚠️  Logs subscription for %s lost, reconnecting in %v: %v", mention, delay, err))
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// startDynamicTasks запускает источники задач, появляющихся во время работы: слушателя
+// новых токенов и повторение сделок по шаблонам, а также задачи с start_at в их время.
+// Очередь закрывается, когда все источники остановятся.
+func (r *Runner) startDynamicTasks(ctx context.Context, templates, scheduled []*task.Task, taskCh chan *task.Task, lastID int) {
+	queue := &dynamicTasks{ch: taskCh, lastID: lastID}
+	var sources []func(context.Context)
+	if len(scheduled) > 0 {
+		sources = append(sources, func(ctx context.Context) { r.runScheduledTasks(ctx, scheduled, taskCh) })
+	}
+
+	var snipes []*task.Task
+	for _, t := range templates {
+		if t.TokenMint == task.NewTokenMint {
+			snipes = append(snipes, t)
+		}
+	}
+	if len(snipes) > 0 {
+		sources = append(sources, r.newTokenSniper(snipes, queue).Run)
+	}
+	if copier := r.newCopyTrader(templates, queue); copier != nil {
+		sources = append(sources, copier.Run)
+	}
+
+	if len(sources) == 0 {
+		close(taskCh)
+		return
+	}
+	var wg sync.WaitGroup
+	for _, run := range sources {
+		wg.Add(1)
+		go func(run func(context.Context)) {
+			defer wg.Done()
+			run(ctx)
+		}(run)
+	}
+	go func() {
+		wg.Wait()
+		close(taskCh)
+	}()
+}
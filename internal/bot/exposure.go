@@ -0,0 +1,66 @@
+// internal/bot/exposure.go
+package bot
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/risk"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// exposureReservations — покупки, которые уже проверены ограничениями размера позиций,
+// но еще не вошли в открытые позиции: параллельные задачи не должны проскочить лимит вместе.
+type exposureReservations struct {
+	mu   sync.Mutex
+	next int
+	open map[int]risk.Exposure
+}
+
+// sizeLimits возвращает ограничения размера позиций из config.json.
+func (wp *WorkerPool) sizeLimits() risk.Limits {
+	return risk.Limits{
+		MaxTokenSol:    wp.config.MaxTokenSol,
+		MaxPositions:   wp.config.MaxOpenPositions,
+		MaxExposureSol: wp.config.MaxExposureSol,
+	}
+}
+
+// reserveBuy проверяет покупку amountSol SOL задачей t по ограничениям размера позиций
+// и резервирует ее. release снимает резерв; его вызывают, когда покупка вошла в
+// позицию или не состоялась (повторный вызов безопасен).
+func (wp *WorkerPool) reserveBuy(t *task.Task, amountSol float64, logger *zap.Logger) (release func(), err error) {
+	limits := wp.sizeLimits()
+	if !limits.Enabled() {
+		return func() {}, nil
+	}
+	buy := risk.Exposure{Mint: t.TokenMint, Wallet: t.WalletName, Sol: amountSol}
+
+	r := &wp.reservations
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	open := wp.book.exposure()
+	for _, e := range r.open {
+		open = append(open, e)
+	}
+	if err := limits.Check(open, buy); err != nil {
+		logger.Warn(fmt.Sprintf("🚫 Buy rejected for task %s: %v", t.TaskName, err))
+		return func() {}, err
+	}
+
+	if r.open == nil {
+		r.open = make(map[int]risk.Exposure)
+	}
+	r.next++
+	id := r.next
+	r.open[id] = buy
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.open, id)
+			r.mu.Unlock()
+		})
+	}, nil
+}
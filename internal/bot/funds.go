@@ -0,0 +1,132 @@
+// internal/bot/funds.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// errInsufficientFunds — на кошельке или у плательщика комиссий не хватает SOL на покупку.
+var errInsufficientFunds = errors.New("insufficient SOL")
+
+// checkFunds до отправки покупки проверяет, что SOL хватит на сумму покупки, депозит
+// за новый токен-аккаунт и комиссию транзакции, и отказывает с точной нехваткой вместо
+// общей ошибки транзакции. Если баланс узнать не удалось, покупка не блокируется.
+func (wp *WorkerPool) checkFunds(ctx context.Context, t *task.Task, logger *zap.Logger) error {
+	w := wp.wallets[t.WalletName]
+	if w == nil || dex.IsSimulated(t.Module) {
+		return nil
+	}
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		return fmt.Errorf("invalid token mint: %w", err)
+	}
+	client, err := wp.clientFor(t)
+	if err != nil {
+		return err
+	}
+
+	funds, err := wp.estimateFunds(ctx, client, t, w, mint)
+	if err != nil {
+		logger.Warn("⚠️  Could not estimate buy costs: " + err.Error())
+		return nil
+	}
+
+	// Депозит и комиссию платит плательщик комиссий; внешний сервис проверить нельзя
+	need := funds.Total()
+	payer := w.FeePayer
+	if payer != nil || w.Sponsor != nil {
+		need = funds.Amount
+	}
+	balance, err := client.GetBalance(ctx, w.PublicKey, rpc.CommitmentConfirmed)
+	if err != nil {
+		logger.Warn("⚠️  Could not check wallet balance before buying: " + err.Error())
+		return nil
+	}
+	if short := execution.Shortfall(balance, need); short > 0 {
+		costs := funds.String()
+		if need != funds.Total() {
+			costs = fmt.Sprintf("buy %.6f SOL", lamportsToSol(funds.Amount))
+		}
+		return fundsError(t, "wallet "+t.WalletName, costs, need, balance, short, logger)
+	}
+
+	if payer != nil {
+		payerBalance, err := client.GetBalance(ctx, payer.PublicKey, rpc.CommitmentConfirmed)
+		if err != nil {
+			logger.Warn("⚠️  Could not check fee payer balance before buying: " + err.Error())
+			return nil
+		}
+		if short := execution.Shortfall(payerBalance, funds.PayerCosts()); short > 0 {
+			costs := fmt.Sprintf("token account rent %.6f + network fee %.6f SOL",
+				lamportsToSol(funds.Rent), lamportsToSol(funds.NetworkFee))
+			return fundsError(t, "fee payer "+payer.Name, costs, funds.PayerCosts(), payerBalance, short, logger)
+		}
+	}
+	return nil
+}
+
+// estimateFunds оценивает стоимость покупки: депозит нужен, если у кошелька еще нет
+// токен-аккаунта для этого mint, priority fee в режиме "auto" запрашивается у провайдера.
+func (wp *WorkerPool) estimateFunds(ctx context.Context, client *blockchain.Client, t *task.Task, w *task.Wallet, mint solana.PublicKey) (execution.Funds, error) {
+	funds := execution.Funds{Amount: model.SolToLamports(t.AmountSol)}
+
+	// ATA зависит от программы токена, поэтому запрашиваются оба варианта вместе с mint
+	classic, _, err := solana.FindAssociatedTokenAddress(w.PublicKey, mint)
+	if err != nil {
+		return funds, fmt.Errorf("derive token account: %w", err)
+	}
+	ext, err := blockchain.FindAssociatedTokenAddress(w.PublicKey, mint, blockchain.Token2022ProgramID)
+	if err != nil {
+		return funds, fmt.Errorf("derive token account: %w", err)
+	}
+	res, err := client.GetMultipleAccounts(ctx, []solana.PublicKey{mint, classic, ext})
+	if err != nil {
+		return funds, fmt.Errorf("get token accounts: %w", err)
+	}
+	if len(res.Value) != 3 || res.Value[0] == nil {
+		return funds, fmt.Errorf("mint account %s not found", mint)
+	}
+	if res.Value[0].Owner.Equals(blockchain.Token2022ProgramID) {
+		if res.Value[2] == nil {
+			funds.Rent = execution.Token2022AccountRent
+		}
+	} else if res.Value[1] == nil {
+		funds.Rent = execution.TokenAccountRent
+	}
+
+	price, ok := execution.PriorityFeePrice(t.PriorityFeeSol)
+	if !ok {
+		if price, err = client.PriorityFee(ctx, t.PriorityFeeSol, []solana.PublicKey{mint}); err != nil {
+			price = execution.DefaultPriorityFeePrice // Площадка подставит то же значение
+		}
+	}
+	units := t.ComputeUnits
+	if units == 0 {
+		units = execution.DefaultComputeUnits
+	}
+	funds.NetworkFee = execution.NetworkFee(price, units)
+	return funds, nil
+}
+
+// fundsError описывает нехватку SOL для лога и итогов задачи.
+func fundsError(t *task.Task, who, costs string, need, have, short uint64, logger *zap.Logger) error {
+	logger.Error(fmt.Sprintf("💸 Not buying %s for task %s: %s has %.6f SOL, needs %.6f SOL (%s), short by %.6f SOL",
+		t.TokenMint, t.TaskName, who, lamportsToSol(have), lamportsToSol(need), costs, lamportsToSol(short)))
+	return fmt.Errorf("%w: %s has %.6f SOL, needs %.6f SOL (%s), short by %.6f SOL",
+		errInsufficientFunds, who, lamportsToSol(have), lamportsToSol(need), costs, lamportsToSol(short))
+}
+
+func lamportsToSol(lamports uint64) float64 {
+	return float64(lamports) / float64(solana.LAMPORTS_PER_SOL)
+}
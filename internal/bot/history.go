@@ -0,0 +1,122 @@
+// internal/bot/history.go
+package bot
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+)
+
+// sessionHistory накапливает жизненный цикл сессии мониторинга, чтобы после
+// закрытия сохранить его в архив. Обновляется из горутин цены и UI.
+type sessionHistory struct {
+	archive *execution.SessionArchive
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	events  []execution.SessionEvent
+	outcome string
+	update  monitor.PriceUpdate
+	pnl     model.PnLResult
+}
+
+// event добавляет шаг жизненного цикла.
+func (h *sessionHistory) event(kind, detail string) {
+	h.mu.Lock()
+	h.events = append(h.events, execution.SessionEvent{At: h.clock.Now(), Kind: kind, Detail: detail})
+	h.mu.Unlock()
+}
+
+// observe запоминает последнее состояние цены и PnL.
+func (h *sessionHistory) observe(update monitor.PriceUpdate, pnl model.PnLResult) {
+	h.mu.Lock()
+	h.update, h.pnl = update, pnl
+	h.mu.Unlock()
+}
+
+// finish фиксирует итог сессии; учитывается только первый итог.
+func (h *sessionHistory) finish(outcome, detail string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.outcome != "" {
+		return
+	}
+	h.outcome = outcome
+	h.events = append(h.events, execution.SessionEvent{At: h.clock.Now(), Kind: outcome, Detail: detail})
+}
+
+// result возвращает итог сессии; "" — сессия еще не завершилась.
+func (h *sessionHistory) result() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.outcome
+}
+
+// archiveSession сохраняет закрытую сессию вместе с покупками позиции.
+func (mw *MonitorWorker) archiveSession() {
+	h := mw.history
+	if h.archive == nil {
+		return
+	}
+	h.finish(execution.OutcomeStopped, "")
+
+	s := execution.Session{
+		ID:         execution.SessionID(mw.task.TokenMint, mw.openedAt),
+		TaskName:   mw.task.TaskName,
+		Tasks:      []string{mw.task.TaskName},
+		Mint:       mw.task.TokenMint,
+		Wallet:     mw.task.WalletName,
+		Venue:      mw.dex.GetName(),
+		OpenedAt:   mw.openedAt,
+		ClosedAt:   mw.clock.Now(),
+		Invested:   mw.invested(),
+		EntryPrice: mw.entryPrice(),
+	}
+
+	var buys []execution.SessionEvent
+	if mw.pos != nil {
+		s.Tasks = s.Tasks[:0]
+		for _, b := range mw.pos.Buys() {
+			detail := fmt.Sprintf("%s %.3f SOL, %d tokens", b.Task, b.AmountSol, b.Tokens)
+			buys = append(buys, execution.SessionEvent{At: b.At, Kind: "buy", Detail: detail})
+			if !slices.Contains(s.Tasks, b.Task) {
+				s.Tasks = append(s.Tasks, b.Task)
+			}
+		}
+		if !slices.Contains(s.Tasks, mw.task.TaskName) {
+			s.Tasks = append(s.Tasks, mw.task.TaskName)
+		}
+	}
+
+	h.mu.Lock()
+	s.LastPrice = h.update.Current
+	s.PnLSol = h.pnl.NetPnL
+	s.PnLPercent = h.pnl.PnLPercentage
+	s.Outcome = h.outcome
+	s.Events = append(buys, h.events...)
+	h.mu.Unlock()
+
+	if err := h.archive.Archive(s); err != nil {
+		mw.logger.Warn("⚠️  Failed to archive monitoring session: " + err.Error())
+		return
+	}
+	mw.logger.Info(fmt.Sprintf("🗄️  Session archived: %s (%s, %.2f%%)", s.ID, s.Outcome, s.PnLPercent))
+}
+
+// entryPrice возвращает цену входа позиции: средневзвешенную по покупкам,
+// иначе начальную цену мониторинга.
+func (mw *MonitorWorker) entryPrice() float64 {
+	if mw.pos != nil {
+		if entry := mw.pos.EntryPrice(); entry > 0 {
+			return entry
+		}
+	}
+	mw.history.mu.Lock()
+	defer mw.history.mu.Unlock()
+	return mw.history.update.Initial
+}
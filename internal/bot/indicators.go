@@ -0,0 +1,57 @@
+// internal/bot/indicators.go
+package bot
+
+import (
+	"fmt"
+
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// indicatorAlert уведомляет, когда RSI позиции выше порога, а цена перестала расти.
+// Срабатывает один раз и взводится снова, когда RSI опускается ниже порога.
+type indicatorAlert struct {
+	threshold float64
+	notifier  *notify.Notifier
+	logger    *zap.Logger
+	fired     bool
+}
+
+// newIndicatorAlert возвращает nil, если порог indicator_rsi_alert не задан
+func (wp *WorkerPool) newIndicatorAlert(logger *zap.Logger) *indicatorAlert {
+	if wp.config.IndicatorRSIAlert <= 0 {
+		return nil
+	}
+	return &indicatorAlert{
+		threshold: wp.config.IndicatorRSIAlert,
+		notifier:  wp.notifier,
+		logger:    logger,
+	}
+}
+
+// check проверяет условие по свежим значениям индикаторов
+func (a *indicatorAlert) check(t *task.Task, s monitor.IndicatorSnapshot) {
+	if a == nil || !s.Ready {
+		return
+	}
+	if s.RSI < a.threshold {
+		a.fired = false
+		return
+	}
+	if a.fired || !s.Stalled {
+		return
+	}
+	a.fired = true
+
+	msg := fmt.Sprintf("RSI %.1f above %.0f and price stalling for %s (%s): trend %s, volatility %.2f%%/tick",
+		s.RSI, a.threshold, t.TokenMint, t.WalletName, s.Trend(), s.Volatility)
+	a.logger.Info("📐 " + msg)
+	a.notifier.Notify(notify.Alert{
+		Type:     notify.AlertIndicator,
+		Key:      t.TokenMint,
+		Severity: notify.SeverityWarning,
+		Message:  msg,
+	})
+}
@@ -0,0 +1,46 @@
+// internal/bot/instance.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+)
+
+// acquireInstanceLock занимает loopback-порт, гарантируя один торгующий процесс на машине.
+// Порт освобождается ОС автоматически при любом завершении процесса, поэтому
+// "зависших" lock-файлов не бывает.
+func acquireInstanceLock(port int) (net.Listener, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("another solana-bot instance is already running (port %d is in use); "+
+			"stop it or start this process with -read-only to observe: %w", port, err)
+	}
+	return ln, nil
+}
+
+// runReadOnly показывает балансы и отчет об исполнении, затем следит за сделками,
+// которые записывает торгующий процесс, не отправляя ни одной транзакции.
+func (r *Runner) runReadOnly(ctx context.Context) error {
+	r.logger.Info("👁️  Read-only mode: trading disabled, observing execution log")
+
+	r.logWalletBalances(ctx)
+	r.logWatchlist(ctx)
+	r.logExecutionReport()
+
+	err := r.recorder.Store().Follow(ctx, time.Second, func(rec execution.Record) {
+		status := "✅"
+		if !rec.Success() {
+			status = "❌"
+		}
+		r.logger.Info(fmt.Sprintf("%s %s %s on %s (%s) in %s",
+			status, rec.TaskName, rec.Side, rec.Venue, rec.Mint, rec.Latency().Round(time.Millisecond)))
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
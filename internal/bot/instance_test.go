@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+)
+
+// freePort возвращает loopback-порт, который только что был свободен.
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	require.NoError(t, ln.Close())
+	return port
+}
+
+func TestAcquireInstanceLock(t *testing.T) {
+	port := freePort(t)
+	lock, err := acquireInstanceLock(port)
+	require.NoError(t, err)
+
+	_, err = acquireInstanceLock(port)
+	require.Error(t, err, "a second trading process on the same port must be refused")
+	assert.True(t, strings.HasPrefix(err.Error(), fmt.Sprintf(
+		"another solana-bot instance is already running (port %d is in use); stop it or start this process with -read-only to observe: ", port)), err.Error())
+
+	// После завершения первого процесса порт снова свободен
+	require.NoError(t, lock.Close())
+	lock, err = acquireInstanceLock(port)
+	require.NoError(t, err)
+	require.NoError(t, lock.Close())
+}
+
+func TestRunReadOnly_FollowsTradingProcess(t *testing.T) {
+	store := execution.NewStore(filepath.Join(t.TempDir(), "executions.jsonl"))
+	core, logs := observer.New(zap.InfoLevel)
+	r := &Runner{
+		logger:   zap.New(core),
+		balances: stubBalances{},
+		recorder: execution.NewRecorder(nil, store, zap.NewNop()),
+	}
+
+	// Сделка, записанная до запуска наблюдателя, попадает только в отчет
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, store.Append(execution.Record{TaskName: "old", Side: "buy", StartedAt: old, ConfirmedAt: old}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.runReadOnly(ctx) }()
+
+	// Дожидаемся начала наблюдения: сделки до него не выводятся
+	require.Eventually(t, func() bool {
+		return logs.FilterMessageSnippet("Read-only mode").Len() == 1
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	started := time.Now()
+	require.NoError(t, store.Append(execution.Record{
+		TaskName: "snipe", Side: "buy", Venue: "pump.fun", Mint: "Mint",
+		StartedAt: started, ConfirmedAt: started.Add(420 * time.Millisecond),
+	}))
+	assert.Eventually(t, func() bool {
+		return logs.FilterMessage("✅ snipe buy on pump.fun (Mint) in 420ms").Len() == 1
+	}, 3*time.Second, 20*time.Millisecond)
+
+	assert.Zero(t, logs.FilterMessageSnippet("old buy").Len())
+
+	cancel()
+	assert.NoError(t, <-done, "stopping the observer is not an error")
+}
@@ -0,0 +1,137 @@
+// internal/bot/intents.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// blockhashLifetime — сколько после отправки транзакция еще может попасть в блок:
+// время жизни blockhash с запасом.
+const blockhashLifetime = 90 * time.Second
+
+// intentSettleWindow — сколько после последней отправки ждать итога незавершенной сделки.
+// Копия со свежим blockhash могла уйти до resubmit_deadline после первой отправки
+// и не успеть попасть в журнал, поэтому окно покрывает и ее.
+func intentSettleWindow(resubmitDeadline time.Duration) time.Duration {
+	return resubmitDeadline + blockhashLifetime
+}
+
+// reconcileIntents сверяет с сетью сделки, оборвавшиеся при прошлом завершении процесса.
+// Возвращает ключи намерений, которые нельзя исполнять повторно: сделка прошла
+// или ее судьбу не удалось проверить.
+func (r *Runner) reconcileIntents(ctx context.Context) map[string]bool {
+	pending, err := r.intents.Pending()
+	if err != nil {
+		r.logger.Warn("⚠️  Failed to read intent log: " + err.Error())
+		return nil
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	r.logger.Warn(fmt.Sprintf("🧾 Reconciling %d unfinished trades from the previous run", len(pending)))
+	recovered := make(map[string]bool)
+	settle := intentSettleWindow(r.config.ResubmitDeadline)
+	for _, in := range pending {
+		if wait := settle - r.clock.Since(in.LastActivity()); wait > 0 {
+			r.logger.Info(fmt.Sprintf("⏳ Waiting %s for in-flight %s of %s to settle", wait.Round(time.Second), in.Side, in.TaskName))
+			select {
+			case <-r.clock.After(wait):
+			case <-ctx.Done():
+				return recovered
+			}
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		sig, err := execution.Reconcile(checkCtx, r.solClient, in)
+		cancel()
+
+		switch {
+		case err != nil:
+			// Намерение остается незавершенным и будет проверено при следующем запуске
+			recovered[in.Key] = true
+			r.logger.Error(fmt.Sprintf("❌ Could not verify %s of %s (%s), not repeating it: %v", in.Side, in.TaskName, in.Mint, err))
+		case sig == "":
+			_ = r.intents.Resolve(in.Key, execution.IntentAbandoned)
+			r.logger.Info(fmt.Sprintf("🧾 %s of %s did not land before the restart; it will run again", in.Side, in.TaskName))
+		default:
+			recovered[in.Key] = true
+			_ = r.intents.Resolve(in.Key, execution.IntentLanded)
+			msg := fmt.Sprintf("%s of %s (%s, %s) landed after the previous run stopped: %s",
+				in.Side, in.TaskName, in.Mint, in.Wallet, sig)
+			r.logger.Warn("♻️  " + msg)
+			r.notifier.Notify(notify.Alert{
+				Type:     notify.AlertTradeRecovered,
+				Key:      in.Key,
+				Severity: notify.SeverityWarning,
+				Message:  msg,
+			})
+		}
+	}
+
+	if err := r.intents.Compact(); err != nil {
+		r.logger.Warn("⚠️  Failed to compact intent log: " + err.Error())
+	}
+	return recovered
+}
+
+// intentKey возвращает ключ идемпотентности сделки задачи
+func intentKey(t *task.Task, side string) string {
+	return execution.IntentKey(t.TaskName, side, t.TokenMint, t.WalletName, t.AmountSol)
+}
+
+// recovered сообщает, что сделка задачи уже прошла до перезапуска
+func (wp *WorkerPool) recovered(t *task.Task, side string) bool {
+	return wp.recoveredIntents[intentKey(t, side)]
+}
+
+// beginTrade записывает намерение сделки до отправки транзакции и начинает ее трассировку.
+// Если намерение сохранить не удалось, сделка не выполняется.
+func (wp *WorkerPool) beginTrade(ctx context.Context, t *task.Task, dexAdapter dex.DEX, side string) (context.Context, *execution.Trace, error) {
+	key := intentKey(t, side)
+	in := execution.Intent{
+		Key:       key,
+		TaskName:  t.TaskName,
+		Side:      side,
+		Mint:      t.TokenMint,
+		Wallet:    t.WalletName,
+		AmountSol: t.AmountSol,
+	}
+	if w := wp.wallets[t.WalletName]; w != nil {
+		in.Owner = w.PublicKey.String()
+	}
+	if err := wp.intents.Open(in); err != nil {
+		return ctx, nil, fmt.Errorf("write trade intent: %w", err)
+	}
+
+	traceCtx, tr := wp.startTrace(ctx, t, dexAdapter, side)
+	tr.OnSent(func(sig solana.Signature) {
+		if err := wp.intents.MarkSent(key, sig.String()); err != nil {
+			wp.logger.Warn("⚠️  Failed to record sent signature", zap.Error(err))
+		}
+	})
+	return traceCtx, tr, nil
+}
+
+// finishTrade дожидается confirm_commitment в рамках бюджета сделки, сохраняет метрики
+// исполнения и закрывает намерение сделки. Метрики дочитываются и после исчерпания
+// бюджета. Возвращает итоговую ошибку сделки.
+func (wp *WorkerPool) finishTrade(ctx context.Context, t *task.Task, tr *execution.Trace, err error) error {
+	if err == nil {
+		err = wp.awaitCommitment(ctx, t, tr)
+	}
+	wp.recorder.Finish(context.WithoutCancel(ctx), tr, err)
+	if rerr := wp.intents.Resolve(intentKey(t, tr.Record().Side), execution.IntentDone); rerr != nil {
+		wp.logger.Warn("⚠️  Failed to resolve trade intent", zap.Error(rerr))
+	}
+	return err
+}
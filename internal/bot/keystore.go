@@ -0,0 +1,132 @@
+// internal/bot/keystore.go
+package bot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+)
+
+// KeystorePath — зашифрованное хранилище кошельков. Если файл есть, кошельки
+// загружаются из него, а wallets.csv не читается.
+const KeystorePath = "configs/wallets.keystore"
+
+// JournalKeyPath — соль и параметры scrypt ключа, которым шифруются журналы позиций
+// и сделок. Без него зашифрованные строки журналов не прочитать.
+const JournalKeyPath = "configs/journal.key"
+
+// KeystorePasswordEnv — переменная окружения с паролем хранилища для запуска без терминала.
+const KeystorePasswordEnv = "SOLANA_BOT_KEYSTORE_PASSWORD"
+
+// ReadKeystorePassword берет пароль хранилища из KeystorePasswordEnv или спрашивает
+// его в терминале без эха.
+func ReadKeystorePassword(prompt string) ([]byte, error) {
+	if p := os.Getenv(KeystorePasswordEnv); p != "" {
+		return []byte(p), nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("no terminal to ask for the keystore password; set %s", KeystorePasswordEnv)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("read keystore password: %w", err)
+	}
+	return password, nil
+}
+
+// ConfirmKeystoreOverwrite спрашивает в терминале, заменить ли уже существующее хранилище
+// KeystorePath. Без терминала замена возможна только с force.
+func ConfirmKeystoreOverwrite(force bool) error {
+	if _, err := os.Stat(KeystorePath); err != nil || force {
+		return nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("%s already exists; pass -keystore-force to replace it", KeystorePath)
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  %s already exists; wallets that are not in the CSV will be lost. Overwrite it? [y/N] ", KeystorePath)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil || strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		return fmt.Errorf("%s left unchanged", KeystorePath)
+	}
+	return nil
+}
+
+// loadWallets загружает кошельки из хранилища KeystorePath, а без него — из wallets.csv.
+// encrypted = true, если кошельки взяты из хранилища. Тем же паролем открывается шифр
+// журналов (nil — журналы не шифруются).
+func loadWallets(cfg *task.Config, logger *zap.Logger) (wallets map[string]*task.Wallet, encrypted bool, journals *task.JournalCipher, err error) {
+	if _, err := os.Stat(KeystorePath); err != nil {
+		if cfg.EncryptJournals {
+			return nil, false, nil, fmt.Errorf("encrypt_journals needs the encrypted keystore; create it with -keystore-import")
+		}
+		if _, err := os.Stat(JournalKeyPath); err == nil {
+			return nil, false, nil, fmt.Errorf("journals are encrypted with %s, but %s is missing", JournalKeyPath, KeystorePath)
+		}
+		wallets, err := task.LoadWallets(walletsPath)
+		return wallets, false, nil, err
+	}
+
+	password, err := ReadKeystorePassword("🔐 Keystore password: ")
+	if err != nil {
+		return nil, true, nil, err
+	}
+	defer task.Zero(password)
+
+	wallets, err = task.LoadKeystoreWallets(KeystorePath, password)
+	if err != nil {
+		return nil, true, nil, err
+	}
+	logger.Info(fmt.Sprintf("🔐 Loaded %d wallets from the encrypted keystore", len(wallets)))
+	if _, err := os.Stat(walletsPath); err == nil {
+		logger.Warn("⚠️  " + walletsPath + " still holds plaintext keys; delete it once the keystore works")
+	}
+	if journals, err = journalCipher(cfg.EncryptJournals, password); err != nil {
+		task.ZeroWallets(wallets)
+		return nil, true, nil, err
+	}
+	if cfg.EncryptJournals {
+		logger.Info("🔐 Position and trade journals are encrypted")
+	}
+	return wallets, true, journals, nil
+}
+
+// journalCipher открывает шифр журналов паролем хранилища: на запись, если включен
+// encrypt_journals, и только на чтение, если шифрование выключили после того, как
+// оно было включено. nil — журналы никогда не шифровались.
+func journalCipher(encrypt bool, password []byte) (*task.JournalCipher, error) {
+	if _, err := os.Stat(JournalKeyPath); err != nil && !encrypt {
+		return nil, nil
+	}
+	c, err := task.OpenJournalCipher(JournalKeyPath, password)
+	if err != nil {
+		return nil, fmt.Errorf("open journal key %s: %w", JournalKeyPath, err)
+	}
+	if !encrypt {
+		return c.ReadOnly(), nil
+	}
+	return c, nil
+}
+
+// OpenJournals открывает шифр журналов для команд, читающих их без запуска бота
+// (-export-trades, -tax-report). Пароль хранилища спрашивается, только если журналы
+// шифровались; nil — журналы не зашифрованы.
+func OpenJournals() (*task.JournalCipher, error) {
+	if _, err := os.Stat(JournalKeyPath); err != nil {
+		return nil, nil
+	}
+	password, err := ReadKeystorePassword("🔐 Keystore password (journals are encrypted): ")
+	if err != nil {
+		return nil, err
+	}
+	defer task.Zero(password)
+	return journalCipher(false, password)
+}
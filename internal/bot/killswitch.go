@@ -0,0 +1,177 @@
+// internal/bot/killswitch.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/killswitch"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/orders"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// errHalted — kill switch остановил выполнение задач до rearm.
+var errHalted = errors.New("kill switch is engaged")
+
+// panicButton — kill switch для команд из консоли монитора (*WorkerPool).
+type panicButton interface {
+	Engage(ctx context.Context, source string) killswitch.Report
+	Rearm(source string) (killswitch.Status, bool)
+}
+
+// Engage включает kill switch: отменяет ожидающие лимитные ордера, продает все
+// открытые позиции по рынку и не выполняет новые задачи до Rearm. Повторный вызов
+// продает только то, что еще осталось открытым.
+func (wp *WorkerPool) Engage(ctx context.Context, source string) killswitch.Report {
+	wp.logger.Warn("🛑 Kill switch engaged from " + source + ": canceling orders and selling all positions")
+	report := wp.kill.Engage(ctx, source, wp.flatten)
+
+	if report.OrdersError != "" {
+		wp.logger.Error("❌ Kill switch could not cancel orders: " + report.OrdersError)
+	}
+	for _, p := range report.Positions {
+		line := fmt.Sprintf("%s (%s): %s", p.Mint, p.Wallet, p.Result)
+		if p.Error != "" {
+			wp.logger.Error("   ❌ " + line + ": " + p.Error)
+			continue
+		}
+		wp.logger.Info("   🛑 " + line)
+	}
+
+	wp.notifier.Notify(notify.Alert{
+		Type:     notify.AlertKillSwitch,
+		Severity: notify.SeverityCritical,
+		Message:  describeKillReport(report),
+	})
+	return report
+}
+
+// Rearm снова разрешает выполнение задач после Engage; false — kill switch не был включен.
+func (wp *WorkerPool) Rearm(source string) (killswitch.Status, bool) {
+	status, ok := wp.kill.Rearm()
+	if ok {
+		wp.logger.Info("✅ Kill switch re-armed from " + source + ": tasks run again")
+	}
+	return status, ok
+}
+
+// KillSwitchStatus возвращает состояние kill switch.
+func (wp *WorkerPool) KillSwitchStatus() killswitch.Status {
+	return wp.kill.Status()
+}
+
+// flatten отменяет ожидающие ордера и параллельно продает каждую открытую позицию целиком.
+func (wp *WorkerPool) flatten(ctx context.Context) (int, []killswitch.PositionResult, error) {
+	canceled, ordersErr := wp.cancelPendingOrders()
+
+	active := wp.activeMonitors()
+	results := make([]killswitch.PositionResult, len(active))
+	var wg sync.WaitGroup
+	for i, mw := range active {
+		wg.Add(1)
+		go func(i int, mw *MonitorWorker) {
+			defer wg.Done()
+			result := killswitch.PositionResult{Mint: mw.task.TokenMint, Wallet: mw.task.WalletName, Result: killswitch.ResultSold}
+			switch err := mw.SellAll(ctx); {
+			case errors.Is(err, errMonitorStopped):
+				result.Result = killswitch.ResultClosing
+			case err != nil:
+				result.Result, result.Error = killswitch.ResultFailed, err.Error()
+			}
+			results[i] = result
+		}(i, mw)
+	}
+	wg.Wait()
+	return canceled, results, ordersErr
+}
+
+// sellHalted продает позицию, открытую, когда kill switch уже был включен: снимок
+// открытых позиций в flatten ее не застал.
+func (wp *WorkerPool) sellHalted(mw *MonitorWorker) {
+	mw.logger.Warn("🛑 Kill switch is engaged, selling the position bought meanwhile: " + mw.task.TaskName)
+	if err := mw.SellAll(mw.ctx); err != nil && !errors.Is(err, errMonitorStopped) {
+		mw.logger.Error("❌ Kill switch could not sell " + mw.task.TokenMint + ": " + err.Error())
+	}
+}
+
+// cancelPendingOrders отменяет ожидающие лимитные ордера; задачи, которые их ждут,
+// замечают отмену при следующей сверке с журналом.
+func (wp *WorkerPool) cancelPendingOrders() (int, error) {
+	pending, err := wp.orders.Pending()
+	if err != nil {
+		return 0, fmt.Errorf("list pending orders: %w", err)
+	}
+	canceled := 0
+	for _, o := range pending {
+		if err := wp.orders.Resolve(o.ID, orders.StatusCanceled, "kill switch"); err != nil {
+			return canceled, fmt.Errorf("cancel order %s: %w", o.ID, err)
+		}
+		canceled++
+	}
+	return canceled, nil
+}
+
+// checkHalted не дает задаче торговать, пока включен kill switch.
+func (wp *WorkerPool) checkHalted(t *task.Task, logger *zap.Logger) error {
+	if !wp.kill.Engaged() {
+		return nil
+	}
+	logger.Warn("⛔ Kill switch is engaged, not running task: " + t.TaskName)
+	return errHalted
+}
+
+// describeKillReport кратко описывает итог срабатывания для консоли и чата.
+func describeKillReport(report killswitch.Report) string {
+	lines := make([]string, 0, len(report.Positions)+2)
+	head := fmt.Sprintf("Kill switch engaged since %s (%s): %d pending orders canceled",
+		report.Since.Format("15:04:05"), report.Source, report.CanceledOrders)
+	if report.AlreadyEngaged {
+		head += ", was already engaged"
+	}
+	lines = append(lines, head)
+	if report.OrdersError != "" {
+		lines = append(lines, "orders: "+report.OrdersError)
+	}
+	if len(report.Positions) == 0 {
+		lines = append(lines, "No open positions")
+	}
+	for _, p := range report.Positions {
+		line := fmt.Sprintf("%s (%s): %s", p.Mint, p.Wallet, p.Result)
+		if p.Error != "" {
+			line += ": " + p.Error
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// engageKillSwitch включает kill switch командой "panic" из консоли монитора. Срабатывание
+// продает и эту позицию через handleUIEvents, поэтому выполняется в фоне.
+func (mw *MonitorWorker) engageKillSwitch() {
+	if mw.kill == nil {
+		fmt.Println("Kill switch is not available")
+		return
+	}
+	fmt.Println("\nKill switch: canceling orders and selling all positions...")
+	go func() {
+		fmt.Println(describeKillReport(mw.kill.Engage(mw.ctx, "tui")))
+	}()
+}
+
+// rearmKillSwitch снова разрешает задачи командой "rearm" из консоли монитора.
+func (mw *MonitorWorker) rearmKillSwitch() {
+	if mw.kill == nil {
+		fmt.Println("Kill switch is not available")
+		return
+	}
+	if _, ok := mw.kill.Rearm("tui"); !ok {
+		fmt.Println("Kill switch is not engaged")
+		return
+	}
+	fmt.Println("Kill switch re-armed, tasks run again")
+}
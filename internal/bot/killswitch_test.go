@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/killswitch"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// testMonitor — мониторинг без цикла: продажи по командам читает сам тест.
+func testMonitor(ctx context.Context, t *task.Task) *MonitorWorker {
+	return &MonitorWorker{
+		ctx:          ctx,
+		logger:       zap.NewNop(),
+		task:         t,
+		sellRequests: make(chan sellRequest),
+		stopped:      make(chan struct{}),
+	}
+}
+
+func TestKillSwitch_SellsBuyLandedDuringEngage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	wp := &WorkerPool{logger: zap.NewNop(), kill: killswitch.New(), monitors: make(map[string]*MonitorWorker)}
+	buy := &task.Task{TaskName: "snipe", WalletName: "main", TokenMint: "Mint1111111111111111111111111111111111111111"}
+	mw := testMonitor(ctx, buy)
+
+	report := wp.kill.Engage(ctx, "test", func(ctx context.Context) (int, []killswitch.PositionResult, error) {
+		canceled, positions, err := wp.flatten(ctx) // Снимок пуст: покупка еще в пути
+		untrack := wp.trackMonitor(buy, mw)         // Покупка прошла, пока Engage еще идет
+		t.Cleanup(untrack)
+		return canceled, positions, err
+	})
+	assert.Empty(t, report.Positions)
+
+	select {
+	case req := <-mw.sellRequests:
+		assert.Equal(t, 100.0, req.percent, "the position opened during Engage is sold in full")
+		req.result <- nil
+	case <-ctx.Done():
+		t.Fatal("the position opened during Engage was not sold")
+	}
+}
@@ -0,0 +1,128 @@
+// internal/bot/limit.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/orders"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// orderCheckInterval — как часто ожидающий ордер сверяется с журналом,
+// чтобы заметить отмену через -cancel-order.
+const orderCheckInterval = 2 * time.Second
+
+// handleLimitTask выставляет лимитный ордер задачи и следит за ценой, пока она не
+// дойдет до limit_price. Покупка затем продолжается как отслеживаемая сделка,
+// продажа — как задача sell. Ордер хранится в журнале и переживает перезапуск.
+func (wp *WorkerPool) handleLimitTask(ctx context.Context, t *task.Task, dexAdapter dex.DEX, logger *zap.Logger) error {
+	trade := *t
+	order := orders.Order{
+		Side:       orders.SideBuy,
+		TaskName:   t.TaskName,
+		Module:     t.Module,
+		Mint:       t.TokenMint,
+		Wallet:     t.WalletName,
+		LimitPrice: t.LimitPrice,
+		AmountSol:  t.AmountSol,
+	}
+	if t.Operation == task.OperationLimitSell {
+		trade.Operation = task.OperationSell
+		order.Side, order.AmountSol, order.Percent = orders.SideSell, 0, t.AutosellAmount
+	} else {
+		trade.Operation = buyOperationFor(t.Module)
+	}
+	order.ID = orders.ID(t.TaskName, order.Side, t.TokenMint, t.WalletName, t.LimitPrice)
+
+	order, err := wp.orders.Place(order)
+	if err != nil {
+		return fmt.Errorf("place limit order: %w", err)
+	}
+	if !order.Pending() {
+		logger.Info(fmt.Sprintf("📒 Limit order %s is already %s, not placing it again", order.ID, order.Status))
+		return nil
+	}
+	logger.Info("📒 Limit order pending: " + order.String())
+
+	if !wp.awaitLimitPrice(ctx, order, dexAdapter, logger) {
+		return ctx.Err()
+	}
+
+	if order.Side == orders.SideSell {
+		err = wp.handleSellTask(ctx, &trade, dexAdapter, logger)
+		wp.resolveOrder(order, err, logger)
+		if err != nil {
+			wp.alertTradeFailed(&trade, err)
+			return err
+		}
+		logger.Info("🎉 Limit sell filled: " + t.TaskName)
+		wp.alertTradeExecuted(&trade)
+		return nil
+	}
+
+	filled := false
+	err = wp.runMonitoredTask(ctx, &trade, dexAdapter, logger, func() {
+		filled = true
+		wp.resolveOrder(order, nil, logger)
+	})
+	if !filled {
+		if err == nil {
+			err = fmt.Errorf("buy did not complete")
+		}
+		wp.resolveOrder(order, err, logger)
+	}
+	return err
+}
+
+// awaitLimitPrice опрашивает цену, пока она не пересечет лимит ордера. Возвращает
+// false, если ордер отменили или отменен ctx: ордер остается в журнале как есть.
+func (wp *WorkerPool) awaitLimitPrice(ctx context.Context, order orders.Order, dexAdapter dex.DEX, logger *zap.Logger) bool {
+	interval := wp.config.PriceDelay
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	ticker := wp.clock.NewTicker(interval)
+	defer ticker.Stop()
+	checked := wp.clock.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C():
+		}
+
+		if wp.clock.Since(checked) >= orderCheckInterval {
+			checked = wp.clock.Now()
+			if current, ok, err := wp.orders.Get(order.ID); err == nil && ok && !current.Pending() {
+				logger.Info(fmt.Sprintf("🚫 Limit order %s %s: %s", order.ID, current.Status, current.Reason))
+				return false
+			}
+		}
+
+		price, err := dexAdapter.GetTokenPrice(ctx, order.Mint)
+		if err != nil {
+			logger.Debug("Limit order price fetch failed: " + err.Error())
+			continue
+		}
+		if order.Crossed(price) {
+			logger.Info(fmt.Sprintf("🎯 Limit price reached for order %s: %.10f SOL (limit %.10f)", order.ID, price, order.LimitPrice))
+			return true
+		}
+	}
+}
+
+// resolveOrder фиксирует исход сделки ордера в журнале.
+func (wp *WorkerPool) resolveOrder(order orders.Order, err error, logger *zap.Logger) {
+	status, reason := orders.StatusFilled, ""
+	if err != nil {
+		status, reason = orders.StatusFailed, err.Error()
+	}
+	if rerr := wp.orders.Resolve(order.ID, status, reason); rerr != nil {
+		logger.Warn("⚠️  Failed to record limit order "+status, zap.Error(rerr))
+	}
+}
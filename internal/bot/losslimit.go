@@ -0,0 +1,112 @@
+// internal/bot/losslimit.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/risk"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// lossLimitInterval — как часто дневной лимит убытка сверяется с PnL открытых позиций.
+const lossLimitInterval = 5 * time.Second
+
+// errLossLimit — дневной лимит убытка достигнут, новые покупки запрещены до конца дня.
+var errLossLimit = errors.New("daily loss limit reached")
+
+// startLossLimit включает дневной лимит убытка (daily_loss_limit_sol). Позиции,
+// проданные сегодня до перезапуска, берутся из архива сессий.
+func (wp *WorkerPool) startLossLimit() {
+	wp.lossLimit = risk.NewLossLimit(wp.config.DailyLossLimitSol, wp.clock.Now)
+	if wp.lossLimit == nil {
+		return
+	}
+	if wp.sessions != nil {
+		closed, err := wp.sessions.Search(execution.SessionQuery{From: wp.lossLimit.State().Day})
+		if err != nil {
+			wp.logger.Warn("⚠️  Daily loss limit: failed to read today's sessions: " + err.Error())
+		}
+		for _, s := range closed {
+			if s.Outcome == execution.OutcomeSold {
+				wp.lossLimit.Record(s.PnLSol, s.ClosedAt)
+			}
+		}
+	}
+	wp.updateLossLimit()
+	go wp.watchLossLimit(wp.ctx)
+}
+
+// watchLossLimit периодически сверяет PnL дня с лимитом, пока не отменен ctx.
+func (wp *WorkerPool) watchLossLimit(ctx context.Context) {
+	ticker := wp.clock.NewTicker(lossLimitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			wp.updateLossLimit()
+		}
+	}
+}
+
+// updateLossLimit пересчитывает PnL дня по открытым позициям. Сработавший лимит
+// пишется в лог, уходит в уведомления и выводится баннером над мониторингом;
+// в новый день баннер снимается.
+func (wp *WorkerPool) updateLossLimit() risk.State {
+	var unrealized float64
+	for _, pos := range wp.positions.Snapshot() {
+		unrealized += pos.PnLSol
+	}
+	state, tripped := wp.lossLimit.Update(unrealized)
+
+	wp.lossBannerMu.Lock()
+	defer wp.lossBannerMu.Unlock()
+	switch {
+	case tripped:
+		wp.logger.Warn("🧯 Daily loss limit reached, new buys are disabled until midnight, sells still run: " + state.String())
+		wp.notifier.Notify(notify.Alert{
+			Type:     notify.AlertLossLimit,
+			Severity: notify.SeverityCritical,
+			Message:  "Daily loss limit reached, new buys are disabled until midnight: " + state.String(),
+		})
+		wp.renderer.SetBanner(fmt.Sprintf("DAILY LOSS LIMIT: %+.4f of -%.4f SOL, buys disabled until midnight", state.PnL(), state.Limit))
+		wp.lossBanner = true
+	case wp.lossBanner && !state.Tripped:
+		wp.logger.Info("🧯 New trading day, buys are enabled again")
+		wp.renderer.SetBanner("")
+		wp.lossBanner = false
+	}
+	return state
+}
+
+// recordClosedPosition учитывает в PnL дня позицию, мониторинг которой закончился продажей.
+func (wp *WorkerPool) recordClosedPosition(mw *MonitorWorker) {
+	if wp.lossLimit == nil || mw.history.result() != execution.OutcomeSold {
+		return
+	}
+	mw.history.mu.Lock()
+	pnl := mw.history.pnl.NetPnL
+	mw.history.mu.Unlock()
+	wp.lossLimit.Record(pnl, wp.clock.Now())
+	wp.updateLossLimit()
+}
+
+// checkLossLimit не дает задаче покупать после срабатывания дневного лимита убытка.
+func (wp *WorkerPool) checkLossLimit(t *task.Task, logger *zap.Logger) error {
+	if wp.lossLimit == nil {
+		return nil
+	}
+	state := wp.updateLossLimit()
+	if !state.Tripped {
+		return nil
+	}
+	logger.Warn(fmt.Sprintf("🧯 Daily loss limit reached, not buying for task %s: %s", t.TaskName, state))
+	return fmt.Errorf("%w: %s", errLossLimit, state)
+}
@@ -0,0 +1,81 @@
+// internal/bot/metadata.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+)
+
+// metadataTimeout — сколько ждать метаданных токена; без них токен называется адресом mint.
+const metadataTimeout = 10 * time.Second
+
+// tokenNames — найденные метаданные токенов задач по mint, для экрана мониторинга и уведомлений.
+type tokenNames struct {
+	mu     sync.Mutex
+	byMint map[string]*model.TokenMetadata
+}
+
+func newTokenNames() *tokenNames {
+	return &tokenNames{byMint: make(map[string]*model.TokenMetadata)}
+}
+
+// resolve ищет метаданные токена через адаптер в фоне: покупка не ждет поиска,
+// а уведомления до его окончания называют токен адресом.
+func (n *tokenNames) resolve(ctx context.Context, mint string, adapter dex.DEX, logger *zap.Logger) {
+	if n == nil || n.get(mint) != nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+		defer cancel()
+		meta, err := adapter.GetTokenMetadata(ctx, mint)
+		if err != nil || meta == nil {
+			logger.Debug(fmt.Sprintf("🏷️  No metadata for %s: %v", shortMint(mint), err))
+			return
+		}
+		logger.Debug(fmt.Sprintf("🏷️  %s is %s (%s, from %s)", shortMint(mint), meta.Label(), meta.Name, meta.Source))
+		n.mu.Lock()
+		n.byMint[mint] = meta
+		n.mu.Unlock()
+	}()
+}
+
+func (n *tokenNames) get(mint string) *model.TokenMetadata {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.byMint[mint]
+}
+
+// label называет токен в уведомлениях символом и адресом: адрес остается полным,
+// чтобы его можно было передать в /sell. Без метаданных — только адрес.
+func (n *tokenNames) label(mint string) string {
+	if symbol := n.get(mint).Label(); symbol != "" {
+		return symbol + " " + mint
+	}
+	return mint
+}
+
+// title возвращает символ и название токена для экрана мониторинга, например
+// "BONK · Bonk"; "" — метаданные еще не найдены.
+func (n *tokenNames) title(mint string) string {
+	meta := n.get(mint)
+	if meta == nil {
+		return ""
+	}
+	switch {
+	case meta.Symbol == "" || meta.Name == "" || meta.Symbol == meta.Name:
+		return meta.Label()
+	default:
+		return meta.Symbol + " · " + meta.Name
+	}
+}
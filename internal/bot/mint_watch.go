@@ -0,0 +1,177 @@
+// internal/bot/mint_watch.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// mintWatchSell — значение mint_watch_action, при котором позиция продается целиком
+const mintWatchSell = "sell"
+
+// mintWatch следит за mint-аккаунтом удерживаемого токена: смена authority,
+// появление freeze authority, рост эмиссии и заморозка нашего токен-аккаунта —
+// типичные rug-сценарии, которые по цене видны слишком поздно.
+type mintWatch struct {
+	client   *blockchain.Client
+	notifier *notify.Notifier
+	mint     solana.PublicKey
+	account  solana.PublicKey // ATA кошелька задачи
+	interval time.Duration
+	sell     bool // Продавать позицию целиком при обнаружении риска
+	baseline blockchain.MintState
+	frozen   bool
+}
+
+// newMintWatch запоминает исходное состояние mint. Возвращает nil, если наблюдение
+// выключено (mint_watch_interval = 0) или mint не удалось прочитать.
+func (wp *WorkerPool) newMintWatch(ctx context.Context, t *task.Task, logger *zap.Logger) *mintWatch {
+	if wp.config.MintWatchInterval <= 0 {
+		return nil
+	}
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		logger.Warn("⚠️  Mint watch disabled, invalid mint: " + err.Error())
+		return nil
+	}
+
+	w := &mintWatch{
+		client:   wp.solClient,
+		notifier: wp.notifier,
+		mint:     mint,
+		interval: wp.config.MintWatchInterval,
+		sell:     wp.config.MintWatchAction == mintWatchSell,
+	}
+	if wallet := wp.wallets[t.WalletName]; wallet != nil {
+		if ata, err := wp.solClient.AssociatedTokenAccount(ctx, wallet.PublicKey, mint); err == nil {
+			w.account = ata
+		}
+	}
+
+	state, frozen, err := wp.solClient.GetMintState(ctx, mint, w.account)
+	if err != nil {
+		logger.Warn("⚠️  Mint watch disabled, mint unavailable: " + err.Error())
+		return nil
+	}
+	w.baseline, w.frozen = *state, frozen
+
+	if state.FreezeAuthority != nil {
+		logger.Warn(fmt.Sprintf("🧊 %s is freezable: freeze authority %s", shortMint(t.TokenMint), state.FreezeAuthority))
+	}
+	if state.MintAuthority != nil {
+		logger.Warn(fmt.Sprintf("🖨️  %s can still be minted: mint authority %s", shortMint(t.TokenMint), state.MintAuthority))
+	}
+	return w
+}
+
+// check сравнивает текущее состояние mint с последним известным и возвращает
+// описания новых рисков; каждое изменение сообщается один раз.
+func (w *mintWatch) check(ctx context.Context) ([]string, error) {
+	state, frozen, err := w.client.GetMintState(ctx, w.mint, w.account)
+	if err != nil {
+		return nil, err
+	}
+
+	var risks []string
+	if !sameKey(state.MintAuthority, w.baseline.MintAuthority) {
+		risks = append(risks, "mint authority changed to "+keyOrNone(state.MintAuthority))
+	}
+	if !sameKey(state.FreezeAuthority, w.baseline.FreezeAuthority) && state.FreezeAuthority != nil {
+		if w.baseline.FreezeAuthority == nil {
+			risks = append(risks, "mint became freezable by "+state.FreezeAuthority.String())
+		} else {
+			risks = append(risks, "freeze authority changed to "+state.FreezeAuthority.String())
+		}
+	}
+	if state.Supply > w.baseline.Supply && w.baseline.Supply > 0 {
+		risks = append(risks, fmt.Sprintf("supply inflated by %.2f%%",
+			float64(state.Supply-w.baseline.Supply)/float64(w.baseline.Supply)*100))
+	}
+	if frozen && !w.frozen {
+		risks = append(risks, "our token account was frozen")
+	}
+
+	if len(risks) > 0 {
+		// Кешированный mint (комиссия Token-2022, authority) устарел
+		w.client.InvalidateAccount(w.mint)
+	}
+	w.baseline, w.frozen = *state, frozen
+	return risks, nil
+}
+
+func sameKey(a, b *solana.PublicKey) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equals(*b)
+}
+
+func keyOrNone(k *solana.PublicKey) string {
+	if k == nil {
+		return "none"
+	}
+	return k.String()
+}
+
+// watchMint опрашивает mint позиции, уведомляет о рисках и при mint_watch_action = sell
+// продает позицию целиком.
+func (mw *MonitorWorker) watchMint(ctx context.Context) error {
+	w := mw.mintWatch
+	if w == nil {
+		return nil
+	}
+	ticker := mw.clock.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-mw.stopped:
+			return nil
+		case <-ticker.C():
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		risks, err := w.check(checkCtx)
+		cancel()
+		if err != nil {
+			mw.logger.Debug("Mint watch check failed: " + err.Error())
+			continue
+		}
+		if len(risks) == 0 {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s (%s): %s", mw.task.TokenMint, mw.task.WalletName, strings.Join(risks, "; "))
+		mw.logger.Warn("🚩 Mint risk: " + msg)
+		mw.history.event("mint_risk", strings.Join(risks, "; "))
+		w.notifier.Notify(notify.Alert{
+			Type:     notify.AlertMintRisk,
+			Key:      mw.task.TokenMint,
+			Severity: notify.SeverityCritical,
+			Message:  "Mint risk on " + msg,
+		})
+		if !w.sell {
+			continue
+		}
+
+		mw.logger.Warn("🚩 Selling the whole position after the mint change")
+		mw.Stop()
+		if err := mw.sellFn(ctx, 100); err != nil {
+			mw.logger.Error("❌ Mint risk sell failed: " + err.Error())
+			return err
+		}
+		mw.history.finish(execution.OutcomeSold, "mint risk")
+		return nil
+	}
+}
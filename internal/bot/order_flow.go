@@ -0,0 +1,155 @@
+// internal/bot/order_flow.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+const (
+	orderFlowWindow = time.Minute // Окно сводки сделок на экране позиции
+	orderFlowBuffer = 64          // Сделок, ждущих монитора; лишние отбрасываются
+)
+
+// orderFlow подписывается на транзакции с mint позиции и передает монитору сделки
+// Pump.fun по этому токену: собственные и чужие от min_sol. Фильтр стоит прямо у
+// подписки, поэтому монитор не разбирает весь поток токена.
+type orderFlow struct {
+	logs   logSource
+	mint   solana.PublicKey
+	minSol float64                   // Меньшие сделки других кошельков отбрасываются у источника
+	ours   map[solana.PublicKey]bool // Кошельки бота
+	flow   *monitor.OrderFlow
+	events chan monitor.FlowTrade
+	now    func() time.Time
+}
+
+// newOrderFlow возвращает nil, если order_flow_min_sol и order_flow_exit_sol не заданы,
+// нет источника логов или mint некорректен.
+func (wp *WorkerPool) newOrderFlow(t *task.Task, logger *zap.Logger) *orderFlow {
+	minSol, exitSol := wp.config.OrderFlowMinSol, wp.config.OrderFlowExitSol
+	if minSol <= 0 && exitSol <= 0 {
+		return nil
+	}
+	if wp.config.WebSocketURL == "" && wp.geyser == nil {
+		return nil
+	}
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		logger.Warn("⚠️  Order flow disabled, invalid mint: " + err.Error())
+		return nil
+	}
+	if minSol <= 0 || (exitSol > 0 && exitSol < minSol) {
+		minSol = exitSol // Крупные продажи должны доходить до монитора
+	}
+	ours := make(map[solana.PublicKey]bool, len(wp.wallets))
+	for _, w := range wp.wallets {
+		ours[w.PublicKey] = true
+	}
+	return &orderFlow{
+		logs:   logSource{wsURL: wp.config.WebSocketURL, geyser: wp.geyser},
+		mint:   mint,
+		minSol: minSol,
+		ours:   ours,
+		flow:   monitor.NewOrderFlow(orderFlowWindow, exitSol),
+		events: make(chan monitor.FlowTrade, orderFlowBuffer),
+		now:    wp.clock.Now,
+	}
+}
+
+// route разбирает сделки транзакции ev и передает монитору прошедшие фильтр.
+// Монитор не успевает — сделка отбрасывается: подписка не должна ждать.
+func (f *orderFlow) route(ev blockchain.LogsEvent) {
+	if ev.Err != nil {
+		return
+	}
+	for _, tr := range pumpfun.ParseTradeLogs(ev.Logs) {
+		if !tr.Mint.Equals(f.mint) {
+			continue
+		}
+		sol := float64(tr.SolAmount) / float64(solana.LAMPORTS_PER_SOL)
+		ours := f.ours[tr.User]
+		if !ours && sol < f.minSol {
+			continue
+		}
+		select {
+		case f.events <- monitor.FlowTrade{At: f.now(), Wallet: tr.User.String(), Buy: tr.IsBuy, Sol: sol, Ours: ours}:
+		default:
+		}
+	}
+}
+
+// summary описывает поток для экрана позиции (пусто — потока нет или сделок не было).
+func (f *orderFlow) summary() string {
+	if f == nil {
+		return ""
+	}
+	return f.flow.Summary(f.now())
+}
+
+// watchOrderFlow держит подписку на сделки токена до конца мониторинга, пишет в лог
+// крупные сделки других кошельков и продает позицию целиком на крупной продаже
+// (order_flow_exit_sol).
+func (mw *MonitorWorker) watchOrderFlow(ctx context.Context) error {
+	f := mw.flow
+	if f == nil {
+		return nil
+	}
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go listenLogs(listenCtx, f.logs, f.mint, mw.logger, func(ev blockchain.LogsEvent) bool {
+		f.route(ev)
+		return false
+	})
+
+	for {
+		var tr monitor.FlowTrade
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-mw.stopped:
+			return nil
+		case tr = <-f.events:
+		}
+
+		side := "sold"
+		if tr.Buy {
+			side = "bought"
+		}
+		if tr.Ours {
+			mw.logger.Debug(fmt.Sprintf("Own fill seen in the stream: %s %s %.4f SOL", tr.Wallet, side, tr.Sol))
+			continue
+		}
+		mw.logger.Info(fmt.Sprintf("🌊 %s %s %.3f SOL of %s", tr.Wallet, side, tr.Sol, mw.task.TokenMint))
+		if !f.flow.Add(tr) {
+			continue
+		}
+
+		select {
+		case <-mw.stopped:
+			return nil // Позицию уже продает другое правило
+		default:
+		}
+		detail := fmt.Sprintf("%s sold %.3f SOL", tr.Wallet, tr.Sol)
+		mw.logger.Warn(fmt.Sprintf("🌊 Big sell on %s: %s, selling the position", mw.task.TokenMint, detail))
+		mw.history.event("order_flow_exit", detail)
+
+		mw.Stop()
+		if err := mw.sellFn(ctx, 100); err != nil {
+			mw.logger.Error("❌ Order flow exit sell failed: " + err.Error())
+			return err
+		}
+		mw.history.finish(execution.OutcomeSold, "order_flow")
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+)
+
+// tradeLog — строка лога с событием TradeEvent Pump.fun.
+func tradeLog(mint, user solana.PublicKey, lamports uint64, buy bool) string {
+	disc := sha256.Sum256([]byte("event:TradeEvent"))
+	data := append([]byte{}, disc[:8]...)
+	data = append(data, mint[:]...)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	data = binary.LittleEndian.AppendUint64(data, 1_000_000)
+	if buy {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	data = append(data, user[:]...)
+	return "Program data: " + base64.StdEncoding.EncodeToString(data)
+}
+
+func TestOrderFlow_RouteFiltersAtSource(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	mint, other := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	ours, whale, minnow := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	f := &orderFlow{
+		mint:   mint,
+		minSol: 1,
+		ours:   map[solana.PublicKey]bool{ours: true},
+		flow:   monitor.NewOrderFlow(time.Minute, 5),
+		events: make(chan monitor.FlowTrade, 2),
+		now:    func() time.Time { return now },
+	}
+
+	f.route(blockchain.LogsEvent{Logs: []string{
+		tradeLog(other, whale, 9e9, false), // Другой токен
+		tradeLog(mint, minnow, 1e8, true),  // Меньше min_sol
+		tradeLog(mint, ours, 1e7, true),    // Своя сделка проходит при любом размере
+		tradeLog(mint, whale, 6e9, false),  // Крупная продажа
+		tradeLog(mint, whale, 2e9, true),   // Буфер заполнен: отбрасывается без ожидания
+	}})
+	f.route(blockchain.LogsEvent{Err: "failed", Logs: []string{tradeLog(mint, whale, 9e9, false)}})
+
+	assert.Equal(t, monitor.FlowTrade{At: now, Wallet: ours.String(), Buy: true, Sol: 0.01, Ours: true}, <-f.events)
+	assert.Equal(t, monitor.FlowTrade{At: now, Wallet: whale.String(), Sol: 6}, <-f.events)
+	assert.Empty(t, f.events)
+}
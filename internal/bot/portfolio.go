@@ -0,0 +1,129 @@
+// internal/bot/portfolio.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rovshanmuradov/solana-bot/internal/bot/ui"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/portfolio"
+)
+
+// runPortfolio выводит экран портфеля: SOL и токены каждого кошелька с их стоимостью
+// и общий итог. Экран обновляется раз в portfolio_refresh, пока не отменен ctx.
+func (r *Runner) runPortfolio(ctx context.Context) error {
+	r.logger.Info("💼 Portfolio mode: trading disabled")
+
+	if err := r.renderPortfolio(ctx); err != nil {
+		return err
+	}
+	if r.config.PortfolioRefresh <= 0 {
+		return nil
+	}
+
+	ticker := r.clock.NewTicker(r.config.PortfolioRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+		if err := r.renderPortfolio(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// Сбой RPC не закрывает экран: следующее обновление попробует снова
+			r.logger.Warn("⚠️  Failed to refresh the portfolio: " + err.Error())
+		}
+	}
+}
+
+// renderPortfolio загружает свежие балансы, оценивает токены и выводит экран.
+func (r *Runner) renderPortfolio(ctx context.Context) error {
+	for _, w := range r.wallets {
+		r.balances.Invalidate(w.PublicKey)
+	}
+	balances, err := r.balances.Balances(ctx, r.wallets)
+	if err != nil {
+		return fmt.Errorf("fetch wallet balances: %w", err)
+	}
+
+	prices, symbols := r.quoteHoldings(ctx, balances)
+	view := ui.Portfolio{UpdatedAt: r.clock.Now(), Refresh: r.config.PortfolioRefresh}
+	for _, b := range balances {
+		w := ui.PortfolioWallet{Name: b.Name, Role: string(b.Role), SOL: b.SOL()}
+		for _, t := range b.Tokens {
+			if t.Amount == 0 {
+				continue
+			}
+			mint := t.Mint.String()
+			price, ok := prices[mint]
+			w.Tokens = append(w.Tokens, ui.PortfolioToken{
+				Mint:   mint,
+				Symbol: symbols[mint],
+				Amount: t.UIAmount(),
+				Value:  price * t.UIAmount(),
+				Priced: ok,
+			})
+		}
+		view.Wallets = append(view.Wallets, w)
+	}
+	ui.RenderPortfolio(view)
+	return nil
+}
+
+// quoteHoldings получает цены в SOL и символы всех токенов на кошельках. Токены без
+// котировки или метаданных в соответствующий результат не попадают.
+func (r *Runner) quoteHoldings(ctx context.Context, balances []portfolio.WalletBalance) (map[string]float64, map[string]string) {
+	prices := make(map[string]float64)
+	symbols := make(map[string]string)
+	if r.defaultWallet == nil {
+		return prices, symbols
+	}
+
+	seen := make(map[string]bool)
+	for _, b := range balances {
+		for _, t := range b.Tokens {
+			if t.Amount > 0 {
+				seen[t.Mint.String()] = true
+			}
+		}
+	}
+	mints := make([]string, 0, len(seen))
+	for mint := range seen {
+		mints = append(mints, mint)
+	}
+	sort.Strings(mints)
+
+	// Адаптеры инициализируются под один токен, поэтому у каждого токена свой
+	reqs := make([]dex.QuoteRequest, 0, len(mints))
+	for _, mint := range mints {
+		adapter, err := dex.GetDEXByName("snipe", r.solClient, r.defaultWallet, r.logger.Named("portfolio"))
+		if err != nil {
+			r.logger.Warn("⚠️  Token prices unavailable: " + err.Error())
+			return prices, symbols
+		}
+		reqs = append(reqs, dex.QuoteRequest{Mint: mint, DEX: adapter})
+	}
+
+	quoteCtx, cancel := context.WithTimeout(ctx, watchlistTimeout)
+	defer cancel()
+	for q := range dex.QuoteBatch(quoteCtx, reqs, dex.DefaultQuoteConcurrency) {
+		if q.Err != nil {
+			r.logger.Debug(fmt.Sprintf("   %s: no quote: %v", shortMint(q.Mint), q.Err))
+			continue
+		}
+		prices[q.Mint] = q.Price
+	}
+
+	// Метаданные кешируются адаптерами, поэтому сеть спрашивается только при первом выводе
+	for _, req := range reqs {
+		if meta, err := req.DEX.GetTokenMetadata(quoteCtx, req.Mint); err == nil {
+			symbols[req.Mint] = meta.Label()
+		}
+	}
+	return prices, symbols
+}
@@ -0,0 +1,220 @@
+// internal/bot/position.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/risk"
+	"github.com/rovshanmuradov/solana-bot/internal/storage"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// defaultTokenDecimals — decimals токенов pump.fun; берутся, если mint не удалось прочитать.
+const defaultTokenDecimals = 6
+
+// positionBuy — одна покупка, вошедшая в позицию.
+type positionBuy struct {
+	Task      string
+	AmountSol float64
+	Tokens    uint64 // Куплено токенов (raw)
+	At        time.Time
+}
+
+// position — открытая позиция кошелька по токену. Повторные покупки того же токена
+// сливаются в нее: вложения и баланс суммируются, цена входа усредняется.
+type position struct {
+	mu       sync.Mutex
+	mint     string
+	wallet   string
+	decimals uint8     // Decimals токена из его mint
+	task     task.Task // Задача, открывшая позицию
+	buys     []positionBuy
+}
+
+// Invested возвращает суммарно вложенные в позицию SOL.
+func (p *position) Invested() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := 0.0
+	for _, b := range p.buys {
+		total += b.AmountSol
+	}
+	return total
+}
+
+// EntryPrice возвращает средневзвешенную цену входа в SOL за токен.
+func (p *position) EntryPrice() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var sol float64
+	var tokens uint64
+	for _, b := range p.buys {
+		sol += b.AmountSol
+		tokens += b.Tokens
+	}
+	if tokens == 0 {
+		return 0
+	}
+	return sol / (float64(tokens) / math.Pow10(int(p.decimals)))
+}
+
+// Buys возвращает копию покупок позиции.
+func (p *position) Buys() []positionBuy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]positionBuy(nil), p.buys...)
+}
+
+// Merged сообщает, состоит ли позиция из нескольких покупок.
+func (p *position) Merged() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.buys) > 1
+}
+
+// History описывает покупки позиции, например "task-a 0.100 SOL → task-b 0.050 SOL".
+func (p *position) History() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	parts := make([]string, 0, len(p.buys))
+	for _, b := range p.buys {
+		parts = append(parts, fmt.Sprintf("%s %.3f SOL", b.Task, b.AmountSol))
+	}
+	return strings.Join(parts, " → ")
+}
+
+// snapshot возвращает позицию для журнала открытых позиций.
+func (p *position) snapshot(tokenBalance uint64) storage.Position {
+	entry := p.EntryPrice()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	saved := storage.Position{
+		Mint:         p.mint,
+		Wallet:       p.wallet,
+		Task:         p.task,
+		EntryPrice:   entry,
+		TokenBalance: tokenBalance,
+	}
+	for _, b := range p.buys {
+		saved.Buys = append(saved.Buys, storage.Buy{Task: b.Task, AmountSol: b.AmountSol, Tokens: b.Tokens, At: b.At})
+	}
+	return saved
+}
+
+// positionDecimals читает decimals токена задачи t из его mint для цены входа позиции.
+func (wp *WorkerPool) positionDecimals(ctx context.Context, t *task.Task, logger *zap.Logger) uint8 {
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("⚠️  Invalid token mint, assuming %d decimals: %v", defaultTokenDecimals, err))
+		return defaultTokenDecimals
+	}
+	decimals, err := wp.solClient.GetMintDecimals(ctx, mint)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("⚠️  Token decimals unavailable, assuming %d: %v", defaultTokenDecimals, err))
+		return defaultTokenDecimals
+	}
+	return decimals
+}
+
+// positionBook хранит открытые позиции, чтобы на один токен кошелька приходилась одна сессия мониторинга.
+type positionBook struct {
+	mu   sync.Mutex
+	open map[string]*position
+}
+
+func newPositionBook() *positionBook {
+	return &positionBook{open: make(map[string]*position)}
+}
+
+// add добавляет покупку задачи t: открывает новую позицию токена с decimals или сливает
+// покупку с уже открытой. merged = true, если позиция уже мониторится и новая сессия не нужна.
+func (b *positionBook) add(t *task.Task, decimals uint8, buy positionBuy) (p *position, merged bool) {
+	return b.join(t, decimals, []positionBuy{buy})
+}
+
+// restore открывает позицию, сохраненную до перезапуска, с ее покупками.
+func (b *positionBook) restore(t *task.Task, decimals uint8, saved storage.Position) (p *position, merged bool) {
+	buys := make([]positionBuy, 0, len(saved.Buys))
+	for _, sb := range saved.Buys {
+		buys = append(buys, positionBuy{Task: sb.Task, AmountSol: sb.AmountSol, Tokens: sb.Tokens, At: sb.At})
+	}
+	return b.join(t, decimals, buys)
+}
+
+// join открывает позицию с покупками buys или добавляет их к уже открытой.
+func (b *positionBook) join(t *task.Task, decimals uint8, buys []positionBuy) (p *position, merged bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := storage.PositionKey(t.WalletName, t.TokenMint)
+	if p, ok := b.open[key]; ok {
+		p.mu.Lock()
+		p.buys = append(p.buys, buys...)
+		p.mu.Unlock()
+		return p, true
+	}
+
+	p = &position{mint: t.TokenMint, wallet: t.WalletName, decimals: decimals, task: *t, buys: buys}
+	b.open[key] = p
+	return p, false
+}
+
+// merge добавляет покупку к уже открытой позиции задачи t; ok = false, если позиция закрыта.
+func (b *positionBook) merge(t *task.Task, buy positionBuy) (p *position, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p, ok = b.open[storage.PositionKey(t.WalletName, t.TokenMint)]
+	if ok {
+		p.mu.Lock()
+		p.buys = append(p.buys, buy)
+		p.mu.Unlock()
+	}
+	return p, ok
+}
+
+// isOpen сообщает, открыта ли позиция задачи t.
+func (b *positionBook) isOpen(t *task.Task) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.open[storage.PositionKey(t.WalletName, t.TokenMint)]
+	return ok
+}
+
+// exposure возвращает вложения всех открытых позиций.
+func (b *positionBook) exposure() []risk.Exposure {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]risk.Exposure, 0, len(b.open))
+	for _, p := range b.open {
+		out = append(out, risk.Exposure{Mint: p.mint, Wallet: p.wallet, Sol: p.Invested()})
+	}
+	return out
+}
+
+// close убирает позицию из открытых; последующие покупки откроют новую.
+func (b *positionBook) close(p *position) {
+	if b == nil || p == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := storage.PositionKey(p.wallet, p.mint)
+	if b.open[key] == p {
+		delete(b.open, key)
+	}
+}
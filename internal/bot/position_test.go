@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rovshanmuradov/solana-bot/internal/storage"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+func TestPositionBook_AddMergesAndCloses(t *testing.T) {
+	book := newPositionBook()
+	first := &task.Task{TaskName: "snipe", WalletName: "main", TokenMint: "MintA"}
+	again := &task.Task{TaskName: "dip", WalletName: "main", TokenMint: "MintA"}
+	other := &task.Task{TaskName: "snipe-alt", WalletName: "alt", TokenMint: "MintA"}
+
+	pos, merged := book.add(first, 6, positionBuy{Task: first.TaskName, AmountSol: 0.1, Tokens: 1_000_000_000})
+	assert.False(t, merged)
+	assert.True(t, book.isOpen(first))
+
+	same, merged := book.add(again, 9, positionBuy{Task: again.TaskName, AmountSol: 0.05, Tokens: 1_000_000_000})
+	assert.True(t, merged, "a repeat buy of the same token by the same wallet joins the open position")
+	assert.Same(t, pos, same)
+	assert.Equal(t, uint8(6), pos.decimals, "decimals come from the position's mint, not from later buys")
+	assert.True(t, pos.Merged())
+	assert.Equal(t, "snipe 0.100 SOL → dip 0.050 SOL", pos.History())
+
+	alt, merged := book.add(other, 6, positionBuy{Task: other.TaskName, AmountSol: 0.2, Tokens: 1})
+	assert.False(t, merged, "another wallet opens its own position")
+	assert.NotSame(t, pos, alt)
+	assert.Len(t, book.exposure(), 2)
+
+	book.close(pos)
+	assert.False(t, book.isOpen(first))
+	_, ok := book.merge(again, positionBuy{Task: again.TaskName, AmountSol: 0.01, Tokens: 1})
+	assert.False(t, ok, "a closed position takes no more buys")
+
+	reopened, merged := book.add(first, 6, positionBuy{Task: first.TaskName, AmountSol: 0.3, Tokens: 1})
+	assert.False(t, merged)
+	assert.NotSame(t, pos, reopened)
+	book.close(pos) // Закрытие старой позиции не трогает новую
+	assert.True(t, book.isOpen(first))
+	book.close(nil)
+}
+
+func TestPosition_EntryPriceAndInvested(t *testing.T) {
+	for name, tc := range map[string]struct {
+		decimals uint8
+		buys     []positionBuy
+		invested float64
+		entry    float64
+	}{
+		"six decimals": {
+			decimals: 6,
+			buys:     []positionBuy{{AmountSol: 0.1, Tokens: 1_000_000_000}},
+			invested: 0.1,
+			entry:    0.0001,
+		},
+		"nine decimals": {
+			decimals: 9,
+			buys:     []positionBuy{{AmountSol: 0.1, Tokens: 1_000_000_000}},
+			invested: 0.1,
+			entry:    0.1,
+		},
+		"zero decimals": {
+			decimals: 0,
+			buys:     []positionBuy{{AmountSol: 2, Tokens: 4}},
+			invested: 2,
+			entry:    0.5,
+		},
+		"weighted average": {
+			decimals: 6,
+			buys:     []positionBuy{{AmountSol: 0.1, Tokens: 1_000_000_000}, {AmountSol: 0.3, Tokens: 1_000_000_000}},
+			invested: 0.4,
+			entry:    0.0002,
+		},
+		"no tokens": {
+			decimals: 6,
+			buys:     []positionBuy{{AmountSol: 0.1}},
+			invested: 0.1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			book := newPositionBook()
+			tk := &task.Task{TaskName: "snipe", WalletName: "main", TokenMint: "MintA"}
+			pos, _ := book.add(tk, tc.decimals, tc.buys[0])
+			for _, b := range tc.buys[1:] {
+				_, ok := book.merge(tk, b)
+				require.True(t, ok)
+			}
+			assert.InDelta(t, tc.invested, pos.Invested(), 1e-12)
+			assert.InDelta(t, tc.entry, pos.EntryPrice(), 1e-12)
+		})
+	}
+}
+
+func TestPositionBook_Restore(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tk := &task.Task{TaskName: "snipe", WalletName: "main", TokenMint: "MintA"}
+	saved := storage.Position{Mint: tk.TokenMint, Wallet: tk.WalletName, Task: *tk, Buys: []storage.Buy{
+		{Task: "snipe", AmountSol: 0.1, Tokens: 500_000, At: at},
+		{Task: "dca", AmountSol: 0.1, Tokens: 500_000, At: at},
+	}}
+
+	pos, merged := newPositionBook().restore(tk, 3, saved)
+	assert.False(t, merged)
+	assert.InDelta(t, 0.2, pos.Invested(), 1e-12)
+	assert.InDelta(t, 0.0002, pos.EntryPrice(), 1e-12)
+
+	snap := pos.snapshot(1_000_000)
+	assert.Equal(t, saved.Buys, snap.Buys)
+	assert.Equal(t, uint64(1_000_000), snap.TokenBalance)
+	assert.InDelta(t, 0.0002, snap.EntryPrice, 1e-12)
+}
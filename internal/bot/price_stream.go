@@ -0,0 +1,137 @@
+// internal/bot/price_stream.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// priceAccountsRecheck — как часто перечитывается набор аккаунтов цены: после миграции
+// токена с bonding curve цена считается по хранилищам пула.
+const priceAccountsRecheck = 30 * time.Second
+
+// priceStream подписывается через Geyser на аккаунты, по которым считается цена
+// позиции, и сигналит монитору о каждом их изменении: цена обновляется за доли
+// секунды, а не раз в monitor_delay. Пока потока нет, цена обновляется опросом.
+type priceStream struct {
+	geyser  *blockchain.GeyserClient
+	source  dex.PriceAccountSource
+	mint    string
+	updates chan struct{}
+}
+
+// newPriceStream возвращает nil, если Geyser не настроен или цену площадки нельзя
+// отслеживать по аккаунтам.
+func (wp *WorkerPool) newPriceStream(t *task.Task, dexAdapter dex.DEX) *priceStream {
+	if wp.geyser == nil {
+		return nil
+	}
+	src, ok := dexAdapter.(dex.PriceAccountSource)
+	if !ok {
+		return nil
+	}
+	return &priceStream{geyser: wp.geyser, source: src, mint: t.TokenMint, updates: make(chan struct{}, 1)}
+}
+
+// trigger возвращает канал сигналов для сессии мониторинга (nil — потока нет).
+func (s *priceStream) trigger() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.updates
+}
+
+// streamPrices держит подписку на аккаунты цены до конца мониторинга, переподключаясь
+// при обрывах и при смене набора аккаунтов.
+func (mw *MonitorWorker) streamPrices(ctx context.Context) error {
+	s := mw.prices
+	if s == nil {
+		return nil
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-mw.stopped:
+			cancel()
+		case <-streamCtx.Done():
+		}
+	}()
+
+	delay := time.Second
+	for streamCtx.Err() == nil {
+		started := time.Now()
+		err := mw.streamPriceAccounts(streamCtx, s)
+		if streamCtx.Err() != nil {
+			break
+		}
+		if err == nil {
+			continue // Набор аккаунтов сменился
+		}
+		if time.Since(started) > time.Minute {
+			delay = time.Second
+		}
+		mw.logger.Warn(fmt.Sprintf("⚠️  Geyser price stream lost, polling every %v and retrying in %v: %v",
+			mw.monitorInterval, delay, err))
+		select {
+		case <-streamCtx.Done():
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+
+	select {
+	case <-mw.stopped:
+		return nil
+	default:
+		return ctx.Err()
+	}
+}
+
+// streamPriceAccounts подписывается на текущие аккаунты цены. Возвращает nil, когда
+// набор аккаунтов сменился и подписку нужно открыть заново.
+func (mw *MonitorWorker) streamPriceAccounts(ctx context.Context, s *priceStream) error {
+	lookupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	accounts, err := s.source.PriceAccounts(lookupCtx, s.mint)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("price accounts: %w", err)
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		ticker := mw.clock.NewTicker(priceAccountsRecheck)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-subCtx.Done():
+				return
+			case <-ticker.C():
+			}
+			current, err := s.source.PriceAccounts(subCtx, s.mint)
+			if err == nil && !slices.EqualFunc(current, accounts, solana.PublicKey.Equals) {
+				mw.logger.Debug("Price accounts changed, resubscribing", zap.String("token", s.mint))
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return s.geyser.SubscribeAccounts(subCtx, accounts, rpc.CommitmentProcessed, func(blockchain.AccountUpdate) {
+		select {
+		case s.updates <- struct{}{}:
+		default: // Сигнал уже ждет монитора
+		}
+	})
+}
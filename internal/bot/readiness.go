@@ -0,0 +1,331 @@
+// internal/bot/readiness.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/jupiter"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpswap"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+const readinessTimeout = 10 * time.Second
+
+// readinessRPC is the part of *blockchain.Client the startup checks call.
+type readinessRPC interface {
+	GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error)
+	GetGenesisHash(ctx context.Context) (solana.Hash, error)
+	GetAccountInfo(ctx context.Context, pubkey solana.PublicKey) (*rpc.GetAccountInfoResult, error)
+}
+
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFailed
+)
+
+// readinessCheck is the outcome of a single startup check.
+type readinessCheck struct {
+	status checkStatus
+	name   string
+	detail string
+}
+
+// readinessReport collects startup checks into one consolidated report.
+type readinessReport struct {
+	checks []readinessCheck
+}
+
+func (rep *readinessReport) add(status checkStatus, name, detail string) {
+	rep.checks = append(rep.checks, readinessCheck{status: status, name: name, detail: detail})
+}
+
+func (rep *readinessReport) count(status checkStatus) int {
+	n := 0
+	for _, c := range rep.checks {
+		if c.status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// checkReadiness cross-validates tasks, wallets, RPC endpoints and program IDs before trading.
+// In headless mode any failed check aborts the run; otherwise failures are only reported.
+func (r *Runner) checkReadiness(ctx context.Context, tasks []*task.Task) error {
+	checkCtx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	rep := &readinessReport{}
+	r.checkWalletKeys(rep)
+	used := r.checkTaskWallets(rep, tasks)
+	r.checkTaskExitPlans(rep, tasks)
+	r.checkTaskFees(rep, tasks)
+	r.checkWalletBalances(checkCtx, rep, used)
+	r.checkRPCEndpoints(checkCtx, rep)
+	r.checkTaskRPCs(checkCtx, rep, tasks)
+	r.checkPrograms(checkCtx, rep, tasks)
+
+	r.logReadiness(rep)
+
+	failed := rep.count(checkFailed)
+	if failed == 0 {
+		return nil
+	}
+	if r.config.Headless {
+		return fmt.Errorf("startup checks failed: %d problem(s), see readiness report", failed)
+	}
+	r.logger.Warn(fmt.Sprintf("⚠️  Continuing despite %d failed startup check(s)", failed))
+	return nil
+}
+
+// logReadiness prints the report, listing problems before passed checks
+func (r *Runner) logReadiness(rep *readinessReport) {
+	r.logger.Info(fmt.Sprintf("🩺 Readiness: %d checks, %d failed, %d warnings",
+		len(rep.checks), rep.count(checkFailed), rep.count(checkWarn)))
+
+	for _, status := range []checkStatus{checkFailed, checkWarn, checkOK} {
+		for _, c := range rep.checks {
+			if c.status != status {
+				continue
+			}
+			line := fmt.Sprintf("   %s %s: %s", statusIcon(c.status), c.name, c.detail)
+			switch c.status {
+			case checkFailed:
+				r.logger.Error(line)
+			case checkWarn:
+				r.logger.Warn(line)
+			default:
+				r.logger.Info(line)
+			}
+		}
+	}
+}
+
+// checkWalletKeys reports wallets.csv rows whose private key cannot be parsed
+func (r *Runner) checkWalletKeys(rep *readinessReport) {
+	if r.keystore {
+		// Ключи проверяются при импорте в хранилище
+		rep.add(checkOK, "Wallet keys", fmt.Sprintf("%d wallets loaded from the keystore", len(r.wallets)))
+		return
+	}
+	invalid, err := task.InvalidWalletKeys(walletsPath)
+	if err != nil {
+		rep.add(checkFailed, "Wallet keys", err.Error())
+		return
+	}
+	for _, name := range invalid {
+		rep.add(checkFailed, "Wallet "+name, "private key is not valid base58")
+	}
+	if len(invalid) == 0 {
+		rep.add(checkOK, "Wallet keys", fmt.Sprintf("%d wallets loaded", len(r.wallets)))
+	}
+}
+
+// checkTaskWallets verifies that every task references a loaded wallet and returns
+// the wallets that will trade (with their fee payers).
+func (r *Runner) checkTaskWallets(rep *readinessReport, tasks []*task.Task) map[string]*task.Wallet {
+	used := make(map[string]*task.Wallet)
+	missing := 0
+	for _, t := range tasks {
+		w := r.wallets[t.WalletName]
+		if w == nil {
+			rep.add(checkFailed, "Task "+t.TaskName, fmt.Sprintf("wallet %q not found in wallets.csv", t.WalletName))
+			missing++
+			continue
+		}
+		used[w.Name] = w
+		if w.FeePayer != nil {
+			used[w.FeePayer.Name] = w.FeePayer
+		}
+	}
+	if missing == 0 {
+		rep.add(checkOK, "Task wallets", fmt.Sprintf("%d tasks reference existing wallets", len(tasks)))
+	}
+	return used
+}
+
+// checkTaskExitPlans fails for tasks whose exit_plan is not defined in config.json exit_plans
+func (r *Runner) checkTaskExitPlans(rep *readinessReport, tasks []*task.Task) {
+	for _, t := range tasks {
+		if t.ExitPlan == "" {
+			continue
+		}
+		if _, ok := r.config.ExitPlans[t.ExitPlan]; !ok {
+			rep.add(checkFailed, "Task "+t.TaskName, fmt.Sprintf("exit plan %q not found in config.json exit_plans", t.ExitPlan))
+		}
+	}
+}
+
+// checkTaskFees fails for tasks whose priority_fee asks for an estimate in a malformed way, e.g. "auto:p0"
+func (r *Runner) checkTaskFees(rep *readinessReport, tasks []*task.Task) {
+	for _, t := range tasks {
+		if !blockchain.IsAutoFee(t.PriorityFeeSol) {
+			continue
+		}
+		if _, err := blockchain.ParseAutoFee(t.PriorityFeeSol); err != nil {
+			rep.add(checkFailed, "Task "+t.TaskName, err.Error())
+		}
+	}
+}
+
+// checkWalletBalances fails for trading wallets without SOL and warns for idle ones
+func (r *Runner) checkWalletBalances(ctx context.Context, rep *readinessReport, used map[string]*task.Wallet) {
+	balances, err := r.balances.Balances(ctx, r.wallets)
+	if err != nil {
+		rep.add(checkWarn, "Wallet balances", "could not fetch balances: "+err.Error())
+		return
+	}
+
+	empty := 0
+	for _, b := range balances {
+		if b.Lamports > 0 {
+			continue
+		}
+		empty++
+		if _, ok := used[b.Name]; ok {
+			rep.add(checkFailed, "Wallet "+b.Name, "balance is 0 SOL but tasks trade from it")
+		} else {
+			rep.add(checkWarn, "Wallet "+b.Name, "balance is 0 SOL")
+		}
+	}
+	if empty == 0 {
+		rep.add(checkOK, "Wallet balances", fmt.Sprintf("%d wallets funded", len(balances)))
+	}
+}
+
+// checkRPCEndpoints verifies every RPC responds and that all of them are on mainnet-beta
+func (r *Runner) checkRPCEndpoints(ctx context.Context, rep *readinessReport) {
+	for i, url := range r.config.RPCList {
+		name := "Primary RPC " + rpcLabel(url)
+		failStatus := checkFailed
+		if i > 0 {
+			name = fmt.Sprintf("Fallback RPC %d %s", i, rpcLabel(url))
+			failStatus = checkWarn // The pool keeps trading through the other endpoints
+		}
+
+		// Общий клиент переключается между эндпоинтами, поэтому каждый проверяется отдельно
+		client := r.checkClient(url, false)
+
+		slot, err := client.GetSlot(ctx, rpc.CommitmentProcessed)
+		if err != nil {
+			rep.add(failStatus, name, "not responding: "+err.Error())
+			continue
+		}
+		genesis, err := client.GetGenesisHash(ctx)
+		if err != nil {
+			rep.add(checkWarn, name, fmt.Sprintf("slot %d, network unknown: %v", slot, err))
+			continue
+		}
+		if !genesis.Equals(blockchain.MainnetGenesisHash) {
+			rep.add(checkFailed, name, fmt.Sprintf("not a mainnet-beta endpoint (genesis %s); DEX program IDs are mainnet addresses", genesis))
+			continue
+		}
+		rep.add(checkOK, name, fmt.Sprintf("mainnet-beta, slot %d", slot))
+	}
+}
+
+// checkTaskRPCs verifies that per-task rpc overrides resolve and respond
+func (r *Runner) checkTaskRPCs(ctx context.Context, rep *readinessReport, tasks []*task.Task) {
+	checked := make(map[string]bool)
+	for _, t := range tasks {
+		if t.RPC == "" || checked[t.RPC] {
+			continue
+		}
+		checked[t.RPC] = true
+
+		endpoint, err := r.config.ResolveRPC(t.RPC)
+		if err != nil {
+			rep.add(checkFailed, "Task RPC "+t.RPC, err.Error())
+			continue
+		}
+		name := "Task RPC " + rpcLabel(endpoint)
+		if endpoint != t.RPC {
+			name = fmt.Sprintf("Task RPC %s (%s)", t.RPC, rpcLabel(endpoint))
+		}
+		slot, err := r.checkClient(endpoint, true).GetSlot(ctx, rpc.CommitmentProcessed)
+		if err != nil {
+			rep.add(checkFailed, name, "not responding: "+err.Error())
+			continue
+		}
+		rep.add(checkOK, name, fmt.Sprintf("slot %d", slot))
+	}
+}
+
+// checkPrograms verifies that the DEX programs used by tasks are deployed on the connected network
+func (r *Runner) checkPrograms(ctx context.Context, rep *readinessReport, tasks []*task.Task) {
+	programs := make(map[string]solana.PublicKey)
+	for _, t := range tasks {
+		switch strings.ToLower(t.Module) {
+		case "pump.fun":
+			programs["Pump.fun program"] = pumpfun.PumpFunProgramID
+		case "pump.swap":
+			programs["PumpSwap program"] = pumpswap.PumpSwapProgramID
+		case "snipe":
+			programs["Pump.fun program"] = pumpfun.PumpFunProgramID
+			programs["PumpSwap program"] = pumpswap.PumpSwapProgramID
+		case "jupiter":
+			programs["Jupiter program"] = jupiter.JupiterProgramID
+		}
+	}
+
+	names := make([]string, 0, len(programs))
+	for name := range programs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		id := programs[name]
+		info, err := r.checkClient("", true).GetAccountInfo(ctx, id)
+		switch {
+		case errors.Is(err, rpc.ErrNotFound):
+			rep.add(checkFailed, name, id.String()+" does not exist on this network")
+		case err != nil:
+			rep.add(checkWarn, name, "could not verify: "+err.Error())
+		case info == nil || info.Value == nil:
+			rep.add(checkFailed, name, id.String()+" does not exist on this network")
+		case !info.Value.Executable:
+			rep.add(checkFailed, name, id.String()+" is not an executable program")
+		default:
+			rep.add(checkOK, name, id.String())
+		}
+	}
+}
+
+// checkClient returns the client a check talks to: the shared pool for endpoint "",
+// the pool's per-endpoint client when pooled, or a standalone client otherwise.
+func (r *Runner) checkClient(endpoint string, pooled bool) readinessRPC {
+	switch {
+	case r.readinessDial != nil:
+		return r.readinessDial(endpoint)
+	case endpoint == "":
+		return r.solClient
+	case pooled:
+		return r.solClient.WithEndpoint(endpoint)
+	default:
+		return blockchain.NewClient(endpoint, r.logger)
+	}
+}
+
+func statusIcon(s checkStatus) string {
+	switch s {
+	case checkFailed:
+		return "❌"
+	case checkWarn:
+		return "⚠️ "
+	default:
+		return "✅"
+	}
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/jupiter"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpswap"
+	"github.com/rovshanmuradov/solana-bot/internal/portfolio"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// stubRPC отвечает на вызовы проверок готовности заданными значениями.
+type stubRPC struct {
+	slotErr    error
+	genesis    solana.Hash
+	genesisErr error
+	accountErr error
+	programs   map[solana.PublicKey]bool // Аккаунт существует; значение — исполняемый ли он
+}
+
+func (s *stubRPC) GetSlot(context.Context, rpc.CommitmentType) (uint64, error) {
+	return 42, s.slotErr
+}
+
+func (s *stubRPC) GetGenesisHash(context.Context) (solana.Hash, error) {
+	return s.genesis, s.genesisErr
+}
+
+func (s *stubRPC) GetAccountInfo(_ context.Context, pubkey solana.PublicKey) (*rpc.GetAccountInfoResult, error) {
+	if s.accountErr != nil {
+		return nil, s.accountErr
+	}
+	executable, ok := s.programs[pubkey]
+	if !ok {
+		return nil, rpc.ErrNotFound
+	}
+	return &rpc.GetAccountInfoResult{Value: &rpc.Account{Executable: executable}}, nil
+}
+
+type stubBalances struct {
+	balances []portfolio.WalletBalance
+}
+
+func (s stubBalances) Balances(context.Context, map[string]*task.Wallet) ([]portfolio.WalletBalance, error) {
+	return s.balances, nil
+}
+
+func (stubBalances) Invalidate(solana.PublicKey) {}
+
+// readinessRunner возвращает раннер, чьи проверки обращаются к заглушкам по URL эндпоинта.
+func readinessRunner(cfg *task.Config, clients map[string]*stubRPC) *Runner {
+	return &Runner{
+		logger: zap.NewNop(),
+		config: cfg,
+		readinessDial: func(endpoint string) readinessRPC {
+			return clients[endpoint]
+		},
+	}
+}
+
+func statuses(rep *readinessReport) []checkStatus {
+	out := make([]checkStatus, 0, len(rep.checks))
+	for _, c := range rep.checks {
+		out = append(out, c.status)
+	}
+	return out
+}
+
+func TestCheckRPCEndpoints(t *testing.T) {
+	mainnet := &stubRPC{genesis: blockchain.MainnetGenesisHash}
+	down := &stubRPC{slotErr: errors.New("connection refused")}
+	devnet := &stubRPC{genesis: solana.MustHashFromBase58("EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG")}
+	unknown := &stubRPC{genesisErr: errors.New("method not found")}
+
+	for name, tc := range map[string]struct {
+		primary, fallback *stubRPC
+		want              []checkStatus
+	}{
+		"both healthy":          {mainnet, mainnet, []checkStatus{checkOK, checkOK}},
+		"primary down":          {down, mainnet, []checkStatus{checkFailed, checkOK}},
+		"fallback down":         {mainnet, down, []checkStatus{checkOK, checkWarn}},
+		"primary on devnet":     {devnet, mainnet, []checkStatus{checkFailed, checkOK}},
+		"fallback on devnet":    {mainnet, devnet, []checkStatus{checkOK, checkFailed}},
+		"network not confirmed": {unknown, mainnet, []checkStatus{checkWarn, checkOK}},
+	} {
+		cfg := &task.Config{RPCList: []string{"https://primary.example", "https://fallback.example"}}
+		r := readinessRunner(cfg, map[string]*stubRPC{"https://primary.example": tc.primary, "https://fallback.example": tc.fallback})
+		rep := &readinessReport{}
+		r.checkRPCEndpoints(context.Background(), rep)
+		assert.Equal(t, tc.want, statuses(rep), name)
+		require.Len(t, rep.checks, 2, name)
+		assert.Equal(t, "Primary RPC primary.example", rep.checks[0].name, name)
+		assert.Equal(t, "Fallback RPC 1 fallback.example", rep.checks[1].name, name)
+	}
+}
+
+func TestCheckPrograms(t *testing.T) {
+	tasks := []*task.Task{{Module: "snipe"}, {Module: "Jupiter"}}
+	for name, tc := range map[string]struct {
+		client *stubRPC
+		want   map[string]checkStatus
+	}{
+		"deployed": {
+			client: &stubRPC{programs: map[solana.PublicKey]bool{pumpfun.PumpFunProgramID: true, pumpswap.PumpSwapProgramID: true, jupiter.JupiterProgramID: true}},
+			want:   map[string]checkStatus{"Jupiter program": checkOK, "Pump.fun program": checkOK, "PumpSwap program": checkOK},
+		},
+		"missing and not executable": {
+			client: &stubRPC{programs: map[solana.PublicKey]bool{pumpfun.PumpFunProgramID: true, jupiter.JupiterProgramID: false}},
+			want:   map[string]checkStatus{"Jupiter program": checkFailed, "Pump.fun program": checkOK, "PumpSwap program": checkFailed},
+		},
+		"rpc error": {
+			client: &stubRPC{accountErr: errors.New("timeout")},
+			want:   map[string]checkStatus{"Jupiter program": checkWarn, "Pump.fun program": checkWarn, "PumpSwap program": checkWarn},
+		},
+	} {
+		r := readinessRunner(&task.Config{}, map[string]*stubRPC{"": tc.client})
+		rep := &readinessReport{}
+		r.checkPrograms(context.Background(), rep, tasks)
+		got := make(map[string]checkStatus)
+		for _, c := range rep.checks {
+			got[c.name] = c.status
+		}
+		assert.Equal(t, tc.want, got, name)
+	}
+}
+
+func TestCheckReadiness_Headless(t *testing.T) {
+	wallet := &task.Wallet{Name: "main", PublicKey: solana.NewWallet().PublicKey()}
+	tasks := []*task.Task{{TaskName: "buy", WalletName: "main", Module: "pump.fun"}}
+	program := &stubRPC{genesis: blockchain.MainnetGenesisHash, programs: map[solana.PublicKey]bool{pumpfun.PumpFunProgramID: true}}
+
+	for name, tc := range map[string]struct {
+		lamports uint64
+		fallback *stubRPC
+		headless bool
+		wantErr  string
+	}{
+		"all checks pass":                 {lamports: 1e9, fallback: program, headless: true},
+		"fallback down is only a warning": {lamports: 1e9, fallback: &stubRPC{slotErr: errors.New("timeout")}, headless: true},
+		"failed check aborts headless":    {lamports: 0, fallback: program, headless: true, wantErr: "startup checks failed: 1 problem(s), see readiness report"},
+		"failed check only reported":      {lamports: 0, fallback: program},
+	} {
+		cfg := &task.Config{RPCList: []string{"https://primary.example", "https://fallback.example"}, Headless: tc.headless}
+		r := readinessRunner(cfg, map[string]*stubRPC{"": program, "https://primary.example": program, "https://fallback.example": tc.fallback})
+		r.keystore = true
+		r.wallets = map[string]*task.Wallet{"main": wallet}
+		r.balances = stubBalances{balances: []portfolio.WalletBalance{{Name: "main", Address: wallet.PublicKey, Lamports: tc.lamports}}}
+
+		err := r.checkReadiness(context.Background(), tasks)
+		if tc.wantErr != "" {
+			assert.EqualError(t, err, tc.wantErr, name)
+		} else {
+			assert.NoError(t, err, name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// internal/bot/restore.go
+package bot
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rovshanmuradov/solana-bot/internal/storage"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// restorePositions возвращает позиции, открытые до перезапуска, в мониторинг. Каждая
+// позиция продолжается задачей, которая ее открыла (в том виде, в каком она была при
+// покупке), и ставится в начало очереди. Строки tasks.csv, чьи покупки уже вошли
+// в позицию, не выполняются повторно.
+func (r *Runner) restorePositions(tasks []*task.Task) ([]*task.Task, map[string]storage.Position) {
+	open, err := r.store.Open()
+	if err != nil {
+		r.logger.Warn("⚠️  Failed to read position journal: " + err.Error())
+		return tasks, nil
+	}
+	if len(open) == 0 {
+		return tasks, nil
+	}
+
+	r.logger.Warn(fmt.Sprintf("♻️  Restoring %d open positions from the previous run", len(open)))
+	restored := make(map[string]storage.Position, len(open))
+	resumed := make([]*task.Task, 0, len(open)+len(tasks))
+	for _, p := range open {
+		restored[p.Key()] = p
+		t := p.Task
+		resumed = append(resumed, &t)
+		r.logger.Info(fmt.Sprintf("♻️  %s: %s (%s), %.3f SOL invested, entry %.10f SOL",
+			t.TaskName, p.Mint, p.Wallet, p.Invested(), p.EntryPrice))
+	}
+
+	for _, t := range tasks {
+		p, ok := restored[storage.PositionKey(t.WalletName, t.TokenMint)]
+		if ok && slices.ContainsFunc(p.Buys, func(b storage.Buy) bool { return b.Task == t.TaskName }) {
+			r.logger.Info(fmt.Sprintf("⏭️  Skipping task '%s': its buy is part of a restored position", t.TaskName))
+			continue
+		}
+		resumed = append(resumed, t)
+	}
+
+	if err := r.store.Compact(); err != nil {
+		r.logger.Warn("⚠️  Failed to compact position journal: " + err.Error())
+	}
+	return resumed, restored
+}
+
+// restoredPosition возвращает позицию, которую задача открыла до перезапуска.
+func (wp *WorkerPool) restoredPosition(t *task.Task) (storage.Position, bool) {
+	p, ok := wp.restoredPositions[storage.PositionKey(t.WalletName, t.TokenMint)]
+	if !ok || p.Task.TaskName != t.TaskName {
+		return storage.Position{}, false
+	}
+	return p, true
+}
+
+// savePosition записывает позицию в журнал открытых позиций.
+func (wp *WorkerPool) savePosition(pos *position, tokenBalance uint64, logger *zap.Logger) {
+	if err := wp.store.Save(pos.snapshot(tokenBalance)); err != nil {
+		logger.Warn("⚠️  Failed to save open position: " + err.Error())
+	}
+}
+
+// forgetPosition убирает позицию задачи из журнала открытых позиций.
+func (wp *WorkerPool) forgetPosition(t *task.Task, logger *zap.Logger) {
+	if err := wp.store.Close(t.WalletName, t.TokenMint); err != nil {
+		logger.Warn("⚠️  Failed to close saved position: " + err.Error())
+	}
+}
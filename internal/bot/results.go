@@ -0,0 +1,76 @@
+// internal/bot/results.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// taskResults собирает итоги задач, выполненных воркерами, для сводки в конце запуска.
+type taskResults struct {
+	mu          sync.Mutex
+	succeeded   int
+	interrupted int      // Прерваны остановкой бота
+	failed      []string // "задача: ошибка"
+}
+
+// record учитывает результат задачи t.
+func (r *taskResults) record(t *task.Task, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case err == nil:
+		r.succeeded++
+	case errors.Is(err, context.Canceled):
+		r.interrupted++
+	default:
+		r.failed = append(r.failed, fmt.Sprintf("%s: %v", t.TaskName, err))
+	}
+}
+
+// summary описывает итоги одной строкой, например "5 succeeded, 2 failed".
+func (r *taskResults) summary() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parts := []string{fmt.Sprintf("%d succeeded", r.succeeded), fmt.Sprintf("%d failed", len(r.failed))}
+	if r.interrupted > 0 {
+		parts = append(parts, fmt.Sprintf("%d interrupted", r.interrupted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// failures возвращает описания неудачных задач в порядке завершения.
+func (r *taskResults) failures() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.failed...)
+}
+
+// reportResults выводит итоги задач запуска и отправляет их уведомлением.
+func (wp *WorkerPool) reportResults() {
+	summary := wp.results.summary()
+	failures := wp.results.failures()
+
+	wp.logger.Info("📊 Tasks: " + summary)
+	for _, f := range failures {
+		wp.logger.Warn("   ❌ " + f)
+	}
+
+	severity := notify.SeverityInfo
+	if len(failures) > 0 {
+		severity = notify.SeverityWarning
+	}
+	wp.notifier.Notify(notify.Alert{
+		Type:     notify.AlertRunSummary,
+		Severity: severity,
+		Message:  strings.Join(append([]string{"Run finished: " + summary}, failures...), "\n"),
+	})
+}
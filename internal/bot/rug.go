@@ -0,0 +1,136 @@
+// internal/bot/rug.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// rugSentinel опрашивает цену, ликвидность и authority mint удерживаемого токена
+// чаще обычного мониторинга и при признаках rug-pull продает позицию сразу, не
+// дожидаясь следующего обновления цены: к тому моменту продавать обычно уже нечего.
+type rugSentinel struct {
+	sentinel *monitor.RugSentinel
+	client   *blockchain.Client
+	notifier *notify.Notifier
+	mint     solana.PublicKey
+	interval time.Duration
+	sellFn   SellFunc // Продажа с повышенными priority fee и slippage
+}
+
+// newRugSentinel создает сторожа позиции задачи. Возвращает nil, если сторож
+// выключен (rug_sentinel_interval = 0) или mint некорректен.
+func (wp *WorkerPool) newRugSentinel(t *task.Task, dexAdapter dex.DEX, logger *zap.Logger) *rugSentinel {
+	if wp.config.RugSentinelInterval <= 0 {
+		return nil
+	}
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		logger.Warn("⚠️  Rug sentinel disabled, invalid mint: " + err.Error())
+		return nil
+	}
+	return &rugSentinel{
+		sentinel: monitor.NewRugSentinel(wp.config.RugPriceDropPercent, wp.config.RugLiquidityDropPercent),
+		client:   wp.solClient,
+		notifier: wp.notifier,
+		mint:     mint,
+		interval: wp.config.RugSentinelInterval,
+		sellFn: wp.withSellAlerts(t, wp.withSellTrace(t, dexAdapter, CreateSellFunc(
+			dexAdapter,
+			t.TokenMint,
+			wp.config.RugSlippagePercent,
+			wp.config.RugPriorityFee,
+			t.ComputeUnits,
+			logger.Named("rug_sell"),
+		))),
+	}
+}
+
+// sample читает состояние токена для одного опроса; то, что прочитать не удалось,
+// остается неизвестным и не сравнивается.
+func (r *rugSentinel) sample(ctx context.Context, dexAdapter dex.DEX, tokenMint string) monitor.RugSample {
+	var s monitor.RugSample
+	if price, err := dexAdapter.GetTokenPrice(ctx, tokenMint); err == nil {
+		s.Price = price
+	}
+	if src, ok := dexAdapter.(dex.LiquiditySource); ok {
+		if liq, err := src.GetLiquidity(ctx, tokenMint); err == nil {
+			s.Venue, s.LiquiditySol, s.LPSupply, s.LiquidityKnown = liq.Venue, liq.Sol, liq.LPSupply, true
+		}
+	}
+	if state, _, err := r.client.GetMintState(ctx, r.mint, solana.PublicKey{}); err == nil {
+		s.MintAuthority, s.FreezeAuthority, s.AuthorityKnown = keyOrEmpty(state.MintAuthority), keyOrEmpty(state.FreezeAuthority), true
+	}
+	return s
+}
+
+func keyOrEmpty(k *solana.PublicKey) string {
+	if k == nil {
+		return ""
+	}
+	return k.String()
+}
+
+// watchRug опрашивает токен каждые rug_sentinel_interval и при признаках rug-pull
+// продает позицию целиком с rug_priority_fee и rug_slippage_percent.
+func (mw *MonitorWorker) watchRug(ctx context.Context) error {
+	r := mw.rug
+	if r == nil {
+		return nil
+	}
+	ticker := mw.clock.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-mw.stopped:
+			return nil
+		case <-ticker.C():
+		}
+
+		pollCtx, cancel := context.WithTimeout(ctx, r.interval+time.Second)
+		sample := r.sample(pollCtx, mw.dex, mw.task.TokenMint)
+		cancel()
+		reasons := r.sentinel.Check(sample)
+		if len(reasons) == 0 {
+			continue
+		}
+
+		select {
+		case <-mw.stopped:
+			return nil // Позицию уже продает другое правило
+		default:
+		}
+		detail := strings.Join(reasons, "; ")
+		mw.logger.Warn(fmt.Sprintf("🚨 Rug detected on %s (%s): %s, selling everything now",
+			mw.task.TokenMint, mw.task.WalletName, detail))
+		mw.history.event("rug_detected", detail)
+		r.notifier.Notify(notify.Alert{
+			Type:     notify.AlertRugDetected,
+			Key:      mw.task.TokenMint,
+			Severity: notify.SeverityCritical,
+			Message:  fmt.Sprintf("Rug detected on %s (%s), selling the whole position: %s", mw.task.TokenMint, mw.task.WalletName, detail),
+		})
+
+		mw.Stop()
+		if err := r.sellFn(ctx, 100); err != nil {
+			mw.logger.Error("❌ Rug exit sell failed: " + err.Error())
+			return err
+		}
+		mw.history.finish(execution.OutcomeSold, "rug")
+		return nil
+	}
+}
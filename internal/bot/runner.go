@@ -3,30 +3,77 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"github.com/rovshanmuradov/solana-bot/internal/backtest"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/jupiter"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpswap"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
 	"github.com/rovshanmuradov/solana-bot/internal/license"
+	"github.com/rovshanmuradov/solana-bot/internal/localrpc"
+	"github.com/rovshanmuradov/solana-bot/internal/metrics"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/orders"
+	"github.com/rovshanmuradov/solana-bot/internal/portfolio"
+	"github.com/rovshanmuradov/solana-bot/internal/storage"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"go.uber.org/zap"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 )
 
+// Files with wallets and tasks, relative to the working directory
+const (
+	walletsPath = "configs/wallets.csv"
+	tasksPath   = "configs/tasks.csv"
+)
+
+// Task files tried in order; tasks.csv stays the fallback for existing setups
+var tasksPaths = []string{"configs/tasks.yaml", "configs/tasks.yml", "configs/tasks.json", tasksPath}
+
 type Runner struct {
 	logger        *zap.Logger
 	config        *task.Config
 	solClient     *blockchain.Client
 	taskManager   *task.Manager
 	wallets       map[string]*task.Wallet
+	keystore      bool // Кошельки загружены из зашифрованного хранилища, а не из wallets.csv
 	defaultWallet *task.Wallet
+	notifier      *notify.Notifier
+	telegram      *notify.TelegramSink // Чат Telegram для команд (nil — выключен)
+	balances      balanceSource
+	recorder      *execution.Recorder
+	positions     *metrics.Positions
+	intents       *execution.IntentLog
+	sessions      *execution.SessionArchive
+	orders        *orders.Book
+	store         *storage.Positions
+	market        *backtest.Recorder       // Запись снимков рынка для бэктеста (nil — выключена)
+	geyser        *blockchain.GeyserClient // Поток Yellowstone gRPC (nil — только WebSocket и опрос)
+	clock         clock.Clock
 	shutdownCh    chan os.Signal
+
+	readinessDial func(endpoint string) readinessRPC // Клиенты проверок готовности в тестах ("" — общий пул)
+}
+
+// balanceSource — балансы кошельков с кешем (*portfolio.BalanceService).
+type balanceSource interface {
+	Balances(ctx context.Context, wallets map[string]*task.Wallet) ([]portfolio.WalletBalance, error)
+	Invalidate(owner solana.PublicKey)
 }
 
 // NewRunner NewRunner: принимает cfg и logger
 func NewRunner(cfg *task.Config, logger *zap.Logger) *Runner {
 	// Загружаем кошельки
-	wallets, err := task.LoadWallets("configs/wallets.csv")
+	wallets, encrypted, journals, err := loadWallets(cfg, logger)
 	if err != nil {
 		logger.Fatal("💥 Failed to load wallets: " + err.Error())
 	}
@@ -48,21 +95,143 @@ func NewRunner(cfg *task.Config, logger *zap.Logger) *Runner {
 		}
 	}
 
+	// Уведомления отправляются только если настроен хотя бы один канал
+	sinks, telegram := alertSinks(cfg, logger)
+	var notifier *notify.Notifier
+	if len(sinks) > 0 {
+		notifier = notify.New(logger, notify.Options{
+			DedupeWindow:    cfg.AlertDedupeWindow,
+			AggregateWindow: cfg.AlertAggregateWindow,
+			RatePerMinute:   cfg.AlertRateLimit,
+			MaxAge:          cfg.AlertMaxAge,
+			BufferDir:       notify.DefaultBufferDir,
+		}, sinks...)
+	}
+
+	// Все RPC из rpc_list работают как один клиент с переключением при сбоях
+	endpointLimits := make(map[string]blockchain.RateLimit, len(cfg.RPCRateLimits))
+	for _, l := range cfg.RPCRateLimits {
+		endpointLimits[l.URL] = blockchain.RateLimit{PerSecond: l.RPS, Burst: l.Burst}
+	}
+	rpcPool := blockchain.NewPool(cfg.RPCList, blockchain.PoolOptions{
+		Routing:         cfg.RPCRouting,
+		FailureCooldown: cfg.RPCFailureCooldown,
+		RateLimit:       blockchain.RateLimit{PerSecond: cfg.RPCRateLimit, Burst: cfg.RPCBurst},
+		EndpointLimits:  endpointLimits,
+	}, logger)
+	solClient := blockchain.NewPoolClient(rpcPool, logger)
+	solClient.SetAccountBatching(cfg.RPCBatchWindow)
+	// Подтверждения приходят подпиской signatureSubscribe, опрос статусов остается страховкой
+	solClient.SetConfirmationSocket(cfg.WebSocketURL)
+
+	// Источник рекомендаций для priority fee "auto"
+	feeURL := cfg.PriorityFeeURL
+	if feeURL == "" {
+		feeURL = cfg.RPCList[0]
+	}
+	feeProvider, err := blockchain.NewPriorityFeeProvider(cfg.PriorityFeeSource, feeURL)
+	if err != nil {
+		logger.Fatal("💥 Failed to configure priority fee provider: " + err.Error())
+	}
+	solClient.SetPriorityFeeProvider(feeProvider)
+	solClient.SetFeeEscalation(blockchain.FeeEscalation{
+		Interval:    cfg.RebroadcastInterval,
+		StepPercent: cfg.RebroadcastFeeStepPercent,
+		MaxPrice:    cfg.RebroadcastMaxCUPrice,
+		MaxAttempts: cfg.RebroadcastMaxAttempts,
+	})
+	solClient.SetResubmitDeadline(cfg.ResubmitDeadline)
+	if cfg.SimulateBeforeSend {
+		solClient.SetSimulateBeforeSend(true)
+		logger.Info("🧪 Transactions are simulated before sending")
+	}
+
+	// Внешний плательщик комиссий для кошельков без fee_payer в группе
+	if cfg.FeeSponsorURL != "" {
+		sponsorCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		sponsor, err := blockchain.NewHTTPFeeSponsor(sponsorCtx, cfg.FeeSponsorURL)
+		cancel()
+		if err != nil {
+			logger.Fatal("💥 Failed to configure fee sponsor: " + err.Error())
+		}
+		n := task.AttachFeeSponsor(wallets, sponsor)
+		logger.Info(fmt.Sprintf("⛽ Fee sponsor %s pays fees for %d wallets", sponsor.PublicKey(), n))
+	}
+
+	if err := pumpswap.UseLookupTable(cfg.PumpSwapLookupTable); err != nil {
+		logger.Fatal("💥 Failed to configure PumpSwap lookup table: " + err.Error())
+	}
+	if err := jupiter.UseAPI(cfg.JupiterAPIURL); err != nil {
+		logger.Fatal("💥 Failed to configure Jupiter API: " + err.Error())
+	}
+
+	// Поток Geyser ускоряет слушателей логов и мониторинг цены; WebSocket и опрос остаются запасными
+	var geyser *blockchain.GeyserClient
+	if cfg.GeyserEndpoint != "" {
+		geyser, err = blockchain.NewGeyserClient(cfg.GeyserEndpoint, cfg.GeyserToken)
+		if err != nil {
+			logger.Fatal("💥 Failed to configure Geyser stream: " + err.Error())
+		}
+		logger.Info("🛰️  Account and transaction updates stream over Geyser")
+	}
+
+	// Позиции собираются только если их кто-то читает: эндпоинт метрик или локальный JSON-RPC
+	var positions *metrics.Positions
+	if cfg.MetricsAddr != "" || cfg.LocalRPCAddr != "" {
+		positions = metrics.NewPositions()
+	}
+
+	trades := execution.NewStore(execution.DefaultStorePath)
+	trades.SetCipher(journals)
+	store := storage.NewPositions(storage.DefaultPositionsPath)
+	store.SetCipher(journals)
+
 	return &Runner{
 		logger:        logger,
 		config:        cfg,
-		solClient:     blockchain.NewClient(cfg.RPCList[0], logger),
+		solClient:     solClient,
 		taskManager:   task.NewManager(logger),
 		wallets:       wallets,
+		keystore:      encrypted,
 		defaultWallet: defaultW,
+		notifier:      notifier,
+		telegram:      telegram,
+		balances:      portfolio.NewBalanceService(solClient, logger, portfolio.DefaultBalanceTTL),
+		recorder:      execution.NewRecorder(solClient, trades, logger),
+		positions:     positions,
+		intents:       execution.NewIntentLog(execution.DefaultIntentPath),
+		sessions:      execution.NewSessionArchive(execution.DefaultSessionPath),
+		orders:        orders.NewBook(orders.DefaultPath),
+		store:         store,
+		geyser:        geyser,
+		clock:         clock.Real,
 		shutdownCh:    make(chan os.Signal, 1),
 	}
 }
 
+// pruneSessions удаляет из архива сессии старше session_retention_days
+func (r *Runner) pruneSessions() {
+	if r.config.SessionRetentionDays <= 0 {
+		return
+	}
+	cutoff := r.clock.Now().AddDate(0, 0, -r.config.SessionRetentionDays)
+	n, err := r.sessions.Prune(cutoff)
+	if err != nil {
+		r.logger.Warn("⚠️  Failed to prune session archive: " + err.Error())
+		return
+	}
+	if n > 0 {
+		r.logger.Info(fmt.Sprintf("🗄️  Pruned %d archived sessions older than %d days", n, r.config.SessionRetentionDays))
+	}
+}
+
 func (r *Runner) Run(ctx context.Context) error {
 	signal.Notify(r.shutdownCh, syscall.SIGINT, syscall.SIGTERM)
 	shutdownCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	defer r.notifier.Close()
+	// Ключи стираются из памяти, когда торговля закончена
+	defer task.ZeroWallets(r.wallets)
 
 	go func() {
 		sig := <-r.shutdownCh
@@ -75,17 +244,107 @@ func (r *Runner) Run(ctx context.Context) error {
 		return fmt.Errorf("license validation failed: %w", err)
 	}
 
-	tasks, err := r.taskManager.LoadTasks("configs/tasks.csv")
+	if r.config.ReadOnly {
+		return r.runReadOnly(shutdownCtx)
+	}
+	if r.config.Portfolio {
+		return r.runPortfolio(shutdownCtx)
+	}
+	if r.config.CloseEmpty {
+		return r.runCleanup(shutdownCtx)
+	}
+
+	lock, err := acquireInstanceLock(r.config.InstancePort)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	if r.config.MetricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(shutdownCtx, r.config.MetricsAddr, r.positions, r.logger); err != nil {
+				r.logger.Error("❌ Metrics endpoint failed: " + err.Error())
+			}
+		}()
+		r.logger.Info("📈 Metrics endpoint: http://" + r.config.MetricsAddr + "/metrics")
+	}
+
+	// Изменяющие команды из Telegram и local RPC и отказы в доступе
+	auditLog := audit.NewLog(audit.DefaultPath)
+
+	var rpcService *localrpc.Service
+	if r.config.LocalRPCAddr != "" {
+		svc := localrpc.NewService(r.positions, r.recorder.Store(), r.sessions, r.orders)
+		svc.SetAPIKey(r.config.LocalRPCAPIKey)
+		for _, c := range r.config.LocalRPCClients {
+			role, _ := audit.ParseRole(c.Role) // Проверена в LoadConfig
+			svc.AddClient(c.Name, c.APIKey, role)
+		}
+		svc.SetAudit(auditLog, r.logger)
+		go func() {
+			if err := localrpc.Serve(shutdownCtx, r.config.LocalRPCAddr, svc, r.logger); err != nil {
+				r.logger.Error("❌ Local JSON-RPC failed: " + err.Error())
+			}
+		}()
+		r.logger.Info("🔌 Local JSON-RPC: " + r.config.LocalRPCAddr)
+		rpcService = svc
+	}
+
+	if r.config.RecordMarketData {
+		r.market = backtest.NewRecorder(backtest.DefaultRecorderDir)
+		go func() {
+			if err := r.market.Run(shutdownCtx); err != nil {
+				r.logger.Error("❌ Market data recorder failed: " + err.Error())
+			}
+		}()
+		// Снимки последних секунд дописываются и при штатном завершении
+		defer r.market.Flush()
+		r.logger.Info("🎞️  Recording market snapshots to " + r.market.Path(r.clock.Now()))
+	}
+
+	r.logWalletBalances(ctx)
+
+	tasks, err := r.taskManager.LoadTasks(r.tasksFile())
 	if err != nil {
 		return err
 	}
+	tasks = r.taskManager.ResolveWallets(tasks, r.wallets)
+	lastID := 0
+	for _, t := range tasks {
+		lastID = max(lastID, t.ID)
+	}
+	tasks, templates := task.SplitTemplates(tasks)
 	r.logger.Info(fmt.Sprintf("📋 Loaded %d trading tasks", len(tasks)))
 
-	taskCh := make(chan *task.Task, len(tasks))
+	if err := r.checkReadiness(ctx, tasks); err != nil {
+		return err
+	}
+
+	if err := r.checkPumpFunLayout(ctx, tasks); err != nil {
+		return err
+	}
+
+	recovered := r.reconcileIntents(shutdownCtx)
+	tasks, restored := r.restorePositions(tasks)
+	r.pruneSessions()
+
+	if r.config.RPCHealthCheckInterval > 0 && len(r.config.RPCList) > 1 {
+		go r.solClient.Pool().CheckHealth(shutdownCtx, r.config.RPCHealthCheckInterval)
+		r.logger.Info(fmt.Sprintf("🩺 RPC health checks every %v across %d endpoints (%s routing)",
+			r.config.RPCHealthCheckInterval, len(r.config.RPCList), r.config.RPCRouting))
+	}
+
+	if r.config.BlockhashRefresh > 0 {
+		go r.solClient.PrefetchBlockhash(shutdownCtx, r.config.BlockhashRefresh)
+		r.logger.Info(fmt.Sprintf("🧱 Blockhash prefetch every %v", r.config.BlockhashRefresh))
+	}
+
+	taskCh := make(chan *task.Task, len(tasks)+dynamicQueue)
+	tasks, scheduled := task.SplitScheduled(tasks, r.clock.Now())
 	for _, t := range tasks {
 		taskCh <- t
 	}
-	close(taskCh)
+	r.startDynamicTasks(shutdownCtx, templates, scheduled, taskCh, lastID)
 
 	numWorkers := r.config.Workers
 	if numWorkers <= 0 {
@@ -99,16 +358,72 @@ func (r *Runner) Run(ctx context.Context) error {
 		r.logger,
 		r.solClient,
 		r.wallets,
+		r.notifier,
+		r.recorder,
+		r.positions,
+		r.intents,
+		r.sessions,
+		r.orders,
+		r.store,
+		recovered,
+		restored,
 		taskCh,
 	)
+	workerPool.clock = r.clock
+	workerPool.market = r.market
+	workerPool.geyser = r.geyser
+	workerPool.audit = auditLog
+
+	if r.telegram != nil {
+		go r.telegram.Commands(shutdownCtx, workerPool.remoteCommand)
+	}
+	if rpcService != nil {
+		rpcService.SetKillSwitch(workerPool)
+	}
 
 	workerPool.Start(numWorkers)
 	workerPool.Wait()
 
 	r.logger.Info("✅ All workers finished")
+	workerPool.reportResults()
+	r.logExecutionReport()
+	r.logRPCUsage()
 	return nil
 }
 
+// tasksFile returns the first task file of tasksPaths that exists, warning when
+// several do since only one is loaded.
+func (r *Runner) tasksFile() string {
+	var found []string
+	for _, path := range tasksPaths {
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+	if len(found) == 0 {
+		return tasksPath
+	}
+	if len(found) > 1 {
+		r.logger.Warn(fmt.Sprintf("⚠️  Several task files found, using %s and ignoring %s",
+			found[0], strings.Join(found[1:], ", ")))
+	}
+	return found[0]
+}
+
+// logExecutionReport prints the weekly execution quality section of the run summary
+func (r *Runner) logExecutionReport() {
+	const period = 7 * 24 * time.Hour
+
+	records, err := r.recorder.Store().Load(time.Now().Add(-period))
+	if err != nil {
+		r.logger.Warn("⚠️  Failed to load execution records: " + err.Error())
+		return
+	}
+	for _, line := range execution.ReportLines(records, period) {
+		r.logger.Info(line)
+	}
+}
+
 func (r *Runner) Shutdown() {
 	r.logger.Info("👋 Bot shutting down gracefully")
 
@@ -127,6 +442,73 @@ func (r *Runner) WaitForShutdown() {
 	r.Shutdown()
 }
 
+// checkPumpFunLayout verifies pump.fun account layouts before any task can trade on them.
+// A layout mismatch aborts the run; RPC failures only produce a warning.
+func (r *Runner) checkPumpFunLayout(ctx context.Context, tasks []*task.Task) error {
+	var referenceMint solana.PublicKey
+	usesPumpFun := false
+	for _, t := range tasks {
+		if t.Module != "pump.fun" && t.Module != "snipe" {
+			continue
+		}
+		usesPumpFun = true
+		if mint, err := solana.PublicKeyFromBase58(t.TokenMint); err == nil {
+			referenceMint = mint
+			break
+		}
+	}
+	if !usesPumpFun {
+		return nil
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := pumpfun.SelfCheck(checkCtx, r.solClient, referenceMint, r.logger)
+	if errors.Is(err, pumpfun.ErrLayoutMismatch) {
+		return fmt.Errorf("refusing to trade: %w (the pump.fun program may have been upgraded; update the bot)", err)
+	}
+	if err != nil {
+		r.logger.Warn("⚠️  Pump.fun self-check skipped: " + err.Error())
+		return nil
+	}
+
+	r.logger.Info("✅ Pump.fun account layout verified")
+	return nil
+}
+
+// logWalletBalances prints a one-line balance summary per wallet
+func (r *Runner) logWalletBalances(ctx context.Context) {
+	balances, err := r.balances.Balances(ctx, r.wallets)
+	if err != nil {
+		r.logger.Warn("⚠️  Failed to fetch wallet balances: " + err.Error())
+		return
+	}
+
+	for _, b := range balances {
+		held := 0
+		for _, t := range b.Tokens {
+			if t.Amount > 0 {
+				held++
+			}
+		}
+		r.logger.Info(fmt.Sprintf("💼 %s: %.4f SOL, %d tokens", b.Name, b.SOL(), held))
+	}
+
+	// Сводка по ролям имеет смысл, только если роли назначены
+	totals := portfolio.TotalsByRole(balances)
+	if len(totals) < 2 && (len(totals) == 0 || totals[0].Role == task.RoleNone) {
+		return
+	}
+	for _, t := range totals {
+		role := string(t.Role)
+		if role == "" {
+			role = "unassigned"
+		}
+		r.logger.Info(fmt.Sprintf("🏷️  Role %s: %d wallets, %.4f SOL", role, t.Wallets, t.SOL()))
+	}
+}
+
 // validateLicense validates the license using either Keygen or fallback validation
 func (r *Runner) validateLicense(ctx context.Context) error {
 	// Check if Keygen is configured
@@ -186,3 +568,23 @@ func (r *Runner) validateSimple() error {
 	r.logger.Info("✅ License validated (basic mode)")
 	return nil
 }
+
+// logRPCUsage prints how many requests each RPC endpoint served and how long they
+// waited for its rate limit, to help tune rpc_rate_limit against the provider plan
+func (r *Runner) logRPCUsage() {
+	pool := r.solClient.Pool()
+	if pool == nil {
+		return
+	}
+	for _, ep := range pool.Status() {
+		line := fmt.Sprintf("📡 RPC %s: %d requests", ep.Host, ep.Requests)
+		if ep.TaskOnly {
+			line = fmt.Sprintf("📡 Task RPC %s: %d requests", ep.Host, ep.Requests)
+		}
+		if ep.Throttled > 0 {
+			line += fmt.Sprintf(", %d throttled, avg wait %s",
+				ep.Throttled, (ep.Waited / time.Duration(ep.Throttled)).Round(time.Millisecond))
+		}
+		r.logger.Info(line)
+	}
+}
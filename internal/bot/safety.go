@@ -0,0 +1,48 @@
+// internal/bot/safety.go
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// checkSafety оценивает риск токена перед покупкой. При оценке выше safety_max_risk
+// покупка отменяется (safety_action = "abort") или только сопровождается предупреждением.
+func (wp *WorkerPool) checkSafety(ctx context.Context, t *task.Task, logger *zap.Logger) error {
+	if wp.safety == nil {
+		return nil
+	}
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		return fmt.Errorf("invalid token mint: %w", err)
+	}
+
+	report, err := wp.safety.Check(ctx, mint)
+	if err != nil {
+		logger.Warn("⚠️  Could not check token safety: " + err.Error())
+		return nil
+	}
+	if report.Score <= wp.config.SafetyMaxRisk {
+		logger.Info(fmt.Sprintf("🛡️  Risk score %d/100 for %s: %s", report.Score, t.TokenMint, report.Risks()))
+		return nil
+	}
+
+	msg := fmt.Sprintf("risk score %d exceeds %d for %s: %s", report.Score, wp.config.SafetyMaxRisk, t.TokenMint, report.Risks())
+	if wp.config.SafetyAction == "warn" {
+		logger.Warn("🚩 Buying despite " + msg)
+		wp.notifier.Notify(notify.Alert{
+			Type:     notify.AlertMintRisk,
+			Key:      t.TokenMint,
+			Severity: notify.SeverityWarning,
+			Message:  fmt.Sprintf("%s (%s) bought despite %s", t.TaskName, t.WalletName, msg),
+		})
+		return nil
+	}
+	return fmt.Errorf("buy aborted: %s", msg)
+}
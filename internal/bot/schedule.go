@@ -0,0 +1,77 @@
+// internal/bot/schedule.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// scheduleReportInterval — как часто в лог выводится обратный отсчет до отложенных задач.
+const scheduleReportInterval = time.Minute
+
+// errTaskExpired — задача не началась до своего expire_at.
+var errTaskExpired = errors.New("task expired before it started")
+
+// runScheduledTasks ставит задачи с start_at в очередь воркеров в их время и раз
+// в scheduleReportInterval выводит обратный отсчет, пока не отменен ctx.
+// scheduled упорядочены по start_at.
+func (r *Runner) runScheduledTasks(ctx context.Context, scheduled []*task.Task, taskCh chan<- *task.Task) {
+	r.logger.Info(fmt.Sprintf("⏰ %d scheduled tasks:\n%s", len(scheduled), describeSchedule(scheduled, r.clock.Now())))
+	report := r.clock.NewTicker(scheduleReportInterval)
+	defer report.Stop()
+
+	for len(scheduled) > 0 {
+		next := scheduled[0]
+		select {
+		case <-ctx.Done():
+			return
+		case <-report.C():
+			r.logger.Info("⏳ Scheduled tasks:\n" + describeSchedule(scheduled, r.clock.Now()))
+			continue
+		case <-r.clock.After(next.StartAt.Sub(r.clock.Now())):
+		}
+
+		scheduled = scheduled[1:]
+		r.logger.Info(fmt.Sprintf("⏰ Start time reached for task %s", next.TaskName))
+		select {
+		case taskCh <- next:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// describeSchedule перечисляет отложенные задачи с обратным отсчетом до запуска.
+func describeSchedule(scheduled []*task.Task, now time.Time) string {
+	lines := make([]string, 0, len(scheduled))
+	for _, t := range scheduled {
+		line := fmt.Sprintf("   %s (%s) starts in %s at %s", t.TaskName, t.Operation,
+			t.StartAt.Sub(now).Round(time.Second), t.StartAt.Format("2006-01-02 15:04:05"))
+		if !t.ExpireAt.IsZero() {
+			line += ", expires at " + t.ExpireAt.Format("15:04:05")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkSchedule пропускает задачу, чей start_at еще не наступил (задачи по шаблонам
+// до начала окна) или чей expire_at уже прошел, пока задача ждала воркера.
+func (wp *WorkerPool) checkSchedule(t *task.Task, logger *zap.Logger) error {
+	now := wp.clock.Now()
+	switch {
+	case t.Expired(now):
+		logger.Warn(fmt.Sprintf("⌛ Task %s expired at %s before it started, skipping", t.TaskName, t.ExpireAt.Format("15:04:05")))
+		return fmt.Errorf("%w: expire_at %s", errTaskExpired, t.ExpireAt.Format(time.RFC3339))
+	case t.Scheduled(now):
+		logger.Info(fmt.Sprintf("⏰ Task %s is not armed until %s, skipping", t.TaskName, t.StartAt.Format("15:04:05")))
+		return fmt.Errorf("task starts at %s", t.StartAt.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -4,9 +4,15 @@ package bot
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
+	"github.com/gagliardetto/solana-go"
 	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"go.uber.org/zap"
 )
 
@@ -38,8 +44,8 @@ func SellTokens(
 
 		logger.Info(fmt.Sprintf("💱 Starting token sell: %s (%.1f%% at %.1f%% slippage)", tokenMint, percent, slippagePercent))
 
-		// Создаем отдельный контекст с таймаутом для операции продажи
-		sellCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		// Отдельный таймаут нужен, только если вызывающий не задал бюджет сделки
+		sellCtx, cancel := execution.StageContext(ctx, 60*time.Second)
 		defer cancel()
 
 		// Выполняем продажу
@@ -97,3 +103,122 @@ func CreateSellFunc(
 		}
 	}
 }
+
+// handleSellTask продает токены, уже лежащие в кошельке: баланс берется из сети,
+// поэтому задаче не нужны предшествующая покупка или сессия мониторинга.
+func (wp *WorkerPool) handleSellTask(ctx context.Context, t *task.Task, dexAdapter dex.DEX, logger *zap.Logger) error {
+	if wp.recovered(t, execution.SideSell) {
+		logger.Warn("♻️  Sell landed before the restart, not selling again: " + t.TaskName)
+		return nil
+	}
+
+	balanceCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	balance, err := dexAdapter.GetTokenBalance(balanceCtx, t.TokenMint)
+	if err != nil {
+		return fmt.Errorf("resolve token balance: %w", err)
+	}
+	if balance == 0 {
+		return fmt.Errorf("wallet %s holds no %s tokens", t.WalletName, t.TokenMint)
+	}
+
+	var decimals uint8
+	if t.SellAmount > 0 {
+		mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+		if err != nil {
+			return fmt.Errorf("invalid token mint: %w", err)
+		}
+		if decimals, err = wp.solClient.GetMintDecimals(balanceCtx, mint); err != nil {
+			return fmt.Errorf("resolve token decimals: %w", err)
+		}
+	}
+	plan, err := wp.planSell(t, balance, decimals)
+	if err != nil {
+		return err
+	}
+	if plan.capped {
+		logger.Warn(fmt.Sprintf("⚠️  sell_amount %s exceeds the balance, selling all %d tokens",
+			strconv.FormatFloat(t.SellAmount, 'f', -1, 64), balance))
+	}
+	if plan.swept {
+		logger.Info("🧹 Selling the whole balance: the rest would be dust")
+	}
+
+	if wp.config.ApprovalAboveSol > 0 {
+		value, err := wp.sellValue(balanceCtx, t, dexAdapter, plan.unitsOf(balance))
+		if err != nil {
+			logger.Warn("⚠️  Could not estimate sell value, asking for approval: " + err.Error())
+			value = math.Inf(1)
+		}
+		if err := wp.approveOrder(ctx, t, execution.SideSell, value, logger); err != nil {
+			return err
+		}
+	}
+
+	slippage, _ := wp.sellSlippage(t, dexAdapter, logger)
+	logger.Info(fmt.Sprintf("💱 Selling %d of %d tokens of %s from %s", plan.unitsOf(balance), balance, t.TokenMint, t.WalletName))
+
+	tradeCtx, cancel := execution.WithBudget(ctx, wp.config.TradeDeadline)
+	defer cancel()
+
+	traceCtx, tr, err := wp.beginTrade(tradeCtx, t, dexAdapter, execution.SideSell)
+	if err != nil {
+		return err
+	}
+	if plan.units > 0 {
+		err = dexAdapter.SellTokenAmount(traceCtx, t.TokenMint, plan.units, slippage, t.PriorityFeeSol, t.ComputeUnits)
+	} else {
+		err = dexAdapter.SellPercentTokens(traceCtx, t.TokenMint, plan.percent, slippage, t.PriorityFeeSol, t.ComputeUnits)
+	}
+	return wp.finishTrade(tradeCtx, t, tr, err)
+}
+
+// sellPlan — сколько продает задача sell: ровно units базовых единиц (sell_amount)
+// или percent процентов баланса.
+type sellPlan struct {
+	percent float64
+	units   uint64 // > 0 — точное количество, иначе percent
+	capped  bool   // sell_amount больше баланса: продается весь баланс
+	swept   bool   // Остаток был бы пылью: продается весь баланс
+}
+
+// unitsOf возвращает количество базовых единиц, которое продаст план при балансе balance.
+func (p sellPlan) unitsOf(balance uint64) uint64 {
+	if p.units > 0 {
+		return p.units
+	}
+	return model.PercentOf(balance, p.percent)
+}
+
+// planSell переводит percent_to_sell или sell_amount задачи в план продажи баланса
+// balance. sell_amount переводится в базовые единицы без float, с учетом decimals.
+func (wp *WorkerPool) planSell(t *task.Task, balance uint64, decimals uint8) (sellPlan, error) {
+	if t.SellAmount <= 0 {
+		percent := wp.sweepDust(t.AutosellAmount)
+		return sellPlan{percent: percent, swept: percent != t.AutosellAmount}, nil
+	}
+
+	units, err := model.TokensToBaseUnits(t.SellAmount, decimals)
+	if err != nil {
+		return sellPlan{}, fmt.Errorf("invalid sell_amount: %w", err)
+	}
+	if units == 0 {
+		return sellPlan{}, fmt.Errorf("sell_amount %s is below one base unit of the token", strconv.FormatFloat(t.SellAmount, 'f', -1, 64))
+	}
+	if units >= balance {
+		return sellPlan{percent: 100, capped: units > balance}, nil
+	}
+	if wp.config.SweepDustPercent > 0 && float64(balance-units) <= float64(balance)*wp.config.SweepDustPercent/100 {
+		return sellPlan{percent: 100, swept: true}, nil
+	}
+	return sellPlan{percent: float64(units) / float64(balance) * 100, units: units}, nil
+}
+
+// sweepDust поднимает процент продажи до 100, если остаток был бы меньше sweep_dust_percent баланса
+func (wp *WorkerPool) sweepDust(percent float64) float64 {
+	if percent < 100 && 100-percent <= wp.config.SweepDustPercent {
+		return 100
+	}
+	return percent
+}
@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+func TestPlanSell_AmountInBaseUnits(t *testing.T) {
+	wp := &WorkerPool{config: &task.Config{}}
+
+	// 0.3 * 10^6 во float дает 299999.99999999994: продалось бы на единицу меньше
+	plan, err := wp.planSell(&task.Task{SellAmount: 0.3}, 1_000_000, 6)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300_000), plan.units)
+	assert.Equal(t, uint64(300_000), plan.unitsOf(1_000_000))
+
+	_, err = wp.planSell(&task.Task{SellAmount: 0.0000001}, 1_000_000, 6)
+	assert.ErrorContains(t, err, "below one base unit")
+}
+
+func TestPlanSell(t *testing.T) {
+	const balance = 1_000_000 // 1 токен при 6 decimals
+	for name, tc := range map[string]struct {
+		task  task.Task
+		sweep float64 // sweep_dust_percent
+		want  sellPlan
+		units uint64 // Продается базовых единиц
+	}{
+		"percent of the balance":       {task: task.Task{AutosellAmount: 50}, want: sellPlan{percent: 50}, units: 500_000},
+		"percent leaving dust":         {task: task.Task{AutosellAmount: 98}, sweep: 5, want: sellPlan{percent: 100, swept: true}, units: balance},
+		"percent above the dust limit": {task: task.Task{AutosellAmount: 90}, sweep: 5, want: sellPlan{percent: 90}, units: 900_000},
+		"exact amount":                 {task: task.Task{SellAmount: 0.25}, want: sellPlan{percent: 25, units: 250_000}, units: 250_000},
+		"amount of the whole balance":  {task: task.Task{SellAmount: 1}, want: sellPlan{percent: 100}, units: balance},
+		"amount above the balance":     {task: task.Task{SellAmount: 5}, want: sellPlan{percent: 100, capped: true}, units: balance},
+		"amount leaving dust":          {task: task.Task{SellAmount: 0.99}, sweep: 2, want: sellPlan{percent: 100, swept: true}, units: balance},
+		"amount above the dust limit":  {task: task.Task{SellAmount: 0.9}, sweep: 2, want: sellPlan{percent: 90, units: 900_000}, units: 900_000},
+	} {
+		wp := &WorkerPool{config: &task.Config{SweepDustPercent: tc.sweep}}
+		plan, err := wp.planSell(&tc.task, balance, 6)
+		require.NoError(t, err, name)
+		assert.Equal(t, tc.want, plan, name)
+		assert.Equal(t, tc.units, plan.unitsOf(balance), name)
+	}
+}
+
+func TestSweepDust(t *testing.T) {
+	for name, tc := range map[string]struct {
+		sweep, percent, want float64
+	}{
+		"off":                 {sweep: 0, percent: 99.9, want: 99.9},
+		"rest is dust":        {sweep: 3, percent: 97, want: 100},
+		"rest is not dust":    {sweep: 3, percent: 96.9, want: 96.9},
+		"whole balance as is": {sweep: 3, percent: 100, want: 100},
+	} {
+		wp := &WorkerPool{config: &task.Config{SweepDustPercent: tc.sweep}}
+		assert.Equal(t, tc.want, wp.sweepDust(tc.percent), name)
+	}
+}
+
+// sellDEX запоминает, какой продажей адаптера воспользовалась задача.
+type sellDEX struct {
+	dex.DEX
+	balance uint64
+	percent float64 // Аргумент SellPercentTokens (0 — не вызывался)
+	units   uint64  // Аргумент SellTokenAmount (0 — не вызывался)
+}
+
+func (d *sellDEX) GetName() string { return "Sim" }
+
+func (d *sellDEX) GetTokenBalance(context.Context, string) (uint64, error) {
+	return d.balance, nil
+}
+
+func (d *sellDEX) SellPercentTokens(_ context.Context, _ string, percent, _ float64, _ string, _ uint32) error {
+	d.percent = percent
+	return nil
+}
+
+func (d *sellDEX) SellTokenAmount(_ context.Context, _ string, amount uint64, _ float64, _ string, _ uint32) error {
+	d.units = amount
+	return nil
+}
+
+// mintRPC отвечает на getAccountInfo аккаунтом SPL mint с заданными decimals.
+func mintRPC(t *testing.T, decimals uint8) *httptest.Server {
+	data := make([]byte, 82)
+	data[44] = decimals
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"context":{"slot":1},"value":{"data":[%q,"base64"],"executable":false,"lamports":1461600,"owner":%q,"rentEpoch":0}}}`,
+			req.ID, base64.StdEncoding.EncodeToString(data), solana.TokenProgramID)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHandleSellTask(t *testing.T) {
+	srv := mintRPC(t, 6)
+	mint := solana.NewWallet().PublicKey().String()
+
+	for name, tc := range map[string]struct {
+		task    task.Task
+		balance uint64
+		sweep   float64
+		percent float64
+		units   uint64
+		wantErr string
+	}{
+		"percent sell":         {task: task.Task{AutosellAmount: 40}, balance: 2_000_000, percent: 40},
+		"percent sweeps dust":  {task: task.Task{AutosellAmount: 99}, balance: 2_000_000, sweep: 1, percent: 100},
+		"exact amount":         {task: task.Task{SellAmount: 0.5}, balance: 2_000_000, units: 500_000},
+		"amount capped to all": {task: task.Task{SellAmount: 3}, balance: 2_000_000, percent: 100},
+		"empty wallet":         {task: task.Task{AutosellAmount: 100}, wantErr: "wallet main holds no " + mint + " tokens"},
+	} {
+		wp := &WorkerPool{
+			logger:    zap.NewNop(),
+			config:    &task.Config{RPCList: []string{srv.URL}, SweepDustPercent: tc.sweep},
+			solClient: blockchain.NewClient(srv.URL, zap.NewNop()),
+		}
+		tk := tc.task
+		tk.TaskName, tk.WalletName, tk.TokenMint = "sell", "main", mint
+		d := &sellDEX{balance: tc.balance}
+
+		err := wp.handleSellTask(context.Background(), &tk, d, zap.NewNop())
+		if tc.wantErr != "" {
+			assert.EqualError(t, err, tc.wantErr, name)
+			continue
+		}
+		require.NoError(t, err, name)
+		assert.Equal(t, tc.percent, d.percent, name)
+		assert.Equal(t, tc.units, d.units, name)
+	}
+}
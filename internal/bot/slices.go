@@ -0,0 +1,120 @@
+// internal/bot/slices.go
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/risk"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// sliceBlockhashWait — сколько срез ждет нового blockhash, прежде чем уйти со старым.
+const sliceBlockhashWait = 10 * time.Second
+
+// handleSlicedTask разбивает покупку amount_sol на buy_slices покупок случайного
+// размера со случайными паузами между ними: крупную покупку труднее зажать в
+// сэндвич, если она приходит частями. Позиция мониторится с первого среза,
+// остальные сливаются с ней, пока она открыта.
+func (wp *WorkerPool) handleSlicedTask(ctx context.Context, t *task.Task, dexAdapter dex.DEX, client *blockchain.Client, logger *zap.Logger) error {
+	if _, restored := wp.restoredPosition(t); restored || wp.recovered(t, execution.SideBuy) {
+		// Какие срезы успели пройти, не сохраняется: после перезапуска только мониторим позицию
+		logger.Warn("♻️  Sliced buy is not resumed after a restart, monitoring the position only: " + t.TaskName)
+		return wp.handleMonitoredTask(ctx, t, dexAdapter, logger)
+	}
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	amounts := t.SliceAmounts(rnd)
+	logger.Info(fmt.Sprintf("🔪 Buying %s...%s in %d slices: %v SOL (up to %s apart)",
+		t.TokenMint[:4], t.TokenMint[len(t.TokenMint)-4:], len(amounts), amounts, t.SliceJitter))
+
+	first := *t
+	first.AmountSol = amounts[0]
+	blockhash := wp.sliceBlockhash(ctx, t, client, solana.Hash{}, logger)
+
+	var wg sync.WaitGroup
+	err := wp.runMonitoredTask(ctx, &first, dexAdapter, logger, func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wp.runSlices(ctx, &first, amounts, blockhash, rnd, dexAdapter, client, logger)
+		}()
+	})
+	wg.Wait()
+	return err
+}
+
+// runSlices покупает оставшиеся срезы. Они идут под ctx задачи, а не под мониторинг:
+// позиция, слитая с чужой, тоже набирается полностью. Закрытая позиция останавливает срезы.
+func (wp *WorkerPool) runSlices(ctx context.Context, t *task.Task, amounts []float64, blockhash solana.Hash,
+	rnd *rand.Rand, dexAdapter dex.DEX, client *blockchain.Client, logger *zap.Logger) {
+	failed := 0
+	for n := 2; n <= len(amounts); n++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wp.clock.After(t.SliceDelay(rnd)):
+		}
+		blockhash = wp.sliceBlockhash(ctx, t, client, blockhash, logger)
+
+		buy := *t
+		buy.TaskName = fmt.Sprintf("%s slice %d/%d", t.TaskName, n, len(amounts))
+		buy.AmountSol = amounts[n-1]
+		pos, err := wp.buyIntoPosition(ctx, t, &buy, dexAdapter, logger)
+		if errors.Is(err, errHalted) || errors.Is(err, errLossLimit) || errors.Is(err, risk.ErrLimitExceeded) ||
+			errors.Is(err, errInsufficientFunds) {
+			logger.Warn(fmt.Sprintf("🔪 Slicing stopped after %d/%d buys: %v (%s)", n-1, len(amounts), err, t.TaskName))
+			return
+		}
+		if errors.Is(err, errPositionClosed) {
+			logger.Info(fmt.Sprintf("🔪 Slicing stopped after %d/%d buys: the position is closed (%s)", n-1, len(amounts), t.TaskName))
+			return
+		}
+		if err != nil {
+			failed++
+			logger.Error(fmt.Sprintf("❌ Buy slice %d/%d failed: %v", n, len(amounts), err))
+			continue
+		}
+		logger.Info(fmt.Sprintf("🔪 Slice %s done: %.3f SOL invested, avg entry %.10f SOL",
+			buy.TaskName, pos.Invested(), pos.EntryPrice()))
+	}
+	if failed > 0 {
+		logger.Warn(fmt.Sprintf("⚠️  Sliced buy complete with %d/%d slices failed: %s", failed, len(amounts), t.TaskName))
+		return
+	}
+	logger.Info(fmt.Sprintf("✅ Sliced buy complete: %d slices (%s)", len(amounts), t.TaskName))
+}
+
+// sliceBlockhash возвращает blockhash, с которым уйдет следующий срез. С
+// slice_new_blockhash он ждет, пока blockhash сменится относительно prev, чтобы
+// срезы попадали в разные окна blockhash, а не в один блок к одному сэндвичу.
+func (wp *WorkerPool) sliceBlockhash(ctx context.Context, t *task.Task, client *blockchain.Client, prev solana.Hash, logger *zap.Logger) solana.Hash {
+	if !t.SliceNewBlockhash || client == nil {
+		return prev
+	}
+	deadline := wp.clock.Now().Add(sliceBlockhashWait)
+	for {
+		hash, _, err := client.LatestBlockhash(ctx)
+		if err == nil && hash != prev {
+			return hash
+		}
+		if wp.clock.Now().After(deadline) {
+			logger.Warn(fmt.Sprintf("⚠️  No new blockhash within %s, sending the next slice anyway (%s)", sliceBlockhashWait, t.TaskName))
+			return prev
+		}
+		select {
+		case <-ctx.Done():
+			return prev
+		case <-wp.clock.After(blockchain.DefaultBlockhashRefresh):
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// internal/bot/sniper.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+	"github.com/rovshanmuradov/solana-bot/internal/launch"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// newTokenSniper слушает создание токенов Pump.fun и для каждого нового токена,
+// прошедшего фильтры, ставит в очередь копии задач-шаблонов (token_mint = "new").
+type newTokenSniper struct {
+	logs      logSource
+	templates []*task.Task
+	filters   *launch.Chain
+	maxTokens int
+	queue     *dynamicTasks
+	logger    *zap.Logger
+
+	mu       sync.Mutex
+	seen     map[solana.PublicKey]bool
+	launched int
+}
+
+// sniperFilters собирает встроенные фильтры из sniper_* конфигурации: сначала
+// дешевые проверки события создания, последней — возраст создателя, которому нужен
+// запрос к RPC. Неверные адреса создателей пропускаются.
+func sniperFilters(cfg *task.Config, history launch.SignatureHistory, now func() time.Time, logger *zap.Logger) *launch.Chain {
+	var filters []launch.LaunchFilter
+	var creators []solana.PublicKey
+	for _, c := range cfg.SniperCreators {
+		key, err := solana.PublicKeyFromBase58(c)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("⚠️  Ignoring invalid sniper creator %s: %v", c, err))
+			continue
+		}
+		creators = append(creators, key)
+	}
+	if len(creators) > 0 {
+		filters = append(filters, launch.CreatorFilter(creators))
+	}
+	var keywords []string
+	for _, k := range cfg.SniperKeywords {
+		if k = strings.TrimSpace(k); k != "" {
+			keywords = append(keywords, k)
+		}
+	}
+	if len(keywords) > 0 {
+		filters = append(filters, launch.KeywordFilter(keywords))
+	}
+	if cfg.SniperNameRegex != "" {
+		// Выражение уже проверено при загрузке конфигурации
+		filters = append(filters, launch.NameRegexFilter(regexp.MustCompile(cfg.SniperNameRegex)))
+	}
+	if cfg.SniperMinInitialBuy > 0 || cfg.SniperMaxInitialBuy > 0 {
+		filters = append(filters, launch.DevBuyFilter(cfg.SniperMinInitialBuy, cfg.SniperMaxInitialBuy))
+	}
+	if cfg.SniperMinCreatorAge > 0 {
+		filters = append(filters, launch.CreatorAgeFilter(cfg.SniperMinCreatorAge, history, now))
+	}
+	return launch.NewChain(filters...)
+}
+
+// newTokenSniper создает слушателя для шаблонов templates.
+func (r *Runner) newTokenSniper(templates []*task.Task, queue *dynamicTasks) *newTokenSniper {
+	return &newTokenSniper{
+		logs:      r.logSource(),
+		templates: templates,
+		filters:   sniperFilters(r.config, r.solClient, r.clock.Now, r.logger),
+		maxTokens: r.config.SniperMaxTokens,
+		queue:     queue,
+		logger:    r.logger.Named("sniper"),
+		seen:      make(map[solana.PublicKey]bool),
+	}
+}
+
+// Run слушает новые токены, переподключаясь при обрывах, пока не отменен ctx
+// или не набрано sniper_max_tokens токенов.
+func (s *newTokenSniper) Run(ctx context.Context) {
+	s.logger.Info(fmt.Sprintf("🎯 Listening for new Pump.fun tokens with %d snipe templates", len(s.templates)))
+
+	listenLogs(ctx, s.logs, pumpfun.PumpFunMintAuthority, s.logger, func(ev blockchain.LogsEvent) bool {
+		return s.handle(ctx, ev)
+	})
+	s.logger.Info("🎯 New token listener stopped")
+}
+
+// handle разбирает создание токена и ставит задачи в очередь; true — лимит токенов набран.
+func (s *newTokenSniper) handle(ctx context.Context, ev blockchain.LogsEvent) bool {
+	if ev.Err != nil {
+		return false
+	}
+	token, ok := pumpfun.ParseCreateLogs(ev.Logs)
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	seen := s.seen[token.Mint]
+	s.seen[token.Mint] = true
+	s.mu.Unlock()
+	if seen {
+		return false
+	}
+
+	// Фильтры проверяются без блокировки: возрасту создателя нужен запрос к RPC
+	decision, filter := s.filters.Evaluate(ctx, token)
+	if decision.Verdict == launch.Reject {
+		s.logger.Debug(fmt.Sprintf("New token %s (%s) skipped by %s filter: %s", token.Symbol, token.Mint, filter, decision.Reason))
+		return false
+	}
+	if filter != "" {
+		s.logger.Debug(fmt.Sprintf("New token %s (%s) accepted by %s filter: %s", token.Symbol, token.Mint, filter, decision.Reason))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logger.Info(fmt.Sprintf("🆕 New token %s (%s) at slot %d by %s, initial buy %.3f SOL",
+		token.Symbol, token.Mint, ev.Slot, token.Creator, token.InitialBuySol))
+	for _, tmpl := range s.templates {
+		t := *tmpl
+		t.TaskName = fmt.Sprintf("%s-%s", tmpl.TaskName, token.Symbol)
+		t.TokenMint = token.Mint.String()
+		if !s.queue.enqueue(&t) {
+			s.logger.Warn(fmt.Sprintf("⚠️  Snipe of %s by '%s' skipped: too many tasks wait for a free worker", token.Mint, tmpl.TaskName))
+		}
+	}
+
+	s.launched++
+	if s.maxTokens > 0 && s.launched >= s.maxTokens {
+		s.logger.Info(fmt.Sprintf("🎯 Sniped %d new tokens, sniper_max_tokens reached", s.launched))
+		return true
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+// internal/bot/spread.go
+package bot
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// spreadLeg — вложения и PnL позиции одного кошелька задачи с wallet_spread.
+type spreadLeg struct {
+	invested float64
+	net      float64
+}
+
+// spreadPnL сводит PnL позиций задачи, запущенной на нескольких кошельках группы.
+type spreadPnL struct {
+	mu      sync.Mutex
+	wallets int                  // Кошельков, на которых запущена задача
+	legs    map[string]spreadLeg // По имени кошелька, только позиции с мониторингом
+}
+
+// update запоминает вложения и чистый PnL позиции кошелька wallet.
+func (s *spreadPnL) update(wallet string, invested, net float64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.legs[wallet] = spreadLeg{invested: invested, net: net}
+	s.mu.Unlock()
+}
+
+// String описывает сводный PnL и число кошельков с позицией, например "+0.0370 SOL (+12.33%) on 3/3".
+func (s *spreadPnL) String() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var invested, net float64
+	for _, leg := range s.legs {
+		invested += leg.invested
+		net += leg.net
+	}
+	percent := 0.0
+	if invested > 0 {
+		percent = net / invested * 100
+	}
+	return fmt.Sprintf("%+.4f SOL (%+.2f%%) on %d/%d", net, percent, len(s.legs), s.wallets)
+}
+
+// spreadPnL возвращает сводный PnL задачи t по всем ее кошелькам (nil — задача
+// запущена на одном кошельке). Копии задачи разных кошельков получают одну сводку.
+func (wp *WorkerPool) spreadPnL(t *task.Task) *spreadPnL {
+	if t.SpreadWallets <= 1 {
+		return nil
+	}
+	key := t.TaskName + "|" + t.TokenMint
+	wp.spreadsMu.Lock()
+	defer wp.spreadsMu.Unlock()
+	s, ok := wp.spreads[key]
+	if !ok {
+		s = &spreadPnL{wallets: t.SpreadWallets, legs: make(map[string]spreadLeg)}
+		wp.spreads[key] = s
+	}
+	return s
+}
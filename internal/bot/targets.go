@@ -0,0 +1,134 @@
+// internal/bot/targets.go
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// priceTrigger — цель продажи по капитализации, переведенная в цену токена.
+type priceTrigger struct {
+	target task.MarketCapTarget
+	price  float64 // Цена токена в SOL, при которой капитализация достигает цели
+	hit    bool
+}
+
+// marketCapTriggers переводит цели mcap_targets в ценовые триггеры позиции:
+// цена = капитализация в SOL / эмиссия токена. Методы безопасны для nil.
+type marketCapTriggers struct {
+	supply   float64 // Эмиссия токена в UI-единицах
+	solUSD   float64 // Курс SOL/USD для целей в долларах
+	triggers []priceTrigger
+	sold     float64 // Уже продано процентов исходной позиции
+}
+
+// newMarketCapTriggers строит триггеры для целей; цели в долларах без курса SOL/USD
+// возвращаются в skipped.
+func newMarketCapTriggers(targets []task.MarketCapTarget, supply, solUSD float64) (m *marketCapTriggers, skipped []task.MarketCapTarget) {
+	m = &marketCapTriggers{supply: supply, solUSD: solUSD}
+	for _, target := range targets {
+		capSol := target.MarketCap
+		if !target.InSOL {
+			if solUSD <= 0 {
+				skipped = append(skipped, target)
+				continue
+			}
+			capSol = target.MarketCap / solUSD
+		}
+		m.triggers = append(m.triggers, priceTrigger{target: target, price: capSol / supply})
+	}
+	return m, skipped
+}
+
+// marketCap возвращает капитализацию при цене price в SOL и в долларах (0 без курса).
+func (m *marketCapTriggers) marketCap(price float64) (sol, usd float64) {
+	sol = price * m.supply
+	return sol, sol * m.solUSD
+}
+
+// reached отмечает и возвращает цели, достигнутые при цене price.
+func (m *marketCapTriggers) reached(price float64) []task.MarketCapTarget {
+	if m == nil {
+		return nil
+	}
+	var hit []task.MarketCapTarget
+	for i := range m.triggers {
+		tr := &m.triggers[i]
+		if !tr.hit && price >= tr.price {
+			tr.hit = true
+			hit = append(hit, tr.target)
+		}
+	}
+	return hit
+}
+
+// sellPercent переводит долю исходной позиции в процент текущего баланса
+// и учитывает ее как проданную.
+func (m *marketCapTriggers) sellPercent(target task.MarketCapTarget) float64 {
+	remaining := 100 - m.sold
+	m.sold += target.Percent
+	if remaining <= 0 {
+		return 100
+	}
+	return min(target.Percent/remaining*100, 100)
+}
+
+// done сообщает, продана ли позиция целями полностью.
+func (m *marketCapTriggers) done() bool {
+	return m != nil && m.sold >= 100
+}
+
+// lines описывает текущую капитализацию и цели для экрана позиции:
+// каждая цель выводится и в капитализации, и в цене токена.
+func (m *marketCapTriggers) lines(price float64) (marketCap string, targets []string) {
+	if m == nil || len(m.triggers) == 0 {
+		return "", nil
+	}
+	capSol, capUSD := m.marketCap(price)
+	marketCap = task.FormatCompact(capSol) + " SOL"
+	if capUSD > 0 {
+		marketCap = "$" + task.FormatCompact(capUSD) + " / " + marketCap
+	}
+	for _, tr := range m.triggers {
+		mark := " "
+		if tr.hit {
+			mark = "✓"
+		}
+		targets = append(targets, fmt.Sprintf("%s %s = %.10f", mark, tr.target, tr.price))
+	}
+	return marketCap, targets
+}
+
+// marketCapTriggers готовит цели продажи задачи по капитализации. Без целей или
+// без эмиссии токена возвращает nil: позиция мониторится как обычно.
+func (wp *WorkerPool) marketCapTriggers(ctx context.Context, t *task.Task, logger *zap.Logger) *marketCapTriggers {
+	if len(t.MarketCapTargets) == 0 {
+		return nil
+	}
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		logger.Warn("⚠️  Market cap targets disabled, invalid mint: " + err.Error())
+		return nil
+	}
+	supply, err := wp.solClient.GetTokenSupply(ctx, mint)
+	if err != nil || supply <= 0 {
+		logger.Warn(fmt.Sprintf("⚠️  Market cap targets disabled, token supply unavailable: %v", err))
+		return nil
+	}
+
+	triggers, skipped := newMarketCapTriggers(t.MarketCapTargets, supply, wp.config.SOLUSDPrice)
+	for _, target := range skipped {
+		logger.Warn(fmt.Sprintf("⚠️  Skipping target %s: set sol_usd_price for USD market caps", target))
+	}
+	for _, tr := range triggers.triggers {
+		logger.Info(fmt.Sprintf("🎯 Target %s → sell at %.10f SOL per token", tr.target, tr.price))
+	}
+	if len(triggers.triggers) == 0 {
+		return nil
+	}
+	return triggers
+}
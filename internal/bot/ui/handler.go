@@ -2,15 +2,11 @@
 package ui
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"strings"
 
-	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
-	"github.com/rovshanmuradov/solana-bot/internal/monitor"
 	"go.uber.org/zap"
 )
 
@@ -18,8 +14,12 @@ import (
 type EventType int
 
 const (
-	SellRequested EventType = iota // Запрос на продажу токенов (пустая строка)
-	ExitRequested                  // Запрос на выход без продажи (q/exit)
+	SellRequested   EventType = iota // Запрос на продажу токенов (пустая строка)
+	ExitRequested                    // Запрос на выход без продажи (q/exit)
+	LogPaneResized                   // Запрос на изменение высоты панели логов (+/-), Data — знак
+	ExitRuleChanged                  // Новый порог stop-loss / take-profit / trailing stop (sl N / tp N / ts N), Data — команда
+	PanicRequested                   // Kill switch: продать все позиции и остановить задачи (panic)
+	RearmRequested                   // Снова разрешить задачи после kill switch (rearm)
 )
 
 // Event представляет событие от пользовательского интерфейса
@@ -52,50 +52,53 @@ func (h *Handler) Start() {
 	h.logger.Debug("Starting UI handler")
 	fmt.Println("\nMonitoring started. Press Enter to sell tokens or 'q' to exit.")
 
+	stdin.start(os.Stdin)
 	go func() {
-		reader := bufio.NewReader(os.Stdin)
-
 		for {
+			var command string
 			select {
 			case <-h.ctx.Done():
 				h.logger.Debug("UI handler stopped due to context cancellation")
 				return
+			case <-stdin.done:
+				// EOF означает, что stdin закрыт или отсоединен
+				h.logger.Warn("Stdin closed or detached, exiting UI handler")
+				h.publishEvent(ExitRequested, "")
+				return
+			case command = <-stdin.lines:
+			}
+
+			// Обрабатываем команды
+			switch command {
+			case "":
+				// Пустая строка - запрос на продажу
+				h.publishEvent(SellRequested, "")
+			case "q", "exit":
+				// Запрос на выход
+				h.publishEvent(ExitRequested, "")
+			case "+", "-":
+				h.publishEvent(LogPaneResized, command)
+			case "panic":
+				h.publishEvent(PanicRequested, "")
+			case "rearm":
+				h.publishEvent(RearmRequested, "")
 			default:
-				// Read a line non-blocking
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					if err == io.EOF {
-						// EOF means stdin is closed or detached
-						h.logger.Warn("Stdin closed or detached, exiting UI handler")
-						h.publishEvent(ExitRequested, "")
-						return
-					}
-
-					if h.ctx.Err() == nil { // Only log if not canceled
-						h.logger.Error("Error reading input", zap.Error(err))
-					}
+				if isExitRuleCommand(command) {
+					h.publishEvent(ExitRuleChanged, command)
 					continue
 				}
-
-				// Process the command
-				command := strings.TrimSpace(line)
-
-				// Обрабатываем команды
-				switch command {
-				case "":
-					// Пустая строка - запрос на продажу
-					h.publishEvent(SellRequested, "")
-				case "q", "exit":
-					// Запрос на выход
-					h.publishEvent(ExitRequested, "")
-				default:
-					fmt.Println("Unknown command. Press Enter to sell tokens or 'q' to exit.")
-				}
+				fmt.Println("Unknown command. Press Enter to sell tokens, 'q' to exit, '+' / '-' to resize the log pane, 'sl N' / 'tp N' / 'ts N' to set stop-loss / take-profit / trailing stop, 'panic' to sell everything and halt tasks or 'rearm' to resume them.")
 			}
 		}
 	}()
 }
 
+// isExitRuleCommand распознает команды "sl N", "tp N" и "ts N"; порог проверяет монитор.
+func isExitRuleCommand(command string) bool {
+	fields := strings.Fields(command)
+	return len(fields) == 2 && (fields[0] == "sl" || fields[0] == "tp" || fields[0] == "ts")
+}
+
 // Stop останавливает обработчик
 func (h *Handler) Stop() {
 	if h.cancel != nil {
@@ -129,7 +132,9 @@ func shortenAddress(addr string) string {
 }
 
 // Render выводит в консоль аккуратно выровненный бокс с данными мониторинга
-func Render(update monitor.PriceUpdate, pnl model.PnLResult, tokenMint string) {
+func Render(f Frame) {
+	update, pnl, indicators := f.Update, f.PnL, f.Indicators
+
 	// Форматирование процента изменения цены
 	changeStr := fmt.Sprintf("%.2f%%", update.Percent)
 	if update.Percent > 0 {
@@ -148,7 +153,15 @@ func Render(update monitor.PriceUpdate, pnl model.PnLResult, tokenMint string) {
 
 	// Вывод информации в консоль
 	fmt.Println("\n╔════════════════ TOKEN MONITOR ════════════════╗")
-	fmt.Printf("║ Token: %-38s ║\n", shortenAddress(tokenMint))
+	if f.TokenName != "" {
+		fmt.Printf("║ Token: %-38s ║\n", truncate(f.TokenName, 38))
+		fmt.Printf("║ Mint: %-39s ║\n", shortenAddress(f.TokenMint))
+	} else {
+		fmt.Printf("║ Token: %-38s ║\n", shortenAddress(f.TokenMint))
+	}
+	if f.Wallet != "" {
+		fmt.Printf("║ Wallet: %-37s ║\n", truncate(f.Wallet, 37))
+	}
 	fmt.Println("╟───────────────────────────────────────────────╢")
 	fmt.Printf("║ Current Price:       %-20.8f SOL ║\n", update.Current)
 	fmt.Printf("║ Initial Price:       %-20.8f SOL ║\n", update.Initial)
@@ -158,6 +171,39 @@ func Render(update monitor.PriceUpdate, pnl model.PnLResult, tokenMint string) {
 	fmt.Printf("║ Sold (Estimate):     %-20.8f SOL ║\n", pnl.SellEstimate)
 	fmt.Printf("║ Invested:            %-20.8f SOL ║\n", pnl.InitialInvestment)
 	fmt.Printf("║ P&L:                 %-25s ║\n", pnlStr)
+	if pnl.ProtocolFee+pnl.CreatorFee > 0 {
+		// Комиссии протокола и создателя токена, уже вычтенные из оценки продажи
+		fmt.Printf("║ Sell Fees:           %-24s ║\n", fmt.Sprintf("%.6f + %.6f SOL", pnl.ProtocolFee, pnl.CreatorFee))
+	}
+	if f.SellSlippage != "" {
+		fmt.Printf("║ Sell Slippage:       %-24s ║\n", f.SellSlippage)
+	}
+	if f.Exits != "" {
+		fmt.Printf("║ Exit Rules:          %-24s ║\n", f.Exits)
+	}
+	if f.Schedule != "" {
+		fmt.Printf("║ DCA Buys:            %-24s ║\n", f.Schedule)
+	}
+	if f.Flow != "" {
+		fmt.Printf("║ Order Flow (1m):     %-24s ║\n", f.Flow)
+	}
+	if f.Spread != "" {
+		fmt.Println("╟───────────────────────────────────────────────╢")
+		fmt.Printf("║ All Wallets: %-32s ║\n", truncate(f.Spread, 32))
+	}
+	if f.MarketCap != "" {
+		fmt.Println("╟───────────────────────────────────────────────╢")
+		fmt.Printf("║ Market Cap:          %-24s ║\n", f.MarketCap)
+		for _, target := range f.Targets {
+			fmt.Printf("║ %-45s ║\n", truncate(target, logPaneWidth))
+		}
+	}
+	if indicators.Ready {
+		fmt.Println("╟───────────────────────────────────────────────╢")
+		fmt.Printf("║ RSI:                 %-24.1f ║\n", indicators.RSI)
+		fmt.Printf("║ EMA Trend:           %-24s ║\n", indicators.Trend())
+		fmt.Printf("║ Volatility:          %-24s ║\n", fmt.Sprintf("%.2f%%/tick", indicators.Volatility))
+	}
 	fmt.Println("╚═══════════════════════════════════════════════╝")
-	fmt.Println("Press Enter to sell tokens, 'q' to exit without selling")
+	fmt.Println("Press Enter to sell tokens, 'q' to exit without selling, 'sl N' / 'tp N' / 'ts N' to set stop-loss / take-profit / trailing stop (0 = off)")
 }
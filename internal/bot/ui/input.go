@@ -0,0 +1,109 @@
+// internal/bot/ui/input.go
+package ui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrInputClosed — stdin закрыт или отсоединен, ответа оператора не будет.
+var ErrInputClosed = errors.New("operator input closed")
+
+// lineInput — единственный читатель stdin процесса. Пока оператору задан вопрос,
+// следующая строка уходит в ответ, остальные — обработчикам мониторинга.
+// Строки, набранные без активного мониторинга, отбрасываются, чтобы лишний Enter
+// не продал позицию следующей сессии.
+type lineInput struct {
+	once  sync.Once
+	lines chan string
+	done  chan struct{} // Закрывается на EOF
+
+	askMu  sync.Mutex // Вопросы оператору задаются по одному
+	mu     sync.Mutex
+	answer chan string
+}
+
+var stdin = newLineInput()
+
+func newLineInput() *lineInput {
+	return &lineInput{lines: make(chan string), done: make(chan struct{})}
+}
+
+// start запускает чтение r один раз за время жизни процесса.
+func (in *lineInput) start(r io.Reader) {
+	in.once.Do(func() { go in.read(r) })
+}
+
+func (in *lineInput) read(r io.Reader) {
+	defer close(in.done)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		in.mu.Lock()
+		answer := in.answer
+		in.answer = nil
+		in.mu.Unlock()
+		if answer != nil {
+			answer <- line
+			continue
+		}
+
+		select {
+		case in.lines <- line:
+		default:
+		}
+	}
+}
+
+// ask ждет следующую строку как ответ на вопрос.
+func (in *lineInput) ask(ctx context.Context) (string, error) {
+	answer := make(chan string, 1)
+	in.mu.Lock()
+	in.answer = answer
+	in.mu.Unlock()
+
+	select {
+	case line := <-answer:
+		return line, nil
+	case <-in.done:
+		return "", ErrInputClosed
+	case <-ctx.Done():
+		in.mu.Lock()
+		if in.answer == answer {
+			in.answer = nil
+		}
+		in.mu.Unlock()
+		return "", ctx.Err()
+	}
+}
+
+// Ask выводит вопрос оператору и ждет ответ из stdin до отмены ctx. Пока вопрос
+// открыт, рендерер повторяет его под кадрами мониторинга.
+func (r *Renderer) Ask(ctx context.Context, question string) (string, error) {
+	stdin.start(os.Stdin)
+	stdin.askMu.Lock()
+	defer stdin.askMu.Unlock()
+
+	r.setPrompt(question)
+	defer r.setPrompt("")
+	fmt.Println("\n" + question)
+
+	return stdin.ask(ctx)
+}
+
+func (r *Renderer) setPrompt(question string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.prompt = question
+	r.mu.Unlock()
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineInputAnswersQuestionFirst(t *testing.T) {
+	r, w := io.Pipe()
+	in := newLineInput()
+	in.start(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	answers := make(chan string, 1)
+	go func() {
+		answer, err := in.ask(ctx)
+		assert.NoError(t, err)
+		answers <- answer
+	}()
+	require.Eventually(t, func() bool {
+		in.mu.Lock()
+		defer in.mu.Unlock()
+		return in.answer != nil
+	}, time.Second, time.Millisecond)
+
+	_, err := io.WriteString(w, " y \n")
+	require.NoError(t, err)
+	assert.Equal(t, "y", <-answers)
+
+	// Без вопроса строка уходит обработчику мониторинга
+	lines := make(chan string, 1)
+	go func() { lines <- <-in.lines }()
+	require.Eventually(t, func() bool {
+		_, err := io.WriteString(w, "q\n")
+		return err == nil && len(lines) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, "q", <-lines)
+
+	require.NoError(t, w.Close())
+	_, err = in.ask(ctx)
+	assert.ErrorIs(t, err, ErrInputClosed)
+}
+
+func TestLineInputAskTimesOut(t *testing.T) {
+	r, _ := io.Pipe()
+	in := newLineInput()
+	in.start(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := in.ask(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	assert.Nil(t, in.answer, "timed out question must not take the next line")
+}
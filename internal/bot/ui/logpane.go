@@ -0,0 +1,105 @@
+// internal/bot/ui/logpane.go
+package ui
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// MaxLogPaneLines — наибольшая высота панели логов.
+	MaxLogPaneLines = 30
+	// logPaneWidth — ширина строки панели, как у бокса мониторинга.
+	logPaneWidth = 45
+)
+
+// LogPane хранит последние сообщения лога и выводит их под боксами мониторинга,
+// чтобы причина неудачной продажи была видна, не уходя с экрана позиций.
+// Подключается к логгеру как zapcore.Core.
+type LogPane struct {
+	mu     sync.Mutex
+	lines  []string
+	height int
+}
+
+// NewLogPane создает панель высотой height строк.
+func NewLogPane(height int) *LogPane {
+	return &LogPane{height: clampPaneHeight(height)}
+}
+
+// Resize меняет высоту панели на delta строк и возвращает новую высоту.
+func (p *LogPane) Resize(delta int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.height = clampPaneHeight(p.height + delta)
+	return p.height
+}
+
+// Tail возвращает последние строки по высоте панели.
+func (p *LogPane) Tail() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := min(p.height, len(p.lines))
+	return append([]string(nil), p.lines[len(p.lines)-n:]...)
+}
+
+// Enabled — в панель попадают сообщения от Info и выше.
+func (p *LogPane) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.InfoLevel
+}
+
+// With возвращает ту же панель: поля в панели не выводятся.
+func (p *LogPane) With([]zapcore.Field) zapcore.Core {
+	return p
+}
+
+// Check добавляет панель к записи, если уровень подходит.
+func (p *LogPane) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if p.Enabled(entry.Level) {
+		return checked.AddCore(entry, p)
+	}
+	return checked
+}
+
+// Write запоминает сообщение, вытесняя самые старые сверх MaxLogPaneLines.
+func (p *LogPane) Write(entry zapcore.Entry, _ []zapcore.Field) error {
+	line := fmt.Sprintf("%s %s", entry.Time.Format("15:04:05"), entry.Message)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lines = append(p.lines, line)
+	if len(p.lines) > MaxLogPaneLines {
+		p.lines = p.lines[len(p.lines)-MaxLogPaneLines:]
+	}
+	return nil
+}
+
+// Sync ничего не делает: панель пишет только в память.
+func (p *LogPane) Sync() error {
+	return nil
+}
+
+// renderLogPane выводит строки панели в боксе той же ширины, что и мониторинг.
+func renderLogPane(lines []string) {
+	fmt.Println("╔═════════════════════ LOG ═════════════════════╗")
+	for _, line := range lines {
+		fmt.Printf("║ %-*s ║\n", logPaneWidth, truncate(line, logPaneWidth))
+	}
+	fmt.Println("╚═══════════════════════════════════════════════╝")
+	fmt.Println("Press '+' / '-' and Enter to resize the log pane")
+}
+
+// truncate обрезает строку до width символов.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+func clampPaneHeight(h int) int {
+	return max(0, min(h, MaxLogPaneLines))
+}
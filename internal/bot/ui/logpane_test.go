@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogPane_TailAndResize(t *testing.T) {
+	pane := NewLogPane(2)
+	logger := zap.New(pane)
+
+	logger.Debug("hidden")
+	for i := 1; i <= 3; i++ {
+		logger.Info(fmt.Sprintf("line %d", i))
+	}
+
+	tail := pane.Tail()
+	assert.Len(t, tail, 2)
+	assert.Contains(t, tail[0], "line 2")
+	assert.Contains(t, tail[1], "line 3")
+
+	assert.Equal(t, 3, pane.Resize(1))
+	assert.Len(t, pane.Tail(), 3)
+	assert.Equal(t, 0, pane.Resize(-10))
+	assert.Empty(t, pane.Tail())
+	assert.Equal(t, MaxLogPaneLines, pane.Resize(100))
+}
+
+func TestLogPane_KeepsLastLines(t *testing.T) {
+	pane := NewLogPane(MaxLogPaneLines)
+	for i := 0; i < MaxLogPaneLines+5; i++ {
+		_ = pane.Write(zapcore.Entry{Time: time.Now(), Message: fmt.Sprintf("m%d", i)}, nil)
+	}
+	tail := pane.Tail()
+	assert.Len(t, tail, MaxLogPaneLines)
+	assert.Contains(t, tail[0], "m5")
+}
@@ -0,0 +1,106 @@
+// internal/bot/ui/portfolio.go
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PortfolioToken — SPL-токен на кошельке и его стоимость по текущей цене.
+type PortfolioToken struct {
+	Mint   string
+	Symbol string  // Символ токена, пусто — выводится адрес
+	Amount float64 // Баланс с учетом decimals
+	Value  float64 // Стоимость в SOL
+	Priced bool    // Цена получена; без нее токен не входит в итог
+}
+
+// PortfolioWallet — балансы одного кошелька.
+type PortfolioWallet struct {
+	Name   string
+	Role   string
+	SOL    float64
+	Tokens []PortfolioToken
+}
+
+// Value возвращает стоимость кошелька в SOL: SOL-баланс и оцененные токены.
+func (w PortfolioWallet) Value() float64 {
+	total := w.SOL
+	for _, t := range w.Tokens {
+		if t.Priced {
+			total += t.Value
+		}
+	}
+	return total
+}
+
+// Portfolio — данные экрана портфеля.
+type Portfolio struct {
+	Wallets   []PortfolioWallet
+	UpdatedAt time.Time
+	Refresh   time.Duration // Интервал обновления, 0 — экран выводится один раз
+}
+
+// Total возвращает стоимость всех кошельков в SOL.
+func (p Portfolio) Total() float64 {
+	var total float64
+	for _, w := range p.Wallets {
+		total += w.Value()
+	}
+	return total
+}
+
+// RenderPortfolio выводит бокс портфеля той же ширины, что и мониторинг.
+func RenderPortfolio(p Portfolio) {
+	fmt.Println("\n╔══════════════════ PORTFOLIO ══════════════════╗")
+	for i, line := range portfolioLines(p) {
+		if line == "" {
+			if i > 0 {
+				fmt.Println("╟───────────────────────────────────────────────╢")
+			}
+			continue
+		}
+		fmt.Printf("║ %s ║\n", padRight(truncate(line, logPaneWidth), logPaneWidth))
+	}
+	fmt.Println("╚═══════════════════════════════════════════════╝")
+	if p.Refresh > 0 {
+		fmt.Printf("Updated %s, refreshing every %s. Press Ctrl+C to exit\n", p.UpdatedAt.Format("15:04:05"), p.Refresh)
+	}
+}
+
+// portfolioLines собирает строки бокса; пустая строка — разделитель.
+func portfolioLines(p Portfolio) []string {
+	var lines []string
+	for _, w := range p.Wallets {
+		name := w.Name
+		if w.Role != "" {
+			name += " (" + w.Role + ")"
+		}
+		lines = append(lines, "", fmt.Sprintf("%-27s %13.6f SOL", truncate(name, 27), w.SOL))
+		for _, t := range w.Tokens {
+			value := "no price"
+			if t.Priced {
+				value = fmt.Sprintf("%.6f SOL", t.Value)
+			}
+			name := shortenAddress(t.Mint)
+			if t.Symbol != "" {
+				name = truncate(t.Symbol, 13)
+			}
+			lines = append(lines, fmt.Sprintf("  %s %13.2f %15s", padRight(name, 13), t.Amount, value))
+		}
+		if len(w.Tokens) > 0 {
+			lines = append(lines, fmt.Sprintf("%-27s %13.6f SOL", "  Wallet value", w.Value()))
+		}
+	}
+	lines = append(lines, "", fmt.Sprintf("%-27s %13.6f SOL", "Total", p.Total()))
+	return lines
+}
+
+// padRight дополняет строку пробелами до width символов.
+func padRight(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
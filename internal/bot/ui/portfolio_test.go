@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortfolio_TotalSkipsUnpricedTokens(t *testing.T) {
+	p := Portfolio{Wallets: []PortfolioWallet{
+		{Name: "main", SOL: 1.5, Tokens: []PortfolioToken{
+			{Mint: "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", Amount: 1000, Value: 0.25, Priced: true},
+			{Mint: "So11111111111111111111111111111111111111112", Amount: 5, Value: 9, Priced: false},
+			{Mint: "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263", Symbol: "BONK", Amount: 2e6, Value: 0.05, Priced: true},
+		}},
+		{Name: "vault", Role: "vault", SOL: 2},
+	}}
+
+	assert.InDelta(t, 1.8, p.Wallets[0].Value(), 1e-9)
+	assert.InDelta(t, 3.8, p.Total(), 1e-9)
+
+	lines := portfolioLines(p)
+	assert.Contains(t, lines, "  7xKXtg…osgAsU       1000.00    0.250000 SOL")
+	assert.Contains(t, lines, "  So1111…111112          5.00        no price")
+	assert.Contains(t, lines, "  BONK             2000000.00    0.050000 SOL")
+	assert.Contains(t, lines, "vault (vault)                    2.000000 SOL")
+	assert.Equal(t, "Total                            3.800000 SOL", lines[len(lines)-1])
+	for _, line := range lines {
+		assert.LessOrEqual(t, len([]rune(line)), logPaneWidth)
+	}
+}
@@ -0,0 +1,195 @@
+// internal/bot/ui/renderer.go
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+)
+
+// DefaultFrameInterval — как часто обновляется экран мониторинга.
+const DefaultFrameInterval = 250 * time.Millisecond
+
+// Frame — данные одного кадра мониторинга токена.
+type Frame struct {
+	Update       monitor.PriceUpdate
+	PnL          model.PnLResult
+	TokenMint    string
+	TokenName    string                    // Символ и название токена, пусто — выводится только адрес
+	Wallet       string                    // Кошелек позиции, пусто — не выводится
+	SellSlippage string                    // Настройка slippage продажи, пусто — не выводится
+	Indicators   monitor.IndicatorSnapshot // Выводятся после прогрева
+	MarketCap    string                    // Текущая капитализация, пусто — без целей по капитализации
+	Targets      []string                  // Цели продажи по капитализации с ценой срабатывания
+	Exits        string                    // Правила stop-loss / take-profit, пусто — не выводятся
+	Schedule     string                    // Прогресс покупок DCA, пусто — не выводится
+	Spread       string                    // Сводный PnL задачи по всем кошелькам, пусто — не выводится
+	Flow         string                    // Сделки других кошельков за минуту, пусто — не выводятся
+}
+
+// frameKey — ключ кадра в очереди: у позиций одного токена на разных кошельках свои кадры.
+func frameKey(tokenMint, wallet string) string {
+	return tokenMint + "/" + wallet
+}
+
+// Renderer прореживает обновления: за кадр по каждой позиции выводится только
+// последнее состояние, промежуточные отбрасываются. Вывод всех токенов идет
+// из одной горутины, поэтому боксы разных мониторов не перемешиваются.
+type Renderer struct {
+	interval time.Duration
+	clock    clock.Clock     // Часы кадров
+	render   func(Frame)     // Вывод кадра, по умолчанию Render
+	closed   map[string]bool // Позиции после Discard: их поздние кадры не выводятся
+	mu       sync.Mutex
+	pending  map[string]Frame
+	pane     *LogPane // Панель логов под боксами (nil — без нее)
+	prompt   string   // Открытый вопрос оператору, повторяется под кадрами
+	banner   string   // Предупреждение над кадрами, например о дневном лимите убытка
+}
+
+// NewRenderer создает рендерер с заданной длительностью кадра.
+func NewRenderer(interval time.Duration) *Renderer {
+	if interval <= 0 {
+		interval = DefaultFrameInterval
+	}
+	return &Renderer{
+		interval: interval,
+		clock:    clock.Real,
+		render:   Render,
+		pending:  make(map[string]Frame),
+		closed:   make(map[string]bool),
+	}
+}
+
+// Submit ставит кадр в очередь, заменяя еще не выведенный кадр той же позиции.
+// Кадры позиции, убранной через Discard, отбрасываются до Open.
+// Без рендерера (nil) кадр выводится сразу.
+func (r *Renderer) Submit(f Frame) {
+	if r == nil {
+		Render(f)
+		return
+	}
+	key := frameKey(f.TokenMint, f.Wallet)
+	r.mu.Lock()
+	if !r.closed[key] {
+		r.pending[key] = f
+	}
+	r.mu.Unlock()
+}
+
+// Open снова принимает кадры позиции, например при новом мониторинге токена кошелька.
+func (r *Renderer) Open(tokenMint, wallet string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	delete(r.closed, frameKey(tokenMint, wallet))
+	r.mu.Unlock()
+}
+
+// SetLogPane включает вывод панели логов под кадрами мониторинга.
+func (r *Renderer) SetLogPane(p *LogPane) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.pane = p
+	r.mu.Unlock()
+}
+
+// ResizeLogPane меняет высоту панели логов на delta строк.
+func (r *Renderer) ResizeLogPane(delta int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	pane := r.pane
+	r.mu.Unlock()
+	if pane != nil {
+		pane.Resize(delta)
+	}
+}
+
+// SetBanner выводит text над кадрами мониторинга; "" убирает предупреждение.
+func (r *Renderer) SetBanner(text string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.banner = text
+	r.mu.Unlock()
+}
+
+// Discard убирает невыведенный кадр позиции, например после остановки ее мониторинга.
+// Кадры, которые остановленный монитор успеет прислать позже, тоже не выводятся.
+func (r *Renderer) Discard(tokenMint, wallet string) {
+	if r == nil {
+		return
+	}
+	key := frameKey(tokenMint, wallet)
+	r.mu.Lock()
+	delete(r.pending, key)
+	r.closed[key] = true
+	r.mu.Unlock()
+}
+
+// Run выводит накопленные кадры раз в интервал, пока не отменен ctx.
+func (r *Renderer) Run(ctx context.Context) {
+	ticker := r.clock.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			frames := r.takePending()
+			r.mu.Lock()
+			pane, prompt, banner := r.pane, r.prompt, r.banner
+			r.mu.Unlock()
+			if len(frames) > 0 && banner != "" {
+				fmt.Println("\n\033[41;97m " + banner + " \033[0m")
+			}
+			for _, f := range frames {
+				r.render(f)
+			}
+			if len(frames) > 0 && pane != nil {
+				if lines := pane.Tail(); len(lines) > 0 {
+					renderLogPane(lines)
+				}
+			}
+			if len(frames) > 0 && prompt != "" {
+				fmt.Println(prompt)
+			}
+		}
+	}
+}
+
+// takePending забирает кадры текущего интервала в стабильном порядке токенов и кошельков.
+func (r *Renderer) takePending() []Frame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) == 0 {
+		return nil
+	}
+	frames := make([]Frame, 0, len(r.pending))
+	for _, f := range r.pending {
+		frames = append(frames, f)
+	}
+	r.pending = make(map[string]Frame, len(frames))
+
+	sort.Slice(frames, func(i, j int) bool {
+		if frames[i].TokenMint != frames[j].TokenMint {
+			return frames[i].TokenMint < frames[j].TokenMint
+		}
+		return frames[i].Wallet < frames[j].Wallet
+	})
+	return frames
+}
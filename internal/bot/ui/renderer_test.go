@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+)
+
+func priceFrame(mint, wallet string, price float64) Frame {
+	return Frame{TokenMint: mint, Wallet: wallet, Update: monitor.PriceUpdate{Current: price}}
+}
+
+// testRenderer запускает рендерер на управляемых часах; выведенные кадры идут в канал.
+func testRenderer(t *testing.T) (*Renderer, *clock.Fake, <-chan Frame) {
+	clk := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	drawn := make(chan Frame, 16)
+	r := NewRenderer(DefaultFrameInterval)
+	r.clock = clk
+	r.render = func(f Frame) { drawn <- f }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	clk.BlockUntil(1)
+	return r, clk, drawn
+}
+
+func nextFrame(t *testing.T, drawn <-chan Frame) Frame {
+	t.Helper()
+	select {
+	case f := <-drawn:
+		return f
+	case <-time.After(5 * time.Second):
+		t.Fatal("no frame drawn")
+		return Frame{}
+	}
+}
+
+func TestRenderer_CoalescesUpdatesWithinInterval(t *testing.T) {
+	r, clk, drawn := testRenderer(t)
+
+	// Мониторы шлют обновления одновременно; за интервал копится по кадру на позицию
+	var wg sync.WaitGroup
+	for _, wallet := range []string{"main", "alt"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= 100; i++ {
+				r.Submit(priceFrame("MintA", wallet, float64(i)))
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 100; i++ {
+			r.Submit(priceFrame("MintB", "main", float64(i)/10))
+		}
+	}()
+	wg.Wait()
+
+	clk.Advance(DefaultFrameInterval)
+	var got []Frame
+	for range 3 {
+		got = append(got, nextFrame(t, drawn))
+	}
+	assert.Equal(t, []Frame{
+		priceFrame("MintA", "alt", 100),
+		priceFrame("MintA", "main", 100),
+		priceFrame("MintB", "main", 10),
+	}, got, "one frame per position with its latest state, in a stable order")
+
+	// Следующий интервал выводит только новые обновления
+	r.Submit(priceFrame("MintB", "main", 11))
+	clk.Advance(DefaultFrameInterval)
+	assert.Equal(t, priceFrame("MintB", "main", 11), nextFrame(t, drawn))
+	assert.Empty(t, drawn)
+}
+
+func TestRenderer_DiscardedPositionIsNotDrawn(t *testing.T) {
+	r, clk, drawn := testRenderer(t)
+
+	r.Submit(priceFrame("MintA", "main", 1))
+	r.Submit(priceFrame("MintA", "alt", 2))
+	r.Discard("MintA", "main") // Мониторинг остановлен до вывода кадра
+	clk.Advance(DefaultFrameInterval)
+	assert.Equal(t, priceFrame("MintA", "alt", 2), nextFrame(t, drawn))
+
+	// Остановленный монитор успел прислать еще кадр: он не выводится
+	r.Submit(priceFrame("MintA", "main", 5))
+	clk.Advance(DefaultFrameInterval)
+	r.Submit(priceFrame("MintZ", "main", 3))
+	clk.Advance(DefaultFrameInterval)
+	f := nextFrame(t, drawn)
+	require.Equal(t, "MintZ", f.TokenMint, "no frame of the discarded position comes before the next one")
+	assert.Empty(t, drawn)
+
+	// Новый мониторинг той же позиции снова выводится
+	r.Open("MintA", "main")
+	r.Submit(priceFrame("MintA", "main", 6))
+	clk.Advance(DefaultFrameInterval)
+	assert.Equal(t, priceFrame("MintA", "main", 6), nextFrame(t, drawn))
+}
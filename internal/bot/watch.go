@@ -0,0 +1,133 @@
+// internal/bot/watch.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// handleWatchTask следит за ценой токена без позиции и покупает на просадке
+// от референсной цены (недавний максимум, заданная reference_price или цена
+// последнего выхода кошелька из токена). После покупки задача продолжается как
+// обычная отслеживаемая сделка.
+func (wp *WorkerPool) handleWatchTask(ctx context.Context, t *task.Task, dexAdapter dex.DEX, logger *zap.Logger) error {
+	buy := *t
+	buy.Operation = buyOperationFor(t.Module)
+	if wp.recovered(&buy, execution.SideBuy) {
+		// Покупка на просадке уже прошла до перезапуска: сразу переходим к мониторингу
+		return wp.handleMonitoredTask(ctx, &buy, dexAdapter, logger)
+	}
+
+	reference := t.ReferencePrice
+	if t.Reference == task.ReferenceLastExit {
+		exit, err := wp.lastExitPrice(ctx, t, logger)
+		if err != nil {
+			return err
+		}
+		reference = exit
+	}
+
+	logger.Info(fmt.Sprintf("👀 Watching %s...%s: buy %.3f SOL on %.1f%% dip (for %s)",
+		t.TokenMint[:4], t.TokenMint[len(t.TokenMint)-4:], t.AmountSol, t.DipPercent, t.WatchDuration))
+
+	dipped, err := wp.awaitDip(ctx, t, dexAdapter, reference, logger)
+	if err != nil || !dipped {
+		return err
+	}
+	return wp.handleMonitoredTask(ctx, &buy, dexAdapter, logger)
+}
+
+// awaitDip опрашивает цену, пока она не опустится на dip_percent ниже reference
+// (0 — ниже максимума, замеченного за время наблюдения). false — срок наблюдения
+// истек без просадки.
+func (wp *WorkerPool) awaitDip(ctx context.Context, t *task.Task, dexAdapter dex.DEX, reference float64, logger *zap.Logger) (bool, error) {
+	interval := wp.config.PriceDelay
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	trackHigh := reference == 0
+
+	ticker := wp.clock.NewTicker(interval)
+	defer ticker.Stop()
+	expired := wp.clock.After(t.WatchDuration)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-expired:
+			logger.Info("⌛ Watch expired without a dip: " + t.TaskName)
+			return false, nil
+		case <-ticker.C():
+		}
+
+		price, err := dexAdapter.GetTokenPrice(ctx, t.TokenMint)
+		if err != nil {
+			logger.Debug("Watch price fetch failed: " + err.Error())
+			continue
+		}
+		if price <= 0 {
+			continue
+		}
+
+		if trackHigh && price > reference {
+			reference = price
+			continue
+		}
+
+		trigger := reference * (1 - t.DipPercent/100)
+		if price > trigger {
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("📉 Dip detected: %.10f SOL (reference %.10f, -%.2f%%)",
+			price, reference, (1-price/reference)*100))
+		return true, nil
+	}
+}
+
+// lastExitPrice возвращает цену последнего выхода кошелька задачи из ее токена: по
+// фактическим суммам последней продажи из журнала сделок, а без нее — последнюю
+// цену мониторинга из архива сессий.
+func (wp *WorkerPool) lastExitPrice(ctx context.Context, t *task.Task, logger *zap.Logger) (float64, error) {
+	session, ok, err := wp.sessions.LastExit(t.TokenMint, t.WalletName)
+	if err != nil {
+		return 0, fmt.Errorf("read session archive: %w", err)
+	}
+	if !ok {
+		return 0, fmt.Errorf("reference last_exit: wallet %s has no archived sale of %s", t.WalletName, t.TokenMint)
+	}
+
+	price := session.LastPrice
+	if store := wp.recorder.Store(); store != nil {
+		records, err := store.Load(time.Time{})
+		if err != nil {
+			logger.Debug("Failed to load execution history", zap.Error(err))
+		}
+		if sale, ok := session.LastSale(records); ok {
+			if sold, ok := sale.SellPrice(wp.positionDecimals(ctx, t, logger)); ok {
+				price = sold
+			}
+		}
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("reference last_exit: session %s has no exit price", session.ID)
+	}
+
+	logger.Info(fmt.Sprintf("📌 Reference is the last exit at %.10f SOL (session %s)", price, session.ID))
+	return price, nil
+}
+
+// buyOperationFor возвращает операцию покупки, подходящую для модуля DEX.
+func buyOperationFor(module string) task.OperationType {
+	if module == "pump.swap" || module == "jupiter" {
+		return task.OperationSwap
+	}
+	return task.OperationSnipe
+}
@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// feedDEX отдает цены, которые тест присылает в prices: каждый запрос ждет следующую.
+type feedDEX struct {
+	dex.DEX
+	prices chan float64
+}
+
+func (d *feedDEX) GetTokenPrice(ctx context.Context, _ string) (float64, error) {
+	select {
+	case p := <-d.prices:
+		return p, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func watchPool(clk clock.Clock) *WorkerPool {
+	return &WorkerPool{logger: zap.NewNop(), config: &task.Config{PriceDelay: time.Second}, clock: clk}
+}
+
+func TestAwaitDip(t *testing.T) {
+	watch := &task.Task{TaskName: "dip", TokenMint: "MintA", DipPercent: 10, WatchDuration: time.Hour}
+
+	for name, tc := range map[string]struct {
+		reference float64
+		prices    []float64 // Последняя цена вызывает покупку
+	}{
+		"from the recent high": {prices: []float64{1.0, 2.0, 1.95, 1.85, 1.8}},
+		"from a fixed price":   {reference: 1.0, prices: []float64{1.5, 0.95, 0.91, 0.85}},
+		"skips missing prices": {reference: 1.0, prices: []float64{0, -1, 0.5}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clk := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+			d := &feedDEX{prices: make(chan float64)}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			type result struct {
+				dipped bool
+				err    error
+			}
+			done := make(chan result, 1)
+			go func() {
+				dipped, err := watchPool(clk).awaitDip(ctx, watch, d, tc.reference, zap.NewNop())
+				done <- result{dipped, err}
+			}()
+			clk.BlockUntil(2) // Тикер цены и срок наблюдения
+
+			for _, price := range tc.prices {
+				clk.Advance(time.Second)
+				select {
+				case d.prices <- price: // Цена запрашивается только по тику часов
+				case <-done:
+					t.Fatalf("bought before the price reached %v", price)
+				}
+			}
+			res := <-done
+			require.NoError(t, res.err)
+			assert.True(t, res.dipped)
+		})
+	}
+
+	t.Run("expires", func(t *testing.T) {
+		clk := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+		wp := watchPool(clk)
+		wp.config.PriceDelay = 2 * time.Hour
+		done := make(chan bool, 1)
+		go func() {
+			dipped, err := wp.awaitDip(context.Background(), watch, &feedDEX{}, 0, zap.NewNop())
+			assert.NoError(t, err)
+			done <- dipped
+		}()
+		clk.BlockUntil(2)
+		clk.Advance(watch.WatchDuration)
+		assert.False(t, <-done, "the watch gives up after watch_minutes")
+	})
+}
+
+func TestLastExitPrice(t *testing.T) {
+	closed := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	archive := execution.NewSessionArchive(filepath.Join(t.TempDir(), "sessions.jsonl"))
+	session := func(wallet, outcome string, last float64, at time.Time) execution.Session {
+		return execution.Session{ID: wallet + outcome, Mint: "MintA", Wallet: wallet, ClosedAt: at, LastPrice: last, Outcome: outcome}
+	}
+	wp := &WorkerPool{logger: zap.NewNop(), sessions: archive}
+	watch := &task.Task{TaskName: "dip", WalletName: "main", TokenMint: "MintA", Reference: task.ReferenceLastExit}
+
+	_, err := wp.lastExitPrice(context.Background(), watch, zap.NewNop())
+	assert.ErrorContains(t, err, "has no archived sale")
+
+	require.NoError(t, archive.Archive(session("main", execution.OutcomeSold, 0.002, closed)))
+	require.NoError(t, archive.Archive(session("main", execution.OutcomeFailed, 0.009, closed.Add(time.Hour))))
+	require.NoError(t, archive.Archive(session("alt", execution.OutcomeSold, 0.005, closed.Add(2*time.Hour))))
+
+	price, err := wp.lastExitPrice(context.Background(), watch, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 0.002, price, "the wallet's last sale, not a failed exit or another wallet's")
+}
@@ -0,0 +1,76 @@
+// internal/bot/watchlist.go
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+)
+
+const watchlistTimeout = 15 * time.Second
+
+// logWatchlist котирует все токены на кошельках пакетно и выводит строку по каждому
+// токену, как только приходит его цена.
+func (r *Runner) logWatchlist(ctx context.Context) {
+	if r.defaultWallet == nil {
+		return
+	}
+	balances, err := r.balances.Balances(ctx, r.wallets)
+	if err != nil {
+		r.logger.Warn("⚠️  Failed to fetch wallet balances: " + err.Error())
+		return
+	}
+
+	held := make(map[string]float64)
+	for _, b := range balances {
+		for _, t := range b.Tokens {
+			if t.Amount > 0 {
+				held[t.Mint.String()] += t.UIAmount()
+			}
+		}
+	}
+	if len(held) == 0 {
+		return
+	}
+
+	mints := make([]string, 0, len(held))
+	for mint := range held {
+		mints = append(mints, mint)
+	}
+	sort.Strings(mints)
+
+	// Адаптеры инициализируются под один токен, поэтому у каждого токена свой
+	reqs := make([]dex.QuoteRequest, 0, len(mints))
+	for _, mint := range mints {
+		adapter, err := dex.GetDEXByName("snipe", r.solClient, r.defaultWallet, r.logger.Named("watchlist"))
+		if err != nil {
+			r.logger.Warn("⚠️  Watchlist unavailable: " + err.Error())
+			return
+		}
+		reqs = append(reqs, dex.QuoteRequest{Mint: mint, DEX: adapter})
+	}
+
+	quoteCtx, cancel := context.WithTimeout(ctx, watchlistTimeout)
+	defer cancel()
+
+	r.logger.Info(fmt.Sprintf("📋 Watchlist: quoting %d tokens", len(reqs)))
+	for q := range dex.QuoteBatch(quoteCtx, reqs, dex.DefaultQuoteConcurrency) {
+		if q.Err != nil {
+			r.logger.Debug(fmt.Sprintf("   %s: no quote: %v", shortMint(q.Mint), q.Err))
+			continue
+		}
+		r.logger.Info(fmt.Sprintf("   %s: %.10f SOL, holding %.2f (≈ %.4f SOL) in %s",
+			shortMint(q.Mint), q.Price, held[q.Mint], q.Price*held[q.Mint], q.Latency.Round(time.Millisecond)))
+	}
+}
+
+// shortMint сокращает адрес токена для вывода, например "7xKX...sAsU"
+func shortMint(mint string) string {
+	if len(mint) <= 8 {
+		return mint
+	}
+	return mint[:4] + "..." + mint[len(mint)-4:]
+}
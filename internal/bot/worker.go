@@ -4,13 +4,29 @@ package bot
 import (
 	"context"
 	"fmt"
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"github.com/rovshanmuradov/solana-bot/internal/backtest"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/bot/ui"
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/killswitch"
+	"github.com/rovshanmuradov/solana-bot/internal/metrics"
+	"github.com/rovshanmuradov/solana-bot/internal/monitor"
+	"net/url"
 	"sync"
 	"time"
 
 	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/orders"
+	"github.com/rovshanmuradov/solana-bot/internal/risk"
+	"github.com/rovshanmuradov/solana-bot/internal/safety"
+	"github.com/rovshanmuradov/solana-bot/internal/storage"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type WorkerPool struct {
@@ -21,6 +37,54 @@ type WorkerPool struct {
 	config    *task.Config
 	solClient *blockchain.Client
 	wallets   map[string]*task.Wallet
+	notifier  *notify.Notifier
+	recorder  *execution.Recorder
+	positions *metrics.Positions
+	renderer  *ui.Renderer
+	book      *positionBook
+	intents   *execution.IntentLog
+	sessions  *execution.SessionArchive
+	orders    *orders.Book
+	store     *storage.Positions // Журнал открытых позиций для восстановления после перезапуска
+	clock     clock.Clock        // Часы мониторинга и наблюдения за ценой
+	safety    *safety.Checker    // Оценка риска токена перед покупкой (nil — выключена)
+	kill      *killswitch.Switch // Аварийный останов: продать все и не выполнять задачи до rearm
+	audit     *audit.Log         // Журнал изменяющих команд из Telegram (nil — не ведется)
+	lossLimit *risk.LossLimit    // Дневной лимит убытка (nil — выключен)
+	cooldown  *risk.Cooldown     // Повторные покупки токена кошельком в пределах buy_cooldown (nil — выключен)
+
+	reservations exposureReservations // Покупки в пути для ограничений размера позиций
+
+	results *taskResults             // Итоги выполненных задач для сводки в конце запуска
+	market  *backtest.Recorder       // Запись снимков рынка (nil — выключена)
+	geyser  *blockchain.GeyserClient // Поток изменений аккаунтов цены (nil — цена только опросом)
+
+	// Баннер сработавшего дневного лимита убытка выведен над мониторингом
+	lossBannerMu sync.Mutex
+	lossBanner   bool
+
+	// Мониторинги открытых позиций по ключу кошелек/mint, для команд из Telegram
+	monitorsMu sync.Mutex
+	monitors   map[string]*MonitorWorker
+
+	// Расписания задач DCA по ключу кошелек/mint, для экрана позиции
+	schedulesMu sync.Mutex
+	schedules   map[string]*dcaSchedule
+
+	// Сводный PnL задач с wallet_spread по ключу задача/mint, для экрана позиции
+	spreadsMu sync.Mutex
+	spreads   map[string]*spreadPnL
+
+	tokens *tokenNames // Метаданные токенов задач для экрана и уведомлений
+
+	// Клиенты эндпоинтов из колонки rpc задач, по URL
+	clientsMu sync.Mutex
+	clients   map[string]*blockchain.Client
+
+	// Ключи намерений, чьи сделки прошли до перезапуска и не должны повторяться
+	recoveredIntents map[string]bool
+	// Позиции, открытые до перезапуска, по ключу кошелек/mint: мониторинг продолжается без покупки
+	restoredPositions map[string]storage.Position
 }
 
 func NewWorkerPool(
@@ -29,8 +93,33 @@ func NewWorkerPool(
 	logger *zap.Logger,
 	solClient *blockchain.Client,
 	wallets map[string]*task.Wallet,
+	notifier *notify.Notifier,
+	recorder *execution.Recorder,
+	positions *metrics.Positions,
+	intents *execution.IntentLog,
+	sessions *execution.SessionArchive,
+	orderBook *orders.Book,
+	store *storage.Positions,
+	recoveredIntents map[string]bool,
+	restoredPositions map[string]storage.Position,
 	tasks <-chan *task.Task,
 ) *WorkerPool {
+	renderer := ui.NewRenderer(ui.DefaultFrameInterval)
+
+	// Панель логов под мониторингом получает те же сообщения, что и консоль
+	if cfg.LogPaneLines > 0 {
+		pane := ui.NewLogPane(cfg.LogPaneLines)
+		renderer.SetLogPane(pane)
+		logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, pane)
+		}))
+	}
+
+	var checker *safety.Checker
+	if cfg.SafetyMaxRisk > 0 {
+		checker = safety.NewChecker(solClient, cfg.SafetyMaxTopHolders)
+	}
+
 	return &WorkerPool{
 		ctx:       ctx,
 		config:    cfg,
@@ -38,10 +127,34 @@ func NewWorkerPool(
 		tasks:     tasks,
 		solClient: solClient,
 		wallets:   wallets,
+		notifier:  notifier,
+		recorder:  recorder,
+		positions: positions,
+		renderer:  renderer,
+		book:      newPositionBook(),
+		intents:   intents,
+		sessions:  sessions,
+		orders:    orderBook,
+		store:     store,
+		clock:     clock.Real,
+		safety:    checker,
+		kill:      killswitch.New(),
+		monitors:  make(map[string]*MonitorWorker),
+		schedules: make(map[string]*dcaSchedule),
+		spreads:   make(map[string]*spreadPnL),
+		tokens:    newTokenNames(),
+		results:   &taskResults{},
+
+		recoveredIntents:  recoveredIntents,
+		restoredPositions: restoredPositions,
 	}
 }
 
 func (wp *WorkerPool) Start(n int) {
+	go wp.renderer.Run(wp.ctx)
+	wp.startLossLimit()
+	wp.cooldown = risk.NewCooldown(wp.config.BuyCooldown, wp.clock.Now)
+
 	for i := 0; i < n; i++ {
 		wp.wg.Add(1)
 		go wp.worker(i + 1)
@@ -66,23 +179,40 @@ func (wp *WorkerPool) worker(id int) {
 				logger.Info("✅ All tasks completed")
 				return
 			}
-			wp.handleTask(wp.ctx, t, logger)
+			wp.results.record(t, wp.handleTask(wp.ctx, t, logger))
 		}
 	}
 }
 
-func (wp *WorkerPool) handleTask(ctx context.Context, t *task.Task, logger *zap.Logger) {
+// handleTask выполняет задачу; возвращаемая ошибка попадает в итоги запуска.
+func (wp *WorkerPool) handleTask(ctx context.Context, t *task.Task, logger *zap.Logger) error {
+	if err := wp.checkHalted(t, logger); err != nil {
+		return err
+	}
+	if err := wp.checkSchedule(t, logger); err != nil {
+		return err
+	}
 	w := wp.wallets[t.WalletName]
 	if w == nil {
 		logger.Warn("⚠️  Skipping task - no wallet found: " + t.WalletName)
-		return
+		return fmt.Errorf("no wallet found: %s", t.WalletName)
+	}
+
+	client, err := wp.clientFor(t)
+	if err != nil {
+		logger.Error(fmt.Sprintf("❌ RPC override error for task '%s': %v", t.TaskName, err))
+		return err
 	}
 
-	dexAdapter, err := dex.GetDEXByName(t.Module, wp.solClient, w, logger)
+	dexAdapter, err := dex.GetDEXByName(t.Module, client, w, logger)
 	if err != nil {
 		logger.Error(fmt.Sprintf("❌ DEX adapter init error for task '%s': %v", t.TaskName, err))
-		return
+		return err
 	}
+	if src, ok := dexAdapter.(dex.MigrationSource); ok {
+		src.OnMigration(func(ev dex.MigrationEvent) { wp.alertTokenMigrated(t, ev) })
+	}
+	wp.tokens.resolve(ctx, t.TokenMint, dexAdapter, logger)
 
 	logger.Info(fmt.Sprintf("⚡ Executing %s on %s for %s...%s",
 		string(t.Operation),
@@ -90,29 +220,127 @@ func (wp *WorkerPool) handleTask(ctx context.Context, t *task.Task, logger *zap.
 		t.TokenMint[:4],
 		t.TokenMint[len(t.TokenMint)-4:]))
 
-	if t.Operation == task.OperationSnipe || t.Operation == task.OperationSwap {
-		err := wp.handleMonitoredTask(ctx, t, dexAdapter, logger)
+	if t.Operation == task.OperationWatch {
+		err = wp.handleWatchTask(ctx, t, dexAdapter, logger)
+		if err != nil {
+			logger.Error("❌ Watch task failed: " + err.Error())
+		}
+	} else if t.Operation == task.OperationLimitBuy || t.Operation == task.OperationLimitSell {
+		err = wp.handleLimitTask(ctx, t, dexAdapter, logger)
+		if err != nil {
+			logger.Error("❌ Limit order failed: " + err.Error())
+		}
+	} else if t.Operation == task.OperationDCA {
+		err = wp.handleDCATask(ctx, t, dexAdapter, logger)
+		if err != nil {
+			logger.Error("❌ DCA task failed: " + err.Error())
+		}
+	} else if (t.Operation == task.OperationSnipe || t.Operation == task.OperationSwap) && t.Sliced() {
+		err = wp.handleSlicedTask(ctx, t, dexAdapter, client, logger)
+		if err != nil {
+			logger.Error("❌ Sliced buy failed: " + err.Error())
+		}
+	} else if t.Operation == task.OperationSnipe || t.Operation == task.OperationSwap {
+		err = wp.handleMonitoredTask(ctx, t, dexAdapter, logger)
 		if err != nil {
 			logger.Error("❌ Monitored task failed: " + err.Error())
 		}
 	} else {
-		err := dexAdapter.Execute(ctx, t)
+		err = wp.handleSellTask(ctx, t, dexAdapter, logger)
 		if err != nil {
 			logger.Error(fmt.Sprintf("❌ Task execution failed for '%s': %v", t.TaskName, err))
+			wp.alertTradeFailed(t, err)
 		} else {
 			logger.Info("🎉 Trade completed successfully: " + t.TaskName)
+			wp.alertTradeExecuted(t)
 		}
 	}
+	return err
 }
 
 func (wp *WorkerPool) handleMonitoredTask(ctx context.Context, t *task.Task, dexAdapter dex.DEX, logger *zap.Logger) error {
+	return wp.runMonitoredTask(ctx, t, dexAdapter, logger, nil)
+}
+
+// runMonitoredTask покупает токен и мониторит позицию. onBought, если задан,
+// вызывается, когда покупка учтена в открытой позиции, до начала мониторинга:
+// к этому моменту к позиции уже можно добавлять следующие покупки.
+func (wp *WorkerPool) runMonitoredTask(ctx context.Context, t *task.Task, dexAdapter dex.DEX, logger *zap.Logger, onBought func()) error {
 	logger.Info(fmt.Sprintf("📊 Starting monitored trade for %s...%s", t.TokenMint[:4], t.TokenMint[len(t.TokenMint)-4:]))
 
-	if err := dexAdapter.Execute(ctx, t); err != nil {
-		return fmt.Errorf("execute task: %w", err)
+	if err := wp.checkTransferFee(ctx, t, logger); err != nil {
+		wp.alertTradeFailed(t, err)
+		return err
 	}
 
-	logger.Info("🎉 Trade executed successfully: " + t.TaskName)
+	var tr *execution.Trace
+	releaseBuy := func() {}
+	defer func() { releaseBuy() }()
+	saved, restored := wp.restoredPosition(t)
+	if restored {
+		logger.Warn(fmt.Sprintf("♻️  Restoring position opened before the restart: %.3f SOL invested, entry %.10f SOL (%s)",
+			saved.Invested(), saved.EntryPrice, t.TaskName))
+	} else if wp.recovered(t, execution.SideBuy) {
+		logger.Warn("♻️  Buy landed before the restart, resuming monitoring without buying again: " + t.TaskName)
+	} else {
+		if err := wp.checkHalted(t, logger); err != nil {
+			return err
+		}
+		if err := wp.checkLossLimit(t, logger); err != nil {
+			wp.alertTradeFailed(t, err)
+			return err
+		}
+		forget, err := wp.acquireBuyCooldown(t, logger)
+		if err != nil {
+			return err
+		}
+		bought := false
+		defer func() {
+			if !bought {
+				forget() // Несостоявшаяся покупка не мешает повторить задачу
+			}
+		}()
+		if err := wp.checkSafety(ctx, t, logger); err != nil {
+			wp.alertTradeFailed(t, err)
+			return err
+		}
+		if err := wp.checkFunds(ctx, t, logger); err != nil {
+			wp.alertTradeFailed(t, err)
+			return err
+		}
+		if err := wp.approveOrder(ctx, t, execution.SideBuy, t.AmountSol, logger); err != nil {
+			return err
+		}
+		// Kill switch мог сработать, пока шли проверки и ожидалось подтверждение
+		if err := wp.checkHalted(t, logger); err != nil {
+			return err
+		}
+		release, err := wp.reserveBuy(t, t.AmountSol, logger)
+		if err != nil {
+			wp.alertTradeFailed(t, err)
+			return err
+		}
+		releaseBuy = release
+		tradeCtx, cancel := execution.WithBudget(ctx, wp.config.TradeDeadline)
+		traceCtx, trace, err := wp.beginTrade(tradeCtx, t, dexAdapter, execution.SideBuy)
+		if err != nil {
+			cancel()
+			wp.alertTradeFailed(t, err)
+			return err
+		}
+		tr = trace
+		err = wp.finishTrade(tradeCtx, t, tr, dexAdapter.Execute(traceCtx, t))
+		cancel()
+		wp.checkLatencyBudget(t, tr, logger)
+		if err != nil {
+			wp.alertTradeFailed(t, err)
+			return fmt.Errorf("execute task: %w", err)
+		}
+		bought = true
+
+		logger.Info("🎉 Trade executed successfully: " + t.TaskName)
+		wp.alertTradeExecuted(t)
+	}
 
 	var tokenBalance uint64
 	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -140,19 +368,49 @@ tokenLoop:
 	}
 
 	if tokenBalance == 0 {
+		if restored {
+			logger.Warn("⚠️  Restored position no longer holds tokens; closing it: " + t.TaskName)
+			wp.forgetPosition(t, logger)
+			return nil
+		}
 		logger.Warn("⚠️  No tokens received; skipping monitor")
 		return nil
 	}
 
+	// Повторная покупка того же токена тем же кошельком сливается с открытой позицией
+	var pos *position
+	var merged bool
+	decimals := wp.positionDecimals(ctx, t, logger)
+	if restored {
+		pos, merged = wp.book.restore(t, decimals, saved)
+	} else {
+		pos, merged = wp.book.add(t, decimals, positionBuy{
+			Task:      t.TaskName,
+			AmountSol: t.AmountSol,
+			Tokens:    boughtTokens(tr.Record(), tokenBalance),
+			At:        time.Now(),
+		})
+	}
+	releaseBuy() // Покупка уже учтена во вложениях открытой позиции
+	wp.savePosition(pos, tokenBalance, logger)
+	if onBought != nil {
+		onBought()
+	}
+	if merged {
+		wp.reportMerge(t, pos, logger)
+		return nil
+	}
+
 	// Создаем SellFunc для продажи токенов
-	sellFn := CreateSellFunc(
+	slippage, slippageLabel := wp.sellSlippage(t, dexAdapter, logger)
+	sellFn := wp.withSellAlerts(t, wp.withSellTrace(t, dexAdapter, CreateSellFunc(
 		dexAdapter,
 		t.TokenMint,
-		t.SlippagePercent,
+		slippage,
 		t.PriorityFeeSol,
 		t.ComputeUnits,
 		logger.Named("sell"),
-	)
+	)))
 
 	// Создаем и запускаем рабочий процесс мониторинга
 	worker := NewMonitorWorker(
@@ -164,10 +422,39 @@ tokenLoop:
 		0, // Initial price will be fetched by monitor
 		wp.config.MonitorDelay,
 		sellFn,
+		slippageLabel,
+		wp.positions,
+		wp.renderer,
+		pos,
+		wp.book,
+		wp.newIndicatorAlert(logger),
+		wp.sessions,
+		wp.marketCapTriggers(ctx, t, logger),
+		wp.newMintWatch(ctx, t, logger),
+		wp.newRugSentinel(t, dexAdapter, logger),
+		wp.clock,
+		wp.exitRules(t),
+		wp.exitPlan(t, logger),
+		wp.dcaSchedule(t),
+		wp.spreadPnL(t),
+		wp.market,
+		wp.tokens,
+		wp.newPriceStream(t, dexAdapter),
+		wp.newOrderFlow(t, logger),
 	)
 
+	worker.kill = wp
+
 	// Запускаем и ожидаем завершения рабочего процесса
+	untrack := wp.trackMonitor(t, worker)
 	err := worker.Start()
+	untrack()
+	wp.recordClosedPosition(worker)
+	// Позиция, мониторинг которой прервала остановка бота или сбой, остается
+	// в журнале и продолжится при следующем запуске
+	if outcome := worker.history.result(); outcome == execution.OutcomeSold || outcome == execution.OutcomeExited {
+		wp.forgetPosition(t, logger)
+	}
 	if err != nil {
 		logger.Error("❌ Monitor worker failed: " + err.Error())
 		return err
@@ -175,3 +462,269 @@ tokenLoop:
 
 	return nil
 }
+
+// exitRules собирает stop-loss / take-profit / trailing stop позиции: значения задачи, иначе из config.json
+func (wp *WorkerPool) exitRules(t *task.Task) *monitor.ExitRules {
+	stopLoss, takeProfit, trailing := t.StopLossPercent, t.TakeProfitPercent, t.TrailingStopPercent
+	if stopLoss == 0 {
+		stopLoss = wp.config.StopLossPercent
+	}
+	if takeProfit == 0 {
+		takeProfit = wp.config.TakeProfitPercent
+	}
+	if trailing == 0 {
+		trailing = wp.config.TrailingStopPercent
+	}
+	rules := monitor.NewExitRules(stopLoss, takeProfit, trailing)
+	rules.WarmUp(wp.config.StopLossWarmup, wp.clock.Now)
+	return rules
+}
+
+// exitPlan готовит многоступенчатый план выхода задачи из config.json exit_plans
+// (nil — план не задан).
+func (wp *WorkerPool) exitPlan(t *task.Task, logger *zap.Logger) *monitor.ExitPlan {
+	if t.ExitPlan == "" {
+		return nil
+	}
+	tiers, ok := wp.config.ExitPlans[t.ExitPlan]
+	if !ok {
+		logger.Warn(fmt.Sprintf("⚠️  Exit plan %q not found in config.json, monitoring without it", t.ExitPlan))
+		return nil
+	}
+	return monitor.NewExitPlan(tiers)
+}
+
+// reportMerge сообщает о слиянии покупки с уже открытой позицией
+func (wp *WorkerPool) reportMerge(t *task.Task, pos *position, logger *zap.Logger) {
+	msg := fmt.Sprintf("Merged %s into open position %s (%s): %.3f SOL invested, avg entry %.10f SOL, buys: %s",
+		t.TaskName, wp.tokens.label(t.TokenMint), t.WalletName, pos.Invested(), pos.EntryPrice(), pos.History())
+	logger.Info("🔗 " + msg)
+	wp.notifier.Notify(notify.Alert{
+		Type:     notify.AlertPositionMerged,
+		Key:      t.TokenMint,
+		Severity: notify.SeverityInfo,
+		Message:  msg,
+	})
+}
+
+// boughtTokens возвращает количество купленных токенов: фактическое из транзакции,
+// иначе ожидаемое по котировке, иначе весь баланс кошелька
+func boughtTokens(rec execution.Record, balance uint64) uint64 {
+	if rec.ActualOut > 0 {
+		return rec.ActualOut
+	}
+	if rec.QuotedOut > 0 {
+		return rec.QuotedOut
+	}
+	return balance
+}
+
+// withSellAlerts оборачивает SellFunc отправкой уведомления о результате продажи
+func (wp *WorkerPool) withSellAlerts(t *task.Task, sellFn SellFunc) SellFunc {
+	return func(ctx context.Context, percent float64) error {
+		err := sellFn(ctx, percent)
+		if err != nil {
+			wp.notifier.Notify(notify.Alert{
+				Type:     notify.AlertSellFailed,
+				Key:      t.TokenMint,
+				Severity: notify.SeverityCritical,
+				Message:  fmt.Sprintf("Sell failed for %s (%s): %v", t.TaskName, wp.tokens.label(t.TokenMint), err),
+			})
+			return err
+		}
+		wp.notifier.Notify(notify.Alert{
+			Type:     notify.AlertSellCompleted,
+			Key:      t.TokenMint,
+			Severity: notify.SeverityInfo,
+			Message:  fmt.Sprintf("Sold %.1f%% of %s (%s)", percent, t.TaskName, wp.tokens.label(t.TokenMint)),
+		})
+		return nil
+	}
+}
+
+func (wp *WorkerPool) alertTradeExecuted(t *task.Task) {
+	wp.notifier.Notify(notify.Alert{
+		Type:     notify.AlertTradeExecuted,
+		Key:      t.TokenMint,
+		Severity: notify.SeverityInfo,
+		Message:  fmt.Sprintf("%s executed on %s: %s (%s)", t.Operation, t.Module, t.TaskName, wp.tokens.label(t.TokenMint)),
+	})
+}
+
+func (wp *WorkerPool) alertTradeFailed(t *task.Task, err error) {
+	wp.notifier.Notify(notify.Alert{
+		Type:     notify.AlertTradeFailed,
+		Key:      t.TokenMint,
+		Severity: notify.SeverityWarning,
+		Message:  fmt.Sprintf("%s failed on %s: %s (%s): %v", t.Operation, t.Module, t.TaskName, wp.tokens.label(t.TokenMint), err),
+	})
+}
+
+// alertTokenMigrated сообщает, что токен задачи ушел с bonding curve и сделки идут на новой площадке
+func (wp *WorkerPool) alertTokenMigrated(t *task.Task, ev dex.MigrationEvent) {
+	wp.notifier.Notify(notify.Alert{
+		Type:     notify.AlertTokenMigrated,
+		Key:      ev.Mint,
+		Severity: notify.SeverityInfo,
+		Message:  fmt.Sprintf("%s migrated from %s to %s: %s now trades there", wp.tokens.label(ev.Mint), ev.From, ev.To, t.TaskName),
+		Time:     ev.At,
+	})
+}
+
+// checkTransferFee отказывается от покупки токена, чья комиссия Token-2022 за перевод выше лимита
+func (wp *WorkerPool) checkTransferFee(ctx context.Context, t *task.Task, logger *zap.Logger) error {
+	if wp.config.MaxTransferFeeBps <= 0 {
+		return nil
+	}
+
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		return fmt.Errorf("invalid token mint: %w", err)
+	}
+
+	fee, err := wp.solClient.GetTransferFee(ctx, mint)
+	if err != nil {
+		logger.Warn("⚠️  Could not check transfer fee: " + err.Error())
+		return nil
+	}
+	if fee != nil && int(fee.BasisPoints) > wp.config.MaxTransferFeeBps {
+		return fmt.Errorf("token transfer fee %.2f%% exceeds limit %.2f%%",
+			fee.Percent(), float64(wp.config.MaxTransferFeeBps)/100)
+	}
+	return nil
+}
+
+// withSellTrace оборачивает SellFunc сбором метрик исполнения продажи
+func (wp *WorkerPool) withSellTrace(t *task.Task, dexAdapter dex.DEX, sellFn SellFunc) SellFunc {
+	return func(ctx context.Context, percent float64) error {
+		tradeCtx, cancel := execution.WithBudget(ctx, wp.config.TradeDeadline)
+		defer cancel()
+
+		traceCtx, tr, err := wp.beginTrade(tradeCtx, t, dexAdapter, execution.SideSell)
+		if err != nil {
+			return err
+		}
+		return wp.finishTrade(tradeCtx, t, tr, sellFn(traceCtx, wp.sweepDust(percent)))
+	}
+}
+
+// sellSlippage возвращает slippage для продажи токена задачи и подпись для экрана позиции.
+// Рекомендация строится по фактическому проскальзыванию прошлых продаж этого токена на этой
+// площадке; она применяется только при apply_learned_slippage, иначе лишь выводится в лог.
+func (wp *WorkerPool) sellSlippage(t *task.Task, dexAdapter dex.DEX, logger *zap.Logger) (float64, string) {
+	label := fmt.Sprintf("%.2f%% (task)", t.SlippagePercent)
+
+	store := wp.recorder.Store()
+	if store == nil {
+		return t.SlippagePercent, label
+	}
+	records, err := store.Load(time.Time{})
+	if err != nil {
+		logger.Debug("Failed to load execution history", zap.Error(err))
+		return t.SlippagePercent, label
+	}
+
+	learned := execution.LearnSellSlippage(records, t.TokenMint, dexAdapter.GetName())
+	if !learned.Reliable {
+		return t.SlippagePercent, label
+	}
+
+	if wp.config.ApplyLearnedSlippage {
+		logger.Info(fmt.Sprintf("🎯 Using learned sell slippage %.2f%% (worst %.2f%% over %d sells, task %.2f%%)",
+			learned.Percent, learned.Worst, learned.Samples, t.SlippagePercent))
+		return learned.Percent, fmt.Sprintf("%.2f%% (learned x%d)", learned.Percent, learned.Samples)
+	}
+
+	logger.Info(fmt.Sprintf("💡 Suggested sell slippage %.2f%% (worst %.2f%% over %d sells, task %.2f%%)",
+		learned.Percent, learned.Worst, learned.Samples, t.SlippagePercent))
+	return t.SlippagePercent, fmt.Sprintf("%.2f%% (learned %.2f%%)", t.SlippagePercent, learned.Percent)
+}
+
+// checkLatencyBudget предупреждает, если покупка ушла в сеть позже заданного бюджета задержки
+func (wp *WorkerPool) checkLatencyBudget(t *task.Task, tr *execution.Trace, logger *zap.Logger) {
+	budget := wp.config.LatencyBudget
+	rec := tr.Record()
+	if budget <= 0 || rec.Side != execution.SideBuy {
+		return
+	}
+
+	latency := rec.SendLatency()
+	if latency <= budget {
+		return
+	}
+
+	msg := fmt.Sprintf("Latency budget exceeded for %s (%s): sent after %s, budget %s (%s)",
+		t.TaskName, t.TokenMint, latency.Round(time.Millisecond), budget, rec.Breakdown())
+	logger.Warn("🐢 " + msg)
+	wp.notifier.Notify(notify.Alert{
+		Type:     notify.AlertLatencyBudget,
+		Key:      t.TaskName,
+		Severity: notify.SeverityWarning,
+		Message:  msg,
+	})
+}
+
+// startTrace создает трассировку исполнения сделки и кладет ее в контекст. RPC записи —
+// эндпоинт задачи или основной из rpc_list, пока пул не сообщит, какой эндпоинт принял
+// sendTransaction.
+func (wp *WorkerPool) startTrace(ctx context.Context, t *task.Task, dexAdapter dex.DEX, side string) (context.Context, *execution.Trace) {
+	var wallet string
+	if w := wp.wallets[t.WalletName]; w != nil {
+		wallet = w.PublicKey.String()
+	}
+	endpoint, err := wp.config.ResolveRPC(t.RPC)
+	if err != nil || endpoint == "" {
+		endpoint = wp.config.RPCList[0]
+	}
+
+	rec := execution.Record{
+		TaskName:  t.TaskName,
+		Side:      side,
+		Venue:     dexAdapter.GetName(),
+		RPC:       rpcLabel(endpoint),
+		Mint:      t.TokenMint,
+		Wallet:    wallet,
+		StartedAt: time.Now(),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		rec.Budget = deadline.Sub(rec.StartedAt).Milliseconds()
+	}
+	tr := execution.NewTrace(rec)
+	ctx = blockchain.WithEndpointObserver(ctx, func(method, host string) {
+		if method == "sendTransaction" {
+			tr.SetRPC(host)
+		}
+	})
+	return execution.WithTrace(ctx, tr), tr
+}
+
+// clientFor возвращает RPC-клиент задачи: общий, если колонка rpc пуста, иначе клиент
+// указанного эндпоинта (один на URL на все задачи).
+func (wp *WorkerPool) clientFor(t *task.Task) (*blockchain.Client, error) {
+	endpoint, err := wp.config.ResolveRPC(t.RPC)
+	if err != nil || endpoint == "" {
+		return wp.solClient, err
+	}
+
+	wp.clientsMu.Lock()
+	defer wp.clientsMu.Unlock()
+	if c, ok := wp.clients[endpoint]; ok {
+		return c, nil
+	}
+	if wp.clients == nil {
+		wp.clients = make(map[string]*blockchain.Client)
+	}
+	c := wp.solClient.WithEndpoint(endpoint)
+	wp.clients[endpoint] = c
+	wp.logger.Info(fmt.Sprintf("🛰️  Task %s trades through RPC %s", t.TaskName, rpcLabel(endpoint)))
+	return c, nil
+}
+
+// rpcLabel возвращает хост RPC без пути и ключей для отчетов
+func rpcLabel(rpcURL string) string {
+	u, err := url.Parse(rpcURL)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
@@ -3,18 +3,27 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"golang.org/x/sync/errgroup"
+	"sync"
 	"time"
 
+	"github.com/rovshanmuradov/solana-bot/internal/backtest"
 	"github.com/rovshanmuradov/solana-bot/internal/bot/ui"
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
 	"github.com/rovshanmuradov/solana-bot/internal/dex"
 	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/metrics"
 	"github.com/rovshanmuradov/solana-bot/internal/monitor"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"go.uber.org/zap"
 )
 
+// logPaneStep — на сколько строк меняется высота панели логов за одно нажатие
+const logPaneStep = 2
+
 // SellFunc представляет функцию для продажи токенов
 type SellFunc func(ctx context.Context, percent float64) error
 
@@ -28,9 +37,43 @@ type MonitorWorker struct {
 	session         *monitor.MonitoringSession
 	uiHandle        *ui.Handler
 	sellFn          SellFunc
+	sellSlippage    string // Настройка slippage продажи для экрана позиции
 	monitorInterval time.Duration
+	positions       *metrics.Positions
+	renderer        *ui.Renderer
+	pos             *position // Позиция, в которую сливаются повторные покупки токена
+	book            *positionBook
+	indicators      *monitor.Indicators // EMA/RSI/волатильность по ценам позиции
+	indicatorAlert  *indicatorAlert
+	openedAt        time.Time
+	history         *sessionHistory    // Жизненный цикл сессии для архива
+	targets         *marketCapTriggers // Продажи по капитализации (nil — нет целей)
+	mintWatch       *mintWatch         // Наблюдение за mint (nil — выключено)
+	rug             *rugSentinel       // Сторож rug-pull (nil — выключен)
+	prices          *priceStream       // Поток изменений аккаунтов цены (nil — только опрос)
+	flow            *orderFlow         // Поток сделок других кошельков по токену (nil — выключен)
+	exits           *monitor.ExitRules // Stop-loss / take-profit позиции
+	plan            *monitor.ExitPlan  // Многоступенчатый план выхода (nil — не задан)
+	dca             *dcaSchedule       // Расписание покупок DCA (nil — позиция набрана не по DCA)
+	spread          *spreadPnL         // Сводный PnL задачи по всем кошелькам (nil — задача на одном кошельке)
+	market          *backtest.Recorder // Запись снимков рынка (nil — выключена)
+	tokens          *tokenNames        // Метаданные токенов для заголовка экрана
+	clock           clock.Clock
+	kill            panicButton      // Kill switch для команд panic / rearm (nil — недоступен)
+	sellRequests    chan sellRequest // Продажи по командам вне консоли (Telegram, kill switch)
+	stopped         chan struct{}    // Закрывается в Stop
+	stopOnce        sync.Once
+}
+
+// sellRequest — продажа percent процентов баланса по команде вне консоли.
+type sellRequest struct {
+	percent float64
+	result  chan error // Итог продажи (nil — итог не ждут)
 }
 
+// errMonitorStopped — мониторинг позиции уже завершается.
+var errMonitorStopped = errors.New("monitoring of the position has stopped")
+
 // NewMonitorWorker создает новый экземпляр рабочего процесса мониторинга
 func NewMonitorWorker(
 	ctx context.Context,
@@ -41,7 +84,27 @@ func NewMonitorWorker(
 	initialPrice float64,
 	monitorInterval time.Duration,
 	sellFn SellFunc,
+	sellSlippage string,
+	positions *metrics.Positions,
+	renderer *ui.Renderer,
+	pos *position,
+	book *positionBook,
+	indicatorAlert *indicatorAlert,
+	archive *execution.SessionArchive,
+	targets *marketCapTriggers,
+	watch *mintWatch,
+	rug *rugSentinel,
+	clk clock.Clock,
+	exits *monitor.ExitRules,
+	plan *monitor.ExitPlan,
+	dca *dcaSchedule,
+	spread *spreadPnL,
+	market *backtest.Recorder,
+	tokens *tokenNames,
+	prices *priceStream,
+	flow *orderFlow,
 ) *MonitorWorker {
+	clk = clock.Or(clk)
 	return &MonitorWorker{
 		ctx:    ctx,
 		logger: logger.Named("monitor_worker"),
@@ -50,11 +113,40 @@ func NewMonitorWorker(
 		sellFn: sellFn,
 		// Store the monitor interval for later use
 		monitorInterval: monitorInterval,
+		sellSlippage:    sellSlippage,
+		positions:       positions,
+		renderer:        renderer,
+		pos:             pos,
+		book:            book,
+		indicators:      monitor.NewIndicators(0, 0, 0, 0),
+		indicatorAlert:  indicatorAlert,
+		openedAt:        clk.Now(),
+		history:         &sessionHistory{archive: archive, clock: clk},
+		targets:         targets,
+		mintWatch:       watch,
+		rug:             rug,
+		prices:          prices,
+		flow:            flow,
+		exits:           exits,
+		plan:            plan,
+		dca:             dca,
+		spread:          spread,
+		market:          market,
+		tokens:          tokens,
+		clock:           clk,
+		sellRequests:    make(chan sellRequest),
+		stopped:         make(chan struct{}),
 	}
 }
 
 // Start запускает рабочий процесс мониторинга
 func (mw *MonitorWorker) Start() error {
+	// Позиция пропадает из метрик и из открытых при любом завершении мониторинга
+	defer mw.positions.Remove(mw.task.TokenMint, mw.task.WalletName)
+	defer mw.book.close(mw.pos)
+	defer mw.archiveSession()
+	mw.renderer.Open(mw.task.TokenMint, mw.task.WalletName)
+
 	// Создаем конфигурацию сессии мониторинга
 	monitorConfig := &monitor.SessionConfig{
 		Task:            mw.task,
@@ -63,6 +155,8 @@ func (mw *MonitorWorker) Start() error {
 		DEX:             mw.dex,
 		Logger:          mw.logger.Named("session"),
 		MonitorInterval: mw.monitorInterval,
+		Clock:           mw.clock,
+		PriceTrigger:    mw.prices.trigger(),
 	}
 
 	// Создаем пользовательский интерфейс
@@ -92,6 +186,26 @@ func (mw *MonitorWorker) Start() error {
 		return mw.handlePriceUpdates(gCtx)
 	})
 
+	// Горутина наблюдения за mint-аккаунтом токена
+	g.Go(func() error {
+		return mw.watchMint(gCtx)
+	})
+
+	// Горутина сторожа rug-pull
+	g.Go(func() error {
+		return mw.watchRug(gCtx)
+	})
+
+	// Горутина потока изменений аккаунтов цены из Geyser
+	g.Go(func() error {
+		return mw.streamPrices(gCtx)
+	})
+
+	// Горутина потока сделок по токену
+	g.Go(func() error {
+		return mw.watchOrderFlow(gCtx)
+	})
+
 	// Горутина для обработки ошибок сессии мониторинга
 	g.Go(func() error {
 		return mw.handleSessionErrors(gCtx)
@@ -99,6 +213,11 @@ func (mw *MonitorWorker) Start() error {
 
 	// Ожидаем завершения всех горутин
 	if err := g.Wait(); err != nil {
+		outcome := execution.OutcomeFailed
+		if errors.Is(err, context.Canceled) {
+			outcome = execution.OutcomeStopped
+		}
+		mw.history.finish(outcome, err.Error())
 		mw.logger.Error("❌ Monitor worker failed: " + err.Error())
 		return err
 	}
@@ -108,12 +227,68 @@ func (mw *MonitorWorker) Start() error {
 
 // Stop останавливает рабочий процесс мониторинга
 func (mw *MonitorWorker) Stop() {
+	mw.stopOnce.Do(func() { close(mw.stopped) })
 	if mw.uiHandle != nil {
 		mw.uiHandle.Stop()
 	}
 	if mw.session != nil {
 		mw.session.Stop()
 	}
+	mw.renderer.Discard(mw.task.TokenMint, mw.task.WalletName)
+	// Покупки после начала продажи открывают новую позицию
+	mw.book.close(mw.pos)
+}
+
+// RequestSell просит мониторинг продать percent процентов баланса; 100 продает позицию
+// целиком и завершает мониторинг.
+func (mw *MonitorWorker) RequestSell(ctx context.Context, percent float64) error {
+	return mw.requestSell(ctx, sellRequest{percent: percent})
+}
+
+// SellAll продает позицию целиком, завершает мониторинг и ждет итога продажи.
+func (mw *MonitorWorker) SellAll(ctx context.Context) error {
+	result := make(chan error, 1)
+	if err := mw.requestSell(ctx, sellRequest{percent: 100, result: result}); err != nil {
+		return err
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (mw *MonitorWorker) requestSell(ctx context.Context, req sellRequest) error {
+	select {
+	case mw.sellRequests <- req:
+		return nil
+	case <-mw.stopped:
+		return errMonitorStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sellOnRequest продает долю позиции по команде RequestSell.
+// done = true, если позиция продана целиком и мониторинг завершен.
+func (mw *MonitorWorker) sellOnRequest(ctx context.Context, percent float64) (done bool, err error) {
+	mw.logger.Info(fmt.Sprintf("💰 Remote sell of %.1f%% requested", percent))
+	mw.history.event("sell_requested", fmt.Sprintf("%.1f%% (remote)", percent))
+	if percent < 100 {
+		if err := mw.sellFn(ctx, percent); err != nil {
+			mw.logger.Error("❌ Remote sell failed: " + err.Error())
+		}
+		return false, nil
+	}
+
+	mw.Stop()
+	if err := mw.sellFn(ctx, 100); err != nil {
+		mw.logger.Error("❌ Remote sell failed: " + err.Error())
+		return true, err
+	}
+	mw.history.finish(execution.OutcomeSold, "remote")
+	return true, nil
 }
 
 // handleUIEvents processes UI events and initiates sale or exit
@@ -123,6 +298,15 @@ func (mw *MonitorWorker) handleUIEvents(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 
+		case req := <-mw.sellRequests:
+			done, err := mw.sellOnRequest(ctx, req.percent)
+			if req.result != nil {
+				req.result <- err
+			}
+			if err != nil || done {
+				return err
+			}
+
 		case event, ok := <-mw.uiHandle.Events():
 			if !ok {
 				return nil // channel closed
@@ -134,8 +318,9 @@ func (mw *MonitorWorker) handleUIEvents(ctx context.Context) error {
 
 				fmt.Println("\nPreparing to sell tokens...")
 
-				// Создаем контекст, привязанный к родительскому контексту
-				sellCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+				// Создаем контекст, привязанный к родительскому контексту;
+				// срок продажи задает бюджет сделки в sellFn
+				sellCtx, cancel := context.WithCancel(ctx)
 				defer cancel()
 
 				// RPC-имплементация уже ждет CommitmentProcessed
@@ -148,6 +333,7 @@ func (mw *MonitorWorker) handleUIEvents(ctx context.Context) error {
 				mw.Stop()
 
 				// Выполняем продажу синхронно, чтобы дождаться результата
+				mw.history.event("sell_requested", fmt.Sprintf("%.1f%%", mw.task.AutosellAmount))
 				if err := mw.sellFn(sellCtx, mw.task.AutosellAmount); err != nil {
 					mw.logger.Error("❌ Failed to sell tokens: " + err.Error())
 					fmt.Printf("Error selling tokens: %v\n", err)
@@ -156,13 +342,31 @@ func (mw *MonitorWorker) handleUIEvents(ctx context.Context) error {
 
 				mw.logger.Info("✅ Tokens sold successfully!")
 				fmt.Println("Tokens sold successfully!")
+				mw.history.finish(execution.OutcomeSold, "")
 				return nil
 
 			case ui.ExitRequested:
 				mw.logger.Info("🚪 Exit requested by user")
 				fmt.Println("\nExiting monitor mode without selling tokens.")
 				mw.Stop()
+				mw.history.finish(execution.OutcomeExited, "")
 				return nil
+
+			case ui.LogPaneResized:
+				delta := logPaneStep
+				if event.Data == "-" {
+					delta = -logPaneStep
+				}
+				mw.renderer.ResizeLogPane(delta)
+
+			case ui.ExitRuleChanged:
+				mw.setExitRule(event.Data)
+
+			case ui.PanicRequested:
+				mw.engageKillSwitch()
+
+			case ui.RearmRequested:
+				mw.rearmKillSwitch()
 			}
 		}
 	}
@@ -179,6 +383,10 @@ func (mw *MonitorWorker) handlePriceUpdates(ctx context.Context) error {
 				return nil // Канал закрыт
 			}
 
+			// Под нагрузкой считаем PnL только по самому свежему обновлению
+			update = mw.latestPriceUpdate(update)
+			update = mw.withEntryPrice(update)
+
 			// Расчет PnL
 			pnlData, err := mw.calculatePnL(ctx, update)
 			if err != nil {
@@ -186,8 +394,195 @@ func (mw *MonitorWorker) handlePriceUpdates(ctx context.Context) error {
 				continue
 			}
 
+			mw.market.Record(backtest.Snapshot{
+				Time:  mw.clock.Now(),
+				Mint:  mw.task.TokenMint,
+				Venue: mw.dex.GetName(),
+				Price: update.Current,
+			})
+			mw.positions.Update(mw.task.TokenMint, mw.task.WalletName,
+				update.Current, pnlData.PnLPercentage, pnlData.NetPnL, mw.openedAt)
+			mw.history.observe(update, *pnlData)
+
+			if done, err := mw.sellAtTargets(ctx, update.Current); err != nil || done {
+				return err
+			}
+			if done, err := mw.sellByPlan(ctx, pnlData.PnLPercentage, update.Current); err != nil || done {
+				return err
+			}
+			if done, err := mw.sellAtExit(ctx, pnlData.PnLPercentage, update.Current); err != nil || done {
+				return err
+			}
+
+			mw.spread.update(mw.task.WalletName, pnlData.InitialInvestment, pnlData.NetPnL)
+			indicators := mw.indicators.Add(update.Current)
+			mw.indicatorAlert.check(mw.task, indicators)
+
 			// Отображение информации через UI
-			ui.Render(update, *pnlData, mw.task.TokenMint)
+			marketCap, targets := mw.targets.lines(update.Current)
+			mw.renderer.Submit(ui.Frame{
+				Update:       update,
+				PnL:          *pnlData,
+				TokenMint:    mw.task.TokenMint,
+				TokenName:    mw.tokens.title(mw.task.TokenMint),
+				Wallet:       mw.task.WalletName,
+				SellSlippage: mw.sellSlippage,
+				Indicators:   indicators,
+				MarketCap:    marketCap,
+				Targets:      targets,
+				Exits:        mw.exitsFrameLine(),
+				Schedule:     mw.dca.String(),
+				Spread:       mw.spread.String(),
+				Flow:         mw.flow.summary(),
+			})
+		}
+	}
+}
+
+// sellAtTargets продает доли позиции, чьи цели по капитализации достигнуты при цене price.
+// done = true, если цели продали позицию целиком и мониторинг завершен.
+func (mw *MonitorWorker) sellAtTargets(ctx context.Context, price float64) (done bool, err error) {
+	for _, target := range mw.targets.reached(price) {
+		capSol, capUSD := mw.targets.marketCap(price)
+		percent := mw.targets.sellPercent(target)
+		mw.logger.Info(fmt.Sprintf("🎯 Market cap target reached: %s (now %.2f SOL, $%.0f), selling %.1f%% of the balance",
+			target, capSol, capUSD, percent))
+		mw.history.event("target_hit", target.String())
+
+		if mw.targets.done() {
+			mw.Stop()
+		}
+		if err := mw.sellFn(ctx, percent); err != nil {
+			mw.logger.Error("❌ Target sell failed: " + err.Error())
+			return true, err
+		}
+	}
+	if mw.targets.done() {
+		mw.logger.Info("✅ All market cap targets sold")
+		mw.history.finish(execution.OutcomeSold, "")
+		return true, nil
+	}
+	return false, nil
+}
+
+// sellByPlan продает доли позиции по ступеням плана выхода, сработавшим при PnL
+// pnlPercent и цене price. done = true, если план продал позицию целиком.
+func (mw *MonitorWorker) sellByPlan(ctx context.Context, pnlPercent, price float64) (done bool, err error) {
+	for {
+		tier, percent, hit := mw.plan.Check(pnlPercent, price)
+		if !hit {
+			break
+		}
+		step := mw.plan.Describe(tier)
+		mw.logger.Info(fmt.Sprintf("🪜 Exit plan step %s hit at %.2f%% PnL, selling %.1f%% of the balance",
+			step, pnlPercent, percent))
+		mw.history.event("plan_step", step)
+
+		if mw.plan.Done() {
+			mw.Stop()
+		}
+		if err := mw.sellFn(ctx, percent); err != nil {
+			mw.logger.Error("❌ Exit plan sell failed: " + err.Error())
+			return true, err
+		}
+	}
+	if mw.plan.Done() {
+		mw.logger.Info("✅ Exit plan sold the position")
+		mw.history.finish(execution.OutcomeSold, "exit_plan")
+		return true, nil
+	}
+	return false, nil
+}
+
+// sellAtExit продает позицию целиком, если PnL pnlPercent пробил stop-loss или take-profit
+// либо цена price откатилась от максимума до trailing stop.
+// done = true, если мониторинг завершен продажей.
+func (mw *MonitorWorker) sellAtExit(ctx context.Context, pnlPercent, price float64) (done bool, err error) {
+	trailStop := mw.exits.TrailStop()
+	kind, hit := mw.exits.Check(pnlPercent, price)
+	if !hit {
+		return false, nil
+	}
+	detail := fmt.Sprintf("%.2f%% PnL", pnlPercent)
+	label := "🛑 Stop-loss"
+	switch kind {
+	case monitor.ExitTakeProfit:
+		label = "💰 Take-profit"
+	case monitor.ExitTrailingStop:
+		label = "📉 Trailing stop"
+		detail = fmt.Sprintf("%.10f SOL, stop %.10f", price, trailStop)
+	}
+	mw.logger.Info(fmt.Sprintf("%s hit at %s (%s), selling the position", label, detail, mw.exits))
+	mw.history.event(string(kind), detail)
+
+	mw.Stop()
+	if err := mw.sellFn(ctx, 100); err != nil {
+		mw.logger.Error("❌ Exit sell failed: " + err.Error())
+		return true, err
+	}
+	mw.history.finish(execution.OutcomeSold, string(kind))
+	return true, nil
+}
+
+// setExitRule применяет команду оператора "sl N", "tp N" или "ts N".
+func (mw *MonitorWorker) setExitRule(command string) {
+	var name string
+	var percent float64
+	if _, err := fmt.Sscanf(command, "%s %g", &name, &percent); err != nil {
+		fmt.Println("Usage: 'sl N', 'tp N' or 'ts N' with N in percent, 0 turns the rule off")
+		return
+	}
+	kind := monitor.ExitStopLoss
+	switch name {
+	case "tp":
+		kind = monitor.ExitTakeProfit
+	case "ts":
+		kind = monitor.ExitTrailingStop
+	}
+	if err := mw.exits.Set(kind, percent); err != nil {
+		fmt.Println("Invalid exit rule: " + err.Error())
+		return
+	}
+	mw.logger.Info(fmt.Sprintf("🎚️  Exit rules updated: %s", mw.exitsLabel()))
+	mw.history.event("exit_rules", mw.exitsLabel())
+}
+
+// exitsFrameLine описывает правила выхода для экрана позиции вместе с ценой trailing stop
+func (mw *MonitorWorker) exitsFrameLine() string {
+	line := mw.exits.String()
+	if stop := mw.exits.TrailStop(); stop > 0 {
+		line += fmt.Sprintf(" @%.8f", stop)
+	}
+	if left := mw.exits.WarmUpLeft(); left > 0 {
+		line += fmt.Sprintf(" · SL warming up %ds", int(left.Round(time.Second).Seconds()))
+	}
+	if plan := mw.plan.String(); plan != "" {
+		if line != "" {
+			line += " · "
+		}
+		line += "Plan " + plan
+	}
+	return line
+}
+
+func (mw *MonitorWorker) exitsLabel() string {
+	if rules := mw.exits.String(); rules != "" {
+		return rules
+	}
+	return "off"
+}
+
+// latestPriceUpdate вычитывает накопившиеся обновления цены и возвращает последнее
+func (mw *MonitorWorker) latestPriceUpdate(update monitor.PriceUpdate) monitor.PriceUpdate {
+	for {
+		select {
+		case next, ok := <-mw.session.PriceUpdates():
+			if !ok {
+				return update
+			}
+			update = next
+		default:
+			return update
 		}
 	}
 }
@@ -215,10 +610,32 @@ func (mw *MonitorWorker) calculatePnL(ctx context.Context, update monitor.PriceU
 		return nil, fmt.Errorf("failed to get calculator for DEX: %w", err)
 	}
 
-	pnlData, err := calculator.CalculatePnL(ctx, update.Tokens, mw.task.AmountSol)
+	pnlData, err := calculator.CalculatePnL(ctx, update.Tokens, mw.invested())
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate PnL: %w", err)
 	}
 
 	return pnlData, nil
 }
+
+// invested возвращает вложения позиции с учетом слитых покупок
+func (mw *MonitorWorker) invested() float64 {
+	if mw.pos == nil {
+		return mw.task.AmountSol
+	}
+	return mw.pos.Invested()
+}
+
+// withEntryPrice подменяет начальную цену средневзвешенной ценой входа слитой позиции
+func (mw *MonitorWorker) withEntryPrice(update monitor.PriceUpdate) monitor.PriceUpdate {
+	if mw.pos == nil || !mw.pos.Merged() {
+		return update
+	}
+	entry := mw.pos.EntryPrice()
+	if entry <= 0 {
+		return update
+	}
+	update.Initial = entry
+	update.Percent = (update.Current - entry) / entry * 100
+	return update
+}
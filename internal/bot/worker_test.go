@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/notify"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// namedDEX — адаптер, от которого трассировке нужно только имя.
+type namedDEX struct {
+	dex.DEX
+}
+
+func (namedDEX) GetName() string { return "Pump.fun" }
+
+func TestStartTrace_RecordsRPC(t *testing.T) {
+	// Основной эндпоинт недоступен, транзакцию принимает резервный
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"sig"}`))
+	}))
+	defer up.Close()
+
+	wp := &WorkerPool{logger: zap.NewNop(), config: &task.Config{
+		RPCList:      []string{down.URL},
+		RPCEndpoints: map[string]string{"fast": "https://fast.rpc.test/?api-key=secret"},
+	}}
+
+	_, tr := wp.startTrace(context.Background(), &task.Task{TaskName: "snipe", RPC: "fast"}, namedDEX{}, "buy")
+	assert.Equal(t, "fast.rpc.test", tr.Record().RPC, "a task endpoint is credited to itself")
+
+	ctx, tr := wp.startTrace(context.Background(), &task.Task{TaskName: "snipe"}, namedDEX{}, "buy")
+	assert.Equal(t, rpcLabel(down.URL), tr.Record().RPC)
+
+	pool := blockchain.NewPool([]string{down.URL, up.URL}, blockchain.PoolOptions{}, zap.NewNop())
+	var sig string
+	require.NoError(t, pool.CallForInto(ctx, &sig, "sendTransaction", nil))
+	assert.Equal(t, rpcLabel(up.URL), tr.Record().RPC, "a failover is credited to the endpoint that took the transaction")
+}
+
+// alertSink копит доставленные алерты.
+type alertSink struct {
+	mu     sync.Mutex
+	alerts []notify.Alert
+}
+
+func (s *alertSink) Name() string { return "test" }
+
+func (s *alertSink) Send(_ context.Context, alert notify.Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+func (s *alertSink) received() []notify.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]notify.Alert(nil), s.alerts...)
+}
+
+func TestCheckLatencyBudget(t *testing.T) {
+	for name, tc := range map[string]struct {
+		side   string
+		budget time.Duration
+		sentIn time.Duration // Через сколько после команды отправлена транзакция
+		sent   bool
+		alert  bool
+	}{
+		"buy over budget":   {side: execution.SideBuy, budget: 500 * time.Millisecond, sentIn: 2 * time.Second, sent: true, alert: true},
+		"buy within budget": {side: execution.SideBuy, budget: time.Minute, sentIn: 2 * time.Second, sent: true},
+		"sell is not timed": {side: execution.SideSell, budget: 500 * time.Millisecond, sentIn: 2 * time.Second, sent: true},
+		"budget off":        {side: execution.SideBuy, sentIn: 2 * time.Second, sent: true},
+		"never sent":        {side: execution.SideBuy, budget: 500 * time.Millisecond},
+	} {
+		sink := &alertSink{}
+		wp := &WorkerPool{
+			config:   &task.Config{LatencyBudget: tc.budget},
+			notifier: notify.New(zap.NewNop(), notify.Options{}, sink),
+		}
+		tr := execution.NewTrace(execution.Record{Side: tc.side, StartedAt: time.Now().Add(-tc.sentIn)})
+		if tc.sent {
+			tr.MarkSent(solana.Signature{1})
+		}
+
+		wp.checkLatencyBudget(&task.Task{TaskName: "snipe", TokenMint: "MintA"}, tr, zap.NewNop())
+		if !tc.alert {
+			time.Sleep(50 * time.Millisecond)
+			assert.Empty(t, sink.received(), name)
+			continue
+		}
+		require.Eventually(t, func() bool { return len(sink.received()) == 1 }, time.Second, 5*time.Millisecond, name)
+		alert := sink.received()[0]
+		assert.Equal(t, notify.AlertLatencyBudget, alert.Type, name)
+		assert.Equal(t, "snipe", alert.Key, name)
+		assert.Equal(t, notify.SeverityWarning, alert.Severity, name)
+		assert.True(t, strings.HasPrefix(alert.Message, "Latency budget exceeded for snipe (MintA): sent after 2"), alert.Message)
+		assert.Contains(t, alert.Message, ", budget 500ms (", name)
+	}
+}
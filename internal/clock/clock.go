@@ -0,0 +1,46 @@
+// internal/clock/clock.go
+package clock
+
+import "time"
+
+// Clock — источник времени для логики, завязанной на часы: тикеры мониторинга,
+// окна ожидания, сроки удержания. В боевом коде используется Real, в тестах — Fake.
+type Clock interface {
+	// Now возвращает текущее время.
+	Now() time.Time
+	// Since возвращает время, прошедшее с t.
+	Since(t time.Time) time.Duration
+	// After возвращает канал, в который придет время через d.
+	After(d time.Duration) <-chan time.Time
+	// NewTicker создает тикер с периодом d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker — периодический источник тиков, аналог time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real — системные часы.
+var Real Clock = realClock{}
+
+// Or возвращает c или Real, если c не задан.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ t *time.Ticker }
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
@@ -0,0 +1,132 @@
+// internal/clock/fake.go
+package clock
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Fake — управляемые часы для детерминированных тестов: время стоит на месте,
+// пока его не сдвинут через Advance, и тогда срабатывают наступившие таймеры и тикеры.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter — таймер After (period = 0) или тикер.
+type fakeWaiter struct {
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+}
+
+// NewFake создает часы, показывающие start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now возвращает текущее время часов.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since возвращает время, прошедшее с t по часам.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After возвращает канал, который сработает, когда часы сдвинут на d.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.add(d, 0).ch
+}
+
+// NewTicker создает тикер, срабатывающий на каждые d сдвига часов.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return &fakeTicker{clock: f, w: f.add(d, d)}
+}
+
+func (f *Fake) add(d, period time.Duration) *fakeWaiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{at: f.now.Add(d), period: period, ch: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return w
+}
+
+func (f *Fake) remove(w *fakeWaiter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, other := range f.waiters {
+		if other == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// Advance сдвигает часы на d и по порядку срабатывает все наступившие таймеры и тикеры.
+// Как и у time.Ticker, непрочитанный тик не копится: лишние тики отбрасываются.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	target := f.now.Add(d)
+	for {
+		var next *fakeWaiter
+		for _, w := range f.waiters {
+			if !w.at.After(target) && (next == nil || w.at.Before(next.at)) {
+				next = w
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		f.now = next.at
+		select {
+		case next.ch <- f.now:
+		default:
+		}
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+			continue
+		}
+		for i, w := range f.waiters {
+			if w == next {
+				f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+				break
+			}
+		}
+	}
+	f.now = target
+}
+
+// Waiters возвращает число активных таймеров и тикеров.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil ждет, пока тестируемый код не заведет n таймеров и тикеров,
+// чтобы Advance не опередил их создание.
+func (f *Fake) BlockUntil(n int) {
+	for f.Waiters() < n {
+		runtime.Gosched()
+	}
+}
+
+type fakeTicker struct {
+	clock *Fake
+	w     *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.ch }
+func (t *fakeTicker) Stop()               { t.clock.remove(t.w) }
@@ -0,0 +1,54 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func received(ch <-chan time.Time) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFake_After(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	ch := c.After(time.Minute)
+	assert.Equal(t, 1, c.Waiters())
+
+	c.Advance(59 * time.Second)
+	assert.False(t, received(ch))
+
+	c.Advance(time.Second)
+	assert.True(t, received(ch))
+	assert.Equal(t, start.Add(time.Minute), c.Now())
+	assert.Equal(t, time.Minute, c.Since(start))
+	assert.Zero(t, c.Waiters())
+}
+
+func TestFake_Ticker(t *testing.T) {
+	c := NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	ticker := c.NewTicker(time.Second)
+
+	c.Advance(500 * time.Millisecond)
+	assert.False(t, received(ticker.C()))
+
+	// Непрочитанные тики не копятся, как у time.Ticker
+	c.Advance(3 * time.Second)
+	assert.True(t, received(ticker.C()))
+	assert.False(t, received(ticker.C()))
+
+	c.Advance(time.Second)
+	assert.True(t, received(ticker.C()))
+
+	ticker.Stop()
+	c.Advance(time.Hour)
+	assert.False(t, received(ticker.C()))
+	assert.Zero(t, c.Waiters())
+}
@@ -5,6 +5,7 @@ package dex
 import (
 	"context"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"sync"
 
@@ -50,3 +51,8 @@ func (b *baseDEXAdapter) init(ctx context.Context, tokenMint string, initFn func
 func (b *baseDEXAdapter) GetName() string {
 	return b.name
 }
+
+// GetTokenMetadata ищет метаданные токена в Metaplex и API Pump.fun через общий кеш адаптеров.
+func (b *baseDEXAdapter) GetTokenMetadata(ctx context.Context, tokenMint string) (*model.TokenMetadata, error) {
+	return tokenMetadata.Resolve(ctx, b.client, tokenMint)
+}
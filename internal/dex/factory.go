@@ -6,6 +6,7 @@ package dex
 import (
 	"fmt"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/sim"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"go.uber.org/zap"
 	"strings"
@@ -45,6 +46,16 @@ func GetDEXByName(name string, client *blockchain.Client, w *task.Wallet, logger
 			},
 		}, nil
 
+	case "jupiter":
+		return &jupiterDEXAdapter{
+			baseDEXAdapter: baseDEXAdapter{
+				client: client,
+				wallet: w,
+				logger: logger.Named("jupiter"),
+				name:   "Jupiter",
+			},
+		}, nil
+
 	case "snipe":
 		return &smartDEXAdapter{
 			baseDEXAdapter: baseDEXAdapter{
@@ -56,6 +67,29 @@ func GetDEXByName(name string, client *blockchain.Client, w *task.Wallet, logger
 		}, nil
 
 	default:
+		// "sim" или "sim:<path>" — симулируемая площадка со сценарием цены pump, dump или chop
+		if IsSimulated(name) {
+			path, err := sim.ParsePath(strings.TrimPrefix(strings.TrimPrefix(name, "sim"), ":"))
+			if err != nil {
+				return nil, err
+			}
+			return &simDEXAdapter{
+				baseDEXAdapter: baseDEXAdapter{
+					client: client,
+					wallet: w,
+					logger: logger.Named("sim_dex"),
+					name:   "Sim DEX",
+				},
+				path: path,
+			}, nil
+		}
 		return nil, fmt.Errorf("exchange %s is not supported", name)
 	}
 }
+
+// IsSimulated сообщает, что модуль задачи — симулируемая площадка ("sim" или
+// "sim:<path>"), сделки которой не уходят в сеть.
+func IsSimulated(module string) bool {
+	rest, ok := strings.CutPrefix(strings.ToLower(strings.TrimSpace(module)), "sim")
+	return ok && (rest == "" || rest[0] == ':')
+}
@@ -0,0 +1,191 @@
+// internal/dex/jupiter/api.go
+package jupiter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// DefaultAPIURL — публичный Swap API агрегатора Jupiter.
+const DefaultAPIURL = "https://lite-api.jup.ag/swap/v1"
+
+var (
+	// JupiterProgramID — программа маршрутизации Jupiter v6.
+	JupiterProgramID = solana.MustPublicKeyFromBase58("JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4")
+	// SOLMint — wrapped SOL; Jupiter сам оборачивает и разворачивает SOL кошелька.
+	SOLMint = solana.SolMint
+)
+
+var (
+	apiMu  sync.RWMutex
+	apiURL = DefaultAPIURL
+)
+
+// UseAPI задает адрес Swap API (jupiter_api_url), например собственный инстанс
+// или платный эндпоинт; "" возвращает публичный API.
+func UseAPI(baseURL string) error {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	if u, err := url.Parse(baseURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("jupiter_api_url must be an http(s) URL")
+	}
+	apiMu.Lock()
+	apiURL = baseURL
+	apiMu.Unlock()
+	return nil
+}
+
+func currentAPIURL() string {
+	apiMu.RLock()
+	defer apiMu.RUnlock()
+	return apiURL
+}
+
+// Quote — котировка маршрута. Raw передается в /swap без изменений: Jupiter
+// строит транзакцию ровно по тому маршруту, который вернул.
+type Quote struct {
+	InAmount       uint64
+	OutAmount      uint64
+	MinOutAmount   uint64  // Выход с учетом slippage: меньше — транзакция откатится
+	PriceImpactPct float64 // Влияние сделки на цену, %
+	Venues         []string
+	Raw            json.RawMessage
+}
+
+// API — клиент Swap API Jupiter.
+type API struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewAPI создает клиент для baseURL.
+func NewAPI(baseURL string) *API {
+	return &API{baseURL: baseURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Quote запрашивает лучший маршрут обмена amount единиц inputMint на outputMint.
+func (a *API) Quote(ctx context.Context, inputMint, outputMint solana.PublicKey, amount uint64, slippagePercent float64) (*Quote, error) {
+	q := url.Values{}
+	q.Set("inputMint", inputMint.String())
+	q.Set("outputMint", outputMint.String())
+	q.Set("amount", strconv.FormatUint(amount, 10))
+	q.Set("slippageBps", strconv.Itoa(int(slippagePercent*100)))
+
+	var raw json.RawMessage
+	if err := a.call(ctx, http.MethodGet, "/quote?"+q.Encode(), nil, &raw); err != nil {
+		return nil, err
+	}
+	return parseQuote(raw)
+}
+
+// parseQuote разбирает ответ /quote, сохраняя исходный JSON.
+func parseQuote(raw json.RawMessage) (*Quote, error) {
+	var resp struct {
+		InAmount             string `json:"inAmount"`
+		OutAmount            string `json:"outAmount"`
+		OtherAmountThreshold string `json:"otherAmountThreshold"`
+		PriceImpactPct       string `json:"priceImpactPct"`
+		RoutePlan            []struct {
+			SwapInfo struct {
+				Label string `json:"label"`
+			} `json:"swapInfo"`
+		} `json:"routePlan"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("decode jupiter quote: %w", err)
+	}
+
+	quote := &Quote{Raw: raw}
+	var err error
+	if quote.InAmount, err = strconv.ParseUint(resp.InAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid quote inAmount %q", resp.InAmount)
+	}
+	if quote.OutAmount, err = strconv.ParseUint(resp.OutAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid quote outAmount %q", resp.OutAmount)
+	}
+	quote.MinOutAmount, _ = strconv.ParseUint(resp.OtherAmountThreshold, 10, 64)
+	if impact, err := strconv.ParseFloat(resp.PriceImpactPct, 64); err == nil {
+		quote.PriceImpactPct = impact * 100
+	}
+	for _, step := range resp.RoutePlan {
+		quote.Venues = append(quote.Venues, step.SwapInfo.Label)
+	}
+	return quote, nil
+}
+
+// SwapTransaction получает у Jupiter неподписанную транзакцию по котировке quote
+// для кошелька user с ценой вычислительной единицы microLamports.
+func (a *API) SwapTransaction(ctx context.Context, quote *Quote, user solana.PublicKey, microLamports uint64) (*solana.Transaction, error) {
+	req := map[string]interface{}{
+		"quoteResponse":                 quote.Raw,
+		"userPublicKey":                 user.String(),
+		"wrapAndUnwrapSol":              true,
+		"dynamicComputeUnitLimit":       true,
+		"computeUnitPriceMicroLamports": microLamports,
+	}
+	var resp struct {
+		SwapTransaction string `json:"swapTransaction"`
+	}
+	if err := a.call(ctx, http.MethodPost, "/swap", req, &resp); err != nil {
+		return nil, err
+	}
+	tx, err := solana.TransactionFromBase64(resp.SwapTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("decode jupiter swap transaction: %w", err)
+	}
+	return tx, nil
+}
+
+func (a *API) call(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshal jupiter request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("create jupiter request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call jupiter: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		// Jupiter объясняет отказ (нет маршрута, слишком мелкая сумма) в поле error
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error != "" {
+			return fmt.Errorf("jupiter returned status %d: %s", resp.StatusCode, apiErr.Error)
+		}
+		return fmt.Errorf("jupiter returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode jupiter response: %w", err)
+	}
+	return nil
+}
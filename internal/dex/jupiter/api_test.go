@@ -0,0 +1,73 @@
+package jupiter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const quoteJSON = `{"inAmount":"100000000","outAmount":"2500000","otherAmountThreshold":"2375000",
+"priceImpactPct":"0.0123","routePlan":[{"swapInfo":{"label":"Pump.fun Amm"}},{"swapInfo":{"label":"Raydium"}}]}`
+
+func TestAPI_QuoteAndSwap(t *testing.T) {
+	user := solana.NewWallet().PublicKey()
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(1, user, solana.NewWallet().PublicKey()).Build()},
+		solana.Hash{}, solana.TransactionPayer(user))
+	require.NoError(t, err)
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/quote":
+			assert.Equal(t, "100000000", r.URL.Query().Get("amount"))
+			assert.Equal(t, "500", r.URL.Query().Get("slippageBps"))
+			_, _ = w.Write([]byte(quoteJSON))
+		case "/swap":
+			var req map[string]json.RawMessage
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.JSONEq(t, quoteJSON, string(req["quoteResponse"]), "the quote goes back unchanged")
+			assert.Equal(t, `"`+user.String()+`"`, string(req["userPublicKey"]))
+			_ = json.NewEncoder(w).Encode(map[string]string{"swapTransaction": base64.StdEncoding.EncodeToString(raw)})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"Could not find any route"}`))
+		}
+	}))
+	defer srv.Close()
+
+	api := NewAPI(srv.URL)
+	quote, err := api.Quote(context.Background(), SOLMint, solana.NewWallet().PublicKey(), 100_000_000, 5)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2_500_000), quote.OutAmount)
+	assert.Equal(t, uint64(2_375_000), quote.MinOutAmount)
+	assert.InDelta(t, 1.23, quote.PriceImpactPct, 1e-9)
+	assert.Equal(t, []string{"Pump.fun Amm", "Raydium"}, quote.Venues)
+
+	swapTx, err := api.SwapTransaction(context.Background(), quote, user, 5_000)
+	require.NoError(t, err)
+	assert.True(t, swapTx.Message.AccountKeys[0].Equals(user))
+
+	api.baseURL = srv.URL + "/missing"
+	_, err = api.Quote(context.Background(), SOLMint, user, 1, 1)
+	assert.EqualError(t, err, "jupiter returned status 400: Could not find any route")
+}
+
+func TestUseAPI(t *testing.T) {
+	defer func() { _ = UseAPI("") }()
+
+	assert.NoError(t, UseAPI("https://quote.example.com/swap/v1/"))
+	assert.Equal(t, "https://quote.example.com/swap/v1", currentAPIURL())
+	assert.Error(t, UseAPI("quote.example.com"))
+	assert.NoError(t, UseAPI(""))
+	assert.Equal(t, DefaultAPIURL, currentAPIURL())
+}
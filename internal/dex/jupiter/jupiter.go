@@ -0,0 +1,264 @@
+// internal/dex/jupiter/jupiter.go
+package jupiter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+)
+
+// defaultPriorityFee — цена вычислительной единицы для priority fee "default", micro-lamports.
+const defaultPriorityFee = 5_000
+
+// DEX — торговля через агрегатор Jupiter: маршрут выбирает Jupiter среди всех AMM,
+// где торгуется токен, а транзакцию подписывает кошелек задачи и отправляет
+// blockchain.Client. Подходит для токенов, которые ушли с Pump.fun на несколько площадок.
+type DEX struct {
+	client *blockchain.Client
+	wallet *task.Wallet
+	logger *zap.Logger
+	api    *API
+
+	mu       sync.Mutex
+	decimals map[solana.PublicKey]uint8
+	programs map[solana.PublicKey]solana.PublicKey // Программа токена по mint
+}
+
+// NewDEX создает DEX поверх Swap API, заданного UseAPI.
+func NewDEX(client *blockchain.Client, w *task.Wallet, logger *zap.Logger) *DEX {
+	return &DEX{
+		client:   client,
+		wallet:   w,
+		logger:   logger,
+		api:      NewAPI(currentAPIURL()),
+		decimals: make(map[solana.PublicKey]uint8),
+		programs: make(map[solana.PublicKey]solana.PublicKey),
+	}
+}
+
+// Buy покупает токен mint на lamports SOL.
+func (d *DEX) Buy(ctx context.Context, mint solana.PublicKey, lamports uint64, slippagePercent float64, priorityFeeSol string) error {
+	return d.swap(ctx, SOLMint, mint, lamports, slippagePercent, priorityFeeSol)
+}
+
+// SellPercentTokens продает процент баланса токена за SOL.
+func (d *DEX) SellPercentTokens(ctx context.Context, tokenMint string, percentToSell, slippagePercent float64, priorityFeeSol string) error {
+	if percentToSell <= 0 || percentToSell > 100 {
+		return fmt.Errorf("percent to sell must be between 0 and 100")
+	}
+	mint, err := solana.PublicKeyFromBase58(tokenMint)
+	if err != nil {
+		return fmt.Errorf("invalid token mint: %w", err)
+	}
+	balance, err := d.GetTokenBalance(ctx, tokenMint)
+	if err != nil {
+		return err
+	}
+	amount := model.PercentOf(balance, percentToSell)
+	if amount == 0 {
+		return fmt.Errorf("no tokens to sell")
+	}
+	return d.swap(ctx, mint, SOLMint, amount, slippagePercent, priorityFeeSol)
+}
+
+// SellTokenAmount продает ровно amount базовых единиц токена за SOL.
+func (d *DEX) SellTokenAmount(ctx context.Context, tokenMint string, amount uint64, slippagePercent float64, priorityFeeSol string) error {
+	if amount == 0 {
+		return fmt.Errorf("no tokens to sell")
+	}
+	mint, err := solana.PublicKeyFromBase58(tokenMint)
+	if err != nil {
+		return fmt.Errorf("invalid token mint: %w", err)
+	}
+	return d.swap(ctx, mint, SOLMint, amount, slippagePercent, priorityFeeSol)
+}
+
+// swap котирует обмен, получает транзакцию маршрута, подписывает, отправляет и ждет подтверждения.
+func (d *DEX) swap(ctx context.Context, inputMint, outputMint solana.PublicKey, amount uint64, slippagePercent float64, priorityFeeSol string) error {
+	trace := execution.FromContext(ctx)
+
+	quote, err := d.api.Quote(ctx, inputMint, outputMint, amount, slippagePercent)
+	if err != nil {
+		return fmt.Errorf("jupiter quote: %w", err)
+	}
+	trace.SetQuote(quote.OutAmount)
+	trace.MarkStage(execution.StageQuote)
+	d.logger.Info(fmt.Sprintf("🪐 Jupiter route %s: %d → %d (min %d, impact %.2f%%)",
+		strings.Join(quote.Venues, " → "), quote.InAmount, quote.OutAmount, quote.MinOutAmount, quote.PriceImpactPct))
+
+	microLamports, err := d.priorityFee(ctx, priorityFeeSol)
+	if err != nil {
+		return err
+	}
+	// Лимит вычислительных единиц Jupiter подбирает по симуляции маршрута
+	trace.SetPriorityFee(microLamports, 0)
+
+	tx, err := d.api.SwapTransaction(ctx, quote, d.wallet.PublicKey, microLamports)
+	if err != nil {
+		return fmt.Errorf("jupiter swap: %w", err)
+	}
+	trace.MarkStage(execution.StageBlockhash)
+	if len(tx.Message.AccountKeys) == 0 || !tx.Message.AccountKeys[0].Equals(d.wallet.PublicKey) {
+		return fmt.Errorf("jupiter swap transaction is not paid by wallet %s", d.wallet.PublicKey)
+	}
+	if err := d.wallet.SignTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	trace.MarkStage(execution.StageSigned)
+
+	sig, err := d.client.SendTransaction(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("send transaction: %w", err)
+	}
+	d.logger.Info("📤 Transaction sent: " + sig.String()[:8] + "...")
+	trace.MarkSent(sig)
+
+	if err := d.client.WaitForTransactionConfirmation(ctx, sig, rpc.CommitmentProcessed); err != nil {
+		return fmt.Errorf("transaction %s: %w", sig, err)
+	}
+	d.logger.Info("✅ Transaction confirmed: " + sig.String()[:8] + "...")
+	trace.MarkConfirmed()
+	return nil
+}
+
+// priorityFee переводит priority_fee задачи в цену вычислительной единицы, как у Pump.swap:
+// "default", "auto" или "auto:pN" (оценка blockchain.Client) или сумма в SOL.
+func (d *DEX) priorityFee(ctx context.Context, priorityFeeSol string) (uint64, error) {
+	switch {
+	case priorityFeeSol == "default" || priorityFeeSol == "":
+		return defaultPriorityFee, nil
+	case blockchain.IsAutoFee(priorityFeeSol):
+		fee, err := d.client.PriorityFee(ctx, priorityFeeSol, []solana.PublicKey{JupiterProgramID})
+		if err != nil {
+			d.logger.Warn(fmt.Sprintf("Priority fee estimate failed, using default: %v", err))
+			return defaultPriorityFee, nil
+		}
+		return fee, nil
+	default:
+		var solValue float64
+		if _, err := fmt.Sscanf(priorityFeeSol, "%f", &solValue); err != nil {
+			return 0, fmt.Errorf("invalid priority fee format: %w", err)
+		}
+		return uint64(solValue * 1_000_000_000_000), nil // SOL to micro-lamports (1e12)
+	}
+}
+
+// GetTokenPrice возвращает цену токена в SOL по котировке продажи одного токена.
+func (d *DEX) GetTokenPrice(ctx context.Context, tokenMint string) (float64, error) {
+	mint, err := solana.PublicKeyFromBase58(tokenMint)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token mint: %w", err)
+	}
+	decimals, err := d.tokenDecimals(ctx, mint)
+	if err != nil {
+		return 0, err
+	}
+	quote, err := d.api.Quote(ctx, mint, SOLMint, uint64(math.Pow10(int(decimals))), 1)
+	if err != nil {
+		return 0, fmt.Errorf("jupiter quote: %w", err)
+	}
+	return float64(quote.OutAmount) / 1e9, nil
+}
+
+// GetTokenBalance возвращает баланс токена в ATA кошелька в минимальных единицах.
+func (d *DEX) GetTokenBalance(ctx context.Context, tokenMint string) (uint64, error) {
+	mint, err := solana.PublicKeyFromBase58(tokenMint)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token mint: %w", err)
+	}
+	program, err := d.tokenProgram(ctx, mint)
+	if err != nil {
+		return 0, err
+	}
+	ata, err := blockchain.FindAssociatedTokenAddress(d.wallet.PublicKey, mint, program)
+	if err != nil {
+		return 0, fmt.Errorf("failed to derive associated token account: %w", err)
+	}
+	result, err := d.client.GetTokenAccountBalance(ctx, ata, rpc.CommitmentProcessed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token account balance: %w", err)
+	}
+	if result == nil || result.Value == nil {
+		return 0, nil
+	}
+	var amount uint64
+	if _, err := fmt.Sscan(result.Value.Amount, &amount); err != nil {
+		return 0, fmt.Errorf("invalid token balance %q: %w", result.Value.Amount, err)
+	}
+	return amount, nil
+}
+
+// CalculatePnL оценивает PnL по котировке продажи tokenAmount токенов через Jupiter:
+// оценка уже учитывает комиссии и влияние сделки на цену выбранного маршрута.
+func (d *DEX) CalculatePnL(ctx context.Context, tokenMint string, tokenAmount, initialInvestment float64) (*model.PnLResult, error) {
+	mint, err := solana.PublicKeyFromBase58(tokenMint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token mint: %w", err)
+	}
+	decimals, err := d.tokenDecimals(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.PnLResult{InitialInvestment: initialInvestment}
+	if raw := uint64(tokenAmount * math.Pow10(int(decimals))); raw > 0 {
+		quote, err := d.api.Quote(ctx, mint, SOLMint, raw, 1)
+		if err != nil {
+			return nil, fmt.Errorf("jupiter quote: %w", err)
+		}
+		res.SellEstimate = float64(quote.OutAmount) / 1e9
+	}
+	res.NetPnL = res.SellEstimate - initialInvestment
+	if initialInvestment > 0 {
+		res.PnLPercentage = res.NetPnL / initialInvestment * 100
+	}
+	return res, nil
+}
+
+// tokenDecimals возвращает decimals минта, запрашивая их один раз.
+func (d *DEX) tokenDecimals(ctx context.Context, mint solana.PublicKey) (uint8, error) {
+	d.mu.Lock()
+	decimals, ok := d.decimals[mint]
+	d.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+
+	decimals, err := d.client.GetMintDecimals(ctx, mint)
+	if err != nil {
+		return 0, fmt.Errorf("get mint decimals: %w", err)
+	}
+	d.mu.Lock()
+	d.decimals[mint] = decimals
+	d.mu.Unlock()
+	return decimals, nil
+}
+
+// tokenProgram возвращает программу токена mint (SPL Token или Token-2022), от нее зависит адрес ATA.
+func (d *DEX) tokenProgram(ctx context.Context, mint solana.PublicKey) (solana.PublicKey, error) {
+	d.mu.Lock()
+	program, ok := d.programs[mint]
+	d.mu.Unlock()
+	if ok {
+		return program, nil
+	}
+
+	info, err := d.client.GetTokenMint(ctx, mint)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("detect token program: %w", err)
+	}
+	d.mu.Lock()
+	d.programs[mint] = info.Program
+	d.mu.Unlock()
+	return info.Program, nil
+}
@@ -0,0 +1,104 @@
+// internal/dex/jupiter_adapter.go
+package dex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/jupiter"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// jupiterDEXAdapter адаптирует агрегатор Jupiter к нашему DEX-интерфейсу.
+type jupiterDEXAdapter struct {
+	baseDEXAdapter
+	inner *jupiter.DEX
+}
+
+// Execute покупает токен за SOL (swap) или продает весь баланс (sell) по лучшему маршруту Jupiter.
+func (d *jupiterDEXAdapter) Execute(ctx context.Context, t *task.Task) error {
+	if t.TokenMint == "" {
+		return fmt.Errorf("token mint is required for Jupiter")
+	}
+	mint, err := solana.PublicKeyFromBase58(t.TokenMint)
+	if err != nil {
+		return fmt.Errorf("invalid token mint: %w", err)
+	}
+	if err := d.init(ctx, t.TokenMint, d.makeInitJupiter()); err != nil {
+		return err
+	}
+
+	switch t.Operation {
+	case task.OperationSwap, task.OperationSnipe:
+		d.logger.Info(fmt.Sprintf("🪐 Jupiter: %.3f SOL for %s...%s",
+			t.AmountSol,
+			t.TokenMint[:4],
+			t.TokenMint[len(t.TokenMint)-4:]))
+		return d.inner.Buy(ctx, mint, model.SolToLamports(t.AmountSol), t.SlippagePercent, t.PriorityFeeSol)
+
+	case task.OperationSell:
+		d.logger.Info(fmt.Sprintf("🪐 Jupiter sell: %s...%s",
+			t.TokenMint[:4],
+			t.TokenMint[len(t.TokenMint)-4:]))
+		return d.inner.SellPercentTokens(ctx, t.TokenMint, 100, t.SlippagePercent, t.PriorityFeeSol)
+
+	default:
+		return fmt.Errorf("operation %s is not supported on Jupiter", t.Operation)
+	}
+}
+
+// GetTokenBalance возвращает баланс, предварительно инициализировав DEX.
+func (d *jupiterDEXAdapter) GetTokenBalance(ctx context.Context, tokenMint string) (uint64, error) {
+	if err := d.init(ctx, tokenMint, d.makeInitJupiter()); err != nil {
+		return 0, err
+	}
+	return d.inner.GetTokenBalance(ctx, tokenMint)
+}
+
+// SellPercentTokens продаёт процент токенов; лимит вычислительных единиц подбирает Jupiter.
+func (d *jupiterDEXAdapter) SellPercentTokens(ctx context.Context, tokenMint string, percentToSell, slippage float64, priorityFee string, _ uint32) error {
+	if err := d.init(ctx, tokenMint, d.makeInitJupiter()); err != nil {
+		return err
+	}
+	return d.inner.SellPercentTokens(ctx, tokenMint, percentToSell, slippage, priorityFee)
+}
+
+// SellTokenAmount продаёт точное количество токенов; лимит вычислительных единиц подбирает Jupiter.
+func (d *jupiterDEXAdapter) SellTokenAmount(ctx context.Context, tokenMint string, amount uint64, slippage float64, priorityFee string, _ uint32) error {
+	if err := d.init(ctx, tokenMint, d.makeInitJupiter()); err != nil {
+		return err
+	}
+	return d.inner.SellTokenAmount(ctx, tokenMint, amount, slippage, priorityFee)
+}
+
+// GetTokenPrice возвращает цену, предварительно инициализировав DEX.
+func (d *jupiterDEXAdapter) GetTokenPrice(ctx context.Context, tokenMint string) (float64, error) {
+	if err := d.init(ctx, tokenMint, d.makeInitJupiter()); err != nil {
+		return 0, err
+	}
+	return d.inner.GetTokenPrice(ctx, tokenMint)
+}
+
+// CalculatePnL рассчитывает PnL по котировке продажи через Jupiter.
+func (d *jupiterDEXAdapter) CalculatePnL(ctx context.Context, tokenAmount, initialInvestment float64) (*model.PnLResult, error) {
+	d.mu.Lock()
+	tokenMint := d.tokenMint
+	d.mu.Unlock()
+
+	if err := d.init(ctx, tokenMint, d.makeInitJupiter()); err != nil {
+		return nil, err
+	}
+	return d.inner.CalculatePnL(ctx, tokenMint, tokenAmount, initialInvestment)
+}
+
+// Вспомогательный метод для передачи initFn
+func (d *jupiterDEXAdapter) makeInitJupiter() func() error {
+	return func() error {
+		if d.inner == nil {
+			d.inner = jupiter.NewDEX(d.client, d.wallet, d.logger)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+// internal/dex/liquidity.go
+package dex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Liquidity — ликвидность, на которой сейчас торгуется токен.
+type Liquidity struct {
+	Venue    string  // Площадка: pump.fun или pump.swap
+	Sol      float64 // SOL на bonding curve или в пуле
+	LPSupply uint64  // Эмиссия LP-токенов пула (0 — у площадки нет LP)
+}
+
+// LiquiditySource — адаптер, который читает ликвидность токена в обход кешей цены:
+// по ней сторож rug-pull замечает вывод ликвидности.
+type LiquiditySource interface {
+	GetLiquidity(ctx context.Context, tokenMint string) (*Liquidity, error)
+}
+
+// GetLiquidity возвращает реальные резервы SOL bonding curve. У завершенной curve
+// ликвидность ушла в пул, и ошибка не дает принять миграцию за вывод ликвидности.
+func (d *pumpfunDEXAdapter) GetLiquidity(ctx context.Context, tokenMint string) (*Liquidity, error) {
+	if err := d.init(ctx, tokenMint, d.makeInitPumpFun(tokenMint)); err != nil {
+		return nil, err
+	}
+	lamports, complete, err := d.inner.SolReserves(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if complete {
+		return nil, fmt.Errorf("bonding curve of %s is complete", tokenMint)
+	}
+	return &Liquidity{Venue: VenuePumpFun, Sol: float64(lamports) / float64(solana.LAMPORTS_PER_SOL)}, nil
+}
+
+// GetLiquidity возвращает резервы SOL пула и эмиссию его LP-токенов.
+func (d *pumpswapDEXAdapter) GetLiquidity(ctx context.Context, tokenMint string) (*Liquidity, error) {
+	if err := d.init(ctx, tokenMint, d.makeInitPumpSwap(tokenMint)); err != nil {
+		return nil, err
+	}
+	lamports, lpSupply, err := d.inner.PoolLiquidity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Liquidity{Venue: VenuePumpSwap, Sol: float64(lamports) / float64(solana.LAMPORTS_PER_SOL), LPSupply: lpSupply}, nil
+}
+
+// GetLiquidity читает ликвидность на текущей площадке токена.
+func (d *smartDEXAdapter) GetLiquidity(ctx context.Context, tokenMint string) (*Liquidity, error) {
+	target, venue, err := d.route(ctx, tokenMint)
+	if err != nil {
+		return nil, err
+	}
+	src, ok := target.(LiquiditySource)
+	if !ok {
+		return nil, fmt.Errorf("liquidity is not available on %s", venue)
+	}
+	return src.GetLiquidity(ctx, tokenMint)
+}
@@ -0,0 +1,175 @@
+// internal/dex/metadata.go
+package dex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+)
+
+// pumpFunAPIURL — публичный API фронтенда Pump.fun с описанием токенов.
+const pumpFunAPIURL = "https://frontend-api-v3.pump.fun"
+
+// metadataRetryTTL — через сколько повторяется поиск метаданных, который не удался.
+const metadataRetryTTL = time.Minute
+
+// MetaplexSource читает аккаунт метаданных Metaplex (*blockchain.Client).
+type MetaplexSource interface {
+	GetMetaplexMetadata(ctx context.Context, mint solana.PublicKey) (*blockchain.MetaplexMetadata, error)
+}
+
+// MetadataResolver находит название, символ и картинку токена: сначала в аккаунте
+// метаданных Metaplex, затем в API Pump.fun. Найденные метаданные кешируются до
+// выхода — после запуска токена они не меняются, неудачный поиск — на metadataRetryTTL.
+type MetadataResolver struct {
+	apiURL string
+	client *http.Client
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]metadataEntry
+}
+
+// metadataEntry — результат поиска метаданных одного токена.
+type metadataEntry struct {
+	meta     *model.TokenMetadata
+	err      error
+	failedAt time.Time
+}
+
+// tokenMetadata — общий кеш всех адаптеров: задачи разных кошельков обычно торгуют одними токенами.
+var tokenMetadata = NewMetadataResolver()
+
+// NewMetadataResolver создает резолвер с пустым кешем.
+func NewMetadataResolver() *MetadataResolver {
+	return &MetadataResolver{
+		apiURL: pumpFunAPIURL,
+		client: &http.Client{Timeout: 5 * time.Second},
+		now:    time.Now,
+		cache:  make(map[string]metadataEntry),
+	}
+}
+
+// Resolve возвращает метаданные токена mint. Metaplex читается через chain;
+// nil — сразу спрашивается API Pump.fun.
+func (r *MetadataResolver) Resolve(ctx context.Context, chain MetaplexSource, mint string) (*model.TokenMetadata, error) {
+	r.mu.Lock()
+	entry, ok := r.cache[mint]
+	r.mu.Unlock()
+	if ok && (entry.meta != nil || r.now().Sub(entry.failedAt) < metadataRetryTTL) {
+		return entry.meta, entry.err
+	}
+
+	meta, err := r.lookup(ctx, chain, mint)
+	if err != nil && ctx.Err() != nil {
+		return nil, err // Отмена вызывающего — не повод откладывать следующий поиск
+	}
+	entry = metadataEntry{meta: meta, err: err}
+	if err != nil {
+		entry.failedAt = r.now()
+	}
+	r.mu.Lock()
+	r.cache[mint] = entry
+	r.mu.Unlock()
+	return meta, err
+}
+
+// Cached возвращает уже найденные метаданные токена, не обращаясь к сети (nil — еще не найдены).
+func (r *MetadataResolver) Cached(mint string) *model.TokenMetadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cache[mint].meta
+}
+
+// CachedTokenMetadata возвращает метаданные токена, которые уже нашел какой-либо адаптер.
+func CachedTokenMetadata(mint string) *model.TokenMetadata {
+	return tokenMetadata.Cached(mint)
+}
+
+func (r *MetadataResolver) lookup(ctx context.Context, chain MetaplexSource, mint string) (*model.TokenMetadata, error) {
+	key, err := solana.PublicKeyFromBase58(mint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token mint %q: %w", mint, err)
+	}
+
+	var metaplexErr error
+	if chain != nil {
+		onchain, err := chain.GetMetaplexMetadata(ctx, key)
+		if err == nil && (onchain.Name != "" || onchain.Symbol != "") {
+			return &model.TokenMetadata{
+				Name:   onchain.Name,
+				Symbol: onchain.Symbol,
+				Image:  r.offchainImage(ctx, onchain.URI),
+				Source: "metaplex",
+			}, nil
+		}
+		metaplexErr = err
+	}
+
+	meta, err := r.pumpFun(ctx, mint)
+	if err != nil {
+		if metaplexErr != nil {
+			return nil, fmt.Errorf("resolve metadata of %s: %v; %w", mint, metaplexErr, err)
+		}
+		return nil, fmt.Errorf("resolve metadata of %s: %w", mint, err)
+	}
+	return meta, nil
+}
+
+// pumpFun запрашивает описание токена у API Pump.fun.
+func (r *MetadataResolver) pumpFun(ctx context.Context, mint string) (*model.TokenMetadata, error) {
+	var coin struct {
+		Name     string `json:"name"`
+		Symbol   string `json:"symbol"`
+		ImageURI string `json:"image_uri"`
+	}
+	if err := r.getJSON(ctx, r.apiURL+"/coins/"+mint, &coin); err != nil {
+		return nil, fmt.Errorf("pump.fun api: %w", err)
+	}
+	if coin.Name == "" && coin.Symbol == "" {
+		return nil, fmt.Errorf("pump.fun api: token %s not found", mint)
+	}
+	return &model.TokenMetadata{Name: coin.Name, Symbol: coin.Symbol, Image: coin.ImageURI, Source: "pump.fun"}, nil
+}
+
+// offchainImage берет картинку из JSON по URI метаданных Metaplex. Картинка
+// необязательна, поэтому ошибки не возвращаются.
+func (r *MetadataResolver) offchainImage(ctx context.Context, uri string) string {
+	if !strings.HasPrefix(uri, "https://") && !strings.HasPrefix(uri, "http://") {
+		return ""
+	}
+	var doc struct {
+		Image string `json:"image"`
+	}
+	if err := r.getJSON(ctx, uri, &doc); err != nil {
+		return ""
+	}
+	return doc.Image
+}
+
+func (r *MetadataResolver) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package dex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+)
+
+type fakeMetaplex struct {
+	meta  map[solana.PublicKey]*blockchain.MetaplexMetadata
+	calls int
+}
+
+func (f *fakeMetaplex) GetMetaplexMetadata(_ context.Context, mint solana.PublicKey) (*blockchain.MetaplexMetadata, error) {
+	f.calls++
+	if m, ok := f.meta[mint]; ok {
+		return m, nil
+	}
+	return nil, errors.New("metadata account not found")
+}
+
+func TestMetadataResolver(t *testing.T) {
+	bonk := solana.NewWallet().PublicKey()
+	pump := solana.NewWallet().PublicKey()
+	missing := solana.NewWallet().PublicKey()
+
+	apiCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiCalls++
+		switch r.URL.Path {
+		case "/coins/" + pump.String():
+			_, _ = w.Write([]byte(`{"name":"Pump Cat","symbol":"PCAT","image_uri":"https://ipfs.io/ipfs/cat.png"}`))
+		case "/bonk.json":
+			_, _ = w.Write([]byte(`{"image":"https://example.com/bonk.png"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	chain := &fakeMetaplex{meta: map[solana.PublicKey]*blockchain.MetaplexMetadata{
+		bonk: {Name: "Bonk", Symbol: "BONK", URI: srv.URL + "/bonk.json"},
+	}}
+	now := time.Unix(1_700_000_000, 0)
+	r := NewMetadataResolver()
+	r.apiURL = srv.URL
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	meta, err := r.Resolve(ctx, chain, bonk.String())
+	require.NoError(t, err)
+	assert.Equal(t, &model.TokenMetadata{Name: "Bonk", Symbol: "BONK", Image: "https://example.com/bonk.png", Source: "metaplex"}, meta)
+
+	// Без аккаунта Metaplex метаданные берутся из API Pump.fun
+	meta, err = r.Resolve(ctx, chain, pump.String())
+	require.NoError(t, err)
+	assert.Equal(t, &model.TokenMetadata{Name: "Pump Cat", Symbol: "PCAT", Image: "https://ipfs.io/ipfs/cat.png", Source: "pump.fun"}, meta)
+
+	// Найденное берется из кеша
+	calls, api := chain.calls, apiCalls
+	meta, err = r.Resolve(ctx, chain, pump.String())
+	require.NoError(t, err)
+	assert.Equal(t, "PCAT", meta.Label())
+	assert.Equal(t, calls, chain.calls)
+	assert.Equal(t, api, apiCalls)
+	assert.Equal(t, "BONK", r.Cached(bonk.String()).Label())
+
+	// Неудача повторяется только через metadataRetryTTL
+	_, err = r.Resolve(ctx, chain, missing.String())
+	assert.Error(t, err)
+	api = apiCalls
+	_, err = r.Resolve(ctx, chain, missing.String())
+	assert.Error(t, err)
+	assert.Equal(t, api, apiCalls)
+	now = now.Add(metadataRetryTTL)
+	_, _ = r.Resolve(ctx, chain, missing.String())
+	assert.Equal(t, api+1, apiCalls)
+	assert.Nil(t, r.Cached(missing.String()))
+
+	_, err = r.Resolve(ctx, nil, "not-a-mint")
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+// internal/dex/migration.go
+package dex
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// migrationCheckTTL — сколько результат проверки площадки токена считается актуальным.
+const migrationCheckTTL = 15 * time.Second
+
+// Площадки, между которыми выбирает Smart DEX.
+const (
+	VenuePumpFun  = "pump.fun"  // Bonding curve еще активна
+	VenuePumpSwap = "pump.swap" // Curve завершена, токен торгуется в пуле PumpSwap
+	VenueJupiter  = "jupiter"   // Curve завершена, пула PumpSwap нет: маршрут ищет Jupiter
+)
+
+// MigrationEvent — токен ушел с bonding curve Pump.fun на другую площадку.
+type MigrationEvent struct {
+	Mint string
+	From string // Площадка до миграции
+	To   string // Площадка после миграции
+	At   time.Time
+}
+
+// MigrationSource — адаптер, который сообщает о миграции токена между площадками.
+type MigrationSource interface {
+	// OnMigration подписывает fn на события миграции.
+	OnMigration(fn func(MigrationEvent))
+}
+
+// migrationDetector определяет живую площадку токена по флагу завершения bonding
+// curve и наличию пула PumpSwap. Результат кешируется на ttl; миграция необратима,
+// поэтому найденный пул PumpSwap больше не перепроверяется.
+type migrationDetector struct {
+	curveComplete func(ctx context.Context) (bool, error)
+	poolExists    func(ctx context.Context) (bool, error)
+	ttl           time.Duration
+	now           func() time.Time
+
+	mu        sync.Mutex
+	venue     string
+	checkedAt time.Time
+}
+
+func newMigrationDetector(curveComplete, poolExists func(ctx context.Context) (bool, error)) *migrationDetector {
+	return &migrationDetector{
+		curveComplete: curveComplete,
+		poolExists:    poolExists,
+		ttl:           migrationCheckTTL,
+		now:           time.Now,
+	}
+}
+
+// Venue возвращает площадку токена, проверяя ее не чаще раза в ttl. Если проверка
+// не удалась, возвращается последняя известная площадка.
+func (m *migrationDetector) Venue(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.venue == VenuePumpSwap || (m.venue != "" && m.now().Sub(m.checkedAt) < m.ttl) {
+		return m.venue, nil
+	}
+
+	venue, err := m.check(ctx)
+	if err != nil {
+		if m.venue != "" {
+			return m.venue, nil
+		}
+		return "", err
+	}
+	m.venue, m.checkedAt = venue, m.now()
+	return venue, nil
+}
+
+// Invalidate заставляет следующий Venue проверить площадку заново, например
+// после ошибки BondingCurveComplete.
+func (m *migrationDetector) Invalidate() {
+	m.mu.Lock()
+	m.checkedAt = time.Time{}
+	m.mu.Unlock()
+}
+
+func (m *migrationDetector) check(ctx context.Context) (string, error) {
+	complete, err := m.curveComplete(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !complete {
+		return VenuePumpFun, nil
+	}
+	exists, err := m.poolExists(ctx)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return VenuePumpSwap, nil
+	}
+	return VenueJupiter, nil
+}
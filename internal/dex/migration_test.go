@@ -0,0 +1,63 @@
+package dex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationDetector_Venue(t *testing.T) {
+	complete, pool := false, false
+	var curveErr error
+	curveChecks := 0
+	m := newMigrationDetector(
+		func(ctx context.Context) (bool, error) { curveChecks++; return complete, curveErr },
+		func(ctx context.Context) (bool, error) { return pool, nil },
+	)
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	venue, err := m.Venue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, VenuePumpFun, venue)
+
+	// В пределах ttl результат берется из кеша
+	complete = true
+	venue, _ = m.Venue(ctx)
+	assert.Equal(t, VenuePumpFun, venue)
+	assert.Equal(t, 1, curveChecks)
+
+	// Curve завершена, пула еще нет — маршрут через Jupiter
+	now = now.Add(migrationCheckTTL)
+	venue, _ = m.Venue(ctx)
+	assert.Equal(t, VenueJupiter, venue)
+
+	// Invalidate перепроверяет сразу; найденный пул больше не проверяется
+	pool = true
+	m.Invalidate()
+	venue, _ = m.Venue(ctx)
+	assert.Equal(t, VenuePumpSwap, venue)
+	now = now.Add(time.Hour)
+	venue, _ = m.Venue(ctx)
+	assert.Equal(t, VenuePumpSwap, venue)
+	assert.Equal(t, 3, curveChecks)
+}
+
+func TestMigrationDetector_CheckFailure(t *testing.T) {
+	curveErr := errors.New("rpc down")
+	m := newMigrationDetector(
+		func(ctx context.Context) (bool, error) { return false, curveErr },
+		func(ctx context.Context) (bool, error) { return false, nil },
+	)
+	_, err := m.Venue(context.Background())
+	assert.ErrorIs(t, err, curveErr, "no known venue yet")
+
+	m.venue = VenuePumpFun
+	venue, err := m.Venue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, VenuePumpFun, venue, "a failed recheck keeps the last known venue")
+}
@@ -0,0 +1,56 @@
+// Package model internal/model/amount.go
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// percentScale — precision of PercentOf: percent is applied in billionths of the amount.
+const percentScale = 1_000_000_000
+
+// SolToLamports converts a SOL amount to lamports, rounding to the nearest lamport
+// so values like 0.29 SOL do not lose a lamport to float truncation.
+func SolToLamports(sol float64) uint64 {
+	if sol <= 0 || math.IsNaN(sol) {
+		return 0
+	}
+	return uint64(math.Round(sol * 1e9))
+}
+
+// TokensToBaseUnits converts a token amount in UI units to base units with decimal math:
+// the amount is taken in its shortest decimal form (as written in the task) and digits
+// past decimals are dropped, so 0.3 tokens with 6 decimals are exactly 300000 units.
+func TokensToBaseUnits(amount float64, decimals uint8) (uint64, error) {
+	if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("invalid token amount %v", amount)
+	}
+	whole, frac, _ := strings.Cut(strconv.FormatFloat(amount, 'f', -1, 64), ".")
+	if len(frac) > int(decimals) {
+		frac = frac[:decimals]
+	}
+	frac += strings.Repeat("0", int(decimals)-len(frac))
+	units, err := strconv.ParseUint(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("token amount %v with %d decimals is out of range", amount, decimals)
+	}
+	return units, nil
+}
+
+// PercentOf returns percent of amount in base units, rounded down so it never
+// exceeds amount. 100% (or more) returns amount itself, leaving no dust behind.
+func PercentOf(amount uint64, percent float64) uint64 {
+	switch {
+	case percent >= 100:
+		return amount
+	case percent <= 0 || math.IsNaN(percent):
+		return 0
+	}
+	parts := uint64(math.Round(percent / 100 * percentScale))
+	hi, lo := bits.Mul64(amount, parts)
+	q, _ := bits.Div64(hi, lo, percentScale)
+	return q
+}
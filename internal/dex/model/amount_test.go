@@ -0,0 +1,56 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolToLamports(t *testing.T) {
+	assert.Equal(t, uint64(290_000_000), SolToLamports(0.29), "float truncation would give 289999999")
+	assert.Equal(t, uint64(1), SolToLamports(0.000000001))
+	assert.Zero(t, SolToLamports(-1))
+	assert.Zero(t, SolToLamports(math.NaN()))
+}
+
+func TestPercentOf(t *testing.T) {
+	assert.Equal(t, uint64(500), PercentOf(1_000, 50))
+	assert.Equal(t, uint64(333), PercentOf(1_000, 100.0/3), "rounds down to a whole base unit")
+	assert.Equal(t, uint64(0), PercentOf(1, 99.9))
+	assert.Zero(t, PercentOf(1_000, 0))
+
+	// Баланс выше 2^53 не помещается в float64 без потерь: 100% должны вернуть его целиком
+	balance := uint64(1<<60 + 1)
+	assert.Equal(t, balance, PercentOf(balance, 100))
+	assert.Equal(t, balance, PercentOf(balance, 150))
+	assert.LessOrEqual(t, PercentOf(balance, 99.9999999), balance)
+	assert.Equal(t, balance/4, PercentOf(balance, 25))
+}
+
+func TestTokensToBaseUnits(t *testing.T) {
+	for name, tc := range map[string]struct {
+		amount   float64
+		decimals uint8
+		want     uint64
+	}{
+		"float product is not exact": {amount: 0.3, decimals: 6, want: 300_000},
+		"many decimals":              {amount: 1234.567891, decimals: 6, want: 1_234_567_891},
+		"extra digits round down":    {amount: 1.23456789, decimals: 6, want: 1_234_567},
+		"whole tokens":               {amount: 42, decimals: 9, want: 42_000_000_000},
+		"no decimals":                {amount: 7.9, decimals: 0, want: 7},
+		"zero":                       {amount: 0, decimals: 6, want: 0},
+		"smallest unit":              {amount: 0.000001, decimals: 6, want: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := TokensToBaseUnits(tc.amount, tc.decimals)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	for _, amount := range []float64{-1, math.NaN(), math.Inf(1), 1e20} {
+		_, err := TokensToBaseUnits(amount, 6)
+		assert.Error(t, err, amount)
+	}
+}
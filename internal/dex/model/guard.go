@@ -0,0 +1,61 @@
+// Package model internal/model/guard.go
+package model
+
+import (
+	"math"
+	"math/bits"
+)
+
+// SlippageGuard turns a quoted amount into the bound that goes into the swap
+// instruction, so the program itself rejects a fill worse than the slippage.
+type SlippageGuard interface {
+	MaxIn(quoted uint64, slippagePercent float64) uint64  // most we agree to pay
+	MinOut(quoted uint64, slippagePercent float64) uint64 // least we agree to receive
+}
+
+// DefaultSlippageGuard is used when a DEX config sets no guard.
+var DefaultSlippageGuard SlippageGuard = PercentGuard{}
+
+// PercentGuard widens the quote by slippagePercent in basis points, using
+// integer math so large raw amounts do not lose precision.
+type PercentGuard struct{}
+
+// MaxIn returns quoted × (1 + slippage), rounded up and capped at MaxUint64.
+func (PercentGuard) MaxIn(quoted uint64, slippagePercent float64) uint64 {
+	bps := slippageBps(slippagePercent)
+	hi, lo := bits.Mul64(quoted, 10_000+bps)
+	if hi >= 10_000 {
+		return math.MaxUint64
+	}
+	q, r := bits.Div64(hi, lo, 10_000)
+	if r > 0 && q < math.MaxUint64 {
+		q++
+	}
+	return q
+}
+
+// MinOut returns quoted × (1 - slippage), rounded down.
+func (PercentGuard) MinOut(quoted uint64, slippagePercent float64) uint64 {
+	bps := slippageBps(slippagePercent)
+	if bps >= 10_000 {
+		return 0
+	}
+	hi, lo := bits.Mul64(quoted, 10_000-bps)
+	q, _ := bits.Div64(hi, lo, 10_000)
+	return q
+}
+
+// GuardOrDefault returns g, or DefaultSlippageGuard when g is nil.
+func GuardOrDefault(g SlippageGuard) SlippageGuard {
+	if g == nil {
+		return DefaultSlippageGuard
+	}
+	return g
+}
+
+func slippageBps(slippagePercent float64) uint64 {
+	if slippagePercent <= 0 || math.IsNaN(slippagePercent) {
+		return 0
+	}
+	return uint64(math.Round(slippagePercent * 100))
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentGuard(t *testing.T) {
+	g := PercentGuard{}
+
+	assert.Equal(t, uint64(1_100_000), g.MaxIn(1_000_000, 10))
+	assert.Equal(t, uint64(900_000), g.MinOut(1_000_000, 10))
+
+	// Верхняя граница округляется вверх, нижняя — вниз
+	assert.Equal(t, uint64(2), g.MaxIn(1, 0.5))
+	assert.Equal(t, uint64(0), g.MinOut(1, 0.5))
+
+	assert.Equal(t, uint64(1_000), g.MaxIn(1_000, 0), "zero slippage keeps the quote")
+	assert.Equal(t, uint64(0), g.MinOut(1_000, 100), "100% slippage accepts any output")
+	assert.Equal(t, uint64(math.MaxUint64), g.MaxIn(math.MaxUint64/2, 150), "overflow caps instead of wrapping")
+	assert.Equal(t, uint64(4150517416584649113), g.MinOut(1<<62, 10), "large amounts keep precision")
+}
+
+func TestGuardOrDefault(t *testing.T) {
+	assert.Equal(t, DefaultSlippageGuard, GuardOrDefault(nil))
+
+	custom := PercentGuard{}
+	assert.Equal(t, SlippageGuard(custom), GuardOrDefault(custom))
+}
@@ -0,0 +1,23 @@
+// Package model internal/model/metadata.go
+package model
+
+// TokenMetadata describes a token for display, so positions and alerts can show
+// a name instead of a truncated mint address.
+type TokenMetadata struct {
+	Name   string
+	Symbol string
+	Image  string // image URL, empty when unknown
+	Source string // where it was resolved: "metaplex" or "pump.fun"
+}
+
+// Label returns the symbol, or the name when the token has no symbol;
+// "" for nil metadata.
+func (m *TokenMetadata) Label() string {
+	if m == nil {
+		return ""
+	}
+	if m.Symbol != "" {
+		return m.Symbol
+	}
+	return m.Name
+}
@@ -7,4 +7,6 @@ type PnLResult struct {
 	SellEstimate      float64 // value if sold now (fee‑adjusted)
 	NetPnL            float64 // profit / loss
 	PnLPercentage     float64 // NetPnL ÷ InitialInvestment x 100
+	ProtocolFee       float64 // venue fee already taken out of SellEstimate, SOL
+	CreatorFee        float64 // token creator fee already taken out of SellEstimate, SOL
 }
@@ -0,0 +1,72 @@
+// internal/dex/price_accounts.go
+package dex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// PriceAccountSource — адаптер, цена которого считается по известным аккаунтам: по их
+// изменениям из потока Geyser монитор пересчитывает цену сразу, не дожидаясь тика.
+type PriceAccountSource interface {
+	// PriceAccounts возвращает аккаунты, изменение которых меняет цену токена.
+	PriceAccounts(ctx context.Context, tokenMint string) ([]solana.PublicKey, error)
+	// InvalidatePrice сбрасывает кеши цены: следующий GetTokenPrice читает цепочку.
+	InvalidatePrice()
+}
+
+// PriceAccounts возвращает bonding curve токена.
+func (d *pumpfunDEXAdapter) PriceAccounts(ctx context.Context, tokenMint string) ([]solana.PublicKey, error) {
+	if err := d.init(ctx, tokenMint, d.makeInitPumpFun(tokenMint)); err != nil {
+		return nil, err
+	}
+	return d.inner.PriceAccounts(ctx)
+}
+
+// InvalidatePrice сбрасывает кеш bonding curve.
+func (d *pumpfunDEXAdapter) InvalidatePrice() {
+	if d.inner != nil {
+		d.inner.InvalidatePrice()
+	}
+}
+
+// PriceAccounts возвращает хранилища токенов пула.
+func (d *pumpswapDEXAdapter) PriceAccounts(ctx context.Context, tokenMint string) ([]solana.PublicKey, error) {
+	if err := d.init(ctx, tokenMint, d.makeInitPumpSwap(tokenMint)); err != nil {
+		return nil, err
+	}
+	return d.inner.PriceAccounts(ctx)
+}
+
+// InvalidatePrice сбрасывает кеш цены и пула.
+func (d *pumpswapDEXAdapter) InvalidatePrice() {
+	if d.inner != nil {
+		d.inner.InvalidatePrice()
+	}
+}
+
+// PriceAccounts возвращает аккаунты цены на текущей площадке токена. После миграции
+// набор меняется, поэтому вызывающий перечитывает его при изменениях.
+func (d *smartDEXAdapter) PriceAccounts(ctx context.Context, tokenMint string) ([]solana.PublicKey, error) {
+	target, venue, err := d.route(ctx, tokenMint)
+	if err != nil {
+		return nil, err
+	}
+	src, ok := target.(PriceAccountSource)
+	if !ok {
+		return nil, fmt.Errorf("price accounts are not available on %s", venue)
+	}
+	return src.PriceAccounts(ctx, tokenMint)
+}
+
+// InvalidatePrice сбрасывает кеши цены текущей площадки.
+func (d *smartDEXAdapter) InvalidatePrice() {
+	d.routeMu.Lock()
+	target := d.dex
+	d.routeMu.Unlock()
+	if src, ok := target.(PriceAccountSource); ok {
+		src.InvalidatePrice()
+	}
+}
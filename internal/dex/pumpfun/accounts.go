@@ -14,7 +14,13 @@ import (
 )
 
 // ----- адреса Bonding‑Curve кэшируются раз‑и‑навсегда -----
-func (d *DEX) deriveBondingCurveAccounts(_ context.Context) (solana.PublicKey, solana.PublicKey, error) {
+func (d *DEX) deriveBondingCurveAccounts(ctx context.Context) (solana.PublicKey, solana.PublicKey, error) {
+	// ATA кривой зависит от программы токена, поэтому она определяется до деривации
+	program, err := d.tokenProgram(ctx)
+	if err != nil {
+		return solana.PublicKey{}, solana.PublicKey{}, err
+	}
+
 	var initErr error
 	d.bcOnce.Do(func() {
 		d.bondingCurve, _, initErr = solana.FindProgramAddress(
@@ -24,8 +30,8 @@ func (d *DEX) deriveBondingCurveAccounts(_ context.Context) (solana.PublicKey, s
 		if initErr != nil {
 			return
 		}
-		d.associatedBondingCurve, _, initErr =
-			solana.FindAssociatedTokenAddress(d.bondingCurve, d.config.Mint)
+		d.associatedBondingCurve, initErr =
+			blockchain.FindAssociatedTokenAddress(d.bondingCurve, d.config.Mint, program)
 	})
 	if initErr != nil {
 		return solana.PublicKey{}, solana.PublicKey{},
@@ -64,36 +70,20 @@ func (d *DEX) getBondingCurveData(ctx context.Context) (*BondingCurve, solana.Pu
 
 	raw := res.Value[0].Data.GetBinary()
 
-	// Проверяем, что у нас достаточно данных для дискриминатора и базовых полей
-	// 8 (дискриминатор) + 8*5 (u64*5) + 1 (bool) = 49 байт минимум
-	if len(raw) < 49 {
-		return nil, bcAddr, fmt.Errorf("bonding curve data too short for basic fields: %d bytes", len(raw))
+	// 4) Десериализация полей прямо из буфера ответа RPC
+	bc, err := parseBondingCurve(raw)
+	if err != nil {
+		return nil, bcAddr, err
 	}
 
-	// Пропускаем первые 8 байт (дискриминатор)
-	dataWithoutDiscriminator := raw[8:]
-
-	// 4) Десериализация полей (без дискриминатора)
-	bc := &BondingCurve{
-		VirtualTokenReserves: binary.LittleEndian.Uint64(dataWithoutDiscriminator[0:8]),
-		VirtualSolReserves:   binary.LittleEndian.Uint64(dataWithoutDiscriminator[8:16]),
-		RealTokenReserves:    binary.LittleEndian.Uint64(dataWithoutDiscriminator[16:24]),
-		RealSolReserves:      binary.LittleEndian.Uint64(dataWithoutDiscriminator[24:32]),
-		TokenTotalSupply:     binary.LittleEndian.Uint64(dataWithoutDiscriminator[32:40]),
-		Complete:             dataWithoutDiscriminator[40] != 0,
-	}
-
-	// Проверяем, есть ли данные для поля Creator (должно быть минимум 40+1+32 байт)
-	if len(dataWithoutDiscriminator) >= 41+32 {
-		bc.Creator = solana.PublicKeyFromBytes(dataWithoutDiscriminator[41:73])
-		d.logger.Debug("Parsed creator from bonding curve",
-			zap.String("creator", bc.Creator.String()),
-			zap.String("bonding_curve", bcAddr.String()))
+	if !bc.Creator.IsZero() {
+		// base58 ключей считаем, только если debug действительно пишется: это горячий путь тика
+		if ce := d.logger.Check(zap.DebugLevel, "Parsed creator from bonding curve"); ce != nil {
+			ce.Write(zap.Stringer("creator", bc.Creator), zap.Stringer("bonding_curve", bcAddr))
+		}
 	} else {
-		// Если данных недостаточно, устанавливаем Creator в пустой PublicKey
-		bc.Creator = solana.PublicKey{}
 		d.logger.Warn("Bonding curve data too short to include Creator field",
-			zap.Int("data_length", len(dataWithoutDiscriminator)),
+			zap.Int("data_length", len(raw)),
 			zap.String("bonding_curve", bcAddr.String()))
 	}
 
@@ -106,6 +96,43 @@ func (d *DEX) getBondingCurveData(ctx context.Context) (*BondingCurve, solana.Pu
 	return bc, bcAddr, nil
 }
 
+// parseBondingCurve разбирает сырые данные аккаунта bonding curve (вместе с дискриминатором).
+func parseBondingCurve(raw []byte) (*BondingCurve, error) {
+	bc := &BondingCurve{}
+	if err := decodeBondingCurve(raw, bc); err != nil {
+		return nil, err
+	}
+	return bc, nil
+}
+
+// decodeBondingCurve заполняет bc прямо из raw без промежуточных копий и аллокаций.
+func decodeBondingCurve(raw []byte, bc *BondingCurve) error {
+	// Проверяем, что у нас достаточно данных для дискриминатора и базовых полей
+	// 8 (дискриминатор) + 8*5 (u64*5) + 1 (bool) = 49 байт минимум
+	if len(raw) < 49 {
+		return fmt.Errorf("bonding curve data too short for basic fields: %d bytes", len(raw))
+	}
+
+	// Пропускаем первые 8 байт (дискриминатор)
+	data := raw[8:]
+
+	*bc = BondingCurve{
+		VirtualTokenReserves: binary.LittleEndian.Uint64(data[0:8]),
+		VirtualSolReserves:   binary.LittleEndian.Uint64(data[8:16]),
+		RealTokenReserves:    binary.LittleEndian.Uint64(data[16:24]),
+		RealSolReserves:      binary.LittleEndian.Uint64(data[24:32]),
+		TokenTotalSupply:     binary.LittleEndian.Uint64(data[32:40]),
+		Complete:             data[40] != 0,
+	}
+
+	// Поле Creator есть только в новых аккаунтах (минимум 40+1+32 байт)
+	if len(data) >= 41+32 {
+		copy(bc.Creator[:], data[41:73])
+	}
+
+	return nil
+}
+
 // DeriveCreatorVaultPDA определяет адрес creator-vault PDA на основе адреса создателя токена
 // Реализация соответствует Python-коду для поиска creator-vault
 func DeriveCreatorVaultPDA(programID, creator solana.PublicKey) (solana.PublicKey, uint8, error) {
@@ -119,7 +146,7 @@ func DeriveCreatorVaultPDA(programID, creator solana.PublicKey) (solana.PublicKe
 func FetchGlobalAccount(ctx context.Context, client *blockchain.Client, globalAddr solana.PublicKey, logger *zap.Logger) (*GlobalAccount, error) {
 	// Получение информации об аккаунте с блокчейна
 	start := time.Now()
-	accountInfo, err := client.GetAccountInfo(ctx, globalAddr)
+	accountInfo, err := client.GetCachedAccountInfo(ctx, globalAddr, blockchain.ConfigCacheTTL)
 
 	if logger != nil {
 		logger.Debug("RPC:GetAccountInfo",
@@ -159,6 +186,10 @@ func FetchGlobalAccount(ctx context.Context, client *blockchain.Client, globalAd
 		account.Initialized = data[0] != 0
 		account.Authority = solana.PublicKeyFromBytes(data[1:33])
 		account.FeeRecipient = solana.PublicKeyFromBytes(data[33:65])
+		account.InitialVirtualTokenRes = binary.LittleEndian.Uint64(data[65:73])
+		account.InitialVirtualSolRes = binary.LittleEndian.Uint64(data[73:81])
+		account.InitialRealTokenRes = binary.LittleEndian.Uint64(data[81:89])
+		account.TokenTotalSupply = binary.LittleEndian.Uint64(data[89:97])
 
 		// Для расчета комиссий нам особенно важны эти поля, поэтому их обязательно парсим
 		offset := 97 // Пропускаем другие поля, которые мы не используем
@@ -180,3 +211,20 @@ func FetchGlobalAccount(ctx context.Context, client *blockchain.Client, globalAd
 
 	return account, nil
 }
+
+// FetchBondingCurve читает bonding curve токена mint. Возвращает nil без ошибки,
+// если токен не создавался на Pump.fun.
+func FetchBondingCurve(ctx context.Context, client *blockchain.Client, mint solana.PublicKey) (*BondingCurve, error) {
+	addr, _, err := solana.FindProgramAddress([][]byte{[]byte("bonding-curve"), mint.Bytes()}, PumpFunProgramID)
+	if err != nil {
+		return nil, fmt.Errorf("derive bonding curve: %w", err)
+	}
+	res, err := client.GetMultipleAccounts(ctx, []solana.PublicKey{addr})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Value) == 0 || res.Value[0] == nil {
+		return nil, nil
+	}
+	return parseBondingCurve(res.Value[0].Data.GetBinary())
+}
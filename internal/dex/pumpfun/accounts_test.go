@@ -0,0 +1,56 @@
+package pumpfun
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bondingCurveAccount собирает данные аккаунта bonding curve с дискриминатором и creator.
+func bondingCurveAccount(creator solana.PublicKey) []byte {
+	raw := make([]byte, 8+41+32)
+	data := raw[8:]
+	binary.LittleEndian.PutUint64(data[0:], 1_073_000_000_000_000)
+	binary.LittleEndian.PutUint64(data[8:], 30_000_000_000)
+	binary.LittleEndian.PutUint64(data[16:], 793_100_000_000_000)
+	binary.LittleEndian.PutUint64(data[32:], 1_000_000_000_000_000)
+	copy(data[41:], creator[:])
+	return raw
+}
+
+func TestDecodeBondingCurve(t *testing.T) {
+	creator := solana.NewWallet().PublicKey()
+	raw := bondingCurveAccount(creator)
+
+	bc, err := parseBondingCurve(raw)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(30_000_000_000), bc.VirtualSolReserves)
+	assert.Equal(t, creator, bc.Creator)
+	assert.False(t, bc.Complete)
+
+	// Разбор в переиспользуемую структуру не аллоцирует и затирает прошлые значения
+	var reused BondingCurve
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = decodeBondingCurve(raw, &reused)
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, *bc, reused)
+
+	assert.NoError(t, decodeBondingCurve(raw[:49], &reused))
+	assert.True(t, reused.Creator.IsZero())
+
+	_, err = parseBondingCurve(raw[:20])
+	assert.Error(t, err)
+}
+
+func BenchmarkDecodeBondingCurve(b *testing.B) {
+	raw := bondingCurveAccount(solana.NewWallet().PublicKey())
+	var bc BondingCurve
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = decodeBondingCurve(raw, &bc)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
 	"go.uber.org/zap"
 )
 
@@ -18,6 +19,7 @@ type Config struct {
 	EventAuthority  solana.PublicKey
 	Mint            solana.PublicKey
 	MonitorInterval string
+	SlippageGuard   model.SlippageGuard // Границы min-out/max-cost для инструкций; nil — model.DefaultSlippageGuard
 }
 
 // GetDefaultConfig создает конфигурацию по умолчанию для Pump.fun DEX.
@@ -0,0 +1,180 @@
+// =============================
+// File: internal/dex/pumpfun/create_event.go
+// =============================
+package pumpfun
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// PumpFunMintAuthority — mint authority токенов Pump.fun. Адрес участвует только
+// в транзакциях создания токенов, поэтому удобен для подписки на новые mint.
+var PumpFunMintAuthority = solana.MustPublicKeyFromBase58("TSLvdd1pWpHVjahSpsvCXUbgwsL3JAcvokwaKt1eokM")
+
+// Anchor-дискриминаторы событий, которые Pump.fun пишет в логи (Program data).
+var (
+	createEventDiscriminator = eventDiscriminator("CreateEvent")
+	tradeEventDiscriminator  = eventDiscriminator("TradeEvent")
+)
+
+func eventDiscriminator(name string) []byte {
+	h := sha256.Sum256([]byte("event:" + name))
+	return h[:8]
+}
+
+// errShortEvent означает, что данные события короче ожидаемого формата.
+var errShortEvent = errors.New("pump.fun event data too short")
+
+// NewToken — токен, созданный инструкцией Create программы Pump.fun.
+type NewToken struct {
+	Mint          solana.PublicKey
+	BondingCurve  solana.PublicKey
+	Creator       solana.PublicKey
+	Name          string
+	Symbol        string
+	URI           string
+	InitialBuySol float64 // Покупка создателя в той же транзакции, SOL (0 — без покупки)
+}
+
+// ParseCreateLogs ищет в логах транзакции событие создания токена и покупки в той же
+// транзакции. Второй результат false, если транзакция не создавала токен.
+func ParseCreateLogs(logs []string) (*NewToken, bool) {
+	var token *NewToken
+	for _, data := range eventData(logs, createEventDiscriminator) {
+		if t, err := parseCreateEvent(data); err == nil {
+			token = t
+			break
+		}
+	}
+	if token == nil {
+		return nil, false
+	}
+
+	var buyLamports uint64
+	for _, tr := range ParseTradeLogs(logs) {
+		if tr.IsBuy && tr.Mint.Equals(token.Mint) {
+			buyLamports += tr.SolAmount
+		}
+	}
+	token.InitialBuySol = float64(buyLamports) / float64(solana.LAMPORTS_PER_SOL)
+	return token, true
+}
+
+// Trade — покупка или продажа на bonding curve Pump.fun (событие TradeEvent).
+type Trade struct {
+	Mint        solana.PublicKey
+	User        solana.PublicKey
+	SolAmount   uint64 // lamports
+	TokenAmount uint64 // Базовые единицы токена
+	IsBuy       bool
+}
+
+// ParseTradeLogs возвращает сделки Pump.fun из логов транзакции в порядке исполнения.
+func ParseTradeLogs(logs []string) []Trade {
+	var trades []Trade
+	for _, data := range eventData(logs, tradeEventDiscriminator) {
+		if tr, err := parseTradeEvent(data); err == nil {
+			trades = append(trades, tr)
+		}
+	}
+	return trades
+}
+
+// eventData возвращает данные событий с дискриминатором discriminator из строк
+// "Program data: <base64>" (без самого дискриминатора).
+func eventData(logs []string, discriminator []byte) [][]byte {
+	var events [][]byte
+	for _, line := range logs {
+		encoded, ok := strings.CutPrefix(line, "Program data: ")
+		if !ok {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(data) < 8 || string(data[:8]) != string(discriminator) {
+			continue
+		}
+		events = append(events, data[8:])
+	}
+	return events
+}
+
+// parseCreateEvent разбирает CreateEvent: name, symbol, uri, mint, bonding_curve, user
+// и, в новых версиях программы, creator.
+func parseCreateEvent(data []byte) (*NewToken, error) {
+	r := eventReader{data: data}
+	t := &NewToken{
+		Name:   r.string(),
+		Symbol: r.string(),
+		URI:    r.string(),
+	}
+	t.Mint = r.pubkey()
+	t.BondingCurve = r.pubkey()
+	t.Creator = r.pubkey() // user — подписант создания
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) >= solana.PublicKeyLength {
+		t.Creator = r.pubkey()
+	}
+	return t, nil
+}
+
+// parseTradeEvent разбирает начало TradeEvent: mint, sol_amount, token_amount, is_buy, user.
+func parseTradeEvent(data []byte) (Trade, error) {
+	r := eventReader{data: data}
+	tr := Trade{Mint: r.pubkey(), SolAmount: r.u64(), TokenAmount: r.u64()}
+	tr.IsBuy = r.bool()
+	tr.User = r.pubkey()
+	return tr, r.err
+}
+
+// eventReader последовательно читает поля события в формате Borsh.
+type eventReader struct {
+	data []byte
+	err  error
+}
+
+func (r *eventReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errShortEvent
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *eventReader) u64() uint64 {
+	if b := r.take(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *eventReader) bool() bool {
+	b := r.take(1)
+	return b != nil && b[0] != 0
+}
+
+func (r *eventReader) pubkey() solana.PublicKey {
+	var key solana.PublicKey
+	copy(key[:], r.take(solana.PublicKeyLength))
+	return key
+}
+
+func (r *eventReader) string() string {
+	b := r.take(4)
+	if b == nil {
+		return ""
+	}
+	return string(r.take(int(binary.LittleEndian.Uint32(b))))
+}
@@ -0,0 +1,106 @@
+package pumpfun
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func borshString(s string) []byte {
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(s))), s...)
+}
+
+func programData(data []byte) string {
+	return "Program data: " + base64.StdEncoding.EncodeToString(data)
+}
+
+func TestParseCreateLogs(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	curve := solana.NewWallet().PublicKey()
+	user := solana.NewWallet().PublicKey()
+	creator := solana.NewWallet().PublicKey()
+
+	create := append([]byte{}, createEventDiscriminator...)
+	create = append(create, borshString("Moon Cat")...)
+	create = append(create, borshString("MCAT")...)
+	create = append(create, borshString("https://example.com/mcat.json")...)
+	create = append(create, mint[:]...)
+	create = append(create, curve[:]...)
+	create = append(create, user[:]...)
+
+	trade := func(m solana.PublicKey, lamports uint64, buy bool) []byte {
+		data := append([]byte{}, tradeEventDiscriminator...)
+		data = append(data, m[:]...)
+		data = binary.LittleEndian.AppendUint64(data, lamports)
+		data = binary.LittleEndian.AppendUint64(data, 1_000_000)
+		if buy {
+			data = append(data, 1)
+		} else {
+			data = append(data, 0)
+		}
+		return append(data, user[:]...)
+	}
+
+	logs := []string{
+		"Program 6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P invoke [1]",
+		"Program log: Instruction: Create",
+		programData(create),
+		"Program log: Instruction: Buy",
+		programData(trade(mint, 1_500_000_000, true)),
+		programData(trade(solana.NewWallet().PublicKey(), 9_000_000_000, true)), // Чужой mint не считается
+		"Program data: not-base64!",
+	}
+	token, ok := ParseCreateLogs(logs)
+	require.True(t, ok)
+	assert.Equal(t, &NewToken{
+		Mint:          mint,
+		BondingCurve:  curve,
+		Creator:       user,
+		Name:          "Moon Cat",
+		Symbol:        "MCAT",
+		URI:           "https://example.com/mcat.json",
+		InitialBuySol: 1.5,
+	}, token)
+
+	// Новые версии программы добавляют creator после user
+	withCreator := append(append([]byte{}, create...), creator[:]...)
+	token, ok = ParseCreateLogs([]string{programData(withCreator)})
+	require.True(t, ok)
+	assert.Equal(t, creator, token.Creator)
+	assert.Zero(t, token.InitialBuySol)
+
+	_, ok = ParseCreateLogs([]string{"Program log: Instruction: Buy", programData(trade(mint, 1, true))})
+	assert.False(t, ok)
+	_, ok = ParseCreateLogs([]string{programData(create[:40])})
+	assert.False(t, ok, "truncated event is ignored")
+}
+
+func TestParseTradeLogs(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	user := solana.NewWallet().PublicKey()
+	event := func(lamports, tokens uint64, buy byte) string {
+		data := append([]byte{}, tradeEventDiscriminator...)
+		data = append(data, mint[:]...)
+		data = binary.LittleEndian.AppendUint64(data, lamports)
+		data = binary.LittleEndian.AppendUint64(data, tokens)
+		data = append(data, buy)
+		data = append(data, user[:]...)
+		return programData(binary.LittleEndian.AppendUint64(data, 1_700_000_000)) // timestamp
+	}
+
+	trades := ParseTradeLogs([]string{
+		"Program log: Instruction: Buy",
+		event(250_000_000, 9_000_000, 1),
+		"Program log: Instruction: Sell",
+		event(100_000_000, 3_000_000, 0),
+		programData(append(append([]byte{}, tradeEventDiscriminator...), mint[:10]...)), // Обрезанное событие
+	})
+	assert.Equal(t, []Trade{
+		{Mint: mint, User: user, SolAmount: 250_000_000, TokenAmount: 9_000_000, IsBuy: true},
+		{Mint: mint, User: user, SolAmount: 100_000_000, TokenAmount: 3_000_000},
+	}, trades)
+}
@@ -0,0 +1,69 @@
+// internal/dex/pumpfun/curve.go
+package pumpfun
+
+import "math/big"
+
+// buyCost вычисляет, сколько программа спишет за покупку tokenAmount по кривой:
+// стоимость тянется вдоль всей кривой, а не по спотовой цене,
+// floor(vsol * amount / (vtoken - amount)) + 1, и комиссии поверх нее.
+func (d *DEX) buyCost(tokenAmount uint64, curve *BondingCurve) (total, protocolFee, creatorFee uint64, ok bool) {
+	if tokenAmount == 0 || tokenAmount >= curve.VirtualTokenReserves {
+		return 0, 0, 0, false
+	}
+	cost := mulDivFloor(curve.VirtualSolReserves, tokenAmount, curve.VirtualTokenReserves-tokenAmount)
+	if !cost.IsUint64() {
+		return 0, 0, 0, false
+	}
+	solCost := cost.Uint64() + 1
+	protocolFee, creatorFee = d.curveFees(curve).Split(solCost)
+	return solCost + protocolFee + creatorFee, protocolFee, creatorFee, true
+}
+
+// quoteBuy возвращает наибольшее количество токенов (raw), покупка которых по
+// текущей кривой вместе с комиссиями стоит не больше solAmountLamports. Для крупных
+// покупок цена заметно растет по ходу сделки, поэтому количество считается по
+// формуле кривой, а не по спотовой цене. Покупка не может забрать больше
+// реальных резервов токенов кривой.
+func (d *DEX) quoteBuy(solAmountLamports uint64, curve *BondingCurve) uint64 {
+	if curve.VirtualSolReserves == 0 || curve.VirtualTokenReserves == 0 || solAmountLamports == 0 {
+		return 0
+	}
+
+	// Сумма без комиссий; округление комиссий вверх уточняется циклом ниже
+	fees := d.curveFees(curve)
+	budget := mulDivFloor(solAmountLamports, 10_000, 10_000+fees.TotalBps()).Uint64()
+
+	for budget > 1 {
+		// Обращение формулы стоимости: amount = vtoken * (c - 1) / (vsol + c - 1)
+		spend := budget - 1
+		tokens := mulDivFloor(curve.VirtualTokenReserves, spend, curve.VirtualSolReserves+spend).Uint64()
+		if curve.RealTokenReserves > 0 && tokens > curve.RealTokenReserves {
+			tokens = curve.RealTokenReserves
+		}
+		if total, _, _, ok := d.buyCost(tokens, curve); ok && total <= solAmountLamports {
+			return tokens
+		}
+		budget--
+	}
+	return 0
+}
+
+// priceImpact возвращает, на сколько процентов покупка tokens за solAmountLamports
+// дороже спотовой цены кривой (комиссии не входят).
+func (d *DEX) priceImpact(solAmountLamports, tokens uint64, curve *BondingCurve) float64 {
+	if tokens == 0 || curve.VirtualTokenReserves == 0 {
+		return 0
+	}
+	spend := float64(solAmountLamports) * (1 - d.curveFees(curve).Percent()/100)
+	spot := float64(curve.VirtualSolReserves) / float64(curve.VirtualTokenReserves)
+	return (spend/float64(tokens)/spot - 1) * 100
+}
+
+// mulDivFloor вычисляет floor(a * b / c) без переполнения.
+func mulDivFloor(a, b, c uint64) *big.Int {
+	if c == 0 {
+		return new(big.Int)
+	}
+	num := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	return num.Quo(num, new(big.Int).SetUint64(c))
+}
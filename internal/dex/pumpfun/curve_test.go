@@ -0,0 +1,63 @@
+package pumpfun
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buyData(t *testing.T, tokens, maxSolCost uint64) []byte {
+	ix := createBuyInstruction(PumpFunProgramID, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{},
+		solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{},
+		solana.PublicKey{}, TokenProgramID, tokens, maxSolCost)
+	data, err := ix.Data()
+	require.NoError(t, err)
+	return data
+}
+
+func TestQuoteBuy_LargeOrderFitsExactly(t *testing.T) {
+	d := &DEX{fees: Fees{ProtocolBps: testFeeBps}}
+	curve := testCurve()
+
+	for _, solIn := range []uint64{10_000_000, 1_000_000_000, 50_000_000_000} {
+		tokens := d.quoteBuy(solIn, &curve)
+		require.NotZero(t, tokens)
+
+		// Без запаса на slippage покупка проходит по исходной кривой впритык
+		assert.NoError(t, simulateBuy(curve, buyData(t, tokens, solIn)), "%d lamports", solIn)
+		total, _, _, ok := d.buyCost(tokens, &curve)
+		require.True(t, ok)
+		assert.LessOrEqual(t, total, solIn)
+
+		// Чуть больше токенов за ту же сумму программа уже не отдаст
+		assert.ErrorIs(t, simulateBuy(curve, buyData(t, tokens+tokens/10_000+1_000, solIn)), errTooMuchSolRequired)
+	}
+
+	// Крупная покупка получает заметно меньше токенов, чем по спотовой цене
+	spot := float64(curve.VirtualTokenReserves) / float64(curve.VirtualSolReserves) * 50e9 * 0.99
+	assert.Less(t, float64(d.quoteBuy(50_000_000_000, &curve)), spot*0.5)
+}
+
+func TestQuoteBuy_CappedByRealReserves(t *testing.T) {
+	d := &DEX{}
+	curve := testCurve()
+	curve.RealTokenReserves = 10_000_000_000_000
+
+	tokens := d.quoteBuy(20_000_000_000, &curve)
+	assert.Equal(t, curve.RealTokenReserves, tokens)
+	total, _, _, ok := d.buyCost(tokens, &curve)
+	require.True(t, ok)
+	assert.Less(t, total, uint64(20_000_000_000))
+}
+
+func TestPriceImpact(t *testing.T) {
+	d := &DEX{fees: Fees{ProtocolBps: testFeeBps}}
+	curve := testCurve()
+
+	small := d.priceImpact(10_000_000, d.quoteBuy(10_000_000, &curve), &curve)
+	large := d.priceImpact(50_000_000_000, d.quoteBuy(50_000_000_000, &curve), &curve)
+	assert.Less(t, small, 0.1)
+	assert.Greater(t, large, 100.0)
+}
@@ -0,0 +1,61 @@
+// internal/dex/pumpfun/fees.go
+package pumpfun
+
+import "math/bits"
+
+// DefaultFees — комиссии Pump.fun, пока не прочитан глобальный аккаунт.
+var DefaultFees = Fees{ProtocolBps: 95, CreatorBps: 5}
+
+// Fees — комиссии bonding curve в базисных пунктах от суммы сделки в SOL:
+// протоколу (fee_basis_points) и создателю токена (creator_fee_basis_points).
+type Fees struct {
+	ProtocolBps uint64
+	CreatorBps  uint64
+}
+
+// FeesFromGlobal берет комиссии из глобального аккаунта; nil — DefaultFees.
+func FeesFromGlobal(g *GlobalAccount) Fees {
+	if g == nil || g.FeeBasisPoints == 0 {
+		return DefaultFees
+	}
+	return Fees{ProtocolBps: g.FeeBasisPoints, CreatorBps: g.CreatorFeeBasisPoints}
+}
+
+// forCurve возвращает комиссии сделок по кривой: без создателя в кривой (токены,
+// созданные до появления creator fee) его комиссия не взимается.
+func (f Fees) forCurve(curve *BondingCurve) Fees {
+	if curve == nil || curve.Creator.IsZero() {
+		f.CreatorBps = 0
+	}
+	return f
+}
+
+// TotalBps возвращает суммарную комиссию в базисных пунктах.
+func (f Fees) TotalBps() uint64 {
+	return f.ProtocolBps + f.CreatorBps
+}
+
+// Percent возвращает суммарную комиссию в процентах.
+func (f Fees) Percent() float64 {
+	return float64(f.TotalBps()) / 100
+}
+
+// Split считает комиссии сделки на lamports SOL так же, как программа:
+// каждая округляется вверх, ceil(lamports * bps / 10000).
+func (f Fees) Split(lamports uint64) (protocol, creator uint64) {
+	return feeOf(lamports, f.ProtocolBps), feeOf(lamports, f.CreatorBps)
+}
+
+// maxFeeBps — комиссия не больше суммы сделки; большее значение из глобального
+// аккаунта не переполняет деление в feeOf.
+const maxFeeBps = 10_000
+
+func feeOf(lamports, bps uint64) uint64 {
+	if bps == 0 || lamports == 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(lamports, min(bps, maxFeeBps))
+	lo, carry := bits.Add64(lo, 9_999, 0)
+	fee, _ := bits.Div64(hi+carry, lo, 10_000)
+	return fee
+}
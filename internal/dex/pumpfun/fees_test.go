@@ -0,0 +1,46 @@
+package pumpfun
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFees_Split(t *testing.T) {
+	fees := FeesFromGlobal(&GlobalAccount{FeeBasisPoints: 95, CreatorFeeBasisPoints: 30})
+	protocol, creator := fees.Split(1_000_000_001)
+	assert.Equal(t, uint64(9_500_001), protocol, "rounded up like the program")
+	assert.Equal(t, uint64(3_000_001), creator)
+	assert.InDelta(t, 1.25, fees.Percent(), 1e-9)
+
+	assert.Equal(t, DefaultFees, FeesFromGlobal(nil))
+}
+
+func TestFeeOf_Bounds(t *testing.T) {
+	assert.Equal(t, uint64(1), feeOf(1, 1), "any fee rounds up to a lamport")
+	assert.Equal(t, uint64(math.MaxUint64), feeOf(math.MaxUint64, 10_000))
+	assert.NotPanics(t, func() {
+		assert.Equal(t, uint64(math.MaxUint64), feeOf(math.MaxUint64, math.MaxUint16), "fees above 100% are capped at the amount")
+	})
+}
+
+func TestSellQuote_DeductsCreatorFee(t *testing.T) {
+	d := &DEX{fees: Fees{ProtocolBps: 95, CreatorBps: 30}}
+	curve := testCurve()
+	const tokens = 20_000_000_000_000
+	gross := mulDiv(tokens, curve.VirtualSolReserves, curve.VirtualTokenReserves+tokens)
+
+	// Кривая без создателя: комиссия создателя не взимается
+	out, protocol, creator := d.sellQuote(tokens, &curve)
+	assert.Zero(t, creator)
+	assert.Equal(t, gross-protocol, out)
+
+	curve.Creator = solana.NewWallet().PublicKey()
+	out, protocol, creator = d.sellQuote(tokens, &curve)
+	assert.Equal(t, feeOf(gross, 95), protocol)
+	assert.Equal(t, feeOf(gross, 30), creator)
+	assert.Equal(t, gross-protocol-creator, out)
+	assert.Equal(t, out, d.calculateSellSol(tokens, &curve))
+}
@@ -0,0 +1,117 @@
+package pumpfun
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errTooMuchSolRequired и errTooLittleSolReceived — ошибки программы Pump.fun при нарушении границ.
+var (
+	errTooMuchSolRequired   = errors.New("TooMuchSolRequired")
+	errTooLittleSolReceived = errors.New("TooLittleSolReceived")
+)
+
+const testFeeBps = 100
+
+// simulateBuy повторяет проверку инструкции buy программы: стоимость токенов
+// по текущей кривой плюс комиссия не должна превышать max_sol_cost из данных инструкции.
+func simulateBuy(curve BondingCurve, data []byte) error {
+	amount := binary.LittleEndian.Uint64(data[8:16])
+	maxSolCost := binary.LittleEndian.Uint64(data[16:24])
+
+	solCost := mulDiv(curve.VirtualSolReserves, amount, curve.VirtualTokenReserves-amount) + 1
+	fee := (solCost*testFeeBps + 9_999) / 10_000
+	if solCost+fee > maxSolCost {
+		return errTooMuchSolRequired
+	}
+	return nil
+}
+
+// simulateSell повторяет проверку инструкции sell: выход SOL за вычетом комиссии
+// не должен быть меньше min_sol_output.
+func simulateSell(curve BondingCurve, data []byte) error {
+	amount := binary.LittleEndian.Uint64(data[8:16])
+	minSolOutput := binary.LittleEndian.Uint64(data[16:24])
+
+	solOut := mulDiv(amount, curve.VirtualSolReserves, curve.VirtualTokenReserves+amount)
+	fee := (solOut*testFeeBps + 9_999) / 10_000
+	if solOut-fee < minSolOutput {
+		return errTooLittleSolReceived
+	}
+	return nil
+}
+
+// frontRun возвращает состояние кривой после чужой покупки на solIn lamports.
+func frontRun(curve BondingCurve, solIn uint64) BondingCurve {
+	tokens := mulDiv(curve.VirtualTokenReserves, solIn, curve.VirtualSolReserves+solIn)
+	curve.VirtualSolReserves += solIn
+	curve.VirtualTokenReserves -= tokens
+	return curve
+}
+
+// mulDiv вычисляет a*b/c в u128, как программа.
+func mulDiv(a, b, c uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	q, _ := bits.Div64(hi, lo, c)
+	return q
+}
+
+func testCurve() BondingCurve {
+	return BondingCurve{VirtualTokenReserves: 1_073_000_000_000_000, VirtualSolReserves: 30_000_000_000}
+}
+
+func TestBuyInstructionCarriesMaxSolCost(t *testing.T) {
+	d := &DEX{config: &Config{}}
+	curve := testCurve()
+	const solIn, slippage = 1_000_000_000, 5.0
+
+	tokens := d.calculateBuyTokens(solIn, &curve)
+	maxSolCost := model.GuardOrDefault(d.config.SlippageGuard).MaxIn(solIn, slippage)
+	ix := createBuyInstruction(PumpFunProgramID, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{},
+		solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{},
+		solana.PublicKey{}, TokenProgramID, tokens, maxSolCost)
+	data, err := ix.Data()
+	require.NoError(t, err)
+
+	assert.Equal(t, buyDiscriminator, data[:8])
+	assert.Equal(t, tokens, binary.LittleEndian.Uint64(data[8:16]))
+	assert.Equal(t, uint64(1_050_000_000), binary.LittleEndian.Uint64(data[16:24]))
+
+	assert.NoError(t, simulateBuy(curve, data), "quoted state fits the bound")
+	assert.NoError(t, simulateBuy(frontRun(curve, 300_000_000), data), "move within slippage passes")
+	assert.ErrorIs(t, simulateBuy(frontRun(curve, 3_000_000_000), data), errTooMuchSolRequired,
+		"the program rejects a fill past slippage")
+}
+
+func TestSellInstructionCarriesMinSolOutput(t *testing.T) {
+	d := &DEX{config: &Config{}}
+	curve := frontRun(testCurve(), 5_000_000_000)
+	const tokens, slippage = 20_000_000_000_000, 5.0
+
+	expected := d.calculateSellSol(tokens, &curve)
+	minOut := model.GuardOrDefault(d.config.SlippageGuard).MinOut(expected, slippage)
+	ix := createSellInstruction(PumpFunProgramID, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{},
+		solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{}, solana.PublicKey{},
+		solana.PublicKey{}, TokenProgramID, tokens, minOut)
+	data, err := ix.Data()
+	require.NoError(t, err)
+
+	assert.Equal(t, sellDiscriminator, data[:8])
+	assert.Equal(t, minOut, binary.LittleEndian.Uint64(data[16:24]))
+
+	// Чужая продажа перед нашей: резервы SOL уменьшаются
+	dumped := curve
+	dumped.VirtualSolReserves -= 2_000_000_000
+	dumped.VirtualTokenReserves += 80_000_000_000_000
+
+	assert.NoError(t, simulateSell(curve, data), "quoted state fits the bound")
+	assert.ErrorIs(t, simulateSell(dumped, data), errTooLittleSolReceived,
+		"the program rejects a fill past slippage")
+}
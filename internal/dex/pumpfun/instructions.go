@@ -11,8 +11,8 @@ import (
 
 // Constants for the Pump.fun protocol
 var (
+	buyDiscriminator         = []byte{0x66, 0x06, 0x3d, 0x12, 0x01, 0xda, 0xeb, 0xea}
 	sellDiscriminator        = []byte{0x33, 0xe6, 0x85, 0xa4, 0x01, 0x7f, 0x83, 0xad}
-	PumpFunExactSolProgramID = solana.MustPublicKeyFromBase58("6sbiyZ7mLKmYkES2AKYPHtg4FjQMaqVx3jTHez6ZtfmX")
 	PumpFunProgramID         = solana.MustPublicKeyFromBase58("6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P")
 	PumpFunEventAuth         = solana.MustPublicKeyFromBase58("Ce6TQqeHC9p8KetsN6JsjHK7UTZk7nasjjnr7XxXp9F1")
 	AssociatedTokenProgramID = solana.SPLAssociatedTokenAccountProgramID
@@ -39,43 +39,44 @@ func createExtendAccountInstruction(
 	return solana.NewInstruction(programID, accounts, extendDiscriminator)
 }
 
-// createBuyExactSolInstruction создаёт инструкцию для покупки токена за точное
-// количество SOL, включая новый creator_vault PDA.
-func createBuyExactSolInstruction(
+// createBuyInstruction создаёт инструкцию покупки tokenAmount токенов в протоколе Pump.fun.
+// Программа отклоняет транзакцию, если с учётом комиссий покупка стоит больше maxSolCost.
+// tokenProgram — программа токена mint: SPL Token или Token-2022.
+func createBuyInstruction(
+	programID,
 	global,
 	feeRecipient,
 	mint,
 	bondingCurve,
-	associatedBondingCurve,
+	associatedBC,
 	userATA,
 	userWallet,
 	creatorVault,
 	eventAuthority,
-	programID solana.PublicKey,
-	solAmountLamports uint64,
+	tokenProgram solana.PublicKey,
+	tokenAmount,
+	maxSolCost uint64,
 ) solana.Instruction {
-	// 1) Кодируем количество SOL в 8 байт
-	data := make([]byte, 8)
-	binary.LittleEndian.PutUint64(data, solAmountLamports)
+	data := make([]byte, 24)
+	copy(data[0:8], buyDiscriminator)
+	binary.LittleEndian.PutUint64(data[8:16], tokenAmount)
+	binary.LittleEndian.PutUint64(data[16:24], maxSolCost)
 
-	// 2) Формируем список аккаунтов в соответствии с новой схемой:
 	accounts := []*solana.AccountMeta{
 		solana.NewAccountMeta(global, false, false),
 		solana.NewAccountMeta(feeRecipient, true, false),
 		solana.NewAccountMeta(mint, false, false),
 		solana.NewAccountMeta(bondingCurve, true, false),
-		solana.NewAccountMeta(associatedBondingCurve, true, false),
+		solana.NewAccountMeta(associatedBC, true, false),
 		solana.NewAccountMeta(userATA, true, false),
 		solana.NewAccountMeta(userWallet, true, true),
 		solana.NewAccountMeta(SystemProgramID, false, false),
-		solana.NewAccountMeta(TokenProgramID, false, false),
-		solana.NewAccountMeta(creatorVault, true, false), // ← новый параметр
+		solana.NewAccountMeta(tokenProgram, false, false),
+		solana.NewAccountMeta(creatorVault, true, false),
 		solana.NewAccountMeta(eventAuthority, false, false),
 		solana.NewAccountMeta(programID, false, false),
 	}
-
-	// 3) Возвращаем инструкцию, указывая PID ExactSol
-	return solana.NewInstruction(PumpFunExactSolProgramID, accounts, data)
+	return solana.NewInstruction(programID, accounts, data)
 }
 
 // createSellInstruction создает инструкцию для продажи токенов в протоколе Pump.fun.
@@ -89,7 +90,8 @@ func createSellInstruction(
 	userATA,
 	userWallet,
 	creatorVault,
-	eventAuthority solana.PublicKey,
+	eventAuthority,
+	tokenProgram solana.PublicKey,
 	amount,
 	minSolOutput uint64,
 ) solana.Instruction {
@@ -108,7 +110,7 @@ func createSellInstruction(
 		{PublicKey: userWallet, IsWritable: true, IsSigner: true},
 		{PublicKey: SystemProgramID, IsWritable: false, IsSigner: false},
 		{PublicKey: creatorVault, IsWritable: true, IsSigner: false}, // ← сюда
-		{PublicKey: TokenProgramID, IsWritable: false, IsSigner: false},
+		{PublicKey: tokenProgram, IsWritable: false, IsSigner: false},
 		{PublicKey: eventAuthority, IsWritable: false, IsSigner: false},
 		{PublicKey: programID, IsWritable: false, IsSigner: false},
 	}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/gagliardetto/solana-go"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"sync"
 	"time"
@@ -32,6 +33,13 @@ type DEX struct {
 		data      *BondingCurve
 		fetchedAt time.Time
 	}
+
+	// Комиссии bonding curve из глобального аккаунта (нулевые — DefaultFees)
+	fees Fees
+
+	// ---------- программа токена и transfer fee Token-2022 ----------
+	mintMu    sync.Mutex
+	tokenMint *blockchain.TokenMint
 }
 
 // NewDEX создает новый экземпляр DEX для работы с Pump.fun.
@@ -71,11 +79,14 @@ func NewDEX(client *blockchain.Client, w *task.Wallet, logger *zap.Logger, confi
 		config.FeeRecipient = globalAccount.FeeRecipient
 		logger.Info("📧 Updated fee recipient: " + config.FeeRecipient.String())
 	}
+	dex.fees = FeesFromGlobal(globalAccount)
+	logger.Debug(fmt.Sprintf("🧾 Pump.fun fees: protocol %d bps, creator %d bps", dex.fees.ProtocolBps, dex.fees.CreatorBps))
 
 	return dex, nil
 }
 
-// ExecuteSnipe выполняет операцию покупки токена на Pump.fun с точным количеством SOL.
+// ExecuteSnipe выполняет операцию покупки токена на Pump.fun на amountSol SOL.
+// Стоимость покупки ограничена amountSol плюс slippagePercent и проверяется программой.
 func (d *DEX) ExecuteSnipe(ctx context.Context, amountSol float64, slippagePercent float64, priorityFeeSol string, computeUnits uint32) error {
 	// Логируем информацию о начале операции
 	d.logger.Info(fmt.Sprintf("💰 Starting Pump.fun buy: %.3f SOL (%.1f%% slippage)", amountSol, slippagePercent))
@@ -85,13 +96,13 @@ func (d *DEX) ExecuteSnipe(ctx context.Context, amountSol float64, slippagePerce
 	defer cancel()
 
 	// Конвертируем SOL в ламппорты (1 SOL = 10^9 ламппортов)
-	solAmountLamports := uint64(amountSol * 1_000_000_000)
+	solAmountLamports := model.SolToLamports(amountSol)
 
-	// Логируем точное количество SOL для покупки
-	d.logger.Info(fmt.Sprintf("📊 Using exact SOL amount: %.9f SOL", float64(solAmountLamports)/1_000_000_000))
+	// Логируем количество SOL для покупки
+	d.logger.Info(fmt.Sprintf("📊 Using SOL amount: %.9f SOL", float64(solAmountLamports)/1_000_000_000))
 
 	// Подготавливаем инструкции для транзакции покупки
-	instructions, err := d.prepareBuyTransaction(opCtx, solAmountLamports, priorityFeeSol, computeUnits)
+	instructions, err := d.prepareBuyTransaction(opCtx, solAmountLamports, slippagePercent, priorityFeeSol, computeUnits)
 	// TODO: пересмотреть логику solAmountLamports, priorityFeeSol, computeUnits. Данные должны брать из config.json and tasks.csv
 
 	if err != nil {
@@ -0,0 +1,122 @@
+// =============================
+// File: internal/dex/pumpfun/selfcheck.go
+// =============================
+package pumpfun
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"go.uber.org/zap"
+)
+
+// maxSaneFeeBasisPoints — верхняя граница правдоподобной комиссии протокола (10%).
+const maxSaneFeeBasisPoints = 1_000
+
+// ErrLayoutMismatch означает, что данные аккаунтов Pump.fun не проходят проверки
+// и, вероятно, формат аккаунтов изменился.
+var ErrLayoutMismatch = errors.New("pump.fun account layout mismatch")
+
+// bondingCurveDiscriminator — Anchor-дискриминатор аккаунта BondingCurve.
+var bondingCurveDiscriminator = func() []byte {
+	h := sha256.Sum256([]byte("account:BondingCurve"))
+	return h[:8]
+}()
+
+// SelfCheck проверяет, что глобальный аккаунт и (если задан) bonding curve
+// referenceMint разбираются в правдоподобные значения. Ошибки RPC возвращаются
+// как есть, а подозрение на смену формата — обернутым ErrLayoutMismatch.
+func SelfCheck(ctx context.Context, client *blockchain.Client, referenceMint solana.PublicKey, logger *zap.Logger) error {
+	globalAddr, _, err := solana.FindProgramAddress([][]byte{[]byte("global")}, PumpFunProgramID)
+	if err != nil {
+		return fmt.Errorf("derive global account: %w", err)
+	}
+
+	global, err := FetchGlobalAccount(ctx, client, globalAddr, logger)
+	if err != nil {
+		return err
+	}
+	if err := checkGlobalAccount(global); err != nil {
+		return err
+	}
+	logger.Debug("Pump.fun global account passed self-check",
+		zap.Uint64("fee_bps", global.FeeBasisPoints),
+		zap.Uint64("creator_fee_bps", global.CreatorFeeBasisPoints))
+
+	if referenceMint.IsZero() {
+		return nil
+	}
+
+	bcAddr, _, err := solana.FindProgramAddress(
+		[][]byte{[]byte("bonding-curve"), referenceMint.Bytes()},
+		PumpFunProgramID,
+	)
+	if err != nil {
+		return fmt.Errorf("derive bonding curve: %w", err)
+	}
+
+	info, err := client.GetAccountInfo(ctx, bcAddr)
+	if err != nil || info == nil || info.Value == nil {
+		// Кривой может еще не быть (или токен не с Pump.fun) — это не признак смены формата
+		logger.Debug("Reference bonding curve unavailable, skipping curve self-check",
+			zap.String("mint", referenceMint.String()))
+		return nil
+	}
+
+	if !info.Value.Owner.Equals(PumpFunProgramID) {
+		return nil
+	}
+
+	if err := checkBondingCurve(info.Value.Data.GetBinary()); err != nil {
+		return err
+	}
+	logger.Debug("Pump.fun bonding curve passed self-check", zap.String("bonding_curve", bcAddr.String()))
+	return nil
+}
+
+// checkGlobalAccount проверяет правдоподобность полей глобального аккаунта.
+func checkGlobalAccount(g *GlobalAccount) error {
+	switch {
+	case !g.Initialized:
+		return fmt.Errorf("%w: global account is not initialized", ErrLayoutMismatch)
+	case g.FeeRecipient.IsZero():
+		return fmt.Errorf("%w: global fee recipient is empty", ErrLayoutMismatch)
+	case g.FeeBasisPoints == 0 || g.FeeBasisPoints > maxSaneFeeBasisPoints:
+		return fmt.Errorf("%w: global fee_basis_points %d out of range", ErrLayoutMismatch, g.FeeBasisPoints)
+	case g.CreatorFeeBasisPoints > maxSaneFeeBasisPoints:
+		return fmt.Errorf("%w: creator_fee_basis_points %d out of range", ErrLayoutMismatch, g.CreatorFeeBasisPoints)
+	case g.InitialVirtualTokenRes == 0 || g.InitialVirtualSolRes == 0 || g.TokenTotalSupply == 0:
+		return fmt.Errorf("%w: global initial reserves are zero", ErrLayoutMismatch)
+	case g.InitialRealTokenRes > g.InitialVirtualTokenRes:
+		return fmt.Errorf("%w: initial real token reserves exceed virtual reserves", ErrLayoutMismatch)
+	}
+	return nil
+}
+
+// checkBondingCurve проверяет дискриминатор и правдоподобность резервов bonding curve.
+func checkBondingCurve(raw []byte) error {
+	if len(raw) < 8 || string(raw[:8]) != string(bondingCurveDiscriminator) {
+		return fmt.Errorf("%w: unexpected bonding curve discriminator", ErrLayoutMismatch)
+	}
+
+	bc, err := parseBondingCurve(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrLayoutMismatch, err)
+	}
+
+	switch {
+	case bc.TokenTotalSupply == 0:
+		return fmt.Errorf("%w: bonding curve total supply is zero", ErrLayoutMismatch)
+	case !bc.Complete && (bc.VirtualTokenReserves == 0 || bc.VirtualSolReserves == 0):
+		return fmt.Errorf("%w: active bonding curve has zero virtual reserves", ErrLayoutMismatch)
+	case bc.RealTokenReserves > bc.VirtualTokenReserves:
+		return fmt.Errorf("%w: real token reserves exceed virtual reserves", ErrLayoutMismatch)
+	case bc.RealSolReserves > bc.VirtualSolReserves:
+		return fmt.Errorf("%w: real SOL reserves exceed virtual reserves", ErrLayoutMismatch)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package pumpfun
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// validGlobal возвращает глобальный аккаунт с правдоподобными значениями mainnet.
+func validGlobal() *GlobalAccount {
+	return &GlobalAccount{
+		Initialized:            true,
+		FeeRecipient:           solana.NewWallet().PublicKey(),
+		FeeBasisPoints:         95,
+		CreatorFeeBasisPoints:  5,
+		InitialVirtualTokenRes: 1_073_000_000_000_000,
+		InitialVirtualSolRes:   30_000_000_000,
+		InitialRealTokenRes:    793_100_000_000_000,
+		TokenTotalSupply:       1_000_000_000_000_000,
+	}
+}
+
+func TestCheckGlobalAccount(t *testing.T) {
+	for name, tc := range map[string]struct {
+		edit    func(g *GlobalAccount)
+		wantErr string
+	}{
+		"valid":               {edit: func(*GlobalAccount) {}},
+		"not initialized":     {edit: func(g *GlobalAccount) { g.Initialized = false }, wantErr: "global account is not initialized"},
+		"empty fee recipient": {edit: func(g *GlobalAccount) { g.FeeRecipient = solana.PublicKey{} }, wantErr: "global fee recipient is empty"},
+		"zero fee":            {edit: func(g *GlobalAccount) { g.FeeBasisPoints = 0 }, wantErr: "global fee_basis_points 0 out of range"},
+		"fee above 10%":       {edit: func(g *GlobalAccount) { g.FeeBasisPoints = 1_001 }, wantErr: "global fee_basis_points 1001 out of range"},
+		"fee at 10%":          {edit: func(g *GlobalAccount) { g.FeeBasisPoints = maxSaneFeeBasisPoints }},
+		"creator fee above 10%": {
+			edit:    func(g *GlobalAccount) { g.CreatorFeeBasisPoints = 5_000 },
+			wantErr: "creator_fee_basis_points 5000 out of range",
+		},
+		"zero initial reserves": {edit: func(g *GlobalAccount) { g.InitialVirtualSolRes = 0 }, wantErr: "global initial reserves are zero"},
+		"real reserves above virtual": {
+			edit:    func(g *GlobalAccount) { g.InitialRealTokenRes = g.InitialVirtualTokenRes + 1 },
+			wantErr: "initial real token reserves exceed virtual reserves",
+		},
+	} {
+		g := validGlobal()
+		tc.edit(g)
+		err := checkGlobalAccount(g)
+		if tc.wantErr == "" {
+			assert.NoError(t, err, name)
+			continue
+		}
+		assert.ErrorIs(t, err, ErrLayoutMismatch, name)
+		assert.EqualError(t, err, ErrLayoutMismatch.Error()+": "+tc.wantErr, name)
+	}
+}
+
+// curveData собирает аккаунт bonding curve с дискриминатором и заданными резервами.
+func curveData(virtualToken, virtualSol, realToken, realSol, supply uint64, complete bool) []byte {
+	raw := make([]byte, 8+41+32)
+	copy(raw, bondingCurveDiscriminator)
+	data := raw[8:]
+	binary.LittleEndian.PutUint64(data[0:], virtualToken)
+	binary.LittleEndian.PutUint64(data[8:], virtualSol)
+	binary.LittleEndian.PutUint64(data[16:], realToken)
+	binary.LittleEndian.PutUint64(data[24:], realSol)
+	binary.LittleEndian.PutUint64(data[32:], supply)
+	if complete {
+		data[40] = 1
+	}
+	return raw
+}
+
+func TestCheckBondingCurve(t *testing.T) {
+	const supply = 1_000_000_000_000_000
+	badDiscriminator := curveData(1_073_000_000_000_000, 30_000_000_000, 793_100_000_000_000, 0, supply, false)
+	badDiscriminator[0] ^= 0xff
+
+	for name, tc := range map[string]struct {
+		raw     []byte
+		wantErr string
+	}{
+		"valid active curve":                 {raw: curveData(1_073_000_000_000_000, 30_000_000_000, 793_100_000_000_000, 0, supply, false)},
+		"completed curve with zero reserves": {raw: curveData(0, 0, 0, 0, supply, true)},
+		"bad discriminator":                  {raw: badDiscriminator, wantErr: "unexpected bonding curve discriminator"},
+		"shorter than the discriminator":     {raw: bondingCurveDiscriminator[:4], wantErr: "unexpected bonding curve discriminator"},
+		"truncated fields": {
+			raw:     curveData(1, 1, 0, 0, supply, false)[:40],
+			wantErr: "bonding curve data too short for basic fields: 40 bytes",
+		},
+		"zero supply":                     {raw: curveData(1_073_000_000_000_000, 30_000_000_000, 0, 0, 0, false), wantErr: "bonding curve total supply is zero"},
+		"active curve with zero reserves": {raw: curveData(0, 0, 0, 0, supply, false), wantErr: "active bonding curve has zero virtual reserves"},
+		"real tokens above virtual": {
+			raw:     curveData(1_000, 30_000_000_000, 1_001, 0, supply, false),
+			wantErr: "real token reserves exceed virtual reserves",
+		},
+		"real SOL above virtual": {
+			raw:     curveData(1_073_000_000_000_000, 1_000, 0, 1_001, supply, false),
+			wantErr: "real SOL reserves exceed virtual reserves",
+		},
+	} {
+		err := checkBondingCurve(tc.raw)
+		if tc.wantErr == "" {
+			assert.NoError(t, err, name)
+			continue
+		}
+		assert.ErrorIs(t, err, ErrLayoutMismatch, name)
+		assert.EqualError(t, err, ErrLayoutMismatch.Error()+": "+tc.wantErr, name)
+	}
+}
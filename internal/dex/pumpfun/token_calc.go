@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
 	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
 	"math"
+	"math/big"
 	"strconv"
 	"time"
 
@@ -20,8 +22,6 @@ const (
 	tokenDecimals = 6
 	// Минимальная цена для предотвращения деления на ноль или слишком малых значений
 	minPriceThreshold = 1e-18 // Очень маленькое значение, близкое к нулю
-	// Базовая комиссия протокола Pump.fun
-	protocolFeePercent = 1.0 // 1% комиссия протокола
 )
 
 // CalculateTokenPrice рассчитывает текущую спотовую цену токена на основе виртуальных резервов bonding curve.
@@ -100,7 +100,15 @@ func (d *DEX) GetTokenPrice(ctx context.Context, tokenMint string) (float64, err
 func (d *DEX) GetTokenBalance(ctx context.Context, tokenMint string) (uint64, error) {
 	// Шаг 1: Вычисление адреса ассоциированного токен-аккаунта (ATA)
 	mint := solana.MustPublicKeyFromBase58(tokenMint)
-	userATA, _, err := solana.FindAssociatedTokenAddress(d.wallet.PublicKey, mint)
+	program := TokenProgramID
+	if mint.Equals(d.config.Mint) {
+		detected, err := d.tokenProgram(ctx)
+		if err != nil {
+			return 0, err
+		}
+		program = detected
+	}
+	userATA, err := blockchain.FindAssociatedTokenAddress(d.wallet.PublicKey, mint, program)
 	if err != nil {
 		return 0, fmt.Errorf("failed to derive associated token account: %w", err)
 	}
@@ -143,17 +151,24 @@ func (d *DEX) GetTokenBalance(ctx context.Context, tokenMint string) (uint64, er
 	return balance, nil
 }
 
-// calculateEstimate возвращает прогнозный выход SOL за tokenAmount,
-// с учётом только протокольной комиссии.
+// saleEstimate — прогноз продажи: выход SOL и удержанные из него комиссии.
+type saleEstimate struct {
+	Sol         float64 // Выход за вычетом комиссий, SOL
+	ProtocolFee float64 // Комиссия протокола, SOL
+	CreatorFee  float64 // Комиссия создателя токена, SOL
+}
+
+// calculateEstimate возвращает прогнозный выход SOL за tokenAmount с учетом
+// комиссий протокола и создателя токена.
 func (d *DEX) calculateEstimate(
 	ctx context.Context,
 	tokenAmount float64,
 	reserves interface{},
-) (float64, error) {
+) (saleEstimate, error) {
 	// Приводим reserves к типу BondingCurve
 	bondingCurveData, ok := reserves.(*BondingCurve)
 	if !ok {
-		return 0, fmt.Errorf("invalid reserves type for pumpfun: %T", reserves)
+		return saleEstimate{}, fmt.Errorf("invalid reserves type for pumpfun: %T", reserves)
 	}
 
 	// Проверка на нулевые резервы
@@ -161,69 +176,135 @@ func (d *DEX) calculateEstimate(
 		d.logger.Warn("Invalid reserve state with zero reserves",
 			zap.Uint64("VirtualTokenReserves", bondingCurveData.VirtualTokenReserves),
 			zap.Uint64("VirtualSolReserves", bondingCurveData.VirtualSolReserves))
-		return 0, nil
+		return saleEstimate{}, nil
 	}
 
-	// 1. Переводим tokenAmount в raw
-	tokenAmountRaw := uint64(tokenAmount * math.Pow10(tokenDecimals))
-
-	// 2. Считаем lamports через формулу AMM
-	solAmountLamports := float64(tokenAmountRaw) * float64(bondingCurveData.VirtualSolReserves) /
-		float64(bondingCurveData.VirtualTokenReserves+tokenAmountRaw)
+	// 1. Переводим tokenAmount в raw (за вычетом transfer fee Token-2022)
+	tokenAmountRaw := d.getTransferFee(ctx).Net(uint64(tokenAmount * math.Pow10(tokenDecimals)))
 
-	// 3. Учитываем комиссию протокола (1%)
-	solAfterFees := solAmountLamports * (1.0 - (protocolFeePercent / 100.0))
+	// 2. Считаем выход по кривой и комиссии так же, как программа
+	solOut, protocolFee, creatorFee := d.sellQuote(tokenAmountRaw, bondingCurveData)
 
-	// 4. Конвертируем из lamports в SOL
-	solOutput := solAfterFees / math.Pow10(solDecimals)
+	estimate := saleEstimate{
+		Sol:         float64(solOut) / math.Pow10(solDecimals),
+		ProtocolFee: float64(protocolFee) / math.Pow10(solDecimals),
+		CreatorFee:  float64(creatorFee) / math.Pow10(solDecimals),
+	}
 
 	d.logger.Debug("Calculated expected SOL output",
 		zap.Float64("token_amount", tokenAmount),
-		zap.Float64("token_amount_raw", float64(tokenAmountRaw)),
-		zap.Float64("sol_amount_lamports_before_fee", solAmountLamports),
-		zap.Float64("fee_percentage", protocolFeePercent),
-		zap.Float64("sol_after_fees_lamports", solAfterFees),
-		zap.Float64("sol_output", solOutput))
+		zap.Uint64("token_amount_raw", tokenAmountRaw),
+		zap.Uint64("protocol_fee_lamports", protocolFee),
+		zap.Uint64("creator_fee_lamports", creatorFee),
+		zap.Float64("sol_output", estimate.Sol))
 
-	return solOutput, nil
+	return estimate, nil
 }
 
-// calculateMinSolOutput вычисляет минимальный ожидаемый выход SOL при продаже токенов
-// с учетом заданного допустимого проскальзывания.
-// Сохранено для совместимости с trade.go.
-func (d *DEX) calculateMinSolOutput(tokenAmount uint64, bondingCurveData *BondingCurve, slippagePercent float64) uint64 {
-	// Формула из Python SDK: (tokens * virtual_sol_reserves) / (virtual_token_reserves + tokens)
-	solAmount := (tokenAmount * bondingCurveData.VirtualSolReserves) /
-		(bondingCurveData.VirtualTokenReserves + tokenAmount)
+// getTokenMint возвращает программу токена и его комиссию за перевод. Удачный ответ
+// кэшируется на время жизни DEX, после ошибки RPC mint запрашивается снова.
+func (d *DEX) getTokenMint(ctx context.Context) (*blockchain.TokenMint, error) {
+	d.mintMu.Lock()
+	defer d.mintMu.Unlock()
+	if d.tokenMint != nil {
+		return d.tokenMint, nil
+	}
+	mint, err := d.client.GetTokenMint(ctx, d.config.Mint)
+	if err != nil {
+		return nil, err
+	}
+	if mint.IsToken2022() {
+		d.logger.Info("🪙 Token uses the Token-2022 program")
+	}
+	if mint.TransferFee != nil {
+		d.logger.Info(fmt.Sprintf("🧾 Token has a %.2f%% transfer fee", mint.TransferFee.Percent()))
+	}
+	d.tokenMint = mint
+	return mint, nil
+}
+
+// getTransferFee возвращает комиссию Token-2022 за перевод токена (nil, если ее нет).
+func (d *DEX) getTransferFee(ctx context.Context) *blockchain.TransferFee {
+	mint, err := d.getTokenMint(ctx)
+	if err != nil {
+		d.logger.Debug("Failed to read mint transfer fee", zap.Error(err))
+		return nil
+	}
+	return mint.TransferFee
+}
+
+// tokenProgram возвращает программу токена: от нее зависят адреса ATA и аккаунты инструкций.
+func (d *DEX) tokenProgram(ctx context.Context) (solana.PublicKey, error) {
+	mint, err := d.getTokenMint(ctx)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to detect token program: %w", err)
+	}
+	return mint.Program, nil
+}
+
+// curveFees возвращает комиссии сделок по кривой bondingCurveData.
+func (d *DEX) curveFees(bondingCurveData *BondingCurve) Fees {
+	fees := d.fees
+	if fees == (Fees{}) {
+		fees = DefaultFees
+	}
+	return fees.forCurve(bondingCurveData)
+}
+
+// calculateBuyTokens вычисляет ожидаемое количество токенов (raw) за solAmountLamports
+// по формуле bonding curve с учетом комиссий протокола и создателя (см. quoteBuy).
+func (d *DEX) calculateBuyTokens(solAmountLamports uint64, bondingCurveData *BondingCurve) uint64 {
+	return d.quoteBuy(solAmountLamports, bondingCurveData)
+}
+
+// calculateSellSol вычисляет ожидаемый выход SOL (lamports) при продаже токенов
+// за вычетом комиссий. Slippage применяется отдельно, через SlippageGuard.
+func (d *DEX) calculateSellSol(tokenAmount uint64, bondingCurveData *BondingCurve) uint64 {
+	solOut, _, _ := d.sellQuote(tokenAmount, bondingCurveData)
+	return solOut
+}
 
-	// Применяем фиксированную комиссию протокола
-	expectedSolValueLamports := uint64(float64(solAmount) * (1.0 - (protocolFeePercent / 100.0)))
+// sellQuote вычисляет выход SOL (lamports) от продажи tokenAmount и удержанные
+// из него комиссии протокола и создателя.
+func (d *DEX) sellQuote(tokenAmount uint64, bondingCurveData *BondingCurve) (solOut, protocolFee, creatorFee uint64) {
+	// Формула из Python SDK: (tokens * virtual_sol_reserves) / (virtual_token_reserves + tokens)
+	// Произведение не помещается в uint64 уже при продаже нескольких миллионов токенов
+	num := new(big.Int).Mul(new(big.Int).SetUint64(tokenAmount), new(big.Int).SetUint64(bondingCurveData.VirtualSolReserves))
+	den := new(big.Int).Add(new(big.Int).SetUint64(bondingCurveData.VirtualTokenReserves), new(big.Int).SetUint64(tokenAmount))
+	if den.Sign() == 0 {
+		return 0, 0, 0
+	}
+	gross := num.Quo(num, den).Uint64()
 
-	// Применяем допустимое проскальзывание
-	slippageFactor := 1.0 - (slippagePercent / 100.0)
-	return uint64(float64(expectedSolValueLamports) * slippageFactor)
+	protocolFee, creatorFee = d.curveFees(bondingCurveData).Split(gross)
+	if protocolFee+creatorFee >= gross {
+		return 0, protocolFee, creatorFee
+	}
+	return gross - protocolFee - creatorFee, protocolFee, creatorFee
 }
 
 // CalculatePnL вычисляет прибыль/убыток (PnL) для указанного количества токенов и начальной инвестиции.
-// Расчет учитывает только комиссию протокола. Slippage не учитывается.
+// Расчет учитывает комиссии протокола и создателя токена, поэтому выручка совпадает
+// с SOL, которые придут при продаже по текущей кривой. Slippage не учитывается.
 func (d *DEX) CalculatePnL(ctx context.Context, tokenAmount float64, initialInvestment float64) (*model.PnLResult, error) {
-	// 1. Учитываем buy-fee при вычислении costBasis
-	buyFee := initialInvestment * (protocolFeePercent / 100)
-	costBasis := initialInvestment - buyFee
-
-	// 2. Получаем данные bonding curve
+	// 1. Получаем данные bonding curve
 	bondingCurveData, _, err := d.getBondingCurveData(ctx)
 	if err != nil {
 		d.logger.Warn("Failed to fetch bonding curve data, assuming zero reserves", zap.Error(err))
 		bondingCurveData = &BondingCurve{}
 	}
 
-	// 3. Рассчитываем ожидаемую выручку от продажи с учетом комиссии протокола
-	sellEstimate, err := d.calculateEstimate(ctx, tokenAmount, bondingCurveData)
+	// 2. Учитываем buy-fee при вычислении costBasis
+	buyFee := initialInvestment * (d.curveFees(bondingCurveData).Percent() / 100)
+	costBasis := initialInvestment - buyFee
+
+	// 3. Рассчитываем ожидаемую выручку от продажи за вычетом комиссий
+	estimate, err := d.calculateEstimate(ctx, tokenAmount, bondingCurveData)
 	if err != nil {
 		d.logger.Warn("Error calculating sell estimate", zap.Error(err))
-		sellEstimate = 0
+		estimate = saleEstimate{}
 	}
+	sellEstimate := estimate.Sol
 
 	// 4. Рассчитываем чистый PnL
 	netPnL := sellEstimate - costBasis
@@ -251,6 +332,8 @@ func (d *DEX) CalculatePnL(ctx context.Context, tokenAmount float64, initialInve
 		InitialInvestment: costBasis,
 		NetPnL:            netPnL,
 		PnLPercentage:     pnlPercentage,
+		ProtocolFee:       estimate.ProtocolFee,
+		CreatorFee:        estimate.CreatorFee,
 	}, nil
 }
 
@@ -276,8 +359,8 @@ func (d *DEX) SellPercentTokens(ctx context.Context, tokenMint string, percentTo
 		return fmt.Errorf("no tokens to sell")
 	}
 
-	// Рассчитываем количество токенов для продажи на основе процента
-	tokensToSell := uint64(float64(tokenBalance) * (percentToSell / 100.0))
+	// Рассчитываем количество токенов для продажи на основе процента (целочисленно, с округлением вниз)
+	tokensToSell := model.PercentOf(tokenBalance, percentToSell)
 
 	// Логируем информацию о продаже
 	d.logger.Info("Selling tokens",
@@ -289,3 +372,54 @@ func (d *DEX) SellPercentTokens(ctx context.Context, tokenMint string, percentTo
 	// Выполняем продажу рассчитанного количества токенов
 	return d.ExecuteSell(ctx, tokensToSell, slippagePercent, priorityFeeSol, computeUnits)
 }
+
+// SellTokenAmount продает ровно amount базовых единиц токена, если они есть на балансе.
+func (d *DEX) SellTokenAmount(ctx context.Context, tokenMint string, amount uint64, slippagePercent float64, priorityFeeSol string, computeUnits uint32) error {
+	if amount == 0 {
+		return fmt.Errorf("amount to sell must be positive")
+	}
+
+	balanceCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tokenBalance, err := d.GetTokenBalance(balanceCtx, tokenMint)
+	if err != nil {
+		return fmt.Errorf("failed to get token balance: %w", err)
+	}
+	if tokenBalance < amount {
+		return fmt.Errorf("balance %d is below the amount to sell %d", tokenBalance, amount)
+	}
+
+	d.logger.Info("Selling tokens",
+		zap.String("token_mint", tokenMint),
+		zap.Uint64("total_balance", tokenBalance),
+		zap.Uint64("tokens_to_sell", amount))
+
+	return d.ExecuteSell(ctx, amount, slippagePercent, priorityFeeSol, computeUnits)
+}
+
+// SolReserves возвращает реальные резервы SOL bonding curve в лампортах и признак
+// завершения curve.
+func (d *DEX) SolReserves(ctx context.Context) (lamports uint64, complete bool, err error) {
+	bc, _, err := d.getBondingCurveData(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get bonding curve data: %w", err)
+	}
+	return bc.RealSolReserves, bc.Complete, nil
+}
+
+// PriceAccounts возвращает bonding curve: цена меняется только вместе с ней.
+func (d *DEX) PriceAccounts(ctx context.Context) ([]solana.PublicKey, error) {
+	bcAddr, _, err := d.deriveBondingCurveAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []solana.PublicKey{bcAddr}, nil
+}
+
+// InvalidatePrice сбрасывает кеш bonding curve: следующий расчет цены читает цепочку.
+func (d *DEX) InvalidatePrice() {
+	d.bcCache.mu.Lock()
+	d.bcCache.fetchedAt = time.Time{}
+	d.bcCache.mu.Unlock()
+}
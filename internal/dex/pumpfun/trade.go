@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
 	"go.uber.org/zap"
 )
 
@@ -17,6 +19,7 @@ import (
 func (d *DEX) prepareBuyTransaction(
 	ctx context.Context,
 	solAmountLamports uint64,
+	slippagePercent float64,
 	priorityFeeSol string,
 	computeUnits uint32,
 ) ([]solana.Instruction, error) {
@@ -31,6 +34,18 @@ func (d *DEX) prepareBuyTransaction(
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare bonding curve data: %w", err)
 	}
+	program, err := d.tokenProgram(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokenAmount := d.calculateBuyTokens(solAmountLamports, bcData)
+	if tokenAmount == 0 {
+		return nil, fmt.Errorf("bonding curve has no reserves to buy from")
+	}
+	if impact := d.priceImpact(solAmountLamports, tokenAmount, bcData); impact >= 1 {
+		d.logger.Info(fmt.Sprintf("📈 Buy moves the curve: %.2f%% above the spot price", impact))
+	}
+	execution.FromContext(ctx).SetQuote(d.getTransferFee(ctx).Net(tokenAmount))
 
 	// 3) Проверяем, нужно ли добавить extend_account
 	info, err := d.client.GetAccountInfo(ctx, bcAddr)
@@ -57,8 +72,10 @@ func (d *DEX) prepareBuyTransaction(
 	d.logger.Info("Using creator vault", zap.String("vault", creatorVault.String()),
 		zap.String("creator", bcData.Creator.String()))
 
-	// 5) Формируем основную инструкцию buy
-	buyIx := createBuyExactSolInstruction(
+	// 5) Формируем инструкцию buy: верхняя граница стоимости проверяется программой on-chain
+	maxSolCost := model.GuardOrDefault(d.config.SlippageGuard).MaxIn(solAmountLamports, slippagePercent)
+	buyIx := createBuyInstruction(
+		d.config.ContractAddress,
 		d.config.Global,
 		d.config.FeeRecipient,
 		d.config.Mint,
@@ -68,8 +85,9 @@ func (d *DEX) prepareBuyTransaction(
 		d.wallet.PublicKey,
 		creatorVault,
 		d.config.EventAuthority,
-		d.config.ContractAddress,
-		solAmountLamports,
+		program,
+		tokenAmount,
+		maxSolCost,
 	)
 
 	// 6) Собираем и возвращаем все инструкции
@@ -97,6 +115,10 @@ func (d *DEX) prepareSellTransaction(
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare bonding curve data: %w", err)
 	}
+	program, err := d.tokenProgram(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// 3) Проверяем, нужно ли добавить extend_account
 	info, err := d.client.GetAccountInfo(ctx, bondingCurve)
@@ -123,8 +145,14 @@ func (d *DEX) prepareSellTransaction(
 	d.logger.Info("Using creator vault for sell", zap.String("vault", creatorVault.String()),
 		zap.String("creator", bcData.Creator.String()))
 
-	// 5) Рассчитываем минимальный выход SOL с учётом слиппэджа
-	minSolOutput := d.calculateMinSolOutput(tokenAmount, bcData, slippagePercent)
+	// 5) Рассчитываем минимальный выход SOL с учётом слиппэджа: он проверяется программой on-chain
+	// Для Token-2022 с transfer fee до кривой дойдет меньше токенов, чем списано
+	netTokens := d.getTransferFee(ctx).Net(tokenAmount)
+	expectedSol, protocolFee, creatorFee := d.sellQuote(netTokens, bcData)
+	minSolOutput := model.GuardOrDefault(d.config.SlippageGuard).MinOut(expectedSol, slippagePercent)
+	trace := execution.FromContext(ctx)
+	trace.SetQuote(expectedSol)
+	trace.SetVenueFees(protocolFee, creatorFee)
 
 	// 6) Формируем sell-инструкцию
 	sellIx := createSellInstruction(
@@ -138,6 +166,7 @@ func (d *DEX) prepareSellTransaction(
 		d.wallet.PublicKey,
 		creatorVault,
 		d.config.EventAuthority,
+		program,
 		tokenAmount,
 		minSolOutput,
 	)
@@ -12,15 +12,17 @@ import (
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
 )
 
-// prepareTransactionContext создает контекст с таймаутом для операции.
+// prepareTransactionContext создает контекст с таймаутом для операции,
+// если вызывающий не задал бюджет сделки.
 func (d *DEX) prepareTransactionContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
-	return context.WithTimeout(ctx, timeout)
+	return execution.StageContext(ctx, timeout)
 }
 
 // prepareBaseInstructions подготавливает базовые инструкции для транзакции.
-func (d *DEX) prepareBaseInstructions(_ context.Context, priorityFeeSol string, computeUnits uint32) ([]solana.Instruction, solana.PublicKey, error) {
+func (d *DEX) prepareBaseInstructions(ctx context.Context, priorityFeeSol string, computeUnits uint32) ([]solana.Instruction, solana.PublicKey, error) {
 	var instructions []solana.Instruction
 
 	// Set compute unit limit
@@ -31,9 +33,18 @@ func (d *DEX) prepareBaseInstructions(_ context.Context, priorityFeeSol string,
 
 	// Handle priority fee
 	var priorityFee uint64
-	if priorityFeeSol == "default" {
+	switch {
+	case priorityFeeSol == "default":
 		priorityFee = 5_000 // Default priority fee (5000 micro-lamports)
-	} else {
+	case blockchain.IsAutoFee(priorityFeeSol):
+		// Рекомендация провайдера по аккаунтам программы и токена
+		fee, err := d.client.PriorityFee(ctx, priorityFeeSol, []solana.PublicKey{d.config.ContractAddress, d.config.Mint})
+		if err != nil {
+			d.logger.Warn("⚠️  Priority fee estimate failed, using default: " + err.Error())
+			fee = 5_000
+		}
+		priorityFee = fee
+	default:
 		var solValue float64
 		if _, err := fmt.Sscanf(priorityFeeSol, "%f", &solValue); err != nil {
 			return nil, solana.PublicKey{}, fmt.Errorf("invalid priority fee format: %w", err)
@@ -42,62 +53,93 @@ func (d *DEX) prepareBaseInstructions(_ context.Context, priorityFeeSol string,
 	}
 
 	instructions = append(instructions, computebudget.NewSetComputeUnitPriceInstruction(priorityFee).Build())
+	execution.FromContext(ctx).SetPriorityFee(priorityFee, computeUnits)
 
-	// Create ATA instruction
-	userATA, _, err := solana.FindAssociatedTokenAddress(d.wallet.PublicKey, d.config.Mint)
+	// Create ATA instruction: адрес и программа зависят от того, SPL Token это или Token-2022
+	program, err := d.tokenProgram(ctx)
+	if err != nil {
+		return nil, solana.PublicKey{}, err
+	}
+	userATA, err := blockchain.FindAssociatedTokenAddress(d.wallet.PublicKey, d.config.Mint, program)
 	if err != nil {
 		return nil, solana.PublicKey{}, fmt.Errorf("failed to derive associated token account: %w", err)
 	}
 
-	ataInstruction := d.wallet.CreateAssociatedTokenAccountIdempotentInstruction(
-		d.wallet.PublicKey, d.wallet.PublicKey, d.config.Mint)
+	ataInstruction := d.wallet.CreateTokenAccountIdempotentInstruction(
+		d.wallet.Payer(), d.wallet.PublicKey, d.config.Mint, program)
 	instructions = append(instructions, ataInstruction)
 
 	return instructions, userATA, nil
 }
 
 // sendAndConfirmTransaction создает, подписывает, отправляет и ожидает подтверждения транзакции.
+// Если транзакция долго не подтверждается, клиент переотправляет ее с повышенной ценой CU.
 func (d *DEX) sendAndConfirmTransaction(ctx context.Context, instructions []solana.Instruction) (solana.Signature, error) {
-	// 1) blockhash
-	blockhash, err := d.client.GetRecentBlockhash(ctx)
-	if err != nil {
-		return solana.Signature{}, fmt.Errorf("get recent blockhash: %w", err)
-	}
+	trace := execution.FromContext(ctx)
 
-	// 2) сборка готовой транзакции
-	tx, err := solana.NewTransaction(
-		instructions,
-		blockhash,
-		solana.TransactionPayer(d.wallet.PublicKey),
-		// сюда же при необходимости ALT:
-		// solana.TransactionWithAddressLookupTables(d.addressTables...),
-	)
+	// 1) blockhash — общий для всех версий транзакции, чтобы они истекали одновременно
+	blockhash, fetchedAt, err := d.client.LatestBlockhash(ctx)
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("create transaction: %w", err)
+		return solana.Signature{}, fmt.Errorf("get recent blockhash: %w", err)
 	}
-
-	// 3) подпись
-	if err := d.wallet.SignTransaction(tx); err != nil {
-		return solana.Signature{}, fmt.Errorf("sign transaction: %w", err)
+	trace.SetBlockhash(fetchedAt)
+	trace.MarkStage(execution.StageBlockhash)
+
+	// 2) сборка и подпись; при повторной отправке меняется цена CU, а после истечения
+	// blockhash — сам blockhash
+	signed := false
+	build := func(ixs []solana.Instruction, recent solana.Hash) (*solana.Transaction, error) {
+		tx, err := solana.NewTransaction(
+			ixs,
+			recent,
+			solana.TransactionPayer(d.wallet.Payer()),
+			// сюда же при необходимости ALT:
+			// solana.TransactionWithAddressLookupTables(d.addressTables...),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create transaction: %w", err)
+		}
+		if err := d.wallet.SignTransaction(ctx, tx); err != nil {
+			return nil, fmt.Errorf("sign transaction: %w", err)
+		}
+		if !signed {
+			signed = true
+			trace.MarkStage(execution.StageSigned)
+		}
+		return tx, nil
 	}
 
-	// 4) отправка с опциями для ускорения обработки
+	// 3) отправка с опциями для ускорения обработки и
+	// 4) ожидание подтверждения (используем CommitmentProcessed для быстрого подтверждения)
 	txOpts := blockchain.TransactionOptions{
 		SkipPreflight:       true,
 		PreflightCommitment: rpc.CommitmentProcessed,
 	}
-	sig, err := d.client.SendTransactionWithOpts(ctx, tx, txOpts)
+	landed, err := d.client.SendAndConfirm(ctx, instructions, blockhash, build, txOpts, rpc.CommitmentProcessed, func(a blockchain.SendAttempt) {
+		switch {
+		case a.Attempt == 0 && a.Resubmission == 0:
+			d.logger.Info("📤 Transaction sent: " + a.Signature.String()[:8] + "...")
+			trace.MarkSent(a.Signature)
+			return
+		case a.Attempt == 0:
+			d.logger.Info(fmt.Sprintf("♻️  Resubmitted #%d with a fresh blockhash: %s...", a.Resubmission, a.Signature.String()[:8]))
+		default:
+			d.logger.Info(fmt.Sprintf("🔁 Rebroadcast #%d at %d micro-lamports: %s...", a.Attempt, a.MicroLamports, a.Signature.String()[:8]))
+		}
+		trace.MarkRebroadcast(a.Signature)
+	})
+	if landed.Signature.IsZero() {
+		return solana.Signature{}, err
+	}
 	if err != nil {
-		return solana.Signature{}, fmt.Errorf("send transaction: %w", err)
+		d.logger.Warn("⚠️  Confirmation failed for " + landed.Signature.String()[:8] + "...: " + err.Error())
+		return landed.Signature, fmt.Errorf("confirmation failed: %w", err)
 	}
-	d.logger.Info("📤 Transaction sent: " + sig.String()[:8] + "...")
-
-	// 5) ожидание подтверждения (используем CommitmentProcessed для быстрого подтверждения)
-	if err := d.client.WaitForTransactionConfirmation(ctx, sig, rpc.CommitmentProcessed); err != nil {
-		d.logger.Warn("⚠️  Confirmation failed for " + sig.String()[:8] + "...: " + err.Error())
-		return sig, fmt.Errorf("confirmation failed: %w", err)
+	d.logger.Info("✅ Transaction confirmed: " + landed.Signature.String()[:8] + "...")
+	if landed.Attempt > 0 || landed.Resubmission > 0 {
+		trace.SetLanded(landed.Signature, landed.MicroLamports)
 	}
-	d.logger.Info("✅ Transaction confirmed: " + sig.String()[:8] + "...")
+	trace.MarkConfirmed()
 
-	return sig, nil
+	return landed.Signature, nil
 }
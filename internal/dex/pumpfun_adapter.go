@@ -42,7 +42,7 @@ func (d *pumpfunDEXAdapter) Execute(ctx context.Context, t *task.Task) error {
 			return d.inner.ExecuteSell(ctx, bal, t.SlippagePercent, t.PriorityFeeSol, t.ComputeUnits)
 		}
 		// fallback: конвертируем SOL в лампорты
-		lamports := uint64(t.AmountSol * 1e9)
+		lamports := model.SolToLamports(t.AmountSol)
 		return d.inner.ExecuteSell(ctx, lamports, t.SlippagePercent, t.PriorityFeeSol, t.ComputeUnits)
 	default:
 		return fmt.Errorf("unsupported operation %s on Pump.fun", t.Operation)
@@ -73,6 +73,14 @@ func (d *pumpfunDEXAdapter) SellPercentTokens(ctx context.Context, tokenMint str
 	return d.inner.SellPercentTokens(ctx, tokenMint, pct, slip, fee, cu)
 }
 
+// SellTokenAmount продаёт точное количество, гарантируя init.
+func (d *pumpfunDEXAdapter) SellTokenAmount(ctx context.Context, tokenMint string, amount uint64, slip float64, fee string, cu uint32) error {
+	if err := d.init(ctx, tokenMint, d.makeInitPumpFun(tokenMint)); err != nil {
+		return err
+	}
+	return d.inner.SellTokenAmount(ctx, tokenMint, amount, slip, fee, cu)
+}
+
 // CalculatePnL считает PnL, гарантируя init.
 func (d *pumpfunDEXAdapter) CalculatePnL(ctx context.Context, amount, invest float64) (*model.PnLResult, error) {
 	d.mu.Lock()
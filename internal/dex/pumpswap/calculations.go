@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"github.com/gagliardetto/solana-go"
-	"github.com/gagliardetto/solana-go/programs/token"
 	"go.uber.org/zap"
 	"math/big"
 )
@@ -81,11 +80,10 @@ func (d *DEX) getTokenDecimals(ctx context.Context, mint solana.PublicKey, defau
 
 // DetermineTokenPrecision получает количество десятичных знаков для данного токена.
 func (d *DEX) DetermineTokenPrecision(ctx context.Context, mint solana.PublicKey) (uint8, error) {
-	var mintInfo token.Mint
-	err := d.client.GetAccountDataInto(ctx, mint, &mintInfo)
+	decimals, err := d.client.GetMintDecimals(ctx, mint)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get mint info: %w", err)
 	}
 
-	return mintInfo.Decimals, nil
+	return decimals, nil
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
 	"go.uber.org/zap"
 )
 
@@ -24,6 +25,8 @@ type Config struct {
 	LPMint      solana.PublicKey // Токен пула ликвидности
 
 	MonitorInterval string
+
+	SlippageGuard model.SlippageGuard // Границы min-out/max-in для инструкций; nil — model.DefaultSlippageGuard
 }
 
 // GetDefaultConfig возвращает конфигурацию по умолчанию для PumpSwap.
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/gagliardetto/solana-go"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"go.uber.org/zap"
 	"time"
@@ -54,10 +55,21 @@ func (d *DEX) ExecuteSwap(ctx context.Context, params SwapParams) error {
 	}
 
 	// Вычисляем параметры для свапа
-	amounts := d.calculateSwapAmounts(pool, params.IsBuy, params.Amount)
+	// Для Token-2022 с transfer fee: при продаже в пул дойдет меньше токенов,
+	// при покупке на кошелек придет меньше, чем отправит пул
+	fee := d.getTransferFee(ctx)
+	var amounts *SwapAmounts
+	if params.IsBuy {
+		amounts = d.calculateSwapAmounts(pool, true, params.Amount)
+		execution.FromContext(ctx).SetQuote(fee.Net(amounts.BaseAmount))
+	} else {
+		amounts = d.calculateSwapAmounts(pool, false, fee.Net(params.Amount))
+		amounts.BaseAmount = params.Amount
+		execution.FromContext(ctx).SetQuote(amounts.QuoteAmount)
+	}
 
 	// Подготавливаем инструкции для транзакции
-	instructions, err := d.prepareSwapInstructions(pool, accounts, params, amounts)
+	instructions, err := d.prepareSwapInstructions(ctx, pool, accounts, params, amounts)
 	if err != nil {
 		return err
 	}
@@ -75,9 +87,10 @@ func (d *DEX) ExecuteSwap(ctx context.Context, params SwapParams) error {
 }
 
 // prepareSwapInstructions подготавливает инструкции для выполнения операции свапа.
-func (d *DEX) prepareSwapInstructions(pool *PoolInfo, accounts *PreparedTokenAccounts,
+func (d *DEX) prepareSwapInstructions(ctx context.Context, pool *PoolInfo, accounts *PreparedTokenAccounts,
 	params SwapParams, amounts *SwapAmounts) ([]solana.Instruction, error) {
-	priorityInstructions, err := d.preparePriorityInstructions(params.ComputeUnits, params.PriorityFeeSol)
+	feeAccounts := []solana.PublicKey{d.config.ProgramID, pool.Address}
+	priorityInstructions, err := d.preparePriorityInstructions(ctx, feeAccounts, params.ComputeUnits, params.PriorityFeeSol)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,111 @@
+package pumpswap
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// errExceededSlippage — ошибка программы PumpSwap при нарушении границы из инструкции.
+var errExceededSlippage = errors.New("ExceededSlippage")
+
+const testPoolFeeBps = 25
+
+// simulateSwap повторяет проверку инструкции свопа программы PumpSwap на заданном
+// состоянии пула: для buy стоимость base_amount_out с комиссией не должна превышать
+// max_quote_amount_in, для sell выход за вычетом комиссии — быть меньше min_quote_amount_out.
+func simulateSwap(pool PoolInfo, ix solana.Instruction) error {
+	data, err := ix.Data()
+	if err != nil {
+		return err
+	}
+	amount1 := binary.LittleEndian.Uint64(data[8:16])
+	amount2 := binary.LittleEndian.Uint64(data[16:24])
+
+	if string(data[:8]) == string(buyDiscriminator) {
+		quoteIn := mulDiv(pool.QuoteReserves, amount1, pool.BaseReserves-amount1) + 1
+		total := quoteIn + (quoteIn*testPoolFeeBps+9_999)/10_000
+		if total > amount2 {
+			return errExceededSlippage
+		}
+		return nil
+	}
+
+	quoteOut := mulDiv(pool.QuoteReserves, amount1, pool.BaseReserves+amount1)
+	net := quoteOut - (quoteOut*testPoolFeeBps+9_999)/10_000
+	if net < amount2 {
+		return errExceededSlippage
+	}
+	return nil
+}
+
+// mulDiv вычисляет a*b/c в u128, как программа.
+func mulDiv(a, b, c uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	q, _ := bits.Div64(hi, lo, c)
+	return q
+}
+
+func guardTestDEX() *DEX {
+	return &DEX{
+		wallet: &task.Wallet{PublicKey: solana.NewWallet().PublicKey()},
+		logger: zap.NewNop(),
+		config: &Config{},
+	}
+}
+
+func TestSwapInstructionCarriesOnChainBounds(t *testing.T) {
+	d := guardTestDEX()
+	pool := PoolInfo{BaseReserves: 200_000_000_000_000, QuoteReserves: 80_000_000_000, FeesBasisPoints: testPoolFeeBps}
+	feeFactor := 1.0 - float64(pool.FeesBasisPoints)/10_000.0
+	const slippage = 5.0
+
+	t.Run("buy", func(t *testing.T) {
+		const quoteIn = 1_000_000_000
+		baseOut := calculateOutput(pool.QuoteReserves, pool.BaseReserves, quoteIn, feeFactor)
+		ixs := d.buildSwapTransaction(&pool, &PreparedTokenAccounts{}, true, baseOut, quoteIn, slippage, nil)
+		swapIx := ixs[len(ixs)-1]
+
+		data, err := swapIx.Data()
+		require.NoError(t, err)
+		assert.Equal(t, baseOut, binary.LittleEndian.Uint64(data[8:16]))
+		assert.Equal(t, uint64(1_050_000_000), binary.LittleEndian.Uint64(data[16:24]))
+
+		// Чужая покупка перед нашей поднимает цену base
+		pumped := pool
+		pumped.QuoteReserves += 8_000_000_000
+		pumped.BaseReserves -= mulDiv(pool.BaseReserves, 8_000_000_000, pumped.QuoteReserves)
+
+		assert.NoError(t, simulateSwap(pool, swapIx), "quoted state fits the bound")
+		assert.ErrorIs(t, simulateSwap(pumped, swapIx), errExceededSlippage,
+			"the program rejects a fill past slippage")
+	})
+
+	t.Run("sell", func(t *testing.T) {
+		const baseIn = 5_000_000_000_000
+		quoteOut := calculateOutput(pool.BaseReserves, pool.QuoteReserves, baseIn, feeFactor)
+		ixs := d.buildSwapTransaction(&pool, &PreparedTokenAccounts{}, false, baseIn, quoteOut, slippage, nil)
+		swapIx := ixs[len(ixs)-1]
+
+		data, err := swapIx.Data()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(baseIn), binary.LittleEndian.Uint64(data[8:16]))
+		assert.Less(t, binary.LittleEndian.Uint64(data[16:24]), quoteOut)
+
+		// Чужая продажа перед нашей опускает цену base
+		dumped := pool
+		dumped.BaseReserves += 20_000_000_000_000
+		dumped.QuoteReserves -= mulDiv(pool.QuoteReserves, 20_000_000_000_000, dumped.BaseReserves)
+
+		assert.NoError(t, simulateSwap(pool, swapIx), "quoted state fits the bound")
+		assert.ErrorIs(t, simulateSwap(dumped, swapIx), errExceededSlippage,
+			"the program rejects a fill past slippage")
+	})
+}
@@ -0,0 +1,348 @@
+// =============================
+// File: internal/dex/pumpswap/lookup_table.go
+// =============================
+package pumpswap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"go.uber.org/zap"
+)
+
+// LookupTablePath — файл, в котором сохраняется адрес таблицы, созданной в режиме "auto".
+const LookupTablePath = "configs/pumpswap_alt.txt"
+
+const (
+	lookupTableAuto         = "auto"
+	lookupTableRefresh      = 5 * time.Minute
+	lookupTableRetryDelay   = time.Minute
+	lookupTableExtendChunk  = 20
+	lookupTableOpTimeout    = 60 * time.Second
+	lookupTableFetchTimeout = 2 * time.Second
+)
+
+// lookupTableManager хранит общую для всех свопов PumpSwap таблицу адресов.
+type lookupTableManager struct {
+	mu       sync.Mutex
+	mode     string // "", "auto" или адрес существующей таблицы
+	address  solana.PublicKey
+	table    *blockchain.LookupTable
+	loadedAt time.Time
+	busy     bool      // Идет создание или расширение таблицы
+	failedAt time.Time // Время последней неудачной попытки изменить таблицу
+}
+
+var sharedLookupTable = &lookupTableManager{}
+
+// UseLookupTable включает v0 транзакции с общей таблицей адресов (ALT) для свопов PumpSwap.
+// "" — выключено, "auto" — бот сам создает таблицу со статическими аккаунтами протокола
+// и дополняет ее, адрес — использовать существующую таблицу, не изменяя ее.
+func UseLookupTable(setting string) error {
+	setting = strings.TrimSpace(setting)
+
+	m := sharedLookupTable
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mode, m.address, m.table = "", solana.PublicKey{}, nil
+	switch setting {
+	case "":
+		return nil
+	case lookupTableAuto:
+		m.mode = lookupTableAuto
+		if data, err := os.ReadFile(LookupTablePath); err == nil {
+			addr, err := solana.PublicKeyFromBase58(strings.TrimSpace(string(data)))
+			if err != nil {
+				return fmt.Errorf("invalid lookup table address in %s: %w", LookupTablePath, err)
+			}
+			m.address = addr
+		}
+		return nil
+	default:
+		addr, err := solana.PublicKeyFromBase58(setting)
+		if err != nil {
+			return fmt.Errorf("invalid pumpswap lookup table address: %w", err)
+		}
+		m.mode, m.address = setting, addr
+		return nil
+	}
+}
+
+// lookupTables возвращает таблицу для транзакции, если она покрывает хотя бы один ее аккаунт.
+// В режиме "auto" заодно запускает фоновое создание или расширение таблицы.
+func (d *DEX) lookupTables(ctx context.Context, instructions []solana.Instruction) map[solana.PublicKey]solana.PublicKeySlice {
+	m := sharedLookupTable
+	table := m.current(ctx, d.client, d.logger)
+
+	if m.isAuto() {
+		d.maintainLookupTable(ctx, table)
+	}
+	if table == nil {
+		return nil
+	}
+
+	covered, total := lookupCoverage(table, instructions)
+	if covered == 0 {
+		return nil
+	}
+	d.logger.Debug("Using address lookup table",
+		zap.String("table", table.Address.String()),
+		zap.Int("covered", covered),
+		zap.Int("accounts", total))
+
+	return map[solana.PublicKey]solana.PublicKeySlice{table.Address: table.Addresses}
+}
+
+// lookupCoverage считает аккаунты инструкций, которые можно загрузить из таблицы.
+// Подписанты и вызываемые программы должны оставаться в статических ключах.
+func lookupCoverage(table *blockchain.LookupTable, instructions []solana.Instruction) (covered, total int) {
+	programs := make(map[solana.PublicKey]struct{}, len(instructions))
+	for _, ix := range instructions {
+		programs[ix.ProgramID()] = struct{}{}
+	}
+
+	seen := make(map[solana.PublicKey]struct{})
+	for _, ix := range instructions {
+		for _, acc := range ix.Accounts() {
+			if acc.IsSigner {
+				continue
+			}
+			if _, ok := programs[acc.PublicKey]; ok {
+				continue
+			}
+			if _, ok := seen[acc.PublicKey]; ok {
+				continue
+			}
+			seen[acc.PublicKey] = struct{}{}
+			total++
+			if table.Contains(acc.PublicKey) {
+				covered++
+			}
+		}
+	}
+	return covered, total
+}
+
+// staticLookupAddresses возвращает аккаунты, одинаковые для всех свопов PumpSwap.
+func (d *DEX) staticLookupAddresses(ctx context.Context) ([]solana.PublicKey, error) {
+	globalConfig, err := d.getGlobalConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := []solana.PublicKey{
+		d.config.GlobalConfig,
+		d.config.EventAuthority,
+		solana.SolMint,
+		TokenProgramID,
+		SystemProgramID,
+	}
+	for _, recipient := range globalConfig.ProtocolFeeRecipients {
+		if recipient.IsZero() {
+			continue
+		}
+		ata, _, err := solana.FindAssociatedTokenAddress(recipient, solana.SolMint)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, recipient, ata)
+	}
+	return addresses, nil
+}
+
+// maintainLookupTable создает таблицу или дописывает в нее недостающие статические аккаунты.
+// Работа идет в фоне: текущая транзакция не ждет изменений таблицы.
+func (d *DEX) maintainLookupTable(ctx context.Context, table *blockchain.LookupTable) {
+	if table != nil && (table.Authority == nil || !table.Authority.Equals(d.wallet.PublicKey)) {
+		return // Таблица чужая — пользуемся ей как есть
+	}
+	if table == nil && sharedLookupTable.hasAddress() {
+		return // Таблица есть, но сейчас недоступна — не создаем вторую
+	}
+
+	static, err := d.staticLookupAddresses(ctx)
+	if err != nil {
+		return
+	}
+
+	var missing []solana.PublicKey
+	for _, addr := range static {
+		if !table.Contains(addr) {
+			missing = append(missing, addr)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	m := sharedLookupTable
+	if !m.begin() {
+		return
+	}
+
+	go func() {
+		opCtx, cancel := context.WithTimeout(context.Background(), lookupTableOpTimeout)
+		defer cancel()
+
+		err := d.extendLookupTable(opCtx, table, missing)
+		m.end(err)
+		if err != nil {
+			d.logger.Warn("Address lookup table update failed", zap.Error(err))
+		}
+	}()
+}
+
+// extendLookupTable при необходимости создает таблицу и добавляет в нее адреса.
+func (d *DEX) extendLookupTable(ctx context.Context, table *blockchain.LookupTable, addresses []solana.PublicKey) error {
+	var tableAddr solana.PublicKey
+	if table != nil {
+		tableAddr = table.Address
+	} else {
+		slot, err := d.client.GetSlot(ctx, rpc.CommitmentFinalized)
+		if err != nil {
+			return err
+		}
+		createIx, addr, err := blockchain.NewCreateLookupTableInstruction(d.wallet.PublicKey, d.wallet.PublicKey, slot)
+		if err != nil {
+			return err
+		}
+		if err := d.sendLookupTableTransaction(ctx, createIx); err != nil {
+			return fmt.Errorf("create lookup table: %w", err)
+		}
+		if err := saveLookupTableAddress(addr); err != nil {
+			return err
+		}
+		sharedLookupTable.setAddress(addr)
+		tableAddr = addr
+		d.logger.Info("Created address lookup table", zap.String("table", addr.String()))
+	}
+
+	for start := 0; start < len(addresses); start += lookupTableExtendChunk {
+		end := min(start+lookupTableExtendChunk, len(addresses))
+		extendIx := blockchain.NewExtendLookupTableInstruction(tableAddr, d.wallet.PublicKey, d.wallet.PublicKey, addresses[start:end])
+		if err := d.sendLookupTableTransaction(ctx, extendIx); err != nil {
+			return fmt.Errorf("extend lookup table: %w", err)
+		}
+	}
+	d.logger.Info("Extended address lookup table",
+		zap.String("table", tableAddr.String()),
+		zap.Int("added", len(addresses)))
+
+	// Новые адреса становятся доступны только со следующего слота
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Second):
+	}
+	return nil
+}
+
+// sendLookupTableTransaction отправляет служебную legacy-транзакцию без использования таблицы.
+func (d *DEX) sendLookupTableTransaction(ctx context.Context, ix solana.Instruction) error {
+	blockhash, err := d.client.GetRecentBlockhash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get recent blockhash: %w", err)
+	}
+
+	tx, err := solana.NewTransaction([]solana.Instruction{ix}, blockhash, solana.TransactionPayer(d.wallet.PublicKey))
+	if err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+	if err := d.wallet.SignTransaction(ctx, tx); err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	sig, err := d.client.SendTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+	return d.client.WaitForTransactionConfirmation(ctx, sig, rpc.CommitmentConfirmed)
+}
+
+// saveLookupTableAddress сохраняет адрес созданной таблицы для следующих запусков.
+func saveLookupTableAddress(addr solana.PublicKey) error {
+	if err := os.MkdirAll(filepath.Dir(LookupTablePath), 0o755); err != nil {
+		return fmt.Errorf("create lookup table dir: %w", err)
+	}
+	if err := os.WriteFile(LookupTablePath, []byte(addr.String()+"\n"), 0o600); err != nil {
+		return fmt.Errorf("save lookup table address: %w", err)
+	}
+	return nil
+}
+
+func (m *lookupTableManager) isAuto() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mode == lookupTableAuto
+}
+
+// current возвращает загруженную таблицу, периодически перечитывая ее из сети.
+func (m *lookupTableManager) current(ctx context.Context, client *blockchain.Client, logger *zap.Logger) *blockchain.LookupTable {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.mode == "" || m.address.IsZero() {
+		return nil
+	}
+	if time.Since(m.loadedAt) < lookupTableRefresh {
+		return m.table
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, lookupTableFetchTimeout)
+	defer cancel()
+
+	table, err := client.GetLookupTable(fetchCtx, m.address)
+	m.loadedAt = time.Now()
+	if err != nil {
+		logger.Warn("Address lookup table unavailable, using legacy transactions", zap.Error(err))
+		m.table = nil
+		return nil
+	}
+	m.table = table
+	return table
+}
+
+// begin резервирует фоновое изменение таблицы; false, если оно уже идет или недавно не удалось.
+func (m *lookupTableManager) begin() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.busy || time.Since(m.failedAt) < lookupTableRetryDelay {
+		return false
+	}
+	m.busy = true
+	return true
+}
+
+// end завершает изменение таблицы и заставляет перечитать ее при следующей транзакции.
+func (m *lookupTableManager) end(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.busy = false
+	m.loadedAt = time.Time{}
+	if err != nil {
+		m.failedAt = time.Now()
+	}
+}
+
+func (m *lookupTableManager) hasAddress() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.address.IsZero()
+}
+
+func (m *lookupTableManager) setAddress(addr solana.PublicKey) {
+	m.mu.Lock()
+	m.address = addr
+	m.mu.Unlock()
+}
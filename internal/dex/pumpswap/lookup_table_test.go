@@ -0,0 +1,36 @@
+package pumpswap
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCoverage(t *testing.T) {
+	user := solana.NewWallet().PublicKey()
+	pool := solana.NewWallet().PublicKey()
+	globalConfig := solana.NewWallet().PublicKey()
+	eventAuthority := solana.NewWallet().PublicKey()
+
+	swapIx := solana.NewInstruction(PumpSwapProgramID, solana.AccountMetaSlice{
+		solana.NewAccountMeta(pool, true, false),
+		solana.NewAccountMeta(user, true, true),
+		solana.NewAccountMeta(globalConfig, false, false),
+		solana.NewAccountMeta(eventAuthority, false, false),
+		solana.NewAccountMeta(PumpSwapProgramID, false, false),
+	}, nil)
+
+	table := &blockchain.LookupTable{
+		// Подписант и вызываемая программа не должны считаться покрытыми
+		Addresses: solana.PublicKeySlice{globalConfig, eventAuthority, user, PumpSwapProgramID},
+	}
+
+	covered, total := lookupCoverage(table, []solana.Instruction{swapIx})
+	assert.Equal(t, 2, covered)
+	assert.Equal(t, 3, total)
+
+	covered, _ = lookupCoverage(&blockchain.LookupTable{}, []solana.Instruction{swapIx})
+	assert.Equal(t, 0, covered)
+}
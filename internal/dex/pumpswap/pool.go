@@ -6,6 +6,7 @@ package pumpswap
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
 	"golang.org/x/sync/errgroup"
@@ -101,13 +102,14 @@ func (pm *PoolManager) globalConfig(ctx context.Context) (*GlobalConfig, error)
 ////////////////////////////////////////////////////////////////////////////////
 
 // getAccountBinaryData retrieves binary data for a single account with a timeout.
-func (pm *PoolManager) getAccountBinaryData(ctx context.Context, pubkey solana.PublicKey) ([]byte, error) {
+// A positive ttl serves the account from the client's cache (see blockchain.GetCachedAccountInfo).
+func (pm *PoolManager) getAccountBinaryData(ctx context.Context, pubkey solana.PublicKey, ttl time.Duration) ([]byte, error) {
 	// Apply a 5-second timeout to the RPC call
 	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	// Fetch account info
-	accountInfo, err := pm.client.GetAccountInfo(cctx, pubkey)
+	accountInfo, err := pm.client.GetCachedAccountInfo(cctx, pubkey, ttl)
 	if err != nil {
 		pm.logger.Error("GetAccountInfo failed", zap.String("account", pubkey.String()), zap.Error(err))
 		return nil, fmt.Errorf("failed to get account info for %s: %w", pubkey.String(), err)
@@ -120,27 +122,30 @@ func (pm *PoolManager) getAccountBinaryData(ctx context.Context, pubkey solana.P
 	return accountInfo.Value.Data.GetBinary(), nil
 }
 
-// getAccountBinaryDataMultiple retrieves binary data for multiple accounts with a timeout.
-func (pm *PoolManager) getAccountBinaryDataMultiple(ctx context.Context, accounts []solana.PublicKey) ([][]byte, error) {
-	// Apply a 5-second timeout to the RPC call
+// tokenReserves читает резервы двух токен-аккаунтов пула одним запросом,
+// разбирая балансы прямо из ответа RPC.
+func (pm *PoolManager) tokenReserves(ctx context.Context, base, quote solana.PublicKey) (uint64, uint64, error) {
 	cctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Perform the batch request
-	resp, err := pm.client.GetMultipleAccounts(cctx, accounts)
+	resp, err := pm.client.GetMultipleAccounts(cctx, []solana.PublicKey{base, quote})
 	if err != nil {
 		pm.logger.Error("GetMultipleAccounts failed", zap.Error(err))
-		return nil, fmt.Errorf("failed to get multiple accounts info: %w", err)
+		return 0, 0, fmt.Errorf("failed to get multiple accounts info: %w", err)
+	}
+	if len(resp.Value) < 2 {
+		return 0, 0, fmt.Errorf("token accounts not returned")
 	}
+	baseRes, quoteRes := parseTokenAccounts(accountData(resp.Value[0]), accountData(resp.Value[1]))
+	return baseRes, quoteRes, nil
+}
 
-	// Extract binary slices, skipping nil entries
-	data := make([][]byte, len(accounts))
-	for i, info := range resp.Value {
-		if info != nil {
-			data[i] = info.Data.GetBinary()
-		}
+// accountData возвращает буфер данных аккаунта без копирования (nil для отсутствующего аккаунта).
+func accountData(acc *rpc.Account) []byte {
+	if acc == nil || acc.Data == nil {
+		return nil
 	}
-	return data, nil
+	return acc.Data.GetBinary()
 }
 
 // parseTokenAccounts извлекает балансы из бинарных данных токен-аккаунтов.
@@ -238,40 +243,25 @@ func (pm *PoolManager) findPoolByProgramAccounts(ctx context.Context, baseMint,
 		return nil, fmt.Errorf("no program accounts match %s/%s", baseMint, quoteMint)
 	}
 
-	// --- получаем весь бинарный контент пулов одним запросом ---
-	pubkeys := make([]solana.PublicKey, len(accounts))
-	for i, acc := range accounts {
-		pubkeys[i] = acc.Pubkey
-	}
-
-	poolsRaw, err := pm.getAccountBinaryDataMultiple(ctx, pubkeys)
-	if err != nil {
-		return nil, err
-	}
-
 	// кеш глобальной конфигурации
 	cfg, _ := pm.globalConfig(ctx)
 
-	// перебираем кандидатов
-	for i, raw := range poolsRaw {
-		pool, err := ParsePool(raw)
-		if err != nil {
+	// перебираем кандидатов: данные пулов уже пришли в ответе getProgramAccounts,
+	// разбираем их на месте без повторного запроса и копий
+	var pool Pool
+	for _, acc := range accounts {
+		if acc == nil || decodePool(accountData(acc.Account), &pool) != nil {
 			continue
 		}
 
 		// резервы токен‑аккаунтов (два за один запрос)
-		tokens := []solana.PublicKey{pool.PoolBaseTokenAccount, pool.PoolQuoteTokenAccount}
-		tokRaw, err := pm.getAccountBinaryDataMultiple(ctx, tokens)
-		if err != nil {
-			continue
-		}
-		baseRes, quoteRes := parseTokenAccounts(tokRaw[0], tokRaw[1])
-		if baseRes == 0 || quoteRes == 0 {
+		baseRes, quoteRes, err := pm.tokenReserves(ctx, pool.PoolBaseTokenAccount, pool.PoolQuoteTokenAccount)
+		if err != nil || baseRes == 0 || quoteRes == 0 {
 			continue
 		}
 
 		return &PoolInfo{
-			Address:               pubkeys[i],
+			Address:               acc.Pubkey,
 			BaseMint:              pool.BaseMint,
 			QuoteMint:             pool.QuoteMint,
 			BaseReserves:          baseRes,
@@ -309,15 +299,12 @@ func (pm *PoolManager) FetchPoolInfo(ctx context.Context, poolAddress solana.Pub
 	}
 
 	// Резервы токен‑аккаунтов
-	accs := []solana.PublicKey{pool.PoolBaseTokenAccount, pool.PoolQuoteTokenAccount}
-	accData, err := pm.getAccountBinaryDataMultiple(timeoutCtx, accs)
+	baseRes, quoteRes, err := pm.tokenReserves(timeoutCtx, pool.PoolBaseTokenAccount, pool.PoolQuoteTokenAccount)
 	if err != nil {
 		pm.logger.Error("Не удалось получить данные токен‑аккаунтов", zap.Error(err))
 		return nil, err
 	}
 
-	baseRes, quoteRes := parseTokenAccounts(accData[0], accData[1])
-
 	return &PoolInfo{
 		Address:               poolAddress,
 		BaseMint:              pool.BaseMint,
@@ -336,7 +323,7 @@ func (pm *PoolManager) FetchPoolInfo(ctx context.Context, poolAddress solana.Pub
 
 // getPool получает и парсит данные пула по адресу.
 func (pm *PoolManager) getPool(ctx context.Context, poolAddress solana.PublicKey) (*Pool, error) {
-	data, err := pm.getAccountBinaryData(ctx, poolAddress)
+	data, err := pm.getAccountBinaryData(ctx, poolAddress, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -351,7 +338,7 @@ func (pm *PoolManager) fetchGlobalConfig(ctx context.Context) (*GlobalConfig, er
 		return nil, fmt.Errorf("failed to derive global config address: %w", err)
 	}
 
-	data, err := pm.getAccountBinaryData(ctx, globalConfig)
+	data, err := pm.getAccountBinaryData(ctx, globalConfig, blockchain.ConfigCacheTTL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get global config account: %w", err)
 	}
@@ -461,22 +448,40 @@ func (d *DEX) findAndValidatePool(ctx context.Context) (*PoolInfo, bool, error)
 
 // ParsePool парсит бинарные данные аккаунта пула.
 func ParsePool(data []byte) (*Pool, error) {
+	pool := &Pool{}
+	if err := decodePool(data, pool); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Ошибки разбора пула создаются один раз: кандидаты с чужим discriminator
+// отбрасываются на каждом поиске пула.
+var (
+	errPoolTooShort        = errors.New("data too short for Pool")
+	errPoolDiscriminator   = errors.New("invalid discriminator for Pool")
+	errPoolContentTooShort = errors.New("data too short for Pool content")
+)
+
+// decodePool заполняет pool прямо из буфера данных аккаунта без аллокаций;
+// pool можно переиспользовать между вызовами.
+func decodePool(data []byte, pool *Pool) error {
 	if len(data) < 8 {
-		return nil, fmt.Errorf("data too short for Pool")
+		return errPoolTooShort
 	}
 	// Проверяем discriminator
 	for i := 0; i < 8; i++ {
 		if data[i] != PoolDiscriminator[i] {
-			return nil, fmt.Errorf("invalid discriminator for Pool")
+			return errPoolDiscriminator
 		}
 	}
 
 	pos := 8
 	if len(data) < pos+1+2+32*6+8 {
-		return nil, fmt.Errorf("data too short for Pool content")
+		return errPoolContentTooShort
 	}
 
-	pool := &Pool{}
+	*pool = Pool{}
 	pool.PoolBump = data[pos]
 	pos++
 	pool.Index = uint16(data[pos]) | (uint16(data[pos+1]) << 8)
@@ -503,5 +508,5 @@ func ParsePool(data []byte) (*Pool, error) {
 		pool.CoinCreator = solana.PublicKeyFromBytes(data[pos : pos+32])
 	}
 
-	return pool, nil
+	return nil
 }
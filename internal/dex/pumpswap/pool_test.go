@@ -0,0 +1,62 @@
+package pumpswap
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// poolAccount собирает данные аккаунта пула PumpSwap с discriminator и coin creator.
+func poolAccount(baseMint, quoteMint, coinCreator solana.PublicKey) []byte {
+	data := make([]byte, 8+1+2+32*6+8+32)
+	copy(data, PoolDiscriminator)
+	data[8] = 254
+	binary.LittleEndian.PutUint16(data[9:], 3)
+	pos := 11 + 32 // creator
+	copy(data[pos:], baseMint[:])
+	copy(data[pos+32:], quoteMint[:])
+	pos += 32 * 5
+	binary.LittleEndian.PutUint64(data[pos:], 42)
+	copy(data[pos+8:], coinCreator[:])
+	return data
+}
+
+func TestDecodePool(t *testing.T) {
+	base, creator := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	data := poolAccount(base, solana.WrappedSol, creator)
+
+	pool, err := ParsePool(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(3), pool.Index)
+	assert.Equal(t, base, pool.BaseMint)
+	assert.Equal(t, solana.WrappedSol, pool.QuoteMint)
+	assert.Equal(t, uint64(42), pool.LPSupply)
+	assert.Equal(t, creator, pool.CoinCreator)
+
+	// Переиспользуемый Pool разбирается без аллокаций, в том числе для чужих аккаунтов
+	var reused Pool
+	foreign := append([]byte(nil), data...)
+	foreign[0]++
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = decodePool(data, &reused)
+		_ = decodePool(foreign, &reused)
+	})
+	assert.Zero(t, allocs)
+	assert.NoError(t, decodePool(data, &reused))
+	assert.Equal(t, *pool, reused)
+
+	_, err = ParsePool(data[:40])
+	assert.Error(t, err)
+}
+
+func BenchmarkDecodePool(b *testing.B) {
+	data := poolAccount(solana.NewWallet().PublicKey(), solana.WrappedSol, solana.NewWallet().PublicKey())
+	var pool Pool
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = decodePool(data, &pool)
+	}
+}
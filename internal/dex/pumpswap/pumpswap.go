@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
 )
 
 // effectiveMints возвращает эффективные значения базового и квотного минтов для свопа.
@@ -70,7 +71,7 @@ func (d *DEX) fetchGlobalConfigFromChain(ctx context.Context) (*GlobalConfig, er
 		return nil, fmt.Errorf("failed to derive global config address: %w", err)
 	}
 
-	accountInfo, err := d.client.GetAccountInfo(ctx, globalConfigAddr)
+	accountInfo, err := d.client.GetCachedAccountInfo(ctx, globalConfigAddr, blockchain.ConfigCacheTTL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get global config: %w", err)
 	}
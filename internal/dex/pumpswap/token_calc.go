@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
 	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
 	"math"
 	"strconv"
@@ -28,9 +29,9 @@ const (
 func (d *DEX) getPool(ctx context.Context) (*PoolInfo, error) {
 	// Если в кэше есть актуальная информация, возвращаем ее
 	if d.cachedPool != nil && time.Since(d.cachedPoolTime) < d.cacheValidPeriod {
-		d.logger.Debug("Using cached pool info",
-			zap.String("pool", d.cachedPool.Address.String()),
-			zap.Time("cached_at", d.cachedPoolTime))
+		if ce := d.logger.Check(zap.DebugLevel, "Using cached pool info"); ce != nil {
+			ce.Write(zap.Stringer("pool", d.cachedPool.Address), zap.Time("cached_at", d.cachedPoolTime))
+		}
 		return d.cachedPool, nil
 	}
 
@@ -44,14 +45,58 @@ func (d *DEX) getPool(ctx context.Context) (*PoolInfo, error) {
 	// Обновляем кэш
 	d.cachedPool = pool
 	d.cachedPoolTime = time.Now()
-	d.logger.Debug("Updated pool cache",
-		zap.String("pool", pool.Address.String()),
-		zap.Uint64("base_reserves", pool.BaseReserves),
-		zap.Uint64("quote_reserves", pool.QuoteReserves))
+	if ce := d.logger.Check(zap.DebugLevel, "Updated pool cache"); ce != nil {
+		ce.Write(zap.Stringer("pool", pool.Address),
+			zap.Uint64("base_reserves", pool.BaseReserves),
+			zap.Uint64("quote_reserves", pool.QuoteReserves))
+	}
 
 	return pool, nil
 }
 
+// getTokenMint возвращает программу токена и его комиссию за перевод. Удачный ответ
+// кэшируется на время жизни DEX, после ошибки RPC mint запрашивается снова.
+func (d *DEX) getTokenMint(ctx context.Context) (*blockchain.TokenMint, error) {
+	d.mintMu.Lock()
+	defer d.mintMu.Unlock()
+	if d.tokenMint != nil {
+		return d.tokenMint, nil
+	}
+	effBase, _ := d.effectiveMints()
+	mint, err := d.client.GetTokenMint(ctx, effBase)
+	if err != nil {
+		return nil, err
+	}
+	if mint.IsToken2022() {
+		d.logger.Info("Token uses the Token-2022 program", zap.String("mint", effBase.String()))
+	}
+	if mint.TransferFee != nil {
+		d.logger.Info("Token has a transfer fee", zap.Float64("fee_percent", mint.TransferFee.Percent()))
+	}
+	d.tokenMint = mint
+	return mint, nil
+}
+
+// getTransferFee возвращает комиссию Token-2022 за перевод токена (nil, если ее нет).
+func (d *DEX) getTransferFee(ctx context.Context) *blockchain.TransferFee {
+	mint, err := d.getTokenMint(ctx)
+	if err != nil {
+		d.logger.Debug("Failed to read mint transfer fee", zap.Error(err))
+		return nil
+	}
+	return mint.TransferFee
+}
+
+// tokenProgram возвращает программу базового токена: от нее зависят ATA пользователя
+// и аккаунт base_token_program инструкции свопа.
+func (d *DEX) tokenProgram(ctx context.Context) (solana.PublicKey, error) {
+	mint, err := d.getTokenMint(ctx)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to detect token program: %w", err)
+	}
+	return mint.Program, nil
+}
+
 // calculateEstimate возвращает прогнозный выход SOL за tokenAmount,
 // с учётом только протокольной/DEX-комиссии.
 func (d *DEX) calculateEstimate(ctx context.Context, tokenAmount float64, reserves interface{}) (float64, error) {
@@ -72,8 +117,8 @@ func (d *DEX) calculateEstimate(ctx context.Context, tokenAmount float64, reserv
 	effBase, _ := d.effectiveMints()
 	baseDecimals := d.getTokenDecimals(ctx, effBase, DefaultTokenDecimals)
 
-	// Преобразуем токены в минимальные единицы
-	tokenAmountRaw := uint64(tokenAmount * math.Pow10(int(baseDecimals)))
+	// Преобразуем токены в минимальные единицы (за вычетом transfer fee Token-2022)
+	tokenAmountRaw := d.getTransferFee(ctx).Net(uint64(tokenAmount * math.Pow10(int(baseDecimals))))
 
 	// Формула Constant Product AMM для расчета выхода токенов:
 	// ∆y = (y * ∆x) / (x + ∆x)
@@ -163,8 +208,12 @@ func (d *DEX) GetTokenBalance(ctx context.Context, tokenMint string) (uint64, er
 	// Получаем информацию о токене
 	effBase, _ := d.effectiveMints()
 
-	// Находим ATA адрес для токена
-	userATA, _, err := solana.FindAssociatedTokenAddress(d.wallet.PublicKey, effBase)
+	// Находим ATA адрес для токена в его программе (SPL Token или Token-2022)
+	program, err := d.tokenProgram(ctx)
+	if err != nil {
+		return 0, err
+	}
+	userATA, err := blockchain.FindAssociatedTokenAddress(d.wallet.PublicKey, effBase, program)
 	if err != nil {
 		return 0, fmt.Errorf("failed to derive associated token account: %w", err)
 	}
@@ -287,8 +336,8 @@ func (d *DEX) SellPercentTokens(ctx context.Context, tokenMint string, percentTo
 		return fmt.Errorf("нет токенов для продажи")
 	}
 
-	// Рассчитываем количество токенов для продажи
-	amountToSell := uint64(float64(tokenBalance) * percentToSell / 100.0)
+	// Рассчитываем количество токенов для продажи (целочисленно, с округлением вниз)
+	amountToSell := model.PercentOf(tokenBalance, percentToSell)
 
 	// Убедимся, что продаём хотя бы 1 токен, если есть баланс
 	if amountToSell == 0 && tokenBalance > 0 {
@@ -303,3 +352,58 @@ func (d *DEX) SellPercentTokens(ctx context.Context, tokenMint string, percentTo
 	// Выполняем продажу указанного количества токенов
 	return d.executeSell(ctx, amountToSell, slippagePercent, priorityFeeSol, computeUnits)
 }
+
+// SellTokenAmount продает ровно amount базовых единиц токена, если они есть на балансе.
+func (d *DEX) SellTokenAmount(ctx context.Context, tokenMint string, amount uint64,
+	slippagePercent float64, priorityFeeSol string, computeUnits uint32) error {
+	if amount == 0 {
+		return fmt.Errorf("количество для продажи должно быть больше нуля")
+	}
+
+	tokenBalance, err := d.GetTokenBalance(ctx, tokenMint)
+	if err != nil {
+		return fmt.Errorf("не удалось получить баланс токена: %w", err)
+	}
+	if tokenBalance < amount {
+		return fmt.Errorf("баланс %d меньше количества для продажи %d", tokenBalance, amount)
+	}
+
+	d.logger.Info("Продажа токенов",
+		zap.Uint64("current_balance", tokenBalance),
+		zap.Uint64("amount_to_sell", amount))
+
+	return d.executeSell(ctx, amount, slippagePercent, priorityFeeSol, computeUnits)
+}
+
+// PoolLiquidity читает пул заново, минуя кеш, и возвращает его резервы WSOL в
+// лампортах и эмиссию LP-токенов.
+func (d *DEX) PoolLiquidity(ctx context.Context) (solLamports, lpSupply uint64, err error) {
+	cached, err := d.getPool(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	pool, err := d.poolManager.FetchPoolInfo(ctx, cached.Address)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch pool: %w", err)
+	}
+	if pool.BaseMint.Equals(solana.SolMint) {
+		return pool.BaseReserves, pool.LPSupply, nil
+	}
+	return pool.QuoteReserves, pool.LPSupply, nil
+}
+
+// PriceAccounts возвращает хранилища базового и котируемого токена пула: цена
+// считается по их резервам.
+func (d *DEX) PriceAccounts(ctx context.Context) ([]solana.PublicKey, error) {
+	pool, err := d.getPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []solana.PublicKey{pool.PoolBaseTokenAccount, pool.PoolQuoteTokenAccount}, nil
+}
+
+// InvalidatePrice сбрасывает кеш цены и резервов пула: следующий GetTokenPrice читает цепочку.
+func (d *DEX) InvalidatePrice() {
+	d.cachedPriceTime = time.Time{}
+	d.cachedPoolTime = time.Time{}
+}
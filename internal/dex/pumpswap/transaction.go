@@ -5,13 +5,15 @@ package pumpswap
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/cenkalti/backoff/v5"
 	"github.com/gagliardetto/solana-go"
 	computebudget "github.com/gagliardetto/solana-go/programs/compute-budget"
 	"github.com/gagliardetto/solana-go/rpc"
 	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
-	"strings"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
 	"time"
 )
 
@@ -19,65 +21,110 @@ import (
 //
 // Метод объединяет процессы создания, подписи и отправки транзакции
 // с механизмом повторных попыток. Он использует экспоненциальную стратегию
-// задержки между попытками и имеет ограничение на общее время выполнения в 15 секунд.
+// задержки между попытками и имеет ограничение на общее время выполнения в 15 секунд
+// (или оставшийся бюджет сделки, если он задан).
 func (d *DEX) buildAndSubmitTransaction(ctx context.Context, instructions []solana.Instruction) (solana.Signature, error) {
+	maxElapsed := 15 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		maxElapsed = time.Until(deadline)
+	}
+
 	op := func() (solana.Signature, error) {
-		tx, err := d.createSignedTransaction(ctx, instructions)
+		blockhash, build, err := d.createTransactionBuilder(ctx, instructions)
 		if err != nil {
 			return solana.Signature{}, err
 		}
 
-		return d.submitAndConfirmTransaction(ctx, tx)
+		return d.submitAndConfirmTransaction(ctx, instructions, blockhash, build)
 	}
 
 	return backoff.Retry(
 		ctx,
 		op,
 		backoff.WithBackOff(backoff.NewExponentialBackOff()),
-		backoff.WithMaxElapsedTime(15*time.Second),
+		backoff.WithMaxElapsedTime(maxElapsed),
 	)
 }
 
-// createSignedTransaction создает и подписывает новую транзакцию с указанными инструкциями.
+// createTransactionBuilder готовит сборку подписанных транзакций с указанными инструкциями.
 //
-// Метод получает актуальный blockhash, создает транзакцию с переданными инструкциями
-// и подписывает её кошельком DEX. В случае критических ошибок (отсутствие blockhash,
-// невозможность создать или подписать транзакцию) возвращается постоянная ошибка,
-// которая предотвращает повторные попытки.
-func (d *DEX) createSignedTransaction(ctx context.Context, instructions []solana.Instruction) (*solana.Transaction, error) {
-	blockhash, err := d.client.GetRecentBlockhash(ctx)
+// Метод получает актуальный blockhash и возвращает его вместе с функцией, которая создает
+// транзакцию и подписывает её кошельком DEX. Все версии транзакции (при повторной отправке
+// с другой ценой CU) используют один blockhash, новый — только после его истечения.
+// В случае критических ошибок (отсутствие blockhash, невозможность создать или подписать
+// транзакцию) возвращается постоянная ошибка, которая предотвращает повторные попытки.
+func (d *DEX) createTransactionBuilder(ctx context.Context, instructions []solana.Instruction) (solana.Hash, func([]solana.Instruction, solana.Hash) (*solana.Transaction, error), error) {
+	blockhash, fetchedAt, err := d.client.LatestBlockhash(ctx)
 	if err != nil {
-		return nil, backoff.Permanent(fmt.Errorf("failed to get recent blockhash: %w", err))
+		return solana.Hash{}, nil, backoff.Permanent(fmt.Errorf("failed to get recent blockhash: %w", err))
 	}
-
-	tx, err := solana.NewTransaction(instructions, blockhash, solana.TransactionPayer(d.wallet.PublicKey))
-	if err != nil {
-		return nil, backoff.Permanent(fmt.Errorf("failed to create transaction: %w", err))
+	trace := execution.FromContext(ctx)
+	trace.SetBlockhash(fetchedAt)
+	trace.MarkStage(execution.StageBlockhash)
+
+	opts := []solana.TransactionOption{solana.TransactionPayer(d.wallet.Payer())}
+	if tables := d.lookupTables(ctx, instructions); tables != nil {
+		// v0 транзакция: статические аккаунты протокола загружаются из общей ALT
+		opts = append(opts, solana.TransactionAddressTables(tables))
 	}
 
-	if err := d.wallet.SignTransaction(tx); err != nil {
-		return nil, backoff.Permanent(fmt.Errorf("failed to sign transaction: %w", err))
-	}
+	signed := false
+	return blockhash, func(ixs []solana.Instruction, recent solana.Hash) (*solana.Transaction, error) {
+		tx, err := solana.NewTransaction(ixs, recent, opts...)
+		if err != nil {
+			return nil, backoff.Permanent(fmt.Errorf("failed to create transaction: %w", err))
+		}
+
+		if err := d.wallet.SignTransaction(ctx, tx); err != nil {
+			return nil, backoff.Permanent(fmt.Errorf("failed to sign transaction: %w", err))
+		}
+		if !signed {
+			signed = true
+			trace.MarkStage(execution.StageSigned)
+		}
 
-	return tx, nil
+		return tx, nil
+	}, nil
 }
 
 // submitAndConfirmTransaction отправляет транзакцию и ожидает ее подтверждения.
 //
-// Метод отправляет подписанную транзакцию в сеть Solana и ожидает ее подтверждения.
+// Метод отправляет подписанную транзакцию в сеть Solana и ожидает ее подтверждения;
+// если она долго не подтверждается, клиент переотправляет ее с повышенной ценой CU.
 // Он обрабатывает различные типы ошибок: временные (BlockhashNotFound), специфические
 // (SlippageExceeded) и постоянные. Для временных ошибок возможен повторный запуск,
 // для постоянных - операция прерывается.
-func (d *DEX) submitAndConfirmTransaction(ctx context.Context, tx *solana.Transaction) (solana.Signature, error) {
-	// Отправляем транзакцию с опциями для ускорения обработки
+func (d *DEX) submitAndConfirmTransaction(ctx context.Context, instructions []solana.Instruction, blockhash solana.Hash, build func([]solana.Instruction, solana.Hash) (*solana.Transaction, error)) (solana.Signature, error) {
+	trace := execution.FromContext(ctx)
+
+	// Отправляем транзакцию с опциями для ускорения обработки.
+	// Используем CommitmentProcessed для быстрого подтверждения транзакции при продаже
 	txOpts := blockchain.TransactionOptions{
 		SkipPreflight:       true,
 		PreflightCommitment: rpc.CommitmentProcessed,
 	}
-	sig, err := d.client.SendTransactionWithOpts(ctx, tx, txOpts)
-	if err != nil {
-		// Проверяем на специфичные временные ошибки
-		if strings.Contains(err.Error(), "BlockhashNotFound") {
+	landed, err := d.client.SendAndConfirm(ctx, instructions, blockhash, build, txOpts, rpc.CommitmentProcessed, func(a blockchain.SendAttempt) {
+		switch {
+		case a.Attempt == 0 && a.Resubmission == 0:
+			d.logger.Info("📤 Transaction sent: " + a.Signature.String()[:8] + "...")
+			trace.MarkSent(a.Signature)
+			return
+		case a.Attempt == 0:
+			d.logger.Info(fmt.Sprintf("♻️  Resubmitted #%d with a fresh blockhash: %s...", a.Resubmission, a.Signature.String()[:8]))
+		default:
+			d.logger.Info(fmt.Sprintf("🔁 Rebroadcast #%d at %d micro-lamports: %s...", a.Attempt, a.MicroLamports, a.Signature.String()[:8]))
+		}
+		trace.MarkRebroadcast(a.Signature)
+	})
+	if landed.Signature.IsZero() {
+		// Ошибка сборки транзакции уже помечена как постоянная
+		var permanent *backoff.PermanentError
+		if errors.As(err, &permanent) {
+			return solana.Signature{}, err
+		}
+
+		// Проверяем на специфичные временные ошибки: со свежим blockhash транзакция может пройти
+		if blockchain.IsBlockhashError(err) {
 			return solana.Signature{}, err // Временная ошибка для retry
 		}
 
@@ -92,17 +139,17 @@ func (d *DEX) submitAndConfirmTransaction(ctx context.Context, tx *solana.Transa
 		return solana.Signature{}, backoff.Permanent(fmt.Errorf("transaction failed: %w", err))
 	}
 
-	d.logger.Info("📤 Transaction sent: " + sig.String()[:8] + "...")
-
-	// Используем CommitmentProcessed для быстрого подтверждения транзакции при продаже
-	err = d.client.WaitForTransactionConfirmation(ctx, sig, rpc.CommitmentProcessed)
 	if err != nil {
-		d.logger.Warn("⚠️  Confirmation failed for " + sig.String()[:8] + "...: " + err.Error())
-		return sig, fmt.Errorf("transaction confirmed but with error: %w", err)
+		d.logger.Warn("⚠️  Confirmation failed for " + landed.Signature.String()[:8] + "...: " + err.Error())
+		return landed.Signature, fmt.Errorf("transaction confirmed but with error: %w", err)
 	}
 
-	d.logger.Info("✅ Transaction confirmed: " + sig.String()[:8] + "...")
-	return sig, nil
+	d.logger.Info("✅ Transaction confirmed: " + landed.Signature.String()[:8] + "...")
+	if landed.Attempt > 0 || landed.Resubmission > 0 {
+		trace.SetLanded(landed.Signature, landed.MicroLamports)
+	}
+	trace.MarkConfirmed()
+	return landed.Signature, nil
 }
 
 // preparePriorityInstructions подготавливает инструкции для установки лимита и цены вычислительных единиц.
@@ -110,7 +157,7 @@ func (d *DEX) submitAndConfirmTransaction(ctx context.Context, tx *solana.Transa
 // Метод создает инструкции для управления вычислительными ресурсами транзакции:
 // установка лимита вычислительных единиц и их стоимости (приоритетная комиссия).
 // Приоритетная комиссия преобразуется из SOL в микро-лампорты (1 SOL = 1e12 микро-лампортов).
-func (d *DEX) preparePriorityInstructions(computeUnits uint32, priorityFeeSol string) ([]solana.Instruction, error) {
+func (d *DEX) preparePriorityInstructions(ctx context.Context, accounts []solana.PublicKey, computeUnits uint32, priorityFeeSol string) ([]solana.Instruction, error) {
 	var instructions []solana.Instruction
 
 	// Set compute unit limit, использовать значение по умолчанию если не указано
@@ -122,10 +169,19 @@ func (d *DEX) preparePriorityInstructions(computeUnits uint32, priorityFeeSol st
 
 	// Handle priority fee
 	var priorityFee uint64
-	if priorityFeeSol == "default" || priorityFeeSol == "" {
+	switch {
+	case priorityFeeSol == "default" || priorityFeeSol == "":
 		priorityFee = 5_000 // Default priority fee (5000 micro-lamports)
 		d.logger.Debug(fmt.Sprintf("Using default priority fee: %.6f SOL", float64(priorityFee)/1_000_000_000_000))
-	} else {
+	case blockchain.IsAutoFee(priorityFeeSol):
+		fee, err := d.client.PriorityFee(ctx, priorityFeeSol, accounts)
+		if err != nil {
+			d.logger.Warn(fmt.Sprintf("Priority fee estimate failed, using default: %v", err))
+			fee = 5_000
+		}
+		priorityFee = fee
+		d.logger.Debug(fmt.Sprintf("Auto priority fee: %d micro-lamports", priorityFee))
+	default:
 		var solValue float64
 		// Используем fmt.Sscanf вместо strconv.ParseFloat
 		if _, err := fmt.Sscanf(priorityFeeSol, "%f", &solValue); err != nil {
@@ -139,6 +195,7 @@ func (d *DEX) preparePriorityInstructions(computeUnits uint32, priorityFeeSol st
 
 	instructions = append(instructions,
 		computebudget.NewSetComputeUnitPriceInstruction(priorityFee).Build())
+	execution.FromContext(ctx).SetPriorityFee(priorityFee, computeUnits)
 
 	return instructions, nil
 }
@@ -169,7 +226,7 @@ func (d *DEX) prepareSwapParams(
 		PoolQuoteTokenAccount:            pool.PoolQuoteTokenAccount,
 		ProtocolFeeRecipient:             accounts.ProtocolFeeRecipient,
 		ProtocolFeeRecipientTokenAccount: accounts.ProtocolFeeRecipientATA,
-		BaseTokenProgram:                 TokenProgramID,
+		BaseTokenProgram:                 accounts.BaseTokenProgram,
 		QuoteTokenProgram:                TokenProgramID,
 		EventAuthority:                   d.config.EventAuthority,
 		ProgramID:                        d.config.ProgramID,
@@ -186,7 +243,9 @@ func (d *DEX) prepareSwapParams(
 // Инструкции выполняются в следующем порядке:
 // 1) Приоритетные инструкции (установка лимита и цены CU)
 // 2) Создание ассоциированных токен-аккаунтов пользователя (если не существуют)
-// 3) Непосредственно инструкция свопа
+// 3) Для buy с квотой в SOL — перевод суммы с запасом на slippage в wSOL-аккаунт и SyncNative
+// 4) Непосредственно инструкция свопа
+// 5) Для квоты в SOL — закрытие wSOL-аккаунта: остаток и выручка возвращаются нативным SOL
 func (d *DEX) buildSwapTransaction(
 	pool *PoolInfo,
 	accounts *PreparedTokenAccounts,
@@ -204,16 +263,15 @@ func (d *DEX) buildSwapTransaction(
 	origBaseAmount := baseAmount
 	origQuoteAmount := quoteAmount
 
-	// Скорректированные под slippage amounts:
+	// Скорректированные под slippage amounts: границы уходят в инструкцию и проверяются программой on-chain
+	guard := model.GuardOrDefault(d.config.SlippageGuard)
 	if isBuy {
 		// Для buy: quoteAmount — это сколько мы платим → делаем буфер сверху
-		maxQuoteIn := uint64(float64(quoteAmount) * (1 + slippagePercent/100.0))
-		quoteAmount = maxQuoteIn
+		quoteAmount = guard.MaxIn(quoteAmount, slippagePercent)
 		// baseAmount (ожидаемый выход) оставляем как есть
 	} else {
 		// Для sell: quoteAmount — это ожидаемый выход → убираем буфер снизу
-		minQuoteOut := uint64(float64(quoteAmount) * (1 - slippagePercent/100.0))
-		quoteAmount = minQuoteOut
+		quoteAmount = guard.MinOut(quoteAmount, slippagePercent)
 		// baseAmount (сколько мы отдаем) оставляем как есть
 	}
 
@@ -224,7 +282,28 @@ func (d *DEX) buildSwapTransaction(
 	swapParams := d.prepareSwapParams(pool, accounts, isBuy, baseAmount, quoteAmount)
 	swapIx := createSwapInstruction(swapParams)
 
-	return append(instructions, swapIx)
+	wrapSOL := pool.QuoteMint.Equals(solana.SolMint)
+	if wrapSOL && isBuy {
+		instructions = append(instructions,
+			wrapSOLInstructions(d.wallet.PublicKey, accounts.UserQuoteATA, quoteAmount)...)
+	}
+	instructions = append(instructions, swapIx)
+	if wrapSOL {
+		instructions = append(instructions, unwrapSOLInstruction(d.wallet.PublicKey, accounts.UserQuoteATA))
+	}
+	return instructions
+}
+
+// createQuoteATAInstruction создает инструкцию для ATA квотного токена. wSOL-аккаунт
+// закрывается в конце свопа, и депозит уходит владельцу вместе с выручкой, поэтому
+// его открывает сам владелец, а не плательщик комиссий: иначе рента плательщика
+// каждый раз переходила бы на торгующий кошелек.
+func (d *DEX) createQuoteATAInstruction(quoteMint solana.PublicKey) solana.Instruction {
+	payer := d.wallet.Payer()
+	if quoteMint.Equals(solana.SolMint) {
+		payer = d.wallet.PublicKey
+	}
+	return d.wallet.CreateAssociatedTokenAccountIdempotentInstruction(payer, d.wallet.PublicKey, quoteMint)
 }
 
 // prepareTokenAccounts подготавливает ATA пользователя и инструкции для их создания.
@@ -233,7 +312,12 @@ func (d *DEX) buildSwapTransaction(
 // квотного токенов, создает инструкции для их создания (в случае отсутствия)
 // и получает информацию о получателе комиссии протокола из глобальной конфигурации.
 func (d *DEX) prepareTokenAccounts(ctx context.Context, pool *PoolInfo) (*PreparedTokenAccounts, error) {
-	userBaseATA, _, err := solana.FindAssociatedTokenAddress(d.wallet.PublicKey, pool.BaseMint)
+	// Базовый токен может быть выпущен Token-2022: от программы зависят адрес ATA и инструкции
+	baseProgram, err := d.tokenProgram(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userBaseATA, err := blockchain.FindAssociatedTokenAddress(d.wallet.PublicKey, pool.BaseMint, baseProgram)
 	if err != nil {
 		return nil, err
 	}
@@ -243,10 +327,9 @@ func (d *DEX) prepareTokenAccounts(ctx context.Context, pool *PoolInfo) (*Prepar
 		return nil, err
 	}
 
-	createBaseATAIx := d.wallet.CreateAssociatedTokenAccountIdempotentInstruction(
-		d.wallet.PublicKey, d.wallet.PublicKey, pool.BaseMint)
-	createQuoteATAIx := d.wallet.CreateAssociatedTokenAccountIdempotentInstruction(
-		d.wallet.PublicKey, d.wallet.PublicKey, pool.QuoteMint)
+	createBaseATAIx := d.wallet.CreateTokenAccountIdempotentInstruction(
+		d.wallet.Payer(), d.wallet.PublicKey, pool.BaseMint, baseProgram)
+	createQuoteATAIx := d.createQuoteATAInstruction(pool.QuoteMint)
 
 	globalConfig, err := d.getGlobalConfig(ctx)
 	if err != nil {
@@ -290,6 +373,7 @@ func (d *DEX) prepareTokenAccounts(ctx context.Context, pool *PoolInfo) (*Prepar
 
 	return &PreparedTokenAccounts{
 		UserBaseATA:               userBaseATA,
+		BaseTokenProgram:          baseProgram,
 		UserQuoteATA:              userQuoteATA,
 		ProtocolFeeRecipientATA:   protocolFeeRecipientATA,
 		ProtocolFeeRecipient:      protocolFeeRecipient,
@@ -73,6 +73,7 @@ type PoolInfo struct {
 
 type PreparedTokenAccounts struct {
 	UserBaseATA               solana.PublicKey
+	BaseTokenProgram          solana.PublicKey // SPL Token или Token-2022 базового токена
 	UserQuoteATA              solana.PublicKey
 	ProtocolFeeRecipientATA   solana.PublicKey
 	ProtocolFeeRecipient      solana.PublicKey
@@ -98,6 +99,10 @@ type DEX struct {
 	cachedPrice      float64
 	cachedPriceTime  time.Time
 	cacheValidPeriod time.Duration
+
+	// Программа токена и комиссия Token-2022 за перевод
+	mintMu    sync.Mutex
+	tokenMint *blockchain.TokenMint
 }
 
 // SwapAmounts содержит результаты расчёта параметров свапа
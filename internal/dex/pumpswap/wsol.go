@@ -0,0 +1,25 @@
+// =============================
+// File: internal/dex/pumpswap/wsol.go
+// =============================
+package pumpswap
+
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+)
+
+// wrapSOLInstructions переводит lamports нативного SOL на wSOL-аккаунт пользователя
+// и синхронизирует его баланс токенов (SyncNative), чтобы своп мог их списать.
+func wrapSOLInstructions(owner, wsolAccount solana.PublicKey, lamports uint64) []solana.Instruction {
+	return []solana.Instruction{
+		system.NewTransferInstruction(lamports, owner, wsolAccount).Build(),
+		token.NewSyncNativeInstruction(wsolAccount).Build(),
+	}
+}
+
+// unwrapSOLInstruction закрывает wSOL-аккаунт: весь его баланс (неизрасходованный
+// или полученный SOL) вместе с депозитом возвращается владельцу нативным SOL.
+func unwrapSOLInstruction(owner, wsolAccount solana.PublicKey) solana.Instruction {
+	return token.NewCloseAccountInstruction(wsolAccount, owner, owner, nil).Build()
+}
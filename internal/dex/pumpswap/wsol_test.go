@@ -0,0 +1,90 @@
+package pumpswap
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwapWrapsQuoteSOL(t *testing.T) {
+	d := guardTestDEX()
+	pool := PoolInfo{BaseReserves: 200_000_000_000_000, QuoteReserves: 80_000_000_000, QuoteMint: solana.SolMint}
+	accounts := &PreparedTokenAccounts{UserQuoteATA: solana.NewWallet().PublicKey()}
+
+	t.Run("buy", func(t *testing.T) {
+		ixs := d.buildSwapTransaction(&pool, accounts, true, 1_000, 1_000_000_000, 5, nil)
+		require.Len(t, ixs, 6)
+
+		transfer, sync, swap, closeIx := ixs[2], ixs[3], ixs[4], ixs[5]
+		assert.Equal(t, solana.SystemProgramID, transfer.ProgramID())
+		data, err := transfer.Data()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1_050_000_000), binary.LittleEndian.Uint64(data[4:12]),
+			"the wrap covers the slippage bound of the swap")
+		assert.Equal(t, accounts.UserQuoteATA, transfer.Accounts()[1].PublicKey)
+
+		assert.Equal(t, solana.TokenProgramID, sync.ProgramID())
+		data, err = sync.Data()
+		require.NoError(t, err)
+		assert.Equal(t, []byte{17}, data)
+
+		data, err = swap.Data()
+		require.NoError(t, err)
+		assert.Equal(t, buyDiscriminator, data[:8])
+
+		assertUnwrap(t, closeIx, accounts.UserQuoteATA, d.wallet.PublicKey)
+	})
+
+	t.Run("sell", func(t *testing.T) {
+		ixs := d.buildSwapTransaction(&pool, accounts, false, 1_000, 1_000_000_000, 5, nil)
+		require.Len(t, ixs, 4)
+
+		data, err := ixs[2].Data()
+		require.NoError(t, err)
+		assert.Equal(t, sellDiscriminator, data[:8])
+		assertUnwrap(t, ixs[3], accounts.UserQuoteATA, d.wallet.PublicKey)
+	})
+
+	t.Run("other quote", func(t *testing.T) {
+		other := pool
+		other.QuoteMint = solana.NewWallet().PublicKey()
+		ixs := d.buildSwapTransaction(&other, accounts, true, 1_000, 1_000_000_000, 5, nil)
+		assert.Len(t, ixs, 3)
+	})
+}
+
+func TestQuoteATA_WSOLFundedByOwner(t *testing.T) {
+	d := guardTestDEX()
+	d.wallet.FeePayer = &task.Wallet{PublicKey: solana.NewWallet().PublicKey()}
+
+	ix := d.createQuoteATAInstruction(solana.SolMint)
+	metas := ix.Accounts()
+	assert.Equal(t, d.wallet.PublicKey, metas[0].PublicKey, "the owner funds the wSOL account it gets the rent back from")
+	assert.Equal(t, d.wallet.PublicKey, metas[2].PublicKey)
+
+	pool := PoolInfo{BaseReserves: 200_000_000_000_000, QuoteReserves: 80_000_000_000, QuoteMint: solana.SolMint}
+	accounts := &PreparedTokenAccounts{UserQuoteATA: metas[1].PublicKey, CreateQuoteATAIx: ix}
+	ixs := d.buildSwapTransaction(&pool, accounts, false, 1_000, 1_000_000_000, 5, nil)
+	assertUnwrap(t, ixs[len(ixs)-1], accounts.UserQuoteATA, d.wallet.PublicKey)
+
+	other := solana.NewWallet().PublicKey()
+	metas = d.createQuoteATAInstruction(other).Accounts()
+	assert.Equal(t, d.wallet.FeePayer.PublicKey, metas[0].PublicKey, "other quote accounts stay open and are funded by the fee payer")
+	assert.Equal(t, d.wallet.PublicKey, metas[2].PublicKey)
+}
+
+func assertUnwrap(t *testing.T, ix solana.Instruction, wsolAccount, owner solana.PublicKey) {
+	t.Helper()
+	assert.Equal(t, solana.TokenProgramID, ix.ProgramID())
+	data, err := ix.Data()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{9}, data)
+	metas := ix.Accounts()
+	assert.Equal(t, wsolAccount, metas[0].PublicKey)
+	assert.Equal(t, owner, metas[1].PublicKey, "unspent SOL and rent go back to the wallet")
+	assert.Equal(t, owner, metas[2].PublicKey)
+}
@@ -29,7 +29,7 @@ func (d *pumpswapDEXAdapter) Execute(ctx context.Context, t *task.Task) error {
 
 	switch t.Operation {
 	case task.OperationSwap:
-		lamports := uint64(t.AmountSol * 1e9)
+		lamports := model.SolToLamports(t.AmountSol)
 		d.logger.Info(fmt.Sprintf("🔄 Pump.swap: %.3f SOL for %s...%s",
 			t.AmountSol,
 			t.TokenMint[:4],
@@ -82,6 +82,14 @@ func (d *pumpswapDEXAdapter) SellPercentTokens(ctx context.Context, tokenMint st
 	return d.inner.SellPercentTokens(ctx, tokenMint, percentToSell, slippage, priorityFee, computeUnits)
 }
 
+// SellTokenAmount продаёт точное количество токенов, предварительно инициализировав DEX.
+func (d *pumpswapDEXAdapter) SellTokenAmount(ctx context.Context, tokenMint string, amount uint64, slippage float64, priorityFee string, computeUnits uint32) error {
+	if err := d.init(ctx, tokenMint, d.makeInitPumpSwap(tokenMint)); err != nil {
+		return fmt.Errorf("init Pump.swap: %w", err)
+	}
+	return d.inner.SellTokenAmount(ctx, tokenMint, amount, slippage, priorityFee, computeUnits)
+}
+
 // GetTokenPrice возвращает цену, предварительно инициализировав DEX.
 func (d *pumpswapDEXAdapter) GetTokenPrice(ctx context.Context, tokenMint string) (float64, error) {
 	if err := d.init(ctx, tokenMint, d.makeInitPumpSwap(tokenMint)); err != nil {
@@ -0,0 +1,69 @@
+// internal/dex/quote.go
+package dex
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultQuoteConcurrency — сколько котировок запрашивается одновременно по умолчанию.
+const DefaultQuoteConcurrency = 8
+
+// QuoteRequest — запрос цены одного токена через адаптер, инициализированный для этого токена.
+type QuoteRequest struct {
+	Mint string
+	DEX  DEX
+}
+
+// Quote — результат котировки одного токена.
+type Quote struct {
+	Mint    string
+	Venue   string
+	Price   float64 // Цена в SOL за токен
+	Latency time.Duration
+	Err     error
+}
+
+// QuoteBatch запрашивает цены параллельно, не больше concurrency запросов одновременно.
+// Результаты приходят в канал по мере готовности, а не в порядке запросов; канал
+// закрывается, когда получены все ответы или отменен ctx.
+func QuoteBatch(ctx context.Context, reqs []QuoteRequest, concurrency int) <-chan Quote {
+	if concurrency <= 0 {
+		concurrency = DefaultQuoteConcurrency
+	}
+
+	out := make(chan Quote, len(reqs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	go func() {
+		defer close(out)
+		defer wg.Wait()
+
+		for _, req := range reqs {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			wg.Add(1)
+			go func(req QuoteRequest) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				out <- quote(ctx, req)
+			}(req)
+		}
+	}()
+
+	return out
+}
+
+func quote(ctx context.Context, req QuoteRequest) Quote {
+	q := Quote{Mint: req.Mint, Venue: req.DEX.GetName()}
+	start := time.Now()
+	q.Price, q.Err = req.DEX.GetTokenPrice(ctx, req.Mint)
+	q.Latency = time.Since(start)
+	return q
+}
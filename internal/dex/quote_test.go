@@ -0,0 +1,95 @@
+package dex
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQuoteDEX struct {
+	price    float64
+	err      error
+	delay    time.Duration
+	inFlight *int32
+	peak     *int32
+}
+
+func (f *fakeQuoteDEX) GetName() string                                         { return "Fake" }
+func (f *fakeQuoteDEX) Execute(context.Context, *task.Task) error               { return nil }
+func (f *fakeQuoteDEX) GetTokenBalance(context.Context, string) (uint64, error) { return 0, nil }
+func (f *fakeQuoteDEX) SellPercentTokens(context.Context, string, float64, float64, string, uint32) error {
+	return nil
+}
+func (f *fakeQuoteDEX) SellTokenAmount(context.Context, string, uint64, float64, string, uint32) error {
+	return nil
+}
+func (f *fakeQuoteDEX) CalculatePnL(context.Context, float64, float64) (*model.PnLResult, error) {
+	return nil, nil
+}
+func (f *fakeQuoteDEX) GetTokenMetadata(context.Context, string) (*model.TokenMetadata, error) {
+	return nil, nil
+}
+
+func (f *fakeQuoteDEX) GetTokenPrice(ctx context.Context, _ string) (float64, error) {
+	n := atomic.AddInt32(f.inFlight, 1)
+	defer atomic.AddInt32(f.inFlight, -1)
+	for {
+		p := atomic.LoadInt32(f.peak)
+		if n <= p || atomic.CompareAndSwapInt32(f.peak, p, n) {
+			break
+		}
+	}
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	return f.price, f.err
+}
+
+func TestQuoteBatch(t *testing.T) {
+	var inFlight, peak int32
+	mk := func(price float64, err error, delay time.Duration) DEX {
+		return &fakeQuoteDEX{price: price, err: err, delay: delay, inFlight: &inFlight, peak: &peak}
+	}
+	reqs := []QuoteRequest{
+		{Mint: "slow", DEX: mk(1, nil, 50*time.Millisecond)},
+		{Mint: "fast", DEX: mk(2, nil, time.Millisecond)},
+		{Mint: "broken", DEX: mk(0, errors.New("no pool"), time.Millisecond)},
+		{Mint: "other", DEX: mk(3, nil, time.Millisecond)},
+	}
+
+	var got []Quote
+	for q := range QuoteBatch(context.Background(), reqs, 2) {
+		got = append(got, q)
+	}
+
+	assert.Len(t, got, 4)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+	assert.NotEqual(t, "slow", got[0].Mint) // Быстрые ответы не ждут медленный
+
+	byMint := make(map[string]Quote)
+	for _, q := range got {
+		byMint[q.Mint] = q
+	}
+	assert.Equal(t, 2.0, byMint["fast"].Price)
+	assert.Equal(t, "Fake", byMint["fast"].Venue)
+	assert.EqualError(t, byMint["broken"].Err, "no pool")
+}
+
+func TestQuoteBatch_Canceled(t *testing.T) {
+	var inFlight, peak int32
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reqs := []QuoteRequest{{Mint: "a", DEX: &fakeQuoteDEX{delay: time.Second, inFlight: &inFlight, peak: &peak}}}
+	for q := range QuoteBatch(ctx, reqs, 1) {
+		assert.ErrorIs(t, q.Err, context.Canceled)
+	}
+}
@@ -0,0 +1,106 @@
+// internal/dex/sim/market.go
+package sim
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// Path — сценарий движения цены симулируемого токена.
+type Path string
+
+const (
+	PathPump Path = "pump" // Рост в 2.5 раза, затем боковик у максимума
+	PathDump Path = "dump" // Короткий рост и затяжное падение
+	PathChop Path = "chop" // Колебания ±15% вокруг стартовой цены
+)
+
+// DefaultStartPrice — стартовая цена токена в SOL, типичная для новой bonding curve Pump.fun.
+const DefaultStartPrice = 0.000000028
+
+// noiseAmplitude — амплитуда детерминированного шума поверх сценария (доля цены).
+const noiseAmplitude = 0.01
+
+// ParsePath разбирает название сценария; пустая строка — PathChop.
+func ParsePath(s string) (Path, error) {
+	switch p := Path(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PathChop, nil
+	case PathPump, PathDump, PathChop:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown sim price path %q (want pump, dump or chop)", s)
+	}
+}
+
+// Market выдает цену токена по шагам сценария. Один и тот же сценарий с тем же
+// seed всегда дает одну и ту же последовательность цен.
+type Market struct {
+	mu    sync.Mutex
+	path  Path
+	start float64
+	step  int
+	price float64
+	noise *rand.Rand
+}
+
+// NewMarket создает рынок со сценарием path и стартовой ценой startPrice (0 — DefaultStartPrice).
+func NewMarket(path Path, startPrice float64, seed int64) *Market {
+	if startPrice <= 0 {
+		startPrice = DefaultStartPrice
+	}
+	return &Market{
+		path:  path,
+		start: startPrice,
+		price: startPrice,
+		noise: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// SeedFor возвращает seed сценария для mint, чтобы у каждого токена был свой, но повторяемый путь.
+func SeedFor(mint string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(mint))
+	return int64(h.Sum64())
+}
+
+// Next сдвигает сценарий на шаг и возвращает новую цену.
+func (m *Market) Next() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.step++
+	noise := 1 + (m.noise.Float64()*2-1)*noiseAmplitude
+	m.price = m.start * multiplier(m.path, m.step) * noise
+	return m.price
+}
+
+// Price возвращает текущую цену без сдвига сценария.
+func (m *Market) Price() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.price
+}
+
+// multiplier — отношение цены к стартовой на шаге n.
+func multiplier(path Path, n int) float64 {
+	x := float64(n)
+	switch path {
+	case PathPump:
+		if n <= 30 {
+			return 1 + 0.05*x
+		}
+		return 2.5 * (1 + 0.03*math.Sin(x/2))
+	case PathDump:
+		if n <= 5 {
+			return 1 + 0.02*x
+		}
+		return math.Max(1.1*math.Pow(0.95, x-5), 0.05)
+	default:
+		return 1 + 0.15*math.Sin(2*math.Pi*x/20)
+	}
+}
@@ -0,0 +1,84 @@
+// internal/dex/sim/sim.go
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+const (
+	// TokenDecimals — десятичные знаки симулируемого токена, как у токенов Pump.fun.
+	TokenDecimals = 6
+	// feePercent — комиссия площадки с каждой сделки.
+	feePercent = 1.0
+)
+
+// ErrNoTokens возвращается при продаже без баланса.
+var ErrNoTokens = errors.New("no tokens to sell")
+
+// DEX — симулируемая площадка: цена идет по сценарию Market, покупки и продажи
+// исполняются мгновенно по текущей цене и меняют только баланс в памяти.
+type DEX struct {
+	market *Market
+
+	mu      sync.Mutex
+	balance uint64 // Баланс токена в минимальных единицах
+}
+
+// NewDEX создает площадку поверх market.
+func NewDEX(market *Market) *DEX {
+	return &DEX{market: market}
+}
+
+// Market возвращает сценарий цены площадки.
+func (d *DEX) Market() *Market {
+	return d.market
+}
+
+// Buy покупает токены на amountSol SOL и возвращает полученное количество в минимальных единицах.
+func (d *DEX) Buy(amountSol float64) (uint64, error) {
+	if amountSol <= 0 {
+		return 0, fmt.Errorf("buy amount must be positive, got %.9f SOL", amountSol)
+	}
+	tokens := d.QuoteBuy(amountSol)
+
+	d.mu.Lock()
+	d.balance += tokens
+	d.mu.Unlock()
+	return tokens, nil
+}
+
+// Sell продает amount токенов (не больше баланса) и возвращает выручку в lamports.
+func (d *DEX) Sell(amount uint64) (uint64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.balance == 0 {
+		return 0, ErrNoTokens
+	}
+	if amount > d.balance {
+		amount = d.balance
+	}
+	d.balance -= amount
+	return d.Quote(amount), nil
+}
+
+// QuoteBuy оценивает количество токенов, получаемых за amountSol SOL по текущей цене.
+func (d *DEX) QuoteBuy(amountSol float64) uint64 {
+	return uint64(amountSol * (1 - feePercent/100) / d.market.Price() * math.Pow10(TokenDecimals))
+}
+
+// Quote оценивает выручку в lamports от продажи amount токенов по текущей цене.
+func (d *DEX) Quote(amount uint64) uint64 {
+	sol := float64(amount) / math.Pow10(TokenDecimals) * d.market.Price() * (1 - feePercent/100)
+	return uint64(sol * 1e9)
+}
+
+// Balance возвращает баланс токена в минимальных единицах.
+func (d *DEX) Balance() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.balance
+}
@@ -0,0 +1,80 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func walk(m *Market, steps int) []float64 {
+	prices := make([]float64, steps)
+	for i := range prices {
+		prices[i] = m.Next()
+	}
+	return prices
+}
+
+func TestMarketIsDeterministic(t *testing.T) {
+	seed := SeedFor("So11111111111111111111111111111111111111112")
+	a := walk(NewMarket(PathChop, 0, seed), 50)
+	b := walk(NewMarket(PathChop, 0, seed), 50)
+	assert.Equal(t, a, b)
+
+	c := walk(NewMarket(PathChop, 0, seed+1), 50)
+	assert.NotEqual(t, a, c, "another seed gives another path")
+}
+
+func TestMarketPaths(t *testing.T) {
+	const start = 0.00001
+
+	pump := walk(NewMarket(PathPump, start, 1), 40)
+	assert.InDelta(t, 2.5*start, pump[29], 0.05*start, "pump peaks at 2.5x")
+	assert.Greater(t, pump[39], 2*start)
+
+	dump := walk(NewMarket(PathDump, start, 1), 60)
+	assert.Greater(t, dump[4], start)
+	assert.Less(t, dump[59], 0.1*start)
+
+	chop := walk(NewMarket(PathChop, start, 1), 100)
+	for _, p := range chop {
+		assert.InDelta(t, start, p, 0.17*start)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	p, err := ParsePath("")
+	require.NoError(t, err)
+	assert.Equal(t, PathChop, p)
+
+	p, err = ParsePath(" Pump ")
+	require.NoError(t, err)
+	assert.Equal(t, PathPump, p)
+
+	_, err = ParsePath("moon")
+	assert.Error(t, err)
+}
+
+func TestDEXBuySell(t *testing.T) {
+	d := NewDEX(NewMarket(PathPump, 0.00001, 1))
+
+	tokens, err := d.Buy(0.1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9_900_000_000), tokens, "0.1 SOL minus 1% fee at 0.00001 SOL per token")
+	assert.Equal(t, tokens, d.Balance())
+
+	for i := 0; i < 30; i++ {
+		d.Market().Next()
+	}
+	lamports, err := d.Sell(tokens / 2)
+	require.NoError(t, err)
+	assert.Greater(t, lamports, uint64(100_000_000), "half the bag is worth more than the buy after a 2.5x pump")
+	assert.Equal(t, tokens-tokens/2, d.Balance())
+
+	_, err = d.Sell(tokens)
+	require.NoError(t, err, "selling more than the balance sells the balance")
+	assert.Zero(t, d.Balance())
+
+	_, err = d.Sell(1)
+	assert.ErrorIs(t, err, ErrNoTokens)
+}
@@ -0,0 +1,130 @@
+// internal/dex/sim_adapter.go
+package dex
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/sim"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// simDEXAdapter — симулируемая площадка для демо и разработки UI: цена идет по
+// заданному сценарию, сделки не отправляются в сеть и меняют только баланс в памяти.
+type simDEXAdapter struct {
+	baseDEXAdapter
+	path  sim.Path
+	inner *sim.DEX
+}
+
+// Execute исполняет покупку или продажу на симулируемой площадке.
+func (d *simDEXAdapter) Execute(ctx context.Context, t *task.Task) error {
+	if t.TokenMint == "" {
+		return fmt.Errorf("token mint is required for Sim DEX")
+	}
+	if err := d.init(ctx, t.TokenMint, d.makeInitSim(t.TokenMint)); err != nil {
+		return err
+	}
+
+	switch t.Operation {
+	case task.OperationSnipe, task.OperationSwap:
+		execution.FromContext(ctx).SetQuote(d.inner.QuoteBuy(t.AmountSol))
+		tokens, err := d.inner.Buy(t.AmountSol)
+		if err != nil {
+			return err
+		}
+		d.logger.Info(fmt.Sprintf("🧪 Sim buy: %.3f SOL → %.2f tokens at %.10f SOL (%s path)",
+			t.AmountSol, float64(tokens)/math.Pow10(sim.TokenDecimals), d.inner.Market().Price(), d.path))
+		return nil
+
+	case task.OperationSell:
+		return d.sell(ctx, d.inner.Balance())
+
+	default:
+		return fmt.Errorf("unsupported operation %s on Sim DEX", t.Operation)
+	}
+}
+
+// GetTokenPrice сдвигает сценарий на шаг и возвращает новую цену.
+func (d *simDEXAdapter) GetTokenPrice(ctx context.Context, tokenMint string) (float64, error) {
+	if err := d.init(ctx, tokenMint, d.makeInitSim(tokenMint)); err != nil {
+		return 0, err
+	}
+	return d.inner.Market().Next(), nil
+}
+
+// GetTokenBalance возвращает симулируемый баланс токена.
+func (d *simDEXAdapter) GetTokenBalance(ctx context.Context, tokenMint string) (uint64, error) {
+	if err := d.init(ctx, tokenMint, d.makeInitSim(tokenMint)); err != nil {
+		return 0, err
+	}
+	return d.inner.Balance(), nil
+}
+
+// SellPercentTokens продает процент симулируемого баланса.
+func (d *simDEXAdapter) SellPercentTokens(ctx context.Context, tokenMint string, pct, _ float64, _ string, _ uint32) error {
+	if pct <= 0 || pct > 100 {
+		return fmt.Errorf("percent to sell must be between 0 and 100")
+	}
+	if err := d.init(ctx, tokenMint, d.makeInitSim(tokenMint)); err != nil {
+		return err
+	}
+	return d.sell(ctx, model.PercentOf(d.inner.Balance(), pct))
+}
+
+// SellTokenAmount продает точное количество симулируемых токенов.
+func (d *simDEXAdapter) SellTokenAmount(ctx context.Context, tokenMint string, amount uint64, _ float64, _ string, _ uint32) error {
+	if err := d.init(ctx, tokenMint, d.makeInitSim(tokenMint)); err != nil {
+		return err
+	}
+	if amount == 0 || amount > d.inner.Balance() {
+		return fmt.Errorf("amount to sell %d is not within the balance %d", amount, d.inner.Balance())
+	}
+	return d.sell(ctx, amount)
+}
+
+// CalculatePnL оценивает PnL по текущей цене сценария.
+func (d *simDEXAdapter) CalculatePnL(ctx context.Context, amount, invest float64) (*model.PnLResult, error) {
+	d.mu.Lock()
+	tokenMint := d.tokenMint
+	d.mu.Unlock()
+
+	if err := d.init(ctx, tokenMint, d.makeInitSim(tokenMint)); err != nil {
+		return nil, err
+	}
+	estimate := float64(d.inner.Quote(uint64(amount*math.Pow10(sim.TokenDecimals)))) / 1e9
+	res := &model.PnLResult{InitialInvestment: invest, SellEstimate: estimate, NetPnL: estimate - invest}
+	if invest > 0 {
+		res.PnLPercentage = res.NetPnL / invest * 100
+	}
+	return res, nil
+}
+
+// GetTokenMetadata называет симулируемый токен по сценарию цены, не обращаясь к сети.
+func (d *simDEXAdapter) GetTokenMetadata(_ context.Context, _ string) (*model.TokenMetadata, error) {
+	return &model.TokenMetadata{Name: fmt.Sprintf("Sim %s", d.path), Symbol: "SIM", Source: "sim"}, nil
+}
+
+func (d *simDEXAdapter) sell(ctx context.Context, amount uint64) error {
+	execution.FromContext(ctx).SetQuote(d.inner.Quote(amount))
+	lamports, err := d.inner.Sell(amount)
+	if err != nil {
+		return err
+	}
+	d.logger.Info(fmt.Sprintf("🧪 Sim sell: %.2f tokens → %.6f SOL at %.10f SOL (%s path)",
+		float64(amount)/math.Pow10(sim.TokenDecimals), float64(lamports)/1e9, d.inner.Market().Price(), d.path))
+	return nil
+}
+
+// makeInitSim возвращает initFn, создающую рынок со сценарием адаптера.
+func (d *simDEXAdapter) makeInitSim(tokenMint string) func() error {
+	return func() error {
+		if d.inner == nil {
+			d.inner = sim.NewDEX(sim.NewMarket(d.path, 0, sim.SeedFor(tokenMint)))
+		}
+		return nil
+	}
+}
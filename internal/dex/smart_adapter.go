@@ -8,102 +8,179 @@ import (
 	"fmt"
 	"go.uber.org/zap"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/gagliardetto/solana-go"
 	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpswap"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 )
 
+// smartDEXAdapter торгует токеном на его живой площадке: на bonding curve Pump.fun,
+// а после миграции — в пуле PumpSwap или через Jupiter, если пула PumpSwap нет.
+// Площадка перепроверяется при каждой операции (с кешем migrationDetector), поэтому
+// позиция, купленная на curve, продается уже в пуле.
 type smartDEXAdapter struct {
 	baseDEXAdapter
 	pumpfunAdapter  *pumpfunDEXAdapter
 	pumpswapAdapter *pumpswapDEXAdapter
-	// выбранный DEX: pumpfun или pumpswap
-	dex DEX
+	jupiterAdapter  *jupiterDEXAdapter
+	detector        *migrationDetector
+
+	routeMu     sync.Mutex
+	venue       string // Текущая площадка
+	dex         DEX    // Адаптер текущей площадки
+	onMigration []func(MigrationEvent)
+}
+
+// OnMigration реализует MigrationSource.
+func (d *smartDEXAdapter) OnMigration(fn func(MigrationEvent)) {
+	d.routeMu.Lock()
+	d.onMigration = append(d.onMigration, fn)
+	d.routeMu.Unlock()
 }
 
 func (d *smartDEXAdapter) Execute(ctx context.Context, t *task.Task) error {
 	if t.TokenMint == "" {
 		return fmt.Errorf("token mint is required")
 	}
-	// один раз выбираем DEX
-	if d.dex == nil {
-		dex, err := d.determineDEX(ctx, t.TokenMint)
-		if err != nil {
-			return fmt.Errorf("determine DEX: %w", err)
-		}
-		d.dex = dex
-	}
-
 	// проксируем tokenMint в базовом адаптере
 	d.mu.Lock()
 	d.tokenMint = t.TokenMint
 	d.mu.Unlock()
 
-	// готовим таск
+	target, venue, err := d.route(ctx, t.TokenMint)
+	if err != nil {
+		return err
+	}
+
+	// готовим таск; продажа остается продажей на выбранном DEX
 	adaptedTask := *t
-	if d.dex == d.pumpfunAdapter {
+	if t.Operation == task.OperationSell {
+		return target.Execute(ctx, &adaptedTask)
+	}
+	token := zap.String("token", t.TokenMint[:4]+"..."+t.TokenMint[len(t.TokenMint)-4:])
+	switch venue {
+	case VenuePumpFun:
 		adaptedTask.Operation = task.OperationSnipe
-		d.logger.Info("🎯 Smart DEX selected: Pump.fun (bonding curve active)", zap.String("token", t.TokenMint[:4]+"..."+t.TokenMint[len(t.TokenMint)-4:]))
-	} else {
+		d.logger.Info("🎯 Smart DEX selected: Pump.fun (bonding curve active)", token)
+	case VenuePumpSwap:
 		adaptedTask.Operation = task.OperationSwap
-		d.logger.Info("🎯 Smart DEX selected: Pump.swap (bonding curve completed)", zap.String("token", t.TokenMint[:4]+"..."+t.TokenMint[len(t.TokenMint)-4:]))
+		d.logger.Info("🎯 Smart DEX selected: Pump.swap (bonding curve completed)", token)
+	default:
+		adaptedTask.Operation = task.OperationSwap
+		d.logger.Info("🎯 Smart DEX selected: Jupiter (bonding curve completed, no PumpSwap pool)", token)
 	}
 
 	// выполняем
-	err := d.dex.Execute(ctx, &adaptedTask)
-	// fallback по AnchorError 6005 (BondingCurveComplete)
-	if isBondingCurveCompleteError(err) && d.dex == d.pumpfunAdapter {
-		d.logger.Info("🔄 Bonding curve completed, switching to Pump.swap", zap.String("token", t.TokenMint[:4]+"..."+t.TokenMint[len(t.TokenMint)-4:]))
-		d.dex = d.pumpswapAdapter
+	err = target.Execute(ctx, &adaptedTask)
+	// fallback по AnchorError 6005 (BondingCurveComplete): токен мигрировал между проверками
+	if isBondingCurveCompleteError(err) && venue == VenuePumpFun {
+		d.logger.Info("🔄 Bonding curve completed, re-routing", token)
+		d.detector.Invalidate()
+		if target, venue, err = d.route(ctx, t.TokenMint); err != nil {
+			return err
+		}
+		if venue == VenuePumpFun {
+			return fmt.Errorf("bonding curve completed but the token has not migrated yet")
+		}
 		adaptedTask.Operation = task.OperationSwap
-		return d.dex.Execute(ctx, &adaptedTask)
+		return target.Execute(ctx, &adaptedTask)
 	}
 	return err
 }
 
-func (d *smartDEXAdapter) determineDEX(ctx context.Context, tokenMint string) (DEX, error) {
-	if d.pumpfunAdapter == nil {
-		d.pumpfunAdapter = &pumpfunDEXAdapter{
-			baseDEXAdapter: baseDEXAdapter{
-				client: d.client,
-				wallet: d.wallet,
-				logger: d.logger.Named("pumpfun"),
-				name:   "Pump.fun",
-			},
-		}
+// route возвращает адаптер живой площадки токена и сообщает подписчикам о миграции,
+// если площадка сменилась.
+func (d *smartDEXAdapter) route(ctx context.Context, tokenMint string) (DEX, string, error) {
+	d.routeMu.Lock()
+	if d.detector == nil {
+		d.initAdapters(tokenMint)
+	}
+	detector := d.detector
+	d.routeMu.Unlock()
+
+	venue, err := detector.Venue(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("determine DEX: %w", err)
+	}
+
+	d.routeMu.Lock()
+	from := d.venue
+	d.venue = venue
+	switch venue {
+	case VenuePumpFun:
+		d.dex = d.pumpfunAdapter
+	case VenuePumpSwap:
+		d.dex = d.pumpswapAdapter
+	default:
+		d.dex = d.jupiterAdapter
 	}
-	if d.pumpswapAdapter == nil {
-		d.pumpswapAdapter = &pumpswapDEXAdapter{
-			baseDEXAdapter: baseDEXAdapter{
-				client: d.client,
-				wallet: d.wallet,
-				logger: d.logger.Named("pumpswap"),
-				name:   "Pump.Swap",
-			},
+	target := d.dex
+	listeners := d.onMigration
+	d.routeMu.Unlock()
+
+	if from != "" && from != venue {
+		ev := MigrationEvent{Mint: tokenMint, From: from, To: venue, At: time.Now()}
+		d.logger.Info(fmt.Sprintf("🚚 Token migrated from %s to %s, routing trades there", from, venue),
+			zap.String("token", tokenMint))
+		for _, fn := range listeners {
+			fn(ev)
 		}
 	}
+	return target, venue, nil
+}
 
-	if err := d.pumpfunAdapter.init(ctx, tokenMint, d.pumpfunAdapter.makeInitPumpFun(tokenMint)); err != nil {
-		d.logger.Warn("Failed to initialize PumpFun adapter, falling back to PumpSwap",
-			zap.String("token_mint", tokenMint),
-			zap.Error(err))
-		return d.pumpswapAdapter, fmt.Errorf("pumpfun initialization failed: %w", err)
+// initAdapters создает адаптеры площадок и детектор миграции для токена.
+func (d *smartDEXAdapter) initAdapters(tokenMint string) {
+	d.pumpfunAdapter = &pumpfunDEXAdapter{
+		baseDEXAdapter: baseDEXAdapter{
+			client: d.client,
+			wallet: d.wallet,
+			logger: d.logger.Named("pumpfun"),
+			name:   "Pump.fun",
+		},
 	}
-	if d.pumpfunAdapter.inner == nil {
-		return d.pumpswapAdapter, nil
+	d.pumpswapAdapter = &pumpswapDEXAdapter{
+		baseDEXAdapter: baseDEXAdapter{
+			client: d.client,
+			wallet: d.wallet,
+			logger: d.logger.Named("pumpswap"),
+			name:   "Pump.Swap",
+		},
+	}
+	d.jupiterAdapter = &jupiterDEXAdapter{
+		baseDEXAdapter: baseDEXAdapter{
+			client: d.client,
+			wallet: d.wallet,
+			logger: d.logger.Named("jupiter"),
+			name:   "Jupiter",
+		},
 	}
 
-	isComplete, err := d.pumpfunAdapter.inner.IsBondingCurveComplete(ctx)
-	if err != nil {
-		d.logger.Warn("Failed to check if bonding curve is complete, falling back to PumpSwap",
-			zap.String("token_mint", tokenMint),
-			zap.Error(err))
-		return d.pumpswapAdapter, fmt.Errorf("checking bonding curve status failed: %w", err)
+	curveComplete := func(ctx context.Context) (bool, error) {
+		if err := d.pumpfunAdapter.init(ctx, tokenMint, d.pumpfunAdapter.makeInitPumpFun(tokenMint)); err != nil {
+			return false, fmt.Errorf("pumpfun initialization failed: %w", err)
+		}
+		if d.pumpfunAdapter.inner == nil {
+			return true, nil
+		}
+		complete, err := d.pumpfunAdapter.inner.IsBondingCurveComplete(ctx)
+		if err != nil {
+			return false, fmt.Errorf("checking bonding curve status failed: %w", err)
+		}
+		return complete, nil
 	}
-	if isComplete {
-		return d.pumpswapAdapter, nil
+	poolExists := func(ctx context.Context) (bool, error) {
+		mint, err := solana.PublicKeyFromBase58(tokenMint)
+		if err != nil {
+			return false, fmt.Errorf("invalid token mint: %w", err)
+		}
+		_, err = pumpswap.NewPoolManager(d.client, d.logger.Named("pumpswap")).FindPool(ctx, solana.SolMint, mint)
+		return err == nil, nil
 	}
-	return d.pumpfunAdapter, nil
+	d.detector = newMigrationDetector(curveComplete, poolExists)
 }
 
 func isBondingCurveCompleteError(err error) bool {
@@ -114,30 +191,44 @@ func (d *smartDEXAdapter) GetTokenPrice(ctx context.Context, tokenMint string) (
 	d.mu.Lock()
 	d.tokenMint = tokenMint
 	d.mu.Unlock()
-	if d.dex == nil {
-		return 0, fmt.Errorf("DEX not initialized")
+	target, _, err := d.route(ctx, tokenMint)
+	if err != nil {
+		return 0, err
 	}
-	return d.dex.GetTokenPrice(ctx, tokenMint)
+	return target.GetTokenPrice(ctx, tokenMint)
 }
 
 func (d *smartDEXAdapter) GetTokenBalance(ctx context.Context, tokenMint string) (uint64, error) {
 	d.mu.Lock()
 	d.tokenMint = tokenMint
 	d.mu.Unlock()
-	if d.dex == nil {
-		return 0, fmt.Errorf("DEX not initialized")
+	target, _, err := d.route(ctx, tokenMint)
+	if err != nil {
+		return 0, err
 	}
-	return d.dex.GetTokenBalance(ctx, tokenMint)
+	return target.GetTokenBalance(ctx, tokenMint)
 }
 
 func (d *smartDEXAdapter) SellPercentTokens(ctx context.Context, tokenMint string, pct, slip float64, fee string, cu uint32) error {
 	d.mu.Lock()
 	d.tokenMint = tokenMint
 	d.mu.Unlock()
-	if d.dex == nil {
-		return fmt.Errorf("DEX not initialized")
+	target, _, err := d.route(ctx, tokenMint)
+	if err != nil {
+		return err
+	}
+	return target.SellPercentTokens(ctx, tokenMint, pct, slip, fee, cu)
+}
+
+func (d *smartDEXAdapter) SellTokenAmount(ctx context.Context, tokenMint string, amount uint64, slip float64, fee string, cu uint32) error {
+	d.mu.Lock()
+	d.tokenMint = tokenMint
+	d.mu.Unlock()
+	target, _, err := d.route(ctx, tokenMint)
+	if err != nil {
+		return err
 	}
-	return d.dex.SellPercentTokens(ctx, tokenMint, pct, slip, fee, cu)
+	return target.SellTokenAmount(ctx, tokenMint, amount, slip, fee, cu)
 }
 
 func (d *smartDEXAdapter) CalculatePnL(ctx context.Context, amount, invest float64) (*model.PnLResult, error) {
@@ -147,8 +238,11 @@ func (d *smartDEXAdapter) CalculatePnL(ctx context.Context, amount, invest float
 	if tokenMint == "" {
 		return nil, fmt.Errorf("token mint is not set")
 	}
-	if d.dex == nil {
+	d.routeMu.Lock()
+	target := d.dex
+	d.routeMu.Unlock()
+	if target == nil {
 		return nil, fmt.Errorf("DEX not initialized")
 	}
-	return d.dex.CalculatePnL(ctx, amount, invest)
+	return target.CalculatePnL(ctx, amount, invest)
 }
@@ -22,7 +22,11 @@ type DEX interface {
 	GetTokenBalance(ctx context.Context, tokenMint string) (uint64, error)
 	// SellPercentTokens продает указанный процент имеющихся токенов
 	SellPercentTokens(ctx context.Context, tokenMint string, percentToSell float64, slippagePercent float64, priorityFeeSol string, computeUnits uint32) error
+	// SellTokenAmount продает ровно amount базовых единиц токена
+	SellTokenAmount(ctx context.Context, tokenMint string, amount uint64, slippagePercent float64, priorityFeeSol string, computeUnits uint32) error
 	// CalculatePnL вычисляет метрики прибыли и убытка для заданного количества токенов и начальных инвестиций
-	// Учитывает только протокольные комиссии. Slippage не учитывается.
+	// Учитывает комиссии площадки (на Pump.fun — и комиссию создателя токена). Slippage не учитывается.
 	CalculatePnL(ctx context.Context, tokenAmount float64, initialInvestment float64) (*model.PnLResult, error)
+	// GetTokenMetadata возвращает название, символ и картинку токена
+	GetTokenMetadata(ctx context.Context, tokenMint string) (*model.TokenMetadata, error)
 }
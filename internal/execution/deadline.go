@@ -0,0 +1,49 @@
+// internal/execution/deadline.go
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WithBudget ограничивает операцию целиком (котировка, сборка, отправка, подтверждение)
+// бюджетом времени; budget <= 0 — без общего срока.
+func WithBudget(ctx context.Context, budget time.Duration) (context.Context, context.CancelFunc) {
+	if budget <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, budget)
+}
+
+// StageContext ограничивает этап таймаутом timeout, только если у операции нет общего срока.
+// Если срок задан через WithBudget, этап может расходовать весь оставшийся бюджет.
+func StageContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// BudgetUsage описывает, какую долю бюджета сделки занял каждый этап, например
+// "quote 2% → blockhash 1% → send 1% → confirm 30% (34% of 1m0s)". Пусто без бюджета.
+func (r Record) BudgetUsage() string {
+	if r.Budget <= 0 {
+		return ""
+	}
+	budget := time.Duration(r.Budget) * time.Millisecond
+	share := func(d time.Duration) float64 { return float64(d) / float64(budget) * 100 }
+
+	parts := make([]string, 0, len(r.Stages)+1)
+	prev := r.StartedAt
+	for _, st := range r.Stages {
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", st.Name, share(st.At.Sub(prev))))
+		prev = st.At
+	}
+	if !r.ConfirmedAt.IsZero() {
+		parts = append(parts, fmt.Sprintf("confirm %.0f%%", share(r.ConfirmedAt.Sub(prev))))
+		prev = r.ConfirmedAt
+	}
+	return fmt.Sprintf("%s (%.0f%% of %s)", strings.Join(parts, " → "), share(prev.Sub(r.StartedAt)), budget)
+}
@@ -0,0 +1,51 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStageContext_KeepsBudget(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), 2*time.Minute)
+	defer cancel()
+	budgetDeadline, _ := ctx.Deadline()
+
+	// Этап не сокращает срок, заданный бюджетом
+	stageCtx, stageCancel := StageContext(ctx, time.Second)
+	defer stageCancel()
+	deadline, ok := stageCtx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, budgetDeadline, deadline)
+
+	// Без бюджета действует таймаут этапа
+	plain, plainCancel := StageContext(context.Background(), time.Second)
+	defer plainCancel()
+	deadline, ok = plain.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+	unbounded, unboundedCancel := WithBudget(context.Background(), 0)
+	defer unboundedCancel()
+	_, ok = unbounded.Deadline()
+	assert.False(t, ok)
+}
+
+func TestRecord_BudgetUsage(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rec := Record{
+		StartedAt: start,
+		Budget:    10_000,
+		Stages: []Stage{
+			{Name: StageQuote, At: start.Add(500 * time.Millisecond)},
+			{Name: StageSend, At: start.Add(time.Second)},
+		},
+		ConfirmedAt: start.Add(4 * time.Second),
+	}
+	assert.Equal(t, "quote 5% → send 5% → confirm 30% (40% of 10s)", rec.BudgetUsage())
+
+	rec.Budget = 0
+	assert.Empty(t, rec.BudgetUsage())
+}
@@ -0,0 +1,138 @@
+// internal/execution/fees.go
+package execution
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// feePeakRatio — во сколько раз средняя комиссия группы должна превышать общую, чтобы попасть в рекомендации.
+	feePeakRatio = 2.0
+	// feePeakMinTrades — минимум сделок в группе для рекомендации.
+	feePeakMinTrades = 3
+)
+
+// FeeStats — расход на комиссии в одной группе сделок (час суток или площадка).
+type FeeStats struct {
+	Key         string
+	Trades      int
+	FeePaid     uint64 // Вся уплаченная комиссия (lamports)
+	PriorityFee uint64 // Часть комиссии сверх базовой: priority fee и чаевые (lamports)
+}
+
+// AvgFee возвращает среднюю комиссию на сделку в lamports.
+func (s FeeStats) AvgFee() float64 {
+	if s.Trades == 0 {
+		return 0
+	}
+	return float64(s.FeePaid) / float64(s.Trades)
+}
+
+// FeesByHour группирует расход на комиссии по часу суток начала сделки (локальное время).
+// Учитываются и неуспешные сделки: попавшая в блок транзакция платит комиссию в любом случае.
+func FeesByHour(records []Record) []FeeStats {
+	stats := aggregateFees(records, func(r Record) string {
+		return fmt.Sprintf("%02d:00", r.StartedAt.Local().Hour())
+	})
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats
+}
+
+// FeesByVenue группирует расход на комиссии по площадке, начиная с самой дорогой.
+func FeesByVenue(records []Record) []FeeStats {
+	stats := aggregateFees(records, func(r Record) string { return r.Venue })
+	sort.Slice(stats, func(i, j int) bool { return stats[i].FeePaid > stats[j].FeePaid })
+	return stats
+}
+
+func aggregateFees(records []Record, key func(Record) string) []FeeStats {
+	groups := make(map[string]*FeeStats)
+	for _, rec := range records {
+		if rec.FeePaid == 0 {
+			continue // Транзакция не попала в блок
+		}
+		k := key(rec)
+		g, ok := groups[k]
+		if !ok {
+			g = &FeeStats{Key: k}
+			groups[k] = g
+		}
+		g.Trades++
+		g.FeePaid += rec.FeePaid
+		if rec.FeePaid > baseFeeLamports {
+			g.PriorityFee += rec.FeePaid - baseFeeLamports
+		}
+	}
+
+	result := make([]FeeStats, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	return result
+}
+
+// FeeRecommendations подсказывает часы и площадки, где средняя комиссия на сделку
+// заметно выше общей, например из-за ночной перегрузки сети.
+func FeeRecommendations(records []Record) []string {
+	total := aggregateFees(records, func(Record) string { return "all" })
+	if len(total) == 0 {
+		return nil
+	}
+	avg := total[0].AvgFee()
+
+	var recs []string
+	for _, s := range FeesByHour(records) {
+		if s.Trades < feePeakMinTrades || s.AvgFee() < avg*feePeakRatio {
+			continue
+		}
+		recs = append(recs, fmt.Sprintf("Fees peak at %s-%s: %.6f SOL per trade, %.1fx your average; avoid trading then or cap priority_fee",
+			s.Key, nextHour(s.Key), lamportsToSol(uint64(s.AvgFee())), s.AvgFee()/avg))
+	}
+	for _, s := range FeesByVenue(records) {
+		if s.Trades < feePeakMinTrades || s.AvgFee() < avg*feePeakRatio {
+			continue
+		}
+		recs = append(recs, fmt.Sprintf("%s costs %.6f SOL per trade in fees, %.1fx your average; check its priority_fee and compute_units",
+			s.Key, lamportsToSol(uint64(s.AvgFee())), s.AvgFee()/avg))
+	}
+	return recs
+}
+
+// nextHour возвращает конец часового интервала для ключа вида "02:00"
+func nextHour(key string) string {
+	t, err := time.Parse("15:04", key)
+	if err != nil {
+		return key
+	}
+	return t.Add(time.Hour).Format("15:04")
+}
+
+// feeLines формирует разделы по комиссиям для итоговой сводки.
+func feeLines(records []Record) []string {
+	byHour := FeesByHour(records)
+	if len(byHour) == 0 {
+		return nil
+	}
+
+	var lines []string
+	section := func(title string, stats []FeeStats) {
+		lines = append(lines, "  "+title)
+		for _, s := range stats {
+			lines = append(lines, fmt.Sprintf("    %s: %d trades, fee %.6f SOL (priority %.6f SOL), %.6f SOL/trade",
+				s.Key, s.Trades, lamportsToSol(s.FeePaid), lamportsToSol(s.PriorityFee), lamportsToSol(uint64(s.AvgFee()))))
+		}
+	}
+
+	section("Fees by hour:", byHour)
+	section("Fees by venue:", FeesByVenue(records))
+
+	if recs := FeeRecommendations(records); len(recs) > 0 {
+		lines = append(lines, "  Recommendations:")
+		for _, r := range recs {
+			lines = append(lines, "    💡 "+r)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,55 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func feeRecord(hour int, venue string, fee uint64) Record {
+	return Record{
+		Venue:     venue,
+		StartedAt: time.Date(2025, 3, 1, hour, 15, 0, 0, time.Local),
+		FeePaid:   fee,
+	}
+}
+
+func TestFeesByHourAndVenue(t *testing.T) {
+	records := []Record{
+		feeRecord(14, "Pump.fun", 10_000),
+		feeRecord(2, "Pump.fun", 105_000),
+		feeRecord(2, "Pump.Swap", 55_000),
+		feeRecord(14, "Pump.Swap", 0), // Не попала в блок
+	}
+
+	byHour := FeesByHour(records)
+	assert.Len(t, byHour, 2)
+	assert.Equal(t, "02:00", byHour[0].Key)
+	assert.Equal(t, 2, byHour[0].Trades)
+	assert.Equal(t, uint64(160_000), byHour[0].FeePaid)
+	assert.Equal(t, uint64(150_000), byHour[0].PriorityFee)
+	assert.Equal(t, "14:00", byHour[1].Key)
+	assert.Equal(t, 1, byHour[1].Trades)
+
+	byVenue := FeesByVenue(records)
+	assert.Equal(t, "Pump.fun", byVenue[0].Key)
+	assert.Equal(t, 57_500.0, byVenue[0].AvgFee())
+}
+
+func TestFeeRecommendations(t *testing.T) {
+	var records []Record
+	for i := 0; i < 10; i++ {
+		records = append(records, feeRecord(14, "Pump.fun", 10_000))
+	}
+	for i := 0; i < 3; i++ {
+		records = append(records, feeRecord(3, "Pump.fun", 200_000))
+	}
+
+	recs := FeeRecommendations(records)
+	assert.Len(t, recs, 1)
+	assert.Contains(t, recs[0], "03:00-04:00")
+
+	assert.Empty(t, FeeRecommendations(records[:10]))
+	assert.Nil(t, FeeRecommendations(nil))
+}
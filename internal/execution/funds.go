@@ -0,0 +1,77 @@
+// internal/execution/funds.go
+package execution
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rent-exempt депозит нового токен-аккаунта (ATA), lamports: (128 + размер) * 6960.
+const (
+	TokenAccountRent     = 2_039_280 // SPL Token, 165 байт
+	Token2022AccountRent = 2_074_080 // Token-2022 с ImmutableOwner, 170 байт
+)
+
+// Значения по умолчанию, которые площадки подставляют в транзакцию.
+const (
+	DefaultComputeUnits     = 200_000
+	DefaultPriorityFeePrice = 5_000 // micro-lamports за CU
+)
+
+// NetworkFee возвращает комиссию транзакции в lamports: базовую за подпись и
+// priority fee по цене CU в micro-lamports и лимиту CU.
+func NetworkFee(priceMicroLamports uint64, computeUnits uint32) uint64 {
+	return baseFeeLamports + priceMicroLamports*uint64(computeUnits)/1_000_000
+}
+
+// PriorityFeePrice переводит priority_fee задачи в цену CU в micro-lamports так же,
+// как площадки: "default" и пустое значение — цена по умолчанию, число — SOL,
+// умноженные на 1e12. false — "auto" или неверное значение, цену нужно оценить.
+func PriorityFeePrice(setting string) (uint64, bool) {
+	setting = strings.TrimSpace(setting)
+	if setting == "" || setting == "default" {
+		return DefaultPriorityFeePrice, true
+	}
+	sol, err := strconv.ParseFloat(setting, 64)
+	if err != nil || sol < 0 {
+		return 0, false
+	}
+	return uint64(sol * 1_000_000_000_000), true
+}
+
+// Funds — SOL, которые спишет покупка: сама сумма с кошелька, а депозит за новый
+// токен-аккаунт и комиссия — с плательщика комиссий (обычно тоже кошелька).
+type Funds struct {
+	Amount     uint64 // Сумма покупки, lamports
+	Rent       uint64 // Депозит за ATA, если его еще нет, lamports
+	NetworkFee uint64 // Базовая и приоритетная комиссия, lamports
+}
+
+// Total возвращает все, что спишет покупка, в lamports.
+func (f Funds) Total() uint64 {
+	return f.Amount + f.Rent + f.NetworkFee
+}
+
+// PayerCosts возвращает то, что платит плательщик комиссий: депозит и комиссию.
+func (f Funds) PayerCosts() uint64 {
+	return f.Rent + f.NetworkFee
+}
+
+// Shortfall возвращает, скольких lamports не хватает до need при балансе balance.
+func Shortfall(balance, need uint64) uint64 {
+	if balance >= need {
+		return 0
+	}
+	return need - balance
+}
+
+// String раскладывает стоимость покупки по составляющим для сообщений об ошибке.
+func (f Funds) String() string {
+	parts := []string{fmt.Sprintf("buy %.6f", lamportsToSol(f.Amount))}
+	if f.Rent > 0 {
+		parts = append(parts, fmt.Sprintf("token account rent %.6f", lamportsToSol(f.Rent)))
+	}
+	parts = append(parts, fmt.Sprintf("network fee %.6f", lamportsToSol(f.NetworkFee)))
+	return strings.Join(parts, " + ") + " SOL"
+}
@@ -0,0 +1,35 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityFeePrice(t *testing.T) {
+	price, ok := PriorityFeePrice("default")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(DefaultPriorityFeePrice), price)
+
+	price, ok = PriorityFeePrice("0.000005")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5_000_000), price)
+
+	_, ok = PriorityFeePrice("auto:p75")
+	assert.False(t, ok)
+}
+
+func TestFunds(t *testing.T) {
+	f := Funds{
+		Amount:     100_000_000,
+		Rent:       TokenAccountRent,
+		NetworkFee: NetworkFee(5_000_000, DefaultComputeUnits),
+	}
+	assert.Equal(t, uint64(1_005_000), f.NetworkFee)
+	assert.Equal(t, uint64(103_044_280), f.Total())
+	assert.Equal(t, uint64(3_044_280), f.PayerCosts())
+	assert.Equal(t, "buy 0.100000 + token account rent 0.002039 + network fee 0.001005 SOL", f.String())
+
+	assert.Equal(t, uint64(53_044_280), Shortfall(50_000_000, f.Total()))
+	assert.Zero(t, Shortfall(200_000_000, f.Total()))
+}
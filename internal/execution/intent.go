@@ -0,0 +1,237 @@
+// internal/execution/intent.go
+package execution
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultIntentPath — журнал намерений сделок (JSON Lines).
+const DefaultIntentPath = "logs/intents.jsonl"
+
+// Состояния намерения. Open и Sent — незавершенные, остальные — итоговые.
+const (
+	IntentOpen      = "open"      // Намерение записано, транзакция еще не отправлена
+	IntentSent      = "sent"      // Отправлена версия транзакции
+	IntentDone      = "done"      // Сделка завершилась в этом процессе (успешно или нет)
+	IntentLanded    = "landed"    // После перезапуска найдена в сети
+	IntentAbandoned = "abandoned" // После перезапуска в сети не найдена
+)
+
+// Intent — намерение сделки, записанное до отправки транзакции.
+type Intent struct {
+	Key        string    `json:"key"` // Ключ идемпотентности, одинаковый для повторного запуска той же задачи
+	Status     string    `json:"status"`
+	TaskName   string    `json:"task_name,omitempty"`
+	Side       string    `json:"side,omitempty"`
+	Mint       string    `json:"mint,omitempty"`
+	Wallet     string    `json:"wallet,omitempty"`
+	Owner      string    `json:"owner,omitempty"` // Адрес кошелька
+	AmountSol  float64   `json:"amount_sol,omitempty"`
+	Signatures []string  `json:"signatures,omitempty"`
+	At         time.Time `json:"at"`
+	SentAt     time.Time `json:"sent_at,omitempty"` // Отправка последней версии транзакции
+}
+
+// Pending сообщает, что итог намерения неизвестен.
+func (in Intent) Pending() bool {
+	return in.Status == IntentOpen || in.Status == IntentSent
+}
+
+// LastActivity возвращает время последней отправки транзакции, а если она не
+// отправлялась — время записи намерения.
+func (in Intent) LastActivity() time.Time {
+	if in.SentAt.After(in.At) {
+		return in.SentAt
+	}
+	return in.At
+}
+
+// IntentKey строит ключ идемпотентности из параметров задачи.
+func IntentKey(taskName, side, mint, wallet string, amountSol float64) string {
+	sum := sha256.Sum256([]byte(taskName + "|" + side + "|" + mint + "|" + wallet + "|" +
+		strconv.FormatFloat(amountSol, 'f', -1, 64)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// IntentLog — журнал упреждающей записи: намерение сохраняется до отправки сделки,
+// чтобы после аварийного завершения можно было выяснить ее судьбу.
+// Все методы безопасны для nil.
+type IntentLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewIntentLog создает журнал по указанному пути.
+func NewIntentLog(path string) *IntentLog {
+	return &IntentLog{path: path}
+}
+
+// Open записывает новое намерение и сбрасывает его на диск до возврата.
+func (l *IntentLog) Open(in Intent) error {
+	if l == nil {
+		return nil
+	}
+	in.Status = IntentOpen
+	if in.At.IsZero() {
+		in.At = time.Now()
+	}
+	return l.append(in)
+}
+
+// MarkSent дописывает подпись отправленной версии транзакции.
+func (l *IntentLog) MarkSent(key, signature string) error {
+	if l == nil {
+		return nil
+	}
+	return l.append(Intent{Key: key, Status: IntentSent, Signatures: []string{signature}, At: time.Now()})
+}
+
+// Resolve фиксирует итоговое состояние намерения.
+func (l *IntentLog) Resolve(key, status string) error {
+	if l == nil {
+		return nil
+	}
+	return l.append(Intent{Key: key, Status: status, At: time.Now()})
+}
+
+// Pending возвращает незавершенные намерения в порядке создания.
+func (l *IntentLog) Pending() ([]Intent, error) {
+	if l == nil {
+		return nil, nil
+	}
+	all, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Intent
+	for _, in := range all {
+		if in.Pending() {
+			pending = append(pending, in)
+		}
+	}
+	return pending, nil
+}
+
+// Compact переписывает журнал, оставляя только незавершенные намерения.
+// Вызывается при запуске, до начала торговли.
+func (l *IntentLog) Compact() error {
+	pending, err := l.Pending()
+	if err != nil || l == nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tmp := l.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open intent log: %w", err)
+	}
+	for _, in := range pending {
+		line, err := json.Marshal(in)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("marshal intent: %w", err)
+		}
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			f.Close()
+			return fmt.Errorf("write intent: %w", err)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("sync intent log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close intent log: %w", err)
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// append дописывает событие и сбрасывает файл на диск.
+func (l *IntentLog) append(in Intent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("create intent log dir: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open intent log: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("marshal intent: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write intent: %w", err)
+	}
+	// Запись должна пережить падение процесса сразу после отправки сделки
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("sync intent log: %w", err)
+	}
+	return nil
+}
+
+// load сворачивает события журнала в последнее состояние каждого намерения.
+func (l *IntentLog) load() ([]Intent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open intent log: %w", err)
+	}
+	defer f.Close()
+
+	byKey := make(map[string]*Intent)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev Intent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil || ev.Key == "" {
+			continue
+		}
+
+		in, ok := byKey[ev.Key]
+		if !ok || ev.Status == IntentOpen && !in.Pending() {
+			// Новое намерение или повторный запуск уже завершенной задачи
+			copyEv := ev
+			byKey[ev.Key] = &copyEv
+			continue
+		}
+		in.Signatures = append(in.Signatures, ev.Signatures...)
+		if ev.Status == IntentSent && ev.At.After(in.SentAt) {
+			in.SentAt = ev.At
+		}
+		if ev.Status != IntentOpen {
+			in.Status = ev.Status
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read intent log: %w", err)
+	}
+
+	result := make([]Intent, 0, len(byKey))
+	for _, in := range byKey {
+		result = append(result, *in)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].At.Before(result[j].At) })
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package execution
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntentLog_Pending(t *testing.T) {
+	log := NewIntentLog(filepath.Join(t.TempDir(), "intents.jsonl"))
+	buy := IntentKey("snipe-1", SideBuy, "Mint", "main", 0.1)
+	sell := IntentKey("sell-1", SideSell, "Mint", "main", 0)
+	assert.NotEqual(t, buy, sell)
+	assert.Equal(t, buy, IntentKey("snipe-1", SideBuy, "Mint", "main", 0.1))
+
+	assert.NoError(t, log.Open(Intent{Key: buy, TaskName: "snipe-1", Side: SideBuy}))
+	assert.NoError(t, log.MarkSent(buy, "sig1"))
+	assert.NoError(t, log.MarkSent(buy, "sig2"))
+	assert.NoError(t, log.Open(Intent{Key: sell, TaskName: "sell-1", Side: SideSell}))
+	assert.NoError(t, log.Resolve(sell, IntentDone))
+
+	pending, err := log.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "snipe-1", pending[0].TaskName)
+	assert.Equal(t, IntentSent, pending[0].Status)
+	assert.Equal(t, []string{"sig1", "sig2"}, pending[0].Signatures)
+	assert.False(t, pending[0].SentAt.Before(pending[0].At), "the last send is recorded")
+	assert.Equal(t, pending[0].SentAt, pending[0].LastActivity())
+
+	// Повторный запуск завершенной задачи открывает новое намерение
+	assert.NoError(t, log.Open(Intent{Key: sell, TaskName: "sell-1", Side: SideSell}))
+	pending, err = log.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	assert.Empty(t, pending[1].Signatures)
+}
+
+func TestIntentLog_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "intents.jsonl")
+	log := NewIntentLog(path)
+
+	assert.NoError(t, log.Open(Intent{Key: "a"}))
+	assert.NoError(t, log.Resolve("a", IntentLanded))
+	assert.NoError(t, log.Open(Intent{Key: "b"}))
+	assert.NoError(t, log.MarkSent("b", "sig"))
+	assert.NoError(t, log.Compact())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), `"key":"a"`)
+
+	pending, err := log.Pending()
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, []string{"sig"}, pending[0].Signatures)
+	assert.False(t, pending[0].SentAt.IsZero(), "compaction keeps the time of the last send")
+}
+
+func TestIntent_LastActivity(t *testing.T) {
+	opened := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, opened, Intent{At: opened}.LastActivity(), "never sent")
+	assert.Equal(t, opened.Add(time.Minute), Intent{At: opened, SentAt: opened.Add(time.Minute)}.LastActivity())
+}
+
+func TestIntentLog_Nil(t *testing.T) {
+	var log *IntentLog
+	assert.NoError(t, log.Open(Intent{Key: "a"}))
+	assert.NoError(t, log.Compact())
+	pending, err := log.Pending()
+	assert.NoError(t, err)
+	assert.Nil(t, pending)
+}
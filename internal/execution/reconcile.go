@@ -0,0 +1,65 @@
+// internal/execution/reconcile.go
+package execution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+)
+
+// reconcileHistoryLimit — сколько последних транзакций токен-аккаунта просматривается при сверке.
+const reconcileHistoryLimit = 20
+
+// Reconcile ищет в сети транзакцию незавершенного намерения и возвращает ее подпись
+// (пусто — сделка не прошла). Сначала проверяются записанные подписи, затем история
+// токен-аккаунта кошелька: он меняется при любой покупке или продаже токена, поэтому
+// находится и транзакция, подпись которой не успела попасть в журнал.
+func Reconcile(ctx context.Context, client *blockchain.Client, in Intent) (string, error) {
+	if len(in.Signatures) > 0 {
+		sigs := make([]solana.Signature, 0, len(in.Signatures))
+		for _, s := range in.Signatures {
+			if sig, err := solana.SignatureFromBase58(s); err == nil {
+				sigs = append(sigs, sig)
+			}
+		}
+		statuses, err := client.GetSignatureStatuses(ctx, sigs...)
+		if err != nil {
+			return "", fmt.Errorf("signature statuses: %w", err)
+		}
+		for i, st := range statuses.Value {
+			if st != nil && st.Err == nil && i < len(sigs) {
+				return sigs[i].String(), nil
+			}
+		}
+	}
+
+	owner, err := solana.PublicKeyFromBase58(in.Owner)
+	if err != nil {
+		return "", fmt.Errorf("invalid owner %q: %w", in.Owner, err)
+	}
+	mint, err := solana.PublicKeyFromBase58(in.Mint)
+	if err != nil {
+		return "", fmt.Errorf("invalid mint %q: %w", in.Mint, err)
+	}
+	ata, err := client.AssociatedTokenAccount(ctx, owner, mint)
+	if err != nil {
+		return "", fmt.Errorf("derive token account: %w", err)
+	}
+
+	history, err := client.GetSignaturesForAddress(ctx, ata, reconcileHistoryLimit)
+	if err != nil {
+		return "", fmt.Errorf("token account history: %w", err)
+	}
+	for _, h := range history {
+		if h.Err != nil || h.BlockTime == nil {
+			continue
+		}
+		if !h.BlockTime.Time().Before(in.At.Truncate(time.Second)) {
+			return h.Signature.String(), nil
+		}
+	}
+	return "", nil
+}
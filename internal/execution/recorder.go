@@ -0,0 +1,210 @@
+// internal/execution/recorder.go
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"go.uber.org/zap"
+)
+
+const (
+	metaFetchAttempts = 5
+	metaFetchDelay    = 500 * time.Millisecond
+)
+
+// Recorder завершает трассировки: дочитывает фактические комиссию и выход
+// из метаданных транзакции и сохраняет запись в Store.
+type Recorder struct {
+	client *blockchain.Client
+	store  *Store
+	logger *zap.Logger
+}
+
+// NewRecorder создает Recorder.
+func NewRecorder(client *blockchain.Client, store *Store, logger *zap.Logger) *Recorder {
+	return &Recorder{
+		client: client,
+		store:  store,
+		logger: logger.Named("execution"),
+	}
+}
+
+// Store возвращает хранилище записей.
+func (r *Recorder) Store() *Store {
+	if r == nil {
+		return nil
+	}
+	return r.store
+}
+
+// Finish фиксирует результат сделки и сохраняет метрики исполнения.
+func (r *Recorder) Finish(ctx context.Context, tr *Trace, execErr error) {
+	if r == nil || tr == nil {
+		return
+	}
+	tr.Fail(execErr)
+
+	rec := tr.Record()
+	if rec.Signature != "" && !rec.ConfirmedAt.IsZero() {
+		if err := r.fillFromMeta(ctx, tr, rec); err != nil {
+			r.logger.Debug("Failed to read transaction meta", zap.Error(err))
+		}
+		rec = tr.Record()
+	}
+
+	if err := r.store.Append(rec); err != nil {
+		r.logger.Warn("⚠️  Failed to store execution record: " + err.Error())
+	}
+
+	if rec.Success() {
+		slip := "n/a"
+		if s, ok := rec.SlippagePercent(); ok {
+			slip = fmt.Sprintf("%.2f%%", s)
+		}
+		venueFees := ""
+		if rec.ProtocolFee+rec.CreatorFee > 0 {
+			venueFees = fmt.Sprintf(", venue fees %.6f SOL (creator %.6f)",
+				lamportsToSol(rec.ProtocolFee+rec.CreatorFee), lamportsToSol(rec.CreatorFee))
+		}
+		r.logger.Info(fmt.Sprintf("📐 Execution: %s in %s, slippage %s, fee %.6f SOL (est %.6f)%s",
+			rec.Side, rec.Latency().Round(time.Millisecond), slip,
+			lamportsToSol(rec.FeePaid), lamportsToSol(rec.FeeEstimate()), venueFees))
+	}
+	if usage := rec.BudgetUsage(); usage != "" {
+		r.logger.Info("⏱️  Deadline budget: " + usage)
+	}
+}
+
+// fillFromMeta получает транзакцию и извлекает уплаченную комиссию, фактически
+// отданное и фактический выход.
+func (r *Recorder) fillFromMeta(ctx context.Context, tr *Trace, rec Record) error {
+	sig, err := solana.SignatureFromBase58(rec.Signature)
+	if err != nil {
+		return err
+	}
+
+	var tx *rpc.GetTransactionResult
+	for i := 0; i < metaFetchAttempts; i++ {
+		tx, err = r.client.GetTransaction(ctx, sig)
+		if err == nil && tx != nil && tx.Meta != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(metaFetchDelay):
+		}
+	}
+	if tx == nil || tx.Meta == nil {
+		return fmt.Errorf("transaction meta unavailable: %v", err)
+	}
+
+	meta := tx.Meta
+	walletIdx := walletIndex(tx, rec.Wallet)
+	in, out := actualInput(meta, rec, walletIdx), actualOutput(meta, rec, walletIdx)
+
+	tr.update(func(r *Record) {
+		r.FeePaid = meta.Fee
+		r.ActualIn = in
+		r.ActualOut = out
+	})
+	return nil
+}
+
+// actualOutput считает фактический выход сделки по изменению балансов кошелька.
+// Для покупки — прирост токенов, для продажи — прирост SOL кошелька
+// (с учетом комиссии, если кошелек сам ее оплатил).
+func actualOutput(meta *rpc.TransactionMeta, rec Record, walletIdx int) uint64 {
+	if rec.Side == SideSell {
+		if walletIdx < 0 || walletIdx >= len(meta.PreBalances) || walletIdx >= len(meta.PostBalances) {
+			return 0
+		}
+		delta := int64(meta.PostBalances[walletIdx]) - int64(meta.PreBalances[walletIdx])
+		if walletIdx == 0 {
+			delta += int64(meta.Fee)
+		}
+		if delta < 0 {
+			return 0
+		}
+		return uint64(delta)
+	}
+
+	pre := tokenAmountFor(meta.PreTokenBalances, rec.Wallet, rec.Mint)
+	post := tokenAmountFor(meta.PostTokenBalances, rec.Wallet, rec.Mint)
+	if post <= pre {
+		return 0
+	}
+	return post - pre
+}
+
+// actualInput считает, сколько кошелек фактически отдал в сделке. Для покупки —
+// убыль SOL кошелька без комиссии транзакции (включая ренту новых аккаунтов),
+// для продажи — убыль токенов.
+func actualInput(meta *rpc.TransactionMeta, rec Record, walletIdx int) uint64 {
+	if rec.Side == SideSell {
+		pre := tokenAmountFor(meta.PreTokenBalances, rec.Wallet, rec.Mint)
+		post := tokenAmountFor(meta.PostTokenBalances, rec.Wallet, rec.Mint)
+		if pre <= post {
+			return 0
+		}
+		return pre - post
+	}
+
+	if walletIdx < 0 || walletIdx >= len(meta.PreBalances) || walletIdx >= len(meta.PostBalances) {
+		return 0
+	}
+	delta := int64(meta.PreBalances[walletIdx]) - int64(meta.PostBalances[walletIdx])
+	if walletIdx == 0 {
+		delta -= int64(meta.Fee)
+	}
+	if delta < 0 {
+		return 0
+	}
+	return uint64(delta)
+}
+
+// walletIndex возвращает индекс кошелька среди ключей транзакции. Если ключи
+// недоступны, считается, что кошелек — плательщик (индекс 0).
+func walletIndex(tx *rpc.GetTransactionResult, wallet string) int {
+	if tx.Transaction == nil {
+		return 0
+	}
+	parsed, err := tx.Transaction.GetTransaction()
+	if err != nil || parsed == nil {
+		return 0
+	}
+	for i, key := range parsed.Message.AccountKeys {
+		if key.String() == wallet {
+			return i
+		}
+	}
+	return -1
+}
+
+// tokenAmountFor возвращает raw баланс токена mint у владельца owner.
+func tokenAmountFor(balances []rpc.TokenBalance, owner, mint string) uint64 {
+	for _, b := range balances {
+		if b.Owner == nil || b.UiTokenAmount == nil {
+			continue
+		}
+		if b.Owner.String() != owner || b.Mint.String() != mint {
+			continue
+		}
+		amount, err := strconv.ParseUint(b.UiTokenAmount.Amount, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return amount
+	}
+	return 0
+}
+
+func lamportsToSol(lamports uint64) float64 {
+	return float64(lamports) / float64(solana.LAMPORTS_PER_SOL)
+}
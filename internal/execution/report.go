@@ -0,0 +1,152 @@
+// internal/execution/report.go
+package execution
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Stats — агрегированные метрики исполнения по площадке или RPC.
+type Stats struct {
+	Key         string
+	Trades      int
+	Failed      int
+	AvgLatency  time.Duration
+	P50Latency  time.Duration // Медиана задержки успешных сделок
+	P95Latency  time.Duration // 95-й перцентиль задержки: хвост, который прячет среднее
+	AvgSlippage float64       // Средний процент проскальзывания по сделкам с известной котировкой
+	FeePaid     uint64        // Суммарная уплаченная комиссия (lamports)
+	FeeEstimate uint64        // Суммарная ожидаемая комиссия (lamports)
+}
+
+// SuccessRate возвращает долю успешных сделок.
+func (s Stats) SuccessRate() float64 {
+	if s.Trades == 0 {
+		return 0
+	}
+	return float64(s.Trades-s.Failed) / float64(s.Trades)
+}
+
+// Aggregate группирует записи по ключу и ранжирует группы:
+// сначала по доле успешных сделок, затем по средней задержке.
+func Aggregate(records []Record, key func(Record) string) []Stats {
+	type acc struct {
+		Stats
+		latencies   []time.Duration
+		slippageSum float64
+		slippageN   int
+	}
+
+	groups := make(map[string]*acc)
+	for _, rec := range records {
+		k := key(rec)
+		g, ok := groups[k]
+		if !ok {
+			g = &acc{Stats: Stats{Key: k}}
+			groups[k] = g
+		}
+
+		g.Trades++
+		if !rec.Success() {
+			g.Failed++
+			continue
+		}
+		g.latencies = append(g.latencies, rec.Latency())
+		if s, ok := rec.SlippagePercent(); ok {
+			g.slippageSum += s
+			g.slippageN++
+		}
+		g.FeePaid += rec.FeePaid
+		g.FeeEstimate += rec.FeeEstimate()
+	}
+
+	result := make([]Stats, 0, len(groups))
+	for _, g := range groups {
+		if n := len(g.latencies); n > 0 {
+			var sum time.Duration
+			for _, l := range g.latencies {
+				sum += l
+			}
+			g.AvgLatency = sum / time.Duration(n)
+			slices.Sort(g.latencies)
+			g.P50Latency = latencyPercentile(g.latencies, 50)
+			g.P95Latency = latencyPercentile(g.latencies, 95)
+		}
+		if g.slippageN > 0 {
+			g.AvgSlippage = g.slippageSum / float64(g.slippageN)
+		}
+		result = append(result, g.Stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SuccessRate() != result[j].SuccessRate() {
+			return result[i].SuccessRate() > result[j].SuccessRate()
+		}
+		return result[i].AvgLatency < result[j].AvgLatency
+	})
+	return result
+}
+
+// latencyPercentile возвращает p-й перцентиль (1..100) отсортированных задержек
+// по ближайшему рангу: наименьшее значение, не меньшее p% выборки.
+func latencyPercentile(sorted []time.Duration, p int) time.Duration {
+	rank := (len(sorted)*p + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// ReportLines формирует раздел "execution quality" для итоговой сводки.
+func ReportLines(records []Record, period time.Duration) []string {
+	if len(records) == 0 {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("📐 Execution quality (last %s, %d trades)", formatPeriod(period), len(records))}
+
+	section := func(title string, stats []Stats) {
+		lines = append(lines, "  "+title)
+		for i, s := range stats {
+			feeRatio := "n/a"
+			if s.FeeEstimate > 0 {
+				feeRatio = fmt.Sprintf("%.2fx", float64(s.FeePaid)/float64(s.FeeEstimate))
+			}
+			lines = append(lines, fmt.Sprintf("    %d. %s: %d trades, %.0f%% ok, latency %s (p50 %s, p95 %s), slippage %.2f%%, fee %.6f SOL (%s est)",
+				i+1, s.Key, s.Trades, s.SuccessRate()*100, s.AvgLatency.Round(time.Millisecond),
+				s.P50Latency.Round(time.Millisecond), s.P95Latency.Round(time.Millisecond),
+				s.AvgSlippage, lamportsToSol(s.FeePaid), feeRatio))
+		}
+	}
+
+	section("By venue:", Aggregate(records, func(r Record) string { return r.Venue }))
+	section("By RPC:", Aggregate(records, func(r Record) string { return r.RPC }))
+	if line := blockhashAgeLine(records); line != "" {
+		lines = append(lines, line)
+	}
+	return append(lines, feeLines(records)...)
+}
+
+// blockhashAgeLine описывает возраст blockhash в момент отправки; пусто, если он не записывался.
+func blockhashAgeLine(records []Record) string {
+	var sum, maxAge int64
+	n := 0
+	for _, rec := range records {
+		if rec.SentAt.IsZero() || rec.BlockhashAge <= 0 {
+			continue
+		}
+		sum += rec.BlockhashAge
+		maxAge = max(maxAge, rec.BlockhashAge)
+		n++
+	}
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  Blockhash age at send: avg %dms, max %dms (%d trades)", sum/int64(n), maxAge, n)
+}
+
+func formatPeriod(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	return d.String()
+}
@@ -0,0 +1,96 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// qualityRecord — подтвержденная сделка с задержкой latency; failed — сделка с ошибкой.
+func qualityRecord(venue, rpc string, latency time.Duration, failed bool) Record {
+	started := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rec := Record{Venue: venue, RPC: rpc, StartedAt: started, ConfirmedAt: started.Add(latency), FeePaid: 10_000}
+	if failed {
+		rec.ConfirmedAt, rec.Error = time.Time{}, "timeout"
+	}
+	return rec
+}
+
+func TestAggregate(t *testing.T) {
+	var records []Record
+	for ms := 1; ms <= 20; ms++ {
+		records = append(records, qualityRecord("Pump.fun", "rpc-a", time.Duration(ms)*100*time.Millisecond, false))
+	}
+	records = append(records,
+		qualityRecord("Pump.fun", "rpc-a", 0, true),
+		qualityRecord("Pump.Swap", "rpc-b", 300*time.Millisecond, false),
+	)
+	withQuote := qualityRecord("Pump.Swap", "rpc-b", 500*time.Millisecond, false)
+	withQuote.QuotedOut, withQuote.ActualOut = 1_000, 970
+	records = append(records, withQuote)
+
+	stats := Aggregate(records, func(r Record) string { return r.Venue })
+	require.Len(t, stats, 2)
+
+	swap := stats[0]
+	assert.Equal(t, "Pump.Swap", swap.Key, "the group without failures ranks first")
+	assert.Equal(t, 2, swap.Trades)
+	assert.Equal(t, 400*time.Millisecond, swap.AvgLatency)
+	assert.Equal(t, 300*time.Millisecond, swap.P50Latency)
+	assert.Equal(t, 500*time.Millisecond, swap.P95Latency)
+	assert.InDelta(t, 3.0, swap.AvgSlippage, 1e-9, "only trades with a quote count towards slippage")
+	assert.Equal(t, uint64(20_000), swap.FeePaid)
+	assert.Equal(t, uint64(2*baseFeeLamports), swap.FeeEstimate)
+
+	fun := stats[1]
+	assert.Equal(t, 21, fun.Trades)
+	assert.Equal(t, 1, fun.Failed)
+	assert.InDelta(t, 20.0/21, fun.SuccessRate(), 1e-9)
+	assert.Equal(t, 1050*time.Millisecond, fun.AvgLatency, "failed trades do not count towards latency")
+	assert.Equal(t, time.Second, fun.P50Latency)
+	assert.Equal(t, 1900*time.Millisecond, fun.P95Latency)
+	assert.Equal(t, uint64(200_000), fun.FeePaid)
+
+	// При равной доле успешных выше группа с меньшей средней задержкой
+	byRPC := Aggregate(records[20:], func(r Record) string { return r.RPC })
+	assert.Equal(t, "rpc-b", byRPC[0].Key)
+
+	assert.Empty(t, Aggregate(nil, func(r Record) string { return r.Venue }))
+	assert.Zero(t, Stats{}.SuccessRate())
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	one := []time.Duration{time.Second}
+	assert.Equal(t, time.Second, latencyPercentile(one, 50))
+	assert.Equal(t, time.Second, latencyPercentile(one, 95))
+
+	four := []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second}
+	assert.Equal(t, 2*time.Second, latencyPercentile(four, 50))
+	assert.Equal(t, 4*time.Second, latencyPercentile(four, 95))
+	assert.Equal(t, 1*time.Second, latencyPercentile(four, 1))
+	assert.Equal(t, 4*time.Second, latencyPercentile(four, 100))
+}
+
+func TestReportLines(t *testing.T) {
+	assert.Nil(t, ReportLines(nil, 7*24*time.Hour), "no trades, no section")
+
+	records := []Record{
+		qualityRecord("Pump.fun", "rpc-a", 200*time.Millisecond, false),
+		qualityRecord("Pump.fun", "rpc-a", 0, true),
+		qualityRecord("Pump.Swap", "rpc-b", 400*time.Millisecond, false),
+	}
+	lines := ReportLines(records, 7*24*time.Hour)
+	require.GreaterOrEqual(t, len(lines), 7)
+	assert.Equal(t, []string{
+		"📐 Execution quality (last 7d, 3 trades)",
+		"  By venue:",
+		"    1. Pump.Swap: 1 trades, 100% ok, latency 400ms (p50 400ms, p95 400ms), slippage 0.00%, fee 0.000010 SOL (2.00x est)",
+		"    2. Pump.fun: 2 trades, 50% ok, latency 200ms (p50 200ms, p95 200ms), slippage 0.00%, fee 0.000010 SOL (2.00x est)",
+		"  By RPC:",
+		"    1. rpc-b: 1 trades, 100% ok, latency 400ms (p50 400ms, p95 400ms), slippage 0.00%, fee 0.000010 SOL (2.00x est)",
+		"    2. rpc-a: 2 trades, 50% ok, latency 200ms (p50 200ms, p95 200ms), slippage 0.00%, fee 0.000010 SOL (2.00x est)",
+	}, lines[:7])
+	assert.Contains(t, ReportLines(records, 36*time.Hour)[0], "last 36h0m0s")
+}
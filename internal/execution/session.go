@@ -0,0 +1,288 @@
+// internal/execution/session.go
+package execution
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultSessionPath — архив закрытых сессий мониторинга (JSON Lines).
+const DefaultSessionPath = "logs/sessions.jsonl"
+
+// sessionLookback — насколько раньше первой покупки позиции ищутся ее сделки:
+// сделка начинается раньше, чем покупка попадает в позицию.
+const sessionLookback = 5 * time.Minute
+
+// Итоги сессии мониторинга для Session.Outcome.
+const (
+	OutcomeSold    = "sold"    // Позиция продана
+	OutcomeExited  = "exited"  // Выход без продажи
+	OutcomeFailed  = "failed"  // Продажа или сессия завершились ошибкой
+	OutcomeStopped = "stopped" // Бот остановлен
+)
+
+// SessionEvent — один шаг жизненного цикла сессии: покупка, запрос продажи, выход.
+type SessionEvent struct {
+	At     time.Time `json:"at"`
+	Kind   string    `json:"kind"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Session — закрытая сессия мониторинга позиции с итогом и историей.
+type Session struct {
+	ID         string         `json:"id"`
+	TaskName   string         `json:"task_name"`
+	Tasks      []string       `json:"tasks"` // Задачи, чьи покупки вошли в позицию
+	Mint       string         `json:"mint"`
+	Wallet     string         `json:"wallet"`
+	Venue      string         `json:"venue"`
+	OpenedAt   time.Time      `json:"opened_at"`
+	ClosedAt   time.Time      `json:"closed_at"`
+	Invested   float64        `json:"invested_sol"`
+	EntryPrice float64        `json:"entry_price"`
+	LastPrice  float64        `json:"last_price"`
+	PnLSol     float64        `json:"pnl_sol"`
+	PnLPercent float64        `json:"pnl_percent"`
+	Outcome    string         `json:"outcome"`
+	Events     []SessionEvent `json:"events"`
+}
+
+// SessionID строит идентификатор сессии по токену и моменту открытия.
+func SessionID(mint string, openedAt time.Time) string {
+	return fmt.Sprintf("%s-%d", mint, openedAt.UnixMilli())
+}
+
+// Executions отбирает записи исполнения, относящиеся к сессии: сделки ее задач по ее
+// токену от первой покупки до закрытия.
+func (s Session) Executions(records []Record) []Record {
+	from := s.OpenedAt
+	for _, ev := range s.Events {
+		if ev.At.Before(from) {
+			from = ev.At
+		}
+	}
+	from = from.Add(-sessionLookback)
+
+	var out []Record
+	for _, rec := range records {
+		if rec.Mint != s.Mint || !slices.Contains(s.Tasks, rec.TaskName) {
+			continue
+		}
+		if rec.StartedAt.Before(from) || rec.StartedAt.After(s.ClosedAt) {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+// LastSale возвращает последнюю подтвержденную продажу сессии среди records.
+func (s Session) LastSale(records []Record) (Record, bool) {
+	execs := s.Executions(records)
+	for i := len(execs) - 1; i >= 0; i-- {
+		if execs[i].Side == SideSell && execs[i].Success() {
+			return execs[i], true
+		}
+	}
+	return Record{}, false
+}
+
+// SessionQuery — фильтр поиска по архиву; пустые поля не ограничивают выборку.
+type SessionQuery struct {
+	Mint   string    `json:"mint"`
+	From   time.Time `json:"from"` // Закрыта не раньше
+	To     time.Time `json:"to"`   // Закрыта не позже
+	MinPnL *float64  `json:"min_pnl_percent"`
+	MaxPnL *float64  `json:"max_pnl_percent"`
+	Limit  int       `json:"limit"` // Сколько последних сессий вернуть (0 — все)
+}
+
+// Match сообщает, подходит ли сессия под фильтр.
+func (q SessionQuery) Match(s Session) bool {
+	switch {
+	case q.Mint != "" && s.Mint != q.Mint:
+		return false
+	case !q.From.IsZero() && s.ClosedAt.Before(q.From):
+		return false
+	case !q.To.IsZero() && s.ClosedAt.After(q.To):
+		return false
+	case q.MinPnL != nil && s.PnLPercent < *q.MinPnL:
+		return false
+	case q.MaxPnL != nil && s.PnLPercent > *q.MaxPnL:
+		return false
+	}
+	return true
+}
+
+// SessionArchive хранит закрытые сессии в файле JSON Lines. Методы безопасны для nil:
+// без архива сессии просто не сохраняются.
+type SessionArchive struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewSessionArchive создает архив по указанному пути.
+func NewSessionArchive(path string) *SessionArchive {
+	return &SessionArchive{path: path}
+}
+
+// Archive дописывает закрытую сессию в архив.
+func (a *SessionArchive) Archive(s Session) error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o755); err != nil {
+		return fmt.Errorf("create session archive dir: %w", err)
+	}
+	f, err := os.OpenFile(a.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open session archive: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write session: %w", err)
+	}
+	return nil
+}
+
+// Search возвращает подходящие под фильтр сессии в порядке закрытия.
+func (a *SessionArchive) Search(q SessionQuery) ([]Session, error) {
+	if a == nil {
+		return nil, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	all, err := a.load()
+	if err != nil {
+		return nil, err
+	}
+	var found []Session
+	for _, s := range all {
+		if q.Match(s) {
+			found = append(found, s)
+		}
+	}
+	if q.Limit > 0 && len(found) > q.Limit {
+		found = found[len(found)-q.Limit:]
+	}
+	return found, nil
+}
+
+// LastExit возвращает последнюю сессию кошелька wallet по токену mint, закрытую продажей.
+func (a *SessionArchive) LastExit(mint, wallet string) (Session, bool, error) {
+	sessions, err := a.Search(SessionQuery{Mint: mint})
+	if err != nil {
+		return Session{}, false, err
+	}
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if sessions[i].Wallet == wallet && sessions[i].Outcome == OutcomeSold {
+			return sessions[i], true, nil
+		}
+	}
+	return Session{}, false, nil
+}
+
+// Get возвращает сессию по идентификатору.
+func (a *SessionArchive) Get(id string) (Session, bool, error) {
+	if a == nil {
+		return Session{}, false, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	all, err := a.load()
+	if err != nil {
+		return Session{}, false, err
+	}
+	for _, s := range all {
+		if s.ID == id {
+			return s, true, nil
+		}
+	}
+	return Session{}, false, nil
+}
+
+// Prune удаляет сессии, закрытые раньше before, и возвращает их количество.
+func (a *SessionArchive) Prune(before time.Time) (int, error) {
+	if a == nil {
+		return 0, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	all, err := a.load()
+	if err != nil {
+		return 0, err
+	}
+	kept := slices.DeleteFunc(slices.Clone(all), func(s Session) bool { return s.ClosedAt.Before(before) })
+	if len(kept) == len(all) {
+		return 0, nil
+	}
+
+	tmp := a.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("create session archive: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, s := range kept {
+		if err := enc.Encode(s); err != nil {
+			f.Close()
+			return 0, fmt.Errorf("write session: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return 0, fmt.Errorf("write session archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("close session archive: %w", err)
+	}
+	if err := os.Rename(tmp, a.path); err != nil {
+		return 0, fmt.Errorf("replace session archive: %w", err)
+	}
+	return len(all) - len(kept), nil
+}
+
+// load читает все сессии; вызывается под блокировкой. Поврежденные строки пропускаются.
+func (a *SessionArchive) load() ([]Session, error) {
+	f, err := os.Open(a.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open session archive: %w", err)
+	}
+	defer f.Close()
+
+	var sessions []Session
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20) // События длинной сессии не влезают в 64 КБ
+	for scanner.Scan() {
+		var s Session
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read session archive: %w", err)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func archivedSession(mint string, closedAt time.Time, pnl float64) Session {
+	opened := closedAt.Add(-10 * time.Minute)
+	return Session{
+		ID:         SessionID(mint, opened),
+		TaskName:   "snipe-" + mint,
+		Tasks:      []string{"snipe-" + mint},
+		Mint:       mint,
+		OpenedAt:   opened,
+		ClosedAt:   closedAt,
+		PnLPercent: pnl,
+		Outcome:    OutcomeSold,
+	}
+}
+
+func TestSessionArchive_Search(t *testing.T) {
+	archive := NewSessionArchive(filepath.Join(t.TempDir(), "sessions.jsonl"))
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, archive.Archive(archivedSession("MintA", day, 40)))
+	require.NoError(t, archive.Archive(archivedSession("MintB", day.Add(24*time.Hour), -20)))
+	require.NoError(t, archive.Archive(archivedSession("MintA", day.Add(48*time.Hour), 5)))
+
+	found, err := archive.Search(SessionQuery{Mint: "MintA"})
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	minPnL := 0.0
+	found, err = archive.Search(SessionQuery{From: day.Add(time.Hour), MinPnL: &minPnL})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, 5.0, found[0].PnLPercent)
+
+	found, err = archive.Search(SessionQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, day.Add(48*time.Hour), found[0].ClosedAt.UTC())
+
+	s, ok, err := archive.Get(SessionID("MintB", day.Add(24*time.Hour-10*time.Minute)))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, -20.0, s.PnLPercent)
+
+	// Сессии, закрытые до отсечки, удаляются
+	n, err := archive.Prune(day.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	found, err = archive.Search(SessionQuery{})
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+}
+
+func TestSession_Executions(t *testing.T) {
+	closed := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := archivedSession("MintA", closed, 10)
+	s.Events = []SessionEvent{{At: s.OpenedAt.Add(-time.Second), Kind: "buy"}}
+
+	records := []Record{
+		{TaskName: "snipe-MintA", Mint: "MintA", Side: SideBuy, StartedAt: s.OpenedAt.Add(-3 * time.Second)},
+		{TaskName: "snipe-MintA", Mint: "MintA", Side: SideSell, StartedAt: closed.Add(-time.Minute)},
+		{TaskName: "snipe-MintA", Mint: "MintA", Side: SideBuy, StartedAt: s.OpenedAt.Add(-time.Hour)}, // Прошлый запуск
+		{TaskName: "other", Mint: "MintA", StartedAt: closed.Add(-time.Minute)},
+	}
+	got := s.Executions(records)
+	require.Len(t, got, 2)
+	assert.Equal(t, SideBuy, got[0].Side)
+	assert.Equal(t, SideSell, got[1].Side)
+
+	var nilArchive *SessionArchive
+	assert.NoError(t, nilArchive.Archive(s))
+}
+
+func TestSession_LastSale(t *testing.T) {
+	closed := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := archivedSession("MintA", closed, 10)
+	sell := func(at time.Time, in, out uint64, errText string) Record {
+		return Record{TaskName: "snipe-MintA", Mint: "MintA", Side: SideSell, StartedAt: at, ConfirmedAt: at.Add(time.Second),
+			ActualIn: in, ActualOut: out, Error: errText}
+	}
+
+	_, ok := s.LastSale(nil)
+	assert.False(t, ok)
+
+	buy := Record{TaskName: "snipe-MintA", Mint: "MintA", Side: SideBuy, StartedAt: closed.Add(-20 * time.Second)}
+	records := []Record{
+		sell(closed.Add(-5*time.Minute), 500_000_000, 100_000_000, ""), // 500 токенов за 0.1 SOL
+		sell(closed.Add(-time.Minute), 500_000_000, 50_000_000, ""),    // 500 токенов за 0.05 SOL
+		sell(closed.Add(-30*time.Second), 500_000_000, 0, "slippage"),  // Неудачная продажа
+		buy, // Докупка после продаж
+	}
+	sale, ok := s.LastSale(records)
+	require.True(t, ok)
+	price, ok := sale.SellPrice(6)
+	require.True(t, ok)
+	assert.InDelta(t, 0.0001, price, 1e-15, "the last confirmed sale sets the exit price")
+
+	price, ok = sale.SellPrice(9)
+	require.True(t, ok)
+	assert.InDelta(t, 0.1, price, 1e-12)
+
+	_, ok = buy.SellPrice(6)
+	assert.False(t, ok, "a buy has no sell price")
+}
+
+func TestSessionArchive_LastExit(t *testing.T) {
+	archive := NewSessionArchive(filepath.Join(t.TempDir(), "sessions.jsonl"))
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	sold := archivedSession("MintA", day, 40)
+	sold.Wallet = "main"
+	failed := archivedSession("MintA", day.Add(time.Hour), -10)
+	failed.Wallet, failed.Outcome = "main", OutcomeFailed
+	other := archivedSession("MintA", day.Add(2*time.Hour), 5)
+	other.Wallet = "alt"
+	for _, s := range []Session{sold, failed, other} {
+		require.NoError(t, archive.Archive(s))
+	}
+
+	got, ok, err := archive.LastExit("MintA", "main")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, sold.ID, got.ID)
+
+	_, ok, err = archive.LastExit("MintB", "main")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	var nilArchive *SessionArchive
+	_, ok, err = nilArchive.LastExit("MintA", "main")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
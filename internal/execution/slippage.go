@@ -0,0 +1,54 @@
+// internal/execution/slippage.go
+package execution
+
+import (
+	"sort"
+)
+
+const (
+	learnedSlippageWindow     = 10   // Сколько последних продаж учитывать
+	learnedSlippageMinSamples = 2    // Минимум продаж для рекомендации
+	learnedSlippageMargin     = 2.0  // Запас поверх худшего проскальзывания, п.п.
+	learnedSlippageMin        = 1.0  // Нижняя граница рекомендации, %
+	learnedSlippageMax        = 50.0 // Верхняя граница рекомендации, %
+)
+
+// LearnedSlippage — рекомендация проскальзывания для продаж токена на площадке,
+// выведенная из фактического проскальзывания прошлых продаж.
+type LearnedSlippage struct {
+	Mint     string
+	Venue    string
+	Percent  float64 // Рекомендуемая настройка slippage, %
+	Worst    float64 // Худшее фактическое проскальзывание в выборке, %
+	Samples  int
+	Reliable bool // Продаж достаточно для рекомендации
+}
+
+// LearnSellSlippage подбирает slippage для продаж mint на площадке venue:
+// худшее фактическое проскальзывание последних продаж плюс запас.
+func LearnSellSlippage(records []Record, mint, venue string) LearnedSlippage {
+	learned := LearnedSlippage{Mint: mint, Venue: venue}
+
+	var sells []Record
+	for _, rec := range records {
+		if rec.Side != SideSell || rec.Mint != mint || rec.Venue != venue || !rec.Success() {
+			continue
+		}
+		if _, ok := rec.SlippagePercent(); ok {
+			sells = append(sells, rec)
+		}
+	}
+	sort.Slice(sells, func(i, j int) bool { return sells[i].StartedAt.Before(sells[j].StartedAt) })
+	if len(sells) > learnedSlippageWindow {
+		sells = sells[len(sells)-learnedSlippageWindow:]
+	}
+
+	for _, rec := range sells {
+		s, _ := rec.SlippagePercent()
+		learned.Worst = max(learned.Worst, s)
+	}
+	learned.Samples = len(sells)
+	learned.Reliable = learned.Samples >= learnedSlippageMinSamples
+	learned.Percent = min(max(learned.Worst+learnedSlippageMargin, learnedSlippageMin), learnedSlippageMax)
+	return learned
+}
@@ -0,0 +1,56 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sellRecord(mint, venue string, quoted, actual uint64, at time.Time) Record {
+	return Record{
+		Side:        SideSell,
+		Venue:       venue,
+		Mint:        mint,
+		StartedAt:   at,
+		ConfirmedAt: at.Add(time.Second),
+		QuotedOut:   quoted,
+		ActualOut:   actual,
+	}
+}
+
+func TestLearnSellSlippage(t *testing.T) {
+	now := time.Now()
+	records := []Record{
+		sellRecord("MintA", "Pump.fun", 1000, 970, now.Add(-3*time.Minute)), // 3%
+		sellRecord("MintA", "Pump.fun", 1000, 920, now.Add(-2*time.Minute)), // 8%
+		sellRecord("MintA", "Pump.Swap", 1000, 500, now),                    // другая площадка
+		sellRecord("MintB", "Pump.fun", 1000, 500, now),                     // другой токен
+		{Side: SideSell, Venue: "Pump.fun", Mint: "MintA", QuotedOut: 1000, ActualOut: 100, Error: "failed"},
+		{Side: SideBuy, Venue: "Pump.fun", Mint: "MintA", QuotedOut: 1000, ActualOut: 100, ConfirmedAt: now},
+	}
+
+	learned := LearnSellSlippage(records, "MintA", "Pump.fun")
+	assert.Equal(t, 2, learned.Samples)
+	assert.True(t, learned.Reliable)
+	assert.InDelta(t, 8.0, learned.Worst, 1e-9)
+	assert.InDelta(t, 10.0, learned.Percent, 1e-9)
+}
+
+func TestLearnSellSlippage_WindowAndBounds(t *testing.T) {
+	now := time.Now()
+	// Старая продажа с большим проскальзыванием выпадает из окна
+	records := []Record{sellRecord("MintA", "Pump.fun", 1000, 100, now.Add(-time.Hour))}
+	for i := 0; i < learnedSlippageWindow; i++ {
+		records = append(records, sellRecord("MintA", "Pump.fun", 1000, 1010, now.Add(time.Duration(i)*time.Second)))
+	}
+
+	learned := LearnSellSlippage(records, "MintA", "Pump.fun")
+	assert.Equal(t, learnedSlippageWindow, learned.Samples)
+	assert.Equal(t, 0.0, learned.Worst)
+	assert.Equal(t, learnedSlippageMargin, learned.Percent)
+
+	learned = LearnSellSlippage(records[:1], "MintA", "Pump.fun")
+	assert.False(t, learned.Reliable)
+	assert.Equal(t, learnedSlippageMax, learned.Percent)
+}
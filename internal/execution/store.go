@@ -0,0 +1,206 @@
+// internal/execution/store.go
+package execution
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// DefaultStorePath — файл с метриками исполнения (JSON Lines).
+const DefaultStorePath = "logs/executions.jsonl"
+
+// Store хранит записи исполнения в файле JSON Lines.
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	cipher *task.JournalCipher // Шифр строк журнала (nil — открытый текст)
+}
+
+// NewStore создает хранилище по указанному пути.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// SetCipher включает шифрование строк журнала шифром c; вызывается до первой записи.
+func (s *Store) SetCipher(c *task.JournalCipher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cipher = c
+}
+
+// Append дописывает запись в конец файла.
+func (s *Store) Append(rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create store dir: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal record: %w", err)
+	}
+	if line, err = s.cipher.Seal(line); err != nil {
+		return fmt.Errorf("encrypt record: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	return nil
+}
+
+// Load читает записи, начатые не раньше since. Поврежденные строки пропускаются, а
+// зашифрованные без шифра дают ErrJournalLocked.
+func (s *Store) Load(since time.Time) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		data, err := s.cipher.Open(scanner.Bytes())
+		if errors.Is(err, task.ErrJournalLocked) {
+			return nil, fmt.Errorf("read store: %w", err)
+		}
+		var rec Record
+		if err != nil || json.Unmarshal(data, &rec) != nil {
+			continue
+		}
+		if rec.StartedAt.Before(since) {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read store: %w", err)
+	}
+	return records, nil
+}
+
+// TradeQuery — фильтр поиска по журналу сделок; пустые поля не ограничивают выборку.
+type TradeQuery struct {
+	Mint   string    `json:"mint"`
+	Wallet string    `json:"wallet"`
+	Side   string    `json:"side"`  // buy или sell
+	From   time.Time `json:"from"`  // Начата не раньше
+	To     time.Time `json:"to"`    // Начата не позже
+	Limit  int       `json:"limit"` // Сколько последних сделок вернуть (0 — все)
+}
+
+// Match сообщает, подходит ли запись под фильтр.
+func (q TradeQuery) Match(rec Record) bool {
+	switch {
+	case q.Mint != "" && rec.Mint != q.Mint:
+		return false
+	case q.Wallet != "" && rec.Wallet != q.Wallet:
+		return false
+	case q.Side != "" && rec.Side != q.Side:
+		return false
+	case !q.From.IsZero() && rec.StartedAt.Before(q.From):
+		return false
+	case !q.To.IsZero() && rec.StartedAt.After(q.To):
+		return false
+	}
+	return true
+}
+
+// Search возвращает подходящие под фильтр записи в порядке записи.
+func (s *Store) Search(q TradeQuery) ([]Record, error) {
+	records, err := s.Load(q.From)
+	if err != nil {
+		return nil, err
+	}
+	var found []Record
+	for _, rec := range records {
+		if q.Match(rec) {
+			found = append(found, rec)
+		}
+	}
+	if q.Limit > 0 && len(found) > q.Limit {
+		found = found[len(found)-q.Limit:]
+	}
+	return found, nil
+}
+
+// Follow вызывает fn для каждой записи, дописанной в файл после начала наблюдения,
+// пока не будет отменен ctx. Используется для наблюдения за другим процессом.
+func (s *Store) Follow(ctx context.Context, interval time.Duration, fn func(Record)) error {
+	var offset int64
+	if info, err := os.Stat(s.path); err == nil {
+		offset = info.Size()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		next, err := s.readFrom(offset, fn)
+		if err != nil {
+			return err
+		}
+		offset = next
+	}
+}
+
+// readFrom читает полные строки начиная с offset и возвращает новое смещение.
+func (s *Store) readFrom(offset int64, fn func(Record)) (int64, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return offset, nil
+	}
+	if err != nil {
+		return offset, fmt.Errorf("open store: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, fmt.Errorf("seek store: %w", err)
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// Неполная строка будет дочитана на следующем тике
+			return offset, nil
+		}
+		offset += int64(len(line))
+
+		var rec Record
+		if data, err := s.cipher.Open(line); err == nil && json.Unmarshal(data, &rec) == nil {
+			fn(rec)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package execution
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+func TestStore_EmptyAndMissing(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "logs", "executions.jsonl"))
+
+	records, err := store.Load(time.Time{})
+	require.NoError(t, err, "a store that was never written is empty")
+	assert.Empty(t, records)
+
+	found, err := store.Search(TradeQuery{Mint: "MintA"})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	assert.Nil(t, ReportLines(records, 24*time.Hour))
+}
+
+func TestStore_LoadAndSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "executions.jsonl")
+	store := NewStore(path)
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.Append(Record{TaskName: "old", Mint: "MintA", Side: SideBuy, StartedAt: day.Add(-48 * time.Hour)}))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("{broken\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, store.Append(Record{TaskName: "buy", Mint: "MintA", Wallet: "main", Side: SideBuy, StartedAt: day}))
+	require.NoError(t, store.Append(Record{TaskName: "sell", Mint: "MintA", Wallet: "main", Side: SideSell, StartedAt: day.Add(time.Hour)}))
+	require.NoError(t, store.Append(Record{TaskName: "other", Mint: "MintB", Wallet: "alt", Side: SideBuy, StartedAt: day.Add(2 * time.Hour)}))
+
+	records, err := store.Load(day)
+	require.NoError(t, err)
+	require.Len(t, records, 3, "older records and broken lines are skipped")
+	assert.Equal(t, "buy", records[0].TaskName)
+
+	for name, tc := range map[string]struct {
+		query TradeQuery
+		want  []string
+	}{
+		"all":          {query: TradeQuery{}, want: []string{"old", "buy", "sell", "other"}},
+		"by mint":      {query: TradeQuery{Mint: "MintA"}, want: []string{"old", "buy", "sell"}},
+		"by wallet":    {query: TradeQuery{Wallet: "alt"}, want: []string{"other"}},
+		"by side":      {query: TradeQuery{Mint: "MintA", Side: SideSell}, want: []string{"sell"}},
+		"by period":    {query: TradeQuery{From: day, To: day.Add(time.Hour)}, want: []string{"buy", "sell"}},
+		"latest only":  {query: TradeQuery{Limit: 2}, want: []string{"sell", "other"}},
+		"nothing left": {query: TradeQuery{Mint: "MintC"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			found, err := store.Search(tc.query)
+			require.NoError(t, err)
+			var names []string
+			for _, rec := range found {
+				names = append(names, rec.TaskName)
+			}
+			assert.Equal(t, tc.want, names)
+		})
+	}
+}
+
+func TestStore_Follow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "executions.jsonl")
+	store := NewStore(path)
+	require.NoError(t, store.Append(Record{TaskName: "before"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	followed := make(chan string, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- store.Follow(ctx, 5*time.Millisecond, func(rec Record) { followed <- rec.TaskName })
+	}()
+	next := func() string {
+		select {
+		case name := <-followed:
+			return name
+		case <-ctx.Done():
+			t.Fatal("no record followed")
+			return ""
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond) // Follow запомнил размер файла
+	require.NoError(t, store.Append(Record{TaskName: "first"}))
+	assert.Equal(t, "first", next(), "records written before Follow started are not replayed")
+
+	// Строка, дописанная частями, приходит один раз и целиком
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"task_name":"sec`)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	_, err = f.WriteString("ond\"}\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, "second", next())
+
+	require.NoError(t, store.Append(Record{TaskName: "third"}))
+	assert.Equal(t, "third", next())
+	assert.Empty(t, followed)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStore_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "executions.jsonl")
+	c, err := task.OpenJournalCipher(filepath.Join(dir, "journal.key"), []byte("secret"))
+	require.NoError(t, err)
+
+	plain := NewStore(path)
+	require.NoError(t, plain.Append(Record{TaskName: "before", Mint: "MintA"}))
+	store := NewStore(path)
+	store.SetCipher(c)
+	require.NoError(t, store.Append(Record{TaskName: "after", Mint: "MintB"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "MintB")
+	records, err := store.Load(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "before", records[0].TaskName)
+	assert.Equal(t, "after", records[1].TaskName)
+
+	_, err = plain.Load(time.Time{})
+	assert.ErrorIs(t, err, task.ErrJournalLocked)
+}
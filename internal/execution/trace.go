@@ -0,0 +1,317 @@
+// internal/execution/trace.go
+package execution
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Стороны сделки для Record.Side.
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// Этапы исполнения для разбивки задержки.
+const (
+	StageQuote       = "quote"
+	StagePriorityFee = "priority_fee"
+	StageBlockhash   = "blockhash"
+	StageSigned      = "signed"
+	StageSend        = "send"
+)
+
+// baseFeeLamports — базовая комиссия за одну подпись.
+const baseFeeLamports = 5_000
+
+// Record — метрики исполнения одной сделки, сохраняемые после ее завершения.
+type Record struct {
+	TaskName     string    `json:"task_name"`
+	Side         string    `json:"side"`
+	Venue        string    `json:"venue"`
+	RPC          string    `json:"rpc"`
+	Mint         string    `json:"mint"`
+	Wallet       string    `json:"wallet"`
+	Signature    string    `json:"signature,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	SentAt       time.Time `json:"sent_at,omitempty"`
+	ConfirmedAt  time.Time `json:"confirmed_at,omitempty"`
+	QuotedOut    uint64    `json:"quoted_out"`          // Ожидаемый выход (raw токены для buy, lamports для sell)
+	ActualOut    uint64    `json:"actual_out"`          // Фактический выход из метаданных транзакции
+	ActualIn     uint64    `json:"actual_in,omitempty"` // Фактически отдано (lamports для buy, raw токены для sell)
+	PriorityFee  uint64    `json:"priority_fee_micro_lamports"`
+	ComputeUnits uint32    `json:"compute_units"`
+	FeePaid      uint64    `json:"fee_paid_lamports"`
+	ProtocolFee  uint64    `json:"protocol_fee_lamports,omitempty"` // Комиссия площадки, удержанная из выхода продажи
+	CreatorFee   uint64    `json:"creator_fee_lamports,omitempty"`  // Комиссия создателя токена, удержанная из выхода продажи
+	Rebroadcasts int       `json:"rebroadcasts,omitempty"`
+	BlockhashAge int64     `json:"blockhash_age_ms,omitempty"` // Возраст blockhash в момент первой отправки
+	Budget       int64     `json:"budget_ms,omitempty"`        // Бюджет времени сделки (trade_deadline)
+	Stages       []Stage   `json:"stages,omitempty"`           // Моменты прохождения этапов до отправки
+	Error        string    `json:"error,omitempty"`
+}
+
+// Stage — момент завершения одного этапа исполнения.
+type Stage struct {
+	Name string    `json:"name"`
+	At   time.Time `json:"at"`
+}
+
+// Success сообщает, была ли сделка подтверждена без ошибок.
+func (r Record) Success() bool {
+	return r.Error == "" && !r.ConfirmedAt.IsZero()
+}
+
+// Latency возвращает время от команды до подтверждения.
+func (r Record) Latency() time.Duration {
+	if r.ConfirmedAt.IsZero() {
+		return 0
+	}
+	return r.ConfirmedAt.Sub(r.StartedAt)
+}
+
+// SendLatency возвращает время от команды до отправки транзакции.
+func (r Record) SendLatency() time.Duration {
+	if r.SentAt.IsZero() {
+		return 0
+	}
+	return r.SentAt.Sub(r.StartedAt)
+}
+
+// Breakdown описывает, сколько занял каждый этап, например
+// "quote 120ms → priority_fee 40ms → blockhash 300ms → send 80ms".
+func (r Record) Breakdown() string {
+	parts := make([]string, 0, len(r.Stages))
+	prev := r.StartedAt
+	for _, st := range r.Stages {
+		parts = append(parts, fmt.Sprintf("%s %s", st.Name, st.At.Sub(prev).Round(time.Millisecond)))
+		prev = st.At
+	}
+	return strings.Join(parts, " → ")
+}
+
+// SlippagePercent возвращает реализованное проскальзывание относительно котировки
+// (положительное значение — получили меньше, чем ожидали).
+func (r Record) SlippagePercent() (float64, bool) {
+	if r.QuotedOut == 0 || r.ActualOut == 0 {
+		return 0, false
+	}
+	return (float64(r.QuotedOut) - float64(r.ActualOut)) / float64(r.QuotedOut) * 100, true
+}
+
+// SellPrice возвращает фактическую цену продажи в SOL за токен с decimals;
+// false — запись не продажа или в ней нет фактических сумм.
+func (r Record) SellPrice(decimals uint8) (float64, bool) {
+	if r.Side != SideSell || r.ActualIn == 0 || r.ActualOut == 0 {
+		return 0, false
+	}
+	return lamportsToSol(r.ActualOut) / (float64(r.ActualIn) / math.Pow10(int(decimals))), true
+}
+
+// FeeEstimate возвращает ожидаемую комиссию в lamports по заданной цене и лимиту CU.
+func (r Record) FeeEstimate() uint64 {
+	return NetworkFee(r.PriorityFee, r.ComputeUnits)
+}
+
+// Trace накапливает метрики сделки по мере ее исполнения.
+// Передается через context, все методы безопасны для nil.
+type Trace struct {
+	mu     sync.Mutex
+	rec    Record
+	onSent func(solana.Signature) // Вызывается для каждой отправленной версии транзакции
+
+	blockhashAt time.Time // Когда был получен blockhash транзакции
+}
+
+// NewTrace создает трассировку; StartedAt проставляется, если не задан.
+func NewTrace(rec Record) *Trace {
+	if rec.StartedAt.IsZero() {
+		rec.StartedAt = time.Now()
+	}
+	return &Trace{rec: rec}
+}
+
+type traceKey struct{}
+
+// WithTrace возвращает контекст, несущий трассировку.
+func WithTrace(ctx context.Context, tr *Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, tr)
+}
+
+// FromContext возвращает трассировку из контекста или nil.
+func FromContext(ctx context.Context) *Trace {
+	tr, _ := ctx.Value(traceKey{}).(*Trace)
+	return tr
+}
+
+// SetQuote запоминает ожидаемый выход сделки.
+func (t *Trace) SetQuote(out uint64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.QuotedOut = out
+	t.addStage(StageQuote)
+	t.mu.Unlock()
+}
+
+// SetVenueFees запоминает комиссии протокола и создателя токена (lamports),
+// удержанные из ожидаемого выхода продажи.
+func (t *Trace) SetVenueFees(protocol, creator uint64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.ProtocolFee = protocol
+	t.rec.CreatorFee = creator
+	t.mu.Unlock()
+}
+
+// SetPriorityFee запоминает цену CU (micro-lamports) и лимит CU.
+func (t *Trace) SetPriorityFee(microLamports uint64, computeUnits uint32) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.PriorityFee = microLamports
+	t.rec.ComputeUnits = computeUnits
+	t.addStage(StagePriorityFee)
+	t.mu.Unlock()
+}
+
+// SetRPC запоминает хост RPC, через который ушла транзакция.
+func (t *Trace) SetRPC(host string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.RPC = host
+	t.mu.Unlock()
+}
+
+// OnSent регистрирует обработчик подписей отправленных транзакций, включая переотправки.
+func (t *Trace) OnSent(fn func(solana.Signature)) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.onSent = fn
+	t.mu.Unlock()
+}
+
+// MarkSent отмечает отправку транзакции.
+func (t *Trace) MarkSent(sig solana.Signature) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.Signature = sig.String()
+	t.rec.SentAt = time.Now()
+	if !t.blockhashAt.IsZero() {
+		t.rec.BlockhashAge = t.rec.SentAt.Sub(t.blockhashAt).Milliseconds()
+	}
+	t.addStage(StageSend)
+	onSent := t.onSent
+	t.mu.Unlock()
+
+	if onSent != nil {
+		onSent(sig)
+	}
+}
+
+// MarkRebroadcast отмечает повторную отправку транзакции с повышенной ценой CU.
+func (t *Trace) MarkRebroadcast(sig solana.Signature) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.Rebroadcasts++
+	onSent := t.onSent
+	t.mu.Unlock()
+
+	if onSent != nil {
+		onSent(sig)
+	}
+}
+
+// SetLanded запоминает версию транзакции, которая подтвердилась,
+// чтобы фактические комиссия и выход читались по ней.
+func (t *Trace) SetLanded(sig solana.Signature, microLamports uint64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.Signature = sig.String()
+	t.rec.PriorityFee = microLamports
+	t.mu.Unlock()
+}
+
+// SetBlockhash запоминает момент получения blockhash, чтобы при отправке записать его возраст.
+func (t *Trace) SetBlockhash(fetchedAt time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.blockhashAt = fetchedAt
+	t.mu.Unlock()
+}
+
+// MarkStage отмечает завершение промежуточного этапа (получение blockhash, подпись и т.д.).
+func (t *Trace) MarkStage(name string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.addStage(name)
+	t.mu.Unlock()
+}
+
+// MarkConfirmed отмечает подтверждение транзакции.
+func (t *Trace) MarkConfirmed() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.ConfirmedAt = time.Now()
+	t.mu.Unlock()
+}
+
+// Fail фиксирует ошибку сделки.
+func (t *Trace) Fail(err error) {
+	if t == nil || err == nil {
+		return
+	}
+	t.mu.Lock()
+	t.rec.Error = err.Error()
+	t.mu.Unlock()
+}
+
+// Record возвращает снимок накопленных метрик.
+func (t *Trace) Record() Record {
+	if t == nil {
+		return Record{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rec := t.rec
+	rec.Stages = append([]Stage(nil), t.rec.Stages...)
+	return rec
+}
+
+// addStage добавляет этап; вызывается под блокировкой.
+func (t *Trace) addStage(name string) {
+	t.rec.Stages = append(t.rec.Stages, Stage{Name: name, At: time.Now()})
+}
+
+// update изменяет запись под блокировкой.
+func (t *Trace) update(fn func(r *Record)) {
+	t.mu.Lock()
+	fn(&t.rec)
+	t.mu.Unlock()
+}
@@ -0,0 +1,35 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace_BlockhashAge(t *testing.T) {
+	tr := NewTrace(Record{})
+	tr.SetBlockhash(time.Now().Add(-250 * time.Millisecond))
+	tr.MarkSent(solana.Signature{1})
+
+	age := tr.Record().BlockhashAge
+	assert.GreaterOrEqual(t, age, int64(250))
+	assert.Less(t, age, int64(5000))
+
+	// Без SetBlockhash возраст не записывается
+	plain := NewTrace(Record{})
+	plain.MarkSent(solana.Signature{2})
+	assert.Zero(t, plain.Record().BlockhashAge)
+}
+
+func TestBlockhashAgeLine(t *testing.T) {
+	sent := time.Now()
+	records := []Record{
+		{SentAt: sent, BlockhashAge: 100},
+		{SentAt: sent, BlockhashAge: 300},
+		{SentAt: sent}, // Возраст не записан
+	}
+	assert.Equal(t, "  Blockhash age at send: avg 200ms, max 300ms (2 trades)", blockhashAgeLine(records))
+	assert.Empty(t, blockhashAgeLine(records[2:]))
+}
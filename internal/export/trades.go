@@ -0,0 +1,147 @@
+// internal/export/trades.go
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+)
+
+// Format — формат файла выгрузки.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// FormatFromPath определяет формат выгрузки по расширению файла: .csv или .json.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported export file %q: use .csv or .json", path)
+	}
+}
+
+// tradeColumns — колонки CSV-выгрузки сделок.
+var tradeColumns = []string{
+	"started_at", "confirmed_at", "task_name", "side", "venue", "wallet", "mint", "signature",
+	"quoted_out", "actual_in", "actual_out", "fee_paid_lamports", "priority_fee_micro_lamports", "compute_units",
+	"latency_ms", "status", "error",
+}
+
+// TradeExporter выгружает сделки из журнала исполнения в CSV или JSON.
+type TradeExporter struct {
+	store *execution.Store
+}
+
+// NewTradeExporter создает выгрузку сделок журнала store.
+func NewTradeExporter(store *execution.Store) *TradeExporter {
+	return &TradeExporter{store: store}
+}
+
+// Export записывает в path сделки, подходящие под q (период, токен, кошелек, сторона),
+// в формате по расширению path и возвращает число выгруженных сделок.
+func (e *TradeExporter) Export(q execution.TradeQuery, path string) (int, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return 0, err
+	}
+	records, err := e.store.Search(q)
+	if err != nil {
+		return 0, err
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return 0, fmt.Errorf("create export dir: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("create export file: %w", err)
+	}
+	if err := WriteTrades(f, format, records); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("write export file: %w", err)
+	}
+	return len(records), nil
+}
+
+// WriteTrades записывает сделки в w в формате format.
+func WriteTrades(w io.Writer, format Format, records []execution.Record) error {
+	switch format {
+	case FormatJSON:
+		if records == nil {
+			records = []execution.Record{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			return fmt.Errorf("write json export: %w", err)
+		}
+		return nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		_ = cw.Write(tradeColumns)
+		for _, rec := range records {
+			_ = cw.Write(tradeRow(rec))
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("write csv export: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// tradeRow раскладывает запись исполнения по колонкам tradeColumns.
+func tradeRow(rec execution.Record) []string {
+	status := "failed"
+	if rec.Success() {
+		status = "success"
+	}
+	return []string{
+		formatTime(rec.StartedAt),
+		formatTime(rec.ConfirmedAt),
+		rec.TaskName,
+		rec.Side,
+		rec.Venue,
+		rec.Wallet,
+		rec.Mint,
+		rec.Signature,
+		strconv.FormatUint(rec.QuotedOut, 10),
+		strconv.FormatUint(rec.ActualIn, 10),
+		strconv.FormatUint(rec.ActualOut, 10),
+		strconv.FormatUint(rec.FeePaid, 10),
+		strconv.FormatUint(rec.PriorityFee, 10),
+		strconv.FormatUint(uint64(rec.ComputeUnits), 10),
+		strconv.FormatInt(rec.Latency().Milliseconds(), 10),
+		status,
+		rec.Error,
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradeExporter_FiltersAndFormats(t *testing.T) {
+	dir := t.TempDir()
+	store := execution.NewStore(filepath.Join(dir, "executions.jsonl"))
+	day := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, rec := range []execution.Record{
+		{TaskName: "early", Side: "buy", Mint: "MintA", Wallet: "main", StartedAt: day.Add(-48 * time.Hour)},
+		{TaskName: "snipe", Side: "buy", Mint: "MintA", Wallet: "main", Signature: "sig1",
+			StartedAt: day, ConfirmedAt: day.Add(1500 * time.Millisecond), ActualIn: 100_000_000, ActualOut: 1000, FeePaid: 5000},
+		{TaskName: "other", Side: "sell", Mint: "MintB", Wallet: "main", StartedAt: day, Error: "slippage"},
+	} {
+		require.NoError(t, store.Append(rec))
+	}
+	exporter := NewTradeExporter(store)
+	q := execution.TradeQuery{Mint: "MintA", From: day.Add(-time.Hour)}
+
+	csvPath := filepath.Join(dir, "out", "trades.csv")
+	n, err := exporter.Export(q, csvPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	data, err := os.ReadFile(csvPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, strings.Join(tradeColumns, ","), lines[0])
+	assert.Equal(t, "2025-03-01T12:00:00Z,2025-03-01T12:00:01Z,snipe,buy,,main,MintA,sig1,0,100000000,1000,5000,0,0,1500,success,", lines[1])
+
+	jsonPath := filepath.Join(dir, "trades.json")
+	n, err = exporter.Export(execution.TradeQuery{Side: "sell"}, jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	data, err = os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	var records []execution.Record
+	require.NoError(t, json.Unmarshal(data, &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, "slippage", records[0].Error)
+
+	_, err = exporter.Export(q, filepath.Join(dir, "trades.xlsx"))
+	assert.EqualError(t, err, `unsupported export file "`+filepath.Join(dir, "trades.xlsx")+`": use .csv or .json`)
+}
@@ -0,0 +1,124 @@
+//go:build integration
+
+// Интеграционные тесты полного цикла покупка → мониторинг → продажа против
+// solana-test-validator с аккаунтами, клонированными из mainnet (make validator).
+// Запуск: make test-integration. Mint'ы для проверки задаются переменными
+// SOLANA_BOT_TEST_PUMPFUN_MINT и SOLANA_BOT_TEST_PUMPSWAP_MINT; их кривая или пул
+// должны быть склонированы в валидатор через CLONE.
+package integration
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const defaultValidatorURL = "http://127.0.0.1:8899"
+
+func validatorURL() string {
+	if url := os.Getenv("SOLANA_BOT_TEST_RPC"); url != "" {
+		return url
+	}
+	return defaultValidatorURL
+}
+
+// fundedWallet создает новый кошелек и пополняет его airdrop'ом валидатора.
+func fundedWallet(t *testing.T, ctx context.Context, sol uint64) *task.Wallet {
+	t.Helper()
+	key, err := solana.NewRandomPrivateKey()
+	require.NoError(t, err)
+	wallet, err := task.NewWallet(key.String())
+	require.NoError(t, err)
+
+	client := rpc.New(validatorURL())
+	sig, err := client.RequestAirdrop(ctx, wallet.PublicKey, sol*solana.LAMPORTS_PER_SOL, rpc.CommitmentConfirmed)
+	require.NoError(t, err, "validator unreachable at %s, run make validator", validatorURL())
+
+	require.Eventually(t, func() bool {
+		statuses, err := client.GetSignatureStatuses(ctx, false, sig)
+		return err == nil && len(statuses.Value) == 1 && statuses.Value[0] != nil &&
+			statuses.Value[0].ConfirmationStatus != rpc.ConfirmationStatusProcessed
+	}, 30*time.Second, 500*time.Millisecond, "airdrop not confirmed")
+	return wallet
+}
+
+// runCycle покупает токен на amountSol, проверяет цену и баланс, затем продает
+// позицию целиком и проверяет, что токены ушли, а SOL вернулся.
+func runCycle(t *testing.T, dexName string, operation task.OperationType, mint string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	logger := zap.NewNop()
+	client := blockchain.NewClient(validatorURL(), logger)
+	wallet := fundedWallet(t, ctx, 10)
+
+	d, err := dex.GetDEXByName(dexName, client, wallet, logger)
+	require.NoError(t, err)
+
+	solBefore, err := client.GetBalance(ctx, wallet.PublicKey, rpc.CommitmentConfirmed)
+	require.NoError(t, err)
+
+	buy := &task.Task{
+		TaskName:        "integration_buy",
+		Module:          dexName,
+		Operation:       operation,
+		AmountSol:       0.5,
+		SlippagePercent: 20,
+		PriorityFeeSol:  "0.000001",
+		ComputeUnits:    200000,
+		TokenMint:       mint,
+	}
+	require.NoError(t, d.Execute(ctx, buy), "buy")
+
+	tokens, err := d.GetTokenBalance(ctx, mint)
+	require.NoError(t, err)
+	require.Positive(t, tokens, "no tokens after buy")
+
+	solAfterBuy, err := client.GetBalance(ctx, wallet.PublicKey, rpc.CommitmentConfirmed)
+	require.NoError(t, err)
+	assert.Less(t, solAfterBuy, solBefore-solana.LAMPORTS_PER_SOL/4, "buy did not spend SOL")
+
+	// Мониторинг: цена и PnL позиции должны считаться по склонированному состоянию.
+	price, err := d.GetTokenPrice(ctx, mint)
+	require.NoError(t, err)
+	assert.Positive(t, price)
+	pnl, err := d.CalculatePnL(ctx, float64(tokens)/1e6, buy.AmountSol) // У токенов pump.fun 6 знаков
+	require.NoError(t, err)
+	assert.Positive(t, pnl.SellEstimate)
+
+	require.NoError(t, d.SellPercentTokens(ctx, mint, 100, 20, "0.000001", 200000), "sell")
+
+	tokens, err = d.GetTokenBalance(ctx, mint)
+	require.NoError(t, err)
+	assert.Zero(t, tokens, "tokens left after selling 100%")
+
+	solAfterSell, err := client.GetBalance(ctx, wallet.PublicKey, rpc.CommitmentConfirmed)
+	require.NoError(t, err)
+	assert.Greater(t, solAfterSell, solAfterBuy, "sell did not return SOL")
+}
+
+func TestPumpFunCycle(t *testing.T) {
+	mint := os.Getenv("SOLANA_BOT_TEST_PUMPFUN_MINT")
+	if mint == "" {
+		t.Skip("SOLANA_BOT_TEST_PUMPFUN_MINT not set")
+	}
+	runCycle(t, "pump.fun", task.OperationSnipe, mint)
+}
+
+func TestPumpSwapCycle(t *testing.T) {
+	mint := os.Getenv("SOLANA_BOT_TEST_PUMPSWAP_MINT")
+	if mint == "" {
+		t.Skip("SOLANA_BOT_TEST_PUMPSWAP_MINT not set")
+	}
+	runCycle(t, "pump.swap", task.OperationSwap, mint)
+}
@@ -0,0 +1,108 @@
+// internal/killswitch/killswitch.go
+package killswitch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Итоги продажи позиции при срабатывании.
+const (
+	ResultSold    = "sold"
+	ResultFailed  = "failed"
+	ResultClosing = "closing" // Позицию уже продает или закрывает другое правило
+)
+
+// Status — состояние kill switch.
+type Status struct {
+	Engaged bool      `json:"engaged"`
+	Since   time.Time `json:"since,omitempty"`  // Когда сработал
+	Source  string    `json:"source,omitempty"` // Кто включил: tui, telegram, local_rpc
+}
+
+// PositionResult — итог продажи одной открытой позиции.
+type PositionResult struct {
+	Mint   string `json:"mint"`
+	Wallet string `json:"wallet"`
+	Result string `json:"result"` // sold, failed или closing
+	Error  string `json:"error,omitempty"`
+}
+
+// Report — итог срабатывания: отмененные ордера и продажа каждой позиции.
+type Report struct {
+	Status
+	AlreadyEngaged bool             `json:"already_engaged"` // Switch уже был включен; продано только то, что осталось
+	CanceledOrders int              `json:"canceled_orders"`
+	OrdersError    string           `json:"orders_error,omitempty"` // Не удалось отменить ожидающие ордера
+	Positions      []PositionResult `json:"positions"`
+}
+
+// Flatten отменяет ожидающие команды и продает открытые позиции.
+type Flatten func(ctx context.Context) (canceledOrders int, positions []PositionResult, err error)
+
+// Switch — аварийный останов торговли: после Engage новые задачи не выполняются,
+// пока не вызван Rearm. Повторный Engage безопасен: срабатывания выполняются по
+// одному, и каждое продает только позиции, которые еще открыты.
+type Switch struct {
+	run sync.Mutex // Одно срабатывание за раз
+
+	mu    sync.Mutex
+	state Status
+	now   func() time.Time
+}
+
+// New создает выключенный kill switch.
+func New() *Switch {
+	return &Switch{now: time.Now}
+}
+
+// Engaged сообщает, остановлена ли торговля.
+func (s *Switch) Engaged() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Engaged
+}
+
+// Status возвращает текущее состояние.
+func (s *Switch) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Engage останавливает торговлю и вызывает flatten. Торговля останавливается до
+// flatten, чтобы задачи, стартующие во время продажи, уже не покупали.
+func (s *Switch) Engage(ctx context.Context, source string, flatten Flatten) Report {
+	s.run.Lock()
+	defer s.run.Unlock()
+
+	s.mu.Lock()
+	already := s.state.Engaged
+	if !already {
+		s.state = Status{Engaged: true, Since: s.now(), Source: source}
+	}
+	report := Report{Status: s.state, AlreadyEngaged: already}
+	s.mu.Unlock()
+
+	canceled, positions, err := flatten(ctx)
+	report.CanceledOrders, report.Positions = canceled, positions
+	if err != nil {
+		report.OrdersError = err.Error()
+	}
+	return report
+}
+
+// Rearm снова разрешает торговлю; false — switch не был включен.
+func (s *Switch) Rearm() (Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.state.Engaged {
+		return s.state, false
+	}
+	s.state = Status{}
+	return s.state, true
+}
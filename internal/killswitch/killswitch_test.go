@@ -0,0 +1,62 @@
+package killswitch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwitch_EngageIsIdempotent(t *testing.T) {
+	s := New()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return at }
+
+	open := []PositionResult{{Mint: "MintA", Wallet: "main", Result: ResultSold}}
+	flatten := func(ctx context.Context) (int, []PositionResult, error) {
+		assert.True(t, s.Engaged(), "tasks must stop before positions are sold")
+		sold := open
+		open = nil
+		return 2, sold, nil
+	}
+
+	report := s.Engage(context.Background(), "tui", flatten)
+	assert.False(t, report.AlreadyEngaged)
+	assert.Equal(t, Status{Engaged: true, Since: at, Source: "tui"}, report.Status)
+	assert.Equal(t, 2, report.CanceledOrders)
+	assert.Len(t, report.Positions, 1)
+
+	s.now = func() time.Time { return at.Add(time.Minute) }
+	report = s.Engage(context.Background(), "telegram", flatten)
+	assert.True(t, report.AlreadyEngaged)
+	assert.Equal(t, "tui", report.Source, "the first engage is kept")
+	assert.Equal(t, at, report.Since)
+	assert.Empty(t, report.Positions)
+}
+
+func TestSwitch_OrdersError(t *testing.T) {
+	s := New()
+	report := s.Engage(context.Background(), "local_rpc", func(ctx context.Context) (int, []PositionResult, error) {
+		return 0, []PositionResult{{Mint: "MintA", Result: ResultFailed, Error: "boom"}}, errors.New("journal unavailable")
+	})
+	assert.Equal(t, "journal unavailable", report.OrdersError)
+	assert.Len(t, report.Positions, 1)
+	assert.True(t, s.Engaged())
+}
+
+func TestSwitch_Rearm(t *testing.T) {
+	var s *Switch
+	assert.False(t, s.Engaged())
+
+	s = New()
+	_, ok := s.Rearm()
+	assert.False(t, ok)
+
+	s.Engage(context.Background(), "tui", func(context.Context) (int, []PositionResult, error) { return 0, nil, nil })
+	status, ok := s.Rearm()
+	assert.True(t, ok)
+	assert.False(t, status.Engaged)
+	assert.False(t, s.Engaged())
+}
@@ -0,0 +1,107 @@
+// internal/launch/builtin.go
+package launch
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+)
+
+// CreatorFilter пропускает только токены, созданные адресами creators.
+func CreatorFilter(creators []solana.PublicKey) LaunchFilter {
+	set := make(map[solana.PublicKey]bool, len(creators))
+	for _, c := range creators {
+		set[c] = true
+	}
+	return Func("creator", func(_ context.Context, t *pumpfun.NewToken) Decision {
+		if !set[t.Creator] {
+			return Rejected("creator %s", t.Creator)
+		}
+		return Next()
+	})
+}
+
+// KeywordFilter пропускает только токены, в имени или символе которых есть одно из
+// слов keywords без учета регистра.
+func KeywordFilter(keywords []string) LaunchFilter {
+	lower := make([]string, len(keywords))
+	for i, k := range keywords {
+		lower[i] = strings.ToLower(k)
+	}
+	return Func("keyword", func(_ context.Context, t *pumpfun.NewToken) Decision {
+		name := strings.ToLower(t.Name + " " + t.Symbol)
+		for _, k := range lower {
+			if strings.Contains(name, k) {
+				return Next()
+			}
+		}
+		return Rejected("name %s", t.Name)
+	})
+}
+
+// NameRegexFilter пропускает только токены, имя или символ которых совпадает с re.
+func NameRegexFilter(re *regexp.Regexp) LaunchFilter {
+	return Func("name_regex", func(_ context.Context, t *pumpfun.NewToken) Decision {
+		if !re.MatchString(t.Name) && !re.MatchString(t.Symbol) {
+			return Rejected("name %s does not match %s", t.Name, re)
+		}
+		return Next()
+	})
+}
+
+// DevBuyFilter пропускает только токены, создатель которых купил в транзакции
+// создания от minSol до maxSol SOL (0 — без ограничения).
+func DevBuyFilter(minSol, maxSol float64) LaunchFilter {
+	return Func("dev_buy", func(_ context.Context, t *pumpfun.NewToken) Decision {
+		if t.InitialBuySol < minSol {
+			return Rejected("initial buy %.3f SOL below minimum", t.InitialBuySol)
+		}
+		if maxSol > 0 && t.InitialBuySol > maxSol {
+			return Rejected("initial buy %.3f SOL above maximum", t.InitialBuySol)
+		}
+		return Next()
+	})
+}
+
+// SignatureHistory — история транзакций адреса, новые первыми (blockchain.Client).
+type SignatureHistory interface {
+	GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int) ([]*rpc.TransactionSignature, error)
+}
+
+const (
+	creatorHistoryLimit = 1000            // Подписей создателя за один запрос
+	creatorAgeTimeout   = 3 * time.Second // Дольше слушатель новых токенов не ждет
+)
+
+// CreatorAgeFilter пропускает только токены создателей, чья первая транзакция была
+// не меньше minAge назад. Создатель с полной страницей истории считается старым.
+// Если историю получить не удалось, токен отклоняется.
+func CreatorAgeFilter(minAge time.Duration, history SignatureHistory, now func() time.Time) LaunchFilter {
+	return Func("creator_age", func(ctx context.Context, t *pumpfun.NewToken) Decision {
+		ctx, cancel := context.WithTimeout(ctx, creatorAgeTimeout)
+		defer cancel()
+		sigs, err := history.GetSignaturesForAddress(ctx, t.Creator, creatorHistoryLimit)
+		if err != nil {
+			return Rejected("creator %s history unavailable: %v", t.Creator, err)
+		}
+		if len(sigs) >= creatorHistoryLimit {
+			return Next()
+		}
+		for i := len(sigs) - 1; i >= 0; i-- {
+			if sigs[i].BlockTime == nil {
+				continue
+			}
+			if age := now().Sub(sigs[i].BlockTime.Time()); age < minAge {
+				return Rejected("creator %s is only %s old", t.Creator, age.Round(time.Second))
+			}
+			return Next()
+		}
+		return Rejected("creator %s has no dated transactions", t.Creator)
+	})
+}
@@ -0,0 +1,111 @@
+// internal/launch/filter.go
+package launch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+)
+
+// Verdict — итог фильтра по новому токену.
+type Verdict int
+
+const (
+	Continue Verdict = iota // Фильтр не возражает, решают следующие
+	Accept                  // Токен принят, следующие фильтры не проверяются
+	Reject                  // Токен отклонен, следующие фильтры не проверяются
+)
+
+// Decision — решение фильтра с причиной для лога.
+type Decision struct {
+	Verdict Verdict
+	Reason  string
+}
+
+// Next — фильтр пропускает токен к следующим фильтрам.
+func Next() Decision { return Decision{Verdict: Continue} }
+
+// Accepted принимает токен, не проверяя оставшиеся фильтры.
+func Accepted(format string, args ...any) Decision {
+	return Decision{Verdict: Accept, Reason: fmt.Sprintf(format, args...)}
+}
+
+// Rejected отклоняет токен, не проверяя оставшиеся фильтры.
+func Rejected(format string, args ...any) Decision {
+	return Decision{Verdict: Reject, Reason: fmt.Sprintf(format, args...)}
+}
+
+// LaunchFilter решает, снайпить ли новый токен. Evaluate вызывается из слушателя
+// новых токенов для каждого токена по очереди, поэтому должен быть быстрым; сетевые
+// запросы ограничиваются ctx.
+type LaunchFilter interface {
+	Name() string
+	Evaluate(ctx context.Context, token *pumpfun.NewToken) Decision
+}
+
+// funcFilter — LaunchFilter из функции.
+type funcFilter struct {
+	name string
+	fn   func(ctx context.Context, token *pumpfun.NewToken) Decision
+}
+
+func (f funcFilter) Name() string { return f.name }
+
+func (f funcFilter) Evaluate(ctx context.Context, token *pumpfun.NewToken) Decision {
+	return f.fn(ctx, token)
+}
+
+// Func оборачивает функцию fn в фильтр с именем name.
+func Func(name string, fn func(ctx context.Context, token *pumpfun.NewToken) Decision) LaunchFilter {
+	return funcFilter{name: name, fn: fn}
+}
+
+var registry struct {
+	mu      sync.RWMutex
+	filters []LaunchFilter
+}
+
+// Register добавляет фильтр, который проверяется после встроенных фильтров из
+// конфигурации, в порядке регистрации. Регистрировать можно и во время работы
+// слушателя: фильтр применяется со следующего токена.
+func Register(f LaunchFilter) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.filters = append(registry.filters, f)
+}
+
+// Registered возвращает зарегистрированные фильтры в порядке регистрации.
+func Registered() []LaunchFilter {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return append([]LaunchFilter(nil), registry.filters...)
+}
+
+// Chain проверяет фильтры по порядку: первый Accept или Reject завершает проверку,
+// токен, который ни один фильтр не остановил, принимается.
+type Chain struct {
+	filters    []LaunchFilter
+	registered func() []LaunchFilter // Фильтры после filters (nil — только filters)
+}
+
+// NewChain создает цепочку из filters, за которыми идут фильтры из Register.
+func NewChain(filters ...LaunchFilter) *Chain {
+	return &Chain{filters: filters, registered: Registered}
+}
+
+// Evaluate возвращает решение цепочки и имя фильтра, который его принял
+// (пусто — токен прошел все фильтры).
+func (c *Chain) Evaluate(ctx context.Context, token *pumpfun.NewToken) (Decision, string) {
+	filters := c.filters
+	if c.registered != nil {
+		filters = append(filters[:len(filters):len(filters)], c.registered()...)
+	}
+	for _, f := range filters {
+		if d := f.Evaluate(ctx, token); d.Verdict != Continue {
+			return d, f.Name()
+		}
+	}
+	return Decision{Verdict: Accept}, ""
+}
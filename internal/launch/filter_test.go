@@ -0,0 +1,136 @@
+package launch
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+)
+
+func verdictFilter(name string, v Verdict, calls *[]string) LaunchFilter {
+	return Func(name, func(context.Context, *pumpfun.NewToken) Decision {
+		*calls = append(*calls, name)
+		return Decision{Verdict: v, Reason: name}
+	})
+}
+
+func TestChain_OrderAndShortCircuit(t *testing.T) {
+	token := &pumpfun.NewToken{Name: "Moon"}
+	for name, tc := range map[string]struct {
+		verdicts []Verdict
+		want     Verdict
+		filter   string
+		calls    []string
+	}{
+		"all continue": {[]Verdict{Continue, Continue}, Accept, "", []string{"f0", "f1"}},
+		"reject stops": {[]Verdict{Continue, Reject, Continue}, Reject, "f1", []string{"f0", "f1"}},
+		"accept stops": {[]Verdict{Accept, Reject}, Accept, "f0", []string{"f0"}},
+		"empty chain":  {nil, Accept, "", nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var calls []string
+			var filters []LaunchFilter
+			for i, v := range tc.verdicts {
+				filters = append(filters, verdictFilter("f"+string(rune('0'+i)), v, &calls))
+			}
+			chain := &Chain{filters: filters}
+			d, filter := chain.Evaluate(context.Background(), token)
+			assert.Equal(t, tc.want, d.Verdict)
+			assert.Equal(t, tc.filter, filter)
+			assert.Equal(t, tc.calls, calls)
+		})
+	}
+}
+
+func TestRegister_AppliesToRunningChain(t *testing.T) {
+	registry.mu.Lock()
+	saved := registry.filters
+	registry.filters = nil
+	registry.mu.Unlock()
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		registry.filters = saved
+		registry.mu.Unlock()
+	})
+
+	var calls []string
+	chain := NewChain(verdictFilter("builtin", Continue, &calls))
+	d, _ := chain.Evaluate(context.Background(), &pumpfun.NewToken{})
+	assert.Equal(t, Accept, d.Verdict)
+
+	Register(verdictFilter("custom", Reject, &calls))
+	d, filter := chain.Evaluate(context.Background(), &pumpfun.NewToken{})
+	assert.Equal(t, Reject, d.Verdict, "a filter registered later applies to the next token")
+	assert.Equal(t, "custom", filter)
+	assert.Equal(t, []string{"builtin", "builtin", "custom"}, calls, "registered filters run after the chain's own")
+}
+
+func TestBuiltinFilters(t *testing.T) {
+	creator := solana.NewWallet().PublicKey()
+	token := &pumpfun.NewToken{Creator: creator, Name: "Moon Cat", Symbol: "MCAT", InitialBuySol: 1.5}
+	for name, tc := range map[string]struct {
+		filter LaunchFilter
+		want   Verdict
+	}{
+		"creator listed":      {CreatorFilter([]solana.PublicKey{creator}), Continue},
+		"creator not listed":  {CreatorFilter([]solana.PublicKey{solana.NewWallet().PublicKey()}), Reject},
+		"keyword in name":     {KeywordFilter([]string{"CAT"}), Continue},
+		"keyword missing":     {KeywordFilter([]string{"dog"}), Reject},
+		"regex on symbol":     {NameRegexFilter(regexp.MustCompile(`^M[A-Z]+T$`)), Continue},
+		"regex mismatch":      {NameRegexFilter(regexp.MustCompile(`^Dog`)), Reject},
+		"dev buy in range":    {DevBuyFilter(1, 2), Continue},
+		"dev buy below min":   {DevBuyFilter(2, 0), Reject},
+		"dev buy above max":   {DevBuyFilter(0, 1), Reject},
+		"dev buy not limited": {DevBuyFilter(0, 0), Continue},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.filter.Evaluate(context.Background(), token).Verdict)
+		})
+	}
+}
+
+// stubHistory — история подписей создателя с заданным временем самой старой.
+type stubHistory struct {
+	oldest time.Time
+	count  int
+	err    error
+}
+
+func (h stubHistory) GetSignaturesForAddress(_ context.Context, _ solana.PublicKey, limit int) ([]*rpc.TransactionSignature, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	sigs := make([]*rpc.TransactionSignature, min(h.count, limit))
+	for i := range sigs {
+		bt := solana.UnixTimeSeconds(h.oldest.Unix())
+		sigs[i] = &rpc.TransactionSignature{BlockTime: &bt}
+	}
+	return sigs, nil
+}
+
+func TestCreatorAgeFilter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	for name, tc := range map[string]struct {
+		history stubHistory
+		want    Verdict
+	}{
+		"old creator":       {stubHistory{oldest: now.Add(-48 * time.Hour), count: 3}, Continue},
+		"fresh creator":     {stubHistory{oldest: now.Add(-time.Hour), count: 3}, Reject},
+		"full history page": {stubHistory{oldest: now, count: creatorHistoryLimit}, Continue},
+		"no history":        {stubHistory{}, Reject},
+		"rpc error":         {stubHistory{err: errors.New("timeout")}, Reject},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := CreatorAgeFilter(24*time.Hour, tc.history, clock)
+			assert.Equal(t, tc.want, f.Evaluate(context.Background(), &pumpfun.NewToken{}).Verdict)
+		})
+	}
+}
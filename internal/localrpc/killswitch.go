@@ -0,0 +1,78 @@
+// internal/localrpc/killswitch.go
+package localrpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/killswitch"
+)
+
+// codeUnavailable — kill switch еще не подключен (задачи не запущены).
+const codeUnavailable = -32002
+
+// KillSwitch — аварийный останов торговли (*bot.WorkerPool).
+type KillSwitch interface {
+	Engage(ctx context.Context, source string) killswitch.Report
+	Rearm(source string) (killswitch.Status, bool)
+	KillSwitchStatus() killswitch.Status
+}
+
+// RearmResult — ответ rearm.
+type RearmResult struct {
+	killswitch.Status
+	Rearmed bool `json:"rearmed"` // false — kill switch не был включен
+}
+
+// killSwitch хранит kill switch отдельно: он подключается после запуска сервера.
+type killSwitch struct {
+	mu sync.Mutex
+	k  KillSwitch
+}
+
+// SetKillSwitch подключает методы panic, rearm и killSwitchStatus.
+func (s *Service) SetKillSwitch(k KillSwitch) {
+	s.kill.mu.Lock()
+	defer s.kill.mu.Unlock()
+	s.kill.k = k
+}
+
+func (s *Service) killSwitch() (KillSwitch, *rpcError) {
+	s.kill.mu.Lock()
+	defer s.kill.mu.Unlock()
+	if s.kill.k == nil {
+		return nil, &rpcError{Code: codeUnavailable, Message: "kill switch is not available"}
+	}
+	return s.kill.k, nil
+}
+
+// engageKillSwitch включает kill switch и ждет итога продажи каждой позиции. Срабатывание не
+// привязано к соединению: обрыв связи с клиентом не прерывает продажи.
+func (s *Service) engageKillSwitch() (interface{}, *rpcError) {
+	k, err := s.killSwitch()
+	if err != nil {
+		return nil, err
+	}
+	report := k.Engage(context.Background(), "local_rpc")
+	if report.Positions == nil {
+		report.Positions = []killswitch.PositionResult{}
+	}
+	return report, nil
+}
+
+func (s *Service) rearmKillSwitch() (interface{}, *rpcError) {
+	k, err := s.killSwitch()
+	if err != nil {
+		return nil, err
+	}
+	status, ok := k.Rearm("local_rpc")
+	return RearmResult{Status: status, Rearmed: ok}, nil
+}
+
+func (s *Service) killSwitchStatus() (interface{}, *rpcError) {
+	k, err := s.killSwitch()
+	if err != nil {
+		return nil, err
+	}
+	return k.KillSwitchStatus(), nil
+}
@@ -0,0 +1,173 @@
+// internal/localrpc/server.go
+package localrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"go.uber.org/zap"
+)
+
+// maxRequestSize ограничивает размер одной строки запроса.
+const maxRequestSize = 64 * 1024
+
+// request — запрос JSON-RPC 2.0. Запросы без id считаются уведомлениями и остаются без ответа.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// Serve принимает соединения на addr, пока не отменен ctx. Каждый запрос и ответ —
+// одна строка JSON. addr вида "unix:/path/bot.sock" открывает unix-сокет,
+// иначе это TCP-адрес, например "127.0.0.1:47822".
+func Serve(ctx context.Context, addr string, svc *Service, logger *zap.Logger) error {
+	network, address := "tcp", addr
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		network, address = "unix", path
+		// Сокет мог остаться после аварийного завершения
+		_ = os.Remove(path)
+		defer os.Remove(path)
+	}
+
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("local rpc: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("local rpc: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc.serveConn(ctx, conn, logger)
+		}()
+	}
+}
+
+// serveConn обрабатывает запросы одного клиента до закрытия соединения.
+func (s *Service) serveConn(ctx context.Context, conn net.Conn, logger *zap.Logger) {
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+
+	// Ответы и уведомления подписки пишутся из разных горутин
+	var writeMu sync.Mutex
+	enc := json.NewEncoder(conn)
+	write := func(v interface{}) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return enc.Encode(v)
+	}
+
+	c := s.newClient()
+	c.notify = func(n notification) {
+		if err := write(n); err != nil {
+			logger.Debug("Local RPC notification failed", zap.Error(err))
+		}
+	}
+	defer c.stop()
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		resp, ok := s.handleClient(c, line)
+		if ok {
+			if err := write(resp); err != nil {
+				logger.Debug("Local RPC write failed", zap.Error(err))
+				return
+			}
+		}
+		// Строка не JSON-RPC (например, заголовок HTTP-запроса из браузера) — дальше не читаем
+		if resp.Error != nil && (resp.Error.Code == codeParseError || resp.Error.Code == codeInvalidRequest) {
+			logger.Debug("Local RPC closed a connection after a malformed request")
+			return
+		}
+	}
+}
+
+// handle разбирает одну строку запроса вне соединения; false — ответ не нужен (уведомление).
+func (s *Service) handle(line []byte) (response, bool) {
+	return s.handleClient(s.newClient(), line)
+}
+
+// handleClient разбирает одну строку запроса клиента c.
+func (s *Service) handleClient(c *client, line []byte) (response, bool) {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(nil, codeParseError, "parse error: "+err.Error()), true
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "invalid request"), true
+	}
+
+	var result interface{}
+	var rpcErr *rpcError
+	switch {
+	case req.Method == "authenticate":
+		result, rpcErr = s.authenticate(c, req.Params)
+	case c.principal.Role == audit.RoleNone:
+		rpcErr = &rpcError{Code: codeUnauthorized, Message: "authenticate first"}
+	case !c.principal.Role.Allows(methodRole(req.Method)):
+		rpcErr = s.forbidden(c, req.Method)
+	case req.Method == "subscribe":
+		result, rpcErr = s.subscribe(c, req.Params)
+	case req.Method == "unsubscribe":
+		result, rpcErr = s.unsubscribe(c)
+	default:
+		result, rpcErr = s.call(req.Method, req.Params)
+		s.recordCall(c, req.Method, result, rpcErr)
+	}
+	s.logCall(c, req.Method, rpcErr)
+	if len(req.ID) == 0 {
+		return response{}, false
+	}
+	if rpcErr != nil {
+		return response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}, true
+	}
+	return response{JSONRPC: "2.0", ID: req.ID, Result: result}, true
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: msg}}
+}
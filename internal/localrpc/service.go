@@ -0,0 +1,299 @@
+// internal/localrpc/service.go
+package localrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/metrics"
+	"github.com/rovshanmuradov/solana-bot/internal/orders"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTailLimit = 20
+	maxTailLimit     = 500
+)
+
+// Коды ошибок JSON-RPC 2.0.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+	codeNotFound       = -32004 // Позиция или сессия не найдена
+)
+
+// Service отвечает на запросы о состоянии работающего бота; единственные изменяющие
+// методы — kill switch (panic / rearm).
+type Service struct {
+	positions *metrics.Positions
+	store     *execution.Store
+	sessions  *execution.SessionArchive
+	orders    *orders.Book
+	kill      killSwitch
+	keys      []apiKey   // Ключи клиентов; пусто — без авторизации, только чтение
+	audit     *audit.Log // Журнал panic, rearm и отказов в доступе (nil — не ведется)
+	logger    *zap.Logger
+	startedAt time.Time
+	now       func() time.Time
+}
+
+// NewService создает сервис поверх реестра позиций, журнала исполнения, архива сессий
+// и журнала лимитных ордеров.
+func NewService(positions *metrics.Positions, store *execution.Store, sessions *execution.SessionArchive, orderBook *orders.Book) *Service {
+	return &Service{
+		positions: positions,
+		store:     store,
+		sessions:  sessions,
+		orders:    orderBook,
+		logger:    zap.NewNop(),
+		startedAt: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Summary — сводка для getSummary.
+type Summary struct {
+	OpenPositions   int     `json:"open_positions"`
+	UnrealizedPnL   float64 `json:"unrealized_pnl_sol"`
+	TradesToday     int     `json:"trades_today"`
+	FailedToday     int     `json:"failed_today"`
+	FeesTodaySol    float64 `json:"fees_today_sol"`
+	UptimeSeconds   float64 `json:"uptime_seconds"`
+	LastExecutionAt string  `json:"last_execution_at,omitempty"`
+}
+
+type getPositionParams struct {
+	Mint   string `json:"mint"`
+	Wallet string `json:"wallet"`
+}
+
+type getSessionParams struct {
+	ID string `json:"id"`
+}
+
+// SessionView — закрытая сессия вместе со сделками ее жизненного цикла.
+type SessionView struct {
+	execution.Session
+	Executions []execution.Record `json:"executions"`
+}
+
+type tailEventsParams struct {
+	Limit int       `json:"limit"`
+	Since time.Time `json:"since"`
+}
+
+// rpcError — ошибка, возвращаемая клиенту в поле error.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// call выполняет метод и возвращает результат для поля result.
+func (s *Service) call(method string, params json.RawMessage) (interface{}, *rpcError) {
+	switch method {
+	case "listPositions":
+		return s.positions.Snapshot(), nil
+	case "getPosition":
+		var p getPositionParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.getPosition(p)
+	case "getSummary":
+		return s.getSummary()
+	case "listSessions":
+		var q execution.SessionQuery
+		if err := decodeParams(params, &q); err != nil {
+			return nil, err
+		}
+		return s.listSessions(q)
+	case "getSession":
+		var p getSessionParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.getSession(p)
+	case "tailEvents":
+		var p tailEventsParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return s.tailEvents(p)
+	case "listTrades":
+		var q execution.TradeQuery
+		if err := decodeParams(params, &q); err != nil {
+			return nil, err
+		}
+		return s.listTrades(q)
+	case "listOrders":
+		return s.listOrders()
+	case "panic":
+		return s.engageKillSwitch()
+	case "rearm":
+		return s.rearmKillSwitch()
+	case "killSwitchStatus":
+		return s.killSwitchStatus()
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+func (s *Service) getPosition(p getPositionParams) (interface{}, *rpcError) {
+	if p.Mint == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "mint is required"}
+	}
+	for _, pos := range s.positions.Snapshot() {
+		if pos.Mint == p.Mint && (p.Wallet == "" || pos.Wallet == p.Wallet) {
+			return pos, nil
+		}
+	}
+	return nil, &rpcError{Code: codeNotFound, Message: "no open position for " + p.Mint}
+}
+
+func (s *Service) getSummary() (interface{}, *rpcError) {
+	now := s.now()
+	summary := Summary{UptimeSeconds: now.Sub(s.startedAt).Seconds()}
+
+	for _, pos := range s.positions.Snapshot() {
+		summary.OpenPositions++
+		summary.UnrealizedPnL += pos.PnLSol
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	records, err := s.loadRecords(midnight)
+	if err != nil {
+		return nil, err
+	}
+	var fees uint64
+	for _, rec := range records {
+		summary.TradesToday++
+		if !rec.Success() {
+			summary.FailedToday++
+		}
+		fees += rec.FeePaid
+	}
+	summary.FeesTodaySol = float64(fees) / float64(solana.LAMPORTS_PER_SOL)
+	if len(records) > 0 {
+		summary.LastExecutionAt = records[len(records)-1].StartedAt.Format(time.RFC3339)
+	}
+	return summary, nil
+}
+
+func (s *Service) tailEvents(p tailEventsParams) (interface{}, *rpcError) {
+	limit := p.Limit
+	if limit <= 0 {
+		limit = defaultTailLimit
+	}
+	limit = min(limit, maxTailLimit)
+
+	records, err := s.loadRecords(p.Since)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	if records == nil {
+		records = []execution.Record{}
+	}
+	return records, nil
+}
+
+func (s *Service) listSessions(q execution.SessionQuery) (interface{}, *rpcError) {
+	if q.Limit <= 0 {
+		q.Limit = defaultTailLimit
+	}
+	q.Limit = min(q.Limit, maxTailLimit)
+
+	sessions, err := s.sessions.Search(q)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	if sessions == nil {
+		sessions = []execution.Session{}
+	}
+	return sessions, nil
+}
+
+func (s *Service) getSession(p getSessionParams) (interface{}, *rpcError) {
+	if p.ID == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "id is required"}
+	}
+	session, ok, err := s.sessions.Get(p.ID)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	if !ok {
+		return nil, &rpcError{Code: codeNotFound, Message: "no archived session " + p.ID}
+	}
+
+	records, rerr := s.loadRecords(time.Time{})
+	if rerr != nil {
+		return nil, rerr
+	}
+	view := SessionView{Session: session, Executions: session.Executions(records)}
+	if view.Executions == nil {
+		view.Executions = []execution.Record{}
+	}
+	return view, nil
+}
+
+// listTrades ищет сделки по токену, кошельку, стороне и периоду.
+func (s *Service) listTrades(q execution.TradeQuery) (interface{}, *rpcError) {
+	if q.Limit <= 0 {
+		q.Limit = defaultTailLimit
+	}
+	q.Limit = min(q.Limit, maxTailLimit)
+
+	var records []execution.Record
+	if s.store != nil {
+		var err error
+		if records, err = s.store.Search(q); err != nil {
+			return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+	}
+	if records == nil {
+		records = []execution.Record{}
+	}
+	return records, nil
+}
+
+// listOrders возвращает ожидающие лимитные ордера.
+func (s *Service) listOrders() (interface{}, *rpcError) {
+	pending, err := s.orders.Pending()
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	if pending == nil {
+		pending = []orders.Order{}
+	}
+	return pending, nil
+}
+
+func (s *Service) loadRecords(since time.Time) ([]execution.Record, *rpcError) {
+	if s.store == nil {
+		return nil, nil
+	}
+	records, err := s.store.Load(since)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	return records, nil
+}
+
+func decodeParams(raw json.RawMessage, dst interface{}) *rpcError {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
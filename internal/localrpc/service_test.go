@@ -0,0 +1,398 @@
+package localrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/rovshanmuradov/solana-bot/internal/killswitch"
+	"github.com/rovshanmuradov/solana-bot/internal/metrics"
+	"github.com/rovshanmuradov/solana-bot/internal/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func call(t *testing.T, svc *Service, line string) map[string]interface{} {
+	t.Helper()
+	return callAs(t, svc, svc.newClient(), line)
+}
+
+// callAs выполняет запрос от имени соединения c, сохраняя его авторизацию между вызовами.
+func callAs(t *testing.T, svc *Service, c *client, line string) map[string]interface{} {
+	t.Helper()
+	resp, ok := svc.handleClient(c, []byte(line))
+	assert.True(t, ok)
+	data, err := json.Marshal(resp)
+	assert.NoError(t, err)
+	var out map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &out))
+	return out
+}
+
+func TestService_Positions(t *testing.T) {
+	positions := metrics.NewPositions()
+	positions.Update("MintA", "main", 0.001, 12.5, 0.125, time.Now())
+	svc := NewService(positions, nil, nil, nil)
+
+	out := call(t, svc, `{"jsonrpc":"2.0","id":1,"method":"listPositions"}`)
+	assert.Equal(t, float64(1), out["id"])
+	list := out["result"].([]interface{})
+	assert.Len(t, list, 1)
+	assert.Equal(t, "MintA", list[0].(map[string]interface{})["mint"])
+
+	out = call(t, svc, `{"jsonrpc":"2.0","id":"a","method":"getPosition","params":{"mint":"MintB"}}`)
+	assert.Equal(t, float64(codeNotFound), out["error"].(map[string]interface{})["code"])
+
+	out = call(t, svc, `{"jsonrpc":"2.0","id":2,"method":"getSummary"}`)
+	summary := out["result"].(map[string]interface{})
+	assert.Equal(t, float64(1), summary["open_positions"])
+	assert.Equal(t, 0.125, summary["unrealized_pnl_sol"])
+}
+
+func TestService_TailEvents(t *testing.T) {
+	store := execution.NewStore(filepath.Join(t.TempDir(), "executions.jsonl"))
+	now := time.Now()
+	for i, name := range []string{"a", "b", "c"} {
+		assert.NoError(t, store.Append(execution.Record{TaskName: name, StartedAt: now.Add(time.Duration(i) * time.Second)}))
+	}
+	svc := NewService(nil, store, nil, nil)
+
+	out := call(t, svc, `{"jsonrpc":"2.0","id":1,"method":"tailEvents","params":{"limit":2}}`)
+	events := out["result"].([]interface{})
+	assert.Len(t, events, 2)
+	assert.Equal(t, "c", events[1].(map[string]interface{})["task_name"])
+}
+
+func TestService_Trades(t *testing.T) {
+	store := execution.NewStore(filepath.Join(t.TempDir(), "executions.jsonl"))
+	day := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	for _, rec := range []execution.Record{
+		{TaskName: "a", Side: execution.SideBuy, Mint: "MintA", Wallet: "main", StartedAt: day.AddDate(0, 0, -1)},
+		{TaskName: "b", Side: execution.SideBuy, Mint: "MintA", Wallet: "main", StartedAt: day},
+		{TaskName: "c", Side: execution.SideSell, Mint: "MintA", Wallet: "main", StartedAt: day.Add(time.Hour)},
+		{TaskName: "d", Side: execution.SideBuy, Mint: "MintA", Wallet: "alt", StartedAt: day.Add(2 * time.Hour)},
+		{TaskName: "e", Side: execution.SideBuy, Mint: "MintB", Wallet: "main", StartedAt: day.Add(3 * time.Hour)},
+	} {
+		assert.NoError(t, store.Append(rec))
+	}
+	svc := NewService(nil, store, nil, nil)
+
+	names := func(out map[string]interface{}) []string {
+		var names []string
+		for _, rec := range out["result"].([]interface{}) {
+			names = append(names, rec.(map[string]interface{})["task_name"].(string))
+		}
+		return names
+	}
+	out := call(t, svc, `{"jsonrpc":"2.0","id":1,"method":"listTrades","params":{"mint":"MintA","wallet":"main","from":"2025-01-02T00:00:00Z"}}`)
+	assert.Equal(t, []string{"b", "c"}, names(out))
+
+	out = call(t, svc, `{"jsonrpc":"2.0","id":2,"method":"listTrades","params":{"side":"buy","to":"2025-01-02T14:00:00Z","limit":2}}`)
+	assert.Equal(t, []string{"b", "d"}, names(out))
+
+	out = call(t, NewService(nil, nil, nil, nil), `{"jsonrpc":"2.0","id":3,"method":"listTrades"}`)
+	assert.Empty(t, out["result"])
+}
+
+func TestService_Errors(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+
+	out := call(t, svc, `{"jsonrpc":"2.0","id":1,"method":"sell"}`)
+	assert.Equal(t, float64(codeMethodNotFound), out["error"].(map[string]interface{})["code"])
+
+	out = call(t, svc, `not json`)
+	assert.Equal(t, float64(codeParseError), out["error"].(map[string]interface{})["code"])
+	assert.Nil(t, out["id"])
+
+	// Уведомление без id остается без ответа
+	_, ok := svc.handle([]byte(`{"jsonrpc":"2.0","method":"listPositions"}`))
+	assert.False(t, ok)
+}
+
+func TestService_Sessions(t *testing.T) {
+	dir := t.TempDir()
+	store := execution.NewStore(filepath.Join(dir, "executions.jsonl"))
+	archive := execution.NewSessionArchive(filepath.Join(dir, "sessions.jsonl"))
+	closed := time.Now()
+	opened := closed.Add(-time.Minute)
+	session := execution.Session{
+		ID:         execution.SessionID("MintA", opened),
+		TaskName:   "snipe",
+		Tasks:      []string{"snipe"},
+		Mint:       "MintA",
+		OpenedAt:   opened,
+		ClosedAt:   closed,
+		PnLPercent: 25,
+		Outcome:    execution.OutcomeSold,
+	}
+	assert.NoError(t, archive.Archive(session))
+	assert.NoError(t, store.Append(execution.Record{TaskName: "snipe", Mint: "MintA", Side: execution.SideSell, StartedAt: closed.Add(-time.Second)}))
+	svc := NewService(nil, store, archive, nil)
+
+	out := call(t, svc, `{"jsonrpc":"2.0","id":1,"method":"listSessions","params":{"mint":"MintA","min_pnl_percent":10}}`)
+	list := out["result"].([]interface{})
+	assert.Len(t, list, 1)
+
+	out = call(t, svc, `{"jsonrpc":"2.0","id":2,"method":"getSession","params":{"id":"`+session.ID+`"}}`)
+	view := out["result"].(map[string]interface{})
+	assert.Equal(t, "sold", view["outcome"])
+	assert.Len(t, view["executions"].([]interface{}), 1)
+
+	out = call(t, svc, `{"jsonrpc":"2.0","id":3,"method":"getSession","params":{"id":"missing"}}`)
+	assert.Equal(t, float64(codeNotFound), out["error"].(map[string]interface{})["code"])
+}
+
+func TestService_Orders(t *testing.T) {
+	book := orders.NewBook(filepath.Join(t.TempDir(), "orders.jsonl"))
+	_, err := book.Place(orders.Order{ID: "a", Side: orders.SideBuy, Mint: "MintA", LimitPrice: 0.001})
+	assert.NoError(t, err)
+	_, err = book.Place(orders.Order{ID: "b", Side: orders.SideSell, Mint: "MintA", LimitPrice: 0.01})
+	assert.NoError(t, err)
+	assert.NoError(t, book.Cancel("b"))
+	svc := NewService(nil, nil, nil, book)
+
+	out := call(t, svc, `{"jsonrpc":"2.0","id":1,"method":"listOrders"}`)
+	list := out["result"].([]interface{})
+	assert.Len(t, list, 1)
+	assert.Equal(t, "a", list[0].(map[string]interface{})["id"])
+
+	out = call(t, NewService(nil, nil, nil, nil), `{"jsonrpc":"2.0","id":2,"method":"listOrders"}`)
+	assert.Empty(t, out["result"])
+}
+
+func TestService_Subscribe(t *testing.T) {
+	positions := metrics.NewPositions()
+	positions.Update("MintA", "main", 0.001, 1, 0.01, time.Now())
+	svc := NewService(positions, nil, nil, nil)
+	svc.SetAPIKey("secret")
+
+	server, conn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.serveConn(ctx, server, zap.NewNop())
+
+	lines := bufio.NewScanner(conn)
+	send := func(line string) map[string]interface{} {
+		_, err := conn.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+		require.True(t, lines.Scan())
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal(lines.Bytes(), &out))
+		return out
+	}
+
+	out := send(`{"jsonrpc":"2.0","id":1,"method":"listPositions"}`)
+	assert.Equal(t, float64(codeUnauthorized), out["error"].(map[string]interface{})["code"])
+	out = send(`{"jsonrpc":"2.0","id":2,"method":"authenticate","params":{"api_key":"wrong"}}`)
+	assert.Equal(t, float64(codeUnauthorized), out["error"].(map[string]interface{})["code"])
+	out = send(`{"jsonrpc":"2.0","id":3,"method":"authenticate","params":{"api_key":"secret"}}`)
+	assert.Equal(t, true, out["result"])
+
+	// Подписка сразу отдает текущее состояние выбранных токенов
+	out = send(`{"jsonrpc":"2.0","id":4,"method":"subscribe","params":{"mints":["MintA"]}}`)
+	assert.Len(t, out["result"], 1)
+
+	positions.Update("MintB", "main", 0.5, 2, 0.02, time.Now())
+	positions.Update("MintA", "main", 0.002, 5, 0.05, time.Now())
+	require.True(t, lines.Scan())
+	var note map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines.Bytes(), &note))
+	assert.Equal(t, "positionUpdated", note["method"])
+	params := note["params"].(map[string]interface{})
+	assert.Equal(t, "MintA", params["mint"])
+	assert.Equal(t, 0.002, params["price"])
+
+	out = send(`{"jsonrpc":"2.0","id":5,"method":"unsubscribe"}`)
+	assert.Equal(t, true, out["result"])
+
+	// Без соединения подписка невозможна
+	out = call(t, NewService(positions, nil, nil, nil), `{"jsonrpc":"2.0","id":6,"method":"subscribe"}`)
+	assert.Equal(t, float64(codeInvalidRequest), out["error"].(map[string]interface{})["code"])
+}
+
+type fakeKillSwitch struct {
+	status killswitch.Status
+}
+
+func (f *fakeKillSwitch) Engage(ctx context.Context, source string) killswitch.Report {
+	already := f.status.Engaged
+	f.status = killswitch.Status{Engaged: true, Source: source}
+	var positions []killswitch.PositionResult
+	if !already {
+		positions = []killswitch.PositionResult{{Mint: "MintA", Wallet: "main", Result: killswitch.ResultSold}}
+	}
+	return killswitch.Report{Status: f.status, AlreadyEngaged: already, Positions: positions}
+}
+
+func (f *fakeKillSwitch) Rearm(source string) (killswitch.Status, bool) {
+	ok := f.status.Engaged
+	f.status = killswitch.Status{}
+	return f.status, ok
+}
+
+func (f *fakeKillSwitch) KillSwitchStatus() killswitch.Status {
+	return f.status
+}
+
+func TestService_KillSwitch(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+	svc.SetKillSwitch(&fakeKillSwitch{})
+
+	// Без local_rpc_api_key kill switch недоступен даже локальным клиентам
+	for _, method := range []string{"panic", "rearm"} {
+		out := call(t, svc, `{"jsonrpc":"2.0","id":1,"method":"`+method+`"}`)
+		assert.Equal(t, float64(codeUnauthorized), out["error"].(map[string]interface{})["code"], method)
+	}
+
+	svc.SetAPIKey("secret")
+	out := call(t, svc, `{"jsonrpc":"2.0","id":1,"method":"panic"}`)
+	assert.Equal(t, float64(codeUnauthorized), out["error"].(map[string]interface{})["code"], "the connection has not authenticated")
+
+	c := &client{}
+	out = callAs(t, svc, c, `{"jsonrpc":"2.0","id":1,"method":"authenticate","params":{"api_key":"secret"}}`)
+	assert.Equal(t, true, out["result"])
+
+	svc.SetKillSwitch(nil)
+	out = callAs(t, svc, c, `{"jsonrpc":"2.0","id":1,"method":"panic"}`)
+	assert.Equal(t, float64(codeUnavailable), out["error"].(map[string]interface{})["code"])
+
+	svc.SetKillSwitch(&fakeKillSwitch{})
+	out = callAs(t, svc, c, `{"jsonrpc":"2.0","id":2,"method":"panic"}`)
+	report := out["result"].(map[string]interface{})
+	assert.Equal(t, true, report["engaged"])
+	assert.Equal(t, "local_rpc", report["source"])
+	assert.Len(t, report["positions"], 1)
+
+	out = callAs(t, svc, c, `{"jsonrpc":"2.0","id":3,"method":"panic"}`)
+	report = out["result"].(map[string]interface{})
+	assert.Equal(t, true, report["already_engaged"])
+	assert.Empty(t, report["positions"])
+
+	out = callAs(t, svc, c, `{"jsonrpc":"2.0","id":4,"method":"killSwitchStatus"}`)
+	assert.Equal(t, true, out["result"].(map[string]interface{})["engaged"])
+
+	out = callAs(t, svc, c, `{"jsonrpc":"2.0","id":5,"method":"rearm"}`)
+	assert.Equal(t, true, out["result"].(map[string]interface{})["rearmed"])
+	out = callAs(t, svc, c, `{"jsonrpc":"2.0","id":6,"method":"rearm"}`)
+	assert.Equal(t, false, out["result"].(map[string]interface{})["rearmed"])
+}
+
+func TestService_Roles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	svc := NewService(metrics.NewPositions(), nil, nil, nil)
+	svc.SetKillSwitch(&fakeKillSwitch{})
+	core, logs := observer.New(zap.DebugLevel)
+	svc.SetAudit(audit.NewLog(path), zap.New(core))
+	svc.AddClient("dashboard", "view-key", audit.RoleViewer)
+	svc.AddClient("desk", "trade-key", audit.RoleTrader)
+	svc.SetAPIKey("admin-key")
+
+	login := func(key string) *client {
+		c := svc.newClient()
+		out := callAs(t, svc, c, `{"jsonrpc":"2.0","id":1,"method":"authenticate","params":{"api_key":"`+key+`"}}`)
+		assert.Equal(t, true, out["result"], key)
+		return c
+	}
+	errorOf := func(out map[string]interface{}) string {
+		if e, ok := out["error"].(map[string]interface{}); ok {
+			return e["message"].(string)
+		}
+		return ""
+	}
+
+	viewer, trader, admin := login("view-key"), login("trade-key"), login("admin-key")
+	assert.Equal(t, "dashboard", viewer.principal.Name)
+	assert.Equal(t, "default", admin.principal.Name)
+
+	assert.Empty(t, errorOf(callAs(t, svc, viewer, `{"jsonrpc":"2.0","id":2,"method":"killSwitchStatus"}`)))
+	assert.Equal(t, "panic needs the trader role", errorOf(callAs(t, svc, viewer, `{"jsonrpc":"2.0","id":3,"method":"panic"}`)))
+	assert.Empty(t, errorOf(callAs(t, svc, trader, `{"jsonrpc":"2.0","id":4,"method":"panic"}`)))
+	assert.Equal(t, "rearm needs the admin role", errorOf(callAs(t, svc, trader, `{"jsonrpc":"2.0","id":5,"method":"rearm"}`)))
+	assert.Empty(t, errorOf(callAs(t, svc, admin, `{"jsonrpc":"2.0","id":6,"method":"rearm"}`)))
+	out := callAs(t, svc, svc.newClient(), `{"jsonrpc":"2.0","id":7,"method":"authenticate","params":{"api_key":"guess"}}`)
+	assert.Equal(t, "invalid api key", errorOf(out))
+
+	// Чтение в журнал не попадает; изменяющие методы и отказы записаны с именем клиента
+	n, err := audit.Verify(path)
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entries []audit.Entry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var e audit.Entry
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		entries = append(entries, e)
+	}
+	assert.Equal(t, "local_rpc", entries[0].Surface)
+	assert.Equal(t, "dashboard", entries[0].Principal)
+	assert.Equal(t, "viewer", entries[0].Role)
+	assert.Equal(t, "panic", entries[0].Command)
+	assert.False(t, entries[0].Allowed)
+	assert.True(t, entries[1].Allowed)
+	assert.Equal(t, "desk", entries[1].Principal)
+	assert.Equal(t, "engaged, positions: 1", entries[1].Result)
+	assert.False(t, entries[2].Allowed)
+	assert.Equal(t, "default", entries[3].Principal)
+	assert.Equal(t, "rearmed", entries[3].Result)
+	assert.Equal(t, "unauthenticated", entries[4].Principal)
+	assert.Equal(t, "authenticate", entries[4].Command)
+
+	// В лог попадает каждый вызов, включая чтение, с именем клиента
+	calls := logs.FilterMessage("Local RPC call").AllUntimed()
+	require.Len(t, calls, 3)
+	assert.Equal(t, "dashboard", calls[0].ContextMap()["client"])
+	assert.Equal(t, "killSwitchStatus", calls[0].ContextMap()["method"])
+	assert.Equal(t, zap.DebugLevel, calls[0].Level, "reads stay in debug")
+	assert.Equal(t, "desk", calls[1].ContextMap()["client"])
+	assert.Equal(t, zap.InfoLevel, calls[1].Level)
+	assert.Equal(t, "default", calls[2].ContextMap()["client"])
+	assert.Equal(t, 3, logs.FilterMessage("🔑 Local RPC client authenticated").Len())
+	refused := logs.FilterMessageSnippet("Local RPC call refused").AllUntimed()
+	require.Len(t, refused, 3)
+	assert.Equal(t, "dashboard", refused[0].ContextMap()["client"])
+	assert.Equal(t, "unauthenticated", refused[2].ContextMap()["client"])
+}
+
+func TestService_ClosesOnMalformedRequest(t *testing.T) {
+	svc := NewService(metrics.NewPositions(), nil, nil, nil)
+	svc.SetKillSwitch(&fakeKillSwitch{})
+
+	server, conn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		svc.serveConn(ctx, server, zap.NewNop())
+		close(done)
+	}()
+
+	// Запрос браузера: JSON-RPC в теле после строк HTTP-заголовков
+	go func() {
+		_, _ = conn.Write([]byte("POST / HTTP/1.1\r\nHost: 127.0.0.1:47822\r\n\r\n" +
+			`{"jsonrpc":"2.0","id":1,"method":"listPositions"}` + "\n"))
+	}()
+
+	lines := bufio.NewScanner(conn)
+	require.True(t, lines.Scan())
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines.Bytes(), &out))
+	assert.Equal(t, float64(codeParseError), out["error"].(map[string]interface{})["code"])
+	assert.False(t, lines.Scan(), "the body after the HTTP request line is never read")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the connection stayed open after a malformed request")
+	}
+}
@@ -0,0 +1,252 @@
+// internal/localrpc/stream.go
+package localrpc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
+	"github.com/rovshanmuradov/solana-bot/internal/killswitch"
+	"github.com/rovshanmuradov/solana-bot/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// subscriptionBuffer — сколько обновлений позиций ждет отправки медленному клиенту.
+const subscriptionBuffer = 64
+
+// codeUnauthorized — соединение еще не прошло authenticate или его роли не хватает для метода.
+const codeUnauthorized = -32001
+
+// anonymous — клиент сервиса без ключей: ему доступно только чтение.
+var anonymous = audit.Principal{Name: "local", Role: audit.RoleViewer}
+
+// apiKey — ключ одного именованного клиента.
+type apiKey struct {
+	principal audit.Principal
+	key       string
+}
+
+// notification — сообщение, которое сервер сам отправляет подписанному клиенту.
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type authenticateParams struct {
+	APIKey string `json:"api_key"`
+}
+
+type subscribeParams struct {
+	Mints []string `json:"mints"` // Пусто — все токены
+}
+
+// client — состояние одного соединения: авторизация и подписка на позиции.
+type client struct {
+	principal audit.Principal    // RoleNone — соединение еще не прошло authenticate
+	notify    func(notification) // nil — соединение не поддерживает подписки
+
+	mu     sync.Mutex
+	cancel func()
+}
+
+// stop отменяет подписку клиента.
+func (c *client) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// SetAPIKey добавляет клиента "default" с ролью admin и ключом local_rpc_api_key;
+// "" — ничего не меняет.
+func (s *Service) SetAPIKey(key string) {
+	if key != "" {
+		s.AddClient("default", key, audit.RoleAdmin)
+	}
+}
+
+// AddClient добавляет именованного клиента (local_rpc_clients). С первым ключом каждое
+// соединение должно вызвать authenticate до остальных методов и получает роль своего ключа.
+func (s *Service) AddClient(name, key string, role audit.Role) {
+	s.keys = append(s.keys, apiKey{principal: audit.Principal{Name: name, Role: role}, key: key})
+}
+
+// SetAudit записывает в журнал l вызовы panic и rearm, отказы в доступе и неверные ключи.
+// В logger попадает каждый вызов с именем клиента и ошибки записи журнала: kill switch
+// срабатывает и без журнала.
+func (s *Service) SetAudit(l *audit.Log, logger *zap.Logger) {
+	s.audit, s.logger = l, logger
+}
+
+// newClient возвращает состояние нового соединения: без ключей оно сразу может читать.
+func (s *Service) newClient() *client {
+	if len(s.keys) == 0 {
+		return &client{principal: anonymous}
+	}
+	return &client{}
+}
+
+// authenticate проверяет ключ соединения и назначает ему клиента этого ключа.
+func (s *Service) authenticate(c *client, params json.RawMessage) (interface{}, *rpcError) {
+	var p authenticateParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if len(s.keys) == 0 {
+		c.principal = anonymous
+		return true, nil
+	}
+	match := -1
+	for i, k := range s.keys {
+		// Сравниваются все ключи, чтобы время ответа не выдавало, какой из них совпал
+		if subtle.ConstantTimeCompare([]byte(p.APIKey), []byte(k.key)) == 1 {
+			match = i
+		}
+	}
+	if match < 0 {
+		s.record(c.principal, "authenticate", false, "invalid api key")
+		return nil, &rpcError{Code: codeUnauthorized, Message: "invalid api key"}
+	}
+	c.principal = s.keys[match].principal
+	s.logger.Info("🔑 Local RPC client authenticated",
+		zap.String("client", c.principal.Name), zap.String("role", c.principal.Role.String()))
+	return true, nil
+}
+
+// logCall записывает вызов метода с именем клиента: отказы в доступе — предупреждением,
+// panic и rearm — в info, чтение — в debug, чтобы опрос дашборда не засорял лог.
+func (s *Service) logCall(c *client, method string, rpcErr *rpcError) {
+	name := c.principal.Name
+	if name == "" {
+		name = "unauthenticated"
+	}
+	fields := []zap.Field{zap.String("client", name), zap.String("method", method)}
+	switch {
+	case rpcErr != nil && rpcErr.Code == codeUnauthorized:
+		s.logger.Warn("⚠️  Local RPC call refused", append(fields, zap.String("reason", rpcErr.Message))...)
+	case rpcErr != nil:
+		s.logger.Debug("Local RPC call failed", append(fields, zap.String("error", rpcErr.Message))...)
+	case method == "authenticate":
+		// Успешный вход уже записан authenticate
+	case methodRole(method) != audit.RoleViewer:
+		s.logger.Info("Local RPC call", fields...)
+	default:
+		s.logger.Debug("Local RPC call", fields...)
+	}
+}
+
+// methodRole возвращает роль, нужную для метода.
+func methodRole(method string) audit.Role {
+	switch method {
+	case "panic":
+		return audit.RoleTrader
+	case "rearm":
+		return audit.RoleAdmin
+	default:
+		return audit.RoleViewer
+	}
+}
+
+// forbidden отклоняет метод, для которого роли клиента не хватает, и записывает отказ.
+func (s *Service) forbidden(c *client, method string) *rpcError {
+	need := methodRole(method)
+	msg := fmt.Sprintf("%s needs the %s role", method, need)
+	if len(s.keys) == 0 {
+		// Без ключа любой локальный процесс мог бы продать все позиции
+		msg = method + " needs local_rpc_api_key or local_rpc_clients"
+	}
+	s.record(c.principal, method, false, msg)
+	return &rpcError{Code: codeUnauthorized, Message: msg}
+}
+
+// recordCall записывает в журнал аудита выполненный изменяющий метод и его итог.
+func (s *Service) recordCall(c *client, method string, result interface{}, rpcErr *rpcError) {
+	if methodRole(method) == audit.RoleViewer {
+		return
+	}
+	outcome := "ok"
+	switch r := result.(type) {
+	case killswitch.Report:
+		outcome = fmt.Sprintf("engaged, positions: %d", len(r.Positions))
+		if r.AlreadyEngaged {
+			outcome = "already engaged"
+		}
+	case RearmResult:
+		outcome = "rearmed"
+		if !r.Rearmed {
+			outcome = "not engaged"
+		}
+	}
+	if rpcErr != nil {
+		outcome = rpcErr.Message
+	}
+	s.record(c.principal, method, true, outcome)
+}
+
+func (s *Service) record(p audit.Principal, command string, allowed bool, result string) {
+	name := p.Name
+	if name == "" {
+		name = "unauthenticated"
+	}
+	err := s.audit.Record(audit.Entry{
+		Surface:   "local_rpc",
+		Principal: name,
+		Role:      p.Role.String(),
+		Command:   command,
+		Allowed:   allowed,
+		Result:    result,
+	})
+	if err != nil {
+		s.logger.Error("❌ Audit log write failed: " + err.Error())
+	}
+}
+
+// subscribe начинает отправлять клиенту уведомления positionUpdated с ценой и PnL
+// позиций по токенам mints; новая подписка заменяет прежнюю.
+func (s *Service) subscribe(c *client, params json.RawMessage) (interface{}, *rpcError) {
+	var p subscribeParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if c.notify == nil {
+		return nil, &rpcError{Code: codeInvalidRequest, Message: "subscriptions need a socket connection"}
+	}
+	mints := make(map[string]bool, len(p.Mints))
+	for _, m := range p.Mints {
+		mints[m] = true
+	}
+
+	updates, cancel := s.positions.Subscribe(subscriptionBuffer)
+	c.stop()
+	c.mu.Lock()
+	c.cancel = cancel
+	c.mu.Unlock()
+
+	go func() {
+		for state := range updates {
+			if len(mints) == 0 || mints[state.Mint] {
+				c.notify(notification{JSONRPC: "2.0", Method: "positionUpdated", Params: state})
+			}
+		}
+	}()
+
+	// Текущее состояние, чтобы клиенту не ждать следующего тика цены
+	snapshot := []metrics.PositionState{}
+	for _, state := range s.positions.Snapshot() {
+		if len(mints) == 0 || mints[state.Mint] {
+			snapshot = append(snapshot, state)
+		}
+	}
+	return snapshot, nil
+}
+
+// unsubscribe прекращает уведомления о позициях.
+func (s *Service) unsubscribe(c *client) (interface{}, *rpcError) {
+	c.stop()
+	return true, nil
+}
@@ -0,0 +1,129 @@
+// internal/logger/components.go
+package logger
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Components that JSON logs are split by: logs/<component>.jsonl.
+const (
+	ComponentTrade   = "trade"
+	ComponentRPC     = "rpc"
+	ComponentMonitor = "monitor"
+	ComponentBot     = "bot" // Everything that belongs to no other component
+)
+
+// components maps logger names (zap.Logger.Named) to components.
+var components = map[string]string{
+	"pumpfun":        ComponentTrade,
+	"pumpswap":       ComponentTrade,
+	"pool_manager":   ComponentTrade,
+	"jupiter":        ComponentTrade,
+	"smart_dex":      ComponentTrade,
+	"sim_dex":        ComponentTrade,
+	"sell":           ComponentTrade,
+	"sniper":         ComponentTrade,
+	"copytrade":      ComponentTrade,
+	"execution":      ComponentTrade,
+	"solbc-client":   ComponentRPC,
+	"rpc-pool":       ComponentRPC,
+	"balances":       ComponentRPC,
+	"monitor_worker": ComponentMonitor,
+	"session":        ComponentMonitor,
+	"price":          ComponentMonitor,
+	"ui":             ComponentMonitor,
+	"watchlist":      ComponentMonitor,
+	"portfolio":      ComponentMonitor,
+}
+
+// componentOf returns the component of a logger name. The innermost known name
+// wins, so "monitor_worker.sell" belongs to trade.
+func componentOf(loggerName string) string {
+	parts := strings.Split(loggerName, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if c, ok := components[parts[i]]; ok {
+			return c
+		}
+	}
+	return ComponentBot
+}
+
+// ComponentCore writes log entries as JSON Lines into one file per component.
+// The files rotate by size.
+type ComponentCore struct {
+	zapcore.LevelEnabler
+	cores map[string]zapcore.Core
+	files []*RotatingFile
+}
+
+// NewComponentCore opens the component files in dir. A file rotates once it reaches
+// maxBytes (0 = never) and maxFiles rotated copies are kept.
+func NewComponentCore(dir string, level zapcore.LevelEnabler, maxBytes int64, maxFiles int) (*ComponentCore, error) {
+	encoderConfig := zapcore.EncoderConfig{
+		TimeKey:        "ts",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		MessageKey:     "msg",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.MillisDurationEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	}
+
+	c := &ComponentCore{LevelEnabler: level, cores: make(map[string]zapcore.Core)}
+	for _, name := range []string{ComponentTrade, ComponentRPC, ComponentMonitor, ComponentBot} {
+		f, err := OpenRotatingFile(filepath.Join(dir, name+".jsonl"), maxBytes, maxFiles)
+		if err != nil {
+			_ = c.Close()
+			return nil, err
+		}
+		c.files = append(c.files, f)
+		c.cores[name] = zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), f, level)
+	}
+	return c, nil
+}
+
+// With returns a core that adds fields to every entry.
+func (c *ComponentCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &ComponentCore{LevelEnabler: c.LevelEnabler, cores: make(map[string]zapcore.Core, len(c.cores)), files: c.files}
+	for name, core := range c.cores {
+		clone.cores[name] = core.With(fields)
+	}
+	return clone
+}
+
+// Check adds the core to the entry if its level is enabled.
+func (c *ComponentCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write writes the entry to its component's file.
+func (c *ComponentCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.cores[componentOf(entry.LoggerName)].Write(entry, fields)
+}
+
+// Sync flushes all files to disk.
+func (c *ComponentCore) Sync() error {
+	var errs []error
+	for _, f := range c.files {
+		errs = append(errs, f.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the component files.
+func (c *ComponentCore) Close() error {
+	var errs []error
+	for _, f := range c.files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestComponentCore_RoutesByLoggerName(t *testing.T) {
+	dir := t.TempDir()
+	core, err := NewComponentCore(dir, zapcore.InfoLevel, 0, 0)
+	require.NoError(t, err)
+	log := zap.New(core)
+
+	log.Named("monitor_worker").Info("price tick", zap.Float64("price", 0.5))
+	log.Named("monitor_worker").Named("sell").Warn("sell failed")
+	log.Named("solbc-client").Debug("hidden")
+	log.Named("solbc-client").Error("rpc down")
+	log.Info("started")
+	require.NoError(t, core.Close())
+
+	read := func(component string) []map[string]interface{} {
+		data, err := os.ReadFile(filepath.Join(dir, component+".jsonl"))
+		require.NoError(t, err)
+		var entries []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line == "" {
+				continue
+			}
+			var e map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &e))
+			entries = append(entries, e)
+		}
+		return entries
+	}
+
+	monitor := read(ComponentMonitor)
+	require.Len(t, monitor, 1)
+	assert.Equal(t, "price tick", monitor[0]["msg"])
+	assert.Equal(t, 0.5, monitor[0]["price"])
+	assert.Equal(t, "monitor_worker", monitor[0]["logger"])
+
+	trade := read(ComponentTrade)
+	require.Len(t, trade, 1)
+	assert.Equal(t, "warn", trade[0]["level"])
+
+	assert.Len(t, read(ComponentRPC), 1)
+	assert.Len(t, read(ComponentBot), 1)
+}
+
+func TestRotatingFile_KeepsMaxFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.jsonl")
+	f, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	for name, want := range map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"} {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
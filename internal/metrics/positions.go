@@ -0,0 +1,256 @@
+// internal/metrics/positions.go
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// position — последние значения PnL одной открытой позиции.
+type position struct {
+	mint       string
+	wallet     string
+	price      float64
+	pnlPercent float64
+	pnlSol     float64
+	openedAt   time.Time
+	pending    bool
+	pendingNew bool // Запись создана SetPending и еще не обновлялась монитором
+}
+
+// PositionState — снимок открытой позиции для внешних потребителей.
+type PositionState struct {
+	Mint       string    `json:"mint"`
+	Wallet     string    `json:"wallet"`
+	Price      float64   `json:"price"` // Последняя цена токена в SOL
+	PnLPercent float64   `json:"pnl_percent"`
+	PnLSol     float64   `json:"pnl_sol"`
+	OpenedAt   time.Time `json:"opened_at"`
+	Pending    bool      `json:"pending,omitempty"` // Сделка в блоке, но еще не достигла нужного подтверждения
+	Closed     bool      `json:"closed,omitempty"`  // Только в подписке: позиция закрыта
+}
+
+// Positions хранит гауги открытых позиций и отдает их в текстовом формате Prometheus.
+// Все методы безопасны для nil, поэтому при выключенных метриках вызовы ничего не делают.
+type Positions struct {
+	mu        sync.Mutex
+	positions map[string]*position
+	subs      map[int]chan PositionState
+	nextSub   int
+	now       func() time.Time
+}
+
+// NewPositions создает пустой реестр позиций.
+func NewPositions() *Positions {
+	return &Positions{
+		positions: make(map[string]*position),
+		subs:      make(map[int]chan PositionState),
+		now:       time.Now,
+	}
+}
+
+// Update обновляет цену и PnL позиции; openedAt запоминается при первом обновлении.
+func (p *Positions) Update(mint, wallet string, price, pnlPercent, pnlSol float64, openedAt time.Time) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := mint + "|" + wallet
+	pos, ok := p.positions[key]
+	if !ok {
+		pos = &position{mint: mint, wallet: wallet, openedAt: openedAt}
+		p.positions[key] = pos
+	}
+	pos.price = price
+	pos.pnlPercent = pnlPercent
+	pos.pnlSol = pnlSol
+	pos.pendingNew = false
+	p.publish(pos.state())
+}
+
+// SetPending отмечает позицию, чья сделка попала в блок, но еще не достигла нужного
+// подтверждения. Для новой позиции (покупка) запись создается и удаляется при снятии
+// отметки, если монитор так и не обновил ее.
+func (p *Positions) SetPending(mint, wallet string, pending bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := mint + "|" + wallet
+	pos, ok := p.positions[key]
+	if !ok {
+		if !pending {
+			return
+		}
+		pos = &position{mint: mint, wallet: wallet, openedAt: p.now(), pendingNew: true}
+		p.positions[key] = pos
+	}
+	if !pending && pos.pendingNew {
+		delete(p.positions, key)
+		return
+	}
+	pos.pending = pending
+}
+
+// Remove удаляет позицию после продажи или выхода из мониторинга.
+func (p *Positions) Remove(mint, wallet string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := mint + "|" + wallet
+	if pos, ok := p.positions[key]; ok {
+		delete(p.positions, key)
+		state := pos.state()
+		state.Closed = true
+		p.publish(state)
+	}
+}
+
+// Subscribe подписывает на изменения позиций: каждое обновление цены и PnL, а также
+// закрытие (Closed = true). Медленный подписчик пропускает обновления, а не тормозит
+// мониторинг. cancel отписывает и закрывает канал.
+func (p *Positions) Subscribe(buffer int) (<-chan PositionState, func()) {
+	ch := make(chan PositionState, buffer)
+	if p == nil {
+		close(ch)
+		return ch, func() {}
+	}
+	p.mu.Lock()
+	id := p.nextSub
+	p.nextSub++
+	p.subs[id] = ch
+	p.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			p.mu.Lock()
+			delete(p.subs, id)
+			p.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish рассылает состояние подписчикам; вызывается под блокировкой.
+func (p *Positions) publish(state PositionState) {
+	for _, ch := range p.subs {
+		select {
+		case ch <- state:
+		default:
+		}
+	}
+}
+
+// Snapshot возвращает открытые позиции, отсортированные по mint и кошельку.
+func (p *Positions) Snapshot() []PositionState {
+	sorted := p.sorted()
+	states := make([]PositionState, len(sorted))
+	for i, pos := range sorted {
+		states[i] = pos.state()
+	}
+	return states
+}
+
+// state возвращает снимок позиции.
+func (pos *position) state() PositionState {
+	return PositionState{
+		Mint:       pos.mint,
+		Wallet:     pos.wallet,
+		Price:      pos.price,
+		PnLPercent: pos.pnlPercent,
+		PnLSol:     pos.pnlSol,
+		OpenedAt:   pos.openedAt,
+		Pending:    pos.pending,
+	}
+}
+
+// WriteTo записывает гауги в текстовом формате экспозиции Prometheus.
+func (p *Positions) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	snapshot := p.sorted()
+
+	now := time.Now()
+	if p != nil {
+		now = p.now()
+	}
+
+	writeHeader(&b, "solana_bot_open_positions", "Number of positions currently monitored.")
+	fmt.Fprintf(&b, "solana_bot_open_positions %d\n", len(snapshot))
+
+	gauges := []struct {
+		name, help string
+		value      func(pos position) float64
+	}{
+		{"solana_bot_position_pnl_percent", "Unrealized PnL of an open position in percent.",
+			func(pos position) float64 { return pos.pnlPercent }},
+		{"solana_bot_position_pnl_sol", "Unrealized PnL of an open position in SOL.",
+			func(pos position) float64 { return pos.pnlSol }},
+		{"solana_bot_position_age_seconds", "Seconds since the position was opened.",
+			func(pos position) float64 { return now.Sub(pos.openedAt).Seconds() }},
+		{"solana_bot_position_pending", "1 while the position's last trade waits for the required commitment.",
+			func(pos position) float64 {
+				if pos.pending {
+					return 1
+				}
+				return 0
+			}},
+	}
+	for _, g := range gauges {
+		writeHeader(&b, g.name, g.help)
+		for _, pos := range snapshot {
+			fmt.Fprintf(&b, "%s{mint=\"%s\",wallet=\"%s\"} %g\n",
+				g.name, escapeLabel(pos.mint), escapeLabel(pos.wallet), g.value(pos))
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP отдает гауги по запросу Prometheus.
+func (p *Positions) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = p.WriteTo(w)
+}
+
+// sorted копирует позиции в стабильном порядке.
+func (p *Positions) sorted() []position {
+	var snapshot []position
+	if p != nil {
+		p.mu.Lock()
+		for _, pos := range p.positions {
+			snapshot = append(snapshot, *pos)
+		}
+		p.mu.Unlock()
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].mint != snapshot[j].mint {
+			return snapshot[i].mint < snapshot[j].mint
+		}
+		return snapshot[i].wallet < snapshot[j].wallet
+	})
+	return snapshot
+}
+
+func writeHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// escapeLabel экранирует значение метки по правилам формата экспозиции.
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
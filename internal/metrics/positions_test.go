@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositions_WriteTo(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	p := NewPositions()
+	p.now = func() time.Time { return now }
+
+	p.Update("MintB", "main", 0.001, -5, -0.05, now.Add(-30*time.Second))
+	p.Update("MintA", `we"ird`, 0.001, 12.5, 0.125, now.Add(-90*time.Second))
+	// Повторное обновление не сдвигает время открытия
+	p.Update("MintA", `we"ird`, 0.001, 20, 0.2, now)
+
+	var b strings.Builder
+	_, err := p.WriteTo(&b)
+	assert.NoError(t, err)
+	out := b.String()
+
+	assert.Contains(t, out, "# TYPE solana_bot_position_pnl_percent gauge\n")
+	assert.Contains(t, out, "solana_bot_open_positions 2\n")
+	assert.Contains(t, out, `solana_bot_position_pnl_percent{mint="MintA",wallet="we\"ird"} 20`+"\n")
+	assert.Contains(t, out, `solana_bot_position_pnl_sol{mint="MintB",wallet="main"} -0.05`+"\n")
+	assert.Contains(t, out, `solana_bot_position_age_seconds{mint="MintA",wallet="we\"ird"} 90`+"\n")
+	assert.Less(t, strings.Index(out, `pnl_percent{mint="MintA"`), strings.Index(out, `pnl_percent{mint="MintB"`))
+}
+
+func TestPositions_RemoveAndNil(t *testing.T) {
+	p := NewPositions()
+	p.Update("MintA", "main", 0.001, 1, 0.01, time.Now())
+	p.Remove("MintA", "main")
+
+	var b strings.Builder
+	_, _ = p.WriteTo(&b)
+	assert.Contains(t, b.String(), "solana_bot_open_positions 0\n")
+	assert.NotContains(t, b.String(), `mint="MintA"`)
+
+	var nilPositions *Positions
+	nilPositions.Update("MintA", "main", 0.001, 1, 0.01, time.Now())
+	nilPositions.Remove("MintA", "main")
+	b.Reset()
+	_, err := nilPositions.WriteTo(&b)
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), "solana_bot_open_positions 0\n")
+}
+
+func TestPositions_Snapshot(t *testing.T) {
+	opened := time.Unix(1_700_000_000, 0)
+	p := NewPositions()
+	p.Update("MintB", "main", 0.001, -5, -0.05, opened)
+	p.Update("MintA", "main", 0.002, 12.5, 0.125, opened)
+
+	states := p.Snapshot()
+	assert.Len(t, states, 2)
+	assert.Equal(t, PositionState{Mint: "MintA", Wallet: "main", Price: 0.002, PnLPercent: 12.5, PnLSol: 0.125, OpenedAt: opened}, states[0])
+	assert.Equal(t, "MintB", states[1].Mint)
+
+	var nilPositions *Positions
+	assert.Empty(t, nilPositions.Snapshot())
+}
+
+func TestPositions_Subscribe(t *testing.T) {
+	p := NewPositions()
+	updates, cancel := p.Subscribe(1)
+
+	p.Update("MintA", "main", 0.001, 5, 0.05, time.Now())
+	// Полный буфер: обновление пропускается, монитор не ждет
+	p.Update("MintA", "main", 0.002, 10, 0.1, time.Now())
+	state := <-updates
+	assert.Equal(t, 0.001, state.Price)
+	assert.False(t, state.Closed)
+
+	p.Remove("MintA", "main")
+	state = <-updates
+	assert.True(t, state.Closed)
+	assert.Equal(t, 0.002, state.Price)
+
+	cancel()
+	cancel()
+	_, ok := <-updates
+	assert.False(t, ok)
+	p.Update("MintA", "main", 0.003, 1, 0.01, time.Now())
+
+	var nilPositions *Positions
+	closed, _ := nilPositions.Subscribe(1)
+	_, ok = <-closed
+	assert.False(t, ok)
+}
+
+func TestPositions_SetPending(t *testing.T) {
+	opened := time.Unix(1_700_000_000, 0)
+	p := NewPositions()
+	p.now = func() time.Time { return opened }
+
+	// Покупка в ожидании подтверждения: позиция видна сразу
+	p.SetPending("MintA", "main", true)
+	states := p.Snapshot()
+	assert.Len(t, states, 1)
+	assert.True(t, states[0].Pending)
+	assert.Equal(t, opened, states[0].OpenedAt)
+
+	var b strings.Builder
+	_, _ = p.WriteTo(&b)
+	assert.Contains(t, b.String(), `solana_bot_position_pending{mint="MintA",wallet="main"} 1`)
+
+	// Снятие отметки убирает запись, которую монитор так и не обновил
+	p.SetPending("MintA", "main", false)
+	assert.Empty(t, p.Snapshot())
+
+	// Продажа открытой позиции: запись остается после подтверждения
+	p.Update("MintB", "main", 0.001, 10, 0.1, opened)
+	p.SetPending("MintB", "main", true)
+	assert.True(t, p.Snapshot()[0].Pending)
+	p.SetPending("MintB", "main", false)
+	states = p.Snapshot()
+	assert.Len(t, states, 1)
+	assert.False(t, states[0].Pending)
+
+	var nilPositions *Positions
+	nilPositions.SetPending("MintA", "main", true)
+}
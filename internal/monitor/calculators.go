@@ -47,4 +47,8 @@ func init() {
 	RegisterCalculator("Smart DEX", func(d dex.DEX, logger *zap.Logger) PnLCalculator {
 		return &smartDEXCalculator{dex: d, logger: logger}
 	})
+
+	RegisterCalculator("Sim DEX", func(d dex.DEX, logger *zap.Logger) PnLCalculator {
+		return &simDEXCalculator{dex: d, logger: logger}
+	})
 }
@@ -0,0 +1,141 @@
+// internal/monitor/exits.go
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExitKind — правило выхода из позиции.
+type ExitKind string
+
+const (
+	ExitStopLoss     ExitKind = "stop_loss"
+	ExitTakeProfit   ExitKind = "take_profit"
+	ExitTrailingStop ExitKind = "trailing_stop"
+)
+
+// ExitRules — правила автоматического выхода из позиции в процентах: stop-loss при
+// убытке и take-profit при прибыли по PnL, trailing stop — при откате цены от
+// максимума, наблюдавшегося за сессию; 0 выключает правило. Во время прогрева после
+// входа stop-loss не срабатывает, остальные правила работают.
+// Пороги меняются и во время мониторинга, поэтому методы потокобезопасны.
+type ExitRules struct {
+	mu         sync.Mutex
+	stopLoss   float64          // Убыток, %, при котором позиция продается
+	takeProfit float64          // Прибыль, %, при которой позиция продается
+	trailing   float64          // Откат от максимума цены, %, при котором позиция продается
+	high       float64          // Максимальная цена за сессию
+	warmUntil  time.Time        // Конец прогрева, до него stop-loss не срабатывает
+	now        func() time.Time // Часы прогрева (nil — прогрева нет)
+}
+
+// NewExitRules создает правила с порогами stopLoss, takeProfit и trailing в процентах.
+func NewExitRules(stopLoss, takeProfit, trailing float64) *ExitRules {
+	return &ExitRules{stopLoss: stopLoss, takeProfit: takeProfit, trailing: trailing}
+}
+
+// WarmUp выключает stop-loss на d от текущего момента по часам now: цена сразу после
+// запуска токена скачет, и stop-loss выбивал бы позицию на первом же откате.
+func (r *ExitRules) WarmUp(d time.Duration, now func() time.Time) {
+	if d <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = now
+	r.warmUntil = now().Add(d)
+}
+
+// WarmUpLeft возвращает, сколько еще stop-loss выключен прогревом (0 — прогрев
+// закончился или stop-loss не задан).
+func (r *ExitRules) WarmUpLeft() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.warmUpLeftLocked()
+}
+
+func (r *ExitRules) warmUpLeftLocked() time.Duration {
+	if r.now == nil || r.stopLoss <= 0 {
+		return 0
+	}
+	return max(r.warmUntil.Sub(r.now()), 0)
+}
+
+// Set меняет порог правила kind; 0 выключает правило.
+func (r *ExitRules) Set(kind ExitKind, percent float64) error {
+	if percent < 0 {
+		return fmt.Errorf("%s must not be negative", kind)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch kind {
+	case ExitStopLoss, ExitTrailingStop:
+		if percent >= 100 {
+			return fmt.Errorf("%s must be below 100%%", kind)
+		}
+		if kind == ExitStopLoss {
+			r.stopLoss = percent
+		} else {
+			r.trailing = percent
+		}
+	case ExitTakeProfit:
+		r.takeProfit = percent
+	default:
+		return fmt.Errorf("unknown exit rule %q", kind)
+	}
+	return nil
+}
+
+// Check учитывает цену price в максимуме сессии и возвращает правило, сработавшее
+// при PnL pnlPercent и этой цене. Во время прогрева stop-loss пропускается.
+func (r *ExitRules) Check(pnlPercent, price float64) (ExitKind, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.high = max(r.high, price)
+	switch {
+	case r.stopLoss > 0 && pnlPercent <= -r.stopLoss && r.warmUpLeftLocked() == 0:
+		return ExitStopLoss, true
+	case r.takeProfit > 0 && pnlPercent >= r.takeProfit:
+		return ExitTakeProfit, true
+	case r.trailing > 0 && price > 0 && price <= r.trailStopLocked():
+		return ExitTrailingStop, true
+	}
+	return "", false
+}
+
+// TrailStop возвращает текущую цену срабатывания trailing stop (0 — выключен).
+func (r *ExitRules) TrailStop() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.trailStopLocked()
+}
+
+func (r *ExitRules) trailStopLocked() float64 {
+	if r.trailing <= 0 {
+		return 0
+	}
+	return r.high * (1 - r.trailing/100)
+}
+
+// String описывает включенные правила для экрана позиции, например "SL -20% · TP +50% · TS -10%".
+func (r *ExitRules) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var parts []string
+	if r.stopLoss > 0 {
+		parts = append(parts, fmt.Sprintf("SL -%g%%", r.stopLoss))
+	}
+	if r.takeProfit > 0 {
+		parts = append(parts, fmt.Sprintf("TP +%g%%", r.takeProfit))
+	}
+	if r.trailing > 0 {
+		parts = append(parts, fmt.Sprintf("TS -%g%%", r.trailing))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitRules_Check(t *testing.T) {
+	rules := NewExitRules(20, 50, 0)
+	assert.Equal(t, "SL -20% · TP +50%", rules.String())
+
+	_, hit := rules.Check(-19.9, 1)
+	assert.False(t, hit)
+	kind, hit := rules.Check(-20, 1)
+	assert.True(t, hit)
+	assert.Equal(t, ExitStopLoss, kind)
+	kind, hit = rules.Check(75, 1)
+	assert.True(t, hit)
+	assert.Equal(t, ExitTakeProfit, kind)
+
+	assert.NoError(t, rules.Set(ExitStopLoss, 0))
+	_, hit = rules.Check(-90, 1)
+	assert.False(t, hit, "disabled stop-loss must not fire")
+	assert.Equal(t, "TP +50%", rules.String())
+
+	assert.Error(t, rules.Set(ExitStopLoss, 100))
+	assert.Error(t, rules.Set(ExitTakeProfit, -1))
+	assert.Empty(t, NewExitRules(0, 0, 0).String())
+}
+
+func TestExitRules_TrailingStop(t *testing.T) {
+	rules := NewExitRules(0, 0, 10)
+	assert.Equal(t, "TS -10%", rules.String())
+
+	for _, price := range []float64{1, 1.5, 2, 1.9} {
+		_, hit := rules.Check(0, price)
+		assert.False(t, hit, "price %v", price)
+	}
+	assert.InDelta(t, 1.8, rules.TrailStop(), 1e-9, "stop follows the high")
+
+	kind, hit := rules.Check(0, 1.8)
+	assert.True(t, hit)
+	assert.Equal(t, ExitTrailingStop, kind)
+
+	// Выключенный trailing stop не срабатывает, но максимум продолжает отслеживаться
+	assert.NoError(t, rules.Set(ExitTrailingStop, 0))
+	_, hit = rules.Check(0, 0.5)
+	assert.False(t, hit)
+	assert.Zero(t, rules.TrailStop())
+	assert.NoError(t, rules.Set(ExitTrailingStop, 50))
+	assert.InDelta(t, 1.0, rules.TrailStop(), 1e-9)
+}
+
+func TestExitRules_WarmUp(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rules := NewExitRules(20, 50, 10)
+	rules.WarmUp(30*time.Second, func() time.Time { return now })
+	assert.Equal(t, 30*time.Second, rules.WarmUpLeft())
+
+	_, hit := rules.Check(-40, 1)
+	assert.False(t, hit, "stop-loss must not fire while warming up")
+	kind, hit := rules.Check(60, 1)
+	assert.True(t, hit, "take-profit still fires while warming up")
+	assert.Equal(t, ExitTakeProfit, kind)
+	kind, hit = rules.Check(-40, 0.8)
+	assert.True(t, hit, "trailing stop still fires while warming up")
+	assert.Equal(t, ExitTrailingStop, kind)
+
+	now = now.Add(30 * time.Second)
+	assert.Zero(t, rules.WarmUpLeft())
+	kind, hit = rules.Check(-40, 1)
+	assert.True(t, hit)
+	assert.Equal(t, ExitStopLoss, kind)
+
+	assert.NoError(t, rules.Set(ExitStopLoss, 0))
+	rules.WarmUp(time.Minute, func() time.Time { return now })
+	assert.Zero(t, rules.WarmUpLeft(), "nothing warms up without a stop-loss")
+}
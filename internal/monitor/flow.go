@@ -0,0 +1,74 @@
+// internal/monitor/flow.go
+package monitor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FlowTrade — сделка по токену позиции из потока транзакций.
+type FlowTrade struct {
+	At     time.Time
+	Wallet string
+	Buy    bool
+	Sol    float64
+	Ours   bool // Сделка одного из кошельков бота
+}
+
+// OrderFlow — поток сделок других кошельков по токену позиции за последнее окно
+// window: правила выхода реагируют не только на цену, но и на ордера. Продажа
+// другого кошелька от exitSol SOL за одну сделку — сигнал продать позицию.
+type OrderFlow struct {
+	window  time.Duration
+	exitSol float64 // 0 — крупные продажи не продают позицию
+
+	mu     sync.Mutex
+	trades []FlowTrade // Сделки других кошельков за окно, старые первыми
+}
+
+// NewOrderFlow создает поток с окном window и порогом крупной продажи exitSol SOL.
+func NewOrderFlow(window time.Duration, exitSol float64) *OrderFlow {
+	return &OrderFlow{window: window, exitSol: exitSol}
+}
+
+// Add учитывает сделку tr и возвращает true, если это продажа другого кошелька
+// не меньше exitSol. Собственные сделки бота в поток не входят.
+func (f *OrderFlow) Add(tr FlowTrade) bool {
+	if tr.Ours {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.trades = append(f.trades, tr)
+	f.pruneLocked(tr.At)
+	return !tr.Buy && f.exitSol > 0 && tr.Sol >= f.exitSol
+}
+
+func (f *OrderFlow) pruneLocked(now time.Time) {
+	i := 0
+	for i < len(f.trades) && now.Sub(f.trades[i].At) > f.window {
+		i++
+	}
+	f.trades = f.trades[i:]
+}
+
+// Summary описывает покупки и продажи других кошельков за окно до now для экрана
+// позиции, например "B 3.20 · S 1.10 SOL"; пусто — сделок не было.
+func (f *OrderFlow) Summary(now time.Time) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pruneLocked(now)
+	if len(f.trades) == 0 {
+		return ""
+	}
+	var buys, sells float64
+	for _, tr := range f.trades {
+		if tr.Buy {
+			buys += tr.Sol
+		} else {
+			sells += tr.Sol
+		}
+	}
+	return fmt.Sprintf("B %.2f · S %.2f SOL", buys, sells)
+}
@@ -0,0 +1,28 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderFlow(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	flow := NewOrderFlow(time.Minute, 5)
+	assert.Empty(t, flow.Summary(t0))
+
+	assert.False(t, flow.Add(FlowTrade{At: t0, Buy: true, Sol: 2}))
+	assert.False(t, flow.Add(FlowTrade{At: t0.Add(10 * time.Second), Sol: 1.5}))
+	assert.False(t, flow.Add(FlowTrade{At: t0.Add(20 * time.Second), Sol: 9, Ours: true}), "our own sell is not a signal")
+	assert.Equal(t, "B 2.00 · S 1.50 SOL", flow.Summary(t0.Add(30*time.Second)))
+
+	assert.True(t, flow.Add(FlowTrade{At: t0.Add(40 * time.Second), Sol: 5}), "a big sell by another wallet")
+	assert.False(t, flow.Add(FlowTrade{At: t0.Add(45 * time.Second), Buy: true, Sol: 50}), "big buys do not sell")
+
+	// Через минуту первые сделки выходят из окна
+	assert.Equal(t, "B 50.00 · S 5.00 SOL", flow.Summary(t0.Add(100*time.Second)))
+	assert.Empty(t, flow.Summary(t0.Add(3*time.Minute)))
+
+	assert.False(t, NewOrderFlow(time.Minute, 0).Add(FlowTrade{At: t0, Sol: 100}), "exit is off without a threshold")
+}
@@ -0,0 +1,149 @@
+// internal/monitor/indicators.go
+package monitor
+
+import "math"
+
+// Периоды индикаторов по умолчанию (в тиках цены).
+const (
+	DefaultFastEMAPeriod    = 9
+	DefaultSlowEMAPeriod    = 21
+	DefaultRSIPeriod        = 14
+	DefaultVolatilityPeriod = 14
+)
+
+// IndicatorSnapshot — значения индикаторов после очередного тика.
+type IndicatorSnapshot struct {
+	FastEMA    float64 // Быстрая EMA цены
+	SlowEMA    float64 // Медленная EMA цены
+	RSI        float64 // RSI по Уайлдеру, 0..100
+	Volatility float64 // Средний модуль изменения цены за тик, % (аналог ATR для тиков)
+	Stalled    bool    // Цена на последнем тике не выросла
+	Samples    int     // Сколько тиков учтено
+	Ready      bool    // Хватило тиков для прогрева RSI и медленной EMA
+}
+
+// Trend возвращает "up", "down" или "flat" по положению быстрой EMA относительно медленной, до прогрева — "".
+func (s IndicatorSnapshot) Trend() string {
+	switch {
+	case !s.Ready:
+		return ""
+	case s.FastEMA > s.SlowEMA:
+		return "up"
+	case s.FastEMA < s.SlowEMA:
+		return "down"
+	default:
+		return "flat"
+	}
+}
+
+// ema — экспоненциальная скользящая средняя, первое значение берется как есть.
+type ema struct {
+	alpha float64
+	value float64
+	set   bool
+}
+
+func newEMA(period int) ema {
+	return ema{alpha: 2 / float64(period+1)}
+}
+
+func (e *ema) add(v float64) float64 {
+	if !e.set {
+		e.value, e.set = v, true
+		return v
+	}
+	e.value += e.alpha * (v - e.value)
+	return e.value
+}
+
+// Indicators считает EMA, RSI и волатильность по потоку цен одной позиции.
+// Не потокобезопасен: обновляется из горутины, обрабатывающей цены.
+type Indicators struct {
+	fast, slow ema
+	rsiPeriod  int
+	volPeriod  int
+	warmup     int // Тиков до готовности
+	avgGain    float64
+	avgLoss    float64
+	avgMove    float64
+	last       float64
+	snapshot   IndicatorSnapshot
+}
+
+// NewIndicators создает движок индикаторов; нулевые периоды заменяются значениями по умолчанию.
+func NewIndicators(fastPeriod, slowPeriod, rsiPeriod, volPeriod int) *Indicators {
+	if fastPeriod <= 0 {
+		fastPeriod = DefaultFastEMAPeriod
+	}
+	if slowPeriod <= 0 {
+		slowPeriod = DefaultSlowEMAPeriod
+	}
+	if rsiPeriod <= 0 {
+		rsiPeriod = DefaultRSIPeriod
+	}
+	if volPeriod <= 0 {
+		volPeriod = DefaultVolatilityPeriod
+	}
+	return &Indicators{
+		fast:      newEMA(fastPeriod),
+		slow:      newEMA(slowPeriod),
+		rsiPeriod: rsiPeriod,
+		volPeriod: volPeriod,
+		warmup:    max(rsiPeriod+1, slowPeriod),
+	}
+}
+
+// Add учитывает новую цену и возвращает обновленные значения. Неположительные цены пропускаются.
+func (ind *Indicators) Add(price float64) IndicatorSnapshot {
+	if price <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return ind.snapshot
+	}
+
+	s := &ind.snapshot
+	s.FastEMA = ind.fast.add(price)
+	s.SlowEMA = ind.slow.add(price)
+
+	if s.Samples > 0 {
+		change := price - ind.last
+		gain, loss := math.Max(change, 0), math.Max(-change, 0)
+		move := math.Abs(change) / ind.last * 100
+
+		// Первые rsiPeriod изменений усредняются просто, дальше — сглаживание Уайлдера
+		n := s.Samples
+		ind.avgGain = smooth(ind.avgGain, gain, n, ind.rsiPeriod)
+		ind.avgLoss = smooth(ind.avgLoss, loss, n, ind.rsiPeriod)
+		ind.avgMove = smooth(ind.avgMove, move, n, ind.volPeriod)
+
+		s.RSI = rsi(ind.avgGain, ind.avgLoss)
+		s.Volatility = ind.avgMove
+		s.Stalled = change <= 0
+	}
+
+	ind.last = price
+	s.Samples++
+	s.Ready = s.Samples >= ind.warmup
+	return *s
+}
+
+// Snapshot возвращает значения после последнего тика.
+func (ind *Indicators) Snapshot() IndicatorSnapshot {
+	return ind.snapshot
+}
+
+// smooth обновляет среднее n-го значения: простое среднее до period, затем (prev*(period-1)+v)/period
+func smooth(prev, v float64, n, period int) float64 {
+	if n < period {
+		return prev + (v-prev)/float64(n)
+	}
+	return (prev*float64(period-1) + v) / float64(period)
+}
+
+func rsi(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+avgGain/avgLoss)
+}
@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndicators_RisingPrice(t *testing.T) {
+	ind := NewIndicators(3, 5, 4, 4)
+	var s IndicatorSnapshot
+	for i := 1; i <= 10; i++ {
+		s = ind.Add(float64(i))
+	}
+
+	assert.True(t, s.Ready)
+	assert.Equal(t, 10, s.Samples)
+	assert.Equal(t, 100.0, s.RSI)
+	assert.Equal(t, "up", s.Trend())
+	assert.False(t, s.Stalled)
+	assert.Greater(t, s.FastEMA, s.SlowEMA)
+	assert.Greater(t, s.Volatility, 0.0)
+}
+
+func TestIndicators_RSIWilder(t *testing.T) {
+	ind := NewIndicators(0, 0, 2, 2)
+	ind.Add(10)
+	ind.Add(12) // +2
+	s := ind.Add(11)
+	// avgGain = 1, avgLoss = 0.5 → RSI = 100 - 100/3
+	assert.InDelta(t, 66.67, s.RSI, 0.01)
+
+	s = ind.Add(11) // гладим: avgGain = 0.5, avgLoss = 0.25
+	assert.InDelta(t, 66.67, s.RSI, 0.01)
+	assert.True(t, s.Stalled)
+}
+
+func TestIndicators_WarmupAndInvalidPrices(t *testing.T) {
+	ind := NewIndicators(0, 0, 0, 0)
+	s := ind.Add(1)
+	assert.False(t, s.Ready)
+	assert.Equal(t, "", s.Trend())
+
+	s = ind.Add(0)
+	assert.Equal(t, 1, s.Samples)
+	s = ind.Add(-3)
+	assert.Equal(t, 1, s.Samples)
+
+	for i := 0; i < DefaultSlowEMAPeriod; i++ {
+		s = ind.Add(1)
+	}
+	assert.True(t, s.Ready)
+	assert.Equal(t, 50.0, s.RSI)
+	assert.Equal(t, "flat", s.Trend())
+	assert.Equal(t, s, ind.Snapshot())
+}
@@ -0,0 +1,104 @@
+// internal/monitor/plan.go
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// ExitPlan — многоступенчатый выход из позиции по плану из exit_plans: ступени
+// срабатывают по очереди, каждая продает свою долю исходной позиции. Trailing-ступень
+// отсчитывает откат от максимума цены с момента, когда сработала предыдущая ступень.
+type ExitPlan struct {
+	mu    sync.Mutex
+	tiers []task.ExitTier
+	next  int     // Индекс ступени, ожидающей срабатывания
+	sold  float64 // Продано ступенями, % исходной позиции
+	high  float64 // Максимум цены с момента, когда ступень next стала текущей
+}
+
+// NewExitPlan создает план из ступеней tiers; без ступеней возвращает nil.
+func NewExitPlan(tiers []task.ExitTier) *ExitPlan {
+	if len(tiers) == 0 {
+		return nil
+	}
+	return &ExitPlan{tiers: tiers}
+}
+
+// Check проверяет текущую ступень при PnL pnlPercent и цене price. Если ступень
+// сработала, возвращает ее и долю текущего баланса в процентах, которую нужно
+// продать, и переходит к следующей ступени.
+func (p *ExitPlan) Check(pnlPercent, price float64) (task.ExitTier, float64, bool) {
+	if p == nil {
+		return task.ExitTier{}, 0, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.next >= len(p.tiers) {
+		return task.ExitTier{}, 0, false
+	}
+	tier := p.tiers[p.next]
+	p.high = max(p.high, price)
+	switch {
+	case tier.AtPnL > 0 && pnlPercent >= tier.AtPnL:
+	case tier.Trailing > 0 && price > 0 && price <= p.high*(1-tier.Trailing/100):
+	default:
+		return task.ExitTier{}, 0, false
+	}
+
+	p.next++
+	p.high = 0
+	return tier, p.sellPercentLocked(tier), true
+}
+
+// sellPercentLocked переводит долю исходной позиции в процент текущего баланса
+// и учитывает ее как проданную; последняя ступень без доли продает остаток.
+func (p *ExitPlan) sellPercentLocked(tier task.ExitTier) float64 {
+	remaining := 100 - p.sold
+	if tier.SellPercent <= 0 || remaining <= 0 {
+		p.sold = 100
+		return 100
+	}
+	p.sold += tier.SellPercent
+	return min(tier.SellPercent/remaining*100, 100)
+}
+
+// Done сообщает, продана ли позиция планом целиком.
+func (p *ExitPlan) Done() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sold >= 100
+}
+
+// String описывает план для экрана позиции: сработавшие ступени отмечены ✓,
+// например "✓30% @ +50% → 30% @ +120% → rest @ TS -20%".
+func (p *ExitPlan) String() string {
+	if p == nil {
+		return ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	parts := make([]string, len(p.tiers))
+	for i, tier := range p.tiers {
+		parts[i] = tier.String()
+		if i < p.next {
+			parts[i] = "✓" + parts[i]
+		}
+	}
+	return strings.Join(parts, " → ")
+}
+
+// Describe описывает ступень для журнала сессии, например "2/3 30% @ +120%".
+func (p *ExitPlan) Describe(tier task.ExitTier) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return fmt.Sprintf("%d/%d %s", p.next, len(p.tiers), tier)
+}
@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitPlan_Tiers(t *testing.T) {
+	plan := NewExitPlan([]task.ExitTier{
+		{SellPercent: 30, AtPnL: 50},
+		{SellPercent: 30, AtPnL: 120},
+		{Trailing: 20},
+	})
+	assert.Equal(t, "30% @ +50% → 30% @ +120% → rest @ TS -20%", plan.String())
+
+	_, _, hit := plan.Check(49, 1.49)
+	assert.False(t, hit)
+
+	tier, percent, hit := plan.Check(60, 1.6)
+	assert.True(t, hit)
+	assert.Equal(t, 50.0, tier.AtPnL)
+	assert.InDelta(t, 30, percent, 1e-9)
+	_, _, hit = plan.Check(60, 1.6)
+	assert.False(t, hit, "the next tier waits for its own trigger")
+
+	_, percent, hit = plan.Check(130, 2.3)
+	assert.True(t, hit)
+	assert.InDelta(t, 30.0/70*100, percent, 1e-9, "share of the original position in the remaining balance")
+	assert.Equal(t, "✓30% @ +50% → ✓30% @ +120% → rest @ TS -20%", plan.String())
+
+	// Trailing отсчитывается от максимума после второй ступени, а не за всю сессию
+	for _, price := range []float64{2.3, 3, 2.5} {
+		_, _, hit = plan.Check(0, price)
+		assert.False(t, hit, "price %v", price)
+	}
+	assert.False(t, plan.Done())
+	_, percent, hit = plan.Check(0, 2.4)
+	assert.True(t, hit)
+	assert.Equal(t, 100.0, percent)
+	assert.True(t, plan.Done())
+
+	_, _, hit = plan.Check(500, 10)
+	assert.False(t, hit, "a finished plan never fires again")
+}
+
+func TestExitPlan_Nil(t *testing.T) {
+	var plan *ExitPlan
+	assert.Nil(t, NewExitPlan(nil))
+	_, _, hit := plan.Check(1000, 1)
+	assert.False(t, hit)
+	assert.False(t, plan.Done())
+	assert.Empty(t, plan.String())
+}
@@ -6,6 +6,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
 	"github.com/rovshanmuradov/solana-bot/internal/dex"
 	"go.uber.org/zap"
 )
@@ -25,12 +26,14 @@ type PriceUpdateCallback func(update PriceUpdate)
 type PriceMonitor struct {
 	dex           dex.DEX             // DEX interface for price retrieval
 	interval      time.Duration       // Interval between price checks
+	clock         clock.Clock         // Источник тиков мониторинга
 	initialPrice  float64             // Initial token price when monitoring started
 	tokenAmount   float64             // Amount of tokens purchased
 	tokenMint     string              // Token mint address
 	initialAmount float64             // Initial SOL amount spent
 	logger        *zap.Logger         // Logger
 	callback      PriceUpdateCallback // Callback for price updates
+	trigger       <-chan struct{}     // Изменения аккаунтов цены из Geyser (nil — только опрос)
 	ctx           context.Context     // Context for cancellation
 	cancel        context.CancelFunc  // Cancel function
 	stopped       atomic.Bool         // Флаг остановки, используем atomic для безопасного доступа из разных горутин
@@ -39,12 +42,13 @@ type PriceMonitor struct {
 // NewPriceMonitor создает новый монитор цены токена.
 func NewPriceMonitor(parentCtx context.Context, dex dex.DEX, tokenMint string, initialPrice float64,
 	tokenAmount float64, initialAmount float64,
-	interval time.Duration, logger *zap.Logger,
+	interval time.Duration, clk clock.Clock, logger *zap.Logger,
 	callback PriceUpdateCallback) *PriceMonitor {
 	ctx, cancel := context.WithCancel(parentCtx)
 	return &PriceMonitor{
 		dex:           dex,
 		interval:      interval,
+		clock:         clock.Or(clk),
 		initialPrice:  initialPrice,
 		tokenAmount:   tokenAmount,
 		tokenMint:     tokenMint,
@@ -59,7 +63,7 @@ func NewPriceMonitor(parentCtx context.Context, dex dex.DEX, tokenMint string, i
 // Start запускает мониторинг в собственной горутине и корректно выходит при Stop.
 func (pm *PriceMonitor) Start() {
 	pm.logger.Info("PriceMonitor: start", zap.String("token", pm.tokenMint))
-	ticker := time.NewTicker(pm.interval)
+	ticker := pm.clock.NewTicker(pm.interval)
 	defer ticker.Stop()
 
 	// первая итерация сразу
@@ -70,13 +74,19 @@ func (pm *PriceMonitor) Start() {
 		case <-pm.ctx.Done():
 			pm.logger.Info("PriceMonitor: context done, exiting loop")
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			// Проверяем флаг остановки перед обновлением цены
 			if pm.stopped.Load() {
 				pm.logger.Debug("PriceMonitor: stopped, skipping price update")
 				continue
 			}
 			pm.updatePrice()
+		case <-pm.trigger:
+			// Аккаунт цены изменился: кеш адаптера уже устарел
+			if src, ok := pm.dex.(dex.PriceAccountSource); ok {
+				src.InvalidatePrice()
+			}
+			pm.updatePrice()
 		}
 	}
 }
@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stepDEX отдает цены по порядку, по одной на запрос
+type stepDEX struct {
+	prices []float64
+	calls  int
+}
+
+func (d *stepDEX) GetName() string                                         { return "step" }
+func (d *stepDEX) Execute(context.Context, *task.Task) error               { return nil }
+func (d *stepDEX) GetTokenBalance(context.Context, string) (uint64, error) { return 0, nil }
+func (d *stepDEX) SellPercentTokens(context.Context, string, float64, float64, string, uint32) error {
+	return nil
+}
+func (d *stepDEX) SellTokenAmount(context.Context, string, uint64, float64, string, uint32) error {
+	return nil
+}
+func (d *stepDEX) CalculatePnL(context.Context, float64, float64) (*model.PnLResult, error) {
+	return &model.PnLResult{}, nil
+}
+func (d *stepDEX) GetTokenMetadata(context.Context, string) (*model.TokenMetadata, error) {
+	return nil, nil
+}
+
+func (d *stepDEX) GetTokenPrice(context.Context, string) (float64, error) {
+	price := d.prices[min(d.calls, len(d.prices)-1)]
+	d.calls++
+	return price, nil
+}
+
+func TestPriceMonitor_TicksOnClock(t *testing.T) {
+	clk := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	updates := make(chan PriceUpdate, 4)
+	pm := NewPriceMonitor(context.Background(), &stepDEX{prices: []float64{1, 1.5, 0.5}}, "MintA",
+		1, 100, 1, time.Second, clk, zap.NewNop(), func(u PriceUpdate) { updates <- u })
+
+	done := make(chan struct{})
+	go func() {
+		pm.Start()
+		close(done)
+	}()
+
+	// Первая цена запрашивается сразу, следующие — только по тику часов
+	assert.Equal(t, 1.0, (<-updates).Current)
+	clk.BlockUntil(1)
+	assert.Empty(t, updates)
+
+	clk.Advance(time.Second)
+	u := <-updates
+	assert.Equal(t, 1.5, u.Current)
+	assert.InDelta(t, 50, u.Percent, 1e-9)
+
+	clk.Advance(time.Second)
+	assert.InDelta(t, -50, (<-updates).Percent, 1e-9)
+
+	pm.Stop()
+	<-done
+}
+
+// streamDEX — stepDEX с аккаунтами цены
+type streamDEX struct {
+	stepDEX
+	invalidated int
+}
+
+func (d *streamDEX) PriceAccounts(context.Context, string) ([]solana.PublicKey, error) {
+	return nil, nil
+}
+func (d *streamDEX) InvalidatePrice() { d.invalidated++ }
+
+func TestPriceMonitor_UpdatesOnTrigger(t *testing.T) {
+	clk := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	updates := make(chan PriceUpdate, 4)
+	d := &streamDEX{stepDEX: stepDEX{prices: []float64{1, 2}}}
+	pm := NewPriceMonitor(context.Background(), d, "MintA",
+		1, 100, 1, time.Minute, clk, zap.NewNop(), func(u PriceUpdate) { updates <- u })
+	trigger := make(chan struct{}, 1)
+	pm.trigger = trigger
+
+	done := make(chan struct{})
+	go func() {
+		pm.Start()
+		close(done)
+	}()
+	assert.Equal(t, 1.0, (<-updates).Current)
+
+	// Изменение аккаунта обновляет цену без тика, минуя кеш адаптера
+	trigger <- struct{}{}
+	assert.Equal(t, 2.0, (<-updates).Current)
+	assert.Equal(t, 1, d.invalidated)
+
+	pm.Stop()
+	<-done
+}
@@ -0,0 +1,105 @@
+// internal/monitor/rug.go
+package monitor
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RugSample — состояние токена на одном опросе сторожа. Значения, которые не
+// удалось получить (нулевая цена, флаги *Known = false), не сравниваются.
+type RugSample struct {
+	Price           float64 // Цена токена в SOL
+	Venue           string  // Площадка ликвидности; после миграции ликвидность не сравнивается
+	LiquiditySol    float64 // SOL в пуле или на bonding curve
+	LPSupply        uint64  // Эмиссия LP-токенов пула (0 — у площадки нет LP)
+	LiquidityKnown  bool    // Ликвидность удалось прочитать
+	MintAuthority   string  // Пусто — authority отозвана
+	FreezeAuthority string
+	AuthorityKnown  bool // Mint удалось прочитать
+}
+
+// RugSentinel замечает признаки rug-pull между двумя соседними опросами: вывод
+// ликвидности из пула, смену authority mint и обвал цены больше порога. Сравнение
+// идет с предыдущим опросом, а не с ценой входа: медленное падение — забота stop-loss.
+type RugSentinel struct {
+	mu            sync.Mutex
+	priceDrop     float64 // Падение цены за опрос, %, считающееся обвалом (0 — не проверяется)
+	liquidityDrop float64 // Падение ликвидности или LP за опрос, %, считающееся выводом (0 — не проверяется)
+	prev          *RugSample
+}
+
+// NewRugSentinel создает сторожа с порогами priceDrop и liquidityDrop в процентах.
+func NewRugSentinel(priceDrop, liquidityDrop float64) *RugSentinel {
+	return &RugSentinel{priceDrop: priceDrop, liquidityDrop: liquidityDrop}
+}
+
+// Check сравнивает sample с предыдущим опросом и возвращает описания сработавших
+// признаков rug-pull. Первый опрос только запоминается.
+func (s *RugSentinel) Check(sample RugSample) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev := s.prev
+	s.prev = mergeSample(prev, sample)
+	if prev == nil {
+		return nil
+	}
+
+	var reasons []string
+	if s.liquidityDrop > 0 && prev.LiquidityKnown && sample.LiquidityKnown && prev.Venue == sample.Venue {
+		if drop := dropPercent(float64(prev.LPSupply), float64(sample.LPSupply)); drop >= s.liquidityDrop {
+			reasons = append(reasons, fmt.Sprintf("LP supply fell %.1f%% in one poll: liquidity removed", drop))
+		} else if drop := dropPercent(prev.LiquiditySol, sample.LiquiditySol); drop >= s.liquidityDrop {
+			reasons = append(reasons, fmt.Sprintf("liquidity fell %.1f%% in one poll (%.3f → %.3f SOL)",
+				drop, prev.LiquiditySol, sample.LiquiditySol))
+		}
+	}
+	if prev.AuthorityKnown && sample.AuthorityKnown {
+		if sample.MintAuthority != prev.MintAuthority {
+			reasons = append(reasons, "mint authority changed to "+authorityOrNone(sample.MintAuthority))
+		}
+		if sample.FreezeAuthority != prev.FreezeAuthority && sample.FreezeAuthority != "" {
+			reasons = append(reasons, "freeze authority changed to "+sample.FreezeAuthority)
+		}
+	}
+	if drop := dropPercent(prev.Price, sample.Price); s.priceDrop > 0 && sample.Price > 0 && drop >= s.priceDrop {
+		reasons = append(reasons, fmt.Sprintf("price collapsed %.1f%% in one poll (%.10f → %.10f SOL)",
+			drop, prev.Price, sample.Price))
+	}
+	return reasons
+}
+
+// mergeSample берет из sample полученные значения, а недоступные оставляет от prev,
+// чтобы сбой одного запроса не сбивал сравнение на следующем опросе.
+func mergeSample(prev *RugSample, sample RugSample) *RugSample {
+	if prev == nil {
+		return &sample
+	}
+	next := *prev
+	if sample.Price > 0 {
+		next.Price = sample.Price
+	}
+	if sample.LiquidityKnown {
+		next.Venue, next.LiquiditySol, next.LPSupply, next.LiquidityKnown = sample.Venue, sample.LiquiditySol, sample.LPSupply, true
+	}
+	if sample.AuthorityKnown {
+		next.MintAuthority, next.FreezeAuthority, next.AuthorityKnown = sample.MintAuthority, sample.FreezeAuthority, true
+	}
+	return &next
+}
+
+// dropPercent возвращает падение от before до after в процентах (0 — не упало).
+func dropPercent(before, after float64) float64 {
+	if before <= 0 || after >= before {
+		return 0
+	}
+	return (before - after) / before * 100
+}
+
+func authorityOrNone(key string) string {
+	if key == "" {
+		return "none"
+	}
+	return key
+}
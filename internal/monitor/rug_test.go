@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pool(price, sol float64, lp uint64) RugSample {
+	return RugSample{Price: price, Venue: "pump.swap", LiquiditySol: sol, LPSupply: lp, LiquidityKnown: true, AuthorityKnown: true}
+}
+
+func TestRugSentinel_Check(t *testing.T) {
+	s := NewRugSentinel(50, 50)
+	assert.Empty(t, s.Check(pool(1, 100, 1000)), "the first poll is the baseline")
+	assert.Empty(t, s.Check(pool(0.7, 80, 1000)), "a 30% dip is not a rug")
+
+	reasons := s.Check(pool(0.7, 80, 100))
+	require.Len(t, reasons, 1)
+	assert.Contains(t, reasons[0], "LP supply fell 90.0%")
+
+	s = NewRugSentinel(50, 50)
+	s.Check(pool(1, 100, 1000))
+	reasons = s.Check(pool(0.4, 100, 1000))
+	require.Len(t, reasons, 1)
+	assert.Contains(t, reasons[0], "price collapsed 60.0%")
+
+	s = NewRugSentinel(50, 50)
+	s.Check(pool(1, 100, 1000))
+	changed := pool(1, 100, 1000)
+	changed.MintAuthority = "Attacker1111111111111111111111111111111111"
+	reasons = s.Check(changed)
+	require.Len(t, reasons, 1)
+	assert.Contains(t, reasons[0], "mint authority changed")
+}
+
+func TestRugSentinel_SkipsUnknownValues(t *testing.T) {
+	s := NewRugSentinel(50, 50)
+	s.Check(pool(1, 100, 1000))
+
+	assert.Empty(t, s.Check(RugSample{}), "failed reads are not a collapse")
+	reasons := s.Check(pool(0.3, 100, 1000))
+	require.Len(t, reasons, 1, "the last known price is kept across a failed poll")
+	assert.Contains(t, reasons[0], "price collapsed 70.0%")
+
+	s = NewRugSentinel(50, 50)
+	s.Check(RugSample{Venue: "pump.fun", LiquiditySol: 85, LiquidityKnown: true})
+	assert.Empty(t, s.Check(RugSample{Venue: "pump.swap", LiquiditySol: 20, LPSupply: 1000, LiquidityKnown: true}),
+		"migration to another venue is not liquidity removal")
+
+	off := NewRugSentinel(0, 0)
+	off.Check(pool(1, 100, 1000))
+	assert.Empty(t, off.Check(pool(0.01, 1, 1)), "zero thresholds turn the checks off")
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/rovshanmuradov/solana-bot/internal/clock"
 	"github.com/rovshanmuradov/solana-bot/internal/dex"
 	"github.com/rovshanmuradov/solana-bot/internal/task"
 	"go.uber.org/zap"
@@ -16,12 +17,14 @@ import (
 
 // SessionConfig contains configuration for a monitoring session
 type SessionConfig struct {
-	Task            *task.Task    // ссылка на исходную задачу
-	TokenBalance    uint64        // Raw token balance in smallest units
-	InitialPrice    float64       // Initial token price
-	DEX             dex.DEX       // DEX adapter
-	Logger          *zap.Logger   // Logger
-	MonitorInterval time.Duration // Интервал обновления цены
+	Task            *task.Task      // ссылка на исходную задачу
+	TokenBalance    uint64          // Raw token balance in smallest units
+	InitialPrice    float64         // Initial token price
+	DEX             dex.DEX         // DEX adapter
+	Logger          *zap.Logger     // Logger
+	MonitorInterval time.Duration   // Интервал обновления цены
+	Clock           clock.Clock     // Часы тикера цены (nil — системные)
+	PriceTrigger    <-chan struct{} // Изменения аккаунтов цены из Geyser (nil — только по тикеру)
 }
 
 // MonitoringSession представляет сессию мониторинга токенов для операций на DEX.
@@ -100,9 +103,11 @@ func (ms *MonitoringSession) Start() error {
 		initialTokens,
 		t.AmountSol,
 		ms.config.MonitorInterval,
+		ms.config.Clock,
 		ms.logger.Named("price"),
 		ms.onPriceUpdate,
 	)
+	ms.priceMonitor.trigger = ms.config.PriceTrigger
 
 	// Start the price monitor in a goroutine
 	ms.wg.Add(1)
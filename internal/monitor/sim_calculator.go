@@ -0,0 +1,26 @@
+// internal/monitor/sim_calculator.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"github.com/rovshanmuradov/solana-bot/internal/dex"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/model"
+	"go.uber.org/zap"
+)
+
+// simDEXCalculator реализует расчет PnL для симулируемой площадки
+type simDEXCalculator struct {
+	dex    dex.DEX
+	logger *zap.Logger
+}
+
+// CalculatePnL делегирует расчет PnL к Sim DEX адаптеру
+func (c *simDEXCalculator) CalculatePnL(ctx context.Context, tokenAmount float64, initialInvestment float64) (*model.PnLResult, error) {
+	pnl, err := c.dex.CalculatePnL(ctx, tokenAmount, initialInvestment)
+	if err != nil {
+		c.logger.Error("sim_dex.CalculatePnL error", zap.Error(err))
+		return nil, fmt.Errorf("failed to calculate PnL: %w", err)
+	}
+	return pnl, nil
+}
@@ -0,0 +1,71 @@
+// internal/notify/alert.go
+package notify
+
+import (
+	"fmt"
+	"time"
+)
+
+// Severity определяет важность уведомления.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+// String возвращает текстовое представление уровня важности.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
+// AlertType классифицирует уведомления для дедупликации и агрегации.
+type AlertType string
+
+const (
+	AlertTradeExecuted    AlertType = "trade_executed"
+	AlertTradeFailed      AlertType = "trade_failed"
+	AlertSellCompleted    AlertType = "sell_completed"
+	AlertSellFailed       AlertType = "sell_failed"
+	AlertLatencyBudget    AlertType = "latency_budget"
+	AlertPositionMerged   AlertType = "position_merged"
+	AlertIndicator        AlertType = "indicator"
+	AlertTradeRecovered   AlertType = "trade_recovered"
+	AlertStaleDropped     AlertType = "stale_dropped"
+	AlertMintRisk         AlertType = "mint_risk"
+	AlertRugDetected      AlertType = "rug_detected"
+	AlertApprovalRequired AlertType = "approval_required"
+	AlertTokenMigrated    AlertType = "token_migrated"
+	AlertRunSummary       AlertType = "run_summary"
+	AlertKillSwitch       AlertType = "kill_switch"
+	AlertLossLimit        AlertType = "loss_limit"
+)
+
+// Alert — одно уведомление, отправляемое во внешние каналы (webhook, Telegram и т.д.).
+type Alert struct {
+	Type     AlertType // Тип уведомления
+	Key      string    // Ключ дедупликации внутри типа (обычно mint токена)
+	Severity Severity  // Важность
+	Message  string    // Человекочитаемый текст
+	Time     time.Time // Время возникновения
+}
+
+// Text возвращает строку для отправки в канал.
+func (a Alert) Text() string {
+	prefix := "ℹ️"
+	switch a.Severity {
+	case SeverityWarning:
+		prefix = "⚠️"
+	case SeverityCritical:
+		prefix = "🚨"
+	}
+	return fmt.Sprintf("%s %s", prefix, a.Message)
+}
@@ -0,0 +1,103 @@
+// internal/notify/email.go
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// EmailSink отправляет уведомления письмом через SMTP-сервер.
+type EmailSink struct {
+	addr     string // host:port
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewEmailSink создает канал доставки через SMTP-сервер addr. Если задан username,
+// сервер авторизует отправителя (PLAIN, только поверх TLS или на localhost).
+func NewEmailSink(addr, username, password, from string, to []string) *EmailSink {
+	return &EmailSink{addr: addr, username: username, password: password, from: from, to: to}
+}
+
+// Name возвращает имя канала.
+func (e *EmailSink) Name() string {
+	return "email"
+}
+
+// Send отправляет уведомление письмом.
+func (e *EmailSink) Send(ctx context.Context, alert Alert) error {
+	host, _, err := net.SplitHostPort(e.addr)
+	if err != nil {
+		return fmt.Errorf("invalid smtp address: %w", err)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", e.addr)
+	if err != nil {
+		return fmt.Errorf("connect smtp: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if e.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.username, e.password, host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := c.Mail(e.from); err != nil {
+		return fmt.Errorf("smtp sender: %w", err)
+	}
+	for _, rcpt := range e.to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp recipient %s: %w", rcpt, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write([]byte(e.message(alert))); err != nil {
+		return fmt.Errorf("write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return c.Quit()
+}
+
+// message собирает письмо: тема — тип и важность уведомления, тело — его текст.
+func (e *EmailSink) message(alert Alert) string {
+	at := alert.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&b, "Subject: [solana-bot] %s %s\r\n", alert.Severity, alert.Type)
+	fmt.Fprintf(&b, "Date: %s\r\n", at.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(alert.Text(), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,67 @@
+// internal/notify/filter.go
+package notify
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Filter ограничивает, какие уведомления доставляются в канал.
+type Filter struct {
+	Types       []AlertType // Только эти типы (пусто — все)
+	MinSeverity Severity    // Не ниже этой важности
+}
+
+// Accepts сообщает, проходит ли уведомление фильтр.
+func (f Filter) Accepts(alert Alert) bool {
+	if alert.Severity < f.MinSeverity {
+		return false
+	}
+	return len(f.Types) == 0 || slices.Contains(f.Types, alert.Type)
+}
+
+// ParseSeverity разбирает уровень важности из конфигурации: info, warning или critical.
+func ParseSeverity(s string) (Severity, error) {
+	switch s {
+	case "", "info":
+		return SeverityInfo, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return SeverityInfo, fmt.Errorf("unknown severity %q", s)
+	}
+}
+
+// filteredSink — канал со своим именем и фильтром уведомлений.
+type filteredSink struct {
+	Sink
+	name   string
+	filter Filter
+}
+
+// Filtered возвращает канал sink под именем name, в который уходят только
+// уведомления, прошедшие filter. Имя задает и файл буфера канала, поэтому у
+// нескольких каналов одного вида имена должны различаться.
+func Filtered(name string, sink Sink, filter Filter) Sink {
+	return &filteredSink{Sink: sink, name: name, filter: filter}
+}
+
+// Name возвращает имя канала.
+func (f *filteredSink) Name() string {
+	return f.name
+}
+
+// Accepts сообщает, нужно ли доставлять уведомление в канал.
+func (f *filteredSink) Accepts(alert Alert) bool {
+	return f.filter.Accepts(alert)
+}
+
+// accepts сообщает, нужно ли доставлять уведомление в канал sink.
+func accepts(sink Sink, alert Alert) bool {
+	if f, ok := sink.(interface{ Accepts(Alert) bool }); ok {
+		return f.Accepts(alert)
+	}
+	return true
+}
@@ -0,0 +1,347 @@
+// internal/notify/notifier.go
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	maxPending         = 256
+	maxKeysInSummary   = 5
+	defaultDedupe      = time.Minute
+	defaultAggregate   = time.Minute
+	defaultRatePerMin  = 20
+	defaultMaxAge      = 30 * time.Minute
+	defaultRetryDelay  = time.Second
+	maxRetryDelay      = time.Minute
+	defaultCloseWindow = 5 * time.Second
+	sendTimeout        = 10 * time.Second
+)
+
+// DefaultBufferDir — каталог буфера неотправленных уведомлений по умолчанию.
+const DefaultBufferDir = "logs/alerts"
+
+// Sink — канал доставки уведомлений (Discord/Slack webhook, Telegram и т.д.).
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, alert Alert) error
+}
+
+// Options задает окна дедупликации/агрегации и лимит отправки на канал.
+type Options struct {
+	// DedupeWindow — одинаковые (Type, Key) внутри окна отбрасываются.
+	DedupeWindow time.Duration
+	// AggregateWindow — первое уведомление типа уходит сразу, остальные
+	// внутри окна сворачиваются в одну сводку.
+	AggregateWindow time.Duration
+	// RatePerMinute — максимум сообщений в минуту для каждого канала.
+	RatePerMinute int
+	// MaxAge — неотправленные уведомления старше этого срока отбрасываются со сводкой.
+	MaxAge time.Duration
+	// RetryDelay — пауза перед первой повторной отправкой в недоступный канал;
+	// удваивается с каждой неудачей до maxRetryDelay.
+	RetryDelay time.Duration
+	// BufferDir — каталог, где очередь канала переживает перезапуск (пусто — только память).
+	BufferDir string
+}
+
+// aggregateBucket накапливает уведомления одного типа внутри окна агрегации.
+type aggregateBucket struct {
+	count    int
+	severity Severity
+	keys     map[string]struct{}
+	timer    *time.Timer
+}
+
+// Notifier дедуплицирует, агрегирует и рассылает уведомления по каналам.
+// Все методы безопасны для nil-получателя, поэтому уведомления можно
+// отключить, просто не создавая Notifier.
+type Notifier struct {
+	logger *zap.Logger
+	opts   Options
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+	buckets  map[AlertType]*aggregateBucket
+	closed   bool
+
+	sinks []*sinkQueue
+	ctx   context.Context
+	stop  context.CancelFunc
+	wg    sync.WaitGroup
+}
+
+// New создает Notifier и запускает по одной горутине доставки на каждый канал.
+func New(logger *zap.Logger, opts Options, sinks ...Sink) *Notifier {
+	if opts.DedupeWindow <= 0 {
+		opts.DedupeWindow = defaultDedupe
+	}
+	if opts.AggregateWindow <= 0 {
+		opts.AggregateWindow = defaultAggregate
+	}
+	if opts.RatePerMinute <= 0 {
+		opts.RatePerMinute = defaultRatePerMin
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = defaultMaxAge
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultRetryDelay
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &Notifier{
+		logger:   logger.Named("notify"),
+		opts:     opts,
+		lastSent: make(map[string]time.Time),
+		buckets:  make(map[AlertType]*aggregateBucket),
+		ctx:      ctx,
+		stop:     cancel,
+	}
+
+	for _, s := range sinks {
+		limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(opts.RatePerMinute)), opts.RatePerMinute)
+		sq := newSinkQueue(s, limiter, opts.BufferDir)
+		if restored, err := sq.load(); err != nil {
+			n.logger.Warn("Failed to restore buffered alerts",
+				zap.String("sink", s.Name()),
+				zap.Error(err))
+		} else if restored > 0 {
+			n.logger.Info("Restored buffered alerts",
+				zap.String("sink", s.Name()),
+				zap.Int("count", restored))
+		}
+		n.sinks = append(n.sinks, sq)
+		n.wg.Add(1)
+		go n.deliver(sq)
+	}
+
+	return n
+}
+
+// Notify принимает уведомление, применяя дедупликацию и агрегацию.
+func (n *Notifier) Notify(alert Alert) {
+	if n == nil {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return
+	}
+
+	// Дедупликация по (Type, Key)
+	dedupeKey := string(alert.Type) + "|" + alert.Key
+	if last, ok := n.lastSent[dedupeKey]; ok && alert.Time.Sub(last) < n.opts.DedupeWindow {
+		n.logger.Debug("Alert deduplicated",
+			zap.String("type", string(alert.Type)),
+			zap.String("key", alert.Key))
+		return
+	}
+	n.lastSent[dedupeKey] = alert.Time
+
+	// Агрегация: окно уже открыто — копим в сводку
+	if b, ok := n.buckets[alert.Type]; ok {
+		b.count++
+		if alert.Severity > b.severity {
+			b.severity = alert.Severity
+		}
+		if alert.Key != "" {
+			b.keys[alert.Key] = struct{}{}
+		}
+		return
+	}
+
+	alertType := alert.Type
+	n.buckets[alertType] = &aggregateBucket{
+		severity: alert.Severity,
+		keys:     make(map[string]struct{}),
+		timer:    time.AfterFunc(n.opts.AggregateWindow, func() { n.flush(alertType) }),
+	}
+	n.dispatchLocked(alert)
+}
+
+// flush отправляет сводку по типу уведомления и закрывает окно агрегации.
+func (n *Notifier) flush(alertType AlertType) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	b, ok := n.buckets[alertType]
+	if !ok {
+		return
+	}
+	delete(n.buckets, alertType)
+
+	if b.count == 0 {
+		return
+	}
+	n.dispatchLocked(n.summary(alertType, b))
+}
+
+// summary формирует сводное уведомление вида "5 more sell_failed alerts in the last 1m0s".
+func (n *Notifier) summary(alertType AlertType, b *aggregateBucket) Alert {
+	keys := make([]string, 0, len(b.keys))
+	for k := range b.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	msg := fmt.Sprintf("%d more %s alerts in the last %s", b.count, alertType, n.opts.AggregateWindow)
+	if len(keys) > 0 {
+		shown := keys
+		if len(shown) > maxKeysInSummary {
+			shown = shown[:maxKeysInSummary]
+		}
+		msg += ": " + strings.Join(shown, ", ")
+		if extra := len(keys) - len(shown); extra > 0 {
+			msg += fmt.Sprintf(" (+%d)", extra)
+		}
+	}
+
+	return Alert{
+		Type:     alertType,
+		Severity: b.severity,
+		Message:  msg,
+		Time:     time.Now(),
+	}
+}
+
+// dispatchLocked ставит уведомление в очередь каждого канала, чей фильтр его пропускает,
+// без блокировки.
+func (n *Notifier) dispatchLocked(alert Alert) {
+	for _, sq := range n.sinks {
+		if !accepts(sq.sink, alert) {
+			continue
+		}
+		if dropped := sq.push(alert); dropped != nil {
+			n.logger.Warn("Alert queue full, dropping lowest priority alert",
+				zap.String("sink", sq.sink.Name()),
+				zap.String("type", string(dropped.Type)))
+		}
+	}
+}
+
+// deliver отправляет уведомления канала по приоритету с учетом лимита скорости.
+// Неудачная отправка возвращает уведомление в очередь, сохраняет очередь на диск
+// и повторяется с растущей паузой; устаревшие уведомления сворачиваются в сводку.
+func (n *Notifier) deliver(sq *sinkQueue) {
+	defer n.wg.Done()
+
+	retryDelay := n.opts.RetryDelay
+	for {
+		sq.expire(time.Now(), n.opts.MaxAge)
+
+		item, ok := sq.pop()
+		if !ok {
+			if sq.drained() {
+				return
+			}
+			select {
+			case <-sq.wake:
+				continue
+			case <-n.ctx.Done():
+				return
+			}
+		}
+
+		if err := sq.limiter.Wait(n.ctx); err != nil {
+			sq.requeue(item)
+			return
+		}
+
+		sendCtx, cancel := context.WithTimeout(n.ctx, sendTimeout)
+		err := sq.sink.Send(sendCtx, item.Alert)
+		cancel()
+		if err == nil {
+			if item.Attempts > 0 {
+				n.logger.Info("Alert delivered after retry",
+					zap.String("sink", sq.sink.Name()),
+					zap.Int("attempts", item.Attempts+1))
+				if err := sq.save(); err != nil {
+					n.logger.Warn("Failed to update alert buffer", zap.Error(err))
+				}
+			}
+			retryDelay = n.opts.RetryDelay
+			continue
+		}
+
+		item.Attempts++
+		sq.requeue(item)
+		if err := sq.save(); err != nil {
+			n.logger.Warn("Failed to buffer alerts", zap.Error(err))
+		}
+		n.logger.Warn("Failed to send alert, will retry",
+			zap.String("sink", sq.sink.Name()),
+			zap.Duration("retry_in", retryDelay),
+			zap.Error(err))
+
+		select {
+		case <-time.After(retryDelay):
+		case <-n.ctx.Done():
+			return
+		}
+		retryDelay = min(retryDelay*2, maxRetryDelay)
+	}
+}
+
+// Close отправляет накопленные сводки и дожидается опустошения очередей
+// (не дольше defaultCloseWindow); недоставленное сохраняется в BufferDir.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	for alertType, b := range n.buckets {
+		b.timer.Stop()
+		delete(n.buckets, alertType)
+		if b.count > 0 {
+			n.dispatchLocked(n.summary(alertType, b))
+		}
+	}
+	n.closed = true
+	for _, sq := range n.sinks {
+		sq.close()
+	}
+	n.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(defaultCloseWindow):
+		n.logger.Warn("Timed out flushing alerts")
+	}
+	n.stop()
+	n.wg.Wait()
+
+	// Недоставленное остается в буфере до следующего запуска
+	for _, sq := range n.sinks {
+		if err := sq.save(); err != nil {
+			n.logger.Warn("Failed to buffer alerts",
+				zap.String("sink", sq.sink.Name()),
+				zap.Error(err))
+		}
+	}
+}
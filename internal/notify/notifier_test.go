@@ -0,0 +1,216 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeSink запоминает все полученные уведомления
+type fakeSink struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (f *fakeSink) Name() string { return "fake" }
+
+func (f *fakeSink) Send(_ context.Context, alert Alert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.alerts = append(f.alerts, alert)
+	return nil
+}
+
+func (f *fakeSink) received() []Alert {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Alert(nil), f.alerts...)
+}
+
+func TestNotifier_DedupeSameKey(t *testing.T) {
+	sink := &fakeSink{}
+	n := New(zap.NewNop(), Options{DedupeWindow: time.Hour, AggregateWindow: time.Hour, RatePerMinute: 600}, sink)
+
+	for i := 0; i < 3; i++ {
+		n.Notify(Alert{Type: AlertSellFailed, Key: "mintA", Message: "sell failed"})
+	}
+	n.Close()
+
+	// Первое уведомление уходит сразу, дубликаты не попадают даже в сводку
+	got := sink.received()
+	assert.Len(t, got, 1)
+	assert.Equal(t, "sell failed", got[0].Message)
+}
+
+func TestNotifier_AggregatesWithinWindow(t *testing.T) {
+	sink := &fakeSink{}
+	n := New(zap.NewNop(), Options{DedupeWindow: time.Hour, AggregateWindow: 50 * time.Millisecond, RatePerMinute: 600}, sink)
+
+	n.Notify(Alert{Type: AlertSellFailed, Key: "mintA", Message: "first"})
+	n.Notify(Alert{Type: AlertSellFailed, Key: "mintB", Severity: SeverityCritical, Message: "second"})
+	n.Notify(Alert{Type: AlertSellFailed, Key: "mintC", Message: "third"})
+
+	assert.Eventually(t, func() bool { return len(sink.received()) == 2 }, time.Second, 10*time.Millisecond)
+	n.Close()
+
+	got := sink.received()
+	assert.Equal(t, "first", got[0].Message)
+	assert.Contains(t, got[1].Message, "2 more sell_failed alerts")
+	assert.Contains(t, got[1].Message, "mintB, mintC")
+	assert.Equal(t, SeverityCritical, got[1].Severity)
+}
+
+func TestNotifier_TypesAggregateIndependently(t *testing.T) {
+	sink := &fakeSink{}
+	n := New(zap.NewNop(), Options{DedupeWindow: time.Hour, AggregateWindow: time.Hour, RatePerMinute: 600}, sink)
+
+	n.Notify(Alert{Type: AlertTradeExecuted, Key: "mintA", Message: "buy"})
+	n.Notify(Alert{Type: AlertSellCompleted, Key: "mintA", Message: "sell"})
+	n.Close()
+
+	assert.Len(t, sink.received(), 2)
+}
+
+func TestNotifier_RateLimitPerSink(t *testing.T) {
+	// 200/min => burst 200, затем одно сообщение в 300ms на каждый канал
+	const limit = 200
+	first, second := &fakeSink{}, &fakeSink{}
+	n := New(zap.NewNop(), Options{DedupeWindow: time.Hour, AggregateWindow: time.Hour, RatePerMinute: limit}, first, second)
+
+	start := time.Now()
+	for i := 0; i < limit+2; i++ {
+		// Разные типы, чтобы уведомления не сворачивались в сводку
+		n.Notify(Alert{Type: AlertType(fmt.Sprintf("type_%d", i)), Key: "mintA", Message: "alert"})
+	}
+
+	// Лимит у каждого канала свой: оба сразу получают полный burst
+	for _, sink := range []*fakeSink{first, second} {
+		assert.Eventually(t, func() bool { return len(sink.received()) == limit }, time.Second, 5*time.Millisecond)
+	}
+	assert.Never(t, func() bool { return len(first.received()) > limit }, 150*time.Millisecond, 5*time.Millisecond,
+		"alerts over the limit wait for the limiter")
+
+	assert.Eventually(t, func() bool { return len(first.received()) == limit+1 }, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(start), 250*time.Millisecond, "the next alert goes out at the limiter rate")
+
+	n.Close()
+	assert.Len(t, first.received(), limit+2)
+	assert.Len(t, second.received(), limit+2)
+}
+
+func TestNotifier_NilSafe(t *testing.T) {
+	var n *Notifier
+	n.Notify(Alert{Type: AlertTradeFailed})
+	n.Close()
+}
+
+// flakySink принимает уведомления только когда online
+type flakySink struct {
+	fakeSink
+	online atomic.Bool
+}
+
+func (f *flakySink) Send(ctx context.Context, alert Alert) error {
+	if !f.online.Load() {
+		return errors.New("unreachable")
+	}
+	return f.fakeSink.Send(ctx, alert)
+}
+
+func TestNotifier_RetriesByPriority(t *testing.T) {
+	sink := &flakySink{}
+	n := New(zap.NewNop(), Options{RatePerMinute: 600, RetryDelay: 20 * time.Millisecond}, sink)
+
+	n.Notify(Alert{Type: AlertTradeExecuted, Key: "mintA", Message: "bought"})
+	n.Notify(Alert{Type: AlertTradeFailed, Key: "mintB", Severity: SeverityWarning, Message: "buy failed"})
+	time.Sleep(50 * time.Millisecond)
+	sink.online.Store(true)
+
+	assert.Eventually(t, func() bool { return len(sink.received()) == 2 }, time.Second, 10*time.Millisecond)
+	n.Close()
+
+	// После восстановления канала сбой сделки уходит раньше информационного уведомления
+	got := sink.received()
+	assert.Equal(t, "buy failed", got[0].Message)
+	assert.Equal(t, "bought", got[1].Message)
+}
+
+func TestNotifier_DropsStaleAlerts(t *testing.T) {
+	sink := &fakeSink{}
+	n := New(zap.NewNop(), Options{RatePerMinute: 600, MaxAge: time.Minute}, sink)
+
+	n.Notify(Alert{Type: AlertIndicator, Key: "mintA", Message: "rsi", Time: time.Now().Add(-time.Hour)})
+	n.Close()
+
+	got := sink.received()
+	assert.Len(t, got, 1)
+	assert.Equal(t, AlertStaleDropped, got[0].Type)
+	assert.Contains(t, got[0].Message, "Dropped 1 stale alerts older than 1m0s: 1 indicator")
+}
+
+func TestSinkQueue_BufferSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	sq := newSinkQueue(&fakeSink{}, nil, dir)
+	sq.push(Alert{Type: AlertTradeExecuted, Message: "info"})
+	sq.push(Alert{Type: AlertSellFailed, Severity: SeverityCritical, Message: "sell failed"})
+	assert.NoError(t, sq.save())
+
+	restored := newSinkQueue(&fakeSink{}, nil, dir)
+	count, err := restored.load()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	first, _ := restored.pop()
+	assert.Equal(t, "sell failed", first.Alert.Message)
+
+	// Пустая очередь удаляет файл буфера
+	restored.pop()
+	assert.NoError(t, restored.save())
+	count, err = newSinkQueue(&fakeSink{}, nil, dir).load()
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestNotifier_FilteredSink(t *testing.T) {
+	all := &fakeSink{}
+	critical := &fakeSink{}
+	sells := &fakeSink{}
+	n := New(zap.NewNop(), Options{DedupeWindow: time.Hour, AggregateWindow: time.Hour, RatePerMinute: 600},
+		all,
+		Filtered("critical", critical, Filter{MinSeverity: SeverityCritical}),
+		Filtered("sells", sells, Filter{Types: []AlertType{AlertSellFailed, AlertSellCompleted}}),
+	)
+
+	n.Notify(Alert{Type: AlertTradeExecuted, Key: "mintA", Message: "bought"})
+	n.Notify(Alert{Type: AlertSellFailed, Key: "mintA", Severity: SeverityCritical, Message: "sell failed"})
+	n.Notify(Alert{Type: AlertMintRisk, Key: "mintB", Severity: SeverityWarning, Message: "risky mint"})
+	n.Close()
+
+	messages := func(s *fakeSink) []string {
+		var out []string
+		for _, a := range s.received() {
+			out = append(out, a.Message)
+		}
+		return out
+	}
+	assert.ElementsMatch(t, []string{"bought", "sell failed", "risky mint"}, messages(all))
+	assert.Equal(t, []string{"sell failed"}, messages(critical))
+	assert.Equal(t, []string{"sell failed"}, messages(sells))
+}
+
+func TestParseSeverity(t *testing.T) {
+	for in, want := range map[string]Severity{"": SeverityInfo, "info": SeverityInfo, "warning": SeverityWarning, "critical": SeverityCritical} {
+		got, err := ParseSeverity(in)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseSeverity("urgent")
+	assert.EqualError(t, err, `unknown severity "urgent"`)
+}
@@ -0,0 +1,258 @@
+// internal/notify/queue.go
+package notify
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// queuedAlert — уведомление в очереди доставки канала.
+type queuedAlert struct {
+	Alert    Alert `json:"alert"`
+	Attempts int   `json:"attempts"` // Неудачные попытки отправки
+	seq      uint64
+}
+
+// alertHeap упорядочивает уведомления по приоритету, при равном — по порядку поступления.
+type alertHeap []*queuedAlert
+
+func (h alertHeap) Len() int { return len(h) }
+
+func (h alertHeap) Less(i, j int) bool {
+	if pi, pj := h[i].Alert.priority(), h[j].Alert.priority(); pi != pj {
+		return pi > pj
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h alertHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *alertHeap) Push(x any) { *h = append(*h, x.(*queuedAlert)) }
+
+func (h *alertHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// priority ранжирует уведомление для очереди: сначала по важности,
+// при равной важности сбои сделок идут раньше остальных.
+func (a Alert) priority() int {
+	p := int(a.Severity) * 2
+	if a.Type == AlertTradeFailed || a.Type == AlertSellFailed {
+		p++
+	}
+	return p
+}
+
+// sinkQueue — очередь доставки одного канала с собственным лимитом скорости.
+// Пока канал недоступен, уведомления копятся в очереди и в файле буфера на диске,
+// откуда досылаются и после перезапуска.
+type sinkQueue struct {
+	sink    Sink
+	limiter *rate.Limiter
+	path    string // Файл буфера, пусто — очередь только в памяти
+
+	mu     sync.Mutex
+	items  alertHeap
+	seq    uint64
+	closed bool
+	wake   chan struct{}
+}
+
+func newSinkQueue(sink Sink, limiter *rate.Limiter, bufferDir string) *sinkQueue {
+	sq := &sinkQueue{sink: sink, limiter: limiter, wake: make(chan struct{}, 1)}
+	if bufferDir != "" {
+		sq.path = filepath.Join(bufferDir, sink.Name()+".jsonl")
+	}
+	return sq
+}
+
+// push ставит уведомление в очередь. Если очередь заполнена, вытесняется уведомление
+// с наименьшим приоритетом (возможно, само новое); вытесненное возвращается.
+func (sq *sinkQueue) push(alert Alert) (dropped *Alert) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	if sq.closed {
+		return &alert
+	}
+	if len(sq.items) >= maxPending {
+		worst := 0
+		for i := range sq.items {
+			if sq.items.Less(worst, i) {
+				worst = i
+			}
+		}
+		if sq.items[worst].Alert.priority() >= alert.priority() {
+			return &alert
+		}
+		dropped = &heap.Remove(&sq.items, worst).(*queuedAlert).Alert
+	}
+	sq.pushLocked(&queuedAlert{Alert: alert})
+	return dropped
+}
+
+func (sq *sinkQueue) pushLocked(item *queuedAlert) {
+	sq.seq++
+	item.seq = sq.seq
+	heap.Push(&sq.items, item)
+	select {
+	case sq.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pop извлекает самое приоритетное уведомление.
+func (sq *sinkQueue) pop() (*queuedAlert, bool) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	if len(sq.items) == 0 {
+		return nil, false
+	}
+	return heap.Pop(&sq.items).(*queuedAlert), true
+}
+
+// requeue возвращает неотправленное уведомление на его место в очереди.
+func (sq *sinkQueue) requeue(item *queuedAlert) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	heap.Push(&sq.items, item)
+}
+
+// expire удаляет уведомления старше maxAge и ставит вместо них одну сводку.
+func (sq *sinkQueue) expire(now time.Time, maxAge time.Duration) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	byType := make(map[AlertType]int)
+	kept := sq.items[:0]
+	for _, item := range sq.items {
+		if now.Sub(item.Alert.Time) > maxAge {
+			byType[item.Alert.Type]++
+			continue
+		}
+		kept = append(kept, item)
+	}
+	if len(byType) == 0 {
+		return
+	}
+	sq.items = kept
+	heap.Init(&sq.items)
+	sq.pushLocked(&queuedAlert{Alert: staleSummary(byType, maxAge, now)})
+}
+
+// staleSummary формирует сводку вида "Dropped 3 stale alerts older than 30m0s: 2 indicator, 1 trade_executed".
+func staleSummary(byType map[AlertType]int, maxAge time.Duration, now time.Time) Alert {
+	total := 0
+	parts := make([]string, 0, len(byType))
+	for alertType, count := range byType {
+		total += count
+		parts = append(parts, fmt.Sprintf("%d %s", count, alertType))
+	}
+	sort.Strings(parts)
+
+	return Alert{
+		Type:     AlertStaleDropped,
+		Severity: SeverityWarning,
+		Message:  fmt.Sprintf("Dropped %d stale alerts older than %s: %s", total, maxAge, strings.Join(parts, ", ")),
+		Time:     now,
+	}
+}
+
+// close запрещает новые уведомления и будит горутину доставки.
+func (sq *sinkQueue) close() {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	sq.closed = true
+	select {
+	case sq.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drained сообщает, что очередь закрыта и пуста.
+func (sq *sinkQueue) drained() bool {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	return sq.closed && len(sq.items) == 0
+}
+
+// save переписывает файл буфера текущим содержимым очереди; пустая очередь удаляет файл.
+func (sq *sinkQueue) save() error {
+	if sq.path == "" {
+		return nil
+	}
+	sq.mu.Lock()
+	items := append(alertHeap(nil), sq.items...)
+	sq.mu.Unlock()
+
+	if len(items) == 0 {
+		if err := os.Remove(sq.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove alert buffer: %w", err)
+		}
+		return nil
+	}
+	sort.Sort(items)
+
+	if err := os.MkdirAll(filepath.Dir(sq.path), 0o755); err != nil {
+		return fmt.Errorf("create alert buffer dir: %w", err)
+	}
+	tmp := sq.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create alert buffer: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			f.Close()
+			return fmt.Errorf("write alert buffer: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close alert buffer: %w", err)
+	}
+	return os.Rename(tmp, sq.path)
+}
+
+// load восстанавливает уведомления, оставшиеся в буфере с прошлого запуска.
+func (sq *sinkQueue) load() (int, error) {
+	if sq.path == "" {
+		return 0, nil
+	}
+	f, err := os.Open(sq.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("open alert buffer: %w", err)
+	}
+	defer f.Close()
+
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	n := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var item queuedAlert
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			continue // Поврежденную строку пропускаем
+		}
+		sq.pushLocked(&item)
+		n++
+	}
+	return n, scanner.Err()
+}
@@ -0,0 +1,173 @@
+// internal/notify/telegram.go
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// telegramAPIURL — Bot API Telegram.
+const telegramAPIURL = "https://api.telegram.org"
+
+// telegramPollTimeout — сколько getUpdates ждет новых сообщений (long polling).
+const telegramPollTimeout = 30 * time.Second
+
+// Sender — пользователь Telegram, отправивший команду.
+type Sender struct {
+	ID       int64
+	Username string // Без @; может быть пустым
+}
+
+// CommandHandler выполняет команду оператора from (например "/sell MINT 50") и возвращает ответ.
+type CommandHandler func(ctx context.Context, from Sender, command string) string
+
+// TelegramSink отправляет уведомления в чат Telegram и принимает из этого чата команды.
+type TelegramSink struct {
+	baseURL string // Адрес бота: telegramAPIURL + "/bot" + token
+	chatID  string
+	client  *http.Client
+}
+
+// NewTelegramSink создает канал доставки для бота с токеном token и чата chatID.
+func NewTelegramSink(token, chatID string) *TelegramSink {
+	return &TelegramSink{
+		baseURL: telegramAPIURL + "/bot" + token,
+		chatID:  chatID,
+		client:  &http.Client{Timeout: telegramPollTimeout + 10*time.Second},
+	}
+}
+
+// Name возвращает имя канала.
+func (t *TelegramSink) Name() string {
+	return "telegram"
+}
+
+// Send публикует уведомление в чат.
+func (t *TelegramSink) Send(ctx context.Context, alert Alert) error {
+	return t.sendMessage(ctx, alert.Text())
+}
+
+// Commands принимает сообщения из чата и передает их handler, пока не отменен ctx.
+// Сообщения из других чатов игнорируются: управлять ботом может только настроенный чат,
+// а права отправителя внутри чата проверяет handler.
+func (t *TelegramSink) Commands(ctx context.Context, handler CommandHandler) {
+	var offset int64
+	for {
+		updates, err := t.getUpdates(ctx, offset)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// Сеть или Telegram недоступны: пробуем снова чуть позже
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil || strconv.FormatInt(u.Message.Chat.ID, 10) != t.chatID {
+				continue
+			}
+			text := strings.TrimSpace(u.Message.Text)
+			if !strings.HasPrefix(text, "/") {
+				continue
+			}
+			from := Sender{ID: u.Message.From.ID, Username: u.Message.From.Username}
+			if reply := handler(ctx, from, text); reply != "" {
+				_ = t.sendMessage(ctx, reply)
+			}
+		}
+	}
+}
+
+// telegramUpdate — входящее обновление getUpdates; нужны только текстовые сообщения.
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		From struct {
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
+		} `json:"from"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+func (t *TelegramSink) getUpdates(ctx context.Context, offset int64) ([]telegramUpdate, error) {
+	q := url.Values{}
+	q.Set("offset", strconv.FormatInt(offset, 10))
+	q.Set("timeout", strconv.Itoa(int(telegramPollTimeout.Seconds())))
+	q.Set("allowed_updates", `["message"]`)
+
+	var updates []telegramUpdate
+	if err := t.call(ctx, http.MethodGet, "/getUpdates?"+q.Encode(), nil, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+func (t *TelegramSink) sendMessage(ctx context.Context, text string) error {
+	return t.call(ctx, http.MethodPost, "/sendMessage", map[string]string{
+		"chat_id": t.chatID,
+		"text":    text,
+	}, nil)
+}
+
+// call выполняет метод Bot API и разбирает поле result ответа в out.
+func (t *TelegramSink) call(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshal telegram request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("create telegram request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// Ошибка содержит URL с токеном бота
+		return fmt.Errorf("call telegram %s: request failed", strings.SplitN(path, "?", 2)[0])
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram returned status %d", resp.StatusCode)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram returned status %d: %s", resp.StatusCode, result.Description)
+	}
+	if out != nil {
+		if err := json.Unmarshal(result.Result, out); err != nil {
+			return fmt.Errorf("decode telegram response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramSink_SendAndCommands(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botTOKEN/sendMessage":
+			var msg map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+			assert.Equal(t, "42", msg["chat_id"])
+			mu.Lock()
+			sent = append(sent, msg["text"])
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+		case "/botTOKEN/getUpdates":
+			mu.Lock()
+			polls++
+			first := polls == 1
+			mu.Unlock()
+			if !first {
+				assert.Equal(t, "13", r.URL.Query().Get("offset"), "handled updates are acknowledged")
+				<-r.Context().Done()
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":true,"result":[
+				{"update_id":10,"message":{"chat":{"id":7},"text":"/sell Mint 100"}},
+				{"update_id":11,"message":{"chat":{"id":42},"text":"hello"}},
+				{"update_id":12,"message":{"chat":{"id":42},"from":{"id":1001,"username":"alice"},"text":"/positions"}}]}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"ok":false,"description":"Unauthorized"}`))
+		}
+	}))
+	defer srv.Close()
+
+	sink := NewTelegramSink("TOKEN", "42")
+	sink.baseURL = srv.URL + "/botTOKEN"
+	require.NoError(t, sink.Send(context.Background(), Alert{Severity: SeverityWarning, Message: "sell failed"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var commands []string
+	var senders []Sender
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sink.Commands(ctx, func(_ context.Context, from Sender, command string) string {
+			commands = append(commands, command)
+			senders = append(senders, from)
+			return "No open positions"
+		})
+	}()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls == 2
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	// Команды принимаются только из настроенного чата
+	assert.Equal(t, []string{"/positions"}, commands)
+	assert.Equal(t, []Sender{{ID: 1001, Username: "alice"}}, senders)
+	assert.Equal(t, []string{"⚠️ sell failed", "No open positions"}, sent)
+
+	sink.baseURL = srv.URL + "/botWRONG"
+	assert.EqualError(t, sink.Send(context.Background(), Alert{Message: "x"}), "telegram returned status 401: Unauthorized")
+}
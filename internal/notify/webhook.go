@@ -0,0 +1,55 @@
+// internal/notify/webhook.go
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink отправляет уведомления в Discord-совместимый webhook.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink создает канал доставки для указанного webhook URL.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name возвращает имя канала.
+func (w *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Send публикует уведомление в webhook.
+func (w *WebhookSink) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]string{"content": alert.Text()})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
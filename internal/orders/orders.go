@@ -0,0 +1,237 @@
+// internal/orders/orders.go
+package orders
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultPath — журнал лимитных ордеров (JSON Lines).
+const DefaultPath = "logs/orders.jsonl"
+
+// Стороны ордера.
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// Состояния ордера. Pending — единственное незавершенное.
+const (
+	StatusPending  = "pending"
+	StatusFilled   = "filled"
+	StatusFailed   = "failed"
+	StatusCanceled = "canceled"
+)
+
+// ErrNotPending — ордер не найден среди ожидающих.
+var ErrNotPending = errors.New("order is not pending")
+
+// Order — лимитный ордер: покупка, когда цена опускается до LimitPrice,
+// или продажа, когда поднимается до нее. Ордер не привязан к DEX: цену и сделку
+// дает адаптер модуля задачи.
+type Order struct {
+	ID         string    `json:"id"`
+	Status     string    `json:"status"`
+	Side       string    `json:"side,omitempty"`
+	TaskName   string    `json:"task_name,omitempty"`
+	Module     string    `json:"module,omitempty"`
+	Mint       string    `json:"mint,omitempty"`
+	Wallet     string    `json:"wallet,omitempty"`
+	LimitPrice float64   `json:"limit_price,omitempty"` // Цена токена в SOL
+	AmountSol  float64   `json:"amount_sol,omitempty"`  // Покупка: сколько SOL потратить
+	Percent    float64   `json:"percent,omitempty"`     // Продажа: процент баланса
+	Reason     string    `json:"reason,omitempty"`      // Причина сбоя или отмены
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// Pending сообщает, что ордер еще ждет цены.
+func (o Order) Pending() bool {
+	return o.Status == StatusPending
+}
+
+// Crossed сообщает, что цена дошла до лимита ордера.
+func (o Order) Crossed(price float64) bool {
+	if price <= 0 {
+		return false
+	}
+	if o.Side == SideBuy {
+		return price <= o.LimitPrice
+	}
+	return price >= o.LimitPrice
+}
+
+// String описывает ордер для логов и списка ордеров.
+func (o Order) String() string {
+	size := fmt.Sprintf("%.4f SOL", o.AmountSol)
+	if o.Side == SideSell {
+		size = fmt.Sprintf("%g%%", o.Percent)
+	}
+	return fmt.Sprintf("%s %s %s of %s at %.10f SOL (%s, %s)", o.ID, o.Side, size, o.Mint, o.LimitPrice, o.Wallet, o.Status)
+}
+
+// ID строит идентификатор ордера из параметров задачи: та же строка tasks.csv
+// после перезапуска продолжает тот же ордер, а не выставляет новый.
+func ID(taskName, side, mint, wallet string, limitPrice float64) string {
+	sum := sha256.Sum256([]byte(taskName + "|" + side + "|" + mint + "|" + wallet + "|" +
+		strconv.FormatFloat(limitPrice, 'f', -1, 64)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Book — журнал ордеров: события дописываются в файл, состояние ордера —
+// последнее событие с его ID. Файл общий для работающего бота и команд
+// -orders / -cancel-order. Все методы безопасны для nil.
+type Book struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewBook создает журнал по указанному пути.
+func NewBook(path string) *Book {
+	return &Book{path: path}
+}
+
+// Place выставляет ордер. Уже известный ордер возвращается как есть: ожидающий
+// продолжает ждать, исполненный и отмененный повторно не выставляются.
+// Ордер, сделка которого не прошла, выставляется заново.
+func (b *Book) Place(o Order) (Order, error) {
+	if b == nil {
+		return o, nil
+	}
+	existing, ok, err := b.Get(o.ID)
+	if err != nil {
+		return o, err
+	}
+	if ok && existing.Status != StatusFailed {
+		return existing, nil
+	}
+	now := time.Now()
+	o.Status, o.CreatedAt, o.At = StatusPending, now, now
+	return o, b.append(o)
+}
+
+// Resolve фиксирует итоговое состояние ордера.
+func (b *Book) Resolve(id, status, reason string) error {
+	if b == nil {
+		return nil
+	}
+	return b.append(Order{ID: id, Status: status, Reason: reason, At: time.Now()})
+}
+
+// Cancel отменяет ожидающий ордер.
+func (b *Book) Cancel(id string) error {
+	o, ok, err := b.Get(id)
+	if err != nil {
+		return err
+	}
+	if !ok || !o.Pending() {
+		return fmt.Errorf("%w: %s", ErrNotPending, id)
+	}
+	return b.Resolve(id, StatusCanceled, "canceled by operator")
+}
+
+// Get возвращает текущее состояние ордера.
+func (b *Book) Get(id string) (Order, bool, error) {
+	all, err := b.All()
+	if err != nil {
+		return Order{}, false, err
+	}
+	for _, o := range all {
+		if o.ID == id {
+			return o, true, nil
+		}
+	}
+	return Order{}, false, nil
+}
+
+// Pending возвращает ожидающие ордера в порядке выставления.
+func (b *Book) Pending() ([]Order, error) {
+	all, err := b.All()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Order
+	for _, o := range all {
+		if o.Pending() {
+			pending = append(pending, o)
+		}
+	}
+	return pending, nil
+}
+
+// All сворачивает журнал в последнее состояние каждого ордера, в порядке выставления.
+func (b *Book) All() ([]Order, error) {
+	if b == nil {
+		return nil, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f, err := os.Open(b.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open order book: %w", err)
+	}
+	defer f.Close()
+
+	byID := make(map[string]*Order)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev Order
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil || ev.ID == "" {
+			continue
+		}
+		o, ok := byID[ev.ID]
+		if !ok {
+			byID[ev.ID] = &ev
+			continue
+		}
+		o.Status, o.Reason, o.At = ev.Status, ev.Reason, ev.At
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read order book: %w", err)
+	}
+
+	all := make([]Order, 0, len(byID))
+	for _, o := range byID {
+		all = append(all, *o)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].CreatedAt.Before(all[j].CreatedAt) })
+	return all, nil
+}
+
+// append дописывает событие в журнал.
+func (b *Book) append(o Order) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return fmt.Errorf("create order book dir: %w", err)
+	}
+	f, err := os.OpenFile(b.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open order book: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("marshal order: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write order: %w", err)
+	}
+	return f.Sync()
+}
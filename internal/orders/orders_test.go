@@ -0,0 +1,65 @@
+package orders
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrder_Crossed(t *testing.T) {
+	buy := Order{Side: SideBuy, LimitPrice: 0.001}
+	assert.True(t, buy.Crossed(0.0009))
+	assert.True(t, buy.Crossed(0.001))
+	assert.False(t, buy.Crossed(0.0011))
+	assert.False(t, buy.Crossed(0), "missing price must not fill a buy")
+
+	sell := Order{Side: SideSell, LimitPrice: 0.001}
+	assert.True(t, sell.Crossed(0.0011))
+	assert.False(t, sell.Crossed(0.0009))
+}
+
+func TestBook_Lifecycle(t *testing.T) {
+	book := NewBook(filepath.Join(t.TempDir(), "orders.jsonl"))
+	buyID := ID("dip-1", SideBuy, "Mint", "main", 0.001)
+	sellID := ID("exit-1", SideSell, "Mint", "main", 0.01)
+	assert.NotEqual(t, buyID, sellID)
+
+	buy, err := book.Place(Order{ID: buyID, Side: SideBuy, Mint: "Mint", LimitPrice: 0.001, AmountSol: 0.5})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, buy.Status)
+	_, err = book.Place(Order{ID: sellID, Side: SideSell, Mint: "Mint", LimitPrice: 0.01, Percent: 50})
+	require.NoError(t, err)
+
+	pending, err := book.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, buyID, pending[0].ID)
+
+	require.NoError(t, book.Cancel(sellID))
+	assert.ErrorIs(t, book.Cancel(sellID), ErrNotPending)
+	assert.ErrorIs(t, book.Cancel("unknown"), ErrNotPending)
+
+	// Отмененный ордер не выставляется повторно при следующем запуске
+	again, err := book.Place(Order{ID: sellID, Side: SideSell})
+	require.NoError(t, err)
+	assert.Equal(t, StatusCanceled, again.Status)
+	assert.Equal(t, 50.0, again.Percent)
+
+	// Не прошедший ордер выставляется заново
+	require.NoError(t, book.Resolve(buyID, StatusFailed, "slippage"))
+	again, err = book.Place(Order{ID: buyID, Side: SideBuy})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, again.Status)
+
+	require.NoError(t, book.Resolve(buyID, StatusFilled, ""))
+	pending, err = book.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	all, err := book.All()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, StatusFilled, all[0].Status)
+}
@@ -0,0 +1,313 @@
+// internal/portfolio/balances.go
+package portfolio
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// maxAccountsPerRequest — лимит getMultipleAccounts на один запрос.
+	maxAccountsPerRequest = 100
+	// maxParallelOwners — сколько getTokenAccountsByOwner выполняется одновременно.
+	maxParallelOwners = 8
+	// DefaultBalanceTTL — время жизни кэша балансов по умолчанию.
+	DefaultBalanceTTL = 10 * time.Second
+)
+
+// TokenBalance — баланс одного SPL-токена кошелька в raw единицах.
+type TokenBalance struct {
+	Mint     solana.PublicKey
+	Account  solana.PublicKey
+	Amount   uint64
+	Decimals uint8
+}
+
+// UIAmount возвращает баланс с учетом decimals.
+func (t TokenBalance) UIAmount() float64 {
+	amount := float64(t.Amount)
+	for i := uint8(0); i < t.Decimals; i++ {
+		amount /= 10
+	}
+	return amount
+}
+
+// WalletBalance — снимок балансов кошелька.
+type WalletBalance struct {
+	Name      string
+	Role      task.WalletRole
+	Group     string
+	Address   solana.PublicKey
+	Lamports  uint64
+	Tokens    []TokenBalance
+	UpdatedAt time.Time
+}
+
+// SOL возвращает баланс кошелька в SOL.
+func (w WalletBalance) SOL() float64 {
+	return float64(w.Lamports) / float64(solana.LAMPORTS_PER_SOL)
+}
+
+// accountReader — запросы RPC, из которых собираются балансы; его реализует blockchain.Client.
+type accountReader interface {
+	GetMultipleAccounts(ctx context.Context, pubkeys []solana.PublicKey) (*rpc.GetMultipleAccountsResult, error)
+	GetTokenAccountsByOwner(ctx context.Context, owner solana.PublicKey) (*rpc.GetTokenAccountsResult, error)
+}
+
+// BalanceService загружает балансы кошельков пакетно и кэширует их.
+// Повторный вызов обновляет только устаревшие кошельки.
+type BalanceService struct {
+	client accountReader
+	logger *zap.Logger
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	cache    map[solana.PublicKey]*WalletBalance
+	decimals map[solana.PublicKey]uint8
+}
+
+// NewBalanceService создает сервис балансов с заданным TTL кэша.
+func NewBalanceService(client *blockchain.Client, logger *zap.Logger, ttl time.Duration) *BalanceService {
+	if ttl <= 0 {
+		ttl = DefaultBalanceTTL
+	}
+	return &BalanceService{
+		client:   client,
+		logger:   logger.Named("balances"),
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    make(map[solana.PublicKey]*WalletBalance),
+		decimals: make(map[solana.PublicKey]uint8),
+	}
+}
+
+// Balances возвращает балансы всех кошельков, отсортированные по имени.
+func (s *BalanceService) Balances(ctx context.Context, wallets map[string]*task.Wallet) ([]WalletBalance, error) {
+	names := make(map[solana.PublicKey]string, len(wallets))
+	byAddr := make(map[solana.PublicKey]*task.Wallet, len(wallets))
+	var stale []solana.PublicKey
+
+	now := s.now()
+	s.mu.RLock()
+	for name, w := range wallets {
+		names[w.PublicKey] = name
+		byAddr[w.PublicKey] = w
+		if cached, ok := s.cache[w.PublicKey]; !ok || now.Sub(cached.UpdatedAt) >= s.ttl {
+			stale = append(stale, w.PublicKey)
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(stale) > 0 {
+		if err := s.refresh(ctx, stale); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.RLock()
+	result := make([]WalletBalance, 0, len(names))
+	for addr, name := range names {
+		if cached, ok := s.cache[addr]; ok {
+			wb := *cached
+			wb.Name = name
+			wb.Role = byAddr[addr].Role
+			wb.Group = byAddr[addr].Group
+			result = append(result, wb)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// RoleTotal — суммарный SOL-баланс кошельков одной роли.
+type RoleTotal struct {
+	Role     task.WalletRole
+	Wallets  int
+	Lamports uint64
+}
+
+// SOL возвращает суммарный баланс роли в SOL.
+func (t RoleTotal) SOL() float64 {
+	return float64(t.Lamports) / float64(solana.LAMPORTS_PER_SOL)
+}
+
+// TotalsByRole агрегирует балансы по ролям кошельков, отсортированным по имени роли.
+func TotalsByRole(balances []WalletBalance) []RoleTotal {
+	totals := make(map[task.WalletRole]*RoleTotal)
+	for _, b := range balances {
+		t, ok := totals[b.Role]
+		if !ok {
+			t = &RoleTotal{Role: b.Role}
+			totals[b.Role] = t
+		}
+		t.Wallets++
+		t.Lamports += b.Lamports
+	}
+
+	result := make([]RoleTotal, 0, len(totals))
+	for _, t := range totals {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Role < result[j].Role })
+	return result
+}
+
+// Invalidate помечает кошелек устаревшим (например, после сделки).
+func (s *BalanceService) Invalidate(owner solana.PublicKey) {
+	s.mu.Lock()
+	delete(s.cache, owner)
+	s.mu.Unlock()
+}
+
+// refresh обновляет балансы указанных кошельков.
+func (s *BalanceService) refresh(ctx context.Context, owners []solana.PublicKey) error {
+	lamports, err := s.fetchLamports(ctx, owners)
+	if err != nil {
+		return err
+	}
+
+	tokens := make([][]TokenBalance, len(owners))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(maxParallelOwners)
+	for i, owner := range owners {
+		g.Go(func() error {
+			tb, err := s.fetchTokenAccounts(gCtx, owner)
+			if err != nil {
+				return fmt.Errorf("token accounts for %s: %w", owner, err)
+			}
+			tokens[i] = tb
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	if err := s.fillDecimals(ctx, tokens); err != nil {
+		// Decimals не критичны для работы — логируем и продолжаем
+		s.logger.Debug("Failed to fetch mint decimals", zap.Error(err))
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	for i, owner := range owners {
+		s.cache[owner] = &WalletBalance{
+			Address:   owner,
+			Lamports:  lamports[i],
+			Tokens:    tokens[i],
+			UpdatedAt: now,
+		}
+	}
+	s.mu.Unlock()
+
+	s.logger.Debug("Balances refreshed", zap.Int("wallets", len(owners)))
+	return nil
+}
+
+// fetchLamports получает SOL-балансы пачками через getMultipleAccounts.
+func (s *BalanceService) fetchLamports(ctx context.Context, owners []solana.PublicKey) ([]uint64, error) {
+	lamports := make([]uint64, len(owners))
+	for start := 0; start < len(owners); start += maxAccountsPerRequest {
+		end := min(start+maxAccountsPerRequest, len(owners))
+
+		res, err := s.client.GetMultipleAccounts(ctx, owners[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("get multiple accounts: %w", err)
+		}
+		for i, acc := range res.Value {
+			if acc != nil {
+				lamports[start+i] = acc.Lamports
+			}
+		}
+	}
+	return lamports, nil
+}
+
+// fetchTokenAccounts получает все токен-аккаунты владельца одним запросом.
+func (s *BalanceService) fetchTokenAccounts(ctx context.Context, owner solana.PublicKey) ([]TokenBalance, error) {
+	res, err := s.client.GetTokenAccountsByOwner(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]TokenBalance, 0, len(res.Value))
+	for _, ta := range res.Value {
+		if ta == nil || ta.Account.Data == nil {
+			continue
+		}
+		data := ta.Account.Data.GetBinary()
+		// SPL token account: mint [0:32], owner [32:64], amount [64:72]
+		if len(data) < 72 {
+			continue
+		}
+		balances = append(balances, TokenBalance{
+			Mint:    solana.PublicKeyFromBytes(data[0:32]),
+			Account: ta.Pubkey,
+			Amount:  binary.LittleEndian.Uint64(data[64:72]),
+		})
+	}
+	return balances, nil
+}
+
+// fillDecimals подгружает decimals неизвестных минтов пакетно и проставляет их в балансы.
+func (s *BalanceService) fillDecimals(ctx context.Context, tokens [][]TokenBalance) error {
+	s.mu.RLock()
+	seen := make(map[solana.PublicKey]struct{})
+	var missing []solana.PublicKey
+	for _, list := range tokens {
+		for _, tb := range list {
+			if _, ok := s.decimals[tb.Mint]; ok {
+				continue
+			}
+			if _, ok := seen[tb.Mint]; !ok {
+				seen[tb.Mint] = struct{}{}
+				missing = append(missing, tb.Mint)
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	for start := 0; start < len(missing); start += maxAccountsPerRequest {
+		end := min(start+maxAccountsPerRequest, len(missing))
+
+		res, err := s.client.GetMultipleAccounts(ctx, missing[start:end])
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		for i, acc := range res.Value {
+			if acc == nil || acc.Data == nil {
+				continue
+			}
+			// SPL mint: decimals на смещении 44
+			if data := acc.Data.GetBinary(); len(data) > 44 {
+				s.decimals[missing[start+i]] = data[44]
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, list := range tokens {
+		for i := range list {
+			list[i].Decimals = s.decimals[list[i].Mint]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// stubRPC отвечает балансами и токен-аккаунтами из своих карт и запоминает запросы.
+type stubRPC struct {
+	mu       sync.Mutex
+	lamports map[solana.PublicKey]uint64
+	decimals map[solana.PublicKey]uint8
+	tokens   map[solana.PublicKey][]*rpc.TokenAccount
+	batches  [][]solana.PublicKey // Ключи каждого getMultipleAccounts
+	owners   []solana.PublicKey   // Владельцы каждого getTokenAccountsByOwner
+}
+
+func (s *stubRPC) GetMultipleAccounts(_ context.Context, pubkeys []solana.PublicKey) (*rpc.GetMultipleAccountsResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, append([]solana.PublicKey(nil), pubkeys...))
+
+	res := &rpc.GetMultipleAccountsResult{Value: make([]*rpc.Account, len(pubkeys))}
+	for i, key := range pubkeys {
+		if lamports, ok := s.lamports[key]; ok {
+			res.Value[i] = &rpc.Account{Lamports: lamports}
+		}
+		if decimals, ok := s.decimals[key]; ok {
+			data := make([]byte, 82)
+			data[44] = decimals
+			res.Value[i] = &rpc.Account{Data: rpc.DataBytesOrJSONFromBytes(data)}
+		}
+	}
+	return res, nil
+}
+
+func (s *stubRPC) GetTokenAccountsByOwner(_ context.Context, owner solana.PublicKey) (*rpc.GetTokenAccountsResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners = append(s.owners, owner)
+	return &rpc.GetTokenAccountsResult{Value: s.tokens[owner]}, nil
+}
+
+func (s *stubRPC) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches, s.owners = nil, nil
+}
+
+func batchSizes(batches [][]solana.PublicKey) []int {
+	sizes := make([]int, len(batches))
+	for i, b := range batches {
+		sizes[i] = len(b)
+	}
+	return sizes
+}
+
+// tokenAccount кодирует SPL токен-аккаунт: mint, владелец, количество.
+func tokenAccount(mint, owner solana.PublicKey, amount uint64) *rpc.TokenAccount {
+	data := make([]byte, 165)
+	copy(data[0:32], mint[:])
+	copy(data[32:64], owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], amount)
+	return &rpc.TokenAccount{Pubkey: solana.NewWallet().PublicKey(), Account: rpc.Account{Data: rpc.DataBytesOrJSONFromBytes(data)}}
+}
+
+// testWallets создает n кошельков с балансом i+1 SOL; каждый держит общий токен.
+func testWallets(n int, shared solana.PublicKey) (map[string]*task.Wallet, *stubRPC) {
+	stub := &stubRPC{
+		lamports: make(map[solana.PublicKey]uint64),
+		decimals: map[solana.PublicKey]uint8{shared: 6},
+		tokens:   make(map[solana.PublicKey][]*rpc.TokenAccount),
+	}
+	wallets := make(map[string]*task.Wallet, n)
+	for i := range n {
+		w := &task.Wallet{Name: fmt.Sprintf("w%03d", i), PublicKey: solana.NewWallet().PublicKey()}
+		if i%2 == 1 {
+			w.Role = task.RoleSniper
+		}
+		wallets[w.Name] = w
+		stub.lamports[w.PublicKey] = uint64(i+1) * solana.LAMPORTS_PER_SOL
+		stub.tokens[w.PublicKey] = []*rpc.TokenAccount{tokenAccount(shared, w.PublicKey, uint64(i)*1_000_000)}
+	}
+	return wallets, stub
+}
+
+func TestBalanceService_Batches(t *testing.T) {
+	shared := solana.NewWallet().PublicKey()
+	wallets, stub := testWallets(250, shared)
+	svc := NewBalanceService(nil, zap.NewNop(), time.Minute)
+	svc.client = stub
+
+	balances, err := svc.Balances(context.Background(), wallets)
+	require.NoError(t, err)
+	require.Len(t, balances, 250)
+
+	// Три пачки SOL-балансов по лимиту getMultipleAccounts и одна — decimals общего минта
+	assert.Equal(t, []int{100, 100, 50, 1}, batchSizes(stub.batches))
+	assert.Equal(t, []solana.PublicKey{shared}, stub.batches[3], "a mint held by every wallet is requested once")
+	assert.Len(t, stub.owners, 250)
+
+	for i, b := range balances {
+		assert.Equal(t, fmt.Sprintf("w%03d", i), b.Name, "sorted by name")
+		assert.Equal(t, float64(i+1), b.SOL(), "every chunk keeps its offset")
+		require.Len(t, b.Tokens, 1)
+		assert.Equal(t, uint8(6), b.Tokens[0].Decimals)
+		assert.Equal(t, float64(i), b.Tokens[0].UIAmount())
+	}
+	assert.Equal(t, task.RoleSniper, balances[1].Role)
+}
+
+func TestBalanceService_CacheAndInvalidate(t *testing.T) {
+	shared := solana.NewWallet().PublicKey()
+	wallets, stub := testWallets(3, shared)
+	svc := NewBalanceService(nil, zap.NewNop(), 10*time.Second)
+	svc.client = stub
+	now := time.Unix(1_700_000_000, 0)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.Balances(context.Background(), wallets)
+	require.NoError(t, err)
+	stub.reset()
+
+	now = now.Add(5 * time.Second)
+	_, err = svc.Balances(context.Background(), wallets)
+	require.NoError(t, err)
+	assert.Empty(t, stub.batches, "fresh balances come from the cache")
+	assert.Empty(t, stub.owners)
+
+	// После сделки обновляется только ее кошелек; decimals минта уже известны
+	traded := wallets["w001"]
+	stub.lamports[traded.PublicKey] = 42 * solana.LAMPORTS_PER_SOL
+	svc.Invalidate(traded.PublicKey)
+	balances, err := svc.Balances(context.Background(), wallets)
+	require.NoError(t, err)
+	assert.Equal(t, [][]solana.PublicKey{{traded.PublicKey}}, stub.batches)
+	assert.Equal(t, []solana.PublicKey{traded.PublicKey}, stub.owners)
+	assert.Equal(t, 42.0, balances[1].SOL())
+	assert.Equal(t, 1.0, balances[0].SOL())
+	stub.reset()
+
+	// Кошельки старше TTL обновляются вместе, свежий после Invalidate остается в кэше
+	now = now.Add(6 * time.Second)
+	_, err = svc.Balances(context.Background(), wallets)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, batchSizes(stub.batches))
+	assert.NotContains(t, stub.owners, traded.PublicKey)
+}
+
+func TestTotalsByRole(t *testing.T) {
+	totals := TotalsByRole([]WalletBalance{
+		{Role: task.RoleSniper, Lamports: 2 * solana.LAMPORTS_PER_SOL},
+		{Role: task.RoleVault, Lamports: 10 * solana.LAMPORTS_PER_SOL},
+		{Lamports: solana.LAMPORTS_PER_SOL / 2},
+		{Role: task.RoleSniper, Lamports: solana.LAMPORTS_PER_SOL},
+	})
+	require.Len(t, totals, 3)
+	assert.Equal(t, RoleTotal{Role: task.RoleNone, Wallets: 1, Lamports: solana.LAMPORTS_PER_SOL / 2}, totals[0])
+	assert.Equal(t, task.RoleSniper, totals[1].Role)
+	assert.Equal(t, 2, totals[1].Wallets)
+	assert.Equal(t, 3.0, totals[1].SOL())
+	assert.Equal(t, task.RoleVault, totals[2].Role)
+
+	assert.Empty(t, TotalsByRole(nil))
+}
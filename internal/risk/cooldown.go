@@ -0,0 +1,63 @@
+// internal/risk/cooldown.go
+package risk
+
+import (
+	"sync"
+	"time"
+)
+
+// Cooldown не дает повторить покупку по одному ключу (кошелек + токен) раньше, чем
+// через окно: защита от двойного запуска задачи и повторных событий слушателей.
+type Cooldown struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// NewCooldown создает кулдаун с окном window; window <= 0 — без кулдауна (nil).
+func NewCooldown(window time.Duration, now func() time.Time) *Cooldown {
+	if window <= 0 {
+		return nil
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Cooldown{window: window, now: now, last: make(map[string]time.Time)}
+}
+
+// Acquire отмечает покупку по key. ok = false, если предыдущая покупка была меньше
+// окна назад: тогда at — ее время, иначе at — время новой отметки для Release.
+func (c *Cooldown) Acquire(key string) (at time.Time, ok bool) {
+	if c == nil {
+		return time.Time{}, true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, t := range c.last {
+		if now.Sub(t) >= c.window {
+			delete(c.last, k)
+		}
+	}
+	if last, found := c.last[key]; found {
+		return last, false
+	}
+	c.last[key] = now
+	return now, true
+}
+
+// Release снимает отметку, сделанную Acquire в at, например если покупка не состоялась.
+// Более поздняя отметка того же key не снимается.
+func (c *Cooldown) Release(key string, at time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last[key].Equal(at) {
+		delete(c.last, key)
+	}
+}
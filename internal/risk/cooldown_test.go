@@ -0,0 +1,52 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCooldown(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	c := NewCooldown(time.Minute, func() time.Time { return now })
+
+	first, ok := c.Acquire("main/MintA")
+	assert.True(t, ok)
+	_, ok = c.Acquire("alt/MintA")
+	assert.True(t, ok, "other wallets are not limited")
+
+	now = now.Add(30 * time.Second)
+	last, ok := c.Acquire("main/MintA")
+	assert.False(t, ok)
+	assert.Equal(t, first, last)
+
+	now = now.Add(30 * time.Second)
+	_, ok = c.Acquire("main/MintA")
+	assert.True(t, ok, "the window has passed")
+}
+
+func TestCooldown_Release(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	c := NewCooldown(time.Minute, func() time.Time { return now })
+
+	at, ok := c.Acquire("main/MintA")
+	assert.True(t, ok)
+	c.Release("main/MintA", at)
+	_, ok = c.Acquire("main/MintA")
+	assert.True(t, ok, "a failed buy does not hold the cooldown")
+
+	c.Release("main/MintA", at.Add(-time.Second))
+	_, ok = c.Acquire("main/MintA")
+	assert.False(t, ok, "a stale release keeps the newer mark")
+}
+
+func TestCooldown_Off(t *testing.T) {
+	c := NewCooldown(0, nil)
+	assert.Nil(t, c)
+	_, ok := c.Acquire("main/MintA")
+	assert.True(t, ok)
+	_, ok = c.Acquire("main/MintA")
+	assert.True(t, ok)
+	c.Release("main/MintA", time.Now())
+}
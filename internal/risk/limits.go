@@ -0,0 +1,61 @@
+// internal/risk/limits.go
+package risk
+
+import (
+	"errors"
+	"fmt"
+)
+
+// limitEpsilon — допуск сравнения сумм в SOL, чтобы покупка ровно до лимита проходила.
+const limitEpsilon = 1e-9
+
+// ErrLimitExceeded — покупка нарушила бы ограничения размера позиций.
+var ErrLimitExceeded = errors.New("position size limit exceeded")
+
+// Limits — ограничения размера позиций; 0 — без ограничения.
+type Limits struct {
+	MaxTokenSol    float64 // Вложено в один токен на всех кошельках, SOL
+	MaxPositions   int     // Одновременно открытых позиций (кошелек + токен)
+	MaxExposureSol float64 // Вложено во все открытые позиции, SOL
+}
+
+// Exposure — вложения открытой или покупаемой позиции.
+type Exposure struct {
+	Mint   string
+	Wallet string
+	Sol    float64
+}
+
+// Enabled сообщает, задано ли хотя бы одно ограничение.
+func (l Limits) Enabled() bool {
+	return l.MaxTokenSol > 0 || l.MaxPositions > 0 || l.MaxExposureSol > 0
+}
+
+// Check проверяет, не нарушит ли покупка buy ограничения при вложениях open.
+// Покупка в уже открытую позицию не увеличивает число позиций. Ошибка оборачивает
+// ErrLimitExceeded и называет нарушенное ограничение.
+func (l Limits) Check(open []Exposure, buy Exposure) error {
+	positions := make(map[string]bool, len(open))
+	var tokenSol, totalSol float64
+	for _, e := range open {
+		positions[e.Wallet+"/"+e.Mint] = true
+		if e.Mint == buy.Mint {
+			tokenSol += e.Sol
+		}
+		totalSol += e.Sol
+	}
+
+	if l.MaxTokenSol > 0 && tokenSol+buy.Sol > l.MaxTokenSol+limitEpsilon {
+		return fmt.Errorf("%w: %s would hold %.4f SOL (%.4f open + %.4f), max_token_sol is %.4f",
+			ErrLimitExceeded, buy.Mint, tokenSol+buy.Sol, tokenSol, buy.Sol, l.MaxTokenSol)
+	}
+	if l.MaxPositions > 0 && !positions[buy.Wallet+"/"+buy.Mint] && len(positions) >= l.MaxPositions {
+		return fmt.Errorf("%w: %d positions are open, max_open_positions is %d",
+			ErrLimitExceeded, len(positions), l.MaxPositions)
+	}
+	if l.MaxExposureSol > 0 && totalSol+buy.Sol > l.MaxExposureSol+limitEpsilon {
+		return fmt.Errorf("%w: total exposure would be %.4f SOL (%.4f open + %.4f), max_exposure_sol is %.4f",
+			ErrLimitExceeded, totalSol+buy.Sol, totalSol, buy.Sol, l.MaxExposureSol)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits_Check(t *testing.T) {
+	open := []Exposure{
+		{Mint: "MintA", Wallet: "main", Sol: 0.5},
+		{Mint: "MintA", Wallet: "alt", Sol: 0.3},
+		{Mint: "MintB", Wallet: "main", Sol: 0.2},
+	}
+
+	tests := []struct {
+		name    string
+		limits  Limits
+		buy     Exposure
+		wantErr string
+	}{
+		{name: "no limits", buy: Exposure{Mint: "MintA", Wallet: "main", Sol: 10}},
+		{name: "token limit reached exactly", limits: Limits{MaxTokenSol: 1}, buy: Exposure{Mint: "MintA", Wallet: "main", Sol: 0.2}},
+		{name: "token limit exceeded", limits: Limits{MaxTokenSol: 1}, buy: Exposure{Mint: "MintA", Wallet: "third", Sol: 0.3}, wantErr: "max_token_sol"},
+		{name: "other token under token limit", limits: Limits{MaxTokenSol: 1}, buy: Exposure{Mint: "MintB", Wallet: "main", Sol: 0.5}},
+		{name: "new position over count", limits: Limits{MaxPositions: 3}, buy: Exposure{Mint: "MintC", Wallet: "main", Sol: 0.1}, wantErr: "max_open_positions"},
+		{name: "merge does not add a position", limits: Limits{MaxPositions: 3}, buy: Exposure{Mint: "MintB", Wallet: "main", Sol: 0.1}},
+		{name: "exposure exceeded", limits: Limits{MaxExposureSol: 1.2}, buy: Exposure{Mint: "MintC", Wallet: "main", Sol: 0.3}, wantErr: "max_exposure_sol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Check(open, tt.buy)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrLimitExceeded)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLimits_Enabled(t *testing.T) {
+	assert.False(t, Limits{}.Enabled())
+	assert.True(t, Limits{MaxPositions: 1}.Enabled())
+}
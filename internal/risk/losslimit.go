@@ -0,0 +1,106 @@
+// internal/risk/losslimit.go
+package risk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State — PnL текущего дня относительно лимита убытка.
+type State struct {
+	Day        time.Time // Начало дня по местному времени
+	Limit      float64   // Лимит убытка за день, SOL
+	Realized   float64   // PnL позиций, закрытых за день, SOL
+	Unrealized float64   // PnL открытых позиций при последней проверке, SOL
+	Tripped    bool      // Лимит достигнут: покупки запрещены до конца дня
+	TrippedAt  time.Time
+}
+
+// PnL возвращает итог дня: реализованный и нереализованный PnL.
+func (s State) PnL() float64 {
+	return s.Realized + s.Unrealized
+}
+
+// String описывает итог дня для лога и уведомлений.
+func (s State) String() string {
+	return fmt.Sprintf("day PnL %+.4f SOL (realized %+.4f, unrealized %+.4f), limit -%.4f SOL",
+		s.PnL(), s.Realized, s.Unrealized, s.Limit)
+}
+
+// LossLimit — дневной лимит убытка: реализованный PnL закрытых за день позиций
+// вместе с нереализованным PnL открытых сравнивается с лимитом. Сработавший лимит
+// запрещает новые покупки до конца дня, даже если открытые позиции потом отрастут;
+// продажи он не ограничивает. Новый день начинается в полночь по местному времени.
+type LossLimit struct {
+	now func() time.Time
+
+	mu    sync.Mutex
+	state State
+}
+
+// NewLossLimit создает лимит убытка limitSol SOL в день; limitSol <= 0 — лимита нет (nil).
+func NewLossLimit(limitSol float64, now func() time.Time) *LossLimit {
+	if limitSol <= 0 {
+		return nil
+	}
+	if now == nil {
+		now = time.Now
+	}
+	l := &LossLimit{now: now}
+	l.state = State{Day: startOfDay(now()), Limit: limitSol}
+	return l
+}
+
+// Record учитывает PnL позиции, закрытой в closedAt. Позиции прошлых дней не учитываются.
+func (l *LossLimit) Record(pnlSol float64, closedAt time.Time) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover()
+	if startOfDay(closedAt).Equal(l.state.Day) {
+		l.state.Realized += pnlSol
+	}
+}
+
+// Update пересчитывает итог дня с нереализованным PnL открытых позиций.
+// tripped = true, если лимит сработал именно этим вызовом.
+func (l *LossLimit) Update(unrealizedSol float64) (state State, tripped bool) {
+	if l == nil {
+		return State{}, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover()
+	l.state.Unrealized = unrealizedSol
+	if !l.state.Tripped && l.state.PnL() <= -l.state.Limit {
+		l.state.Tripped, l.state.TrippedAt = true, l.now()
+		tripped = true
+	}
+	return l.state, tripped
+}
+
+// State возвращает итог дня на момент последней проверки.
+func (l *LossLimit) State() State {
+	if l == nil {
+		return State{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover()
+	return l.state
+}
+
+// rollover начинает новый день: реализованный PnL обнуляется, лимит снова разрешает покупки.
+func (l *LossLimit) rollover() {
+	if day := startOfDay(l.now()); day.After(l.state.Day) {
+		l.state = State{Day: day, Limit: l.state.Limit, Unrealized: l.state.Unrealized}
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
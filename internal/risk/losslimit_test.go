@@ -0,0 +1,55 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLossLimit_Off(t *testing.T) {
+	l := NewLossLimit(0, nil)
+	assert.Nil(t, l)
+
+	l.Record(-5, time.Now())
+	state, tripped := l.Update(-5)
+	assert.False(t, tripped)
+	assert.False(t, state.Tripped)
+	assert.False(t, l.State().Tripped)
+}
+
+func TestLossLimit_TripsOnRealizedAndUnrealized(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	l := NewLossLimit(1, func() time.Time { return now })
+
+	l.Record(-0.6, now.Add(-time.Hour))
+	l.Record(-3, now.Add(-24*time.Hour)) // Вчерашняя позиция не считается
+	state, tripped := l.Update(-0.3)
+	assert.False(t, tripped)
+	assert.InDelta(t, -0.9, state.PnL(), 1e-9)
+
+	state, tripped = l.Update(-0.5)
+	assert.True(t, tripped)
+	assert.True(t, state.Tripped)
+	assert.Equal(t, now, state.TrippedAt)
+
+	// Лимит держится до конца дня, даже если позиции отросли
+	state, tripped = l.Update(2)
+	assert.False(t, tripped)
+	assert.True(t, state.Tripped)
+}
+
+func TestLossLimit_ResetsAtMidnight(t *testing.T) {
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.Local)
+	l := NewLossLimit(1, func() time.Time { return now })
+
+	l.Record(-2, now)
+	_, tripped := l.Update(0)
+	assert.True(t, tripped)
+
+	now = now.Add(2 * time.Hour)
+	state := l.State()
+	assert.False(t, state.Tripped)
+	assert.Zero(t, state.Realized)
+	assert.Equal(t, time.Date(2024, 5, 2, 0, 0, 0, 0, time.Local), state.Day)
+}
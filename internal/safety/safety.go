@@ -0,0 +1,191 @@
+// internal/safety/safety.go
+package safety
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+)
+
+// Вклад проверок в оценку риска; сумма — 100.
+const (
+	mintAuthorityRisk   = 25
+	freezeAuthorityRisk = 25
+	concentrationRisk   = 20
+	liquidityRisk       = 20
+	creatorRisk         = 10
+)
+
+const (
+	// topHolders — сколько крупнейших кошельков учитывается в концентрации
+	topHolders = 10
+	// minLockedPercent — доля эмиссии в программных аккаунтах, при которой ликвидность считается найденной
+	minLockedPercent = 5.0
+	// freshCreatorTxs — у создателя меньше транзакций: кошелек заведен под этот запуск
+	freshCreatorTxs = 10
+)
+
+// Source — данные сети, нужные проверкам.
+type Source interface {
+	GetMintState(ctx context.Context, mint, tokenAccount solana.PublicKey) (*blockchain.MintState, bool, error)
+	GetLargestHolders(ctx context.Context, mint solana.PublicKey) ([]blockchain.TokenHolder, error)
+	GetSignaturesForAddress(ctx context.Context, address solana.PublicKey, limit int) ([]*rpc.TransactionSignature, error)
+}
+
+// Check — результат одной проверки.
+type Check struct {
+	Name   string
+	Risk   int    // Вклад в оценку (0 — проверка пройдена)
+	Detail string // Пояснение для лога
+}
+
+// Report — оценка риска токена перед покупкой.
+type Report struct {
+	Mint   solana.PublicKey
+	Score  int // 0–100, больше — рискованнее
+	Checks []Check
+}
+
+// Risks перечисляет непройденные проверки одной строкой.
+func (r *Report) Risks() string {
+	var parts []string
+	for _, c := range r.Checks {
+		if c.Risk > 0 {
+			parts = append(parts, c.Detail)
+		}
+	}
+	if len(parts) == 0 {
+		return "no risks found"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Checker оценивает риск токена по mint, держателям, ликвидности и создателю.
+type Checker struct {
+	src             Source
+	curve           func(ctx context.Context, mint solana.PublicKey) (*pumpfun.BondingCurve, error)
+	maxTopHoldersPc float64
+}
+
+// NewChecker создает проверку поверх клиента сети. maxTopHoldersPercent — доля эмиссии,
+// которую могут держать крупнейшие кошельки без штрафа.
+func NewChecker(client *blockchain.Client, maxTopHoldersPercent float64) *Checker {
+	return &Checker{
+		src: client,
+		curve: func(ctx context.Context, mint solana.PublicKey) (*pumpfun.BondingCurve, error) {
+			return pumpfun.FetchBondingCurve(ctx, client, mint)
+		},
+		maxTopHoldersPc: maxTopHoldersPercent,
+	}
+}
+
+// Check проверяет токен mint. Ошибка возвращается, только если не удалось прочитать
+// сам mint; сбой остальных проверок отражается в отчете без штрафа.
+func (c *Checker) Check(ctx context.Context, mint solana.PublicKey) (*Report, error) {
+	state, _, err := c.src.GetMintState(ctx, mint, solana.PublicKey{})
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Mint: mint}
+	report.add(authorityCheck("mint_authority", state.MintAuthority, mintAuthorityRisk, "mint authority is set: supply can be inflated"))
+	report.add(authorityCheck("freeze_authority", state.FreezeAuthority, freezeAuthorityRisk, "freeze authority is set: holders can be frozen"))
+
+	curve, err := c.curve(ctx, mint)
+	if err != nil {
+		report.add(Check{Name: "bonding_curve", Detail: "bonding curve not checked: " + err.Error()})
+	}
+
+	holders, err := c.src.GetLargestHolders(ctx, mint)
+	if err != nil {
+		report.add(Check{Name: "holders", Detail: "holders not checked: " + err.Error()})
+	} else {
+		report.add(c.concentrationCheck(holders, state.Supply))
+		report.add(liquidityCheck(curve, holders, state.Supply))
+	}
+
+	if curve != nil && !curve.Creator.IsZero() {
+		report.add(c.creatorCheck(ctx, curve.Creator))
+	}
+	return report, nil
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+	r.Score += c.Risk
+}
+
+func authorityCheck(name string, authority *solana.PublicKey, risk int, detail string) Check {
+	if authority == nil {
+		return Check{Name: name, Detail: name + " revoked"}
+	}
+	return Check{Name: name, Risk: risk, Detail: detail}
+}
+
+// concentrationCheck считает долю эмиссии у крупнейших кошельков; пулы, bonding curve
+// и локеры (аккаунты программ) не учитываются.
+func (c *Checker) concentrationCheck(holders []blockchain.TokenHolder, supply uint64) Check {
+	if supply == 0 {
+		return Check{Name: "top_holders", Detail: "top holders not checked: zero supply"}
+	}
+	var wallets []uint64
+	for _, h := range holders {
+		if !h.IsProgramOwned() {
+			wallets = append(wallets, h.Amount)
+		}
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i] > wallets[j] })
+
+	var held uint64
+	for i := 0; i < len(wallets) && i < topHolders; i++ {
+		held += wallets[i]
+	}
+	percent := float64(held) / float64(supply) * 100
+	if c.maxTopHoldersPc > 0 && percent > c.maxTopHoldersPc {
+		return Check{Name: "top_holders", Risk: concentrationRisk,
+			Detail: fmt.Sprintf("top %d wallets hold %.1f%% of supply (limit %.1f%%)", topHolders, percent, c.maxTopHoldersPc)}
+	}
+	return Check{Name: "top_holders", Detail: fmt.Sprintf("top %d wallets hold %.1f%% of supply", topHolders, percent)}
+}
+
+// liquidityCheck проверяет, что ликвидность нельзя вывести: токен торгуется на bonding
+// curve Pump.fun или заметная доля эмиссии лежит в аккаунтах программ (пулы, локеры).
+func liquidityCheck(curve *pumpfun.BondingCurve, holders []blockchain.TokenHolder, supply uint64) Check {
+	if curve != nil && !curve.Complete {
+		return Check{Name: "liquidity", Detail: "liquidity locked in the Pump.fun bonding curve"}
+	}
+	var locked uint64
+	for _, h := range holders {
+		if h.IsProgramOwned() {
+			locked += h.Amount
+		}
+	}
+	percent := 0.0
+	if supply > 0 {
+		percent = float64(locked) / float64(supply) * 100
+	}
+	if percent < minLockedPercent {
+		return Check{Name: "liquidity", Risk: liquidityRisk,
+			Detail: fmt.Sprintf("only %.1f%% of supply is in pools or lockers", percent)}
+	}
+	return Check{Name: "liquidity", Detail: fmt.Sprintf("%.1f%% of supply is in pools or lockers", percent)}
+}
+
+// creatorCheck отмечает создателя без истории: кошелек, заведенный под один запуск.
+func (c *Checker) creatorCheck(ctx context.Context, creator solana.PublicKey) Check {
+	sigs, err := c.src.GetSignaturesForAddress(ctx, creator, freshCreatorTxs)
+	if err != nil {
+		return Check{Name: "creator", Detail: "creator history not checked: " + err.Error()}
+	}
+	if len(sigs) < freshCreatorTxs {
+		return Check{Name: "creator", Risk: creatorRisk,
+			Detail: fmt.Sprintf("creator %s is a fresh wallet (%d transactions)", creator, len(sigs))}
+	}
+	return Check{Name: "creator", Detail: "creator has prior history"}
+}
@@ -0,0 +1,103 @@
+package safety
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rovshanmuradov/solana-bot/internal/blockchain"
+	"github.com/rovshanmuradov/solana-bot/internal/dex/pumpfun"
+)
+
+type fakeSource struct {
+	state      *blockchain.MintState
+	holders    []blockchain.TokenHolder
+	holdersErr error
+	creatorTxs int
+}
+
+func (f *fakeSource) GetMintState(context.Context, solana.PublicKey, solana.PublicKey) (*blockchain.MintState, bool, error) {
+	if f.state == nil {
+		return nil, false, errors.New("mint account not found")
+	}
+	return f.state, false, nil
+}
+
+func (f *fakeSource) GetLargestHolders(context.Context, solana.PublicKey) ([]blockchain.TokenHolder, error) {
+	return f.holders, f.holdersErr
+}
+
+func (f *fakeSource) GetSignaturesForAddress(context.Context, solana.PublicKey, int) ([]*rpc.TransactionSignature, error) {
+	return make([]*rpc.TransactionSignature, f.creatorTxs), nil
+}
+
+// programOwner возвращает адрес PDA (вне кривой), как у пула или bonding curve
+func programOwner(t *testing.T) solana.PublicKey {
+	pda, _, err := solana.FindProgramAddress([][]byte{[]byte("pool")}, pumpfun.PumpFunProgramID)
+	require.NoError(t, err)
+	return pda
+}
+
+func newTestChecker(src *fakeSource, curve *pumpfun.BondingCurve) *Checker {
+	return &Checker{
+		src:             src,
+		curve:           func(context.Context, solana.PublicKey) (*pumpfun.BondingCurve, error) { return curve, nil },
+		maxTopHoldersPc: 30,
+	}
+}
+
+func TestChecker_SafePumpFunToken(t *testing.T) {
+	creator := solana.NewWallet().PublicKey()
+	src := &fakeSource{
+		state: &blockchain.MintState{Supply: 1_000_000},
+		holders: []blockchain.TokenHolder{
+			{Owner: programOwner(t), Amount: 800_000}, // Bonding curve не считается держателем
+			{Owner: solana.NewWallet().PublicKey(), Amount: 50_000},
+			{Owner: creator, Amount: 20_000},
+		},
+		creatorTxs: 10,
+	}
+	report, err := newTestChecker(src, &pumpfun.BondingCurve{Creator: creator}).Check(context.Background(), solana.NewWallet().PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Score)
+	assert.Equal(t, "no risks found", report.Risks())
+}
+
+func TestChecker_RiskyToken(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+	creator := solana.NewWallet().PublicKey()
+	src := &fakeSource{
+		state: &blockchain.MintState{MintAuthority: &authority, FreezeAuthority: &authority, Supply: 1_000_000},
+		holders: []blockchain.TokenHolder{
+			{Owner: creator, Amount: 600_000},
+			{Owner: solana.NewWallet().PublicKey(), Amount: 300_000},
+			{Owner: programOwner(t), Amount: 10_000},
+		},
+		creatorTxs: 2,
+	}
+	// Токен ушел с bonding curve, а в пулах почти нет ликвидности
+	curve := &pumpfun.BondingCurve{Complete: true, Creator: creator}
+	report, err := newTestChecker(src, curve).Check(context.Background(), solana.NewWallet().PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, 100, report.Score)
+	assert.Contains(t, report.Risks(), "mint authority is set")
+	assert.Contains(t, report.Risks(), "top 10 wallets hold 90.0% of supply (limit 30.0%)")
+	assert.Contains(t, report.Risks(), "only 1.0% of supply is in pools or lockers")
+	assert.Contains(t, report.Risks(), "is a fresh wallet (2 transactions)")
+}
+
+func TestChecker_PartialData(t *testing.T) {
+	src := &fakeSource{state: &blockchain.MintState{Supply: 1_000}, holdersErr: errors.New("rate limited")}
+	report, err := newTestChecker(src, nil).Check(context.Background(), solana.NewWallet().PublicKey())
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Score, "failed lookups do not add risk")
+	assert.Equal(t, "holders not checked: rate limited", report.Checks[2].Detail)
+
+	_, err = newTestChecker(&fakeSource{}, nil).Check(context.Background(), solana.NewWallet().PublicKey())
+	assert.EqualError(t, err, "mint account not found")
+}
@@ -0,0 +1,249 @@
+// internal/storage/positions.go
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+)
+
+// DefaultPositionsPath — журнал открытых позиций (JSON Lines).
+const DefaultPositionsPath = "logs/positions.jsonl"
+
+// Buy — покупка, вошедшая в позицию.
+type Buy struct {
+	Task      string    `json:"task"`
+	AmountSol float64   `json:"amount_sol"`
+	Tokens    uint64    `json:"tokens"` // Куплено токенов (raw)
+	At        time.Time `json:"at"`
+}
+
+// Position — снимок открытой позиции. Task — задача, открывшая позицию: по ней
+// мониторинг продолжается после перезапуска без повторной покупки.
+type Position struct {
+	Mint         string    `json:"mint"`
+	Wallet       string    `json:"wallet"`
+	Task         task.Task `json:"task"`
+	Buys         []Buy     `json:"buys,omitempty"`
+	EntryPrice   float64   `json:"entry_price,omitempty"`   // Средневзвешенная цена входа, SOL за токен
+	TokenBalance uint64    `json:"token_balance,omitempty"` // Баланс токена кошелька при последнем сохранении (raw)
+	Closed       bool      `json:"closed,omitempty"`
+	At           time.Time `json:"at"`
+}
+
+// Key возвращает ключ позиции: на токен кошелька приходится одна позиция.
+func (p Position) Key() string {
+	return PositionKey(p.Wallet, p.Mint)
+}
+
+// PositionKey строит ключ позиции кошелька по токену.
+func PositionKey(wallet, mint string) string {
+	return wallet + "/" + mint
+}
+
+// Invested возвращает суммарно вложенные в позицию SOL.
+func (p Position) Invested() float64 {
+	total := 0.0
+	for _, b := range p.Buys {
+		total += b.AmountSol
+	}
+	return total
+}
+
+// Positions — журнал открытых позиций: каждое сохранение дописывает снимок позиции,
+// закрытие — событие закрытия; состояние позиции — последняя запись с ее ключом.
+// Все методы безопасны для nil.
+type Positions struct {
+	mu     sync.Mutex
+	path   string
+	cipher *task.JournalCipher // Шифр строк журнала (nil — открытый текст)
+}
+
+// NewPositions создает журнал по указанному пути.
+func NewPositions(path string) *Positions {
+	return &Positions{path: path}
+}
+
+// SetCipher включает шифрование строк журнала шифром c; вызывается до первой записи.
+func (s *Positions) SetCipher(c *task.JournalCipher) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cipher = c
+}
+
+// Save записывает снимок позиции и сбрасывает его на диск до возврата.
+func (s *Positions) Save(p Position) error {
+	if s == nil {
+		return nil
+	}
+	p.Closed = false
+	if p.At.IsZero() {
+		p.At = time.Now()
+	}
+	return s.append(p)
+}
+
+// Close отмечает позицию кошелька по токену закрытой.
+func (s *Positions) Close(wallet, mint string) error {
+	if s == nil {
+		return nil
+	}
+	return s.append(Position{Mint: mint, Wallet: wallet, Closed: true, At: time.Now()})
+}
+
+// Open возвращает открытые позиции в порядке открытия.
+func (s *Positions) Open() ([]Position, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Compact переписывает журнал, оставляя только открытые позиции.
+// Вызывается при запуске, до начала торговли.
+func (s *Positions) Compact() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	open, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create position journal: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	for _, p := range open {
+		line, err := s.line(p)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := w.Write(line); err != nil {
+			f.Close()
+			return fmt.Errorf("write position: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("write position journal: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("sync position journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close position journal: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replace position journal: %w", err)
+	}
+	return nil
+}
+
+// append дописывает запись и сбрасывает файл на диск.
+func (s *Positions) append(p Position) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create position journal dir: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open position journal: %w", err)
+	}
+	defer f.Close()
+
+	line, err := s.line(p)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("write position: %w", err)
+	}
+	return f.Sync()
+}
+
+// line кодирует позицию строкой журнала с переводом строки, шифруя ее, если задан шифр.
+func (s *Positions) line(p Position) ([]byte, error) {
+	line, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal position: %w", err)
+	}
+	if line, err = s.cipher.Seal(line); err != nil {
+		return nil, fmt.Errorf("encrypt position: %w", err)
+	}
+	return append(line, '\n'), nil
+}
+
+// load сворачивает журнал в последнее состояние каждой позиции и возвращает
+// открытые; вызывается под блокировкой. Поврежденные строки пропускаются, а
+// зашифрованные без шифра дают ошибку: иначе позиции молча пропали бы.
+func (s *Positions) load() ([]Position, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open position journal: %w", err)
+	}
+	defer f.Close()
+
+	seq := 0
+	opened := make(map[string]int) // Порядковый номер открытия позиции
+	latest := make(map[string]Position)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		data, err := s.cipher.Open(scanner.Bytes())
+		if errors.Is(err, task.ErrJournalLocked) {
+			return nil, fmt.Errorf("read position journal: %w", err)
+		}
+		var p Position
+		if err != nil || json.Unmarshal(data, &p) != nil || p.Mint == "" {
+			continue
+		}
+		key := p.Key()
+		if prev, ok := latest[key]; !ok || prev.Closed {
+			// Закрытая позиция, открытая заново, встает в конец
+			seq++
+			opened[key] = seq
+		}
+		latest[key] = p
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read position journal: %w", err)
+	}
+
+	var open []Position
+	for _, p := range latest {
+		if !p.Closed {
+			open = append(open, p)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool { return opened[open[i].Key()] < opened[open[j].Key()] })
+	return open, nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositions_Lifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.jsonl")
+	store := NewPositions(path)
+	at := time.Unix(1_700_000_000, 0).UTC()
+
+	snipe := task.Task{
+		TaskName:         "snipe-1",
+		Module:           "snipe",
+		WalletName:       "main",
+		Operation:        task.OperationSnipe,
+		AmountSol:        0.1,
+		TokenMint:        "MintA",
+		StopLossPercent:  20,
+		MarketCapTargets: []task.MarketCapTarget{{Percent: 50, MarketCap: 100_000}},
+	}
+	a := Position{Mint: "MintA", Wallet: "main", Task: snipe, EntryPrice: 0.0001, TokenBalance: 1_000_000_000,
+		Buys: []Buy{{Task: "snipe-1", AmountSol: 0.1, Tokens: 1_000_000_000, At: at}}}
+	require.NoError(t, store.Save(a))
+	require.NoError(t, store.Save(Position{Mint: "MintB", Wallet: "main", Task: task.Task{TaskName: "swap-1"}}))
+
+	// Повторная покупка обновляет снимок позиции
+	a.Buys = append(a.Buys, Buy{Task: "snipe-2", AmountSol: 0.05, Tokens: 250_000_000, At: at})
+	a.TokenBalance = 1_250_000_000
+	require.NoError(t, store.Save(a))
+
+	open, err := store.Open()
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	assert.Equal(t, "MintA", open[0].Mint)
+	assert.InDelta(t, 0.15, open[0].Invested(), 1e-9)
+	assert.Equal(t, uint64(1_250_000_000), open[0].TokenBalance)
+	assert.Equal(t, snipe, open[0].Task, "the task survives the round trip")
+
+	// Закрытая позиция, открытая заново, встает в конец
+	require.NoError(t, store.Close("main", "MintA"))
+	open, err = store.Open()
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, "MintB", open[0].Mint)
+
+	require.NoError(t, store.Save(a))
+	open, err = store.Open()
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	assert.Equal(t, "MintA", open[1].Mint)
+
+	require.NoError(t, store.Compact())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"), "compaction keeps one line per open position")
+	compacted, err := store.Open()
+	require.NoError(t, err)
+	assert.Equal(t, open, compacted)
+}
+
+func TestPositions_Nil(t *testing.T) {
+	var store *Positions
+	assert.NoError(t, store.Save(Position{Mint: "Mint"}))
+	assert.NoError(t, store.Close("main", "Mint"))
+	assert.NoError(t, store.Compact())
+	open, err := store.Open()
+	assert.NoError(t, err)
+	assert.Empty(t, open)
+}
+
+func TestPositions_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "positions.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"mint":"MintA","wallet":"main","at":"2025-01-01T00:00:00Z"}`+"\n"), 0o600))
+	c, err := task.OpenJournalCipher(filepath.Join(dir, "journal.key"), []byte("secret"))
+	require.NoError(t, err)
+
+	store := NewPositions(path)
+	store.SetCipher(c)
+	require.NoError(t, store.Save(Position{Mint: "MintB", Wallet: "main"}))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "MintB", "new lines are encrypted")
+
+	require.NoError(t, store.Compact())
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Mint", "compaction encrypts the plaintext lines too")
+	open, err := store.Open()
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	assert.Equal(t, []string{"MintA", "MintB"}, []string{open[0].Mint, open[1].Mint})
+
+	_, err = NewPositions(path).Open()
+	assert.ErrorIs(t, err, task.ErrJournalLocked, "encrypted positions must not silently disappear")
+}
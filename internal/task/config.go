@@ -5,25 +5,248 @@ package task
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/rovshanmuradov/solana-bot/internal/audit"
 	"github.com/spf13/viper"
 )
 
+// AlertSinkConfig describes one alert channel from alert_sinks.
+type AlertSinkConfig struct {
+	Name        string   `mapstructure:"name"`         // Unique channel name (default: the type)
+	Type        string   `mapstructure:"type"`         // webhook, telegram or email
+	URL         string   `mapstructure:"url"`          // webhook: Discord-compatible webhook URL
+	BotToken    string   `mapstructure:"bot_token"`    // telegram
+	ChatID      string   `mapstructure:"chat_id"`      // telegram
+	SMTPAddr    string   `mapstructure:"smtp_addr"`    // email: SMTP server host:port
+	Username    string   `mapstructure:"username"`     // email: SMTP login (empty = no auth)
+	Password    string   `mapstructure:"password"`     // email
+	From        string   `mapstructure:"from"`         // email
+	To          []string `mapstructure:"to"`           // email
+	AlertTypes  []string `mapstructure:"alert_types"`  // Deliver only these alert types (empty = all)
+	MinSeverity string   `mapstructure:"min_severity"` // info (default), warning or critical
+}
+
+// LocalRPCClient is one named client of the local JSON-RPC from local_rpc_clients.
+type LocalRPCClient struct {
+	Name   string `mapstructure:"name"`    // Shown in the audit log and the local RPC logs
+	APIKey string `mapstructure:"api_key"` // Key the client sends with authenticate
+	Role   string `mapstructure:"role"`    // viewer, trader or admin
+}
+
+// RPCRateLimitConfig overrides rpc_rate_limit and rpc_burst for one rpc_list endpoint.
+type RPCRateLimitConfig struct {
+	URL   string  `mapstructure:"url"`   // Endpoint exactly as written in rpc_list
+	RPS   float64 `mapstructure:"rps"`   // Requests per second
+	Burst int     `mapstructure:"burst"` // Requests that may go back to back (0 = rps rounded up)
+}
+
+// CopyTradeConfig describes one wallet from copy_trading whose Pump.fun buys are mirrored.
+type CopyTradeConfig struct {
+	Wallet    string   `mapstructure:"wallet"`    // Address of the wallet to follow
+	Task      string   `mapstructure:"task"`      // tasks.csv row with token_mint "copy" that places the mirrored buys
+	Ratio     float64  `mapstructure:"ratio"`     // Share of the followed buy's SOL amount to spend (default 1)
+	MaxSol    float64  `mapstructure:"max_sol"`   // Max SOL invested per token copied from this wallet (0 = no cap)
+	Blacklist []string `mapstructure:"blacklist"` // Token mints never to copy
+}
+
 // Config holds application settings loaded from config.json.
 type Config struct {
-	License      string        `mapstructure:"license"`
-	RPCList      []string      `mapstructure:"rpc_list"`
-	WebSocketURL string        `mapstructure:"websocket_url"`
-	MonitorDelay time.Duration `mapstructure:"-"` // Converted from monitor_delay (ms)
-	RPCDelay     time.Duration `mapstructure:"-"` // Converted from rpc_delay (ms)
-	PriceDelay   time.Duration `mapstructure:"-"` // Converted from price_delay (ms)
-	DebugLogging bool          `mapstructure:"debug_logging"`
-	TPSLogging   bool          `mapstructure:"tps_logging"`
-	Retries      int           `mapstructure:"retries"`
-	WebhookURL   string        `mapstructure:"webhook_url"`
-	Workers      int           `mapstructure:"workers"`
+	License      string            `mapstructure:"license"`
+	RPCList      []string          `mapstructure:"rpc_list"`
+	RPCEndpoints map[string]string `mapstructure:"rpc_endpoints"` // Named endpoints tasks can pick with the rpc column
+	WebSocketURL string            `mapstructure:"websocket_url"`
+	MonitorDelay time.Duration     `mapstructure:"-"` // Converted from monitor_delay (ms)
+	RPCDelay     time.Duration     `mapstructure:"-"` // Converted from rpc_delay (ms)
+	PriceDelay   time.Duration     `mapstructure:"-"` // Converted from price_delay (ms)
+	DebugLogging bool              `mapstructure:"debug_logging"`
+	TPSLogging   bool              `mapstructure:"tps_logging"`
+	Retries      int               `mapstructure:"retries"`
+	WebhookURL   string            `mapstructure:"webhook_url"`
+	Workers      int               `mapstructure:"workers"`
+	InstancePort int               `mapstructure:"instance_port"` // Loopback port used as the single-instance lock
+	ReadOnly     bool              `mapstructure:"-"`             // Set by -read-only: observe only, never trade
+	Headless     bool              `mapstructure:"-"`             // Set by -headless: no operator, fail fast on startup problems
+	Portfolio    bool              `mapstructure:"-"`             // Set by -portfolio: show wallet balances and holdings, never trade
+	CloseEmpty   bool              `mapstructure:"-"`             // Set by -close-empty-accounts: close empty token accounts to reclaim rent, never trade
+	DryRun       bool              `mapstructure:"-"`             // Set by -dry-run: with -close-empty-accounts, only list what would be closed
+	MetricsAddr  string            `mapstructure:"metrics_addr"`  // Prometheus /metrics listen address (empty = disabled)
+
+	// Record price snapshots of monitored tokens to logs/market-<day>.jsonl.gz for -backtest
+	RecordMarketData bool `mapstructure:"record_market_data"`
+
+	// Alert delivery tuning
+	AlertDedupeWindow    time.Duration `mapstructure:"-"`                // Converted from alert_dedupe_window (ms)
+	AlertAggregateWindow time.Duration `mapstructure:"-"`                // Converted from alert_aggregate_window (ms)
+	AlertRateLimit       int           `mapstructure:"alert_rate_limit"` // Max alerts per minute per channel
+	AlertMaxAge          time.Duration `mapstructure:"-"`                // Converted from alert_max_age (ms)
+
+	// Telegram bot for alerts and chat commands (/positions, /sell); only telegram_chat_id may use the commands
+	TelegramBotToken string `mapstructure:"telegram_bot_token"`
+	TelegramChatID   string `mapstructure:"telegram_chat_id"`
+	// Role of each Telegram user ID (viewer, trader or admin); empty = every member of telegram_chat_id is admin
+	TelegramRoles map[string]string `mapstructure:"telegram_roles"`
+
+	// Extra alert channels, each with its own alert type and severity filter
+	AlertSinks []AlertSinkConfig `mapstructure:"alert_sinks"`
+
+	// RPC pool over rpc_list: routing, health checks and failover
+	RPCRouting             string        `mapstructure:"rpc_routing"` // "failover" (rpc_list order) or "latency" (fastest healthy endpoint)
+	RPCHealthCheckInterval time.Duration `mapstructure:"-"`           // Converted from rpc_health_check_interval (ms; 0 = off)
+	RPCFailureCooldown     time.Duration `mapstructure:"-"`           // Converted from rpc_failure_cooldown (ms)
+	RPCBatchWindow         time.Duration `mapstructure:"-"`           // Converted from rpc_batch_window (ms; 0 = off)
+
+	// Token bucket per rpc_list endpoint, with per-endpoint overrides
+	RPCRateLimit  float64              `mapstructure:"rpc_rate_limit"`  // Requests per second per endpoint (0 = unlimited)
+	RPCBurst      int                  `mapstructure:"rpc_burst"`       // Requests that may go back to back (0 = rpc_rate_limit rounded up)
+	RPCRateLimits []RPCRateLimitConfig `mapstructure:"rpc_rate_limits"` // Per-endpoint limits
+
+	// Optional Yellowstone gRPC (Geyser) stream for the new-token listener, copy trading and price monitoring
+	GeyserEndpoint string `mapstructure:"geyser_endpoint"` // https://host:port (empty = WebSocket and polling only)
+	GeyserToken    string `mapstructure:"geyser_token"`    // x-token sent with every subscription
+
+	// Warn when a buy is sent later than this after the task starts (latency_budget, ms; 0 = off)
+	LatencyBudget time.Duration `mapstructure:"-"`
+
+	// Refuse to buy Token-2022 mints whose transfer fee exceeds this many basis points (0 = no limit)
+	MaxTransferFeeBps int `mapstructure:"max_transfer_fee_bps"`
+
+	// Score tokens before buying (0-100, higher = riskier) and act when the score exceeds safety_max_risk (0 = off)
+	SafetyMaxRisk       int     `mapstructure:"safety_max_risk"`
+	SafetyAction        string  `mapstructure:"safety_action"`          // "abort" the buy or only "warn"
+	SafetyMaxTopHolders float64 `mapstructure:"safety_max_top_holders"` // % of supply the 10 largest wallets may hold
+
+	// Priority fee suggestions for the "auto" fee mode
+	PriorityFeeSource string `mapstructure:"priority_fee_source"` // rpc, helius or triton
+	PriorityFeeURL    string `mapstructure:"priority_fee_url"`    // Provider RPC URL; defaults to the primary RPC
+
+	// Address lookup table for PumpSwap v0 transactions: empty (off), "auto" or a table address
+	PumpSwapLookupTable string `mapstructure:"pumpswap_lookup_table"`
+
+	// Jupiter Swap API for the "jupiter" module (empty = the public lite-api.jup.ag endpoint)
+	JupiterAPIURL string `mapstructure:"jupiter_api_url"`
+
+	// Sell with the slippage learned from past sells of the token instead of the task setting
+	ApplyLearnedSlippage bool `mapstructure:"apply_learned_slippage"`
+
+	// Rebroadcast an unconfirmed transaction with a higher compute unit price
+	RebroadcastInterval       time.Duration `mapstructure:"-"`                            // Converted from rebroadcast_interval (ms; 0 = off)
+	RebroadcastFeeStepPercent float64       `mapstructure:"rebroadcast_fee_step_percent"` // CU price increase per rebroadcast
+	RebroadcastMaxCUPrice     uint64        `mapstructure:"rebroadcast_max_cu_price"`     // CU price cap in micro-lamports (0 = no cap)
+	RebroadcastMaxAttempts    int           `mapstructure:"rebroadcast_max_attempts"`     // Max rebroadcasts per transaction
+
+	// Time to keep re-signing with a fresh blockhash a transaction whose blockhash expired (resubmit_deadline, ms; 0 = off)
+	ResubmitDeadline time.Duration `mapstructure:"-"`
+
+	// Simulate Pump.fun and Pump.swap transactions before sending and skip the ones that would fail
+	SimulateBeforeSend bool `mapstructure:"simulate_before_send"`
+
+	// Alert when a monitored position's RSI reaches this level while the price stalls (0 = off)
+	IndicatorRSIAlert float64 `mapstructure:"indicator_rsi_alert"`
+
+	// Read-only JSON-RPC for scripts: "127.0.0.1:47822" or "unix:/path/bot.sock" (empty = disabled)
+	LocalRPCAddr string `mapstructure:"local_rpc_addr"`
+	// Key clients must send with authenticate before other local JSON-RPC methods (empty = no auth)
+	LocalRPCAPIKey string `mapstructure:"local_rpc_api_key"`
+	// Named local JSON-RPC clients with their own keys and roles; local_rpc_api_key acts as an admin client "default"
+	LocalRPCClients []LocalRPCClient `mapstructure:"local_rpc_clients"`
+
+	// Sell the whole balance when a percent sell would leave less than this share of it (0 = off)
+	SweepDustPercent float64 `mapstructure:"sweep_dust_percent"`
+
+	// Commitment a trade must reach before it is reported as successful: processed (default), confirmed or finalized
+	ConfirmCommitment string `mapstructure:"confirm_commitment"`
+
+	// Refresh the cached recent blockhash in the background so sends never wait for it
+	BlockhashRefresh time.Duration `mapstructure:"-"` // Converted from blockhash_refresh (ms; 0 = off)
+
+	// Fee sponsorship service that pays fees for wallets without a group fee_payer (empty = off)
+	FeeSponsorURL string `mapstructure:"fee_sponsor_url"`
+
+	// Time budget for a whole trade: quote, build, send and confirm (trade_deadline, ms; 0 = per-stage timeouts)
+	TradeDeadline time.Duration `mapstructure:"-"`
+
+	// How often the -portfolio screen refreshes balances and prices (portfolio_refresh, ms; 0 = show once)
+	PortfolioRefresh time.Duration `mapstructure:"-"`
+
+	// Structured JSON logs in logs/<component>.jsonl (trade, rpc, monitor, bot), rotated by size
+	LogJSON      bool `mapstructure:"log_json"`
+	LogMaxSizeMB int  `mapstructure:"log_max_size_mb"` // Size a log file reaches before it rotates (0 = never)
+	LogMaxFiles  int  `mapstructure:"log_max_files"`   // Rotated copies kept per component
+
+	// Lines of recent logs shown under the position monitor; resized with +/- (0 = off)
+	LogPaneLines int `mapstructure:"log_pane_lines"`
+
+	// Encrypt logs/positions.jsonl and logs/executions.jsonl with a key derived from the keystore password
+	EncryptJournals bool `mapstructure:"encrypt_journals"`
+
+	// Drop archived monitoring sessions closed more than this many days ago (0 = keep all)
+	SessionRetentionDays int `mapstructure:"session_retention_days"`
+
+	// Poll the mint of held tokens for authority changes, supply inflation and freezes (mint_watch_interval, ms; 0 = off)
+	MintWatchInterval time.Duration `mapstructure:"-"`
+	MintWatchAction   string        `mapstructure:"mint_watch_action"` // "alert" or "sell" the whole position
+
+	// Anti-rug sentinel: poll price, liquidity and mint authorities of held tokens every
+	// rug_sentinel_interval (ms; 0 = off) and sell the whole position at once when a single poll shows a rug
+	RugSentinelInterval     time.Duration `mapstructure:"-"`
+	RugPriceDropPercent     float64       `mapstructure:"rug_price_drop_percent"`     // Price fall within one poll that counts as a collapse (0 = not checked)
+	RugLiquidityDropPercent float64       `mapstructure:"rug_liquidity_drop_percent"` // Pool SOL or LP supply fall within one poll that counts as removal (0 = not checked)
+	RugPriorityFee          string        `mapstructure:"rug_priority_fee"`           // Priority fee of the exit sell, SOL
+	RugSlippagePercent      float64       `mapstructure:"rug_slippage_percent"`       // Slippage of the exit sell
+
+	// Filters for new Pump.fun tokens sniped by tasks.csv rows whose token_mint is "new"
+	SniperCreators      []string `mapstructure:"sniper_creators"`        // Only tokens created by these addresses (empty = any)
+	SniperKeywords      []string `mapstructure:"sniper_keywords"`        // Only tokens whose name or symbol contains one of these (empty = any)
+	SniperMinInitialBuy float64  `mapstructure:"sniper_min_initial_buy"` // Creator's buy in the create transaction, SOL (0 = no minimum)
+	SniperMaxInitialBuy float64  `mapstructure:"sniper_max_initial_buy"` // Creator's buy in the create transaction, SOL (0 = no maximum)
+	SniperMaxTokens     int      `mapstructure:"sniper_max_tokens"`      // Stop listening after sniping this many tokens (0 = no limit)
+
+	// Further new-token filters, checked after the ones above and before filters registered with launch.Register
+	SniperNameRegex     string        `mapstructure:"sniper_name_regex"` // Only tokens whose name or symbol matches this regular expression (empty = any)
+	SniperMinCreatorAge time.Duration `mapstructure:"-"`                 // sniper_min_creator_age, ms since the creator's first transaction (0 = any creator)
+
+	// Wallets whose Pump.fun buys are mirrored with tasks.csv rows whose token_mint is "copy"
+	CopyTrading []CopyTradeConfig `mapstructure:"copy_trading"`
+
+	// Default automatic exits of monitored positions in percent; tasks override them (0 = off)
+	StopLossPercent     float64 `mapstructure:"stop_loss_percent"`     // Sell when PnL falls this much
+	TakeProfitPercent   float64 `mapstructure:"take_profit_percent"`   // Sell when PnL rises this much
+	TrailingStopPercent float64 `mapstructure:"trailing_stop_percent"` // Sell when price falls this much from its session high
+
+	// Stop-loss stays off this long after the buy while the launch price settles; take-profit still fires
+	StopLossWarmup time.Duration `mapstructure:"-"` // stop_loss_warmup, ms (0 = no warm-up)
+
+	// Pump.fun trades of monitored tokens streamed from geyser_endpoint or websocket_url
+	OrderFlowMinSol  float64 `mapstructure:"order_flow_min_sol"`  // Show trades by other wallets of at least this size, SOL (0 = off)
+	OrderFlowExitSol float64 `mapstructure:"order_flow_exit_sol"` // Sell the position when another wallet sells at least this much in one trade, SOL (0 = off)
+
+	// Named multi-step exit plans that tasks pick with the exit_plan column (names are lowercase)
+	ExitPlans map[string][]ExitTier `mapstructure:"exit_plans"`
+
+	// Ask the operator before trading approval_above_sol SOL or more (0 = never ask)
+	ApprovalAboveSol      float64       `mapstructure:"approval_above_sol"`
+	ApprovalTimeout       time.Duration `mapstructure:"-"`                       // approval_timeout, ms to wait for an answer
+	ApprovalTimeoutAction string        `mapstructure:"approval_timeout_action"` // "reject" or "approve" when nobody answers
+
+	// Stop new buys for the rest of the day once the day's realized plus unrealized PnL
+	// falls to -daily_loss_limit_sol SOL; sells still run (0 = no limit)
+	DailyLossLimitSol float64 `mapstructure:"daily_loss_limit_sol"`
+
+	// Position size limits checked before every buy (0 = no limit)
+	MaxTokenSol      float64 `mapstructure:"max_token_sol"`      // SOL invested in one token across all wallets
+	MaxOpenPositions int     `mapstructure:"max_open_positions"` // Open positions (wallet + token) at once
+	MaxExposureSol   float64 `mapstructure:"max_exposure_sol"`   // SOL invested in all open positions
+
+	// Refuse a second buy of the same token from the same wallet within buy_cooldown
+	// (ms; 0 = off) unless the task sets allow_rebuy
+	BuyCooldown time.Duration `mapstructure:"-"`
+
+	// SOL price in USD used to turn "$" market cap targets into price triggers (0 = SOL targets only)
+	SOLUSDPrice float64 `mapstructure:"sol_usd_price"`
 
 	// Keygen.sh configuration
 	KeygenAccountID    string `mapstructure:"keygen_account_id"`
@@ -44,6 +267,35 @@ func LoadConfig(path string) (*Config, error) {
 	v.SetDefault("rpc_delay", 100)
 	v.SetDefault("retries", 3)
 	v.SetDefault("workers", 1)
+	v.SetDefault("instance_port", 47821)
+	v.SetDefault("rpc_routing", "failover")
+	v.SetDefault("rpc_health_check_interval", 10000)
+	v.SetDefault("rpc_failure_cooldown", 30000)
+	v.SetDefault("rpc_batch_window", 5)
+	v.SetDefault("alert_dedupe_window", 60000)
+	v.SetDefault("alert_aggregate_window", 60000)
+	v.SetDefault("alert_rate_limit", 20)
+	v.SetDefault("alert_max_age", 1800000)
+	v.SetDefault("priority_fee_source", "rpc")
+	v.SetDefault("rebroadcast_fee_step_percent", 50)
+	v.SetDefault("rebroadcast_max_attempts", 5)
+	v.SetDefault("resubmit_deadline", 120000)
+	v.SetDefault("blockhash_refresh", 400)
+	v.SetDefault("mint_watch_interval", 5000)
+	v.SetDefault("mint_watch_action", "alert")
+	v.SetDefault("rug_price_drop_percent", 50)
+	v.SetDefault("rug_liquidity_drop_percent", 50)
+	v.SetDefault("rug_priority_fee", "0.001")
+	v.SetDefault("rug_slippage_percent", 50)
+	v.SetDefault("safety_action", "abort")
+	v.SetDefault("safety_max_top_holders", 30)
+	v.SetDefault("approval_timeout", 30000)
+	v.SetDefault("approval_timeout_action", "reject")
+	v.SetDefault("trade_deadline", 60000)
+	v.SetDefault("log_pane_lines", 6)
+	v.SetDefault("log_max_size_mb", 10)
+	v.SetDefault("log_max_files", 5)
+	v.SetDefault("portfolio_refresh", 30000)
 
 	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("read config error: %w", err)
@@ -58,6 +310,24 @@ func LoadConfig(path string) (*Config, error) {
 	cfg.MonitorDelay = time.Duration(v.GetInt("monitor_delay")) * time.Millisecond
 	cfg.RPCDelay = time.Duration(v.GetInt("rpc_delay")) * time.Millisecond
 	cfg.PriceDelay = time.Duration(v.GetInt("price_delay")) * time.Millisecond
+	cfg.LatencyBudget = time.Duration(v.GetInt("latency_budget")) * time.Millisecond
+	cfg.AlertDedupeWindow = time.Duration(v.GetInt("alert_dedupe_window")) * time.Millisecond
+	cfg.AlertAggregateWindow = time.Duration(v.GetInt("alert_aggregate_window")) * time.Millisecond
+	cfg.AlertMaxAge = time.Duration(v.GetInt("alert_max_age")) * time.Millisecond
+	cfg.RebroadcastInterval = time.Duration(v.GetInt("rebroadcast_interval")) * time.Millisecond
+	cfg.ResubmitDeadline = time.Duration(v.GetInt("resubmit_deadline")) * time.Millisecond
+	cfg.BlockhashRefresh = time.Duration(v.GetInt("blockhash_refresh")) * time.Millisecond
+	cfg.MintWatchInterval = time.Duration(v.GetInt("mint_watch_interval")) * time.Millisecond
+	cfg.RugSentinelInterval = time.Duration(v.GetInt("rug_sentinel_interval")) * time.Millisecond
+	cfg.TradeDeadline = time.Duration(v.GetInt("trade_deadline")) * time.Millisecond
+	cfg.PortfolioRefresh = time.Duration(v.GetInt("portfolio_refresh")) * time.Millisecond
+	cfg.ApprovalTimeout = time.Duration(v.GetInt("approval_timeout")) * time.Millisecond
+	cfg.RPCHealthCheckInterval = time.Duration(v.GetInt("rpc_health_check_interval")) * time.Millisecond
+	cfg.RPCFailureCooldown = time.Duration(v.GetInt("rpc_failure_cooldown")) * time.Millisecond
+	cfg.RPCBatchWindow = time.Duration(v.GetInt("rpc_batch_window")) * time.Millisecond
+	cfg.BuyCooldown = time.Duration(v.GetInt("buy_cooldown")) * time.Millisecond
+	cfg.StopLossWarmup = time.Duration(v.GetInt("stop_loss_warmup")) * time.Millisecond
+	cfg.SniperMinCreatorAge = time.Duration(v.GetInt("sniper_min_creator_age")) * time.Millisecond
 
 	// Apply fallback RPC endpoints if needed
 	cfg.applyRPCFallbacks()
@@ -71,16 +341,283 @@ func LoadConfig(path string) (*Config, error) {
 }
 
 // validate checks required fields and applies defaults if necessary.
+// validateAlertSinks checks alert_sinks: required fields per type, filters and unique names.
+func (c *Config) validateAlertSinks() error {
+	names := make(map[string]bool)
+	if c.WebhookURL != "" {
+		names["webhook"] = true
+	}
+	if c.TelegramBotToken != "" {
+		names["telegram"] = true
+	}
+	for i := range c.AlertSinks {
+		s := &c.AlertSinks[i]
+		if s.Name == "" {
+			s.Name = s.Type
+		}
+		if names[s.Name] {
+			return fmt.Errorf("alert_sinks: duplicate channel name %q, set a unique name", s.Name)
+		}
+		names[s.Name] = true
+
+		switch s.Type {
+		case "webhook":
+			if s.URL == "" {
+				return fmt.Errorf("alert_sinks %s: url is required", s.Name)
+			}
+		case "telegram":
+			if s.BotToken == "" {
+				return fmt.Errorf("alert_sinks %s: bot_token is required", s.Name)
+			}
+			if _, err := strconv.ParseInt(s.ChatID, 10, 64); err != nil {
+				return fmt.Errorf("alert_sinks %s: chat_id must be a numeric chat ID", s.Name)
+			}
+		case "email":
+			if s.SMTPAddr == "" || s.From == "" || len(s.To) == 0 {
+				return fmt.Errorf("alert_sinks %s: smtp_addr, from and to are required", s.Name)
+			}
+		default:
+			return fmt.Errorf("alert_sinks %s: type must be webhook, telegram or email (got %q)", s.Name, s.Type)
+		}
+
+		switch s.MinSeverity {
+		case "", "info", "warning", "critical":
+		default:
+			return fmt.Errorf("alert_sinks %s: min_severity must be info, warning or critical", s.Name)
+		}
+	}
+	return nil
+}
+
+// validateRoles checks local_rpc_clients and telegram_roles: known roles, unique client names and keys.
+func (c *Config) validateRoles() error {
+	names := make(map[string]bool)
+	keys := make(map[string]bool)
+	if c.LocalRPCAPIKey != "" {
+		names["default"], keys[c.LocalRPCAPIKey] = true, true
+	}
+	for _, client := range c.LocalRPCClients {
+		if client.Name == "" || client.APIKey == "" {
+			return fmt.Errorf("local_rpc_clients: name and api_key are required")
+		}
+		if names[client.Name] {
+			return fmt.Errorf("local_rpc_clients: duplicate client name %q", client.Name)
+		}
+		if keys[client.APIKey] {
+			return fmt.Errorf("local_rpc_clients %s: api_key is already used by another client", client.Name)
+		}
+		names[client.Name], keys[client.APIKey] = true, true
+		if _, err := audit.ParseRole(client.Role); err != nil {
+			return fmt.Errorf("local_rpc_clients %s: %w", client.Name, err)
+		}
+	}
+	for user, role := range c.TelegramRoles {
+		if _, err := strconv.ParseInt(user, 10, 64); err != nil {
+			return fmt.Errorf("telegram_roles: %q is not a numeric Telegram user ID", user)
+		}
+		if _, err := audit.ParseRole(role); err != nil {
+			return fmt.Errorf("telegram_roles %s: %w", user, err)
+		}
+	}
+	return nil
+}
+
 func (c *Config) validate() error {
 	if len(c.RPCList) == 0 {
 		return fmt.Errorf("rpc_list must contain at least one RPC endpoint")
 	}
+	if c.RPCRouting != "failover" && c.RPCRouting != "latency" {
+		return fmt.Errorf("rpc_routing must be \"failover\" or \"latency\"")
+	}
+	if c.RPCHealthCheckInterval < 0 {
+		return fmt.Errorf("rpc_health_check_interval must not be negative")
+	}
+	if c.RPCFailureCooldown <= 0 {
+		return fmt.Errorf("rpc_failure_cooldown must be positive")
+	}
+	if c.RPCBatchWindow < 0 {
+		return fmt.Errorf("rpc_batch_window must not be negative")
+	}
+	if c.GeyserEndpoint != "" && !strings.HasPrefix(c.GeyserEndpoint, "https://") && !strings.HasPrefix(c.GeyserEndpoint, "http://") {
+		return fmt.Errorf("geyser_endpoint must start with https:// or http://")
+	}
+	if c.RPCRateLimit < 0 || c.RPCBurst < 0 {
+		return fmt.Errorf("rpc_rate_limit and rpc_burst must not be negative")
+	}
+	for _, l := range c.RPCRateLimits {
+		found := false
+		for _, u := range c.RPCList {
+			found = found || u == l.URL
+		}
+		for _, u := range c.RPCEndpoints {
+			found = found || u == l.URL
+		}
+		if !found {
+			return fmt.Errorf("rpc_rate_limits: %q is not in rpc_list or rpc_endpoints", l.URL)
+		}
+		if l.RPS <= 0 || l.Burst < 0 {
+			return fmt.Errorf("rpc_rate_limits %q: rps must be positive and burst not negative", l.URL)
+		}
+	}
 	if c.License == "" {
 		return fmt.Errorf("license is required")
 	}
 	if c.WebSocketURL == "" {
 		return fmt.Errorf("websocket_url is required")
 	}
+	if c.TelegramBotToken != "" {
+		if _, err := strconv.ParseInt(c.TelegramChatID, 10, 64); err != nil {
+			return fmt.Errorf("telegram_chat_id must be a numeric chat ID when telegram_bot_token is set")
+		}
+	}
+	if err := c.validateAlertSinks(); err != nil {
+		return err
+	}
+	if err := c.validateRoles(); err != nil {
+		return err
+	}
+
+	switch c.PriorityFeeSource {
+	case "rpc", "helius", "triton":
+	default:
+		return fmt.Errorf("priority_fee_source must be one of rpc, helius, triton (got %q)", c.PriorityFeeSource)
+	}
+
+	if c.RebroadcastFeeStepPercent < 0 {
+		return fmt.Errorf("rebroadcast_fee_step_percent must not be negative")
+	}
+	if c.ResubmitDeadline < 0 {
+		return fmt.Errorf("resubmit_deadline must not be negative")
+	}
+	if c.IndicatorRSIAlert < 0 || c.IndicatorRSIAlert > 100 {
+		return fmt.Errorf("indicator_rsi_alert must be between 0 and 100")
+	}
+	if c.SweepDustPercent < 0 || c.SweepDustPercent >= 100 {
+		return fmt.Errorf("sweep_dust_percent must be between 0 and 100")
+	}
+	switch c.ConfirmCommitment {
+	case "", "processed", "confirmed", "finalized":
+	default:
+		return fmt.Errorf("confirm_commitment must be processed, confirmed or finalized")
+	}
+	if c.BlockhashRefresh < 0 {
+		return fmt.Errorf("blockhash_refresh must not be negative")
+	}
+	if c.TradeDeadline < 0 {
+		return fmt.Errorf("trade_deadline must not be negative")
+	}
+	if c.PortfolioRefresh < 0 {
+		return fmt.Errorf("portfolio_refresh must not be negative")
+	}
+	if c.LogMaxSizeMB < 0 || c.LogMaxFiles < 0 {
+		return fmt.Errorf("log_max_size_mb and log_max_files must not be negative")
+	}
+	if c.LogPaneLines < 0 {
+		return fmt.Errorf("log_pane_lines must not be negative")
+	}
+	if c.SessionRetentionDays < 0 {
+		return fmt.Errorf("session_retention_days must not be negative")
+	}
+	if c.MintWatchAction != "alert" && c.MintWatchAction != "sell" {
+		return fmt.Errorf("mint_watch_action must be \"alert\" or \"sell\"")
+	}
+	if c.RugSentinelInterval < 0 {
+		return fmt.Errorf("rug_sentinel_interval must not be negative")
+	}
+	if c.RugPriceDropPercent < 0 || c.RugPriceDropPercent > 100 ||
+		c.RugLiquidityDropPercent < 0 || c.RugLiquidityDropPercent > 100 {
+		return fmt.Errorf("rug_price_drop_percent and rug_liquidity_drop_percent must be between 0 and 100")
+	}
+	if c.RugSentinelInterval > 0 {
+		if c.RugSlippagePercent <= 0 || c.RugSlippagePercent > 100 {
+			return fmt.Errorf("rug_slippage_percent must be between 0 and 100")
+		}
+		if c.RugPriorityFee == "" {
+			return fmt.Errorf("rug_priority_fee must be set when rug_sentinel_interval is on")
+		}
+	}
+	for name, tiers := range c.ExitPlans {
+		if err := ValidateExitPlan(tiers); err != nil {
+			return fmt.Errorf("exit_plans %s: %w", name, err)
+		}
+	}
+	if c.SafetyMaxRisk < 0 || c.SafetyMaxRisk > 100 {
+		return fmt.Errorf("safety_max_risk must be between 0 and 100")
+	}
+	if c.SafetyAction != "abort" && c.SafetyAction != "warn" {
+		return fmt.Errorf("safety_action must be \"abort\" or \"warn\"")
+	}
+	if c.SafetyMaxTopHolders < 0 || c.SafetyMaxTopHolders > 100 {
+		return fmt.Errorf("safety_max_top_holders must be between 0 and 100")
+	}
+	if c.SniperMinInitialBuy < 0 || c.SniperMaxInitialBuy < 0 {
+		return fmt.Errorf("sniper_min_initial_buy and sniper_max_initial_buy must not be negative")
+	}
+	if c.SniperMaxInitialBuy > 0 && c.SniperMinInitialBuy > c.SniperMaxInitialBuy {
+		return fmt.Errorf("sniper_min_initial_buy must not exceed sniper_max_initial_buy")
+	}
+	if c.SniperMaxTokens < 0 {
+		return fmt.Errorf("sniper_max_tokens must not be negative")
+	}
+	if _, err := regexp.Compile(c.SniperNameRegex); err != nil {
+		return fmt.Errorf("invalid sniper_name_regex: %w", err)
+	}
+	if c.SniperMinCreatorAge < 0 {
+		return fmt.Errorf("sniper_min_creator_age must not be negative")
+	}
+	for i := range c.CopyTrading {
+		ct := &c.CopyTrading[i]
+		if ct.Wallet == "" || ct.Task == "" {
+			return fmt.Errorf("copy_trading: wallet and task are required")
+		}
+		if ct.Ratio == 0 {
+			ct.Ratio = 1
+		}
+		if ct.Ratio < 0 || ct.MaxSol < 0 {
+			return fmt.Errorf("copy_trading %s: ratio and max_sol must not be negative", ct.Wallet)
+		}
+	}
+	if c.StopLossPercent < 0 || c.StopLossPercent >= 100 {
+		return fmt.Errorf("stop_loss_percent must be between 0 and 100")
+	}
+	if c.TrailingStopPercent < 0 || c.TrailingStopPercent >= 100 {
+		return fmt.Errorf("trailing_stop_percent must be between 0 and 100")
+	}
+	if c.TakeProfitPercent < 0 {
+		return fmt.Errorf("take_profit_percent must not be negative")
+	}
+	if c.StopLossWarmup < 0 {
+		return fmt.Errorf("stop_loss_warmup must not be negative")
+	}
+	if c.OrderFlowMinSol < 0 || c.OrderFlowExitSol < 0 {
+		return fmt.Errorf("order_flow_min_sol and order_flow_exit_sol must not be negative")
+	}
+	if c.ApprovalAboveSol < 0 {
+		return fmt.Errorf("approval_above_sol must not be negative")
+	}
+	if c.ApprovalAboveSol > 0 && c.ApprovalTimeout <= 0 {
+		return fmt.Errorf("approval_timeout must be positive when approval_above_sol is set")
+	}
+	if c.ApprovalTimeoutAction != "reject" && c.ApprovalTimeoutAction != "approve" {
+		return fmt.Errorf("approval_timeout_action must be \"reject\" or \"approve\"")
+	}
+	if c.DailyLossLimitSol < 0 {
+		return fmt.Errorf("daily_loss_limit_sol must not be negative")
+	}
+	if c.MaxTokenSol < 0 || c.MaxOpenPositions < 0 || c.MaxExposureSol < 0 {
+		return fmt.Errorf("max_token_sol, max_open_positions and max_exposure_sol must not be negative")
+	}
+	if c.BuyCooldown < 0 {
+		return fmt.Errorf("buy_cooldown must not be negative")
+	}
+	for name, endpoint := range c.RPCEndpoints {
+		if !isRPCURL(endpoint) {
+			return fmt.Errorf("rpc_endpoints.%s must be an http(s) URL", name)
+		}
+	}
+	if c.SOLUSDPrice < 0 {
+		return fmt.Errorf("sol_usd_price must not be negative")
+	}
 
 	// Keygen validation is optional - hardcoded fallbacks available
 
@@ -138,6 +675,26 @@ func (c *Config) applyRPCFallbacks() {
 	}
 }
 
+// ResolveRPC turns a task's rpc column into an endpoint URL: a name from
+// rpc_endpoints or a URL as is. An empty reference resolves to "" (default client).
+func (c *Config) ResolveRPC(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", nil
+	}
+	if endpoint, ok := c.RPCEndpoints[strings.ToLower(ref)]; ok {
+		return endpoint, nil
+	}
+	if isRPCURL(ref) {
+		return ref, nil
+	}
+	return "", fmt.Errorf("unknown rpc endpoint %q: not in rpc_endpoints and not a URL", ref)
+}
+
+func isRPCURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
 // MaskRPCForLogging masks sensitive API keys in RPC URLs for logging
 func (c *Config) MaskRPCForLogging(rpcURL string) string {
 	// List of patterns to mask
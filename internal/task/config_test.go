@@ -0,0 +1,26 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRPC(t *testing.T) {
+	cfg := &Config{RPCEndpoints: map[string]string{"premium": "https://premium.example/rpc"}}
+
+	endpoint, err := cfg.ResolveRPC("")
+	assert.NoError(t, err)
+	assert.Empty(t, endpoint)
+
+	endpoint, err = cfg.ResolveRPC(" Premium ")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://premium.example/rpc", endpoint)
+
+	endpoint, err = cfg.ResolveRPC("http://127.0.0.1:8899")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:8899", endpoint)
+
+	_, err = cfg.ResolveRPC("missing")
+	assert.Error(t, err)
+}
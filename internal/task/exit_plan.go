@@ -0,0 +1,55 @@
+// =============================================
+// File: internal/task/exit_plan.go
+// =============================================
+package task
+
+import "fmt"
+
+// ExitTier is one step of an exit plan from config.json exit_plans: it sells SellPercent
+// of the original position once PnL reaches AtPnL, or once the price falls Trailing
+// percent below its high since the previous step fired.
+type ExitTier struct {
+	SellPercent float64 `mapstructure:"sell_percent"` // Share of the original position (0 = the rest)
+	AtPnL       float64 `mapstructure:"at_pnl"`       // PnL in percent that fires the step
+	Trailing    float64 `mapstructure:"trailing"`     // Drop from the high in percent that fires the step
+}
+
+// String formats the step for logs and the position screen, e.g. "30% @ +50%" or "rest @ TS -20%".
+func (t ExitTier) String() string {
+	share := "rest"
+	if t.SellPercent > 0 {
+		share = fmt.Sprintf("%g%%", t.SellPercent)
+	}
+	if t.Trailing > 0 {
+		return fmt.Sprintf("%s @ TS -%g%%", share, t.Trailing)
+	}
+	return fmt.Sprintf("%s @ %+g%%", share, t.AtPnL)
+}
+
+// ValidateExitPlan checks that every step has exactly one trigger, that the shares
+// add up to at most 100 and that only the last step sells the rest.
+func ValidateExitPlan(tiers []ExitTier) error {
+	if len(tiers) == 0 {
+		return fmt.Errorf("exit plan has no steps")
+	}
+	total := 0.0
+	for i, t := range tiers {
+		switch {
+		case t.AtPnL != 0 && t.Trailing != 0:
+			return fmt.Errorf("step %d: set either at_pnl or trailing, not both", i+1)
+		case t.AtPnL < 0:
+			return fmt.Errorf("step %d: at_pnl must be positive; use stop_loss_percent for losses", i+1)
+		case t.AtPnL == 0 && (t.Trailing <= 0 || t.Trailing >= 100):
+			return fmt.Errorf("step %d: set at_pnl, or trailing between 0 and 100", i+1)
+		case t.SellPercent < 0 || t.SellPercent > 100:
+			return fmt.Errorf("step %d: sell_percent must be between 0 and 100", i+1)
+		case t.SellPercent == 0 && i != len(tiers)-1:
+			return fmt.Errorf("step %d: only the last step may sell the rest", i+1)
+		}
+		total += t.SellPercent
+	}
+	if total > 100 {
+		return fmt.Errorf("exit plan sells %g%% of the position, more than 100%%", total)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// ==================================
+// File: internal/task/journal_cipher.go
+// ==================================
+package task
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// journalCheck — содержимое файла ключа журналов: по нему проверяется пароль.
+var journalCheck = []byte("solana-bot journal key")
+
+// journalLinePrefix отмечает зашифрованную строку журнала; остальные строки — JSON как есть.
+var journalLinePrefix = []byte("enc1:")
+
+// ErrJournalLocked — строка журнала зашифрована, а ключа журналов нет.
+var ErrJournalLocked = errors.New("journal line is encrypted; the keystore password is needed to read it")
+
+// JournalCipher шифрует строки локальных журналов (позиции, сделки) AES-256-GCM ключом,
+// выведенным через scrypt из пароля хранилища кошельков. Строки шифруются по одной,
+// поэтому журналы остаются дописываемыми, а строки, записанные до включения шифрования,
+// читаются как есть. Методы безопасны для nil: без шифра строки не шифруются.
+type JournalCipher struct {
+	aead cipher.AEAD
+	seal bool // false — только чтение: новые строки пишутся открытым текстом
+}
+
+// OpenJournalCipher выводит ключ журналов из password по соли файла path, создавая
+// файл с новой солью, если его нет. Неверный пароль дает ErrWrongPassword.
+func OpenJournalCipher(path string, password []byte) (*JournalCipher, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		if data, err = sealKeystore(journalCheck, password); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("create journal key dir: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return nil, fmt.Errorf("write journal key: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("read journal key: %w", err)
+	}
+
+	check, aead, err := openKeystore(data, password)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(check, journalCheck) {
+		return nil, fmt.Errorf("%s is not a journal key", path)
+	}
+	return &JournalCipher{aead: aead, seal: true}, nil
+}
+
+// ReadOnly возвращает шифр, который расшифровывает строки, но новые не шифрует.
+func (c *JournalCipher) ReadOnly() *JournalCipher {
+	if c == nil {
+		return nil
+	}
+	return &JournalCipher{aead: c.aead}
+}
+
+// Seal шифрует строку журнала line (без перевода строки).
+func (c *JournalCipher) Seal(line []byte) ([]byte, error) {
+	if c == nil || !c.seal {
+		return line, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, line, nil)
+	out := make([]byte, len(journalLinePrefix)+base64.StdEncoding.EncodedLen(len(sealed)))
+	copy(out, journalLinePrefix)
+	base64.StdEncoding.Encode(out[len(journalLinePrefix):], sealed)
+	return out, nil
+}
+
+// Open возвращает JSON строки журнала line: зашифрованную расшифровывает, открытую
+// возвращает как есть.
+func (c *JournalCipher) Open(line []byte) ([]byte, error) {
+	line = bytes.TrimRight(line, "\r\n")
+	if !bytes.HasPrefix(line, journalLinePrefix) {
+		return line, nil
+	}
+	if c == nil {
+		return nil, ErrJournalLocked
+	}
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(line)-len(journalLinePrefix)))
+	n, err := base64.StdEncoding.Decode(sealed, line[len(journalLinePrefix):])
+	if err != nil {
+		return nil, fmt.Errorf("decode journal line: %w", err)
+	}
+	sealed = sealed[:n]
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, fmt.Errorf("journal line is too short")
+	}
+	plain, err := c.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("journal line does not decrypt: %w", err)
+	}
+	return plain, nil
+}
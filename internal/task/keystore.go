@@ -0,0 +1,303 @@
+// ==================================
+// File: internal/task/keystore.go
+// ==================================
+package task
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Параметры scrypt для новых хранилищ; в файле хранятся свои, поэтому их можно
+// менять, не ломая уже созданные хранилища.
+const (
+	keystoreScryptN = 1 << 17
+	keystoreScryptR = 8
+	keystoreScryptP = 1
+	keystoreVersion = 1
+)
+
+// ErrWrongPassword — пароль не подходит к хранилищу (или файл поврежден).
+var ErrWrongPassword = errors.New("wrong keystore password")
+
+// keystoreFile — зашифрованное хранилище кошельков: содержимое wallets.csv,
+// зашифрованное AES-256-GCM ключом, выведенным из пароля через scrypt.
+type keystoreFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptKeystore шифрует содержимое wallets.csv паролем password. Перед шифрованием
+// все ключи проверяются, чтобы в хранилище не попали строки, которые бот пропустит.
+func EncryptKeystore(csvData, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, fmt.Errorf("keystore password must not be empty")
+	}
+	rows, invalid, err := keystoreRows(csvData)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		Zero(row.key)
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid private keys for wallets: %v", invalid)
+	}
+	return sealKeystore(csvData, password)
+}
+
+// sealKeystore шифрует plain паролем password с новой солью и параметрами scrypt по умолчанию.
+func sealKeystore(plain, password []byte) ([]byte, error) {
+	ks := keystoreFile{
+		Version: keystoreVersion,
+		KDF:     "scrypt",
+		N:       keystoreScryptN,
+		R:       keystoreScryptR,
+		P:       keystoreScryptP,
+		Salt:    make([]byte, 32),
+	}
+	if _, err := rand.Read(ks.Salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	aead, err := ks.cipher(password)
+	if err != nil {
+		return nil, err
+	}
+	ks.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(ks.Nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	ks.Ciphertext = aead.Seal(nil, ks.Nonce, plain, nil)
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// DecryptKeystore расшифровывает хранилище и возвращает содержимое wallets.csv.
+// Вызывающий обнуляет результат через Zero, когда он больше не нужен.
+func DecryptKeystore(data, password []byte) ([]byte, error) {
+	plain, _, err := openKeystore(data, password)
+	return plain, err
+}
+
+// openKeystore расшифровывает файл в формате keystoreFile и возвращает содержимое
+// вместе с шифром, выведенным из пароля.
+func openKeystore(data, password []byte) ([]byte, cipher.AEAD, error) {
+	var ks keystoreFile
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, nil, fmt.Errorf("parse keystore: %w", err)
+	}
+	if ks.Version != keystoreVersion || ks.KDF != "scrypt" {
+		return nil, nil, fmt.Errorf("unsupported keystore version %d (%s)", ks.Version, ks.KDF)
+	}
+	aead, err := ks.cipher(password)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ks.Nonce) != aead.NonceSize() {
+		return nil, nil, fmt.Errorf("keystore nonce is corrupted")
+	}
+	plain, err := aead.Open(nil, ks.Nonce, ks.Ciphertext, nil)
+	if err != nil {
+		return nil, nil, ErrWrongPassword
+	}
+	return plain, aead, nil
+}
+
+// cipher выводит ключ из пароля и создает AES-GCM; выведенный ключ сразу обнуляется.
+func (ks *keystoreFile) cipher(password []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(password, ks.Salt, ks.N, ks.R, ks.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive keystore key: %w", err)
+	}
+	defer Zero(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create keystore cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// LoadKeystoreWallets загружает кошельки из зашифрованного хранилища path так же,
+// как LoadWallets из wallets.csv. Расшифрованный CSV обнуляется после разбора; ключи
+// остаются в памяти до ZeroWallets, чтобы подпись сделки не ждала расшифровки.
+func LoadKeystoreWallets(path string, password []byte) (map[string]*Wallet, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keystore: %w", err)
+	}
+	plain, err := DecryptKeystore(data, password)
+	if err != nil {
+		return nil, err
+	}
+	defer Zero(plain)
+
+	rows, _, err := keystoreRows(plain)
+	if err != nil {
+		return nil, err
+	}
+	return walletsFromRows(rows)
+}
+
+// keystoreRows разбирает расшифрованный wallets.csv, не превращая колонку ключа в строки:
+// строки Go неизменяемы и не обнуляются, а plain вызывающий обнуляет сам. Возвращает
+// строки кошельков и имена тех, чей ключ не разбирается.
+func keystoreRows(plain []byte) (rows []walletRow, invalid []string, err error) {
+	var header []string
+	roleIdx, groupIdx := -1, -1
+	for _, line := range bytes.Split(plain, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		fields := splitCSVFields(line)
+		if header == nil {
+			header = make([]string, len(fields))
+			for i, f := range fields {
+				header[i] = csvText(f)
+			}
+			roleIdx, groupIdx = walletColumns(header)
+			continue
+		}
+
+		name := csvText(fields[0])
+		if len(fields) < 2 {
+			if strings.TrimSpace(name) != "" {
+				invalid = append(invalid, name)
+			}
+			continue
+		}
+		key, err := decodeBase58Key(bytes.TrimSpace(unquoteCSV(fields[1])))
+		if err != nil {
+			invalid = append(invalid, name)
+		}
+		// Колонка ключа (индекс 1) строкой не становится, даже если заголовок назвал ее иначе
+		field := func(idx int) string {
+			if idx <= 1 || idx >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(csvText(fields[idx]))
+		}
+		rows = append(rows, walletRow{name: name, key: key, role: field(roleIdx), group: field(groupIdx)})
+	}
+	if header == nil || len(rows)+len(invalid) == 0 {
+		return nil, nil, fmt.Errorf("CSV file is empty or missing data")
+	}
+	return rows, invalid, nil
+}
+
+// splitCSVFields делит строку CSV на поля по запятым вне кавычек; поля — части line.
+func splitCSVFields(line []byte) [][]byte {
+	var fields [][]byte
+	start, quoted := 0, false
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			fields = append(fields, line[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, line[start:])
+}
+
+// unquoteCSV снимает кавычки вокруг поля, не копируя его.
+func unquoteCSV(field []byte) []byte {
+	if len(field) >= 2 && field[0] == '"' && field[len(field)-1] == '"' {
+		return field[1 : len(field)-1]
+	}
+	return field
+}
+
+// csvText возвращает несекретное поле строкой.
+func csvText(field []byte) string {
+	return strings.ReplaceAll(string(unquoteCSV(field)), `""`, `"`)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// decodeBase58Key декодирует приватный ключ ed25519 из base58 без промежуточных строк;
+// рабочий буфер обнуляется.
+func decodeBase58Key(src []byte) (solana.PrivateKey, error) {
+	// Число собирается в конце buf в big-endian: в base58 символов больше, чем байт
+	buf := make([]byte, len(src))
+	defer Zero(buf)
+	size := 0
+	for _, c := range src {
+		carry := strings.IndexByte(base58Alphabet, c)
+		if carry < 0 {
+			return nil, fmt.Errorf("invalid base58 character")
+		}
+		for i := len(buf) - 1; i >= len(buf)-size; i-- {
+			carry += int(buf[i]) * 58
+			buf[i] = byte(carry)
+			carry >>= 8
+		}
+		for ; carry > 0; carry >>= 8 {
+			if size == len(buf) {
+				return nil, fmt.Errorf("invalid base58 key")
+			}
+			size++
+			buf[len(buf)-size] = byte(carry)
+		}
+	}
+
+	zeros := 0
+	for zeros < len(src) && src[zeros] == '1' {
+		zeros++
+	}
+	if zeros+size != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("private key must be %d bytes, got %d", ed25519.PrivateKeySize, zeros+size)
+	}
+	key := make(solana.PrivateKey, ed25519.PrivateKeySize)
+	copy(key[zeros:], buf[len(buf)-size:])
+	return key, nil
+}
+
+// Zero обнуляет буфер с секретом.
+func Zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// ZeroWallets стирает приватные ключи кошельков из памяти; после этого кошельки
+// больше не могут подписывать транзакции.
+func ZeroWallets(wallets map[string]*Wallet) {
+	for _, w := range wallets {
+		Zero(w.PrivateKey)
+	}
+}
+
+// parseWalletCSV разбирает содержимое wallets.csv вместе с заголовком.
+func parseWalletCSV(data []byte) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV file is empty or missing data")
+	}
+	return records, nil
+}
@@ -0,0 +1,125 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore_RoundTrip(t *testing.T) {
+	key := solana.NewWallet().PrivateKey
+	csvData := []byte("name,private_key,role,group\nmain," + key.String() + ",sniper,alpha\n")
+
+	data, err := EncryptKeystore(csvData, []byte("correct horse"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), key.String())
+
+	_, err = DecryptKeystore(data, []byte("wrong"))
+	assert.ErrorIs(t, err, ErrWrongPassword)
+
+	path := filepath.Join(t.TempDir(), "wallets.keystore")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	wallets, err := LoadKeystoreWallets(path, []byte("correct horse"))
+	require.NoError(t, err)
+	require.Contains(t, wallets, "main")
+	assert.Equal(t, key.PublicKey(), wallets["main"].PublicKey)
+	assert.Equal(t, RoleSniper, wallets["main"].Role)
+	assert.Equal(t, "alpha", wallets["main"].Group)
+
+	ZeroWallets(wallets)
+	assert.Equal(t, make([]byte, len(key)), []byte(wallets["main"].PrivateKey), "keys are wiped")
+}
+
+func TestEncryptKeystore_RejectsInvalidKeys(t *testing.T) {
+	_, err := EncryptKeystore([]byte("name,private_key\nmain,not-a-key\n"), []byte("pw"))
+	assert.ErrorContains(t, err, "main")
+	_, err = EncryptKeystore([]byte("name,private_key\n"), []byte("pw"))
+	assert.Error(t, err)
+	_, err = EncryptKeystore([]byte("name,private_key\nmain,x\n"), nil)
+	assert.Error(t, err)
+}
+
+func TestDecodeBase58Key(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		key := solana.NewWallet().PrivateKey
+		got, err := decodeBase58Key([]byte(key.String()))
+		require.NoError(t, err)
+		assert.Equal(t, key, got)
+	}
+
+	// Ведущие единицы — нулевые байты
+	zeroPrefixed := make(solana.PrivateKey, 64)
+	copy(zeroPrefixed[2:], solana.NewWallet().PrivateKey[2:])
+	got, err := decodeBase58Key([]byte(zeroPrefixed.String()))
+	require.NoError(t, err)
+	assert.Equal(t, zeroPrefixed, got)
+
+	for name, src := range map[string]string{
+		"invalid character": "0OIl",
+		"public key length": solana.NewWallet().PublicKey().String(),
+		"empty":             "",
+	} {
+		_, err := decodeBase58Key([]byte(src))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestKeystoreRows(t *testing.T) {
+	key := solana.NewWallet().PrivateKey
+	plain := []byte("name,private_key,role,group\r\n" +
+		`"main, first",` + key.String() + ",sniper,alpha\r\n" +
+		"\n" +
+		`quoted,"` + key.String() + `",,` + "\n" +
+		"broken,not-a-key\n" +
+		"lonely\n")
+
+	rows, invalid, err := keystoreRows(plain)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"broken", "lonely"}, invalid)
+	require.Len(t, rows, 3)
+	assert.Equal(t, walletRow{name: "main, first", key: key, role: "sniper", group: "alpha"}, rows[0])
+	assert.Equal(t, key, rows[1].key)
+	assert.Nil(t, rows[2].key)
+
+	_, _, err = keystoreRows([]byte("name,private_key\n"))
+	assert.Error(t, err)
+}
+
+func TestJournalCipher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "configs", "journal.key")
+	c, err := OpenJournalCipher(path, []byte("secret"))
+	require.NoError(t, err)
+	require.FileExists(t, path)
+
+	sealed, err := c.Seal([]byte(`{"mint":"MintA"}`))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "MintA")
+
+	// Ключ выводится заново из той же соли
+	again, err := OpenJournalCipher(path, []byte("secret"))
+	require.NoError(t, err)
+	plain, err := again.Open(append(sealed, '\n'))
+	require.NoError(t, err)
+	assert.Equal(t, `{"mint":"MintA"}`, string(plain))
+
+	plain, err = again.Open([]byte(`{"mint":"Plain"}`))
+	require.NoError(t, err, "lines written before encryption stay readable")
+	assert.Equal(t, `{"mint":"Plain"}`, string(plain))
+
+	_, err = OpenJournalCipher(path, []byte("wrong"))
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	_, err = (*JournalCipher)(nil).Open(sealed)
+	assert.ErrorIs(t, err, ErrJournalLocked)
+
+	readOnly := c.ReadOnly()
+	line, err := readOnly.Seal([]byte(`{"mint":"MintB"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"mint":"MintB"}`, string(line), "a read-only cipher writes plaintext")
+	plain, err = readOnly.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, `{"mint":"MintA"}`, string(plain))
+}
@@ -10,12 +10,13 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 )
 
-// Manager loads and parses Task definitions from CSV.
+// Manager loads and parses Task definitions from CSV, YAML or JSON.
 type Manager struct {
 	logger *zap.Logger
 }
@@ -25,13 +26,36 @@ func NewManager(logger *zap.Logger) *Manager {
 	return &Manager{logger: logger}
 }
 
-// LoadTasks reads tasks from CSV at path. Returns parsed Task slice.
+// LoadTasks reads tasks from the file at path. Returns parsed Task slice.
+// Files ending in .yaml, .yml or .json are read as structured task lists and
+// rejected as a whole on any schema error; other files are read as CSV, where
+// invalid rows are skipped with a warning.
 func (m *Manager) LoadTasks(path string) ([]*Task, error) {
 	// Validate file path to prevent path traversal
 	if filepath.IsAbs(path) {
 		m.logger.Warn("⚠️  Using absolute path for tasks file: " + path)
 	}
 
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	case ".json":
+		format = "json"
+	}
+	if format != "" {
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("open tasks file: %w", err)
+		}
+		tasks, err := m.loadStructured(data, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		m.logger.Info(fmt.Sprintf("📋 Loaded %d trading tasks", len(tasks)))
+		return tasks, nil
+	}
+
 	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
 		return nil, fmt.Errorf("open tasks file: %w", err)
@@ -85,7 +109,11 @@ func (m *Manager) parseRow(fields []string, indexes map[string]int, line int) (*
 		}
 		return ""
 	}
+	return m.parseTask(get, line-1)
+}
 
+// parseTask builds the Task with the given ID from its fields, whatever the file format.
+func (m *Manager) parseTask(get func(string) string, id int) (*Task, error) {
 	op, err := parseOperation(get("operation"))
 	if err != nil {
 		return nil, err
@@ -112,8 +140,9 @@ func (m *Manager) parseRow(fields []string, indexes map[string]int, line int) (*
 		m.logger.Warn("⚠️  Invalid compute_units, using default: " + err.Error())
 	}
 
+	// Sell and limit_sell tasks read percent_to_sell in parseSellFields
 	autoSell := 99.0
-	if s := get("percent_to_sell"); s != "" {
+	if s := get("percent_to_sell"); s != "" && op != OperationSell && op != OperationLimitSell {
 		if f, err := strconv.ParseFloat(s, 64); err == nil && f >= 1 && f <= 99 {
 			autoSell = f
 		} else {
@@ -121,8 +150,8 @@ func (m *Manager) parseRow(fields []string, indexes map[string]int, line int) (*
 		}
 	}
 
-	return &Task{
-		ID:              line - 1,
+	t := &Task{
+		ID:              id,
 		TaskName:        get("task_name"),
 		Module:          get("module"),
 		WalletName:      get("wallet"),
@@ -133,8 +162,322 @@ func (m *Manager) parseRow(fields []string, indexes map[string]int, line int) (*
 		ComputeUnits:    computeUnits,
 		AutosellAmount:  autoSell,
 		TokenMint:       get("token_mint"),
+		RPC:             strings.TrimSpace(get("rpc")),
 		CreatedAt:       time.Now(),
-	}, nil
+	}
+
+	switch op {
+	case OperationWatch:
+		if err := m.parseWatchFields(t, get); err != nil {
+			return nil, err
+		}
+	case OperationSell:
+		if err := m.parseSellFields(t, get); err != nil {
+			return nil, err
+		}
+	case OperationSnipe, OperationSwap:
+		if t.MarketCapTargets, err = ParseMarketCapTargets(get("mcap_targets")); err != nil {
+			return nil, err
+		}
+		switch spread := WalletSpread(strings.ToLower(get("wallet_spread"))); spread {
+		case SpreadNone, SpreadEach, SpreadSplit:
+			t.WalletSpread = spread
+		default:
+			return nil, fmt.Errorf("invalid wallet_spread %q: use each or split", get("wallet_spread"))
+		}
+		if err := parseSliceFields(t, get); err != nil {
+			return nil, err
+		}
+	case OperationLimitBuy:
+		if t.AmountSol <= 0 {
+			return nil, fmt.Errorf("limit_buy task requires a positive amount_sol")
+		}
+		if t.MarketCapTargets, err = ParseMarketCapTargets(get("mcap_targets")); err != nil {
+			return nil, err
+		}
+		if err := parseLimitPrice(t, get); err != nil {
+			return nil, err
+		}
+	case OperationDCA:
+		if t.MarketCapTargets, err = ParseMarketCapTargets(get("mcap_targets")); err != nil {
+			return nil, err
+		}
+		if err := parseDCAFields(t, get); err != nil {
+			return nil, err
+		}
+	case OperationLimitSell:
+		if err := m.parseSellFields(t, get); err != nil {
+			return nil, err
+		}
+		if err := parseLimitPrice(t, get); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parseScheduleFields(t, get); err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case OperationSnipe, OperationSwap, OperationWatch, OperationLimitBuy, OperationDCA:
+		if err := parseExitFields(t, get); err != nil {
+			return nil, err
+		}
+		if s := get("allow_rebuy"); s != "" {
+			if t.AllowRebuy, err = strconv.ParseBool(s); err != nil {
+				return nil, fmt.Errorf("invalid allow_rebuy %q: use true or false", s)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// ResolveWallets maps tasks whose wallet column names a group onto the group's
+// trading wallets in round-robin order. A task with wallet_spread runs on every
+// trading wallet of its group instead: it is copied once per wallet, and with
+// "split" the copies share amount_sol. Tasks aimed at a vault wallet are dropped.
+func (m *Manager) ResolveWallets(tasks []*Task, wallets map[string]*Wallet) []*Task {
+	next := make(map[string]int)
+	resolved := make([]*Task, 0, len(tasks))
+	lastID := 0
+	for _, t := range tasks {
+		lastID = max(lastID, t.ID)
+	}
+
+	for _, t := range tasks {
+		if w, ok := wallets[t.WalletName]; ok {
+			if w.Role == RoleVault {
+				m.logger.Warn(fmt.Sprintf("⚠️  Skipping task '%s': wallet %s is a vault", t.TaskName, t.WalletName))
+				continue
+			}
+			if t.WalletSpread != SpreadNone {
+				m.logger.Warn(fmt.Sprintf("⚠️  Task '%s' has wallet_spread but %s is a single wallet, not a group", t.TaskName, t.WalletName))
+			}
+			resolved = append(resolved, t)
+			continue
+		}
+
+		members := WalletsInGroup(wallets, t.WalletName)
+		if len(members) == 0 {
+			// Unknown wallet is reported by the worker as before
+			resolved = append(resolved, t)
+			continue
+		}
+
+		group := t.WalletName
+		if t.WalletSpread != SpreadNone {
+			amount := t.AmountSol
+			if t.WalletSpread == SpreadSplit {
+				amount /= float64(len(members))
+			}
+			for i, w := range members {
+				leg := *t
+				if i > 0 {
+					lastID++
+					leg.ID = lastID
+				}
+				leg.WalletName = w.Name
+				leg.AmountSol = amount
+				leg.SpreadWallets = len(members)
+				resolved = append(resolved, &leg)
+			}
+			m.logger.Info(fmt.Sprintf("👛 Task '%s' spread across %d wallets of group %s, %.4f SOL each",
+				t.TaskName, len(members), group, amount))
+			continue
+		}
+		w := members[next[group]%len(members)]
+		next[group]++
+		t.WalletName = w.Name
+		m.logger.Info(fmt.Sprintf("👛 Task '%s' assigned to wallet %s from group %s", t.TaskName, w.Name, group))
+		resolved = append(resolved, t)
+	}
+	return resolved
+}
+
+// parseWatchFields reads the watchlist columns used by the watch operation.
+func (m *Manager) parseWatchFields(t *Task, get func(string) string) error {
+	if t.AmountSol <= 0 {
+		return fmt.Errorf("watch task requires a positive amount_sol budget")
+	}
+
+	t.DipPercent = 10
+	if s := get("dip_percent"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f <= 0 || f >= 100 {
+			return fmt.Errorf("invalid dip_percent %q: must be between 0 and 100", s)
+		}
+		t.DipPercent = f
+	}
+
+	if s := get("reference_price"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 {
+			return fmt.Errorf("invalid reference_price %q", s)
+		}
+		t.ReferencePrice = f
+	}
+
+	switch ref := WatchReference(strings.ToLower(get("reference"))); ref {
+	case ReferenceHigh, "high":
+		t.Reference = ReferenceHigh
+	case ReferenceLastExit:
+		if t.ReferencePrice > 0 {
+			return fmt.Errorf("reference last_exit and reference_price cannot be combined")
+		}
+		t.Reference = ref
+	default:
+		return fmt.Errorf("invalid reference %q: use high or last_exit", get("reference"))
+	}
+
+	t.WatchDuration = 60 * time.Minute
+	if s := get("watch_minutes"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid watch_minutes %q", s)
+		}
+		t.WatchDuration = time.Duration(n) * time.Minute
+	}
+	return nil
+}
+
+// parseSliceFields reads how a snipe or swap buy is split: buy_slices buys of
+// amount_sol in total, up to slice_jitter_ms (default 800) apart, each within
+// slice_variance_percent (default 25) of an even share. slice_new_blockhash
+// holds every slice until the chain has a newer blockhash.
+func parseSliceFields(t *Task, get func(string) string) error {
+	s := get("buy_slices")
+	if s == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > MaxBuySlices {
+		return fmt.Errorf("invalid buy_slices %q: want 1 to %d", s, MaxBuySlices)
+	}
+	t.BuySlices = n
+	if n == 1 {
+		return nil
+	}
+	if t.AmountSol <= 0 {
+		return fmt.Errorf("buy_slices requires a positive amount_sol")
+	}
+
+	t.SliceJitter = defaultSliceJitter
+	if s := get("slice_jitter_ms"); s != "" {
+		ms, err := strconv.Atoi(s)
+		if err != nil || ms < 0 {
+			return fmt.Errorf("invalid slice_jitter_ms %q", s)
+		}
+		t.SliceJitter = time.Duration(ms) * time.Millisecond
+	}
+
+	t.SliceVariance = defaultSliceVariance
+	if s := get("slice_variance_percent"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 || v > 90 {
+			return fmt.Errorf("invalid slice_variance_percent %q: want 0 to 90", s)
+		}
+		t.SliceVariance = v
+	}
+
+	if s := get("slice_new_blockhash"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid slice_new_blockhash %q: use true or false", s)
+		}
+		t.SliceNewBlockhash = b
+	}
+	return nil
+}
+
+// parseDCAFields reads the schedule of a dca task: amount_sol is spent every
+// dca_interval_minutes (default 10) for dca_minutes (default 60).
+func parseDCAFields(t *Task, get func(string) string) error {
+	if t.AmountSol <= 0 {
+		return fmt.Errorf("dca task requires a positive amount_sol per buy")
+	}
+
+	t.DCAInterval = 10 * time.Minute
+	if s := get("dca_interval_minutes"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid dca_interval_minutes %q", s)
+		}
+		t.DCAInterval = time.Duration(n) * time.Minute
+	}
+
+	t.DCADuration = 60 * time.Minute
+	if s := get("dca_minutes"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid dca_minutes %q", s)
+		}
+		t.DCADuration = time.Duration(n) * time.Minute
+	}
+	if t.DCADuration < t.DCAInterval {
+		return fmt.Errorf("dca_minutes must be at least dca_interval_minutes")
+	}
+	return nil
+}
+
+// parseSellFields reads the sell size of a sell task. Unlike auto-sell, a sell task
+// may sell the whole balance, which is also the default.
+func (m *Manager) parseSellFields(t *Task, get func(string) string) error {
+	t.AutosellAmount = 100
+	if s := get("percent_to_sell"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f <= 0 || f > 100 {
+			return fmt.Errorf("invalid percent_to_sell %q: must be above 0 and at most 100", s)
+		}
+		t.AutosellAmount = f
+	}
+
+	if s := get("sell_amount"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 {
+			return fmt.Errorf("invalid sell_amount %q", s)
+		}
+		t.SellAmount = f
+	}
+	return nil
+}
+
+// parseExitFields reads the stop-loss, take-profit and trailing stop columns of tasks that open a position.
+func parseExitFields(t *Task, get func(string) string) error {
+	if s := get("stop_loss_percent"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 || f >= 100 {
+			return fmt.Errorf("invalid stop_loss_percent %q: must be between 0 and 100", s)
+		}
+		t.StopLossPercent = f
+	}
+	if s := get("trailing_stop_percent"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 || f >= 100 {
+			return fmt.Errorf("invalid trailing_stop_percent %q: must be between 0 and 100", s)
+		}
+		t.TrailingStopPercent = f
+	}
+	if s := get("take_profit_percent"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 {
+			return fmt.Errorf("invalid take_profit_percent %q", s)
+		}
+		t.TakeProfitPercent = f
+	}
+	t.ExitPlan = strings.ToLower(strings.TrimSpace(get("exit_plan")))
+	return nil
+}
+
+// parseLimitPrice reads the limit_price column required by limit orders.
+func parseLimitPrice(t *Task, get func(string) string) error {
+	s := get("limit_price")
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f <= 0 {
+		return fmt.Errorf("invalid limit_price %q: must be a positive token price in SOL", s)
+	}
+	t.LimitPrice = f
+	return nil
 }
 
 func parseUint32FieldStr(s string) (uint32, error) {
@@ -148,7 +491,7 @@ func parseUint32FieldStr(s string) (uint32, error) {
 func parseOperation(s string) (OperationType, error) {
 	op := OperationType(s)
 	switch op {
-	case OperationSnipe, OperationSwap, OperationSell:
+	case OperationSnipe, OperationSwap, OperationSell, OperationWatch, OperationLimitBuy, OperationLimitSell, OperationDCA:
 		return op, nil
 	default:
 		return "", fmt.Errorf("unsupported operation: %q", s)
@@ -0,0 +1,90 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResolveWallets_Spread(t *testing.T) {
+	wallets := map[string]*Wallet{
+		"a":     {Name: "a", Group: "snipers"},
+		"b":     {Name: "b", Group: "snipers"},
+		"vault": {Name: "vault", Group: "snipers", Role: RoleVault},
+	}
+	tasks := []*Task{
+		{ID: 1, TaskName: "each", WalletName: "snipers", AmountSol: 0.2, WalletSpread: SpreadEach},
+		{ID: 2, TaskName: "split", WalletName: "snipers", AmountSol: 0.2, WalletSpread: SpreadSplit},
+		{ID: 3, TaskName: "single", WalletName: "snipers", AmountSol: 0.2},
+	}
+
+	resolved := NewManager(zap.NewNop()).ResolveWallets(tasks, wallets)
+	require.Len(t, resolved, 5)
+
+	var ids []int
+	for _, r := range resolved {
+		ids = append(ids, r.ID)
+	}
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5}, ids, "copies get fresh IDs")
+
+	assert.Equal(t, "a", resolved[0].WalletName)
+	assert.Equal(t, "b", resolved[1].WalletName)
+	assert.Equal(t, 0.2, resolved[1].AmountSol)
+	assert.Equal(t, 2, resolved[1].SpreadWallets)
+	assert.Equal(t, 0.1, resolved[2].AmountSol)
+	assert.Equal(t, 0.1, resolved[3].AmountSol)
+	assert.Equal(t, 0, resolved[4].SpreadWallets)
+	assert.Equal(t, 0.2, tasks[0].AmountSol, "the original task is not modified")
+}
+
+func TestParseWatchFields(t *testing.T) {
+	for name, tc := range map[string]struct {
+		amount  float64
+		fields  map[string]string
+		want    Task
+		wantErr string
+	}{
+		"defaults": {
+			amount: 0.1,
+			want:   Task{DipPercent: 10, WatchDuration: 60 * time.Minute},
+		},
+		"fixed reference": {
+			amount: 0.1,
+			fields: map[string]string{"dip_percent": "25", "reference_price": "0.0000002", "watch_minutes": "15"},
+			want:   Task{DipPercent: 25, ReferencePrice: 0.0000002, WatchDuration: 15 * time.Minute},
+		},
+		"last exit": {
+			amount: 0.1,
+			fields: map[string]string{"reference": "LAST_EXIT"},
+			want:   Task{DipPercent: 10, Reference: ReferenceLastExit, WatchDuration: 60 * time.Minute},
+		},
+		"recent high": {
+			amount: 0.1,
+			fields: map[string]string{"reference": "high"},
+			want:   Task{DipPercent: 10, WatchDuration: 60 * time.Minute},
+		},
+		"no budget":           {wantErr: "positive amount_sol"},
+		"dip of zero":         {amount: 0.1, fields: map[string]string{"dip_percent": "0"}, wantErr: "invalid dip_percent"},
+		"dip of 100":          {amount: 0.1, fields: map[string]string{"dip_percent": "100"}, wantErr: "invalid dip_percent"},
+		"negative reference":  {amount: 0.1, fields: map[string]string{"reference_price": "-1"}, wantErr: "invalid reference_price"},
+		"unknown reference":   {amount: 0.1, fields: map[string]string{"reference": "open"}, wantErr: "invalid reference"},
+		"exit and fixed":      {amount: 0.1, fields: map[string]string{"reference": "last_exit", "reference_price": "0.1"}, wantErr: "cannot be combined"},
+		"zero watch minutes":  {amount: 0.1, fields: map[string]string{"watch_minutes": "0"}, wantErr: "invalid watch_minutes"},
+		"watch minutes float": {amount: 0.1, fields: map[string]string{"watch_minutes": "1.5"}, wantErr: "invalid watch_minutes"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := Task{AmountSol: tc.amount}
+			err := NewManager(zap.NewNop()).parseWatchFields(&got, func(key string) string { return tc.fields[key] })
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tc.want.AmountSol = tc.amount
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
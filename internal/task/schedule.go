@@ -0,0 +1,75 @@
+// =============================================
+// File: internal/task/schedule.go
+// =============================================
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// scheduleLayouts are the accepted start_at / expire_at formats; times without a zone are local.
+var scheduleLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+// ParseScheduleTime parses a start_at or expire_at value: RFC 3339, or
+// "2006-01-02 15:04[:05]" in local time.
+func ParseScheduleTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range scheduleLayouts {
+		if at, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("use RFC 3339 or 2006-01-02 15:04:05 (local time)")
+}
+
+// parseScheduleFields reads when the task may start (start_at) and the deadline
+// after which it is dropped if it has not started yet (expire_at).
+func parseScheduleFields(t *Task, get func(string) string) error {
+	var err error
+	if s := get("start_at"); s != "" {
+		if t.StartAt, err = ParseScheduleTime(s); err != nil {
+			return fmt.Errorf("invalid start_at %q: %w", s, err)
+		}
+	}
+	if s := get("expire_at"); s != "" {
+		if t.ExpireAt, err = ParseScheduleTime(s); err != nil {
+			return fmt.Errorf("invalid expire_at %q: %w", s, err)
+		}
+	}
+	if !t.StartAt.IsZero() && !t.ExpireAt.IsZero() && !t.ExpireAt.After(t.StartAt) {
+		return fmt.Errorf("expire_at %s must be after start_at %s", t.ExpireAt.Format(time.RFC3339), t.StartAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Scheduled reports whether the task is still waiting for its start_at at now.
+func (t *Task) Scheduled(now time.Time) bool {
+	return !t.StartAt.IsZero() && now.Before(t.StartAt)
+}
+
+// Expired reports whether the task's expire_at has passed at now.
+func (t *Task) Expired(now time.Time) bool {
+	return !t.ExpireAt.IsZero() && !now.Before(t.ExpireAt)
+}
+
+// SplitScheduled separates tasks that wait for their start_at at now, ordered by start time.
+func SplitScheduled(tasks []*Task, now time.Time) (ready, scheduled []*Task) {
+	for _, t := range tasks {
+		if t.Scheduled(now) {
+			scheduled = append(scheduled, t)
+		} else {
+			ready = append(ready, t)
+		}
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool { return scheduled[i].StartAt.Before(scheduled[j].StartAt) })
+	return ready, scheduled
+}
@@ -0,0 +1,62 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	at, err := ParseScheduleTime("2025-03-01T12:30:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC), at.UTC())
+
+	at, err = ParseScheduleTime("2025-03-01 12:30")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 30, 0, 0, time.Local), at)
+
+	_, err = ParseScheduleTime("tomorrow")
+	assert.Error(t, err)
+}
+
+func TestParseScheduleFields(t *testing.T) {
+	fields := func(m map[string]string) func(string) string {
+		return func(col string) string { return m[col] }
+	}
+
+	tk := &Task{}
+	require.NoError(t, parseScheduleFields(tk, fields(map[string]string{
+		"start_at": "2025-03-01 12:00", "expire_at": "2025-03-01 12:05",
+	})))
+	assert.Equal(t, 5*time.Minute, tk.ExpireAt.Sub(tk.StartAt))
+
+	tk = &Task{}
+	require.NoError(t, parseScheduleFields(tk, fields(nil)))
+	assert.True(t, tk.StartAt.IsZero())
+	assert.False(t, tk.Scheduled(time.Now()))
+	assert.False(t, tk.Expired(time.Now()))
+
+	for _, bad := range []map[string]string{
+		{"start_at": "noon"},
+		{"expire_at": "2025-13-01 12:00"},
+		{"start_at": "2025-03-01 12:00", "expire_at": "2025-03-01 12:00"},
+	} {
+		assert.Error(t, parseScheduleFields(&Task{}, fields(bad)), "%v", bad)
+	}
+}
+
+func TestSplitScheduled(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	late := &Task{TaskName: "late", StartAt: now.Add(time.Hour)}
+	soon := &Task{TaskName: "soon", StartAt: now.Add(time.Minute)}
+	past := &Task{TaskName: "past", StartAt: now.Add(-time.Minute), ExpireAt: now}
+	plain := &Task{TaskName: "plain"}
+
+	ready, scheduled := SplitScheduled([]*Task{late, plain, soon, past}, now)
+	assert.Equal(t, []*Task{plain, past}, ready)
+	assert.Equal(t, []*Task{soon, late}, scheduled)
+	assert.True(t, past.Expired(now))
+	assert.False(t, soon.Expired(now))
+}
@@ -0,0 +1,59 @@
+// =============================================
+// File: internal/task/slices.go
+// =============================================
+package task
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	// MaxBuySlices caps how many buys one task may be split into.
+	MaxBuySlices = 20
+	// defaultSliceJitter is the longest pause between slices when slice_jitter_ms is empty.
+	defaultSliceJitter = 800 * time.Millisecond
+	// defaultSliceVariance is how much slice sizes vary when slice_variance_percent is empty.
+	defaultSliceVariance = 25.0
+)
+
+// Sliced reports whether the task's buy is split into several smaller buys.
+func (t *Task) Sliced() bool {
+	return t.BuySlices > 1
+}
+
+// SliceAmounts splits amount_sol into BuySlices buys. Each slice deviates from an
+// even share by up to SliceVariance percent, so the buys do not look alike; the
+// slices are rounded to lamports and always add up to amount_sol exactly.
+func (t *Task) SliceAmounts(rnd *rand.Rand) []float64 {
+	if !t.Sliced() {
+		return []float64{t.AmountSol}
+	}
+
+	weights := make([]float64, t.BuySlices)
+	sum := 0.0
+	for i := range weights {
+		weights[i] = 1 + t.SliceVariance/100*(2*rnd.Float64()-1)
+		sum += weights[i]
+	}
+
+	amounts := make([]float64, t.BuySlices)
+	total := math.Round(t.AmountSol * 1e9)
+	left := total
+	for i := range amounts[:len(amounts)-1] {
+		lamports := math.Round(total * weights[i] / sum)
+		amounts[i] = lamports / 1e9
+		left -= lamports
+	}
+	amounts[len(amounts)-1] = left / 1e9
+	return amounts
+}
+
+// SliceDelay returns a random pause of up to SliceJitter before the next slice.
+func (t *Task) SliceDelay(rnd *rand.Rand) time.Duration {
+	if t.SliceJitter <= 0 {
+		return 0
+	}
+	return time.Duration(rnd.Int63n(int64(t.SliceJitter) + 1))
+}
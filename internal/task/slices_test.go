@@ -0,0 +1,74 @@
+package task
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceAmounts(t *testing.T) {
+	tk := &Task{AmountSol: 1.5, BuySlices: 4, SliceVariance: 25}
+	amounts := tk.SliceAmounts(rand.New(rand.NewSource(1)))
+	require.Len(t, amounts, 4)
+
+	total := 0.0
+	for _, a := range amounts {
+		assert.InDelta(t, 1.5/4, a, 1.5/4*0.6, "each slice stays near an even share")
+		total += a
+	}
+	assert.InDelta(t, 1.5, total, 1e-9, "slices add up to amount_sol")
+	assert.NotEqual(t, amounts[0], amounts[1], "slice sizes are randomized")
+
+	single := &Task{AmountSol: 0.2}
+	assert.Equal(t, []float64{0.2}, single.SliceAmounts(rand.New(rand.NewSource(1))))
+}
+
+func TestSliceDelay(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tk := &Task{SliceJitter: 500 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		d := tk.SliceDelay(rnd)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, 500*time.Millisecond)
+	}
+	assert.Zero(t, (&Task{}).SliceDelay(rnd))
+}
+
+func TestParseSliceFields(t *testing.T) {
+	fields := func(m map[string]string) func(string) string {
+		return func(col string) string { return m[col] }
+	}
+
+	tk := &Task{AmountSol: 1}
+	require.NoError(t, parseSliceFields(tk, fields(map[string]string{"buy_slices": "3"})))
+	assert.Equal(t, 3, tk.BuySlices)
+	assert.Equal(t, defaultSliceJitter, tk.SliceJitter)
+	assert.Equal(t, defaultSliceVariance, tk.SliceVariance)
+	assert.False(t, tk.SliceNewBlockhash)
+
+	tk = &Task{AmountSol: 1}
+	require.NoError(t, parseSliceFields(tk, fields(map[string]string{
+		"buy_slices": "5", "slice_jitter_ms": "0", "slice_variance_percent": "10", "slice_new_blockhash": "true",
+	})))
+	assert.Zero(t, tk.SliceJitter)
+	assert.Equal(t, 10.0, tk.SliceVariance)
+	assert.True(t, tk.SliceNewBlockhash)
+
+	tk = &Task{AmountSol: 1}
+	require.NoError(t, parseSliceFields(tk, fields(nil)))
+	assert.False(t, tk.Sliced())
+
+	for _, bad := range []map[string]string{
+		{"buy_slices": "0"},
+		{"buy_slices": "21"},
+		{"buy_slices": "2", "slice_jitter_ms": "-1"},
+		{"buy_slices": "2", "slice_variance_percent": "95"},
+		{"buy_slices": "2", "slice_new_blockhash": "maybe"},
+	} {
+		assert.Error(t, parseSliceFields(&Task{AmountSol: 1}, fields(bad)), "%v", bad)
+	}
+	assert.Error(t, parseSliceFields(&Task{}, fields(map[string]string{"buy_slices": "2"})), "requires amount_sol")
+}
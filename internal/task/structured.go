@@ -0,0 +1,323 @@
+// =============================================
+// File: internal/task/structured.go
+// =============================================
+package task
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Columns lists every field a task may set. CSV headers and the keys of YAML and
+// JSON task entries use the same names.
+var Columns = []string{
+	"task_name", "module", "wallet", "operation", "amount_sol", "slippage_percent",
+	"priority_fee", "token_mint", "compute_units", "percent_to_sell", "sell_amount", "rpc",
+	"dip_percent", "reference_price", "reference", "watch_minutes",
+	"dca_interval_minutes", "dca_minutes",
+	"limit_price", "mcap_targets", "wallet_spread",
+	"buy_slices", "slice_jitter_ms", "slice_variance_percent", "slice_new_blockhash",
+	"stop_loss_percent", "take_profit_percent", "trailing_stop_percent", "exit_plan",
+	"allow_rebuy", "start_at", "expire_at",
+}
+
+// requiredFields must be present in every YAML or JSON task entry.
+var requiredFields = []string{"task_name", "module", "wallet", "operation", "amount_sol", "slippage_percent", "token_mint"}
+
+// structuredEntry is one task of a YAML or JSON file with the line of each field.
+type structuredEntry struct {
+	line   int
+	fields map[string]string
+	lines  map[string]int
+}
+
+func newStructuredEntry(line int) *structuredEntry {
+	return &structuredEntry{line: line, fields: make(map[string]string), lines: make(map[string]int)}
+}
+
+// set adds a field, rejecting duplicates.
+func (e *structuredEntry) set(key, value string, line int) error {
+	if prev, ok := e.lines[key]; ok {
+		return fmt.Errorf("line %d: field %q is set twice (first on line %d)", line, key, prev)
+	}
+	e.fields[key], e.lines[key] = value, line
+	return nil
+}
+
+// loadStructured parses the tasks of a YAML or JSON file. The file holds a list of
+// tasks, or an object whose "tasks" key holds the list; every task maps column names
+// to single values. Unlike CSV rows, invalid tasks are not skipped: every problem is
+// reported with its line and field, and no task is loaded.
+func (m *Manager) loadStructured(data []byte, format string) ([]*Task, error) {
+	var entries []*structuredEntry
+	var err error
+	if format == "json" {
+		entries, err = readJSONTasks(data)
+	} else {
+		entries, err = readYAMLTasks(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	tasks := make([]*Task, 0, len(entries))
+	for i, e := range entries {
+		if errs := e.validate(); len(errs) > 0 {
+			problems = append(problems, errs...)
+			continue
+		}
+		t, err := m.parseTask(func(key string) string { return e.fields[key] }, i+1)
+		if err != nil {
+			problems = append(problems, e.describe(err))
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%d problems in tasks:\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	return tasks, nil
+}
+
+// validate checks the entry against the task schema: only known fields, and every required one.
+func (e *structuredEntry) validate() []string {
+	var problems []string
+	keys := make([]string, 0, len(e.fields))
+	for key := range e.fields {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return e.lines[keys[i]] < e.lines[keys[j]] })
+	for _, key := range keys {
+		if !isColumn(key) {
+			msg := fmt.Sprintf("line %d: unknown field %q", e.lines[key], key)
+			if guess := closestColumn(key); guess != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", guess)
+			}
+			problems = append(problems, msg)
+		}
+	}
+	for _, key := range requiredFields {
+		if strings.TrimSpace(e.fields[key]) == "" {
+			problems = append(problems, fmt.Sprintf("line %d: task %s is missing required field %q", e.line, e.name(), key))
+		}
+	}
+	return problems
+}
+
+// describe places a parse error at the field it names, or at the start of the task.
+func (e *structuredEntry) describe(err error) string {
+	msg := err.Error()
+	field := ""
+	for key := range e.fields {
+		if strings.Contains(msg, key) && len(key) > len(field) {
+			field = key
+		}
+	}
+	if field != "" {
+		return fmt.Sprintf("line %d, field %s: %s", e.lines[field], field, msg)
+	}
+	return fmt.Sprintf("line %d: task %s: %s", e.line, e.name(), msg)
+}
+
+func (e *structuredEntry) name() string {
+	if name := e.fields["task_name"]; name != "" {
+		return strconv.Quote(name)
+	}
+	return "without task_name"
+}
+
+func isColumn(key string) bool {
+	for _, c := range Columns {
+		if c == key {
+			return true
+		}
+	}
+	return false
+}
+
+// closestColumn suggests a known column for a misspelled field name.
+func closestColumn(key string) string {
+	best, bestDist := "", 3 // Suggest only names within two edits
+	for _, c := range Columns {
+		if d := editDistance(key, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// readYAMLTasks reads task entries from a YAML document.
+func readYAMLTasks(data []byte) ([]*structuredEntry, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	list := doc.Content[0]
+	if list.Kind == yaml.MappingNode {
+		var tasks *yaml.Node
+		for i := 0; i+1 < len(list.Content); i += 2 {
+			key := list.Content[i]
+			if key.Value != "tasks" {
+				return nil, fmt.Errorf("line %d: unknown top-level field %q, expected \"tasks\"", key.Line, key.Value)
+			}
+			tasks = list.Content[i+1]
+		}
+		if tasks == nil {
+			return nil, fmt.Errorf("line %d: expected a \"tasks\" list", list.Line)
+		}
+		list = tasks
+	}
+	if list.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("line %d: expected a list of tasks", list.Line)
+	}
+
+	entries := make([]*structuredEntry, 0, len(list.Content))
+	for _, item := range list.Content {
+		if item.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("line %d: a task must be a map of fields", item.Line)
+		}
+		e := newStructuredEntry(item.Line)
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			key, value := item.Content[i], item.Content[i+1]
+			if value.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: field %q must be a single value, not a list or map", value.Line, key.Value)
+			}
+			v := value.Value
+			if value.Tag == "!!null" {
+				v = ""
+			}
+			if err := e.set(key.Value, v, key.Line); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// readJSONTasks reads task entries from a JSON document, tracking the line of every field.
+func readJSONTasks(data []byte) ([]*structuredEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	lineAt := func(offset int64) int {
+		return bytes.Count(data[:min(int(offset), len(data))], []byte("\n")) + 1
+	}
+	token := func() (json.Token, error) {
+		tok, err := dec.Token()
+		var syntax *json.SyntaxError
+		switch {
+		case errors.As(err, &syntax):
+			return nil, fmt.Errorf("parse JSON: line %d: %w", lineAt(syntax.Offset), err)
+		case err == io.EOF:
+			return nil, fmt.Errorf("parse JSON: unexpected end of file")
+		case err != nil:
+			return nil, fmt.Errorf("parse JSON: line %d: %w", lineAt(dec.InputOffset()), err)
+		}
+		return tok, nil
+	}
+
+	tok, err := token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == json.Delim('{') {
+		found := false
+		for dec.More() {
+			key, err := token()
+			if err != nil {
+				return nil, err
+			}
+			if key != "tasks" {
+				return nil, fmt.Errorf("line %d: unknown top-level field %q, expected \"tasks\"", lineAt(dec.InputOffset()), key)
+			}
+			if tok, err = token(); err != nil {
+				return nil, err
+			}
+			found = true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("line %d: expected a \"tasks\" list", lineAt(dec.InputOffset()))
+		}
+	}
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("line %d: expected a list of tasks", lineAt(dec.InputOffset()))
+	}
+
+	var entries []*structuredEntry
+	for dec.More() {
+		tok, err := token()
+		if err != nil {
+			return nil, err
+		}
+		line := lineAt(dec.InputOffset())
+		if tok != json.Delim('{') {
+			return nil, fmt.Errorf("line %d: a task must be an object of fields", line)
+		}
+		e := newStructuredEntry(line)
+		for dec.More() {
+			key, err := token()
+			if err != nil {
+				return nil, err
+			}
+			line := lineAt(dec.InputOffset())
+			value, err := token()
+			if err != nil {
+				return nil, err
+			}
+			var v string
+			switch value := value.(type) {
+			case string:
+				v = value
+			case json.Number:
+				v = value.String()
+			case bool:
+				v = strconv.FormatBool(value)
+			case nil:
+			default:
+				return nil, fmt.Errorf("line %d: field %q must be a single value, not a list or object", line, key)
+			}
+			if err := e.set(key.(string), v, line); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := token(); err != nil { // '}'
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,110 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writeTasks(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadTasks_YAML(t *testing.T) {
+	path := writeTasks(t, "tasks.yaml", `tasks:
+  - task_name: snipe
+    module: pumpfun
+    wallet: main
+    operation: snipe
+    amount_sol: 0.1
+    slippage_percent: 5
+    token_mint: new
+    stop_loss_percent: 20
+    allow_rebuy: true
+  - task_name: exit
+    module: pumpfun
+    wallet: main
+    operation: sell
+    amount_sol: 0
+    slippage_percent: 10
+    token_mint: So11111111111111111111111111111111111111112
+    percent_to_sell: 50
+    rpc: ~
+`)
+	tasks, err := NewManager(zap.NewNop()).LoadTasks(path)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, 1, tasks[0].ID)
+	assert.Equal(t, OperationSnipe, tasks[0].Operation)
+	assert.InDelta(t, 0.1, tasks[0].AmountSol, 1e-9)
+	assert.InDelta(t, 20, tasks[0].StopLossPercent, 1e-9)
+	assert.True(t, tasks[0].AllowRebuy)
+	assert.Equal(t, 2, tasks[1].ID)
+	assert.InDelta(t, 50, tasks[1].AutosellAmount, 1e-9)
+	assert.Empty(t, tasks[1].RPC)
+}
+
+func TestLoadTasks_JSON(t *testing.T) {
+	path := writeTasks(t, "tasks.json", `[
+  {
+    "task_name": "snipe",
+    "module": "pumpfun",
+    "wallet": "main",
+    "operation": "snipe",
+    "amount_sol": 0.25,
+    "slippage_percent": 5,
+    "token_mint": "new",
+    "allow_rebuy": false
+  }
+]`)
+	tasks, err := NewManager(zap.NewNop()).LoadTasks(path)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "snipe", tasks[0].TaskName)
+	assert.InDelta(t, 0.25, tasks[0].AmountSol, 1e-9)
+}
+
+func TestLoadTasks_SchemaErrors(t *testing.T) {
+	path := writeTasks(t, "tasks.yaml", `- task_name: snipe
+  module: pumpfun
+  wallet: main
+  operation: snipe
+  amount_sol: lots
+  slippage_percent: 5
+  token_mint: new
+- task_name: exit
+  module: pumpfun
+  wallet: main
+  operation: sell
+  amount_sol: 0
+  slipage_percent: 10
+  token_mint: new
+`)
+	_, err := NewManager(zap.NewNop()).LoadTasks(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 5, field amount_sol")
+	assert.Contains(t, err.Error(), `line 13: unknown field "slipage_percent" (did you mean "slippage_percent"?)`)
+	assert.Contains(t, err.Error(), `line 8: task "exit" is missing required field "slippage_percent"`)
+}
+
+func TestLoadTasks_JSONErrors(t *testing.T) {
+	m := NewManager(zap.NewNop())
+
+	_, err := m.LoadTasks(writeTasks(t, "tasks.json", "[\n  {\"task_name\": \"a\",\n   \"rpc\": [\"x\"]}\n]"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `line 3: field "rpc" must be a single value`)
+
+	_, err = m.LoadTasks(writeTasks(t, "tasks.json", "[\n  {\"task_name\": \"a\",\n   \"task_name\": \"b\"}\n]"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `line 3: field "task_name" is set twice (first on line 2)`)
+
+	_, err = m.LoadTasks(writeTasks(t, "tasks.json", "[\n  {\"task_name\": \"a\",,}\n]"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse JSON: line 2")
+}
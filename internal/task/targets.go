@@ -0,0 +1,119 @@
+// =============================================
+// File: internal/task/targets.go
+// =============================================
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MarketCapTarget sells Percent of the bought position once the token's market cap
+// reaches MarketCap, e.g. "50%@$1M" (USD) or "25%@5000SOL".
+type MarketCapTarget struct {
+	Percent   float64 // Share of the original position to sell
+	MarketCap float64 // Market cap in USD, or in SOL when InSOL
+	InSOL     bool
+}
+
+// ParseMarketCapTargets parses the mcap_targets column: targets separated by ';',
+// each "<percent>%@<market cap>" where the market cap is "$1.5M", "$250k" or "5000SOL".
+// The percents must add up to at most 100.
+func ParseMarketCapTargets(s string) ([]MarketCapTarget, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var targets []MarketCapTarget
+	total := 0.0
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		percentStr, capStr, ok := strings.Cut(part, "@")
+		if !ok {
+			return nil, fmt.Errorf("invalid market cap target %q: want <percent>%%@<market cap>", part)
+		}
+
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(percentStr), "%"), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid market cap target %q: percent must be above 0 and at most 100", part)
+		}
+		target, err := parseMarketCap(capStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid market cap target %q: %w", part, err)
+		}
+		target.Percent = percent
+
+		total += percent
+		if total > 100 {
+			return nil, fmt.Errorf("market cap targets sell more than 100%% of the position")
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// parseMarketCap parses "$1.5M", "$250k", "1B$" or "5000SOL".
+func parseMarketCap(s string) (MarketCapTarget, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	var t MarketCapTarget
+	switch {
+	case strings.HasSuffix(s, "SOL"):
+		t.InSOL = true
+		s = strings.TrimSpace(strings.TrimSuffix(s, "SOL"))
+	case strings.HasPrefix(s, "$"):
+		s = strings.TrimPrefix(s, "$")
+	case strings.HasSuffix(s, "$"):
+		s = strings.TrimSuffix(s, "$")
+	default:
+		return t, fmt.Errorf("market cap %q needs a $ or SOL unit", s)
+	}
+
+	multiplier := 1.0
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K':
+			multiplier = 1e3
+		case 'M':
+			multiplier = 1e6
+		case 'B':
+			multiplier = 1e9
+		}
+		if multiplier != 1 {
+			s = s[:n-1]
+		}
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || value <= 0 {
+		return t, fmt.Errorf("market cap must be a positive number")
+	}
+	t.MarketCap = value * multiplier
+	return t, nil
+}
+
+// String formats the target as "50% at $1.00M" or "25% at 5.00K SOL".
+func (t MarketCapTarget) String() string {
+	if t.InSOL {
+		return fmt.Sprintf("%g%% at %s SOL", t.Percent, FormatCompact(t.MarketCap))
+	}
+	return fmt.Sprintf("%g%% at $%s", t.Percent, FormatCompact(t.MarketCap))
+}
+
+// FormatCompact formats large amounts as "1.25M", "980.00K" or "3.40B".
+func FormatCompact(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.2fK", v/1e3)
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
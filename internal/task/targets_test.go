@@ -0,0 +1,28 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarketCapTargets(t *testing.T) {
+	targets, err := ParseMarketCapTargets("50%@$1M; 25%@$2.5m;25@5000SOL")
+	require.NoError(t, err)
+	require.Len(t, targets, 3)
+	assert.Equal(t, MarketCapTarget{Percent: 50, MarketCap: 1e6}, targets[0])
+	assert.Equal(t, 2.5e6, targets[1].MarketCap)
+	assert.Equal(t, MarketCapTarget{Percent: 25, MarketCap: 5000, InSOL: true}, targets[2])
+	assert.Equal(t, "50% at $1.00M", targets[0].String())
+	assert.Equal(t, "25% at 5.00K SOL", targets[2].String())
+
+	targets, err = ParseMarketCapTargets("")
+	assert.NoError(t, err)
+	assert.Nil(t, targets)
+
+	for _, bad := range []string{"50%", "50%@1M", "0%@$1M", "80%@$1M;30%@$2M", "50%@$-1k"} {
+		_, err := ParseMarketCapTargets(bad)
+		assert.Error(t, err, bad)
+	}
+}
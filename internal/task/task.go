@@ -14,6 +14,35 @@ const (
 	OperationSnipe OperationType = "snipe"
 	OperationSwap  OperationType = "swap"
 	OperationSell  OperationType = "sell"
+	OperationWatch OperationType = "watch" // Buy when price dips from a reference
+
+	OperationLimitBuy  OperationType = "limit_buy"  // Buy once price falls to limit_price
+	OperationLimitSell OperationType = "limit_sell" // Sell once price rises to limit_price
+
+	OperationDCA OperationType = "dca" // Buy amount_sol every dca_interval_minutes for dca_minutes
+)
+
+// WalletSpread is how a buy task aimed at a wallet group is run across the group's wallets.
+type WalletSpread string
+
+const (
+	SpreadNone  WalletSpread = ""      // One wallet of the group, round-robin across tasks
+	SpreadEach  WalletSpread = "each"  // Every wallet buys amount_sol
+	SpreadSplit WalletSpread = "split" // The wallets share amount_sol equally
+)
+
+// WatchReference is the price a watch task measures its dip from.
+type WatchReference string
+
+const (
+	ReferenceHigh     WatchReference = ""          // The high seen while watching, or reference_price if set
+	ReferenceLastExit WatchReference = "last_exit" // The price of the wallet's last sale of the token
+)
+
+// Markers in the token_mint column that make a task a template for mints found at runtime
+const (
+	NewTokenMint  = "new"  // Snipe brand-new Pump.fun tokens
+	CopyTradeMint = "copy" // Mirror buys of the wallets in copy_trading
 )
 
 // Task holds parameters for a trade operation loaded from CSV.
@@ -29,5 +58,73 @@ type Task struct {
 	ComputeUnits    uint32        // Compute units for transaction
 	TokenMint       string        // Token mint address
 	CreatedAt       time.Time     // Timestamp when task was parsed
-	AutosellAmount  float64       // Percent of tokens to auto-sell
+	AutosellAmount  float64       // Percent of tokens to auto-sell (or to sell, for sell tasks)
+	SellAmount      float64       // Sell tasks: tokens to sell in UI units; 0 = sell AutosellAmount percent
+	RPC             string        // RPC endpoint name from rpc_endpoints or URL; empty = primary RPC
+	AllowRebuy      bool          // Buy tasks: skip buy_cooldown for this task
+	StartAt         time.Time     // Not run before this time (zero = at once)
+	ExpireAt        time.Time     // Dropped if not started by this time (zero = never)
+
+	// Running one buy task on every trading wallet of its group (snipe and swap)
+	WalletSpread  WalletSpread // How the task is spread; empty = one wallet of the group
+	SpreadWallets int          // Number of wallets the task runs on after spreading (0 = not spread)
+
+	// Splitting the buy into randomized slices to reduce sandwich exposure (snipe and swap)
+	BuySlices         int           // Number of buys amount_sol is split into; 0 or 1 = one buy
+	SliceJitter       time.Duration // Longest random pause between slices
+	SliceVariance     float64       // Slice sizes vary by up to this percent of an even share
+	SliceNewBlockhash bool          // Send every slice with a newer blockhash than the previous one
+
+	// Limit sells of the monitored position at target market caps (snipe and swap)
+	MarketCapTargets []MarketCapTarget
+
+	// Automatic exits of the monitored position in percent (0 = the value from config)
+	StopLossPercent     float64 // Sell when PnL falls this much
+	TakeProfitPercent   float64 // Sell when PnL rises this much
+	TrailingStopPercent float64 // Sell when price falls this much from its session high
+	ExitPlan            string  // Name of a multi-step plan from config.json exit_plans
+
+	// Limit orders (OperationLimitBuy, OperationLimitSell): token price in SOL that fills the order
+	LimitPrice float64
+
+	// Watch mode (OperationWatch)
+	DipPercent     float64        // Buy when price drops this % below the reference
+	ReferencePrice float64        // Fixed reference price in SOL; 0 = track recent high
+	Reference      WatchReference // Where the reference comes from when ReferencePrice is 0
+	WatchDuration  time.Duration  // How long to watch before giving up
+
+	// Dollar-cost averaging (OperationDCA)
+	DCAInterval time.Duration // Time between buys
+	DCADuration time.Duration // How long to keep buying; the first buy happens at once
+}
+
+// DCABuys returns how many buys a DCA task makes over its duration.
+func (t *Task) DCABuys() int {
+	if t.DCAInterval <= 0 {
+		return 1
+	}
+	return max(int(t.DCADuration/t.DCAInterval), 1)
+}
+
+// IsTemplate reports whether the task is a template for mints found at runtime instead of a fixed mint.
+func (t *Task) IsTemplate() bool {
+	switch t.TokenMint {
+	case NewTokenMint:
+		return t.Operation == OperationSnipe
+	case CopyTradeMint:
+		return t.Operation == OperationSnipe || t.Operation == OperationSwap
+	}
+	return false
+}
+
+// SplitTemplates separates template tasks from regular tasks.
+func SplitTemplates(tasks []*Task) (regular, templates []*Task) {
+	for _, t := range tasks {
+		if t.IsTemplate() {
+			templates = append(templates, t)
+		} else {
+			regular = append(regular, t)
+		}
+	}
+	return regular, templates
 }
@@ -4,17 +4,42 @@
 package task
 
 import (
-	"encoding/csv"
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/gagliardetto/solana-go"
 )
 
+// WalletRole — назначение кошелька.
+type WalletRole string
+
+const (
+	RoleNone     WalletRole = ""          // Обычный торговый кошелек
+	RoleSniper   WalletRole = "sniper"    // Кошелек для снайпинга
+	RoleVault    WalletRole = "vault"     // Хранилище: никогда не торгует
+	RoleFeePayer WalletRole = "fee_payer" // Оплачивает комиссии за остальные кошельки группы
+)
+
+// FeeSponsor — внешний сервис, который оплачивает комиссии транзакций своим ключом.
+type FeeSponsor interface {
+	PublicKey() solana.PublicKey
+	// SignAsPayer добавляет в транзакцию подпись плательщика комиссий.
+	SignAsPayer(ctx context.Context, tx *solana.Transaction) error
+}
+
 // Wallet представляет кошелёк Solana.
 type Wallet struct {
+	Name       string
+	Role       WalletRole
+	Group      string
+	FeePayer   *Wallet    // Кошелек, оплачивающий комиссии транзакций (nil — сам кошелек)
+	Sponsor    FeeSponsor // Внешний плательщик комиссий, если в группе нет fee_payer
 	PrivateKey solana.PrivateKey
 	PublicKey  solana.PublicKey
 	ATACache   map[string]solana.PublicKey // Кеш для ассоциированных адресов токен-аккаунтов (ATA)
@@ -36,8 +61,122 @@ func NewWallet(privateKeyBase58 string) (*Wallet, error) {
 	}, nil
 }
 
-// LoadWallets загружает кошельки из CSV-файла с колонками: [Name, PrivateKeyBase58].
+// LoadWallets загружает кошельки из CSV-файла с колонками: [Name, PrivateKeyBase58]
+// и необязательными role и group. Кошелек с ролью fee_payer оплачивает комиссии
+// за остальные кошельки своей группы.
 func LoadWallets(path string) (map[string]*Wallet, error) {
+	records, err := readWalletRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	return walletsFromRecords(records)
+}
+
+// walletRow — строка wallets.csv: имя, приватный ключ (nil — не разобран) и
+// необязательные role и group.
+type walletRow struct {
+	name  string
+	key   solana.PrivateKey
+	role  string
+	group string
+}
+
+// walletColumns возвращает индексы колонок role и group по заголовку (-1 — колонки нет).
+func walletColumns(header []string) (roleIdx, groupIdx int) {
+	roleIdx, groupIdx = -1, -1
+	for i, col := range header {
+		switch strings.ToLower(strings.TrimSpace(col)) {
+		case "role":
+			roleIdx = i
+		case "group":
+			groupIdx = i
+		}
+	}
+	return roleIdx, groupIdx
+}
+
+// walletField возвращает колонку idx строки без пробелов по краям или "".
+func walletField(record []string, idx int) string {
+	if idx < 0 || idx >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[idx])
+}
+
+// walletsFromRecords создает кошельки из строк wallets.csv с заголовком.
+func walletsFromRecords(records [][]string) (map[string]*Wallet, error) {
+	roleIdx, groupIdx := walletColumns(records[0])
+	var rows []walletRow
+	for _, record := range records[1:] {
+		if len(record) < 2 {
+			continue
+		}
+		key, _ := solana.PrivateKeyFromBase58(record[1])
+		rows = append(rows, walletRow{
+			name:  record[0],
+			key:   key,
+			role:  walletField(record, roleIdx),
+			group: walletField(record, groupIdx),
+		})
+	}
+	return walletsFromRows(rows)
+}
+
+// walletsFromRows создает кошельки; строки с неразобранным ключом пропускаются.
+func walletsFromRows(rows []walletRow) (map[string]*Wallet, error) {
+	wallets := make(map[string]*Wallet)
+	for _, row := range rows {
+		if row.key == nil {
+			continue
+		}
+		role, err := parseWalletRole(row.role)
+		if err != nil {
+			return nil, fmt.Errorf("wallet %q: %w", row.name, err)
+		}
+		wallets[row.name] = &Wallet{
+			Name:       row.name,
+			Role:       role,
+			Group:      row.group,
+			PrivateKey: row.key,
+			PublicKey:  row.key.PublicKey(),
+			ATACache:   make(map[string]solana.PublicKey),
+		}
+	}
+
+	if err := assignFeePayers(wallets); err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
+// InvalidWalletKeys возвращает имена кошельков, чей приватный ключ не разбирается.
+// LoadWallets такие строки пропускает, поэтому их проверяют отдельно при старте.
+func InvalidWalletKeys(path string) ([]string, error) {
+	records, err := readWalletRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	return invalidWalletKeys(records), nil
+}
+
+func invalidWalletKeys(records [][]string) []string {
+	var invalid []string
+	for _, record := range records[1:] {
+		if len(record) < 2 {
+			if len(record) == 1 && strings.TrimSpace(record[0]) != "" {
+				invalid = append(invalid, record[0])
+			}
+			continue
+		}
+		if _, err := solana.PrivateKeyFromBase58(record[1]); err != nil {
+			invalid = append(invalid, record[0])
+		}
+	}
+	return invalid
+}
+
+// readWalletRecords читает строки CSV с кошельками вместе с заголовком.
+func readWalletRecords(path string) ([][]string, error) {
 	// Clean the path to prevent path traversal issues
 	cleanPath := filepath.Clean(path)
 
@@ -51,39 +190,114 @@ func LoadWallets(path string) (map[string]*Wallet, error) {
 		}
 	}()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	data, err := io.ReadAll(file)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CSV: %w", err)
 	}
-	if len(records) < 2 {
-		return nil, fmt.Errorf("CSV file is empty or missing data")
+	return parseWalletCSV(data)
+}
+
+func parseWalletRole(s string) (WalletRole, error) {
+	role := WalletRole(strings.ToLower(s))
+	switch role {
+	case RoleNone, RoleSniper, RoleVault, RoleFeePayer:
+		return role, nil
+	default:
+		return "", fmt.Errorf("unsupported wallet role %q (use sniper, vault or fee_payer)", s)
 	}
+}
 
-	wallets := make(map[string]*Wallet)
-	for _, record := range records[1:] {
-		if len(record) != 2 {
+// assignFeePayers назначает кошелек fee_payer плательщиком комиссий для его группы.
+func assignFeePayers(wallets map[string]*Wallet) error {
+	payers := make(map[string]*Wallet)
+	for _, w := range wallets {
+		if w.Role != RoleFeePayer {
 			continue
 		}
-		name := record[0]
-		w, err := NewWallet(record[1])
-		if err != nil {
+		if w.Group == "" {
+			return fmt.Errorf("fee_payer wallet %q must belong to a group", w.Name)
+		}
+		if other, ok := payers[w.Group]; ok {
+			return fmt.Errorf("group %q has more than one fee_payer (%s, %s)", w.Group, other.Name, w.Name)
+		}
+		payers[w.Group] = w
+	}
+
+	for _, w := range wallets {
+		if payer, ok := payers[w.Group]; ok && w != payer {
+			w.FeePayer = payer
+		}
+	}
+	return nil
+}
+
+// AttachFeeSponsor назначает sponsor плательщиком комиссий для торгующих кошельков,
+// у которых нет fee_payer в группе, и возвращает их количество.
+func AttachFeeSponsor(wallets map[string]*Wallet, sponsor FeeSponsor) int {
+	attached := 0
+	for _, w := range wallets {
+		if w.FeePayer != nil || w.Role == RoleVault || w.Role == RoleFeePayer {
 			continue
 		}
-		wallets[name] = w
+		w.Sponsor = sponsor
+		attached++
 	}
-	return wallets, nil
+	return attached
 }
 
-// SignTransaction подписывает транзакцию с помощью приватного ключа кошелька.
-func (w *Wallet) SignTransaction(tx *solana.Transaction) error {
-	_, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+// WalletsInGroup возвращает торгующие кошельки группы, отсортированные по имени.
+// Кошельки vault и fee_payer в торговлю не назначаются.
+func WalletsInGroup(wallets map[string]*Wallet, group string) []*Wallet {
+	var members []*Wallet
+	for _, w := range wallets {
+		if w.Group != group || w.Role == RoleVault || w.Role == RoleFeePayer {
+			continue
+		}
+		members = append(members, w)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	return members
+}
+
+// Payer возвращает адрес, который оплачивает комиссии транзакций кошелька.
+func (w *Wallet) Payer() solana.PublicKey {
+	if w.FeePayer != nil {
+		return w.FeePayer.PublicKey
+	}
+	if w.Sponsor != nil {
+		return w.Sponsor.PublicKey()
+	}
+	return w.PublicKey
+}
+
+// SignTransaction подписывает транзакцию с помощью приватного ключа кошелька
+// (и плательщика комиссий, если он назначен). Если комиссии оплачивает внешний
+// сервис, его подпись запрашивается после локальных.
+func (w *Wallet) SignTransaction(ctx context.Context, tx *solana.Transaction) error {
+	getter := func(key solana.PublicKey) *solana.PrivateKey {
 		if key.Equals(w.PublicKey) {
 			return &w.PrivateKey
 		}
+		if w.FeePayer != nil && key.Equals(w.FeePayer.PublicKey) {
+			return &w.FeePayer.PrivateKey
+		}
 		return nil
-	})
-	return err
+	}
+
+	sponsored := w.Sponsor != nil && len(tx.Message.AccountKeys) > 0 &&
+		tx.Message.AccountKeys[0].Equals(w.Sponsor.PublicKey())
+	if !sponsored {
+		_, err := tx.Sign(getter)
+		return err
+	}
+
+	if _, err := tx.PartialSign(getter); err != nil {
+		return err
+	}
+	if err := w.Sponsor.SignAsPayer(ctx, tx); err != nil {
+		return fmt.Errorf("fee sponsor: %w", err)
+	}
+	return nil
 }
 
 // GetATA возвращает адрес ассоциированного токен-аккаунта (ATA) для заданного токена (mint).
@@ -115,7 +329,16 @@ func (w *Wallet) PrecomputeATAs(mints []solana.PublicKey) error {
 
 // CreateAssociatedTokenAccountIdempotentInstruction создает инструкцию для создания ассоциированного токен-аккаунта
 func (w *Wallet) CreateAssociatedTokenAccountIdempotentInstruction(payer, wallet, mint solana.PublicKey) solana.Instruction {
-	ata, _, err := solana.FindAssociatedTokenAddress(wallet, mint)
+	return w.CreateTokenAccountIdempotentInstruction(payer, wallet, mint, solana.TokenProgramID)
+}
+
+// CreateTokenAccountIdempotentInstruction создает инструкцию для создания ассоциированного
+// токен-аккаунта в программе токена tokenProgram (SPL Token или Token-2022).
+func (w *Wallet) CreateTokenAccountIdempotentInstruction(payer, wallet, mint, tokenProgram solana.PublicKey) solana.Instruction {
+	ata, _, err := solana.FindProgramAddress(
+		[][]byte{wallet[:], tokenProgram[:], mint[:]},
+		solana.SPLAssociatedTokenAccountProgramID,
+	)
 	if err != nil {
 		panic(fmt.Sprintf("failed to find associated token address: %v", err))
 	}
@@ -128,7 +351,7 @@ func (w *Wallet) CreateAssociatedTokenAccountIdempotentInstruction(payer, wallet
 			solana.Meta(wallet),
 			solana.Meta(mint),
 			solana.Meta(solana.SystemProgramID),
-			solana.Meta(solana.TokenProgramID),
+			solana.Meta(tokenProgram),
 			solana.Meta(solana.SysVarRentPubkey),
 		},
 		[]byte{1}, // 1 = create_idempotent
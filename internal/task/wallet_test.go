@@ -0,0 +1,145 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func walletRecords(rows ...[]string) [][]string {
+	return append([][]string{{"name", "private_key", "role", "group"}}, rows...)
+}
+
+func walletKey() string {
+	return solana.NewWallet().PrivateKey.String()
+}
+
+func TestWalletsFromRecords_Roles(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rows    [][]string
+		wantErr string
+	}{
+		"unknown role":            {rows: [][]string{{"main", walletKey(), "trader", ""}}, wantErr: `wallet "main": unsupported wallet role "trader"`},
+		"fee payer without group": {rows: [][]string{{"gas", walletKey(), "fee_payer", ""}}, wantErr: `fee_payer wallet "gas" must belong to a group`},
+		"two fee payers":          {rows: [][]string{{"gas", walletKey(), "fee_payer", "a"}, {"gas2", walletKey(), "fee_payer", "a"}}, wantErr: `group "a" has more than one fee_payer`},
+		"roles ignore case":       {rows: [][]string{{"gas", walletKey(), " Fee_Payer ", "a"}, {"cold", walletKey(), "VAULT", "a"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := walletsFromRecords(walletRecords(tc.rows...))
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWalletsFromRecords_FeePayers(t *testing.T) {
+	wallets, err := walletsFromRecords(walletRecords(
+		[]string{"gas", walletKey(), "fee_payer", "snipers"},
+		[]string{"a", walletKey(), "sniper", "snipers"},
+		[]string{"b", walletKey(), "", "snipers"},
+		[]string{"solo", walletKey(), "", "unpaid"},
+		[]string{"plain", walletKey()},
+		[]string{"broken", "not-a-key", "", "snipers"},
+	))
+	require.NoError(t, err)
+	require.Len(t, wallets, 5, "rows with an invalid key are skipped")
+
+	gas := wallets["gas"]
+	for _, name := range []string{"a", "b"} {
+		assert.Same(t, gas, wallets[name].FeePayer, name)
+		assert.Equal(t, gas.PublicKey, wallets[name].Payer(), name)
+	}
+	assert.Nil(t, gas.FeePayer, "the fee payer pays for itself")
+	assert.Equal(t, RoleSniper, wallets["a"].Role)
+
+	// В группе без fee_payer и вне групп кошелек платит сам
+	for _, name := range []string{"solo", "plain"} {
+		assert.Nil(t, wallets[name].FeePayer, name)
+		assert.Equal(t, wallets[name].PublicKey, wallets[name].Payer(), name)
+	}
+	assert.Equal(t, []string{"broken"}, invalidWalletKeys(walletRecords(
+		[]string{"ok", walletKey()},
+		[]string{"broken", "not-a-key"},
+	)))
+}
+
+// keySponsor — плательщик комиссий со своим ключом, подписывающий как внешний сервис.
+type keySponsor struct {
+	key   solana.PrivateKey
+	calls int
+}
+
+func (s *keySponsor) PublicKey() solana.PublicKey { return s.key.PublicKey() }
+
+func (s *keySponsor) SignAsPayer(_ context.Context, tx *solana.Transaction) error {
+	s.calls++
+	_, err := tx.PartialSign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(s.key.PublicKey()) {
+			return &s.key
+		}
+		return nil
+	})
+	return err
+}
+
+// ownerTransfer строит транзакцию, которую подписывают плательщик комиссий payer и владелец owner.
+func ownerTransfer(t *testing.T, payer, owner solana.PublicKey) *solana.Transaction {
+	t.Helper()
+	ix := system.NewTransferInstruction(1_000, owner, solana.NewWallet().PublicKey()).Build()
+	tx, err := solana.NewTransaction([]solana.Instruction{ix}, solana.Hash{1}, solana.TransactionPayer(payer))
+	require.NoError(t, err)
+	return tx
+}
+
+func TestWallet_SignTransaction(t *testing.T) {
+	owner, err := NewWallet(walletKey())
+	require.NoError(t, err)
+
+	t.Run("fee payer and owner", func(t *testing.T) {
+		payer, err := NewWallet(walletKey())
+		require.NoError(t, err)
+		w := *owner
+		w.FeePayer = payer
+
+		tx := ownerTransfer(t, payer.PublicKey, owner.PublicKey)
+		require.NoError(t, w.SignTransaction(context.Background(), tx))
+		require.Len(t, tx.Signatures, 2)
+		assert.Equal(t, payer.PublicKey, tx.Message.AccountKeys[0], "the fee payer signs first")
+		assert.NoError(t, tx.VerifySignatures())
+	})
+
+	t.Run("owner pays itself", func(t *testing.T) {
+		tx := ownerTransfer(t, owner.PublicKey, owner.PublicKey)
+		require.NoError(t, owner.SignTransaction(context.Background(), tx))
+		require.Len(t, tx.Signatures, 1)
+		assert.NoError(t, tx.VerifySignatures())
+	})
+
+	t.Run("payer key not held", func(t *testing.T) {
+		tx := ownerTransfer(t, solana.NewWallet().PublicKey(), owner.PublicKey)
+		assert.Error(t, owner.SignTransaction(context.Background(), tx))
+	})
+
+	t.Run("fee sponsor", func(t *testing.T) {
+		sponsor := &keySponsor{key: solana.NewWallet().PrivateKey}
+		w := *owner
+		w.Sponsor = sponsor
+
+		tx := ownerTransfer(t, sponsor.PublicKey(), owner.PublicKey)
+		require.NoError(t, w.SignTransaction(context.Background(), tx))
+		assert.Equal(t, 1, sponsor.calls)
+		assert.NoError(t, tx.VerifySignatures())
+
+		// Транзакцию, где сервис не плательщик, кошелек подписывает сам
+		own := ownerTransfer(t, owner.PublicKey, owner.PublicKey)
+		require.NoError(t, w.SignTransaction(context.Background(), own))
+		assert.Equal(t, 1, sponsor.calls)
+	})
+}
@@ -0,0 +1,199 @@
+// internal/tax/lots.go
+package tax
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+)
+
+// Method — порядок, в котором продажи списывают купленные лоты.
+type Method string
+
+const (
+	FIFO Method = "fifo" // Сначала самые старые покупки
+	LIFO Method = "lifo" // Сначала самые новые покупки
+)
+
+// ParseMethod разбирает метод учета: fifo (по умолчанию) или lifo.
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(strings.ToLower(s)); m {
+	case "", FIFO:
+		return FIFO, nil
+	case LIFO:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown tax method %q: use fifo or lifo", s)
+	}
+}
+
+// Disposal — продажа части одного лота: сколько токенов, когда куплены и проданы,
+// во что обошлись и сколько принесли.
+type Disposal struct {
+	Mint         string
+	Wallet       string
+	Acquired     time.Time // Ноль — покупка не найдена в журнале
+	Disposed     time.Time
+	Tokens       uint64  // Raw единицы токена
+	CostSol      float64 // Стоимость покупки с комиссией, доля лота
+	ProceedsSol  float64 // Выручка за вычетом комиссии, доля продажи
+	CostUSD      float64
+	ProceedsUSD  float64
+	BasisUnknown bool // Токены куплены вне бота: стоимость принята за ноль
+}
+
+// GainUSD возвращает прибыль или убыток продажи в долларах.
+func (d Disposal) GainUSD() float64 {
+	return d.ProceedsUSD - d.CostUSD
+}
+
+// lot — непроданный остаток одной покупки.
+type lot struct {
+	acquired time.Time
+	tokens   uint64
+	cost     float64 // SOL за оставшиеся токены
+}
+
+// Result — продажи, сопоставленные с лотами, и пропущенные сделки.
+type Result struct {
+	Disposals []Disposal
+	Skipped   int // Успешные сделки без фактических объемов (записаны до их учета)
+}
+
+// Match сопоставляет продажи с покупками того же токена на том же кошельке методом
+// method. Учитываются только подтвержденные сделки, по времени подтверждения.
+func Match(records []execution.Record, method Method) Result {
+	sorted := append([]execution.Record(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ConfirmedAt.Before(sorted[j].ConfirmedAt) })
+
+	var res Result
+	books := make(map[string][]lot)
+	for _, rec := range sorted {
+		if !rec.Success() {
+			continue
+		}
+		if rec.ActualIn == 0 || rec.ActualOut == 0 {
+			res.Skipped++
+			continue
+		}
+		key := rec.Wallet + "/" + rec.Mint
+		fee := lamportsToSol(rec.FeePaid)
+
+		if rec.Side == execution.SideBuy {
+			books[key] = append(books[key], lot{
+				acquired: rec.ConfirmedAt,
+				tokens:   rec.ActualOut,
+				cost:     lamportsToSol(rec.ActualIn) + fee,
+			})
+			continue
+		}
+
+		proceeds := lamportsToSol(rec.ActualOut) - fee
+		remaining := rec.ActualIn
+		for remaining > 0 && len(books[key]) > 0 {
+			lots := books[key]
+			i := 0
+			if method == LIFO {
+				i = len(lots) - 1
+			}
+			l := &lots[i]
+
+			take := min(remaining, l.tokens)
+			cost := l.cost * float64(take) / float64(l.tokens)
+			res.Disposals = append(res.Disposals, Disposal{
+				Mint: rec.Mint, Wallet: rec.Wallet,
+				Acquired: l.acquired, Disposed: rec.ConfirmedAt,
+				Tokens:      take,
+				CostSol:     cost,
+				ProceedsSol: proceeds * float64(take) / float64(rec.ActualIn),
+			})
+			l.tokens -= take
+			l.cost -= cost
+			remaining -= take
+			if l.tokens == 0 {
+				books[key] = append(lots[:i], lots[i+1:]...)
+			}
+		}
+		if remaining > 0 {
+			res.Disposals = append(res.Disposals, Disposal{
+				Mint: rec.Mint, Wallet: rec.Wallet, Disposed: rec.ConfirmedAt,
+				Tokens:       remaining,
+				ProceedsSol:  proceeds * float64(remaining) / float64(rec.ActualIn),
+				BasisUnknown: true,
+			})
+		}
+	}
+	return res
+}
+
+// InPeriod оставляет продажи, совершенные в from..to; нулевая граница не ограничивает.
+// Лоты сопоставляются по всей истории, поэтому период применяется уже к продажам.
+func InPeriod(disposals []Disposal, from, to time.Time) []Disposal {
+	var out []Disposal
+	for _, d := range disposals {
+		if (!from.IsZero() && d.Disposed.Before(from)) || (!to.IsZero() && d.Disposed.After(to)) {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// disposalColumns — колонки CSV отчета; первые шесть совпадают с формой 8949,
+// которую импортирует большинство налоговых программ.
+var disposalColumns = []string{
+	"Description", "Date Acquired", "Date Sold", "Proceeds", "Cost Basis", "Gain or Loss",
+	"Token Mint", "Wallet", "Token Amount (raw)", "Proceeds SOL", "Cost Basis SOL", "Basis Unknown",
+}
+
+// WriteCSV записывает продажи в w, упорядоченные по дате продажи.
+func WriteCSV(w io.Writer, disposals []Disposal) error {
+	sorted := append([]Disposal(nil), disposals...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Disposed.Before(sorted[j].Disposed) })
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write(disposalColumns)
+	for _, d := range sorted {
+		acquired := "VARIOUS"
+		if !d.Acquired.IsZero() {
+			acquired = d.Acquired.UTC().Format("01/02/2006")
+		}
+		_ = cw.Write([]string{
+			"Token " + shortMint(d.Mint),
+			acquired,
+			d.Disposed.UTC().Format("01/02/2006"),
+			fmt.Sprintf("%.2f", d.ProceedsUSD),
+			fmt.Sprintf("%.2f", d.CostUSD),
+			fmt.Sprintf("%.2f", d.GainUSD()),
+			d.Mint,
+			d.Wallet,
+			fmt.Sprintf("%d", d.Tokens),
+			fmt.Sprintf("%.9f", d.ProceedsSol),
+			fmt.Sprintf("%.9f", d.CostSol),
+			fmt.Sprintf("%t", d.BasisUnknown),
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("write tax report: %w", err)
+	}
+	return nil
+}
+
+func lamportsToSol(lamports uint64) float64 {
+	return float64(lamports) / float64(solana.LAMPORTS_PER_SOL)
+}
+
+// shortMint сокращает адрес токена для описания, например "7xKX...sAsU".
+func shortMint(mint string) string {
+	if len(mint) <= 8 {
+		return mint
+	}
+	return mint[:4] + "..." + mint[len(mint)-4:]
+}
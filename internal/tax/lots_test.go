@@ -0,0 +1,71 @@
+package tax
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rovshanmuradov/solana-bot/internal/execution"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trade(side string, day int, in, out uint64) execution.Record {
+	at := time.Date(2025, 1, day, 12, 0, 0, 0, time.UTC)
+	return execution.Record{Side: side, Mint: "MintAAAAAAAAAAAA1111", Wallet: "W1", StartedAt: at, ConfirmedAt: at, ActualIn: in, ActualOut: out}
+}
+
+func TestMatch_FIFOAndLIFO(t *testing.T) {
+	records := []execution.Record{
+		trade(execution.SideBuy, 1, 1_000_000_000, 100), // 1 SOL за 100 токенов
+		trade(execution.SideBuy, 2, 3_000_000_000, 100), // 3 SOL за 100 токенов
+		trade(execution.SideSell, 3, 150, 6_000_000_000),
+		{Side: execution.SideBuy, Mint: "MintAAAAAAAAAAAA1111", Wallet: "W1", ConfirmedAt: time.Now(), ActualOut: 5}, // Без ActualIn
+		{Side: execution.SideBuy, Error: "failed"},
+	}
+
+	fifo := Match(records, FIFO)
+	assert.Equal(t, 1, fifo.Skipped)
+	require.Len(t, fifo.Disposals, 2)
+	assert.Equal(t, uint64(100), fifo.Disposals[0].Tokens)
+	assert.InDelta(t, 1.0, fifo.Disposals[0].CostSol, 1e-9)
+	assert.InDelta(t, 4.0, fifo.Disposals[0].ProceedsSol, 1e-9)
+	assert.Equal(t, uint64(50), fifo.Disposals[1].Tokens)
+	assert.InDelta(t, 1.5, fifo.Disposals[1].CostSol, 1e-9)
+	assert.Equal(t, 2, fifo.Disposals[1].Acquired.Day())
+
+	lifo := Match(records, LIFO)
+	require.Len(t, lifo.Disposals, 2)
+	assert.InDelta(t, 3.0, lifo.Disposals[0].CostSol, 1e-9)
+	assert.InDelta(t, 0.5, lifo.Disposals[1].CostSol, 1e-9)
+	assert.Equal(t, 1, lifo.Disposals[1].Acquired.Day())
+}
+
+func TestMatch_SellWithoutBuyHasUnknownBasis(t *testing.T) {
+	res := Match([]execution.Record{
+		trade(execution.SideBuy, 1, 1_000_000_000, 100),
+		trade(execution.SideSell, 2, 200, 4_000_000_000),
+	}, FIFO)
+	require.Len(t, res.Disposals, 2)
+	assert.False(t, res.Disposals[0].BasisUnknown)
+	assert.True(t, res.Disposals[1].BasisUnknown)
+	assert.InDelta(t, 2.0, res.Disposals[1].ProceedsSol, 1e-9)
+	assert.Zero(t, res.Disposals[1].CostSol)
+
+	assert.Len(t, InPeriod(res.Disposals, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), time.Time{}), 0)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, WriteCSV(&b, []Disposal{{
+		Mint: "MintAAAAAAAAAAAA1111", Wallet: "W1",
+		Acquired: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Disposed: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Tokens: 100, CostSol: 1, ProceedsSol: 2, CostUSD: 200, ProceedsUSD: 450.5,
+	}}))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "Token Mint...1111,01/01/2025,02/01/2025,450.50,200.00,250.50,MintAAAAAAAAAAAA1111,W1,100,2.000000000,1.000000000,false", lines[1])
+
+	_, err := ParseMethod("average")
+	assert.Error(t, err)
+}
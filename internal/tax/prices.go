@@ -0,0 +1,103 @@
+// internal/tax/prices.go
+package tax
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// coinGeckoURL — публичный API CoinGecko с историческими ценами.
+const coinGeckoURL = "https://api.coingecko.com/api/v3"
+
+// PriceSource возвращает курс SOL/USD на дату.
+type PriceSource interface {
+	SOLUSD(ctx context.Context, day time.Time) (float64, error)
+}
+
+// CoinGeckoPrices получает дневной курс SOL/USD из истории CoinGecko и кэширует его
+// по дням: отчет за год делает не больше запроса на день со сделками.
+type CoinGeckoPrices struct {
+	baseURL string
+	client  *http.Client
+
+	mu    sync.Mutex
+	cache map[string]float64
+}
+
+// NewCoinGeckoPrices создает источник курсов CoinGecko.
+func NewCoinGeckoPrices() *CoinGeckoPrices {
+	return &CoinGeckoPrices{
+		baseURL: coinGeckoURL,
+		client:  &http.Client{Timeout: 15 * time.Second},
+		cache:   make(map[string]float64),
+	}
+}
+
+// SOLUSD возвращает курс SOL/USD на начало дня day (UTC).
+func (c *CoinGeckoPrices) SOLUSD(ctx context.Context, day time.Time) (float64, error) {
+	date := day.UTC().Format("02-01-2006")
+	c.mu.Lock()
+	price, ok := c.cache[date]
+	c.mu.Unlock()
+	if ok {
+		return price, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		c.baseURL+"/coins/solana/history?localization=false&date="+date, nil)
+	if err != nil {
+		return 0, fmt.Errorf("create price request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("fetch SOL/USD for %s: %w", date, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("fetch SOL/USD for %s: status %d", date, resp.StatusCode)
+	}
+
+	var body struct {
+		MarketData struct {
+			CurrentPrice map[string]float64 `json:"current_price"`
+		} `json:"market_data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("decode SOL/USD for %s: %w", date, err)
+	}
+	price, ok = body.MarketData.CurrentPrice["usd"]
+	if !ok {
+		return 0, fmt.Errorf("no SOL/USD price for %s", date)
+	}
+
+	c.mu.Lock()
+	c.cache[date] = price
+	c.mu.Unlock()
+	return price, nil
+}
+
+// Value пересчитывает стоимость и выручку продаж в доллары по курсу на дату покупки
+// и на дату продажи. У лотов с неизвестной покупкой стоимость остается нулевой.
+func Value(ctx context.Context, disposals []Disposal, prices PriceSource) error {
+	for i := range disposals {
+		d := &disposals[i]
+		rate, err := prices.SOLUSD(ctx, d.Disposed)
+		if err != nil {
+			return err
+		}
+		d.ProceedsUSD = d.ProceedsSol * rate
+
+		if d.BasisUnknown {
+			continue
+		}
+		if rate, err = prices.SOLUSD(ctx, d.Acquired); err != nil {
+			return err
+		}
+		d.CostUSD = d.CostSol * rate
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package tax
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinGeckoPrices_CachesByDay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/coins/solana/history", r.URL.Path)
+		switch r.URL.Query().Get("date") {
+		case "01-01-2025":
+			_, _ = w.Write([]byte(`{"market_data":{"current_price":{"usd":190.5}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"solana"}`))
+		}
+	}))
+	defer srv.Close()
+
+	prices := NewCoinGeckoPrices()
+	prices.baseURL = srv.URL
+
+	day := time.Date(2025, 1, 1, 18, 30, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		price, err := prices.SOLUSD(context.Background(), day)
+		require.NoError(t, err)
+		assert.Equal(t, 190.5, price)
+	}
+	assert.Equal(t, 1, calls)
+
+	_, err := prices.SOLUSD(context.Background(), day.AddDate(0, 0, 1))
+	assert.EqualError(t, err, "no SOL/USD price for 02-01-2025")
+}
+
+type fixedPrices map[int]float64
+
+func (f fixedPrices) SOLUSD(_ context.Context, day time.Time) (float64, error) {
+	return f[day.Day()], nil
+}
+
+func TestValue(t *testing.T) {
+	disposals := []Disposal{
+		{Acquired: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Disposed: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), CostSol: 1, ProceedsSol: 2},
+		{Disposed: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), ProceedsSol: 1, BasisUnknown: true},
+	}
+	require.NoError(t, Value(context.Background(), disposals, fixedPrices{1: 100, 2: 150}))
+	assert.Equal(t, 100.0, disposals[0].CostUSD)
+	assert.Equal(t, 300.0, disposals[0].ProceedsUSD)
+	assert.Equal(t, 200.0, disposals[0].GainUSD())
+	assert.Zero(t, disposals[1].CostUSD)
+	assert.Equal(t, 150.0, disposals[1].ProceedsUSD)
+}